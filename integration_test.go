@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/gitlabtest"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// These tests run the server as main assembles it, with its middleware and
+// the real GitLab client, against a fake GitLab API, and drive it with
+// JSON-RPC requests over the MCP HTTP transport.
+
+// integration is an MCP server under test and the fake GitLab behind it.
+type integration struct {
+	t      *testing.T
+	gitlab *gitlabtest.Server
+	url    string
+	nextID int
+}
+
+// newIntegration starts an MCP server for cfg (nil for the defaults) against
+// a fake GitLab serving the tool fixtures.
+func newIntegration(t *testing.T, cfg *config.Config) *integration {
+	t.Helper()
+	t.Setenv("MCP_AUTH_TOKEN", "")
+
+	gl := gitlabtest.NewServer(t)
+	gl.MustLoadFixtures(t, filepath.Join("pkg", "tools", "testdata", "fixtures"))
+
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	cfg.GitLabAPIURL = gl.URL()
+	cfg.GitLabToken = gl.Token()
+
+	it := serveIntegration(t, cfg, func(*logging.Logger) (gitlab.API, error) {
+		return gl.NewGitLabClient(), nil
+	})
+	it.gitlab = gl
+	return it
+}
+
+// serveIntegration starts an MCP server for cfg with the client returned by
+// newClient.
+func serveIntegration(t *testing.T, cfg *config.Config, newClient func(*logging.Logger) (gitlab.API, error)) *integration {
+	t.Helper()
+	logger, err := logging.NewLogger(logging.Config{LogDir: t.TempDir(), Level: logging.LevelError})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	t.Cleanup(func() { logger.Close() })
+
+	client, err := newClient(logger)
+	if err != nil {
+		t.Fatalf("new GitLab client: %v", err)
+	}
+	server, _, err := newServer(cfg, client, logger, nil)
+	if err != nil {
+		t.Fatalf("newServer: %v", err)
+	}
+
+	httpServer := httptest.NewServer(server.HTTPHandler(nil))
+	t.Cleanup(httpServer.Close)
+	return &integration{t: t, url: httpServer.URL}
+}
+
+// call sends a JSON-RPC request and returns the response.
+func (it *integration) call(method string, params interface{}) mcp.JSONRPCResponse {
+	it.t.Helper()
+	it.nextID++
+	data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": it.nextID, "method": method, "params": params})
+	resp, err := http.Post(it.url+"/", "application/json", bytes.NewReader(data))
+	if err != nil {
+		it.t.Fatalf("%s: %v", method, err)
+	}
+	defer resp.Body.Close()
+
+	var response mcp.JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		it.t.Fatalf("%s: decode response: %v", method, err)
+	}
+	return response
+}
+
+// callTool calls a tool and returns its result.
+func (it *integration) callTool(name string, args map[string]interface{}) mcp.CallToolResult {
+	it.t.Helper()
+	response := it.call("tools/call", map[string]interface{}{"name": name, "arguments": args})
+	if response.Error != nil {
+		it.t.Fatalf("%s: JSON-RPC error %d: %s", name, response.Error.Code, response.Error.Message)
+	}
+	data, _ := json.Marshal(response.Result)
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		it.t.Fatalf("%s: decode result: %v", name, err)
+	}
+	return result
+}
+
+func TestIntegration_InitializeAndList(t *testing.T) {
+	it := newIntegration(t, nil)
+
+	if response := it.call("initialize", map[string]interface{}{"protocolVersion": "2025-06-18"}); response.Error != nil {
+		t.Fatalf("initialize: %+v", response.Error)
+	}
+
+	response := it.call("tools/list", map[string]interface{}{})
+	data, _ := json.Marshal(response.Result)
+	var list mcp.ListToolsResult
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatalf("decode tools/list: %v", err)
+	}
+	names := map[string]bool{}
+	for _, tool := range list.Tools {
+		names[tool.Name] = true
+	}
+	if !names["get_project"] || !names["create_issue"] {
+		t.Errorf("tools/list lacks default tools: %d tools", len(list.Tools))
+	}
+	if names["list_pipelines"] {
+		t.Error("tools/list includes pipeline tools although USE_PIPELINE is off")
+	}
+}
+
+func TestIntegration_GetProject(t *testing.T) {
+	it := newIntegration(t, nil)
+
+	result := it.callTool("get_project", map[string]interface{}{"project_id": "acme/payments-api"})
+	if result.IsError || !strings.Contains(result.Content[0].Text, `"path_with_namespace": "acme/payments-api"`) {
+		t.Errorf("get_project = %+v", result)
+	}
+	// The slash is escaped on the wire, as GitLab requires
+	if requests := it.gitlab.Requests(); len(requests) != 1 || requests[0].Endpoint != "/projects/acme%2Fpayments-api" {
+		t.Errorf("requests = %+v", requests)
+	}
+}
+
+func TestIntegration_APIError(t *testing.T) {
+	it := newIntegration(t, nil)
+
+	result := it.callTool("get_project", map[string]interface{}{"project_id": "404"})
+	if !result.IsError {
+		t.Fatalf("get_project succeeded: %+v", result)
+	}
+	var text strings.Builder
+	for _, item := range result.Content {
+		text.WriteString(item.Text)
+	}
+	if !strings.Contains(text.String(), "404 Project Not Found") || !strings.Contains(text.String(), `"http_status":404`) {
+		t.Errorf("error result = %s", text.String())
+	}
+}
+
+func TestIntegration_ServerStats(t *testing.T) {
+	it := newIntegration(t, nil)
+	it.callTool("get_project", map[string]interface{}{"project_id": "acme/payments-api"})
+	it.callTool("get_project", map[string]interface{}{"project_id": "404"})
+
+	result := it.callTool("get_server_stats", map[string]interface{}{"tool": "get_project"})
+	var report mcp.StatsReport
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &report); err != nil {
+		t.Fatalf("decode get_server_stats: %v: %s", err, result.Content[0].Text)
+	}
+	if len(report.Tools) != 1 || report.Tools[0].Name != "get_project" || report.Tools[0].Calls != 2 || report.Tools[0].Errors != 1 {
+		t.Errorf("get_server_stats = %+v", report)
+	}
+
+	result = it.callTool("get_server_stats", map[string]interface{}{"tool": "list_issues"})
+	if result.IsError || !strings.Contains(result.Content[0].Text, "list_issues has not been called") {
+		t.Errorf("get_server_stats for an unused tool = %+v", result)
+	}
+}
+
+func TestIntegration_Pagination(t *testing.T) {
+	it := newIntegration(t, nil)
+	var members []map[string]interface{}
+	for i := 1; i <= 5; i++ {
+		members = append(members, map[string]interface{}{"id": i, "username": fmt.Sprintf("user%d", i), "access_level": 30})
+	}
+	it.gitlab.Handle(http.MethodGet, "/projects/42/members", http.StatusOK, members)
+
+	result := it.callTool("list_project_members", map[string]interface{}{"project_id": "42", "page": 2, "per_page": 2})
+	var list struct {
+		Items []struct {
+			ID int `json:"id"`
+		} `json:"items"`
+		Pagination struct {
+			Page       int `json:"page"`
+			Total      int `json:"total"`
+			TotalPages int `json:"total_pages"`
+			NextPage   int `json:"next_page"`
+			PrevPage   int `json:"prev_page"`
+		} `json:"pagination"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &list); err != nil {
+		t.Fatalf("decode list_project_members: %v\n%s", err, result.Content[0].Text)
+	}
+	if len(list.Items) != 2 || list.Items[0].ID != 3 || list.Items[1].ID != 4 {
+		t.Errorf("items = %+v", list.Items)
+	}
+	if p := list.Pagination; p.Page != 2 || p.Total != 5 || p.TotalPages != 3 || p.NextPage != 3 || p.PrevPage != 1 {
+		t.Errorf("pagination = %+v", p)
+	}
+}
+
+func TestIntegration_WriteTool(t *testing.T) {
+	it := newIntegration(t, nil)
+
+	result := it.callTool("create_issue", map[string]interface{}{"project_id": "42", "title": "Checkout fails for EUR", "labels": "bug"})
+	if result.IsError {
+		t.Fatalf("create_issue failed: %s", result.Content[0].Text)
+	}
+	requests := it.gitlab.Requests()
+	if len(requests) != 1 || requests[0].Method != http.MethodPost {
+		t.Fatalf("requests = %+v", requests)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(requests[0].Body, &body); err != nil || body["title"] != "Checkout fails for EUR" {
+		t.Errorf("request body = %s", requests[0].Body)
+	}
+}
+
+func TestIntegration_InvalidArguments(t *testing.T) {
+	it := newIntegration(t, nil)
+
+	response := it.call("tools/call", map[string]interface{}{"name": "get_project", "arguments": map[string]interface{}{"project_id": 42.5}})
+	if response.Error == nil || response.Error.Code != mcp.InvalidParams {
+		t.Fatalf("response = %+v", response)
+	}
+	if len(it.gitlab.Requests()) != 0 {
+		t.Error("an invalid call reached GitLab")
+	}
+}
+
+// TestIntegration_Replay checks tool results against GitLab responses recorded
+// in testdata/cassettes with GITLAB_VCR_MODE=record, replayed as main does
+// with GITLAB_VCR_MODE=replay. Run with UPDATE_GOLDEN=1 after re-recording.
+func TestIntegration_Replay(t *testing.T) {
+	tests := []struct {
+		name     string
+		cassette string
+		tool     string
+		args     map[string]interface{}
+	}{
+		{"get_merge_request", "payments_api", "get_merge_request", map[string]interface{}{"project_id": "acme/payments-api", "merge_request_iid": 12}},
+		{"list_issues", "payments_api", "list_issues", map[string]interface{}{"project_id": "acme/payments-api", "state": "opened", "labels": "payments", "per_page": 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MCP_AUTH_TOKEN", "")
+			cfg := &config.Config{
+				GitLabAPIURL: "https://gitlab.example.com/api/v4",
+				VCRMode:      config.VCRReplay,
+				VCRCassette:  filepath.Join("testdata", "cassettes", tt.cassette+".json"),
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("replay needs no token: %v", err)
+			}
+			it := serveIntegration(t, cfg, func(logger *logging.Logger) (gitlab.API, error) {
+				return newGitLabClient(cfg, logger)
+			})
+
+			result := it.callTool(tt.tool, tt.args)
+			if result.IsError {
+				t.Fatalf("%s failed: %s", tt.tool, result.Content[0].Text)
+			}
+			gitlabtest.AssertGolden(t, filepath.Join("testdata", "golden", tt.name+".golden"), []byte(result.Content[0].Text))
+		})
+	}
+}
+
+// TestIntegration_Demo runs read tools against the -demo data, so the data
+// keeps up with the requests the tools make.
+func TestIntegration_Demo(t *testing.T) {
+	t.Setenv("MCP_AUTH_TOKEN", "")
+	cfg := &config.Config{
+		Demo:             true,
+		GitLabAPIURL:     config.DemoAPIURL,
+		UsePipeline:      true,
+		UseMilestone:     true,
+		ReadOnlyMode:     true,
+		DefaultProjectID: config.DemoProjectID,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("demo mode needs no token: %v", err)
+	}
+	it := serveIntegration(t, cfg, func(logger *logging.Logger) (gitlab.API, error) {
+		return newGitLabClient(cfg, logger)
+	})
+
+	calls := []struct {
+		tool string
+		args map[string]interface{}
+		want string
+	}{
+		{"gitlab_connectivity_check", map[string]interface{}{}, `"token_valid": true`},
+		{"list_projects", map[string]interface{}{}, "acme/storefront"},
+		{"get_project", map[string]interface{}{"project_id": "acme/payments-api"}, `"id": 42`},
+		{"list_issues", map[string]interface{}{"project_id": "acme/payments-api", "state": "opened"}, "Card authorisation fails"},
+		{"get_issue", map[string]interface{}{"project_id": "42", "issue_iid": 87}, "gateway timeout"},
+		{"list_merge_requests", map[string]interface{}{"project_id": "acme/payments-api"}, "Idempotency keys"},
+		{"get_merge_request", map[string]interface{}{"project_id": "acme/payments-api", "merge_request_iid": 12}, "retry-authorisation"},
+		{"get_merge_request_diffs", map[string]interface{}{"project_id": "acme/payments-api", "merge_request_iid": 12}, "ErrGatewayTimeout"},
+		{"mr_discussions", map[string]interface{}{"project_id": "acme/payments-api", "merge_request_iid": 12}, "idempotency key"},
+		{"list_pipelines", map[string]interface{}{"project_id": "acme/payments-api"}, `"status": "failed"`},
+		{"list_pipeline_jobs", map[string]interface{}{"project_id": "acme/payments-api", "pipeline_id": 77015}, "unit-tests"},
+		{"get_pipeline_job_output", map[string]interface{}{"project_id": "acme/payments-api", "job_id": 500103}, "TestRefundWebhook_Duplicate"},
+		{"get_file_contents", map[string]interface{}{"project_id": "acme/payments-api", "file_path": "README.md"}, "Payments API"},
+		{"list_group_projects", map[string]interface{}{"group_id": "acme"}, "acme/payments-api"},
+	}
+	for _, call := range calls {
+		result := it.callTool(call.tool, call.args)
+		var text strings.Builder
+		for _, item := range result.Content {
+			text.WriteString(item.Text)
+		}
+		if result.IsError || !strings.Contains(text.String(), call.want) {
+			t.Errorf("%s: want %q in result:\n%.600s", call.tool, call.want, text.String())
+		}
+	}
+
+	result := it.callTool("create_issue", map[string]interface{}{"project_id": "42", "title": "Demo"})
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "server is in read-only mode") {
+		t.Errorf("create_issue in demo mode = %+v", result)
+	}
+}
@@ -1,16 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/demo"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/vcr"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/instructions"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/telemetry"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/tools"
 )
 
@@ -24,41 +29,41 @@ type gitlabLoggerAdapter struct {
 	logger *logging.Logger
 }
 
-func (a *gitlabLoggerAdapter) Access(method, endpoint string, statusCode int, duration time.Duration) {
+func (a *gitlabLoggerAdapter) Access(ctx context.Context, method, endpoint string, statusCode int, duration time.Duration, sizes gitlab.TransferSizes) {
 	if a.logger != nil {
-		a.logger.Access("API_CALL method=%s endpoint=%q status=%d duration=%s", method, endpoint, statusCode, duration)
+		a.logger.AccessContext(ctx, "API_CALL method=%s endpoint=%q status=%d duration=%s %s", method, endpoint, statusCode, duration, sizes)
 	}
 }
 
-func (a *gitlabLoggerAdapter) Debug(msg string, args ...any) {
+func (a *gitlabLoggerAdapter) Debug(ctx context.Context, msg string, args ...any) {
 	if a.logger != nil {
 		if len(args) > 0 {
 			pairs := make([]string, 0, len(args)/2)
 			for i := 0; i+1 < len(args); i += 2 {
 				pairs = append(pairs, fmt.Sprintf("%v=%v", args[i], args[i+1]))
 			}
-			a.logger.Debug("%s %s", msg, joinStrings(pairs, " "))
+			a.logger.DebugContext(ctx, "%s %s", msg, joinStrings(pairs, " "))
 		} else {
-			a.logger.Debug(msg)
+			a.logger.DebugContext(ctx, "%s", msg)
 		}
 	}
 }
 
-func (a *gitlabLoggerAdapter) Error(msg string, args ...any) {
+func (a *gitlabLoggerAdapter) Error(ctx context.Context, msg string, args ...any) {
 	if a.logger != nil {
 		if len(args) > 0 {
 			pairs := make([]string, 0, len(args)/2)
 			for i := 0; i+1 < len(args); i += 2 {
 				pairs = append(pairs, fmt.Sprintf("%v=%v", args[i], args[i+1]))
 			}
-			a.logger.Error("%s %s", msg, joinStrings(pairs, " "))
+			a.logger.ErrorContext(ctx, "%s %s", msg, joinStrings(pairs, " "))
 		} else {
-			a.logger.Error(msg)
+			a.logger.ErrorContext(ctx, "%s", msg)
 		}
 	}
 }
 
-func (a *gitlabLoggerAdapter) LogHTTPRequest(context string, req *gitlab.HTTPRequestInfo, secrets ...string) {
+func (a *gitlabLoggerAdapter) LogHTTPRequest(ctx context.Context, logContext string, req *gitlab.HTTPRequestInfo, secrets ...string) {
 	if a.logger != nil && req != nil {
 		loggingReq := &logging.HTTPRequestInfo{
 			Method:  req.Method,
@@ -66,22 +71,22 @@ func (a *gitlabLoggerAdapter) LogHTTPRequest(context string, req *gitlab.HTTPReq
 			Headers: req.Headers,
 			Body:    req.Body,
 		}
-		a.logger.LogHTTPRequest(context, loggingReq, secrets...)
+		a.logger.LogHTTPRequest(ctx, logContext, loggingReq, secrets...)
 	}
 }
 
-func (a *gitlabLoggerAdapter) LogHTTPResponse(context string, resp *gitlab.HTTPResponseInfo, duration time.Duration, secrets ...string) {
+func (a *gitlabLoggerAdapter) LogHTTPResponse(ctx context.Context, logContext string, resp *gitlab.HTTPResponseInfo, duration time.Duration, secrets ...string) {
 	if a.logger != nil && resp != nil {
 		loggingResp := &logging.HTTPResponseInfo{
 			StatusCode: resp.StatusCode,
 			Headers:    resp.Headers,
 			Body:       resp.Body,
 		}
-		a.logger.LogHTTPResponse(context, loggingResp, duration, secrets...)
+		a.logger.LogHTTPResponse(ctx, logContext, loggingResp, duration, secrets...)
 	}
 }
 
-func (a *gitlabLoggerAdapter) LogHTTPError(context string, req *gitlab.HTTPRequestInfo, resp *gitlab.HTTPResponseInfo, err error, secrets ...string) {
+func (a *gitlabLoggerAdapter) LogHTTPError(ctx context.Context, logContext string, req *gitlab.HTTPRequestInfo, resp *gitlab.HTTPResponseInfo, err error, secrets ...string) {
 	if a.logger != nil {
 		var loggingReq *logging.HTTPRequestInfo
 		var loggingResp *logging.HTTPResponseInfo
@@ -103,7 +108,7 @@ func (a *gitlabLoggerAdapter) LogHTTPError(context string, req *gitlab.HTTPReque
 			}
 		}
 
-		a.logger.LogHTTPError(context, loggingReq, loggingResp, err, secrets...)
+		a.logger.LogHTTPError(ctx, logContext, loggingReq, loggingResp, err, secrets...)
 	}
 }
 
@@ -135,6 +140,11 @@ func main() {
 		os.Exit(0)
 	}
 
+	// -check/-selftest validates everything without starting the server
+	if cfg.SelfTest {
+		os.Exit(runSelfTest(cfg, os.Stdout))
+	}
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
@@ -163,40 +173,53 @@ func main() {
 		logging.ConfigValue{Value: logging.MaskToken(cfg.GitLabToken), Source: convertSource(cfg.Sources["GitLabToken"])},
 	))
 
-	// Create GitLab client with logger adapter and token provider
-	// The token provider allows per-request token override via X-GitLab-Token header
-	logAdapter := &gitlabLoggerAdapter{logger: logger}
-	tokenProvider := func() string {
-		// Check if there's a per-request token set (from X-GitLab-Token header)
-		return auth.GetCurrentGitLabToken()
+	// Initialize OpenTelemetry (OTLP export when OTEL_EXPORTER_OTLP_ENDPOINT is set,
+	// Prometheus /metrics in HTTP mode)
+	shutdownTelemetry, err := telemetry.Init(context.Background(), telemetry.Config{
+		ServiceName:    AppName,
+		ServiceVersion: Version,
+		Prometheus:     cfg.HTTPMode,
+	})
+	if err != nil {
+		logger.Error("Failed to initialize telemetry: %v", err)
+		shutdownTelemetry = func(context.Context) error { return nil }
+	} else if telemetry.Enabled() {
+		logger.Info("OpenTelemetry export enabled via OTLP")
 	}
-	gitlabClient := gitlab.NewClient(
-		cfg.GitLabAPIURL,
-		cfg.GitLabToken,
-		gitlab.WithLogger(logAdapter),
-		gitlab.WithTokenProvider(tokenProvider),
-	)
-	logger.Info("GitLab client initialized: url=%s token_source=%s", cfg.GitLabAPIURL, cfg.TokenSource)
-
-	// Set up the tools context
-	tools.SetContext(gitlabClient, logger, cfg)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTelemetry(shutdownCtx); err != nil {
+			logger.Error("Failed to flush telemetry: %v", err)
+		}
+	}()
 
-	// Create MCP server
-	server := mcp.NewServer(AppName, Version)
-	logger.Info("MCP server created: name=%s, version=%s", AppName, Version)
+	gitlabClient, err := newGitLabClient(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to initialize GitLab client: %v", err)
+		fmt.Fprintf(os.Stderr, "Failed to initialize GitLab client: %v\n", err)
+		os.Exit(1)
+	}
+	logger.Info("GitLab client initialized: url=%s token_source=%s", cfg.GitLabAPIURL, cfg.TokenSource)
 
-	// Set server instructions based on enabled features
-	serverInstructions := instructions.Generate(instructions.EnabledFeatures{
-		Pipelines:  cfg.UsePipeline,
-		Milestones: cfg.UseMilestone,
-		Wiki:       cfg.UseWiki,
-	})
-	server.SetInstructions(serverInstructions)
-	logger.Debug("Server instructions set (%d bytes)", len(serverInstructions))
+	var auditLog *logging.AuditLog
+	if cfg.AuditLog != "" {
+		auditLog, err = logging.OpenAuditLog(cfg.AuditLog)
+		if err != nil {
+			logger.Error("Failed to initialize audit log: %v", err)
+			fmt.Fprintf(os.Stderr, "Failed to initialize audit log: %v\n", err)
+			os.Exit(1)
+		}
+		defer auditLog.Close()
+		logger.Info("Audit log enabled: %s", auditLog.Path())
+	}
 
-	// Register all tools
-	tools.RegisterAllTools(server)
-	logger.Info("Tools registered successfully")
+	server, toolContext, err := newServer(cfg, gitlabClient, logger, auditLog)
+	if err != nil {
+		logger.Error("Failed to set up the MCP server: %v", err)
+		fmt.Fprintf(os.Stderr, "Failed to set up the MCP server: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Log enabled features
 	features := cfg.GetEnabledFeatures()
@@ -204,9 +227,25 @@ func main() {
 		logger.Info("Enabled features: %v", features)
 	}
 
+	// Reload token, log level, feature flags and allowlists on SIGHUP
+	watchReload(logger, gitlabClient, server, toolContext)
+
+	// Log a tool usage summary periodically and at shutdown
+	logToolStats(logger, server.ToolStats(), cfg.StatsLogInterval)
+
 	// Run the server
 	logger.Info("Starting MCP server...")
 	if cfg.HTTPMode {
+		if metricsHandler := telemetry.Prometheus(); metricsHandler != nil {
+			server.SetMetricsHandler(metricsHandler)
+		}
+		// /health?check=deep includes a GitLab connectivity check
+		server.SetHealthCheck(func(ctx context.Context) (interface{}, bool) {
+			ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+			report := tools.CheckConnectivity(ctx, gitlabClient)
+			return map[string]interface{}{"gitlab": report}, report.OK
+		})
 		addr := fmt.Sprintf("%s:%d", cfg.HTTPHost, cfg.HTTPPort)
 		logger.Info("Starting HTTP server on %s", addr)
 		if err := server.RunHTTP(addr); err != nil {
@@ -224,9 +263,166 @@ func main() {
 		}
 	}
 
+	logToolUsage(logger, server.ToolStats())
 	logger.LogShutdown("normal exit")
 }
 
+// logToolStats logs a summary of the tool usage every interval (0 disables).
+func logToolStats(logger *logging.Logger, stats *mcp.ToolStats, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			logToolUsage(logger, stats)
+		}
+	}()
+}
+
+// logToolUsage logs a summary of the tool usage, if any tool was called.
+func logToolUsage(logger *logging.Logger, stats *mcp.ToolStats) {
+	if report := stats.Report(); report.Calls > 0 {
+		logger.Info("Tool usage: %s", report.Summary())
+	}
+}
+
+// newGitLabClient creates the GitLab client for cfg: one serving demo data
+// with -demo, or recording to or replaying from a cassette when
+// GITLAB_VCR_MODE is set.
+func newGitLabClient(cfg *config.Config, logger *logging.Logger) (*gitlab.Client, error) {
+	// The token provider allows per-request token override via X-GitLab-Token header
+	tokenProvider := func() string {
+		// Check if there's a per-request token set (from X-GitLab-Token header)
+		return auth.GetCurrentGitLabToken()
+	}
+	opts := []gitlab.ClientOption{
+		gitlab.WithLogger(&gitlabLoggerAdapter{logger: logger}),
+		gitlab.WithTokenProvider(tokenProvider),
+		gitlab.WithRequestIDProvider(logging.RequestIDFromContext),
+		gitlab.WithRateLimit(cfg.RateLimit, cfg.RateLimitBurst),
+		gitlab.WithMaxResponseSize(cfg.MaxGitLabResponse),
+		gitlab.WithRequestCompression(cfg.GzipRequestBytes),
+		gitlab.WithResponseCache(cfg.ResponseCacheEntries),
+	}
+
+	switch {
+	case cfg.Demo:
+		transport, err := demo.NewTransport(cfg.GitLabAPIURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load demo data: %w", err)
+		}
+		opts = append(opts, gitlab.WithHTTPClient(&http.Client{Transport: transport}))
+		logger.Info("Demo mode: serving synthetic GitLab data, no GitLab connection is made")
+	case cfg.VCRMode == config.VCRRecord:
+		recorder, err := vcr.NewRecorder(cfg.VCRCassette, cfg.GitLabAPIURL, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open cassette: %w", err)
+		}
+		recorder.OnSaveError = func(err error) {
+			logger.Error("Failed to save cassette %s: %v", cfg.VCRCassette, err)
+		}
+		opts = append(opts, gitlab.WithHTTPClient(&http.Client{Timeout: 30 * time.Second, Transport: recorder}))
+		logger.Info("Recording GitLab API responses to %s", cfg.VCRCassette)
+	case cfg.VCRMode == config.VCRReplay:
+		interactions, err := vcr.Load(cfg.VCRCassette)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cassette: %w", err)
+		}
+		replayer, err := vcr.NewReplayer(cfg.GitLabAPIURL, interactions)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, gitlab.WithHTTPClient(&http.Client{Transport: replayer}))
+		logger.Info("Replaying %d GitLab API responses from %s", len(interactions), cfg.VCRCassette)
+	}
+
+	return gitlab.NewClient(cfg.GitLabAPIURL, cfg.GitLabToken, opts...), nil
+}
+
+// newServer creates the MCP server with the tools of cfg registered against
+// client, as main runs it. auditLog may be nil.
+func newServer(cfg *config.Config, client gitlab.API, logger *logging.Logger, auditLog *logging.AuditLog) (*mcp.Server, *tools.ToolContext, error) {
+	// Set up the tools context
+	toolContext := tools.NewToolContext(client, logger, cfg)
+
+	// Create MCP server
+	server := mcp.NewServer(AppName, Version)
+	logger.Info("MCP server created: name=%s, version=%s", AppName, Version)
+	server.SetToolsPageSize(cfg.ToolsPageSize)
+	server.SetToolTimeouts(cfg.ToolTimeout, cfg.ToolTimeouts)
+	server.SetConcurrencyLimit(cfg.MaxConcurrent, cfg.MaxQueued)
+	server.SetReadOnlyTools(tools.ReadOnlyTool)
+	server.SetLogger(logger)
+	server.SetRedactor(resultRedactor(cfg))
+	server.ToolStats().SetSlowThreshold(cfg.SlowToolCall)
+
+	// Register all tools (subject to the config file's tool allow/deny lists)
+	server.SetToolFilter(cfg.IsToolEnabled)
+	server.UseToolMiddleware(tools.ResultMiddleware)
+	server.UseToolMiddleware(tools.DefaultProjectMiddleware)
+	server.UseToolMiddleware(tools.ReadOnlyModeMiddleware)
+	if auditLog != nil {
+		server.UseToolMiddleware(tools.AuditMiddleware(auditLog))
+	}
+	if err := toolContext.ConfigurePolicies(); err != nil {
+		return nil, nil, fmt.Errorf("failed to configure policies: %w", err)
+	}
+	server.UseToolMiddleware(tools.PolicyMiddleware)
+	if cfg.AllowSudo {
+		server.UseToolMiddleware(tools.SudoMiddleware)
+		logger.Warn("GITLAB_ALLOW_SUDO is enabled: write tools accept a sudo argument to act as another user")
+	}
+	// Limit authenticated HTTP callers to the tools of their role
+	server.SetToolAccess(tools.ToolAccess)
+	if len(cfg.Roles) > 0 && !cfg.HTTPMode {
+		logger.Warn("MCP_ROLES is set but roles only apply to authenticated HTTP requests")
+	}
+	toolGroups, err := tools.Register(server, toolContext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to register tools: %w", err)
+	}
+	logger.Info("Tools registered successfully")
+
+	// Set server instructions based on enabled features and the registered tool set
+	serverInstructions := buildInstructions(cfg, toolGroups)
+	server.SetInstructions(serverInstructions)
+	logger.Debug("Server instructions set (%d bytes)", len(serverInstructions))
+
+	return server, toolContext, nil
+}
+
+// buildInstructions generates the server instructions for cfg and the
+// registered tool groups.
+func buildInstructions(cfg *config.Config, toolGroups []tools.ToolGroup) string {
+	deployment := &instructions.Deployment{
+		ReadOnly:          cfg.ReadOnlyMode,
+		Demo:              cfg.Demo,
+		DefaultNamespaces: cfg.DefaultNamespaces,
+		DefaultProjectID:  cfg.DefaultProjectID,
+		AllowedProjectIDs: cfg.AllowedProjectIDs,
+	}
+	for _, group := range toolGroups {
+		deployment.ToolGroups = append(deployment.ToolGroups, instructions.ToolGroup{Name: group.Name, Tools: group.Tools})
+	}
+	return instructions.Generate(instructions.EnabledFeatures{
+		Pipelines:  cfg.UsePipeline,
+		Milestones: cfg.UseMilestone,
+		Wiki:       cfg.UseWiki,
+		Deployment: deployment,
+	})
+}
+
+// resultRedactor returns the redactor applied to tool results, or nil when
+// GITLAB_REDACT_OUTPUT is off.
+func resultRedactor(cfg *config.Config) mcp.Redactor {
+	if cfg.OutputRedactor == nil {
+		return nil
+	}
+	return cfg.OutputRedactor
+}
+
 // convertSource converts config.ConfigSource to logging.ConfigSource
 func convertSource(src config.ConfigSource) logging.ConfigSource {
 	switch src {
@@ -234,6 +430,8 @@ func convertSource(src config.ConfigSource) logging.ConfigSource {
 		return logging.SourceFlag
 	case config.SourceEnvironment:
 		return logging.SourceEnvironment
+	case config.SourceFile:
+		return logging.SourceFile
 	default:
 		return logging.SourceDefault
 	}
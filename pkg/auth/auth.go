@@ -2,7 +2,10 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
+	"strings"
 	"sync"
 )
 
@@ -15,6 +18,9 @@ const GitLabTokenHeader = "X-GitLab-Token"
 // gitLabTokenKey is the context key for storing GitLab tokens
 type gitLabTokenKey struct{}
 
+// principalKey is the context key for storing the authenticated principal
+type principalKey struct{}
+
 // currentGitLabToken stores the per-request GitLab token (thread-local workaround)
 var (
 	currentGitLabToken string
@@ -32,6 +38,28 @@ func GitLabTokenFromContext(ctx context.Context) (string, bool) {
 	return token, ok && token != ""
 }
 
+// WithPrincipal returns a new context with the authenticated HTTP principal stored
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext retrieves the authenticated HTTP principal from context
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalKey{}).(string)
+	return principal, ok && principal != ""
+}
+
+// TokenPrincipal identifies a caller by a short fingerprint of its token, for
+// authorizers that cannot name the caller. The token itself is never exposed.
+func TokenPrincipal(token string) string {
+	token = strings.TrimSpace(token)
+	if scheme, rest, ok := strings.Cut(token, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		token = strings.TrimSpace(rest)
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:])[:12]
+}
+
 // SetCurrentGitLabToken sets the current request's GitLab token (thread-local workaround)
 func SetCurrentGitLabToken(token string) {
 	currentTokenMu.Lock()
@@ -11,6 +11,15 @@ type Authorizer interface {
 	Authorize(ctx context.Context, token string) (bool, error)
 }
 
+// PrincipalAuthorizer is implemented by authorizers that can name the caller
+// behind an authorized token, e.g. the subject of a JWT. The name is recorded
+// as the acting identity in the audit log.
+type PrincipalAuthorizer interface {
+	Authorizer
+	// Principal returns the caller's name for an authorized token.
+	Principal(ctx context.Context, token string) string
+}
+
 // MockAuthorizer is a mock implementation that always authorizes.
 type MockAuthorizer struct{}
 
@@ -20,12 +29,13 @@ func (m *MockAuthorizer) Authorize(ctx context.Context, token string) (bool, err
 }
 
 // AuthMiddleware creates an HTTP middleware that checks for Authorization header.
-// It skips authentication for the /health endpoint.
+// It skips authentication for the /health and /metrics endpoints.
 // If authorizer is nil and no expected token is configured, all requests pass.
+// Authenticated requests carry the caller's principal in their context.
 func AuthMiddleware(authorizer Authorizer, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip authentication for /health endpoint
-		if r.URL.Path == "/health" {
+		// Skip authentication for /health and /metrics endpoints
+		if r.URL.Path == "/health" || r.URL.Path == "/metrics" {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -63,6 +73,13 @@ func AuthMiddleware(authorizer Authorizer, next http.Handler) http.Handler {
 			}
 		}
 
-		next.ServeHTTP(w, r)
+		principal := ""
+		if named, ok := authorizer.(PrincipalAuthorizer); ok {
+			principal = named.Principal(r.Context(), token)
+		}
+		if principal == "" {
+			principal = TokenPrincipal(token)
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
 	})
 }
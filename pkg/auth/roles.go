@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role is the access level of an authenticated principal in HTTP mode.
+type Role string
+
+const (
+	// RoleViewer may only call read-only tools
+	RoleViewer Role = "viewer"
+	// RoleContributor may also create and update issues, merge requests,
+	// notes, branches, files, pipelines, labels, milestones and wikis
+	RoleContributor Role = "contributor"
+	// RoleMaintainer may call every tool, including deletes, merges, releases,
+	// mirrors, imports and deploy freezes
+	RoleMaintainer Role = "maintainer"
+)
+
+// level orders roles so that each includes the tools of the ones below it.
+func (r Role) level() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleContributor:
+		return 2
+	case RoleMaintainer:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Includes reports whether r grants at least the access of other.
+func (r Role) Includes(other Role) bool {
+	return r.level() > 0 && r.level() >= other.level()
+}
+
+// ParseRole converts a role name (case-insensitive) to a Role.
+func ParseRole(name string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(name)))
+	if role.level() == 0 {
+		return "", fmt.Errorf("unknown role %q (want viewer, contributor or maintainer)", name)
+	}
+	return role, nil
+}
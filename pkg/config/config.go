@@ -5,9 +5,16 @@ package config
 import (
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/redact"
 )
 
 // Version information (set at build time)
@@ -24,6 +31,19 @@ const (
 	SourceDefault     ConfigSource = "default"
 	SourceEnvironment ConfigSource = "environment"
 	SourceFlag        ConfigSource = "flag"
+	SourceFile        ConfigSource = "file"
+)
+
+// Demo mode (-demo) settings.
+const (
+	DemoAPIURL    = "https://gitlab.example.com/api/v4"
+	DemoProjectID = "acme/payments-api"
+)
+
+// GitLab API record/replay modes (GITLAB_VCR_MODE).
+const (
+	VCRRecord = "record"
+	VCRReplay = "replay"
 )
 
 // Config holds all configuration settings for the GitLab MCP server.
@@ -38,44 +58,132 @@ type Config struct {
 	AllowedProjectIDs []string
 
 	// Namespace/Group defaults
-	DefaultNamespace string // Default group/namespace for project listing and creation
+	DefaultNamespace  string   // Default group/namespace for project creation, the first of DefaultNamespaces
+	DefaultNamespaces []string // Groups/namespaces searched in order for project listings
 
 	// Feature flags
 	UsePipeline  bool
 	UseMilestone bool
 	UseWiki      bool
 	ReadOnlyMode bool
+	AllowSudo    bool // Offer a sudo argument on write tools, for administrator tokens
+
+	// Client-side rate limiting (0 = unlimited)
+	RateLimit      float64 // Sustained GitLab API requests per second
+	RateLimitBurst int     // Maximum burst size (0 = derived from RateLimit)
+
+	// Largest GitLab JSON or text response read, in bytes (0 = unlimited)
+	MaxGitLabResponse int64
+	// Smallest JSON request body sent gzipped, in bytes (0 = never)
+	GzipRequestBytes int
+	// GET responses kept for revalidation with their ETag (0 = no cache)
+	ResponseCacheEntries int
+
+	// Record/replay of GitLab API traffic for regression tests
+	VCRMode     string // "record", "replay" or empty for neither
+	VCRCassette string // Cassette file recorded to or replayed from
+
+	// MCP protocol
+	ToolsPageSize int                      // Tools per tools/list page (0 = all tools in one page)
+	ToolTimeout   time.Duration            // Default tool execution timeout (0 = none)
+	ToolTimeouts  map[string]time.Duration // Per-tool timeout overrides
+	MaxConcurrent int                      // Concurrently running tool calls (0 = unlimited)
+	MaxQueued     int                      // Tool calls waiting for a slot before ServerBusy errors
+
+	// Tool usage statistics
+	SlowToolCall     time.Duration // Calls taking longer are counted as slow (0 = none)
+	StatsLogInterval time.Duration // Period of the usage summary in the log (0 = disabled)
+
+	// Response guardrails: JSON results above this size are truncated (0 = unlimited)
+	MaxResponseBytes int
 
 	// HTTP Mode
 	HTTPMode bool
 	HTTPPort int
 	HTTPHost string
 
+	// SelfTest is set by -check/-selftest: validate and test the configuration, then exit
+	SelfTest bool
+
+	// Demo is set by -demo: serve synthetic data instead of a GitLab instance
+	Demo bool
+
 	// Logging
 	LogDir          string
 	LogLevel        string
 	AddAppSubfolder bool // When true, add app name as a subfolder to LogDir (for shared MCP_LOG_DIR)
+	AuditLog        string // Append-only audit log of write tool calls; empty disables it
+
+	// Config file (-config or MCP_CONFIG_FILE) and the instance block applied from it
+	ConfigFile string
+	Instance   string
+
+	// Tool exposure from the config file (empty AllowedTools = all tools)
+	AllowedTools []string
+	DeniedTools  []string
+
+	// Custom log extractors for get_pipeline_job_output, from the config file
+	Extractors map[string]Extractor
+
+	// Secret redaction of job logs and tool results
+	RedactLogs     bool             // Redact job logs unless a tool call opts out
+	RedactOutput   bool             // Redact every tool result
+	RedactPatterns []string         // Custom patterns from the config file, masked besides the builtin ones
+	LogRedactor    *redact.Redactor // Built from the builtin secret rules and RedactPatterns
+	OutputRedactor *redact.Redactor // Secret and PII rules plus RedactPatterns; nil unless RedactOutput
+
+	// Pre-execution policy hooks
+	Policies  []string // Names of compiled-in policies to apply, in order
+	PolicyURL string   // OPA-style HTTP decision endpoint consulted after Policies
+
+	// Conventions checked by validate_conventions: regular expressions or preset names
+	CommitPattern string // Commit titles
+	BranchPattern string // Merge request source branches
+	TitlePattern  string // Merge request titles
+
+	// Role-based tool exposure in HTTP mode (empty Roles = every principal sees all tools)
+	Roles       map[string]auth.Role // Role of each authenticated principal
+	DefaultRole auth.Role            // Role of principals missing from Roles
 
 	// Sources tracking - maps config key to its source
 	Sources map[string]ConfigSource
+
+	// file is the loaded config file, consulted after flags and environment
+	file *configFile
+}
+
+// cliFlags holds the CLI flag values parsed by LoadConfig, reused by Reload.
+type cliFlags struct {
+	configFile string
+	instance   string
+	logDir     string
+	logLevel string
+	httpMode bool
+	httpPort int
+	httpHost string
+	demo     bool
 }
 
+// parsedFlags is set by LoadConfig once flags have been parsed.
+var parsedFlags *cliFlags
+
 // LoadConfig loads configuration from CLI flags and environment variables.
 // CLI flags take precedence over environment variables, which take precedence over defaults.
 // Returns the populated Config struct and any error encountered.
 // If -version or -help flags are set, returns nil config with no error (caller should exit).
 func LoadConfig() (*Config, error) {
-	cfg := &Config{
-		Sources: make(map[string]ConfigSource),
-	}
-
 	// Define CLI flags
 	var (
+		configFile  = flag.String("config", "", "Path to a YAML config file")
+		instance    = flag.String("instance", "", "Instance block of the config file to apply")
 		logDir      = flag.String("log-dir", "", "Log directory path")
 		logLevel    = flag.String("log-level", "", "Log level: off, error, warn, info, access, debug")
 		httpMode    = flag.Bool("http", false, "Run in HTTP mode instead of stdio")
 		httpPort    = flag.Int("port", 3000, "HTTP port (only used with --http)")
 		httpHost    = flag.String("host", "127.0.0.1", "HTTP host (only used with --http)")
+		demo        = flag.Bool("demo", false, "Serve synthetic demo data instead of a GitLab instance")
+		check       = flag.Bool("check", false, "Validate configuration and GitLab access, then exit")
+		selfTest    = flag.Bool("selftest", false, "Alias for -check")
 		showVersion = flag.Bool("version", false, "Show version information")
 		showHelp    = flag.Bool("help", false, "Show help message")
 	)
@@ -97,6 +205,66 @@ func LoadConfig() (*Config, error) {
 		return nil, nil
 	}
 
+	parsedFlags = &cliFlags{
+		configFile: *configFile,
+		instance:   *instance,
+		logDir:     *logDir,
+		logLevel: *logLevel,
+		httpMode: *httpMode,
+		httpPort: *httpPort,
+		httpHost: *httpHost,
+		demo:     *demo,
+	}
+	cfg, err := load(parsedFlags)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SelfTest = *check || *selfTest
+	return cfg, nil
+}
+
+// Reload re-reads configuration from the current environment, the config
+// file and token sources such as glab or netrc, keeping the CLI flags given
+// at startup.
+// It is used to apply configuration changes without restarting the server.
+func Reload() (*Config, error) {
+	if parsedFlags == nil {
+		return nil, fmt.Errorf("configuration has not been loaded")
+	}
+	return load(parsedFlags)
+}
+
+// load builds a Config from the given flags, environment variables, the
+// config file and defaults, in that order of precedence.
+func load(flags *cliFlags) (*Config, error) {
+	cfg := &Config{
+		Sources: make(map[string]ConfigSource),
+	}
+
+	// Load the optional config file first; its values rank below flags and env
+	cfg.ConfigFile = cfg.loadStringWithFlag(
+		"ConfigFile",
+		flags.configFile,
+		"MCP_CONFIG_FILE",
+		"",
+	)
+	if cfg.ConfigFile != "" {
+		instance := cfg.loadStringWithFlag("Instance", flags.instance, "GITLAB_INSTANCE", "")
+		file, err := loadConfigFile(cfg.ConfigFile, instance)
+		if err != nil {
+			return nil, err
+		}
+		cfg.file = file
+		cfg.Instance = file.instance
+		if file.instance != "" && instance == "" {
+			cfg.Sources["Instance"] = SourceFile
+		}
+		cfg.AllowedTools = file.allowTools
+		cfg.DeniedTools = file.denyTools
+		cfg.Extractors = file.extractors
+		cfg.RedactPatterns = file.redactions
+	}
+
 	// Load GitLab API URL
 	cfg.GitLabAPIURL = cfg.loadString(
 		"GitLabAPIURL",
@@ -112,10 +280,19 @@ func LoadConfig() (*Config, error) {
 	cfg.GitLabToken = credResult.Token
 	cfg.TokenSource = credResult.Source
 
+	// A token in the config file ranks below environment variables but above
+	// the auto-discovered sources
+	if cfg.file != nil && cfg.file.token != "" && credResult.Source != CredentialSourceEnv {
+		cfg.GitLabToken = cfg.file.token
+		cfg.TokenSource = CredentialSourceFile
+	}
+
 	// Map credential source to config source for logging
-	switch credResult.Source {
+	switch cfg.TokenSource {
 	case CredentialSourceEnv:
 		cfg.Sources["GitLabToken"] = SourceEnvironment
+	case CredentialSourceFile:
+		cfg.Sources["GitLabToken"] = SourceFile
 	case CredentialSourceGlab, CredentialSourceGitCredential, CredentialSourceNetrc:
 		cfg.Sources["GitLabToken"] = SourceDefault // Treat auto-discovered as "default" for simplicity
 	default:
@@ -123,12 +300,22 @@ func LoadConfig() (*Config, error) {
 	}
 
 	// Load project restrictions
+	// Load the default project; GITLAB_DEFAULT_PROJECT takes precedence over
+	// the older GITLAB_PROJECT_ID
 	cfg.DefaultProjectID = cfg.loadString(
 		"DefaultProjectID",
 		"",
-		"GITLAB_PROJECT_ID",
+		"GITLAB_DEFAULT_PROJECT",
 		"",
 	)
+	if cfg.DefaultProjectID == "" {
+		cfg.DefaultProjectID = cfg.loadString(
+			"DefaultProjectID",
+			"",
+			"GITLAB_PROJECT_ID",
+			"",
+		)
+	}
 
 	// Load allowed project IDs (comma-separated)
 	allowedProjectsStr := cfg.loadString(
@@ -141,13 +328,18 @@ func LoadConfig() (*Config, error) {
 		cfg.AllowedProjectIDs = parseCommaSeparated(allowedProjectsStr)
 	}
 
-	// Load default namespace/group for project operations
-	cfg.DefaultNamespace = cfg.loadString(
+	// Load default namespaces/groups for project operations. A comma-separated
+	// list is searched in order; the first one is the default for creation.
+	defaultNamespaceStr := cfg.loadString(
 		"DefaultNamespace",
 		"",
 		"GITLAB_DEFAULT_NAMESPACE",
 		"",
 	)
+	cfg.DefaultNamespaces = parseCommaSeparated(defaultNamespaceStr)
+	if len(cfg.DefaultNamespaces) > 0 {
+		cfg.DefaultNamespace = cfg.DefaultNamespaces[0]
+	}
 
 	// Load feature flags
 	cfg.UsePipeline = cfg.loadBool(
@@ -178,10 +370,204 @@ func LoadConfig() (*Config, error) {
 		false,
 	)
 
+	// Load sudo support: write tools may act as another user
+	cfg.AllowSudo = cfg.loadBool(
+		"AllowSudo",
+		false,
+		"GITLAB_ALLOW_SUDO",
+		false,
+	)
+
+	// Load job log redaction
+	cfg.RedactLogs = cfg.loadBool(
+		"RedactLogs",
+		false,
+		"GITLAB_REDACT_LOGS",
+		true,
+	)
+	cfg.RedactOutput = cfg.loadBool(
+		"RedactOutput",
+		false,
+		"GITLAB_REDACT_OUTPUT",
+		false,
+	)
+	// Invalid custom patterns leave the redactors nil and are reported by Validate
+	cfg.LogRedactor, _ = redact.New(redact.SecretRules, cfg.RedactPatterns)
+	if cfg.RedactOutput {
+		rules := append(append([]redact.Rule{}, redact.SecretRules...), redact.PIIRules...)
+		cfg.OutputRedactor, _ = redact.New(rules, cfg.RedactPatterns)
+	}
+
+	// Load commit, branch and merge request title conventions
+	cfg.CommitPattern = cfg.loadString(
+		"CommitPattern",
+		"",
+		"GITLAB_COMMIT_PATTERN",
+		"",
+	)
+	cfg.BranchPattern = cfg.loadString(
+		"BranchPattern",
+		"",
+		"GITLAB_BRANCH_PATTERN",
+		"",
+	)
+	cfg.TitlePattern = cfg.loadString(
+		"TitlePattern",
+		"",
+		"GITLAB_MR_TITLE_PATTERN",
+		"",
+	)
+
+	// Load pre-execution policy hooks
+	if policies := cfg.loadString("Policies", "", "MCP_POLICIES", ""); policies != "" {
+		cfg.Policies = parseCommaSeparated(policies)
+	}
+	cfg.PolicyURL = cfg.loadString(
+		"PolicyURL",
+		"",
+		"MCP_POLICY_URL",
+		"",
+	)
+
+	// Load client-side rate limit
+	cfg.RateLimit = cfg.loadFloat(
+		"RateLimit",
+		"GITLAB_RATE_LIMIT",
+		0,
+	)
+	cfg.RateLimitBurst = int(cfg.loadFloat(
+		"RateLimitBurst",
+		"GITLAB_RATE_LIMIT_BURST",
+		0,
+	))
+
+	// Load GitLab response size limit
+	cfg.MaxGitLabResponse = int64(cfg.loadFloat(
+		"MaxGitLabResponse",
+		"GITLAB_MAX_RESPONSE_SIZE",
+		64<<20,
+	))
+
+	// Load request body compression threshold
+	cfg.GzipRequestBytes = int(cfg.loadFloat(
+		"GzipRequestBytes",
+		"GITLAB_GZIP_REQUEST_BYTES",
+		0,
+	))
+
+	// Load GitLab response cache size
+	cfg.ResponseCacheEntries = int(cfg.loadFloat(
+		"ResponseCacheEntries",
+		"GITLAB_RESPONSE_CACHE_ENTRIES",
+		0,
+	))
+
+	// Load GitLab API record/replay mode
+	cfg.VCRMode = strings.ToLower(cfg.loadString(
+		"VCRMode",
+		"",
+		"GITLAB_VCR_MODE",
+		"",
+	))
+	cfg.VCRCassette = ExpandPath(cfg.loadString(
+		"VCRCassette",
+		"",
+		"GITLAB_VCR_CASSETTE",
+		"",
+	))
+
+	// Load tools/list page size
+	cfg.ToolsPageSize = int(cfg.loadFloat(
+		"ToolsPageSize",
+		"MCP_TOOLS_PAGE_SIZE",
+		0,
+	))
+
+	// Load tool execution timeouts
+	toolTimeout := cfg.loadString(
+		"ToolTimeout",
+		"",
+		"MCP_TOOL_TIMEOUT",
+		"5m",
+	)
+	timeout, err := time.ParseDuration(toolTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MCP_TOOL_TIMEOUT %q: %w", toolTimeout, err)
+	}
+	cfg.ToolTimeout = timeout
+
+	toolTimeouts := cfg.loadString(
+		"ToolTimeouts",
+		"",
+		"MCP_TOOL_TIMEOUTS",
+		"",
+	)
+	if cfg.ToolTimeouts, err = parseToolTimeouts(toolTimeouts); err != nil {
+		return nil, err
+	}
+
+	// Load tool usage statistics settings
+	slowToolCall := cfg.loadString(
+		"SlowToolCall",
+		"",
+		"MCP_SLOW_TOOL_CALL",
+		"5s",
+	)
+	if cfg.SlowToolCall, err = time.ParseDuration(slowToolCall); err != nil {
+		return nil, fmt.Errorf("invalid MCP_SLOW_TOOL_CALL %q: %w", slowToolCall, err)
+	}
+	statsLogInterval := cfg.loadString(
+		"StatsLogInterval",
+		"",
+		"MCP_STATS_LOG_INTERVAL",
+		"1h",
+	)
+	if cfg.StatsLogInterval, err = time.ParseDuration(statsLogInterval); err != nil {
+		return nil, fmt.Errorf("invalid MCP_STATS_LOG_INTERVAL %q: %w", statsLogInterval, err)
+	}
+
+	// Load principal roles
+	roles := cfg.loadString(
+		"Roles",
+		"",
+		"MCP_ROLES",
+		"",
+	)
+	if cfg.Roles, err = parseRoles(roles); err != nil {
+		return nil, err
+	}
+	if cfg.DefaultRole, err = auth.ParseRole(cfg.loadString("DefaultRole", "", "MCP_DEFAULT_ROLE", string(auth.RoleViewer))); err != nil {
+		return nil, fmt.Errorf("invalid MCP_DEFAULT_ROLE: %w", err)
+	}
+
+	// Load tool concurrency limits
+	cfg.MaxConcurrent = int(cfg.loadFloat(
+		"MaxConcurrent",
+		"MCP_MAX_CONCURRENT_TOOLS",
+		10,
+	))
+	cfg.MaxQueued = int(cfg.loadFloat(
+		"MaxQueued",
+		"MCP_MAX_QUEUED_TOOLS",
+		50,
+	))
+
+	// Load response size budget; a token budget is converted at ~4 bytes per token
+	cfg.MaxResponseBytes = int(cfg.loadFloat(
+		"MaxResponseBytes",
+		"MCP_MAX_RESPONSE_BYTES",
+		256*1024,
+	))
+	if tokens := int(cfg.loadFloat("MaxResponseTokens", "MCP_MAX_RESPONSE_TOKENS", 0)); tokens > 0 {
+		if bytes := tokens * 4; cfg.MaxResponseBytes <= 0 || bytes < cfg.MaxResponseBytes {
+			cfg.MaxResponseBytes = bytes
+		}
+	}
+
 	// Load logging configuration
 	cfg.LogDir = ExpandPath(cfg.loadStringWithFlag(
 		"LogDir",
-		*logDir,
+		flags.logDir,
 		"MCP_LOG_DIR",
 		getDefaultLogDir(),
 	))
@@ -191,15 +577,41 @@ func LoadConfig() (*Config, error) {
 
 	cfg.LogLevel = cfg.loadStringWithFlag(
 		"LogLevel",
-		*logLevel,
+		flags.logLevel,
 		"MCP_LOG_LEVEL",
 		"info",
 	)
 
+	cfg.AuditLog = ExpandPath(cfg.loadString(
+		"AuditLog",
+		"",
+		"MCP_AUDIT_LOG",
+		"",
+	))
+
 	// Load HTTP mode configuration
-	cfg.HTTPMode = *httpMode
-	cfg.HTTPPort = *httpPort
-	cfg.HTTPHost = *httpHost
+	cfg.HTTPMode = flags.httpMode
+	cfg.HTTPPort = flags.httpPort
+	cfg.HTTPHost = flags.httpHost
+
+	// Demo mode answers from synthetic data, so it needs no GitLab or token
+	// and enables every tool group the data covers
+	if flags.demo {
+		cfg.Demo = true
+		cfg.GitLabAPIURL = DemoAPIURL
+		cfg.GitLabToken = ""
+		cfg.TokenSource = CredentialSourceNone
+		cfg.UsePipeline = true
+		cfg.UseMilestone = true
+		cfg.ReadOnlyMode = true
+		cfg.VCRMode = ""
+		if cfg.DefaultProjectID == "" {
+			cfg.DefaultProjectID = DemoProjectID
+		}
+		for _, key := range []string{"GitLabAPIURL", "GitLabToken", "UsePipeline", "UseMilestone", "ReadOnlyMode"} {
+			cfg.Sources[key] = SourceFlag
+		}
+	}
 
 	return cfg, nil
 }
@@ -213,12 +625,18 @@ func (c *Config) loadString(key, flagVal, envVar, defaultVal string) string {
 		return flagVal
 	}
 
-	// Environment variable takes precedence over default
+	// Environment variable takes precedence over the config file
 	if envVal := os.Getenv(envVar); envVal != "" {
 		c.Sources[key] = SourceEnvironment
 		return envVal
 	}
 
+	// Config file takes precedence over default
+	if fileVal, ok := c.fileValue(envVar); ok {
+		c.Sources[key] = SourceFile
+		return fileVal
+	}
+
 	// Use default
 	c.Sources[key] = SourceDefault
 	return defaultVal
@@ -232,12 +650,18 @@ func (c *Config) loadStringWithFlag(key, flagVal, envVar, defaultVal string) str
 		return flagVal
 	}
 
-	// Environment variable takes precedence over default
+	// Environment variable takes precedence over the config file
 	if envVal := os.Getenv(envVar); envVal != "" {
 		c.Sources[key] = SourceEnvironment
 		return envVal
 	}
 
+	// Config file takes precedence over default
+	if fileVal, ok := c.fileValue(envVar); ok {
+		c.Sources[key] = SourceFile
+		return fileVal
+	}
+
 	// Use default
 	c.Sources[key] = SourceDefault
 	return defaultVal
@@ -245,23 +669,60 @@ func (c *Config) loadStringWithFlag(key, flagVal, envVar, defaultVal string) str
 
 // loadBool loads a boolean configuration value from environment variable or default.
 func (c *Config) loadBool(key string, flagVal bool, envVar string, defaultVal bool) bool {
-	// Environment variable takes precedence over default
+	// Environment variable takes precedence over the config file
 	if envVal := os.Getenv(envVar); envVal != "" {
 		c.Sources[key] = SourceEnvironment
 		return parseBool(envVal)
 	}
 
+	// Config file takes precedence over default
+	if fileVal, ok := c.fileValue(envVar); ok {
+		c.Sources[key] = SourceFile
+		return parseBool(fileVal)
+	}
+
 	// Use default
 	c.Sources[key] = SourceDefault
 	return defaultVal
 }
 
+// loadFloat loads a numeric configuration value from environment variable or default.
+// Unparseable values fall back to the default.
+func (c *Config) loadFloat(key, envVar string, defaultVal float64) float64 {
+	if envVal := os.Getenv(envVar); envVal != "" {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(envVal), 64); err == nil {
+			c.Sources[key] = SourceEnvironment
+			return v
+		}
+	}
+
+	if fileVal, ok := c.fileValue(envVar); ok {
+		if v, err := strconv.ParseFloat(fileVal, 64); err == nil {
+			c.Sources[key] = SourceFile
+			return v
+		}
+	}
+
+	c.Sources[key] = SourceDefault
+	return defaultVal
+}
+
+// fileValue returns the config file value for the setting named by envVar.
+func (c *Config) fileValue(envVar string) (string, bool) {
+	if c.file == nil {
+		return "", false
+	}
+	value, ok := c.file.values[envVar]
+	return value, ok
+}
+
 // Validate checks that all required configuration fields are set.
 // Returns an error describing any missing required fields.
 func (c *Config) Validate() error {
 	var errors []string
 
-	if c.GitLabToken == "" {
+	// Replaying a cassette or demo data needs no credentials
+	if c.GitLabToken == "" && c.VCRMode != VCRReplay && !c.Demo {
 		errors = append(errors, `GitLab token not found. Checked the following sources:
     1. Environment variables: GITLAB_PERSONAL_ACCESS_TOKEN, GITLAB_TOKEN, GITLAB_ACCESS_TOKEN, GL_TOKEN
     2. GitLab CLI (glab) config: ~/.config/glab-cli/config.yml
@@ -274,6 +735,64 @@ func (c *Config) Validate() error {
 		errors = append(errors, "GitLab API URL cannot be empty")
 	}
 
+	if c.RateLimit < 0 {
+		errors = append(errors, "GITLAB_RATE_LIMIT cannot be negative")
+	}
+
+	if c.MaxGitLabResponse < 0 {
+		errors = append(errors, "GITLAB_MAX_RESPONSE_SIZE cannot be negative")
+	}
+
+	if c.ResponseCacheEntries < 0 {
+		errors = append(errors, "GITLAB_RESPONSE_CACHE_ENTRIES cannot be negative")
+	}
+
+	switch c.VCRMode {
+	case "":
+	case VCRRecord, VCRReplay:
+		if c.VCRCassette == "" {
+			errors = append(errors, fmt.Sprintf("GITLAB_VCR_MODE=%s requires GITLAB_VCR_CASSETTE", c.VCRMode))
+		}
+	default:
+		errors = append(errors, fmt.Sprintf("GITLAB_VCR_MODE %q must be record or replay", c.VCRMode))
+	}
+
+	if c.MaxConcurrent < 0 || c.MaxQueued < 0 {
+		errors = append(errors, "MCP_MAX_CONCURRENT_TOOLS and MCP_MAX_QUEUED_TOOLS cannot be negative")
+	}
+
+	if c.ToolsPageSize < 0 {
+		errors = append(errors, "MCP_TOOLS_PAGE_SIZE cannot be negative")
+	}
+
+	for name, extractor := range c.Extractors {
+		if _, err := regexp.Compile(extractor.Pattern); err != nil || extractor.Pattern == "" {
+			errors = append(errors, fmt.Sprintf("extractor %q has an invalid pattern: %q", name, extractor.Pattern))
+		}
+	}
+
+	for _, pattern := range c.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil || pattern == "" {
+			errors = append(errors, fmt.Sprintf("redaction pattern %q is invalid", pattern))
+		}
+	}
+
+	for _, convention := range []struct{ envVar, pattern string }{
+		{"GITLAB_COMMIT_PATTERN", c.CommitPattern},
+		{"GITLAB_BRANCH_PATTERN", c.BranchPattern},
+		{"GITLAB_MR_TITLE_PATTERN", c.TitlePattern},
+	} {
+		if _, err := regexp.Compile(ConventionPattern(convention.pattern)); err != nil {
+			errors = append(errors, fmt.Sprintf("%s %q is not a valid regular expression", convention.envVar, convention.pattern))
+		}
+	}
+
+	if c.PolicyURL != "" {
+		if u, err := url.Parse(c.PolicyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errors = append(errors, fmt.Sprintf("MCP_POLICY_URL %q is not an http(s) URL", c.PolicyURL))
+		}
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("configuration validation failed:\n  - %s", strings.Join(errors, "\n  - "))
 	}
@@ -281,6 +800,24 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// ConventionPresets are the named patterns accepted wherever a convention
+// pattern is configured, e.g. GITLAB_COMMIT_PATTERN=conventional.
+var ConventionPresets = map[string]string{
+	// Conventional Commits: type(scope)!: description
+	"conventional": `^(build|chore|ci|docs|feat|fix|perf|refactor|revert|style|test)(\([\w./-]+\))?!?: \S`,
+	// A ticket key such as ABC-123 first, optionally in brackets
+	"ticket": `^\[?[A-Z][A-Z0-9]+-[0-9]+\]?`,
+}
+
+// ConventionPattern returns the regular expression of a convention pattern,
+// expanding preset names.
+func ConventionPattern(pattern string) string {
+	if preset, ok := ConventionPresets[pattern]; ok {
+		return preset
+	}
+	return pattern
+}
+
 // GetEnabledFeatures returns a list of enabled feature flag names.
 func (c *Config) GetEnabledFeatures() []string {
 	var features []string
@@ -325,6 +862,25 @@ func (c *Config) IsProjectAllowed(projectID string) bool {
 	return false
 }
 
+// IsToolEnabled reports whether a tool may be registered: it must be in
+// AllowedTools (when set) and must not be in DeniedTools.
+func (c *Config) IsToolEnabled(name string) bool {
+	for _, denied := range c.DeniedTools {
+		if name == denied {
+			return false
+		}
+	}
+	if len(c.AllowedTools) == 0 {
+		return true
+	}
+	for _, allowed := range c.AllowedTools {
+		if name == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // ExpandPath expands ~ to the user's home directory in file paths.
 // This is necessary because ~ is a shell feature and is not automatically
 // expanded when paths are passed via environment variables or config files.
@@ -371,6 +927,42 @@ func parseCommaSeparated(s string) []string {
 	return result
 }
 
+// parseToolTimeouts parses per-tool timeouts given as comma-separated
+// tool=duration pairs, e.g. "get_pipeline_job_output=2m,list_projects=20s".
+func parseToolTimeouts(s string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range parseCommaSeparated(s) {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid MCP_TOOL_TIMEOUTS entry %q: expected tool=duration", entry)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid MCP_TOOL_TIMEOUTS entry %q: %w", entry, err)
+		}
+		timeouts[strings.TrimSpace(name)] = timeout
+	}
+	return timeouts, nil
+}
+
+// parseRoles parses principal roles given as comma-separated principal=role
+// pairs, e.g. "alice=maintainer,token:3a7bd3e2360a=viewer".
+func parseRoles(s string) (map[string]auth.Role, error) {
+	roles := make(map[string]auth.Role)
+	for _, entry := range parseCommaSeparated(s) {
+		principal, name, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(principal) == "" {
+			return nil, fmt.Errorf("invalid MCP_ROLES entry %q: expected principal=role", entry)
+		}
+		role, err := auth.ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MCP_ROLES entry %q: %w", entry, err)
+		}
+		roles[strings.TrimSpace(principal)] = role
+	}
+	return roles, nil
+}
+
 // parseBool converts a string to a boolean value.
 // Accepts: "true", "1", "yes", "on" (case-insensitive) as true, everything else as false.
 func parseBool(s string) bool {
@@ -390,8 +982,12 @@ func printHelp() {
 	fmt.Println("Usage: go-mcp-gitlab [OPTIONS]")
 	fmt.Println()
 	fmt.Println("Options:")
+	fmt.Println("  -config <path>      YAML config file (settings rank below flags and env)")
+	fmt.Println("  -instance <name>    Instance block of the config file to apply")
 	fmt.Println("  -log-dir <path>     Log directory (default: ~/go-mcp-gitlab/logs)")
 	fmt.Println("  -log-level <level>  Log level: off, error, warn, info, access, debug (default: info)")
+	fmt.Println("  -check, -selftest   Validate configuration, GitLab access and log directory, then exit")
+	fmt.Println("  -demo               Serve synthetic demo data, without a GitLab connection or token")
 	fmt.Println("  -version            Show version information")
 	fmt.Println("  -help               Show this help message")
 	fmt.Println()
@@ -407,15 +1003,43 @@ func printHelp() {
 	fmt.Println()
 	fmt.Println("Environment Variables:")
 	fmt.Println("  GITLAB_API_URL                GitLab API URL (default: https://gitlab.com/api/v4)")
-	fmt.Println("  GITLAB_PROJECT_ID             Default project ID")
+	fmt.Println("  GITLAB_DEFAULT_PROJECT        Project used when a tool call omits project_id")
+	fmt.Println("  GITLAB_PROJECT_ID             Older name for GITLAB_DEFAULT_PROJECT")
 	fmt.Println("  GITLAB_ALLOWED_PROJECT_IDS    Comma-separated list of allowed project IDs")
-	fmt.Println("  GITLAB_DEFAULT_NAMESPACE      Default namespace/group for project operations (ID or path)")
+	fmt.Println("  GITLAB_DEFAULT_NAMESPACE      Default namespaces/groups for project operations (comma-separated IDs or paths, searched in order)")
 	fmt.Println("  USE_PIPELINE                  Enable pipeline tools (default: false)")
 	fmt.Println("  USE_MILESTONE                 Enable milestone tools (default: false)")
 	fmt.Println("  USE_GITLAB_WIKI               Enable wiki tools (default: false)")
 	fmt.Println("  GITLAB_READ_ONLY_MODE         Enable read-only mode (default: false)")
+	fmt.Println("  GITLAB_ALLOW_SUDO             Let write tools act as another user with an admin token (default: false)")
+	fmt.Println("  GITLAB_REDACT_LOGS            Mask secrets in job logs returned by tools (default: true)")
+	fmt.Println("  GITLAB_REDACT_OUTPUT          Mask secrets, emails and custom patterns in all tool results (default: false)")
+	fmt.Println("  GITLAB_RATE_LIMIT             Client-side limit on GitLab requests per second (default: 0, unlimited)")
+	fmt.Println("  GITLAB_RATE_LIMIT_BURST       Burst size for GITLAB_RATE_LIMIT (default: derived from the rate)")
+	fmt.Println("  GITLAB_MAX_RESPONSE_SIZE      Largest GitLab JSON or text response read, in bytes (default: 67108864, 0 = unlimited)")
+	fmt.Println("  GITLAB_GZIP_REQUEST_BYTES     Gzip JSON request bodies of at least this many bytes (default: 0, off)")
+	fmt.Println("  GITLAB_RESPONSE_CACHE_ENTRIES GET responses cached and revalidated by ETag (default: 0, off)")
+	fmt.Println("  GITLAB_VCR_MODE               Record GitLab responses to, or replay them from, a cassette: record|replay")
+	fmt.Println("  GITLAB_VCR_CASSETTE           Cassette file for GITLAB_VCR_MODE")
+	fmt.Println("  MCP_CONFIG_FILE               YAML config file path (same as -config)")
+	fmt.Println("  GITLAB_INSTANCE               Config file instance block (same as -instance)")
+	fmt.Println("  MCP_TOOLS_PAGE_SIZE           Tools per tools/list page (default: 0, all tools in one page)")
+	fmt.Println("  MCP_TOOL_TIMEOUT              Default tool execution timeout, e.g. 90s (default: 5m, 0 disables)")
+	fmt.Println("  MCP_TOOL_TIMEOUTS             Per-tool timeouts, e.g. get_pipeline_job_output=2m,list_projects=20s")
+	fmt.Println("  MCP_SLOW_TOOL_CALL            Log and count tool calls taking longer as slow (default: 5s, 0 disables)")
+	fmt.Println("  MCP_STATS_LOG_INTERVAL        Log a tool usage summary this often (default: 1h, 0 disables)")
+	fmt.Println("  MCP_MAX_CONCURRENT_TOOLS      Tool calls running at once (default: 10, 0 = unlimited)")
+	fmt.Println("  MCP_MAX_QUEUED_TOOLS          Tool calls waiting for a slot before busy errors (default: 50)")
+	fmt.Println("  MCP_MAX_RESPONSE_BYTES        Truncate JSON tool results above this size (default: 262144, 0 = unlimited)")
+	fmt.Println("  MCP_MAX_RESPONSE_TOKENS       Alternative budget in tokens (~4 bytes each); the smaller limit wins")
 	fmt.Println("  MCP_LOG_DIR                   Log directory path")
 	fmt.Println("  MCP_LOG_LEVEL                 Log level")
+	fmt.Println("  MCP_POLICIES                  Compiled-in policy hooks to apply before tool calls, comma-separated")
+	fmt.Println("  MCP_POLICY_URL                OPA-style HTTP endpoint that allows or denies each tool call")
+	fmt.Println("  MCP_ROLES                     HTTP principal roles, e.g. alice=maintainer,bob=viewer (default: no role checks)")
+	fmt.Println("  MCP_DEFAULT_ROLE              Role of principals missing from MCP_ROLES: viewer|contributor|maintainer (default: viewer)")
+	fmt.Println("  MCP_AUDIT_LOG                 Append write tool calls to this audit log file (default: disabled)")
+	fmt.Println("  OTEL_EXPORTER_OTLP_ENDPOINT   Enable OpenTelemetry export to this OTLP/HTTP endpoint")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  # Using environment variable")
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// isolateEnv clears the settings and token sources of the test's environment
+// so that only what the test sets is loaded.
+func isolateEnv(t *testing.T) {
+	t.Helper()
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, prefix := range []string{"GITLAB_", "GL_", "MCP_", "USE_"} {
+			if strings.HasPrefix(name, prefix) {
+				t.Setenv(name, "")
+			}
+		}
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("NETRC", filepath.Join(home, "netrc"))
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+}
+
+// writeGlabConfig stores a glab CLI token for gitlab.com in the isolated home.
+func writeGlabConfig(t *testing.T, token string) {
+	t.Helper()
+	dir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "glab-cli")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	content := "hosts:\n  gitlab.com:\n    token: " + token + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		flag       string
+		env        string
+		file       string
+		want       string
+		wantSource ConfigSource
+	}{
+		{name: "flag over everything", flag: "debug", env: "warn", file: "error", want: "debug", wantSource: SourceFlag},
+		{name: "env over file", env: "warn", file: "error", want: "warn", wantSource: SourceEnvironment},
+		{name: "file over default", file: "error", want: "error", wantSource: SourceFile},
+		{name: "default", want: "info", wantSource: SourceDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t)
+			content := "features:\n  wiki: true\n"
+			if tt.file != "" {
+				content += "logging:\n  level: " + tt.file + "\n"
+			}
+			if tt.env != "" {
+				t.Setenv("MCP_LOG_LEVEL", tt.env)
+			}
+			cfg, err := load(&cliFlags{configFile: writeConfig(t, "config.yaml", content), logLevel: tt.flag})
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if cfg.LogLevel != tt.want || cfg.Sources["LogLevel"] != tt.wantSource {
+				t.Errorf("LogLevel = %q from %s, want %q from %s", cfg.LogLevel, cfg.Sources["LogLevel"], tt.want, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestLoadSources(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("USE_PIPELINE", "false")
+	path := writeConfig(t, "config.yaml", instancesConfig)
+	cfg, err := load(&cliFlags{configFile: path})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	want := map[string]ConfigSource{
+		"ConfigFile":       SourceFlag,
+		"Instance":         SourceFile,
+		"GitLabAPIURL":     SourceFile,
+		"ReadOnlyMode":     SourceFile,
+		"UsePipeline":      SourceEnvironment,
+		"UseMilestone":     SourceDefault,
+		"DefaultProjectID": SourceDefault,
+	}
+	for key, source := range want {
+		if cfg.Sources[key] != source {
+			t.Errorf("Sources[%s] = %q, want %q", key, cfg.Sources[key], source)
+		}
+	}
+	if cfg.Instance != "staging" || cfg.GitLabAPIURL != "https://staging.example.com/api/v4" || !cfg.ReadOnlyMode || cfg.UsePipeline {
+		t.Errorf("config = instance %q, url %q, read-only %v, pipeline %v", cfg.Instance, cfg.GitLabAPIURL, cfg.ReadOnlyMode, cfg.UsePipeline)
+	}
+	if len(cfg.DeniedTools) != 1 || cfg.DeniedTools[0] != "delete_issue" {
+		t.Errorf("DeniedTools = %v", cfg.DeniedTools)
+	}
+
+	// The instance can also come from the flag or the environment
+	t.Setenv("GITLAB_INSTANCE", "prod")
+	cfg, err = load(&cliFlags{configFile: path})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Instance != "prod" || cfg.Sources["Instance"] != SourceEnvironment || cfg.GitLabAPIURL != "https://gitlab.example.com/api/v4" {
+		t.Errorf("instance %q from %s with url %q, want prod from the environment", cfg.Instance, cfg.Sources["Instance"], cfg.GitLabAPIURL)
+	}
+
+	if _, err := load(&cliFlags{configFile: path, instance: "qa"}); err == nil || !strings.Contains(err.Error(), `instance "qa" not found`) {
+		t.Errorf("load with an unknown instance = %v", err)
+	}
+}
+
+func TestLoadToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string
+		glab       string
+		file       string
+		want       string
+		wantSource CredentialSource
+		wantConfig ConfigSource
+	}{
+		{name: "env over file", env: "env-token", file: "file-token", want: "env-token", wantSource: CredentialSourceEnv, wantConfig: SourceEnvironment},
+		// The file ranks above the auto-discovered sources, although
+		// ResolveGitLabToken finds those first
+		{name: "file over glab", glab: "glab-token", file: "file-token", want: "file-token", wantSource: CredentialSourceFile, wantConfig: SourceFile},
+		{name: "glab without file token", glab: "glab-token", want: "glab-token", wantSource: CredentialSourceGlab, wantConfig: SourceDefault},
+		{name: "file only", file: "file-token", want: "file-token", wantSource: CredentialSourceFile, wantConfig: SourceFile},
+		{name: "none", wantSource: CredentialSourceNone, wantConfig: SourceDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t)
+			if tt.env != "" {
+				t.Setenv("GITLAB_TOKEN", tt.env)
+			}
+			if tt.glab != "" {
+				writeGlabConfig(t, tt.glab)
+			}
+			content := "features:\n  wiki: true\n"
+			if tt.file != "" {
+				content = "gitlab:\n  token: " + tt.file + "\n"
+			}
+			cfg, err := load(&cliFlags{configFile: writeConfig(t, "config.yaml", content)})
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if cfg.GitLabToken != tt.want || cfg.TokenSource != tt.wantSource || cfg.Sources["GitLabToken"] != tt.wantConfig {
+				t.Errorf("token %q from %s (%s), want %q from %s (%s)",
+					cfg.GitLabToken, cfg.TokenSource, cfg.Sources["GitLabToken"], tt.want, tt.wantSource, tt.wantConfig)
+			}
+		})
+	}
+}
@@ -29,6 +29,7 @@ const (
 	CredentialSourceGlab          CredentialSource = "glab-cli"
 	CredentialSourceGitCredential CredentialSource = "git-credential"
 	CredentialSourceNetrc         CredentialSource = "netrc"
+	CredentialSourceFile          CredentialSource = "config-file"
 	CredentialSourceNone          CredentialSource = "none"
 )
 
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Extractor is a custom log extractor for get_pipeline_job_output, defined in
+// the config file. Pattern is a regular expression; when it has a capture
+// group, the first group is returned instead of the whole match.
+type Extractor struct {
+	Pattern     string `yaml:"pattern"`
+	Description string `yaml:"description"`
+}
+
+// fileSettings holds the settings that may appear at the top level of the
+// config file or inside an instance block.
+type fileSettings struct {
+	GitLab      fileGitLab           `yaml:"gitlab"`
+	Features    fileFeatures         `yaml:"features"`
+	Logging     fileLogging          `yaml:"logging"`
+	MCP         fileMCP              `yaml:"mcp"`
+	Tools       fileTools            `yaml:"tools"`
+	Extractors  map[string]Extractor `yaml:"extractors"`
+	Redaction   fileRedaction        `yaml:"redaction"`
+	Policy      filePolicy           `yaml:"policy"`
+	Roles       fileRoles            `yaml:"roles"`
+	Conventions fileConventions      `yaml:"conventions"`
+}
+
+// fileGitLab is the "gitlab" section of the config file.
+type fileGitLab struct {
+	APIURL               string   `yaml:"api_url"`
+	Token                string   `yaml:"token"`
+	ProjectID            string   `yaml:"project_id"`
+	DefaultProject       string   `yaml:"default_project"`
+	AllowedProjectIDs    []string `yaml:"allowed_project_ids"`
+	DefaultNamespace     string   `yaml:"default_namespace"`
+	DefaultNamespaces    []string `yaml:"default_namespaces"`
+	ReadOnly             *bool    `yaml:"read_only"`
+	AllowSudo            *bool    `yaml:"allow_sudo"`
+	RateLimit            *float64 `yaml:"rate_limit"`
+	RateLimitBurst       *int     `yaml:"rate_limit_burst"`
+	MaxResponseSize      *int64   `yaml:"max_response_size"`
+	GzipRequestBytes     *int     `yaml:"gzip_request_bytes"`
+	ResponseCacheEntries *int     `yaml:"response_cache_entries"`
+}
+
+// fileFeatures is the "features" section of the config file.
+type fileFeatures struct {
+	Pipeline  *bool `yaml:"pipeline"`
+	Milestone *bool `yaml:"milestone"`
+	Wiki      *bool `yaml:"wiki"`
+}
+
+// fileLogging is the "logging" section of the config file.
+type fileLogging struct {
+	Dir       string `yaml:"dir"`
+	Level     string `yaml:"level"`
+	AuditFile string `yaml:"audit_file"`
+}
+
+// fileMCP is the "mcp" section of the config file.
+type fileMCP struct {
+	ToolsPageSize *int              `yaml:"tools_page_size"`
+	ToolTimeout   string            `yaml:"tool_timeout"`
+	ToolTimeouts  map[string]string `yaml:"tool_timeouts"`
+	MaxConcurrent *int              `yaml:"max_concurrent_tools"`
+	MaxQueued     *int              `yaml:"max_queued_tools"`
+	MaxBytes      *int              `yaml:"max_response_bytes"`
+	MaxTokens     *int              `yaml:"max_response_tokens"`
+	SlowToolCall  string            `yaml:"slow_tool_call"`
+	StatsInterval string            `yaml:"stats_log_interval"`
+}
+
+// fileTools is the "tools" section of the config file.
+type fileTools struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// fileRedaction is the "redaction" section of the config file.
+type fileRedaction struct {
+	Logs     *bool    `yaml:"logs"`
+	Output   *bool    `yaml:"output"`
+	Patterns []string `yaml:"patterns"`
+}
+
+// filePolicy is the "policy" section of the config file.
+type filePolicy struct {
+	Hooks []string `yaml:"hooks"`
+	URL   string   `yaml:"url"`
+}
+
+// fileRoles is the "roles" section of the config file.
+type fileRoles struct {
+	Default    string            `yaml:"default"`
+	Principals map[string]string `yaml:"principals"`
+}
+
+// fileConventions is the "conventions" section of the config file.
+type fileConventions struct {
+	Commit string `yaml:"commit"`
+	Branch string `yaml:"branch"`
+	Title  string `yaml:"title"`
+}
+
+// fileConfig is the layout of the config file. Settings in the selected
+// instance block override the top-level settings.
+type fileConfig struct {
+	fileSettings `yaml:",inline"`
+
+	Instance  string                  `yaml:"instance"`
+	Instances map[string]fileSettings `yaml:"instances"`
+}
+
+// configFile holds the resolved settings of a loaded config file.
+type configFile struct {
+	path       string
+	instance   string
+	values     map[string]string // keyed by the equivalent environment variable
+	token      string
+	allowTools []string
+	denyTools  []string
+	extractors map[string]Extractor
+	redactions []string
+}
+
+// loadConfigFile reads a YAML (or JSON) config file and applies the instance
+// block selected by instance, falling back to the file's own "instance" key.
+func loadConfigFile(path, instance string) (*configFile, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return nil, fmt.Errorf("config file %s: TOML is not supported, use YAML", path)
+	}
+
+	data, err := os.ReadFile(ExpandPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw fileConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&raw); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	file := &configFile{
+		path:       path,
+		values:     make(map[string]string),
+		extractors: make(map[string]Extractor),
+	}
+	file.apply(&raw.fileSettings)
+
+	if instance == "" {
+		instance = raw.Instance
+	}
+	if instance != "" {
+		settings, ok := raw.Instances[instance]
+		if !ok {
+			return nil, fmt.Errorf("config file %s: instance %q not found", path, instance)
+		}
+		file.instance = instance
+		file.apply(&settings)
+	}
+
+	return file, nil
+}
+
+// apply records the settings that are set, overriding earlier values.
+func (f *configFile) apply(s *fileSettings) {
+	set := func(envVar, value string) {
+		if value != "" {
+			f.values[envVar] = value
+		}
+	}
+	setBool := func(envVar string, value *bool) {
+		if value != nil {
+			f.values[envVar] = strconv.FormatBool(*value)
+		}
+	}
+
+	set("GITLAB_API_URL", s.GitLab.APIURL)
+	set("GITLAB_PROJECT_ID", s.GitLab.ProjectID)
+	set("GITLAB_DEFAULT_PROJECT", s.GitLab.DefaultProject)
+	set("GITLAB_ALLOWED_PROJECT_IDS", strings.Join(s.GitLab.AllowedProjectIDs, ","))
+	set("GITLAB_DEFAULT_NAMESPACE", s.GitLab.DefaultNamespace)
+	set("GITLAB_DEFAULT_NAMESPACE", strings.Join(s.GitLab.DefaultNamespaces, ","))
+	setBool("GITLAB_READ_ONLY_MODE", s.GitLab.ReadOnly)
+	setBool("GITLAB_ALLOW_SUDO", s.GitLab.AllowSudo)
+	if s.GitLab.RateLimit != nil {
+		f.values["GITLAB_RATE_LIMIT"] = strconv.FormatFloat(*s.GitLab.RateLimit, 'f', -1, 64)
+	}
+	if s.GitLab.RateLimitBurst != nil {
+		f.values["GITLAB_RATE_LIMIT_BURST"] = strconv.Itoa(*s.GitLab.RateLimitBurst)
+	}
+	if s.GitLab.MaxResponseSize != nil {
+		f.values["GITLAB_MAX_RESPONSE_SIZE"] = strconv.FormatInt(*s.GitLab.MaxResponseSize, 10)
+	}
+	if s.GitLab.GzipRequestBytes != nil {
+		f.values["GITLAB_GZIP_REQUEST_BYTES"] = strconv.Itoa(*s.GitLab.GzipRequestBytes)
+	}
+	if s.GitLab.ResponseCacheEntries != nil {
+		f.values["GITLAB_RESPONSE_CACHE_ENTRIES"] = strconv.Itoa(*s.GitLab.ResponseCacheEntries)
+	}
+	setBool("USE_PIPELINE", s.Features.Pipeline)
+	setBool("USE_MILESTONE", s.Features.Milestone)
+	setBool("USE_GITLAB_WIKI", s.Features.Wiki)
+	setBool("GITLAB_REDACT_LOGS", s.Redaction.Logs)
+	setBool("GITLAB_REDACT_OUTPUT", s.Redaction.Output)
+	set("MCP_LOG_DIR", s.Logging.Dir)
+	set("MCP_LOG_LEVEL", s.Logging.Level)
+	set("MCP_AUDIT_LOG", s.Logging.AuditFile)
+	set("MCP_POLICIES", strings.Join(s.Policy.Hooks, ","))
+	set("MCP_POLICY_URL", s.Policy.URL)
+	set("MCP_DEFAULT_ROLE", s.Roles.Default)
+	set("GITLAB_COMMIT_PATTERN", s.Conventions.Commit)
+	set("GITLAB_BRANCH_PATTERN", s.Conventions.Branch)
+	set("GITLAB_MR_TITLE_PATTERN", s.Conventions.Title)
+	if len(s.Roles.Principals) > 0 {
+		principals := make([]string, 0, len(s.Roles.Principals))
+		for principal := range s.Roles.Principals {
+			principals = append(principals, principal)
+		}
+		sort.Strings(principals)
+		pairs := make([]string, len(principals))
+		for i, principal := range principals {
+			pairs[i] = principal + "=" + s.Roles.Principals[principal]
+		}
+		f.values["MCP_ROLES"] = strings.Join(pairs, ",")
+	}
+	if s.MCP.ToolsPageSize != nil {
+		f.values["MCP_TOOLS_PAGE_SIZE"] = strconv.Itoa(*s.MCP.ToolsPageSize)
+	}
+	if s.MCP.MaxConcurrent != nil {
+		f.values["MCP_MAX_CONCURRENT_TOOLS"] = strconv.Itoa(*s.MCP.MaxConcurrent)
+	}
+	if s.MCP.MaxQueued != nil {
+		f.values["MCP_MAX_QUEUED_TOOLS"] = strconv.Itoa(*s.MCP.MaxQueued)
+	}
+	if s.MCP.MaxBytes != nil {
+		f.values["MCP_MAX_RESPONSE_BYTES"] = strconv.Itoa(*s.MCP.MaxBytes)
+	}
+	if s.MCP.MaxTokens != nil {
+		f.values["MCP_MAX_RESPONSE_TOKENS"] = strconv.Itoa(*s.MCP.MaxTokens)
+	}
+	set("MCP_TOOL_TIMEOUT", s.MCP.ToolTimeout)
+	set("MCP_SLOW_TOOL_CALL", s.MCP.SlowToolCall)
+	set("MCP_STATS_LOG_INTERVAL", s.MCP.StatsInterval)
+	if len(s.MCP.ToolTimeouts) > 0 {
+		names := make([]string, 0, len(s.MCP.ToolTimeouts))
+		for name := range s.MCP.ToolTimeouts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		pairs := make([]string, len(names))
+		for i, name := range names {
+			pairs[i] = name + "=" + s.MCP.ToolTimeouts[name]
+		}
+		f.values["MCP_TOOL_TIMEOUTS"] = strings.Join(pairs, ",")
+	}
+
+	if s.GitLab.Token != "" {
+		f.token = s.GitLab.Token
+	}
+	if s.Tools.Allow != nil {
+		f.allowTools = s.Tools.Allow
+	}
+	if s.Tools.Deny != nil {
+		f.denyTools = s.Tools.Deny
+	}
+	if s.Redaction.Patterns != nil {
+		f.redactions = s.Redaction.Patterns
+	}
+	for name, extractor := range s.Extractors {
+		f.extractors[name] = extractor
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a config file to a temporary directory and returns its path.
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const instancesConfig = `
+instance: staging
+gitlab:
+  api_url: https://gitlab.example.com/api/v4
+  token: top-token
+  read_only: false
+features:
+  pipeline: true
+tools:
+  deny: [delete_issue]
+instances:
+  staging:
+    gitlab:
+      api_url: https://staging.example.com/api/v4
+      read_only: true
+  prod:
+    gitlab:
+      token: prod-token
+    tools:
+      deny: []
+`
+
+func TestLoadConfigFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		instance     string
+		wantInstance string
+		wantValues   map[string]string
+		wantToken    string
+		wantDeny     []string
+	}{
+		{
+			name:    "top level only",
+			content: "gitlab:\n  api_url: https://gitlab.example.com/api/v4\n  allowed_project_ids: [1, group/app]\nmcp:\n  tool_timeouts: {b: 2s, a: 1s}\n",
+			wantValues: map[string]string{
+				"GITLAB_API_URL":             "https://gitlab.example.com/api/v4",
+				"GITLAB_ALLOWED_PROJECT_IDS": "1,group/app",
+				"MCP_TOOL_TIMEOUTS":          "a=1s,b=2s",
+			},
+		},
+		{
+			name:         "instance from the file",
+			content:      instancesConfig,
+			wantInstance: "staging",
+			wantValues: map[string]string{
+				"GITLAB_API_URL":        "https://staging.example.com/api/v4",
+				"GITLAB_READ_ONLY_MODE": "true",
+				"USE_PIPELINE":          "true",
+			},
+			wantToken: "top-token",
+			wantDeny:  []string{"delete_issue"},
+		},
+		{
+			name:         "instance argument overrides the file",
+			content:      instancesConfig,
+			instance:     "prod",
+			wantInstance: "prod",
+			wantValues: map[string]string{
+				"GITLAB_API_URL":        "https://gitlab.example.com/api/v4",
+				"GITLAB_READ_ONLY_MODE": "false",
+				"USE_PIPELINE":          "true",
+			},
+			wantToken: "prod-token",
+			// An explicit empty list clears the top-level one
+			wantDeny: []string{},
+		},
+		{
+			name:       "empty file",
+			content:    "",
+			wantValues: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := loadConfigFile(writeConfig(t, "config.yaml", tt.content), tt.instance)
+			if err != nil {
+				t.Fatalf("loadConfigFile: %v", err)
+			}
+			if file.instance != tt.wantInstance {
+				t.Errorf("instance = %q, want %q", file.instance, tt.wantInstance)
+			}
+			if !reflect.DeepEqual(file.values, tt.wantValues) {
+				t.Errorf("values = %v, want %v", file.values, tt.wantValues)
+			}
+			if file.token != tt.wantToken {
+				t.Errorf("token = %q, want %q", file.token, tt.wantToken)
+			}
+			if !reflect.DeepEqual(file.denyTools, tt.wantDeny) {
+				t.Errorf("denyTools = %#v, want %#v", file.denyTools, tt.wantDeny)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		instance string
+		wantErr  string
+	}{
+		{name: "unknown instance", file: "config.yaml", content: instancesConfig, instance: "qa", wantErr: `instance "qa" not found`},
+		{name: "unknown instance in the file", file: "config.yaml", content: "instance: qa\n", wantErr: `instance "qa" not found`},
+		{name: "unknown key", file: "config.yaml", content: "gitlab:\n  api_ulr: x\n", wantErr: "failed to parse config file"},
+		{name: "toml", file: "config.toml", content: "", wantErr: "TOML is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfigFile(writeConfig(t, tt.file, tt.content), tt.instance)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadConfigFile error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := loadConfigFile(filepath.Join(t.TempDir(), "missing.yaml"), ""); err == nil {
+		t.Error("a missing config file loaded")
+	}
+}
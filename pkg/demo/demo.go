@@ -0,0 +1,138 @@
+// Package demo serves synthetic GitLab data for the -demo flag, so the server
+// can be evaluated and demonstrated without a GitLab connection.
+//
+// The data is a set of embedded cassettes (see package vcr) describing the
+// acme group with its payments-api and storefront projects: issues, merge
+// requests with diffs and discussions, pipelines with a failing job log, and
+// repository files. Responses are deterministic, and changes are rejected.
+package demo
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/vcr"
+)
+
+//go:embed data/*.json
+var data embed.FS
+
+// Interactions returns the demo responses of every embedded cassette.
+func Interactions() ([]vcr.Interaction, error) {
+	files, err := fs.Glob(data, "data/*.json")
+	if err != nil {
+		return nil, err
+	}
+	var interactions []vcr.Interaction
+	for _, file := range files {
+		content, err := data.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		cassette, err := vcr.Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path.Base(file), err)
+		}
+		interactions = append(interactions, cassette...)
+	}
+	return interactions, nil
+}
+
+// Transport answers GitLab requests from the demo data. Projects and groups
+// can be addressed by path as well as by ID, and requests other than GET
+// fail with 403, as the demo is read-only.
+type Transport struct {
+	replayer *vcr.Replayer
+	// aliases maps escaped /projects/<path> and /groups/<path> prefixes to
+	// their numeric form, longest first.
+	aliases [][2]string
+}
+
+// resourcePattern matches the endpoint of a single project or group.
+var resourcePattern = regexp.MustCompile(`^/(projects|groups)/([0-9]+)$`)
+
+// NewTransport returns a Transport for the GitLab API at baseURL.
+func NewTransport(baseURL string) (*Transport, error) {
+	interactions, err := Interactions()
+	if err != nil {
+		return nil, err
+	}
+	replayer, err := vcr.NewReplayer(baseURL, interactions)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Transport{replayer: replayer}
+	for _, interaction := range interactions {
+		m := resourcePattern.FindStringSubmatch(interaction.Endpoint)
+		if m == nil {
+			continue
+		}
+		var resource struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+			FullPath          string `json:"full_path"`
+		}
+		if err := json.Unmarshal(interaction.Body, &resource); err != nil {
+			return nil, fmt.Errorf("demo %s: %w", interaction.Endpoint, err)
+		}
+		fullPath := resource.PathWithNamespace
+		if m[1] == "groups" {
+			fullPath = resource.FullPath
+		}
+		if fullPath != "" {
+			t.aliases = append(t.aliases, [2]string{"/" + m[1] + "/" + url.PathEscape(fullPath), interaction.Endpoint})
+		}
+	}
+	sort.Slice(t.aliases, func(i, j int) bool { return len(t.aliases[i][0]) > len(t.aliases[j][0]) })
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return forbidden(req), nil
+	}
+
+	escaped := req.URL.EscapedPath()
+	for _, alias := range t.aliases {
+		if i := strings.Index(escaped, alias[0]); i >= 0 {
+			rest := escaped[i+len(alias[0]):]
+			if rest == "" || strings.HasPrefix(rest, "/") {
+				req = req.Clone(req.Context())
+				rawPath := escaped[:i] + alias[1] + rest
+				req.URL.RawPath = rawPath
+				req.URL.Path, _ = url.PathUnescape(rawPath)
+				break
+			}
+		}
+	}
+	return t.replayer.RoundTrip(req)
+}
+
+// forbidden is the response to requests that would change GitLab.
+func forbidden(req *http.Request) *http.Response {
+	body := `{"message":"403 Forbidden - demo mode is read-only, no changes are made"}`
+	return &http.Response{
+		Status:        "403 Forbidden",
+		StatusCode:    http.StatusForbidden,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,73 @@
+package demo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+const baseURL = "https://gitlab.example.com/api/v4"
+
+func newClient(t *testing.T) *gitlab.Client {
+	t.Helper()
+	transport, err := NewTransport(baseURL)
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+	return gitlab.NewClient(baseURL, "", gitlab.WithHTTPClient(&http.Client{Transport: transport}))
+}
+
+func TestInteractions(t *testing.T) {
+	interactions, err := Interactions()
+	if err != nil {
+		t.Fatalf("Interactions: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, interaction := range interactions {
+		key := interaction.Method + " " + interaction.Endpoint
+		if seen[key] {
+			t.Errorf("%s is defined twice", key)
+		}
+		seen[key] = true
+		if interaction.Method != http.MethodGet {
+			t.Errorf("%s: demo data must be read-only", key)
+		}
+		if strings.Contains(string(interaction.Body)+interaction.Text, "gitlab.com") {
+			t.Errorf("%s refers to gitlab.com", key)
+		}
+	}
+}
+
+func TestTransport_Aliases(t *testing.T) {
+	client := newClient(t)
+	for _, endpoint := range []string{"/projects/acme%2Fpayments-api", "/projects/42", "/groups/acme"} {
+		var resource map[string]interface{}
+		if err := client.Get(context.Background(), endpoint, &resource); err != nil {
+			t.Errorf("GET %s: %v", endpoint, err)
+		}
+	}
+
+	var mr gitlab.MergeRequest
+	if err := client.Get(context.Background(), "/projects/acme%2Fpayments-api/merge_requests/12", &mr); err != nil || mr.SourceBranch != "retry-authorisation" {
+		t.Errorf("merge request by project path = %+v, %v", mr, err)
+	}
+
+	var apiErr *gitlab.APIError
+	err := client.Get(context.Background(), "/projects/acme%2Fpayments-api-v2", nil)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("a longer path must not match an alias: %v", err)
+	}
+}
+
+func TestTransport_ReadOnly(t *testing.T) {
+	client := newClient(t)
+	err := client.Post(context.Background(), "/projects/42/issues", map[string]string{"title": "x"}, nil)
+	var apiErr *gitlab.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || !strings.Contains(apiErr.Message, "read-only") {
+		t.Errorf("POST in demo mode = %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// API is the set of GitLab requests the tools make. Client implements it
+// against a GitLab server; gitlabtest.Client implements it in memory for
+// tests. Endpoints are relative to the API root (e.g., "/projects/42").
+type API interface {
+	// Get performs a GET request and decodes the JSON response into result.
+	Get(ctx context.Context, endpoint string, result interface{}) error
+	// GetWithPagination is Get for list endpoints, also returning the
+	// pagination headers.
+	GetWithPagination(ctx context.Context, endpoint string, result interface{}) (*PaginationInfo, error)
+	// GetEach is GetWithPagination for large lists, calling fn with each
+	// element as it is decoded instead of decoding the whole array.
+	GetEach(ctx context.Context, endpoint string, fn func(item json.RawMessage) error) (*PaginationInfo, error)
+	// Post performs a POST request with a JSON body.
+	Post(ctx context.Context, endpoint string, body, result interface{}) error
+	// Put performs a PUT request with a JSON body.
+	Put(ctx context.Context, endpoint string, body, result interface{}) error
+	// Patch performs a PATCH request with a JSON body.
+	Patch(ctx context.Context, endpoint string, body, result interface{}) error
+	// Delete performs a DELETE request.
+	Delete(ctx context.Context, endpoint string) error
+	// GetText performs a GET request for a plain text response.
+	GetText(ctx context.Context, endpoint string) (string, error)
+
+	// PostMultipart performs a POST request with a multipart/form-data body.
+	PostMultipart(ctx context.Context, endpoint string, fields map[string]string, file MultipartFile, result interface{}) error
+	// PutFile performs a PUT request with r as the raw body.
+	PutFile(ctx context.Context, endpoint string, r io.Reader, result interface{}) error
+	// Download copies the raw response body of a GET request to w.
+	Download(ctx context.Context, endpoint string, w io.Writer) (int64, http.Header, error)
+	// GetBinary copies the raw response body of a GET request to w, up to
+	// maxBytes (0 means no limit).
+	GetBinary(ctx context.Context, endpoint string, w io.Writer, maxBytes int64) (*BinaryInfo, error)
+	// GetWebBinary is GetBinary for a path on the GitLab web application.
+	GetWebBinary(ctx context.Context, path string, w io.Writer, maxBytes int64) (*BinaryInfo, error)
+
+	// BaseURL returns the API root URL (e.g., "https://gitlab.com/api/v4").
+	BaseURL() string
+	// WebURL returns the root URL of the GitLab web application.
+	WebURL() string
+	// RateLimitStatus returns the rate limit state GitLab last reported.
+	RateLimitStatus() []RateLimitInfo
+	// LimiterBudget returns the client-side limiter state, or nil when
+	// requests are not limited.
+	LimiterBudget() *LimiterBudget
+}
+
+var _ API = (*Client)(nil)
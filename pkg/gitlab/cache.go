@@ -0,0 +1,115 @@
+package gitlab
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+)
+
+// maxCachedBodyBytes is the largest response body kept in the response
+// cache; larger responses are always downloaded.
+const maxCachedBodyBytes = 1 << 20
+
+// WithResponseCache keeps up to entries GET responses that GitLab sent with
+// an ETag and revalidates them with If-None-Match: when GitLab answers 304
+// Not Modified the cached body is decoded instead of downloading it again.
+// Responses are cached per token and Sudo user. 0 disables the cache.
+func WithResponseCache(entries int) ClientOption {
+	return func(c *Client) {
+		c.cache = nil
+		if entries > 0 {
+			c.cache = newResponseCache(entries)
+		}
+	}
+}
+
+// cachedResponse is a decompressed response body and the ETag GitLab sent
+// with it.
+type cachedResponse struct {
+	key        string
+	etag       string
+	body       []byte
+	pagination PaginationInfo
+}
+
+// responseCache is a least recently used cache of GET responses.
+type responseCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *cachedResponse, most recently used first
+	entries map[string]*list.Element
+}
+
+func newResponseCache(size int) *responseCache {
+	return &responseCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// responseCacheKey identifies the response to req made with token. The token
+// is hashed so the cache does not hold copies of it.
+func responseCacheKey(token string, req *http.Request) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8]) + " " + req.Header.Get("Sudo") + " " + req.URL.String()
+}
+
+func (rc *responseCache) get(key string) *cachedResponse {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	elem, ok := rc.entries[key]
+	if !ok {
+		return nil
+	}
+	rc.order.MoveToFront(elem)
+	return elem.Value.(*cachedResponse)
+}
+
+// put stores entry, evicting the least recently used entry when full.
+func (rc *responseCache) put(entry *cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if elem, ok := rc.entries[entry.key]; ok {
+		elem.Value = entry
+		rc.order.MoveToFront(elem)
+		return
+	}
+	rc.entries[entry.key] = rc.order.PushFront(entry)
+	if rc.order.Len() > rc.size {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+func (rc *responseCache) remove(key string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if elem, ok := rc.entries[key]; ok {
+		rc.order.Remove(elem)
+		delete(rc.entries, key)
+	}
+}
+
+// cacheWriter collects a response body as it is decoded, giving up on
+// bodies larger than maxCachedBodyBytes.
+type cacheWriter struct {
+	buf      bytes.Buffer
+	overflow bool
+}
+
+func (w *cacheWriter) Write(p []byte) (int, error) {
+	if w.overflow {
+		return len(p), nil
+	}
+	if w.buf.Len()+len(p) > maxCachedBodyBytes {
+		w.overflow = true
+		w.buf = bytes.Buffer{}
+		return len(p), nil
+	}
+	return w.buf.Write(p)
+}
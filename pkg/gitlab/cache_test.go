@@ -0,0 +1,149 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// etagServer serves each path as a JSON body with an ETag derived from its
+// version, and answers 304 to requests sending the current ETag.
+type etagServer struct {
+	mu       sync.Mutex
+	versions map[string]int
+	// ifNoneMatch is the If-None-Match header of each request, by path
+	ifNoneMatch map[string][]string
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/api/v4")
+	s.ifNoneMatch[path] = append(s.ifNoneMatch[path], r.Header.Get("If-None-Match"))
+	version := s.versions[path]
+	etag := fmt.Sprintf(`W/"%s-%d"`, path, version)
+	if path != "/no-etag" {
+		w.Header().Set("ETag", etag)
+	}
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total", "2")
+	w.Header().Set("X-Next-Page", "2")
+	fmt.Fprintf(w, `[{"name": %q, "version": %d}]`, path, version)
+}
+
+func (s *etagServer) bump(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions[path]++
+}
+
+func TestResponseCache(t *testing.T) {
+	server := &etagServer{versions: map[string]int{}, ifNoneMatch: map[string][]string{}}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+	c := NewClient(srv.URL, "token", WithResponseCache(2))
+	hits := func() int64 { return counterValue(t, "gitlab.cache.requests", attribute.String("result", "hit")) }
+	misses := func() int64 { return counterValue(t, "gitlab.cache.requests", attribute.String("result", "miss")) }
+	beforeHits, beforeMisses := hits(), misses()
+
+	get := func(path string) string {
+		t.Helper()
+		var items []struct {
+			Name    string `json:"name"`
+			Version int    `json:"version"`
+		}
+		pagination, err := c.GetWithPagination(context.Background(), path, &items)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		if len(items) != 1 || pagination.Total != 2 || pagination.NextPage != 2 {
+			t.Fatalf("GET %s = %+v, %+v", path, items, pagination)
+		}
+		return fmt.Sprintf("%s@%d", items[0].Name, items[0].Version)
+	}
+
+	for _, step := range []struct {
+		path, want string
+		bump       bool
+	}{
+		{path: "/a", want: "/a@0"},
+		// Revalidated: GitLab answers 304 and the cached body is decoded
+		{path: "/a", want: "/a@0"},
+		// Changed: the new body replaces the cached one
+		{path: "/a", want: "/a@1", bump: true},
+		{path: "/a", want: "/a@1"},
+		// Responses without an ETag are not cached
+		{path: "/no-etag", want: "/no-etag@0"},
+		{path: "/no-etag", want: "/no-etag@0"},
+		// /b and /c evict /a, the least recently used
+		{path: "/b", want: "/b@0"},
+		{path: "/c", want: "/c@0"},
+		{path: "/a", want: "/a@1"},
+	} {
+		if step.bump {
+			server.bump(step.path)
+		}
+		if got := get(step.path); got != step.want {
+			t.Errorf("GET %s = %s, want %s", step.path, got, step.want)
+		}
+	}
+
+	want := map[string][]string{
+		"/a":       {"", `W/"/a-0"`, `W/"/a-0"`, `W/"/a-1"`, ""},
+		"/no-etag": {"", ""},
+		"/b":       {""},
+		"/c":       {""},
+	}
+	for path, headers := range want {
+		if got := server.ifNoneMatch[path]; strings.Join(got, " ") != strings.Join(headers, " ") {
+			t.Errorf("If-None-Match sent for %s = %q, want %q", path, got, headers)
+		}
+	}
+	if got := hits() - beforeHits; got != 2 {
+		t.Errorf("cache hits = %d, want 2", got)
+	}
+	if got := misses() - beforeMisses; got != 7 {
+		t.Errorf("cache misses = %d, want 7", got)
+	}
+}
+
+func TestResponseCacheKey(t *testing.T) {
+	server := &etagServer{versions: map[string]int{}, ifNoneMatch: map[string][]string{}}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+	c := NewClient(srv.URL, "alice-token", WithResponseCache(10))
+	ctx := context.Background()
+
+	c.Get(ctx, "/a", nil)
+	// Another token, or acting as another user, does not see the response
+	c.SetToken("bob-token")
+	c.Get(ctx, "/a", nil)
+	c.Get(WithSudo(ctx, "carol"), "/a", nil)
+	c.SetToken("alice-token")
+	c.Get(ctx, "/a", nil)
+	// Only GET responses are cached
+	c.Post(ctx, "/a", nil, nil)
+
+	if got, want := server.ifNoneMatch["/a"], []string{"", "", "", `W/"/a-0"`, ""}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("If-None-Match sent = %q, want %q", got, want)
+	}
+
+	// Without the cache nothing is revalidated
+	server.ifNoneMatch = map[string][]string{}
+	uncached := NewClient(srv.URL, "alice-token")
+	uncached.Get(ctx, "/a", nil)
+	uncached.Get(ctx, "/a", nil)
+	if got := server.ifNoneMatch["/a"]; got[0] != "" || got[1] != "" {
+		t.Errorf("If-None-Match sent without a cache = %q", got)
+	}
+}
@@ -2,12 +2,15 @@ package gitlab
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -27,29 +30,48 @@ type HTTPResponseInfo struct {
 }
 
 // Logger defines the interface for logging API calls.
+// Each method receives the request context so implementations can correlate
+// log lines with the originating MCP request.
 type Logger interface {
-	Access(method, endpoint string, statusCode int, duration time.Duration)
-	Debug(msg string, args ...any)
-	Error(msg string, args ...any)
+	// Access logs a completed request with the sizes of its bodies
+	Access(ctx context.Context, method, endpoint string, statusCode int, duration time.Duration, sizes TransferSizes)
+	Debug(ctx context.Context, msg string, args ...any)
+	Error(ctx context.Context, msg string, args ...any)
 	// LogHTTPRequest logs detailed HTTP request information at DEBUG level
-	LogHTTPRequest(context string, req *HTTPRequestInfo, secrets ...string)
+	LogHTTPRequest(ctx context.Context, logContext string, req *HTTPRequestInfo, secrets ...string)
 	// LogHTTPResponse logs detailed HTTP response information at DEBUG level
-	LogHTTPResponse(context string, resp *HTTPResponseInfo, duration time.Duration, secrets ...string)
+	LogHTTPResponse(ctx context.Context, logContext string, resp *HTTPResponseInfo, duration time.Duration, secrets ...string)
 	// LogHTTPError logs detailed HTTP error information
-	LogHTTPError(context string, req *HTTPRequestInfo, resp *HTTPResponseInfo, err error, secrets ...string)
+	LogHTTPError(ctx context.Context, logContext string, req *HTTPRequestInfo, resp *HTTPResponseInfo, err error, secrets ...string)
 }
 
 // TokenProvider is a function that returns the current token to use.
 // This allows for dynamic token resolution (e.g., from request headers).
 type TokenProvider func() string
 
+// RequestIDProvider returns the correlation ID for the request carried by ctx.
+// A non-empty ID is sent to GitLab in the X-Request-Id header.
+type RequestIDProvider func(ctx context.Context) string
+
 // Client is an HTTP client wrapper for the GitLab API.
 type Client struct {
 	baseURL       string
 	token         string
+	tokenMu       sync.RWMutex
 	tokenProvider TokenProvider
+	requestID     RequestIDProvider
 	httpClient    *http.Client
 	logger        Logger
+	limiter       *limiter
+	// maxResponseSize caps JSON and text response bodies (0 = no limit)
+	maxResponseSize int64
+	// compressMin is the smallest JSON request body gzipped (0 = never)
+	compressMin int
+	// cache revalidates GET responses with their ETag (nil = no cache)
+	cache *responseCache
+
+	rateLimitMu sync.Mutex
+	rateLimits  map[string]RateLimitInfo
 }
 
 // ClientOption is a function that configures a Client.
@@ -77,6 +99,14 @@ func WithTokenProvider(provider TokenProvider) ClientOption {
 	}
 }
 
+// WithRequestIDProvider sets the provider used to tag outbound requests
+// with the X-Request-Id header.
+func WithRequestIDProvider(provider RequestIDProvider) ClientOption {
+	return func(c *Client) {
+		c.requestID = provider
+	}
+}
+
 // NewClient creates a new GitLab API client.
 func NewClient(baseURL, token string, opts ...ClientOption) *Client {
 	// Ensure baseURL doesn't have trailing slash
@@ -93,7 +123,8 @@ func NewClient(baseURL, token string, opts ...ClientOption) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		logger: &noopLogger{},
+		logger:          &noopLogger{},
+		maxResponseSize: DefaultMaxResponseSize,
 	}
 
 	for _, opt := range opts {
@@ -112,39 +143,71 @@ func (c *Client) getToken() string {
 			return token
 		}
 	}
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
 	return c.token
 }
 
+// SetToken replaces the default token, e.g. after the configuration is
+// reloaded. Requests already in flight keep the token they started with.
+func (c *Client) SetToken(token string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	c.token = token
+}
+
+// setRequestID copies the correlation ID for ctx onto the outbound request, if any.
+func (c *Client) setRequestID(ctx context.Context, req *http.Request) {
+	if c.requestID == nil {
+		return
+	}
+	if id := c.requestID(ctx); id != "" {
+		req.Header.Set("X-Request-Id", id)
+	}
+}
+
 // Get performs an HTTP GET request to the specified endpoint.
-func (c *Client) Get(endpoint string, result interface{}) error {
-	return c.request(http.MethodGet, endpoint, nil, result)
+func (c *Client) Get(ctx context.Context, endpoint string, result interface{}) error {
+	return c.request(ctx, http.MethodGet, endpoint, nil, result)
 }
 
 // GetWithPagination performs an HTTP GET request and returns pagination info.
-func (c *Client) GetWithPagination(endpoint string, result interface{}) (*PaginationInfo, error) {
-	return c.requestWithPagination(http.MethodGet, endpoint, nil, result)
+func (c *Client) GetWithPagination(ctx context.Context, endpoint string, result interface{}) (*PaginationInfo, error) {
+	return c.requestWithPagination(ctx, http.MethodGet, endpoint, nil, decodeInto(result))
 }
 
 // Post performs an HTTP POST request to the specified endpoint.
-func (c *Client) Post(endpoint string, body, result interface{}) error {
-	return c.request(http.MethodPost, endpoint, body, result)
+func (c *Client) Post(ctx context.Context, endpoint string, body, result interface{}) error {
+	return c.request(ctx, http.MethodPost, endpoint, body, result)
 }
 
 // Put performs an HTTP PUT request to the specified endpoint.
-func (c *Client) Put(endpoint string, body, result interface{}) error {
-	return c.request(http.MethodPut, endpoint, body, result)
+func (c *Client) Put(ctx context.Context, endpoint string, body, result interface{}) error {
+	return c.request(ctx, http.MethodPut, endpoint, body, result)
+}
+
+// Patch performs an HTTP PATCH request to the specified endpoint.
+func (c *Client) Patch(ctx context.Context, endpoint string, body, result interface{}) error {
+	return c.request(ctx, http.MethodPatch, endpoint, body, result)
 }
 
 // Delete performs an HTTP DELETE request to the specified endpoint.
-func (c *Client) Delete(endpoint string) error {
-	return c.request(http.MethodDelete, endpoint, nil, nil)
+func (c *Client) Delete(ctx context.Context, endpoint string) error {
+	return c.request(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 // GetText performs an HTTP GET request and returns the response as plain text.
 // This is used for endpoints that return text/plain content (e.g., job logs).
-func (c *Client) GetText(endpoint string) (string, error) {
+func (c *Client) GetText(ctx context.Context, endpoint string) (text string, err error) {
 	start := time.Now()
 
+	ctx, span := startAPISpan(ctx, http.MethodGet, endpoint)
+	statusCode := 0
+	defer func() {
+		endAPISpan(ctx, span, http.MethodGet, statusCode, time.Since(start), err)
+		recordResponse(ctx, http.MethodGet, endpoint, statusCode)
+	}()
+
 	// Build the full URL
 	url := c.buildURL(endpoint)
 
@@ -152,7 +215,7 @@ func (c *Client) GetText(endpoint string) (string, error) {
 	token := c.getToken()
 
 	// Create the request
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -160,9 +223,12 @@ func (c *Client) GetText(endpoint string) (string, error) {
 	// Set headers
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "text/plain")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	c.setRequestID(ctx, req)
+	setSudo(ctx, req)
 
 	// Log request at DEBUG level (token will be masked)
-	c.logger.LogHTTPRequest("api_request_text", &HTTPRequestInfo{
+	c.logger.LogHTTPRequest(ctx, "api_request_text", &HTTPRequestInfo{
 		Method: http.MethodGet,
 		URL:    url,
 		Headers: map[string]string{
@@ -171,10 +237,19 @@ func (c *Client) GetText(endpoint string) (string, error) {
 		},
 	}, token)
 
+	// Wait for the client-side limiter, if configured
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return "", fmt.Errorf("rate limiter: %w", err)
+		}
+		// Durations cover GitLab, not the wait for the limiter
+		start = time.Now()
+	}
+
 	// Execute the request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		c.logger.LogHTTPError("http_request_text", &HTTPRequestInfo{
+		c.logger.LogHTTPError(ctx, "http_request_text", &HTTPRequestInfo{
 			Method: http.MethodGet,
 			URL:    url,
 			Headers: map[string]string{
@@ -182,31 +257,41 @@ func (c *Client) GetText(endpoint string) (string, error) {
 				"Accept":        "text/plain",
 			},
 		}, nil, err, token)
-		c.logger.Error("request failed", "method", http.MethodGet, "endpoint", endpoint, "error", err)
+		c.logger.Error(ctx, "request failed", "method", http.MethodGet, "endpoint", endpoint, "error", err)
 		return "", fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	statusCode = resp.StatusCode
+	c.recordRateLimit(endpoint, resp.Header)
+	transfer := decodeResponse(resp)
 
 	duration := time.Since(start)
 
-	// Read the response body
-	respBody, err := io.ReadAll(resp.Body)
+	// Read the response body, up to the size limit
+	body, err := c.limitBody(resp)
+	if err != nil {
+		c.logger.Access(ctx, http.MethodGet, endpoint, resp.StatusCode, duration, transfer.sizes(0, 0))
+		return "", err
+	}
+	respBody, err := io.ReadAll(body)
+	c.logger.Access(ctx, http.MethodGet, endpoint, resp.StatusCode, duration, transfer.sizes(0, 0))
+	if errors.Is(err, ErrResponseTooLarge) {
+		return "", err
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// Log response at DEBUG level (body summary for text content)
-	c.logger.LogHTTPResponse("api_response_text", &HTTPResponseInfo{
+	c.logger.LogHTTPResponse(ctx, "api_response_text", &HTTPResponseInfo{
 		StatusCode: resp.StatusCode,
 		Headers:    convertHeaders(resp.Header),
 		Body:       string(respBody),
 	}, duration, token)
 
-	c.logger.Access(http.MethodGet, endpoint, resp.StatusCode, duration)
-
 	// Check for errors
 	if resp.StatusCode >= 400 {
-		c.logger.LogHTTPError("api_error_text", &HTTPRequestInfo{
+		c.logger.LogHTTPError(ctx, "api_error_text", &HTTPRequestInfo{
 			Method: http.MethodGet,
 			URL:    url,
 		}, &HTTPResponseInfo{
@@ -214,22 +299,30 @@ func (c *Client) GetText(endpoint string) (string, error) {
 			Headers:    convertHeaders(resp.Header),
 			Body:       string(respBody),
 		}, nil, token)
-		return "", c.handleErrorResponse(resp.StatusCode, endpoint, respBody)
+		return "", c.handleErrorResponse(resp, endpoint, respBody)
 	}
 
 	return string(respBody), nil
 }
 
-// request performs an HTTP request and decodes the response.
-func (c *Client) request(method, endpoint string, body interface{}, result interface{}) error {
-	_, err := c.requestWithPagination(method, endpoint, body, result)
+// request performs an HTTP request and decodes the JSON response into result.
+func (c *Client) request(ctx context.Context, method, endpoint string, body interface{}, result interface{}) error {
+	_, err := c.requestWithPagination(ctx, method, endpoint, body, decodeInto(result))
 	return err
 }
 
-// requestWithPagination performs an HTTP request and returns pagination info.
-func (c *Client) requestWithPagination(method, endpoint string, body interface{}, result interface{}) (*PaginationInfo, error) {
+// requestWithPagination performs an HTTP request, streams a successful
+// response body to decode and returns pagination info.
+func (c *Client) requestWithPagination(ctx context.Context, method, endpoint string, body interface{}, decode func(io.Reader) error) (pagination *PaginationInfo, err error) {
 	start := time.Now()
 
+	ctx, span := startAPISpan(ctx, method, endpoint)
+	statusCode := 0
+	defer func() {
+		endAPISpan(ctx, span, method, statusCode, time.Since(start), err)
+		recordResponse(ctx, method, endpoint, statusCode)
+	}()
+
 	// Build the full URL
 	url := c.buildURL(endpoint)
 
@@ -238,18 +331,22 @@ func (c *Client) requestWithPagination(method, endpoint string, body interface{}
 
 	// Prepare the request body
 	var bodyReader io.Reader
-	var bodyStr string
+	var bodyStr, contentEncoding string
+	var sent, sentRaw int64
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		bodyStr = string(jsonBody)
-		bodyReader = bytes.NewReader(jsonBody)
+		var wireBody []byte
+		wireBody, contentEncoding = c.compressBody(jsonBody)
+		sent, sentRaw = int64(len(wireBody)), int64(len(jsonBody))
+		bodyReader = bytes.NewReader(wireBody)
 	}
 
 	// Create the request
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -258,9 +355,25 @@ func (c *Client) requestWithPagination(method, endpoint string, body interface{}
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	c.setRequestID(ctx, req)
+	setSudo(ctx, req)
+
+	// Revalidate a cached response rather than downloading it again
+	var cacheKey string
+	var cached *cachedResponse
+	if c.cache != nil && method == http.MethodGet {
+		cacheKey = responseCacheKey(token, req)
+		if cached = c.cache.get(cacheKey); cached != nil {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	}
 
 	// Log request at DEBUG level (token will be masked)
-	c.logger.LogHTTPRequest("api_request", &HTTPRequestInfo{
+	c.logger.LogHTTPRequest(ctx, "api_request", &HTTPRequestInfo{
 		Method: method,
 		URL:    url,
 		Headers: map[string]string{
@@ -271,10 +384,19 @@ func (c *Client) requestWithPagination(method, endpoint string, body interface{}
 		Body: bodyStr,
 	}, token)
 
+	// Wait for the client-side limiter, if configured
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter: %w", err)
+		}
+		// Durations cover GitLab, not the wait for the limiter
+		start = time.Now()
+	}
+
 	// Execute the request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		c.logger.LogHTTPError("http_request", &HTTPRequestInfo{
+		c.logger.LogHTTPError(ctx, "http_request", &HTTPRequestInfo{
 			Method: method,
 			URL:    url,
 			Headers: map[string]string{
@@ -283,31 +405,29 @@ func (c *Client) requestWithPagination(method, endpoint string, body interface{}
 			},
 			Body: bodyStr,
 		}, nil, err, token)
-		c.logger.Error("request failed", "method", method, "endpoint", endpoint, "error", err)
+		c.logger.Error(ctx, "request failed", "method", method, "endpoint", endpoint, "error", err)
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	statusCode = resp.StatusCode
+	c.recordRateLimit(endpoint, resp.Header)
+	transfer := decodeResponse(resp)
 
 	duration := time.Since(start)
 
-	// Read the response body
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Log response at DEBUG level
-	c.logger.LogHTTPResponse("api_response", &HTTPResponseInfo{
-		StatusCode: resp.StatusCode,
-		Headers:    convertHeaders(resp.Header),
-		Body:       string(respBody),
-	}, duration, token)
-
-	c.logger.Access(method, endpoint, resp.StatusCode, duration)
-
 	// Check for errors
 	if resp.StatusCode >= 400 {
-		c.logger.LogHTTPError("api_error", &HTTPRequestInfo{
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		c.logger.LogHTTPResponse(ctx, "api_response", &HTTPResponseInfo{
+			StatusCode: resp.StatusCode,
+			Headers:    convertHeaders(resp.Header),
+			Body:       string(respBody),
+		}, duration, token)
+		c.logger.Access(ctx, method, endpoint, resp.StatusCode, duration, transfer.sizes(sent, sentRaw))
+		c.logger.LogHTTPError(ctx, "api_error", &HTTPRequestInfo{
 			Method: method,
 			URL:    url,
 			Body:   bodyStr,
@@ -316,21 +436,68 @@ func (c *Client) requestWithPagination(method, endpoint string, body interface{}
 			Headers:    convertHeaders(resp.Header),
 			Body:       string(respBody),
 		}, nil, token)
-		return nil, c.handleErrorResponse(resp.StatusCode, endpoint, respBody)
+		return nil, c.handleErrorResponse(resp, endpoint, respBody)
 	}
 
 	// Parse pagination headers
-	pagination := c.parsePaginationHeaders(resp.Header)
-
-	// Decode the response
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			c.logger.Debug("failed to unmarshal response", "body", string(respBody), "error", err)
-			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	pagination = c.parsePaginationHeaders(resp.Header)
+
+	// Decode the response as it arrives, up to the size limit; only its
+	// start is kept for the debug log
+	var respBody io.Reader
+	var store *cacheWriter
+	switch {
+	case cached != nil && resp.StatusCode == http.StatusNotModified:
+		// GitLab confirmed the cached body is current
+		recordCacheRequest(ctx, true)
+		respBody = bytes.NewReader(cached.body)
+		p := cached.pagination
+		pagination = &p
+	default:
+		respBody, err = c.limitBody(resp)
+		if cacheKey != "" {
+			recordCacheRequest(ctx, false)
+			if resp.Header.Get("ETag") != "" && resp.StatusCode == http.StatusOK {
+				store = &cacheWriter{}
+				respBody = io.TeeReader(respBody, store)
+			} else {
+				c.cache.remove(cacheKey)
+			}
 		}
 	}
-
-	return pagination, nil
+	if err == nil {
+		head := &headWriter{}
+		err = decode(io.TeeReader(respBody, head))
+		if err == nil && store != nil && !store.overflow {
+			c.cache.put(&cachedResponse{
+				key:        cacheKey,
+				etag:       resp.Header.Get("ETag"),
+				body:       store.buf.Bytes(),
+				pagination: *pagination,
+			})
+		}
+		c.logger.LogHTTPResponse(ctx, "api_response", &HTTPResponseInfo{
+			StatusCode: resp.StatusCode,
+			Headers:    convertHeaders(resp.Header),
+			Body:       string(head.head),
+		}, duration, token)
+		if err != nil && !errors.Is(err, ErrResponseTooLarge) {
+			c.logger.Debug(ctx, "failed to unmarshal response", "body", string(head.head), "error", err)
+		}
+	}
+	c.logger.Access(ctx, method, endpoint, resp.StatusCode, duration, transfer.sizes(sent, sentRaw))
+
+	var callbackErr *callbackError
+	switch {
+	case err == nil:
+		return pagination, nil
+	case errors.Is(err, ErrResponseTooLarge):
+		return nil, err
+	case errors.As(err, &callbackErr):
+		return nil, callbackErr.err
+	default:
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
 }
 
 // buildURL constructs the full URL for an API endpoint.
@@ -343,12 +510,18 @@ func (c *Client) buildURL(endpoint string) string {
 }
 
 // handleErrorResponse creates an APIError from an error response.
-func (c *Client) handleErrorResponse(statusCode int, endpoint string, body []byte) *APIError {
+func (c *Client) handleErrorResponse(resp *http.Response, endpoint string, body []byte) *APIError {
+	statusCode := resp.StatusCode
 	apiErr := &APIError{
 		StatusCode: statusCode,
 		Endpoint:   endpoint,
 	}
 
+	// Retry-After is sent by GitLab on 429 responses, in seconds
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
 	// Try to parse the error message from the response
 	var errResp struct {
 		Message string   `json:"message"`
@@ -420,11 +593,13 @@ func (c *Client) BaseURL() string {
 // noopLogger is a no-op implementation of the Logger interface.
 type noopLogger struct{}
 
-func (l *noopLogger) Access(method, endpoint string, statusCode int, duration time.Duration)    {}
-func (l *noopLogger) Debug(msg string, args ...any)                                             {}
-func (l *noopLogger) Error(msg string, args ...any)                                             {}
-func (l *noopLogger) LogHTTPRequest(context string, req *HTTPRequestInfo, secrets ...string)    {}
-func (l *noopLogger) LogHTTPResponse(context string, resp *HTTPResponseInfo, duration time.Duration, secrets ...string) {
+func (l *noopLogger) Access(ctx context.Context, method, endpoint string, statusCode int, duration time.Duration, sizes TransferSizes) {
+}
+func (l *noopLogger) Debug(ctx context.Context, msg string, args ...any) {}
+func (l *noopLogger) Error(ctx context.Context, msg string, args ...any) {}
+func (l *noopLogger) LogHTTPRequest(ctx context.Context, logContext string, req *HTTPRequestInfo, secrets ...string) {
+}
+func (l *noopLogger) LogHTTPResponse(ctx context.Context, logContext string, resp *HTTPResponseInfo, duration time.Duration, secrets ...string) {
 }
-func (l *noopLogger) LogHTTPError(context string, req *HTTPRequestInfo, resp *HTTPResponseInfo, err error, secrets ...string) {
+func (l *noopLogger) LogHTTPError(ctx context.Context, logContext string, req *HTTPRequestInfo, resp *HTTPResponseInfo, err error, secrets ...string) {
 }
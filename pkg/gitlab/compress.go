@@ -0,0 +1,202 @@
+package gitlab
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// acceptEncoding is sent with every request. The client decompresses
+// responses itself, rather than leaving it to http.Transport, so it can
+// count the bytes received on the wire.
+const acceptEncoding = "gzip, deflate"
+
+// TransferSizes are the body sizes of one GitLab request, in bytes.
+type TransferSizes struct {
+	// Sent is the request body as sent, SentRaw before compression.
+	Sent, SentRaw int64
+	// Received is the response body as received, ReceivedRaw after
+	// decompression.
+	Received, ReceivedRaw int64
+	// Encoding is the Content-Encoding of the response, "" if none.
+	Encoding string
+}
+
+// String renders the sizes for the access log, e.g.
+// "sent=120 received=5120 received_raw=48213 encoding=gzip". Raw sizes are
+// only included for compressed bodies.
+func (t TransferSizes) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "sent=%d", t.Sent)
+	if t.SentRaw != t.Sent {
+		fmt.Fprintf(&sb, " sent_raw=%d", t.SentRaw)
+	}
+	fmt.Fprintf(&sb, " received=%d", t.Received)
+	if t.Encoding != "" {
+		fmt.Fprintf(&sb, " received_raw=%d encoding=%s", t.ReceivedRaw, t.Encoding)
+	}
+	return sb.String()
+}
+
+// WithRequestCompression gzips JSON request bodies of at least minBytes
+// (e.g. push_files with large file contents) and sends them with
+// Content-Encoding: gzip. Only enable it when GitLab, or a proxy in front of
+// it, decodes compressed request bodies. 0 disables compression.
+func WithRequestCompression(minBytes int) ClientOption {
+	return func(c *Client) {
+		c.compressMin = minBytes
+	}
+}
+
+// gzipWriters and gzipReaders are reused across requests, as their
+// compression state takes a few hundred kilobytes to allocate.
+var (
+	gzipWriters = sync.Pool{New: func() interface{} {
+		// Request bodies are compressed on the hot path: BestSpeed gets most
+		// of the size reduction on JSON for a fraction of the CPU time
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.BestSpeed)
+		return w
+	}}
+	gzipReaders sync.Pool
+)
+
+// compressBody returns the request body to send for a JSON body, and the
+// Content-Encoding to send it with ("" if uncompressed).
+func (c *Client) compressBody(body []byte) ([]byte, string) {
+	if c.compressMin <= 0 || len(body) < c.compressMin {
+		return body, ""
+	}
+	var buf bytes.Buffer
+	w := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(w)
+	w.Reset(&buf)
+	if _, err := w.Write(body); err != nil {
+		return body, ""
+	}
+	if err := w.Close(); err != nil {
+		return body, ""
+	}
+	if buf.Len() >= len(body) {
+		return body, ""
+	}
+	return buf.Bytes(), "gzip"
+}
+
+// responseBody decompresses a response body according to its
+// Content-Encoding and counts the bytes read before and after decompression.
+type responseBody struct {
+	body     io.ReadCloser
+	wire     countingReader
+	encoding string
+	reader   io.Reader
+	gzip     *gzip.Reader
+	decoded  int64
+	// onClose, if set, is called once when the body is closed
+	onClose func()
+}
+
+// decodeResponse replaces the body of resp with a decompressing responseBody
+// and returns it. The Content-Encoding header is removed, and the
+// Content-Length no longer applies to the decompressed body.
+func decodeResponse(resp *http.Response) *responseBody {
+	b := &responseBody{body: resp.Body, wire: countingReader{r: resp.Body}}
+	switch encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); encoding {
+	case "gzip", "x-gzip", "deflate":
+		b.encoding = encoding
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	resp.Body = b
+	return b
+}
+
+func (b *responseBody) Read(p []byte) (int, error) {
+	if b.reader == nil {
+		if err := b.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := b.reader.Read(p)
+	b.decoded += int64(n)
+	return n, err
+}
+
+// open creates the decompressing reader on the first Read, so empty bodies
+// (HEAD requests, 204 responses) never need a gzip header.
+func (b *responseBody) open() error {
+	switch b.encoding {
+	case "gzip", "x-gzip":
+		zr, _ := gzipReaders.Get().(*gzip.Reader)
+		var err error
+		if zr == nil {
+			zr, err = gzip.NewReader(&b.wire)
+		} else {
+			err = zr.Reset(&b.wire)
+		}
+		if err == io.EOF {
+			b.reader = &b.wire
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decompress response: %w", err)
+		}
+		b.gzip, b.reader = zr, zr
+	case "deflate":
+		zr, err := zlib.NewReader(&b.wire)
+		// zlib reports an empty body as unexpected
+		if err == io.EOF || (err == io.ErrUnexpectedEOF && b.wire.n == 0) {
+			b.reader = &b.wire
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decompress response: %w", err)
+		}
+		b.reader = zr
+	default:
+		b.reader = &b.wire
+	}
+	return nil
+}
+
+func (b *responseBody) Close() error {
+	if b.gzip != nil {
+		gzipReaders.Put(b.gzip)
+		b.gzip = nil
+	}
+	if b.onClose != nil {
+		b.onClose()
+		b.onClose = nil
+	}
+	return b.body.Close()
+}
+
+// sizes returns the transfer sizes of the response read so far, with the
+// request body sizes sent and sentRaw.
+func (b *responseBody) sizes(sent, sentRaw int64) TransferSizes {
+	return TransferSizes{
+		Sent:        sent,
+		SentRaw:     sentRaw,
+		Received:    b.wire.n,
+		ReceivedRaw: b.decoded,
+		Encoding:    b.encoding,
+	}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
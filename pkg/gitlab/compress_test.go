@@ -0,0 +1,220 @@
+package gitlab
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(data))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func deflated(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(data))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// encodedResponse returns a response with the given body and Content-Encoding.
+func encodedResponse(encoding string, body []byte) *http.Response {
+	resp := &http.Response{
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+	if encoding != "" {
+		resp.Header.Set("Content-Encoding", encoding)
+	}
+	return resp
+}
+
+func TestDecodeResponse(t *testing.T) {
+	payload := strings.Repeat(`{"id": 1, "name": "project"},`, 100)
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		want     string
+		wantEnc  string
+	}{
+		{name: "identity", body: []byte(payload), want: payload},
+		{name: "gzip", encoding: "gzip", body: gzipped(t, payload), want: payload, wantEnc: "gzip"},
+		{name: "x-gzip", encoding: "x-gzip", body: gzipped(t, payload), want: payload, wantEnc: "x-gzip"},
+		{name: "header case and spaces", encoding: " GZIP ", body: gzipped(t, payload), want: payload, wantEnc: "gzip"},
+		{name: "deflate", encoding: "deflate", body: deflated(t, payload), want: payload, wantEnc: "deflate"},
+		{name: "empty gzip body", encoding: "gzip", wantEnc: "gzip"},
+		{name: "empty deflate body", encoding: "deflate", wantEnc: "deflate"},
+		// Left for the caller, like any other unknown encoding
+		{name: "unsupported encoding", encoding: "br", body: []byte("raw"), want: "raw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := encodedResponse(tt.encoding, tt.body)
+			b := decodeResponse(resp)
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			resp.Body.Close()
+			if string(data) != tt.want {
+				t.Errorf("body = %.40q, want %.40q", data, tt.want)
+			}
+
+			sizes := b.sizes(0, 0)
+			if sizes.Encoding != tt.wantEnc || sizes.Received != int64(len(tt.body)) || sizes.ReceivedRaw != int64(len(tt.want)) {
+				t.Errorf("sizes = %+v, want %s with %d bytes received and %d decoded", sizes, tt.wantEnc, len(tt.body), len(tt.want))
+			}
+			if tt.wantEnc != "" && (resp.Header.Get("Content-Encoding") != "" || resp.ContentLength != -1 || !resp.Uncompressed) {
+				t.Errorf("decoded response still describes the compressed body: %v, length %d", resp.Header, resp.ContentLength)
+			}
+		})
+	}
+}
+
+func TestDecodeResponseCorrupt(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		resp := encodedResponse(encoding, []byte("not compressed"))
+		decodeResponse(resp)
+		if _, err := io.ReadAll(resp.Body); err == nil || !strings.Contains(err.Error(), "failed to decompress response") {
+			t.Errorf("%s: read error = %v, want a decompression failure", encoding, err)
+		}
+	}
+}
+
+func TestDecodeResponseReusesGzipReaders(t *testing.T) {
+	// Each body is read after the previous one was closed and its reader
+	// returned to the pool; a reader that kept state would corrupt the next
+	for i, payload := range []string{strings.Repeat("a", 5000), "short", strings.Repeat("b", 70000)} {
+		resp := encodedResponse("gzip", gzipped(t, payload))
+		b := decodeResponse(resp)
+		data, err := io.ReadAll(resp.Body)
+		if err != nil || string(data) != payload {
+			t.Fatalf("body %d = %d bytes, %v, want %d bytes", i, len(data), err, len(payload))
+		}
+		if b.gzip == nil {
+			t.Fatalf("body %d was not read with a pooled gzip reader", i)
+		}
+		resp.Body.Close()
+		if b.gzip != nil {
+			t.Fatalf("body %d kept its gzip reader after Close", i)
+		}
+		// Closing twice must not put the reader in the pool twice
+		resp.Body.Close()
+	}
+}
+
+func TestResponseBodyOnClose(t *testing.T) {
+	resp := encodedResponse("", []byte("{}"))
+	b := decodeResponse(resp)
+	var closed int
+	b.onClose = func() { closed++ }
+	resp.Body.Close()
+	resp.Body.Close()
+	if closed != 1 {
+		t.Errorf("onClose called %d times, want once", closed)
+	}
+}
+
+func TestCompressBody(t *testing.T) {
+	large := []byte(`{"content": "` + strings.Repeat("line of a file\n", 200) + `"}`)
+	tests := []struct {
+		name         string
+		compressMin  int
+		body         []byte
+		wantEncoding string
+	}{
+		{name: "disabled", compressMin: 0, body: large},
+		{name: "below the threshold", compressMin: len(large) + 1, body: large},
+		{name: "at the threshold", compressMin: len(large), body: large, wantEncoding: "gzip"},
+		// Random-looking data grows when compressed, so it is sent as is
+		{name: "incompressible", compressMin: 1, body: []byte(`{"a":"x"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{compressMin: tt.compressMin}
+			out, encoding := c.compressBody(tt.body)
+			if encoding != tt.wantEncoding {
+				t.Fatalf("encoding = %q, want %q", encoding, tt.wantEncoding)
+			}
+			if encoding == "" {
+				if !bytes.Equal(out, tt.body) {
+					t.Error("uncompressed body changed")
+				}
+				return
+			}
+			if len(out) >= len(tt.body) {
+				t.Errorf("compressed to %d bytes from %d", len(out), len(tt.body))
+			}
+			zr, err := gzip.NewReader(bytes.NewReader(out))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data, _ := io.ReadAll(zr); !bytes.Equal(data, tt.body) {
+				t.Error("compressed body does not decompress to the original")
+			}
+		})
+	}
+}
+
+func TestRequestCompression(t *testing.T) {
+	var encoding string
+	var received []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		body := io.Reader(r.Body)
+		if encoding == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = zr
+		}
+		received, _ = io.ReadAll(body)
+		// The response is compressed as well
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Type", "application/json")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(`{"id": 1}`))
+		zw.Close()
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "token", WithRequestCompression(1024))
+
+	var result struct {
+		ID int `json:"id"`
+	}
+	content := strings.Repeat("line of a file\n", 200)
+	if err := c.Post(context.Background(), "/projects/1/repository/commits", map[string]string{"content": content}, &result); err != nil {
+		t.Fatal(err)
+	}
+	if encoding != "gzip" || !strings.Contains(string(received), "line of a file") || result.ID != 1 {
+		t.Errorf("large body sent with encoding %q, decoded %d bytes, result %+v", encoding, len(received), result)
+	}
+
+	if err := c.Post(context.Background(), "/projects/1/issues", map[string]string{"title": "Bug"}, &result); err != nil {
+		t.Fatal(err)
+	}
+	if encoding != "" || string(received) != `{"title":"Bug"}` {
+		t.Errorf("small body sent with encoding %q as %q", encoding, received)
+	}
+}
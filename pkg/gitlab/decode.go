@@ -0,0 +1,143 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxResponseSize is the response size limit of a Client created
+// without WithMaxResponseSize.
+const DefaultMaxResponseSize = 64 << 20
+
+// debugBodyBytes is how much of a successful response body is kept for the
+// debug log; the body itself is decoded as it arrives.
+const debugBodyBytes = 4 << 10
+
+// ErrStop can be returned by the callback of GetEach to stop reading the
+// list without an error.
+var ErrStop = errors.New("stop iteration")
+
+// WithMaxResponseSize limits the JSON and text responses the client reads to
+// maxBytes, after decompression; larger responses fail with
+// ErrResponseTooLarge instead of exhausting memory. 0 disables the limit.
+// Downloads with GetBinary have their own limit.
+func WithMaxResponseSize(maxBytes int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseSize = maxBytes
+	}
+}
+
+// GetEach performs an HTTP GET request for a JSON array and calls fn with each
+// element as it is decoded, so large lists (commits, jobs) are never held in
+// memory as a whole. If fn returns ErrStop, the rest of the response is
+// skipped and GetEach returns the pagination info without an error; any other
+// error is returned as is.
+func (c *Client) GetEach(ctx context.Context, endpoint string, fn func(item json.RawMessage) error) (*PaginationInfo, error) {
+	return c.requestWithPagination(ctx, http.MethodGet, endpoint, nil, func(r io.Reader) error {
+		return decodeEach(r, fn)
+	})
+}
+
+// decodeInto returns a decoder of a JSON response into result. A nil result
+// discards the response.
+func decodeInto(result interface{}) func(io.Reader) error {
+	return func(r io.Reader) error {
+		if result == nil {
+			_, err := io.Copy(io.Discard, r)
+			return err
+		}
+		err := json.NewDecoder(r).Decode(result)
+		if err == io.EOF {
+			// An empty body leaves result unchanged
+			return nil
+		}
+		return err
+	}
+}
+
+// decodeEach calls fn with each element of the JSON array read from r.
+func decodeEach(r io.Reader, fn func(item json.RawMessage) error) error {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a JSON array, got %v", token)
+	}
+	for decoder.More() {
+		var item json.RawMessage
+		if err := decoder.Decode(&item); err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return &callbackError{err: err}
+		}
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// callbackError carries an error returned by the callback of GetEach, which
+// is passed on unwrapped rather than reported as a decoding failure.
+type callbackError struct {
+	err error
+}
+
+func (e *callbackError) Error() string { return e.err.Error() }
+func (e *callbackError) Unwrap() error { return e.err }
+
+// limitBody returns the body of resp limited to the client's maximum
+// response size. A Content-Length above the limit fails without reading.
+func (c *Client) limitBody(resp *http.Response) (io.Reader, error) {
+	if c.maxResponseSize <= 0 {
+		return resp.Body, nil
+	}
+	if resp.ContentLength > c.maxResponseSize {
+		return nil, fmt.Errorf("%w: %d bytes (limit %d)", ErrResponseTooLarge, resp.ContentLength, c.maxResponseSize)
+	}
+	return &limitedReader{r: resp.Body, remaining: c.maxResponseSize, limit: c.maxResponseSize}, nil
+}
+
+// limitedReader reads up to limit bytes from r and fails with
+// ErrResponseTooLarge if r holds more.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	limit     int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	// Read one byte past the limit to tell "exactly limit" from "more"
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, fmt.Errorf("%w: limit %d bytes", ErrResponseTooLarge, l.limit)
+	}
+	return n, err
+}
+
+// headWriter keeps the first bytes written to it, for the debug log.
+type headWriter struct {
+	head []byte
+}
+
+func (h *headWriter) Write(p []byte) (int, error) {
+	if missing := debugBodyBytes - len(h.head); missing > 0 {
+		h.head = append(h.head, p[:min(missing, len(p))]...)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,94 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLimitBody(t *testing.T) {
+	const limit = 16
+	tests := []struct {
+		name          string
+		size          int
+		contentLength int64
+		wantErr       bool
+	}{
+		{name: "under the limit", size: limit - 1, contentLength: limit - 1},
+		{name: "exactly the limit", size: limit, contentLength: limit},
+		{name: "exactly the limit without Content-Length", size: limit, contentLength: -1},
+		{name: "one byte over", size: limit + 1, contentLength: -1, wantErr: true},
+		{name: "far over", size: 10 * limit, contentLength: -1, wantErr: true},
+		// A wrong Content-Length does not get past the reader
+		{name: "over with a short Content-Length", size: limit + 1, contentLength: limit, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{maxResponseSize: limit}
+			body := strings.Repeat("x", tt.size)
+			resp := &http.Response{Body: io.NopCloser(strings.NewReader(body)), ContentLength: tt.contentLength}
+
+			r, err := c.limitBody(resp)
+			if err != nil {
+				t.Fatalf("limitBody: %v", err)
+			}
+			data, err := io.ReadAll(r)
+			if tt.wantErr {
+				if !errors.Is(err, ErrResponseTooLarge) {
+					t.Errorf("read error = %v, want ErrResponseTooLarge", err)
+				}
+				if len(data) > limit+1 {
+					t.Errorf("read %d bytes, want at most %d", len(data), limit+1)
+				}
+				return
+			}
+			if err != nil || string(data) != body {
+				t.Errorf("read %d bytes with error %v, want the whole body", len(data), err)
+			}
+		})
+	}
+}
+
+func TestLimitBodyContentLength(t *testing.T) {
+	c := &Client{maxResponseSize: 16}
+	body := &countingReader{r: strings.NewReader(strings.Repeat("x", 17))}
+	_, err := c.limitBody(&http.Response{Body: io.NopCloser(body), ContentLength: 17})
+	if !errors.Is(err, ErrResponseTooLarge) || !strings.Contains(err.Error(), "17 bytes (limit 16)") {
+		t.Errorf("limitBody error = %v, want ErrResponseTooLarge naming the sizes", err)
+	}
+	if body.n != 0 {
+		t.Errorf("read %d bytes of a response known to be too large", body.n)
+	}
+}
+
+func TestLimitBodyUnlimited(t *testing.T) {
+	c := &Client{}
+	body := io.NopCloser(strings.NewReader("x"))
+	if r, err := c.limitBody(&http.Response{Body: body, ContentLength: 1 << 40}); err != nil || r != body {
+		t.Errorf("limitBody without a limit = %v, %v, want the body itself", r, err)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	const limit = 32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		// Chunked, so only the reader can enforce the limit
+		w.(http.Flusher).Flush()
+		w.Write([]byte(strings.Repeat("x", size)))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "token", WithMaxResponseSize(limit))
+
+	if text, err := c.GetText(context.Background(), "/log?size=32"); err != nil || len(text) != limit {
+		t.Errorf("GetText of exactly the limit = %d bytes, %v", len(text), err)
+	}
+	if _, err := c.GetText(context.Background(), "/log?size=33"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("GetText of limit+1 bytes = %v, want ErrResponseTooLarge", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // APIError represents an error returned by the GitLab API.
@@ -11,6 +12,8 @@ type APIError struct {
 	StatusCode int    `json:"status_code"`
 	Message    string `json:"message"`
 	Endpoint   string `json:"endpoint"`
+	// RetryAfter is the delay requested by GitLab's Retry-After header, if any.
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
 }
 
 // Error implements the error interface.
@@ -62,3 +65,35 @@ func IsServerError(err error) bool {
 	}
 	return false
 }
+
+// Retryable reports whether repeating the same request may succeed later
+// (rate limiting, timeouts and transient server errors).
+func (e *APIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return e.StatusCode >= 500 && e.StatusCode < 600
+}
+
+// Hint returns a short suggestion for resolving the error, or "" if none applies.
+func (e *APIError) Hint() string {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return "The GitLab token is missing, invalid or expired. Check GITLAB_PERSONAL_ACCESS_TOKEN or the X-GitLab-Token header."
+	case e.StatusCode == http.StatusForbidden:
+		return "The token is valid but lacks permission for this action. Check the token scopes (api/read_api) and your role in the project."
+	case e.StatusCode == http.StatusNotFound:
+		return "The resource does not exist or is not visible to this token. Check project_id, IIDs and ref names."
+	case e.StatusCode == http.StatusConflict:
+		return "The request conflicts with the current state of the resource (e.g., it already exists or was modified concurrently)."
+	case e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity:
+		return "GitLab rejected the parameters. Check gitlab_message for the offending field."
+	case e.StatusCode == http.StatusTooManyRequests:
+		return "Rate limited by GitLab. Wait before retrying (see retry_after_seconds when present)."
+	case e.StatusCode >= 500:
+		return "GitLab returned a server error. The request can usually be retried after a short delay."
+	}
+	return ""
+}
@@ -0,0 +1,451 @@
+// Package gitlabtest provides an in-memory gitlab.API for unit-testing tool
+// handlers without a GitLab server, and helpers for fixture and golden files.
+//
+// A test registers canned responses, either in code with Handle or from a
+// fixture file with LoadFixtures, passes the client to the code under test,
+// and checks the result and the recorded Requests:
+//
+//	client := gitlabtest.NewClient()
+//	client.Handle(http.MethodGet, "/projects/42", http.StatusOK, project)
+//	tc := tools.NewToolContext(client, nil, cfg)
+//
+// Server serves the same routes over HTTP for end-to-end tests that use the
+// real gitlab.Client.
+package gitlabtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+// DefaultBaseURL is the API root reported by a new Client.
+const DefaultBaseURL = "https://gitlab.example.com/api/v4"
+
+// Route is a canned response to one request. Endpoint is matched exactly,
+// query string included; a route without a query string also matches requests
+// to the same path with any query string, when no exact route exists.
+type Route struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	// Status is the HTTP status code; 0 means 200. Statuses of 400 and above
+	// are returned as a *gitlab.APIError with the message from Body.
+	Status int `json:"status,omitempty"`
+	// Body is the JSON response body.
+	Body json.RawMessage `json:"body,omitempty"`
+	// Text is the raw response body of text and binary requests (GetText,
+	// Download, GetBinary); Body is used when it is empty.
+	Text string `json:"text,omitempty"`
+	// Headers are the response headers returned by Download.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Pagination is returned by GetWithPagination.
+	Pagination *gitlab.PaginationInfo `json:"pagination,omitempty"`
+}
+
+// Request is a request received by the Client.
+type Request struct {
+	Method   string
+	Endpoint string
+	// Body is the JSON encoding of the request body, or the raw body of
+	// PutFile and the file of PostMultipart.
+	Body []byte
+	// Fields are the form fields of PostMultipart.
+	Fields map[string]string
+	// Encoding is the Content-Encoding the body was sent with; Body is
+	// decompressed.
+	Encoding string
+	// Sudo is the user the request was made as (see gitlab.WithSudo).
+	Sudo string
+}
+
+// Client is an in-memory gitlab.API. Requests without a route fail with a
+// 404 *gitlab.APIError. It is safe for concurrent use.
+type Client struct {
+	mu         sync.Mutex
+	baseURL    string
+	routes     map[string]Route
+	requests   []Request
+	rateLimits []gitlab.RateLimitInfo
+}
+
+var _ gitlab.API = (*Client)(nil)
+
+// NewClient returns a Client without routes whose BaseURL is DefaultBaseURL.
+func NewClient() *Client {
+	return &Client{baseURL: DefaultBaseURL, routes: map[string]Route{}}
+}
+
+// SetBaseURL changes the API root reported by BaseURL and WebURL.
+func (c *Client) SetBaseURL(baseURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.baseURL = baseURL
+}
+
+// SetRateLimitStatus sets the result of RateLimitStatus.
+func (c *Client) SetRateLimitStatus(status []gitlab.RateLimitInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rateLimits = status
+}
+
+// Handle adds a route answering method and endpoint with status and body.
+// A string or []byte body is used as is, anything else is encoded as JSON.
+// It panics if body cannot be encoded.
+func (c *Client) Handle(method, endpoint string, status int, body interface{}) {
+	route := Route{Method: method, Endpoint: endpoint, Status: status}
+	switch b := body.(type) {
+	case nil:
+	case string:
+		route.Text = b
+		if json.Valid([]byte(b)) {
+			route.Body = json.RawMessage(b)
+		}
+	case []byte:
+		route.Text = string(b)
+		if json.Valid(b) {
+			route.Body = json.RawMessage(b)
+		}
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			panic(fmt.Sprintf("gitlabtest: cannot encode body for %s %s: %v", method, endpoint, err))
+		}
+		route.Body = data
+	}
+	c.AddRoute(route)
+}
+
+// AddRoute adds a route, replacing any route for the same method and endpoint.
+func (c *Client) AddRoute(route Route) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	route.Method = strings.ToUpper(route.Method)
+	if route.Method == "" {
+		route.Method = http.MethodGet
+	}
+	c.routes[route.Method+" "+route.Endpoint] = route
+}
+
+// Requests returns the requests received so far, in order.
+func (c *Client) Requests() []Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Request(nil), c.requests...)
+}
+
+// Unmatched returns the "METHOD endpoint" of received requests that had no
+// route, sorted, so a test can report every missing fixture at once.
+func (c *Client) Unmatched() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	seen := map[string]bool{}
+	var unmatched []string
+	for _, req := range c.requests {
+		key := req.Method + " " + req.Endpoint
+		if _, ok := c.match(req.Method, req.Endpoint); !ok && !seen[key] {
+			seen[key] = true
+			unmatched = append(unmatched, key)
+		}
+	}
+	sort.Strings(unmatched)
+	return unmatched
+}
+
+// match finds the route for a request. c.mu must be held.
+func (c *Client) match(method, endpoint string) (Route, bool) {
+	if route, ok := c.routes[method+" "+endpoint]; ok {
+		return route, true
+	}
+	if path, _, found := strings.Cut(endpoint, "?"); found {
+		route, ok := c.routes[method+" "+path]
+		return route, ok
+	}
+	return Route{}, false
+}
+
+// serve records a request and returns its route, or the error the route (or
+// its absence) stands for.
+func (c *Client) serve(ctx context.Context, req Request) (Route, error) {
+	if err := ctx.Err(); err != nil {
+		return Route{}, err
+	}
+	if req.Sudo == "" {
+		req.Sudo, _ = gitlab.SudoFromContext(ctx)
+	}
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	route, ok := c.match(req.Method, req.Endpoint)
+	c.mu.Unlock()
+
+	if !ok {
+		return Route{}, &gitlab.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("gitlabtest: no route for %s %s", req.Method, req.Endpoint),
+			Endpoint:   req.Endpoint,
+		}
+	}
+	if route.Status >= http.StatusBadRequest {
+		return Route{}, &gitlab.APIError{
+			StatusCode: route.Status,
+			Message:    errorMessage(route),
+			Endpoint:   req.Endpoint,
+		}
+	}
+	return route, nil
+}
+
+// errorMessage extracts the message of an error route the way GitLab error
+// bodies carry it: {"message": ...} or {"error": ...}.
+func errorMessage(route Route) string {
+	var body struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+	}
+	if json.Unmarshal(route.Body, &body) == nil {
+		switch {
+		case body.Message != nil:
+			if s, ok := body.Message.(string); ok {
+				return s
+			}
+			data, _ := json.Marshal(body.Message)
+			return string(data)
+		case body.Error != "":
+			return body.Error
+		}
+	}
+	if route.Text != "" {
+		return route.Text
+	}
+	return http.StatusText(route.Status)
+}
+
+// decode unmarshals the route body into result, if both are set.
+func decode(route Route, result interface{}) error {
+	if result == nil || len(route.Body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(route.Body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// rawBody returns the body of text and binary responses.
+func rawBody(route Route) []byte {
+	if route.Text != "" {
+		return []byte(route.Text)
+	}
+	return route.Body
+}
+
+// encodeBody returns the JSON encoding of a request body.
+func encodeBody(body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	return data, nil
+}
+
+// Get implements gitlab.API.
+func (c *Client) Get(ctx context.Context, endpoint string, result interface{}) error {
+	route, err := c.serve(ctx, Request{Method: http.MethodGet, Endpoint: endpoint})
+	if err != nil {
+		return err
+	}
+	return decode(route, result)
+}
+
+// GetWithPagination implements gitlab.API. Routes without Pagination report a
+// single page.
+func (c *Client) GetWithPagination(ctx context.Context, endpoint string, result interface{}) (*gitlab.PaginationInfo, error) {
+	route, err := c.serve(ctx, Request{Method: http.MethodGet, Endpoint: endpoint})
+	if err != nil {
+		return nil, err
+	}
+	if err := decode(route, result); err != nil {
+		return nil, err
+	}
+	if route.Pagination != nil {
+		pagination := *route.Pagination
+		return &pagination, nil
+	}
+	return &gitlab.PaginationInfo{Page: 1, TotalPages: 1}, nil
+}
+
+// GetEach implements gitlab.API.
+func (c *Client) GetEach(ctx context.Context, endpoint string, fn func(item json.RawMessage) error) (*gitlab.PaginationInfo, error) {
+	var items []json.RawMessage
+	pagination, err := c.GetWithPagination(ctx, endpoint, &items)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if err := fn(item); err != nil {
+			if errors.Is(err, gitlab.ErrStop) {
+				break
+			}
+			return nil, err
+		}
+	}
+	return pagination, nil
+}
+
+// Post implements gitlab.API.
+func (c *Client) Post(ctx context.Context, endpoint string, body, result interface{}) error {
+	return c.send(ctx, http.MethodPost, endpoint, body, result)
+}
+
+// Put implements gitlab.API.
+func (c *Client) Put(ctx context.Context, endpoint string, body, result interface{}) error {
+	return c.send(ctx, http.MethodPut, endpoint, body, result)
+}
+
+// Patch implements gitlab.API.
+func (c *Client) Patch(ctx context.Context, endpoint string, body, result interface{}) error {
+	return c.send(ctx, http.MethodPatch, endpoint, body, result)
+}
+
+// Delete implements gitlab.API.
+func (c *Client) Delete(ctx context.Context, endpoint string) error {
+	_, err := c.serve(ctx, Request{Method: http.MethodDelete, Endpoint: endpoint})
+	return err
+}
+
+// send serves a request with a JSON body.
+func (c *Client) send(ctx context.Context, method, endpoint string, body, result interface{}) error {
+	data, err := encodeBody(body)
+	if err != nil {
+		return err
+	}
+	route, err := c.serve(ctx, Request{Method: method, Endpoint: endpoint, Body: data})
+	if err != nil {
+		return err
+	}
+	return decode(route, result)
+}
+
+// GetText implements gitlab.API.
+func (c *Client) GetText(ctx context.Context, endpoint string) (string, error) {
+	route, err := c.serve(ctx, Request{Method: http.MethodGet, Endpoint: endpoint})
+	if err != nil {
+		return "", err
+	}
+	return string(rawBody(route)), nil
+}
+
+// PostMultipart implements gitlab.API. The file content is read and recorded
+// as the request body.
+func (c *Client) PostMultipart(ctx context.Context, endpoint string, fields map[string]string, file gitlab.MultipartFile, result interface{}) error {
+	var data []byte
+	if file.Reader != nil {
+		var err error
+		if data, err = io.ReadAll(file.Reader); err != nil {
+			return err
+		}
+	}
+	route, err := c.serve(ctx, Request{Method: http.MethodPost, Endpoint: endpoint, Body: data, Fields: fields})
+	if err != nil {
+		return err
+	}
+	return decode(route, result)
+}
+
+// PutFile implements gitlab.API.
+func (c *Client) PutFile(ctx context.Context, endpoint string, r io.Reader, result interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	route, err := c.serve(ctx, Request{Method: http.MethodPut, Endpoint: endpoint, Body: data})
+	if err != nil {
+		return err
+	}
+	return decode(route, result)
+}
+
+// Download implements gitlab.API.
+func (c *Client) Download(ctx context.Context, endpoint string, w io.Writer) (int64, http.Header, error) {
+	route, err := c.serve(ctx, Request{Method: http.MethodGet, Endpoint: endpoint})
+	if err != nil {
+		return 0, nil, err
+	}
+	header := http.Header{}
+	for name, value := range route.Headers {
+		header.Set(name, value)
+	}
+	written, err := io.Copy(w, bytes.NewReader(rawBody(route)))
+	return written, header, err
+}
+
+// GetBinary implements gitlab.API. The content type is taken from the
+// Content-Type route header, or detected from the body.
+func (c *Client) GetBinary(ctx context.Context, endpoint string, w io.Writer, maxBytes int64) (*gitlab.BinaryInfo, error) {
+	route, err := c.serve(ctx, Request{Method: http.MethodGet, Endpoint: endpoint})
+	if err != nil {
+		return nil, err
+	}
+	return writeBinary(route, w, maxBytes)
+}
+
+// GetWebBinary implements gitlab.API. Routes for web paths use the path as
+// their endpoint.
+func (c *Client) GetWebBinary(ctx context.Context, path string, w io.Writer, maxBytes int64) (*gitlab.BinaryInfo, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return c.GetBinary(ctx, path, w, maxBytes)
+}
+
+// writeBinary copies a binary route body to w for GetBinary.
+func writeBinary(route Route, w io.Writer, maxBytes int64) (*gitlab.BinaryInfo, error) {
+	body := rawBody(route)
+	if maxBytes > 0 && int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes (limit %d)", gitlab.ErrResponseTooLarge, len(body), maxBytes)
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	info := &gitlab.BinaryInfo{Size: int64(len(body)), ContentType: route.Headers["Content-Type"]}
+	if info.ContentType == "" {
+		info.ContentType, _, _ = strings.Cut(http.DetectContentType(body), ";")
+	}
+	return info, nil
+}
+
+// BaseURL implements gitlab.API.
+func (c *Client) BaseURL() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.baseURL
+}
+
+// WebURL implements gitlab.API.
+func (c *Client) WebURL() string {
+	return strings.TrimSuffix(c.BaseURL(), "/api/v4")
+}
+
+// RateLimitStatus implements gitlab.API.
+func (c *Client) RateLimitStatus() []gitlab.RateLimitInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]gitlab.RateLimitInfo{}, c.rateLimits...)
+}
+
+// LimiterBudget implements gitlab.API. The fake client has no limiter.
+func (c *Client) LimiterBudget() *gitlab.LimiterBudget {
+	return nil
+}
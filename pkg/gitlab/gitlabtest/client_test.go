@@ -0,0 +1,111 @@
+package gitlabtest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+func TestClient_Routes(t *testing.T) {
+	client := NewClient()
+	client.Handle(http.MethodGet, "/projects/42", http.StatusOK, gitlab.Project{ID: 42, Name: "api"})
+	client.Handle(http.MethodGet, "/projects/42/issues", http.StatusOK, `[{"iid": 1}, {"iid": 2}]`)
+	client.Handle(http.MethodGet, "/projects/7", http.StatusForbidden, `{"message": "403 Forbidden"}`)
+	ctx := context.Background()
+
+	var project gitlab.Project
+	if err := client.Get(ctx, "/projects/42", &project); err != nil || project.Name != "api" {
+		t.Fatalf("Get() = %+v, %v", project, err)
+	}
+
+	// A route without a query string matches any query string
+	var issues []gitlab.Issue
+	pagination, err := client.GetWithPagination(ctx, "/projects/42/issues?state=opened&page=1", &issues)
+	if err != nil || len(issues) != 2 || pagination.TotalPages != 1 {
+		t.Fatalf("GetWithPagination() = %+v, %+v, %v", issues, pagination, err)
+	}
+
+	err = client.Get(ctx, "/projects/7", nil)
+	if !gitlab.IsForbidden(err) {
+		t.Errorf("Get() error = %v, want 403", err)
+	}
+	if err := client.Delete(ctx, "/projects/42"); !gitlab.IsNotFound(err) {
+		t.Errorf("Delete() without a route error = %v, want 404", err)
+	}
+	if got := client.Unmatched(); len(got) != 1 || got[0] != "DELETE /projects/42" {
+		t.Errorf("Unmatched() = %v", got)
+	}
+	if got := len(client.Requests()); got != 4 {
+		t.Errorf("Requests() has %d entries, want 4", got)
+	}
+}
+
+func TestClient_RecordsBodies(t *testing.T) {
+	client := NewClient()
+	client.Handle(http.MethodPost, "/projects/42/issues", http.StatusCreated, gitlab.Issue{IID: 3})
+	client.Handle(http.MethodPut, "/projects/42/packages/generic/app/1.0/app.tgz", http.StatusCreated, nil)
+	ctx := context.Background()
+
+	var issue gitlab.Issue
+	if err := client.Post(ctx, "/projects/42/issues", map[string]string{"title": "Bug"}, &issue); err != nil || issue.IID != 3 {
+		t.Fatalf("Post() = %+v, %v", issue, err)
+	}
+	if err := client.PutFile(ctx, "/projects/42/packages/generic/app/1.0/app.tgz", bytes.NewReader([]byte("data")), nil); err != nil {
+		t.Fatalf("PutFile() error = %v", err)
+	}
+
+	requests := client.Requests()
+	if string(requests[0].Body) != `{"title":"Bug"}` {
+		t.Errorf("Post body = %s", requests[0].Body)
+	}
+	if string(requests[1].Body) != "data" {
+		t.Errorf("PutFile body = %s", requests[1].Body)
+	}
+}
+
+func TestClient_Binary(t *testing.T) {
+	client := NewClient()
+	client.Handle(http.MethodGet, "/projects/42/jobs/1/trace", http.StatusOK, "line 1\nline 2\n")
+	client.Handle(http.MethodGet, "/projects/42/repository/files/logo.png/raw", http.StatusOK, []byte("\x89PNG\r\n\x1a\nrest"))
+	ctx := context.Background()
+
+	if text, err := client.GetText(ctx, "/projects/42/jobs/1/trace"); err != nil || text != "line 1\nline 2\n" {
+		t.Errorf("GetText() = %q, %v", text, err)
+	}
+
+	var buf bytes.Buffer
+	info, err := client.GetBinary(ctx, "/projects/42/repository/files/logo.png/raw", &buf, 0)
+	if err != nil || info.ContentType != "image/png" || info.Size != 12 {
+		t.Errorf("GetBinary() = %+v, %v", info, err)
+	}
+	if _, err := client.GetBinary(ctx, "/projects/42/repository/files/logo.png/raw", &buf, 4); err == nil {
+		t.Error("GetBinary() ignored maxBytes")
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	dir := t.TempDir()
+	fixture := `[{"method": "get", "endpoint": "/version", "body": {"version": "17.0.0"}}]`
+	if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(fixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient()
+	client.MustLoadFixtures(t, dir)
+	var version gitlab.Version
+	if err := client.Get(context.Background(), "/version", &version); err != nil || version.Version != "17.0.0" {
+		t.Errorf("Get() = %+v, %v", version, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewClient().LoadFixtures(dir); err == nil {
+		t.Error("LoadFixtures() accepted an invalid file")
+	}
+}
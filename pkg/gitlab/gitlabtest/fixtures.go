@@ -0,0 +1,81 @@
+package gitlabtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden write
+// the golden files instead of comparing against them:
+//
+//	UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// LoadFixtures adds the routes of a fixture file: a JSON array of Route
+// objects, e.g.
+//
+//	[{"method": "GET", "endpoint": "/projects/42", "body": {"id": 42}}]
+//
+// If path is a directory, every *.json file in it is loaded in name order.
+func (c *Client) LoadFixtures(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var routes []Route
+		if err := json.Unmarshal(data, &routes); err != nil {
+			return fmt.Errorf("invalid fixture file %s: %w", file, err)
+		}
+		for _, route := range routes {
+			c.AddRoute(route)
+		}
+	}
+	return nil
+}
+
+// MustLoadFixtures is LoadFixtures failing the test on error.
+func (c *Client) MustLoadFixtures(t testing.TB, path string) {
+	t.Helper()
+	if err := c.LoadFixtures(path); err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+}
+
+// AssertGolden compares got with the content of the golden file at path,
+// ignoring a trailing newline. With UPDATE_GOLDEN set, it writes got to the
+// file instead.
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+	got = append(bytes.TrimRight(got, "\n"), '\n')
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with %s=1 to create it): %v", UpdateGoldenEnv, err)
+	}
+	if !bytes.Equal(bytes.TrimRight(want, "\n"), bytes.TrimRight(got, "\n")) {
+		t.Errorf("result differs from %s (run with %s=1 to update):\ngot:\n%s\nwant:\n%s", path, UpdateGoldenEnv, got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package gitlabtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+// DefaultToken is the token a Server created by NewServer accepts.
+const DefaultToken = "glpat-gitlabtest"
+
+// Server is a fake GitLab API served over HTTP, for end-to-end tests that
+// exercise the real gitlab.Client. It answers from the routes of its embedded
+// Client, so routes, fixtures, Requests and Unmatched work as for Client.
+//
+// Unlike Client, Server paginates: a route without Pagination whose body is
+// a JSON array is split into pages by the page and per_page query parameters
+// (default 1 and 20), with GitLab's X-Page, X-Total and related headers.
+// Requests without the token's Authorization header get a 401. Like GitLab,
+// the server gzips JSON responses for clients accepting gzip, and accepts
+// gzipped request bodies.
+type Server struct {
+	*Client
+	httpServer *httptest.Server
+	token      string
+}
+
+// NewServer starts a Server accepting DefaultToken. It is closed when the
+// test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{Client: NewClient(), token: DefaultToken}
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.Client.SetBaseURL(s.httpServer.URL + "/api/v4")
+	t.Cleanup(s.httpServer.Close)
+	return s
+}
+
+// URL returns the API root of the server (e.g., http://127.0.0.1:1234/api/v4).
+func (s *Server) URL() string {
+	return s.Client.BaseURL()
+}
+
+// Token returns the token the server accepts.
+func (s *Server) Token() string {
+	return s.token
+}
+
+// NewGitLabClient returns a gitlab.Client for the server.
+func (s *Server) NewGitLabClient(opts ...gitlab.ClientOption) *gitlab.Client {
+	return gitlab.NewClient(s.URL(), s.token, opts...)
+}
+
+// serveHTTP answers an API request from the routes.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.token && r.Header.Get("PRIVATE-TOKEN") != s.token {
+		writeJSON(w, http.StatusUnauthorized, nil, []byte(`{"message":"401 Unauthorized"}`))
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.EscapedPath(), "/api/v4")
+	if !ok {
+		// Web application paths (GetWebBinary) are routed by their path
+		path = r.URL.EscapedPath()
+	}
+	endpoint := path
+	if r.URL.RawQuery != "" {
+		endpoint += "?" + r.URL.RawQuery
+	}
+
+	req := Request{Method: r.Method, Endpoint: endpoint, Sudo: r.Header.Get("Sudo")}
+	if err := readRequestBody(r, &req); err != nil {
+		message, _ := json.Marshal(map[string]string{"message": "400 Bad request - " + err.Error()})
+		writeJSON(w, http.StatusBadRequest, nil, message)
+		return
+	}
+
+	route, err := s.Client.serve(r.Context(), req)
+	var apiErr *gitlab.APIError
+	if errors.As(err, &apiErr) {
+		message, _ := json.Marshal(map[string]string{"message": apiErr.Message})
+		writeJSON(w, apiErr.StatusCode, route.Headers, message)
+		return
+	} else if err != nil {
+		return
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := w.Header()
+	for name, value := range route.Headers {
+		header.Set(name, value)
+	}
+	if len(route.Body) == 0 {
+		if header.Get("Content-Type") == "" && route.Text != "" {
+			header.Set("Content-Type", "text/plain")
+		}
+		w.WriteHeader(status)
+		w.Write(rawBody(route))
+		return
+	}
+
+	body := []byte(route.Body)
+	if route.Pagination != nil {
+		setPaginationHeaders(header, *route.Pagination)
+	} else if r.Method == http.MethodGet && bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		body = paginate(header, r, body)
+	}
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(body)
+		zw.Close()
+		header.Set("Content-Encoding", "gzip")
+		body = buf.Bytes()
+	}
+	writeJSON(w, status, nil, body)
+}
+
+// readRequestBody records the JSON, multipart or raw body of r in req.
+func readRequestBody(r *http.Request, req *Request) error {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return err
+		}
+		req.Fields = map[string]string{}
+		for name, values := range r.MultipartForm.Value {
+			req.Fields[name] = values[0]
+		}
+		for _, files := range r.MultipartForm.File {
+			file, err := files[0].Open()
+			if err != nil {
+				return err
+			}
+			req.Body, err = io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	reader := io.Reader(r.Body)
+	if encoding := r.Header.Get("Content-Encoding"); encoding != "" {
+		if encoding != "gzip" {
+			return fmt.Errorf("unsupported Content-Encoding %s", encoding)
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		req.Encoding, reader = encoding, zr
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	if len(body) > 0 {
+		req.Body = body
+	}
+	return nil
+}
+
+// paginate returns the requested page of a JSON array body and sets the
+// pagination headers.
+func paginate(header http.Header, r *http.Request, body []byte) []byte {
+	var items []json.RawMessage
+	if json.Unmarshal(body, &items) != nil {
+		return body
+	}
+	page, perPage := queryInt(r, "page", 1), queryInt(r, "per_page", 20)
+	total := len(items)
+	totalPages := (total + perPage - 1) / perPage
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	info := gitlab.PaginationInfo{Page: page, PerPage: perPage, Total: total, TotalPages: totalPages}
+	if page < totalPages {
+		info.NextPage = page + 1
+	}
+	if page > 1 {
+		info.PrevPage = page - 1
+	}
+	setPaginationHeaders(header, info)
+
+	pageBody, _ := json.Marshal(append([]json.RawMessage{}, items[start:end]...))
+	return pageBody
+}
+
+// queryInt returns a positive integer query parameter, or def.
+func queryInt(r *http.Request, name string, def int) int {
+	if n, err := strconv.Atoi(r.URL.Query().Get(name)); err == nil && n > 0 {
+		return n
+	}
+	return def
+}
+
+// setPaginationHeaders sets the pagination headers GitLab sends with lists.
+func setPaginationHeaders(header http.Header, info gitlab.PaginationInfo) {
+	header.Set("X-Page", strconv.Itoa(info.Page))
+	header.Set("X-Per-Page", strconv.Itoa(info.PerPage))
+	header.Set("X-Total", strconv.Itoa(info.Total))
+	header.Set("X-Total-Pages", strconv.Itoa(info.TotalPages))
+	if info.NextPage > 0 {
+		header.Set("X-Next-Page", strconv.Itoa(info.NextPage))
+	}
+	if info.PrevPage > 0 {
+		header.Set("X-Prev-Page", strconv.Itoa(info.PrevPage))
+	}
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, headers map[string]string, body []byte) {
+	for name, value := range headers {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
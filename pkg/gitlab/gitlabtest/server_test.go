@@ -0,0 +1,161 @@
+package gitlabtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+func TestServer_Pagination(t *testing.T) {
+	server := NewServer(t)
+	server.Handle(http.MethodGet, "/projects/42/issues", http.StatusOK, `[{"iid": 1}, {"iid": 2}, {"iid": 3}]`)
+	client := server.NewGitLabClient()
+
+	var issues []gitlab.Issue
+	pagination, err := client.GetWithPagination(context.Background(), "/projects/42/issues?page=2&per_page=2", &issues)
+	if err != nil {
+		t.Fatalf("GetWithPagination() error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].IID != 3 {
+		t.Errorf("issues = %+v", issues)
+	}
+	if pagination.Page != 2 || pagination.Total != 3 || pagination.TotalPages != 2 || pagination.NextPage != 0 || pagination.PrevPage != 1 {
+		t.Errorf("pagination = %+v", pagination)
+	}
+	if got := server.Requests(); len(got) != 1 || got[0].Endpoint != "/projects/42/issues?page=2&per_page=2" {
+		t.Errorf("Requests() = %+v", got)
+	}
+}
+
+func TestServer_ErrorsAndBodies(t *testing.T) {
+	server := NewServer(t)
+	server.Handle(http.MethodGet, "/projects/7", http.StatusForbidden, `{"message": "403 Forbidden"}`)
+	server.Handle(http.MethodPost, "/projects/42/issues", http.StatusCreated, gitlab.Issue{IID: 9})
+	ctx := context.Background()
+
+	var apiErr *gitlab.APIError
+	err := server.NewGitLabClient().Get(ctx, "/projects/7", nil)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || apiErr.Message != "403 Forbidden" {
+		t.Errorf("Get() error = %v", err)
+	}
+	err = gitlab.NewClient(server.URL(), "wrong-token").Get(ctx, "/projects/42", nil)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Get() with a wrong token: %v", err)
+	}
+
+	var issue gitlab.Issue
+	if err := server.NewGitLabClient().Post(ctx, "/projects/42/issues", map[string]string{"title": "x"}, &issue); err != nil || issue.IID != 9 {
+		t.Errorf("Post() = %+v, %v", issue, err)
+	}
+	requests := server.Requests()
+	if last := requests[len(requests)-1]; string(last.Body) != `{"title":"x"}` {
+		t.Errorf("request body = %s", last.Body)
+	}
+}
+
+func TestServer_GetEach(t *testing.T) {
+	server := NewServer(t)
+	server.Handle(http.MethodGet, "/projects/42/jobs", http.StatusOK, `[{"id": 1}, {"id": 2}, {"id": 3}]`)
+	ctx := context.Background()
+
+	for name, client := range map[string]gitlab.API{"http": server.NewGitLabClient(), "memory": server.Client} {
+		var ids []int
+		pagination, err := client.GetEach(ctx, "/projects/42/jobs", func(item json.RawMessage) error {
+			var job gitlab.Job
+			if err := json.Unmarshal(item, &job); err != nil {
+				return err
+			}
+			ids = append(ids, job.ID)
+			if len(ids) == 2 {
+				return gitlab.ErrStop
+			}
+			return nil
+		})
+		if err != nil || len(ids) != 2 || ids[1] != 2 || pagination == nil {
+			t.Errorf("%s: GetEach() = %v, %+v, %v", name, ids, pagination, err)
+		}
+
+		failure := errors.New("callback failed")
+		_, err = client.GetEach(ctx, "/projects/42/jobs", func(json.RawMessage) error { return failure })
+		if err != failure {
+			t.Errorf("%s: GetEach() callback error = %v", name, err)
+		}
+	}
+}
+
+func TestServer_MaxResponseSize(t *testing.T) {
+	server := NewServer(t)
+	server.Handle(http.MethodGet, "/projects/42/repository/commits", http.StatusOK, `[{"id": "`+strings.Repeat("a", 2000)+`"}]`)
+	server.Handle(http.MethodGet, "/projects/42/jobs/1/trace", http.StatusOK, strings.Repeat("log line\n", 300))
+	ctx := context.Background()
+
+	client := server.NewGitLabClient(gitlab.WithMaxResponseSize(1024))
+	var commits []map[string]interface{}
+	if err := client.Get(ctx, "/projects/42/repository/commits", &commits); !errors.Is(err, gitlab.ErrResponseTooLarge) {
+		t.Errorf("Get() error = %v, want ErrResponseTooLarge", err)
+	}
+	if _, err := client.GetText(ctx, "/projects/42/jobs/1/trace"); !errors.Is(err, gitlab.ErrResponseTooLarge) {
+		t.Errorf("GetText() error = %v, want ErrResponseTooLarge", err)
+	}
+
+	client = server.NewGitLabClient(gitlab.WithMaxResponseSize(0))
+	if err := client.Get(ctx, "/projects/42/repository/commits", &commits); err != nil || len(commits) != 1 {
+		t.Errorf("Get() without a limit = %d commits, %v", len(commits), err)
+	}
+}
+
+// accessLogger records the transfer sizes of the access log.
+type accessLogger struct {
+	sizes []gitlab.TransferSizes
+}
+
+func (l *accessLogger) Access(_ context.Context, _, _ string, _ int, _ time.Duration, sizes gitlab.TransferSizes) {
+	l.sizes = append(l.sizes, sizes)
+}
+func (l *accessLogger) Debug(context.Context, string, ...any) {}
+func (l *accessLogger) Error(context.Context, string, ...any) {}
+func (l *accessLogger) LogHTTPRequest(context.Context, string, *gitlab.HTTPRequestInfo, ...string) {
+}
+func (l *accessLogger) LogHTTPResponse(context.Context, string, *gitlab.HTTPResponseInfo, time.Duration, ...string) {
+}
+func (l *accessLogger) LogHTTPError(context.Context, string, *gitlab.HTTPRequestInfo, *gitlab.HTTPResponseInfo, error, ...string) {
+}
+
+func TestServer_Compression(t *testing.T) {
+	server := NewServer(t)
+	server.Handle(http.MethodGet, "/projects/42/repository/tree", http.StatusOK, `[{"path": "`+strings.Repeat("src/", 500)+`"}]`)
+	server.Handle(http.MethodPost, "/projects/42/repository/commits", http.StatusCreated, `{"id": "abc"}`)
+	logger := &accessLogger{}
+	client := server.NewGitLabClient(gitlab.WithLogger(logger), gitlab.WithRequestCompression(1024))
+	ctx := context.Background()
+
+	var tree []map[string]string
+	if err := client.Get(ctx, "/projects/42/repository/tree", &tree); err != nil || len(tree) != 1 {
+		t.Fatalf("Get() = %v, %v", tree, err)
+	}
+	received := logger.sizes[0]
+	if received.Encoding != "gzip" || received.Received == 0 || received.Received >= received.ReceivedRaw {
+		t.Errorf("response sizes = %+v", received)
+	}
+
+	small := map[string]string{"branch": "main"}
+	large := map[string]string{"branch": "main", "content": strings.Repeat("package main\n", 200)}
+	for _, body := range []map[string]string{small, large} {
+		if err := client.Post(ctx, "/projects/42/repository/commits", body, nil); err != nil {
+			t.Fatalf("Post() error: %v", err)
+		}
+	}
+	requests := server.Requests()
+	if requests[1].Encoding != "" || requests[2].Encoding != "gzip" || !strings.Contains(string(requests[2].Body), "package main") {
+		t.Errorf("request encodings = %q, %q", requests[1].Encoding, requests[2].Encoding)
+	}
+	if sent := logger.sizes[2]; sent.Sent >= sent.SentRaw || !strings.Contains(sent.String(), "sent_raw=") {
+		t.Errorf("request sizes = %s", sent)
+	}
+}
@@ -0,0 +1,208 @@
+package gitlab
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitInfo is the most recent RateLimit-* state GitLab reported for one endpoint class.
+type RateLimitInfo struct {
+	Class        string     `json:"class"`
+	Limit        int64      `json:"limit"`
+	Remaining    int64      `json:"remaining"`
+	ResetAt      *time.Time `json:"reset_at,omitempty"`
+	ObservedAt   time.Time  `json:"observed_at"`
+	LastEndpoint string     `json:"last_endpoint"`
+}
+
+// LimiterBudget describes the client-side request limiter configured with WithRateLimit.
+type LimiterBudget struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+	Available         float64 `json:"available"`
+}
+
+// WithRateLimit enables a client-side token bucket limiter allowing
+// requestsPerSecond sustained requests with bursts of up to burst requests.
+// A non-positive requestsPerSecond leaves requests unlimited.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.limiter = nil
+		if requestsPerSecond > 0 {
+			c.limiter = newLimiter(requestsPerSecond, burst)
+		}
+		activeLimiter.Store(c.limiter)
+	}
+}
+
+// RateLimitStatus returns the most recent RateLimit-* headers observed per
+// endpoint class, sorted by class. Classes never seen are omitted.
+func (c *Client) RateLimitStatus() []RateLimitInfo {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+
+	result := make([]RateLimitInfo, 0, len(c.rateLimits))
+	for _, info := range c.rateLimits {
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Class < result[j].Class })
+	return result
+}
+
+// LimiterBudget returns the current client-side limiter budget, or nil if no limiter is configured.
+func (c *Client) LimiterBudget() *LimiterBudget {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.budget()
+}
+
+// recordRateLimit stores the RateLimit-* headers from a GitLab response under
+// the endpoint's class and updates the rate limit gauges.
+func (c *Client) recordRateLimit(endpoint string, headers http.Header) {
+	recordRateLimit(headers)
+
+	limit, err := strconv.ParseInt(headers.Get("RateLimit-Limit"), 10, 64)
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.ParseInt(headers.Get("RateLimit-Remaining"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	info := RateLimitInfo{
+		Class:        endpointClass(endpoint),
+		Limit:        limit,
+		Remaining:    remaining,
+		ObservedAt:   time.Now(),
+		LastEndpoint: endpoint,
+	}
+	if reset, err := strconv.ParseInt(headers.Get("RateLimit-Reset"), 10, 64); err == nil {
+		resetAt := time.Unix(reset, 0)
+		info.ResetAt = &resetAt
+	}
+
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	if c.rateLimits == nil {
+		c.rateLimits = make(map[string]RateLimitInfo)
+	}
+	c.rateLimits[info.Class] = info
+}
+
+// endpointClass groups API endpoints the way GitLab applies separate rate limits:
+// search, repository files, raw blobs and archives have their own limits; everything
+// else is grouped by its top-level resource (projects, groups, users, ...).
+func endpointClass(endpoint string) string {
+	path := endpoint
+	if idx := strings.Index(path, "?"); idx != -1 {
+		path = path[:idx]
+	}
+
+	switch {
+	case strings.HasSuffix(path, "/search") || strings.HasPrefix(path, "/search"):
+		return "search"
+	case strings.Contains(path, "/repository/files/"):
+		if strings.HasSuffix(path, "/raw") {
+			return "raw"
+		}
+		return "files"
+	case strings.Contains(path, "/repository/archive"):
+		return "archive"
+	case strings.HasSuffix(path, "/raw"):
+		return "raw"
+	}
+
+	segment := strings.TrimPrefix(path, "/")
+	if idx := strings.Index(segment, "/"); idx != -1 {
+		segment = segment[:idx]
+	}
+	if segment == "" {
+		return "api"
+	}
+	return segment
+}
+
+// limiter is a token bucket shared by all requests made through a Client.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	if burst < 1 {
+		burst = int(math.Max(1, math.Ceil(rate)))
+	}
+	return &limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds tokens accrued since the last call. l.mu must be held.
+func (l *limiter) refill(now time.Time) {
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+}
+
+// wait blocks until a token is available or ctx is done, and records the
+// time spent waiting.
+func (l *limiter) wait(ctx context.Context) error {
+	start := time.Now()
+	l.mu.Lock()
+	l.refill(start)
+	l.tokens--
+	if l.tokens >= 0 {
+		l.mu.Unlock()
+		limiterWait.Record(ctx, 0)
+		return nil
+	}
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	defer func() { limiterWait.Record(ctx, time.Since(start).Seconds()) }()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give back the reserved token
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (l *limiter) budget() *LimiterBudget {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	return &LimiterBudget{
+		RequestsPerSecond: l.rate,
+		Burst:             int(l.burst),
+		Available:         math.Max(0, math.Floor(l.tokens*100)/100),
+	}
+}
+
+// saturation returns the share of the burst in use: 0 when the bucket is
+// full, 1 when it is empty and requests wait for tokens.
+func (l *limiter) saturation() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	return 1 - math.Max(0, l.tokens)/l.burst
+}
@@ -0,0 +1,156 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestLimiterBurst(t *testing.T) {
+	l := newLimiter(1, 3)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("the burst took %v, want no wait", elapsed)
+	}
+	if available := l.budget().Available; available != 0 {
+		t.Errorf("available after the burst = %v, want 0", available)
+	}
+}
+
+func TestLimiterWaitsForRefill(t *testing.T) {
+	l := newLimiter(20, 1)
+	l.wait(context.Background())
+
+	// The next token accrues after 1/20 s
+	start := time.Now()
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("waited %v, want about 50ms", elapsed)
+	}
+}
+
+func TestLimiterRefillCapsAtBurst(t *testing.T) {
+	l := newLimiter(10, 2)
+	l.mu.Lock()
+	l.tokens = 0
+	l.refill(l.last.Add(time.Hour))
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens != 2 {
+		t.Errorf("tokens after an idle hour = %v, want the burst of 2", tokens)
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	l := newLimiter(0.1, 1)
+	l.wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait = %v, want the context error", err)
+	}
+	// The token reserved by the cancelled wait is given back, so the next
+	// request is not delayed by it
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < -0.01 {
+		t.Errorf("tokens after a cancelled wait = %v, want about 0", tokens)
+	}
+}
+
+func TestNewLimiterDefaultBurst(t *testing.T) {
+	tests := []struct {
+		rate  float64
+		burst int
+		want  int
+	}{
+		{rate: 5, burst: 10, want: 10},
+		{rate: 5, burst: 0, want: 5},
+		{rate: 2.5, burst: -1, want: 3},
+		{rate: 0.5, burst: 0, want: 1},
+	}
+	for _, tt := range tests {
+		if got := newLimiter(tt.rate, tt.burst).budget().Burst; got != tt.want {
+			t.Errorf("newLimiter(%v, %d) burst = %d, want %d", tt.rate, tt.burst, got, tt.want)
+		}
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	if c := NewClient("http://gitlab.example.com", "token", WithRateLimit(0, 5)); c.LimiterBudget() != nil {
+		t.Error("a zero rate configured a limiter")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "token", WithRateLimit(1, 2))
+	for i := 0; i < 2; i++ {
+		if err := c.Get(context.Background(), "/version", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	budget := c.LimiterBudget()
+	if budget == nil || budget.RequestsPerSecond != 1 || budget.Burst != 2 || budget.Available >= 1 {
+		t.Errorf("budget after two requests = %+v", budget)
+	}
+
+	// A request that cannot get a token before its deadline is not sent
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.Get(ctx, "/version", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get = %v, want the limiter to give up at the deadline", err)
+	}
+}
+
+func TestLimiterMetrics(t *testing.T) {
+	c := NewClient("http://gitlab.invalid", "token", WithRateLimit(20, 1))
+	beforeCount, beforeSum := histogramCount(t, "gitlab.limiter.wait")
+
+	// The first request takes the burst, the second waits about 50ms
+	for i := 0; i < 2; i++ {
+		if err := c.limiter.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count, sum := histogramCount(t, "gitlab.limiter.wait")
+	if count-beforeCount != 2 || sum-beforeSum < 0.04 || sum-beforeSum > 0.5 {
+		t.Errorf("recorded %d waits of %.3fs, want 2 waits of about 0.05s", count-beforeCount, sum-beforeSum)
+	}
+
+	saturation := func() []metricdata.DataPoint[float64] {
+		gauge, _ := collectMetric(t, "gitlab.limiter.saturation").(metricdata.Gauge[float64])
+		return gauge.DataPoints
+	}
+	if points := saturation(); len(points) != 1 || points[0].Value < 0.9 || points[0].Value > 1 {
+		t.Errorf("saturation with the bucket empty = %+v, want about 1", points)
+	}
+	c.limiter.mu.Lock()
+	c.limiter.tokens = c.limiter.burst
+	c.limiter.mu.Unlock()
+	if points := saturation(); len(points) != 1 || points[0].Value != 0 {
+		t.Errorf("saturation with the bucket full = %+v, want 0", points)
+	}
+
+	// Without a limiter there is nothing to observe
+	NewClient("http://gitlab.invalid", "token", WithRateLimit(0, 0))
+	if points := saturation(); len(points) != 0 {
+		t.Errorf("saturation without a limiter = %+v, want none", points)
+	}
+}
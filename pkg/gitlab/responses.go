@@ -0,0 +1,49 @@
+package gitlab
+
+import (
+	"context"
+	"sync"
+)
+
+// Response is the outcome of one GitLab request: the HTTP status code, or 0
+// when no response was received.
+type Response struct {
+	Method     string `json:"method"`
+	Endpoint   string `json:"endpoint"`
+	StatusCode int    `json:"status"`
+}
+
+// ResponseRecorder collects the GitLab requests made with a context, for
+// callers that report on them afterwards (e.g. the audit log).
+type ResponseRecorder struct {
+	mu        sync.Mutex
+	responses []Response
+}
+
+// responseRecorderKey is the context key of the ResponseRecorder.
+type responseRecorderKey struct{}
+
+// WithResponseRecorder returns a context whose GitLab requests are recorded
+// by the returned recorder.
+func WithResponseRecorder(ctx context.Context) (context.Context, *ResponseRecorder) {
+	recorder := &ResponseRecorder{}
+	return context.WithValue(ctx, responseRecorderKey{}, recorder), recorder
+}
+
+// Responses returns the recorded requests in the order they completed.
+func (r *ResponseRecorder) Responses() []Response {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Response(nil), r.responses...)
+}
+
+// recordResponse adds a request to the recorder of ctx, if any.
+func recordResponse(ctx context.Context, method, endpoint string, statusCode int) {
+	recorder, ok := ctx.Value(responseRecorderKey{}).(*ResponseRecorder)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.responses = append(recorder.responses, Response{Method: method, Endpoint: endpoint, StatusCode: statusCode})
+}
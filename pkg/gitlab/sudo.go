@@ -0,0 +1,30 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+)
+
+// sudoKey is the context key of the user requests are made as.
+type sudoKey struct{}
+
+// WithSudo returns a context whose requests are made as user, a username or
+// numeric user ID, through the Sudo header. GitLab honors the header only for
+// administrator tokens with the sudo scope and rejects it otherwise.
+func WithSudo(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, sudoKey{}, user)
+}
+
+// SudoFromContext returns the user the requests made with ctx act as, if any.
+func SudoFromContext(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(sudoKey{}).(string)
+	return user, ok && user != ""
+}
+
+// setSudo sets the Sudo header of the outbound request for the user of ctx,
+// if any.
+func setSudo(ctx context.Context, req *http.Request) {
+	if user, ok := SudoFromContext(ctx); ok {
+		req.Header.Set("Sudo", user)
+	}
+}
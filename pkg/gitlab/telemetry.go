@@ -0,0 +1,161 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans and metrics produced by the GitLab client.
+const instrumentationName = "github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+
+var (
+	tracer = otel.Tracer(instrumentationName)
+	meter  = otel.Meter(instrumentationName)
+
+	// apiRequests counts outbound GitLab API requests by method and status code.
+	apiRequests, _ = meter.Int64Counter(
+		"gitlab.api.requests",
+		metric.WithDescription("Number of GitLab API requests"),
+		metric.WithUnit("{request}"),
+	)
+
+	// apiDuration records GitLab API latency by method and status code.
+	apiDuration, _ = meter.Float64Histogram(
+		"gitlab.api.duration",
+		metric.WithDescription("Duration of GitLab API requests"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60),
+	)
+
+	// limiterWait records how long requests waited for the client-side limiter,
+	// including the requests that did not wait at all.
+	limiterWait, _ = meter.Float64Histogram(
+		"gitlab.limiter.wait",
+		metric.WithDescription("Time GitLab API requests waited for the client-side rate limiter"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10),
+	)
+
+	// cacheRequests counts cacheable GET requests by result: "hit" when GitLab
+	// answered 304 and the cached body was used, "miss" otherwise.
+	cacheRequests, _ = meter.Int64Counter(
+		"gitlab.cache.requests",
+		metric.WithDescription("Number of GitLab API GET requests looked up in the response cache"),
+		metric.WithUnit("{request}"),
+	)
+
+	// activeLimiter is the limiter of the most recently configured client,
+	// observed by the saturation gauge; nil when requests are unlimited.
+	activeLimiter atomic.Pointer[limiter]
+
+	// rateLimitLimit and rateLimitRemaining hold the most recent RateLimit-* header
+	// values reported by GitLab; -1 means no header has been observed yet.
+	rateLimitLimit     atomic.Int64
+	rateLimitRemaining atomic.Int64
+)
+
+func init() {
+	rateLimitLimit.Store(-1)
+	rateLimitRemaining.Store(-1)
+
+	limitGauge, _ := meter.Int64ObservableGauge(
+		"gitlab.ratelimit.limit",
+		metric.WithDescription("Request limit reported by the most recent GitLab RateLimit-Limit header"),
+		metric.WithUnit("{request}"),
+	)
+	remainingGauge, _ := meter.Int64ObservableGauge(
+		"gitlab.ratelimit.remaining",
+		metric.WithDescription("Requests remaining reported by the most recent GitLab RateLimit-Remaining header"),
+		metric.WithUnit("{request}"),
+	)
+	_, _ = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		if v := rateLimitLimit.Load(); v >= 0 {
+			o.ObserveInt64(limitGauge, v)
+		}
+		if v := rateLimitRemaining.Load(); v >= 0 {
+			o.ObserveInt64(remainingGauge, v)
+		}
+		return nil
+	}, limitGauge, remainingGauge)
+
+	saturationGauge, _ := meter.Float64ObservableGauge(
+		"gitlab.limiter.saturation",
+		metric.WithDescription("Share of the client-side rate limiter burst in use, from 0 (idle) to 1 (requests are waiting)"),
+		metric.WithUnit("1"),
+	)
+	_, _ = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		if l := activeLimiter.Load(); l != nil {
+			o.ObserveFloat64(saturationGauge, l.saturation())
+		}
+		return nil
+	}, saturationGauge)
+}
+
+// recordCacheRequest counts a response cache lookup as a hit or a miss.
+func recordCacheRequest(ctx context.Context, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+// recordRateLimit stores the RateLimit-* headers from a GitLab response, if present.
+func recordRateLimit(headers http.Header) {
+	if v, err := strconv.ParseInt(headers.Get("RateLimit-Limit"), 10, 64); err == nil {
+		rateLimitLimit.Store(v)
+	}
+	if v, err := strconv.ParseInt(headers.Get("RateLimit-Remaining"), 10, 64); err == nil {
+		rateLimitRemaining.Store(v)
+	}
+}
+
+// startAPISpan starts a client span for a GitLab API request.
+// The span is a child of any span already present in ctx (e.g., the tool call span).
+func startAPISpan(ctx context.Context, method, endpoint string) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	path := endpoint
+	if idx := strings.Index(path, "?"); idx != -1 {
+		path = path[:idx]
+	}
+	return tracer.Start(ctx, "GitLab "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.URLPath(path),
+		),
+	)
+}
+
+// endAPISpan finishes the span started by startAPISpan and records request metrics.
+// statusCode is 0 when no response was received.
+func endAPISpan(ctx context.Context, span trace.Span, method string, statusCode int, duration time.Duration, err error) {
+	attrs := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(method)}
+	if statusCode > 0 {
+		attrs = append(attrs, semconv.HTTPResponseStatusCode(statusCode))
+		span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if statusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+	span.End()
+
+	apiRequests.Add(ctx, 1, metric.WithAttributes(attrs...))
+	apiDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+}
@@ -0,0 +1,67 @@
+package gitlab
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// metricsReader reads the package's instruments. It is installed once, as
+// the instruments are created on the global meter at package init.
+var metricsReader = sync.OnceValue(func() *sdkmetric.ManualReader {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	return reader
+})
+
+// collectMetric returns the current data of the named instrument, nil if it
+// has recorded nothing.
+func collectMetric(t *testing.T, name string) metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := metricsReader().Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect metrics: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	return nil
+}
+
+// counterValue returns the value of the named Int64 counter for attrs.
+func counterValue(t *testing.T, name string, attrs ...attribute.KeyValue) int64 {
+	t.Helper()
+	sum, _ := collectMetric(t, name).(metricdata.Sum[int64])
+	for _, point := range sum.DataPoints {
+		if point.Attributes.Equals(ptrSet(attrs...)) {
+			return point.Value
+		}
+	}
+	return 0
+}
+
+// histogramCount returns the number of values and their sum recorded by the
+// named Float64 histogram.
+func histogramCount(t *testing.T, name string) (count uint64, sum float64) {
+	t.Helper()
+	histogram, _ := collectMetric(t, name).(metricdata.Histogram[float64])
+	for _, point := range histogram.DataPoints {
+		count += point.Count
+		sum += point.Sum
+	}
+	return count, sum
+}
+
+func ptrSet(attrs ...attribute.KeyValue) *attribute.Set {
+	set := attribute.NewSet(attrs...)
+	return &set
+}
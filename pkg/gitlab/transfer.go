@@ -0,0 +1,338 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxErrorBodyBytes caps how much of an error response is read from a
+// streamed request.
+const maxErrorBodyBytes = 64 << 10
+
+// ErrResponseTooLarge is returned by GetBinary when the response body exceeds
+// the size limit.
+var ErrResponseTooLarge = errors.New("response exceeds the size limit")
+
+// BinaryInfo describes a file downloaded with GetBinary.
+type BinaryInfo struct {
+	// Size is the number of bytes written.
+	Size int64
+	// ContentType is the media type from the response, or detected from the
+	// content when GitLab sends none or a generic one.
+	ContentType string
+	// Filename is the name from the Content-Disposition header, if any.
+	Filename string
+}
+
+// MultipartFile is the file part of a multipart/form-data request.
+type MultipartFile struct {
+	// Field is the form field name (e.g., "file").
+	Field string
+	// Name is the filename sent to GitLab.
+	Name string
+	// Reader supplies the file content.
+	Reader io.Reader
+}
+
+// PostMultipart performs an HTTP POST request with a multipart/form-data body
+// made of fields and file, and decodes the JSON response into result. The file
+// is streamed, so large archives are not buffered in memory.
+func (c *Client) PostMultipart(ctx context.Context, endpoint string, fields map[string]string, file MultipartFile, result interface{}) error {
+	pipeReader, pipeWriter := io.Pipe()
+	form := multipart.NewWriter(pipeWriter)
+
+	go func() {
+		err := func() error {
+			for name, value := range fields {
+				if err := form.WriteField(name, value); err != nil {
+					return err
+				}
+			}
+			part, err := form.CreateFormFile(file.Field, file.Name)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(part, file.Reader); err != nil {
+				return err
+			}
+			return form.Close()
+		}()
+		pipeWriter.CloseWithError(err)
+	}()
+
+	resp, err := c.doStream(ctx, http.MethodPost, endpoint, pipeReader, form.FormDataContentType())
+	// Unblock the writer goroutine if the request ended before the body was read.
+	pipeReader.Close()
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// PutFile performs an HTTP PUT request with r streamed as the raw request
+// body, as the generic package registry expects, and decodes the JSON response
+// into result.
+func (c *Client) PutFile(ctx context.Context, endpoint string, r io.Reader, result interface{}) error {
+	resp, err := c.doStream(ctx, http.MethodPut, endpoint, r, "application/octet-stream")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// Download performs an HTTP GET request and copies the raw response body to
+// w, returning the number of bytes written and the response headers.
+func (c *Client) Download(ctx context.Context, endpoint string, w io.Writer) (int64, http.Header, error) {
+	resp, err := c.doStream(ctx, http.MethodGet, endpoint, nil, "")
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	written, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return written, resp.Header, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return written, resp.Header, nil
+}
+
+// GetBinary performs an HTTP GET request and copies the raw response body to
+// w, failing with ErrResponseTooLarge once more than maxBytes would be written
+// (0 means no limit). On that error w holds a partial body the caller should
+// discard.
+func (c *Client) GetBinary(ctx context.Context, endpoint string, w io.Writer, maxBytes int64) (*BinaryInfo, error) {
+	resp, err := c.doStream(ctx, http.MethodGet, endpoint, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return readBinary(resp, w, maxBytes)
+}
+
+// GetWebBinary is GetBinary for a path on the GitLab web application rather
+// than the REST API (e.g., "/group/project/badges/main/pipeline.svg"). The
+// request goes to the same host with the same token.
+func (c *Client) GetWebBinary(ctx context.Context, path string, w io.Writer, maxBytes int64) (*BinaryInfo, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	resp, err := c.doStreamURL(ctx, http.MethodGet, path, c.WebURL()+path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return readBinary(resp, w, maxBytes)
+}
+
+// WebURL returns the root URL of the GitLab web application.
+func (c *Client) WebURL() string {
+	return strings.TrimSuffix(c.baseURL, "/api/v4")
+}
+
+// readBinary copies a successful response body to w for GetBinary and
+// GetWebBinary, and closes it.
+func readBinary(resp *http.Response, w io.Writer, maxBytes int64) (*BinaryInfo, error) {
+	defer resp.Body.Close()
+
+	if maxBytes > 0 && resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes (limit %d)", ErrResponseTooLarge, resp.ContentLength, maxBytes)
+	}
+
+	body := io.Reader(resp.Body)
+	if maxBytes > 0 {
+		// Read one byte past the limit to tell "exactly maxBytes" from "more"
+		body = io.LimitReader(resp.Body, maxBytes+1)
+	}
+	sniffer := &sniffWriter{w: w}
+	written, err := io.Copy(sniffer, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if maxBytes > 0 && written > maxBytes {
+		return nil, fmt.Errorf("%w: limit %d bytes", ErrResponseTooLarge, maxBytes)
+	}
+
+	info := &BinaryInfo{Size: written}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		info.ContentType = mediaType
+	}
+	if info.ContentType == "" || info.ContentType == "application/octet-stream" {
+		detected, _, _ := mime.ParseMediaType(http.DetectContentType(sniffer.head))
+		info.ContentType = detected
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		info.Filename = params["filename"]
+	}
+	return info, nil
+}
+
+// sniffWriter passes writes through to w and keeps the first bytes for
+// content type detection.
+type sniffWriter struct {
+	w    io.Writer
+	head []byte
+}
+
+// sniffLen is how many bytes http.DetectContentType considers.
+const sniffLen = 512
+
+func (s *sniffWriter) Write(p []byte) (int, error) {
+	if missing := sniffLen - len(s.head); missing > 0 {
+		s.head = append(s.head, p[:min(missing, len(p))]...)
+	}
+	return s.w.Write(p)
+}
+
+// IsTextContentType reports whether a media type is human-readable text.
+func IsTextContentType(contentType string) bool {
+	if strings.HasPrefix(contentType, "text/") {
+		return true
+	}
+	switch contentType {
+	case "application/json", "application/xml", "application/javascript", "application/x-yaml", "application/yaml", "image/svg+xml":
+		return true
+	}
+	return strings.HasSuffix(contentType, "+json") || strings.HasSuffix(contentType, "+xml")
+}
+
+// doStream sends a request with a raw body and returns the response with its
+// body unread. Error responses are consumed and returned as *APIError. The
+// caller must close the body of a successful response.
+func (c *Client) doStream(ctx context.Context, method, endpoint string, body io.Reader, contentType string) (*http.Response, error) {
+	return c.doStreamURL(ctx, method, endpoint, c.buildURL(endpoint), body, contentType)
+}
+
+// doStreamURL is doStream for an explicit URL; endpoint is only used for
+// logging, tracing and rate limit bookkeeping.
+func (c *Client) doStreamURL(ctx context.Context, method, endpoint, url string, body io.Reader, contentType string) (resp *http.Response, err error) {
+	start := time.Now()
+
+	ctx, span := startAPISpan(ctx, method, endpoint)
+	statusCode := 0
+	defer func() {
+		endAPISpan(ctx, span, method, statusCode, time.Since(start), err)
+		recordResponse(ctx, method, endpoint, statusCode)
+	}()
+
+	token := c.getToken()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	headers := map[string]string{
+		"Authorization":   "Bearer " + token,
+		"Accept":          "*/*",
+		"Accept-Encoding": acceptEncoding,
+	}
+	if contentType != "" {
+		headers["Content-Type"] = contentType
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	c.setRequestID(ctx, req)
+	setSudo(ctx, req)
+	// Count the streamed request body; the Content-Length set above is kept
+	var sent *countingReadCloser
+	if req.Body != nil && req.Body != http.NoBody {
+		sent = &countingReadCloser{ReadCloser: req.Body}
+		req.Body = sent
+	}
+
+	// The body is streamed, so only the request line and headers are logged
+	c.logger.LogHTTPRequest(ctx, "api_request_stream", &HTTPRequestInfo{
+		Method:  method,
+		URL:     url,
+		Headers: headers,
+	}, token)
+
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter: %w", err)
+		}
+		// Durations cover GitLab, not the wait for the limiter
+		start = time.Now()
+	}
+
+	resp, err = c.httpClient.Do(req)
+	if err != nil {
+		c.logger.LogHTTPError(ctx, "http_request_stream", &HTTPRequestInfo{
+			Method:  method,
+			URL:     url,
+			Headers: headers,
+		}, nil, err, token)
+		c.logger.Error(ctx, "request failed", "method", method, "endpoint", endpoint, "error", err)
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	statusCode = resp.StatusCode
+	c.recordRateLimit(endpoint, resp.Header)
+	transfer := decodeResponse(resp)
+	// The access log line is written once the caller has read the body
+	logAccess := func() {
+		c.logger.Access(ctx, method, endpoint, resp.StatusCode, time.Since(start), transfer.sizes(sent.count(), sent.count()))
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		logAccess()
+		c.logger.LogHTTPError(ctx, "api_error_stream", &HTTPRequestInfo{
+			Method: method,
+			URL:    url,
+		}, &HTTPResponseInfo{
+			StatusCode: resp.StatusCode,
+			Headers:    convertHeaders(resp.Header),
+			Body:       string(respBody),
+		}, nil, token)
+		return nil, c.handleErrorResponse(resp, endpoint, respBody)
+	}
+
+	transfer.onClose = logAccess
+	return resp, nil
+}
+
+// countingReadCloser counts the bytes of a streamed request body. The
+// transport reads the body in its own goroutine, hence the atomic counter.
+type countingReadCloser struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// count returns the bytes read, 0 for a nil body.
+func (c *countingReadCloser) count() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.n.Load()
+}
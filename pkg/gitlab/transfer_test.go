@@ -0,0 +1,223 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gatedReader returns first, then waits for open before returning rest, so a
+// request can only complete if its body is streamed.
+type gatedReader struct {
+	first, rest string
+	open        chan struct{}
+	step        int
+}
+
+func (g *gatedReader) Read(p []byte) (int, error) {
+	g.step++
+	switch g.step {
+	case 1:
+		return copy(p, g.first), nil
+	case 2:
+		select {
+		case <-g.open:
+		case <-time.After(5 * time.Second):
+			return 0, errors.New("the server never saw the first part of the file")
+		}
+		return copy(p, g.rest), nil
+	}
+	return 0, io.EOF
+}
+
+func TestPostMultipart(t *testing.T) {
+	type upload struct {
+		contentType, boundary string
+		contentLength         int64
+		fields                map[string]string
+		field, filename, file string
+	}
+	var got upload
+	file := &gatedReader{first: "PK\x03\x04first", rest: "-rest", open: make(chan struct{})}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.contentType = r.Header.Get("Content-Type")
+		got.contentLength = r.ContentLength
+		mediaType, params, err := mime.ParseMediaType(got.contentType)
+		if err != nil || mediaType != "multipart/form-data" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got.boundary = params["boundary"]
+		got.fields = map[string]string{}
+		form := multipart.NewReader(r.Body, got.boundary)
+		for {
+			part, err := form.NextPart()
+			if err != nil {
+				break
+			}
+			if part.FileName() == "" {
+				value, _ := io.ReadAll(part)
+				got.fields[part.FormName()] = string(value)
+				continue
+			}
+			got.field, got.filename = part.FormName(), part.FileName()
+			// The first part of the file arrives before the client has the rest
+			head := make([]byte, len(file.first))
+			if _, err := io.ReadFull(part, head); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			close(file.open)
+			rest, _ := io.ReadAll(part)
+			got.file = string(head) + string(rest)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 7}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "token")
+
+	var result struct {
+		ID int `json:"id"`
+	}
+	fields := map[string]string{"path": "backup", "overwrite[name]": "restored"}
+	err := c.PostMultipart(context.Background(), "/projects/import", fields, MultipartFile{Field: "file", Name: "export.tar.gz", Reader: file}, &result)
+	if err != nil {
+		t.Fatalf("PostMultipart: %v", err)
+	}
+	if result.ID != 7 {
+		t.Errorf("result = %+v", result)
+	}
+	if got.boundary == "" || !strings.HasPrefix(got.contentType, "multipart/form-data; boundary=") {
+		t.Errorf("Content-Type = %q, want multipart/form-data with a boundary", got.contentType)
+	}
+	// Streamed bodies are sent chunked, without a length known in advance
+	if got.contentLength != -1 {
+		t.Errorf("Content-Length = %d, want a streamed body", got.contentLength)
+	}
+	if len(got.fields) != 2 || got.fields["path"] != "backup" || got.fields["overwrite[name]"] != "restored" {
+		t.Errorf("fields = %v", got.fields)
+	}
+	if got.field != "file" || got.filename != "export.tar.gz" || got.file != file.first+file.rest {
+		t.Errorf("file part %s = %s %q", got.field, got.filename, got.file)
+	}
+}
+
+func TestPostMultipartErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message": "This endpoint has been requested too many times"}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "token")
+	ctx := context.Background()
+
+	err := c.PostMultipart(ctx, "/projects/import", nil, MultipartFile{Field: "file", Name: "a.tar.gz", Reader: strings.NewReader("data")}, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Endpoint != "/projects/import" ||
+		apiErr.Message != "This endpoint has been requested too many times" || apiErr.RetryAfter != 30*time.Second {
+		t.Errorf("PostMultipart error = %#v, want the 429 as an *APIError", err)
+	}
+
+	// A file that cannot be read fails the request instead of sending a
+	// truncated upload
+	readErr := errors.New("disk on fire")
+	err = c.PostMultipart(ctx, "/projects/import", nil, MultipartFile{Field: "file", Name: "a.tar.gz", Reader: io.MultiReader(strings.NewReader("data"), errReader{readErr})}, nil)
+	if !errors.Is(err, readErr) {
+		t.Errorf("PostMultipart with a failing file = %v, want the read error", err)
+	}
+}
+
+// errReader fails every read with err.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestGetBinaryLimit(t *testing.T) {
+	const limit = 64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		body := bytes.Repeat([]byte("x"), size)
+		switch r.URL.Query().Get("send") {
+		case "chunked":
+			// Flushing before the body leaves the length unknown
+			w.(http.Flusher).Flush()
+		case "gzip":
+			// The compressed length is under the limit, the content is not
+			w.Header().Set("Content-Encoding", "gzip")
+			body = gzipped(t, string(body))
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		case "short":
+			// Content-Length promises more than is sent before the
+			// connection closes
+			conn, buf, _ := w.(http.Hijacker).Hijack()
+			fmt.Fprintf(buf, "HTTP/1.1 200 OK\r\nContent-Length: %d\r\n\r\n%s", size, body[:size/2])
+			buf.Flush()
+			conn.Close()
+			return
+		default:
+			w.Header().Set("Content-Length", strconv.Itoa(size))
+		}
+		w.Write(body)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "token")
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+		// maxWritten bounds what reaches the writer when the body is refused
+		maxWritten int
+	}{
+		{name: "exactly the limit", query: "size=64"},
+		{name: "exactly the limit without Content-Length", query: "size=64&send=chunked"},
+		{name: "over the limit by Content-Length", query: "size=65", wantErr: "65 bytes (limit 64)"},
+		{name: "over the limit without Content-Length", query: "size=65&send=chunked", wantErr: "limit 64 bytes", maxWritten: limit + 1},
+		{name: "far over the limit without Content-Length", query: "size=100000&send=chunked", wantErr: "limit 64 bytes", maxWritten: limit + 1},
+		{name: "over the limit once decompressed", query: "size=1000&send=gzip", wantErr: "limit 64 bytes", maxWritten: limit + 1},
+		{name: "shorter than Content-Length", query: "size=64&send=short", wantErr: "failed to read response body", maxWritten: limit / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			info, err := c.GetBinary(context.Background(), "/projects/42/jobs/1/artifacts?"+tt.query, &out, limit)
+			if tt.wantErr == "" {
+				if err != nil || info.Size != limit || out.Len() != limit || info.ContentType != "text/plain" {
+					t.Errorf("GetBinary = %+v, %v with %d bytes written, want the whole body", info, err, out.Len())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("GetBinary error = %v, want %q", err, tt.wantErr)
+			}
+			if tooLarge := errors.Is(err, ErrResponseTooLarge); tooLarge != strings.Contains(tt.wantErr, "limit") {
+				t.Errorf("GetBinary error = %v, ErrResponseTooLarge: %v", err, tooLarge)
+			}
+			if info != nil || out.Len() > tt.maxWritten {
+				t.Errorf("GetBinary wrote %d bytes and returned %+v, want at most %d bytes and no info", out.Len(), info, tt.maxWritten)
+			}
+		})
+	}
+
+	// Without a limit any size is read
+	var out bytes.Buffer
+	if info, err := c.GetBinary(context.Background(), "/projects/42/jobs/1/artifacts?size=100000&send=chunked", &out, 0); err != nil || info.Size != 100000 {
+		t.Errorf("GetBinary without a limit = %+v, %v", info, err)
+	}
+}
@@ -13,6 +13,12 @@ type User struct {
 	Email     string `json:"email,omitempty"`
 }
 
+// Version represents the GitLab instance version returned by /version.
+type Version struct {
+	Version  string `json:"version"`
+	Revision string `json:"revision"`
+}
+
 // Project represents a GitLab project.
 type Project struct {
 	ID                int        `json:"id"`
@@ -31,6 +37,8 @@ type Project struct {
 	LastActivityAt    *time.Time `json:"last_activity_at"`
 	Namespace         *Namespace `json:"namespace,omitempty"`
 	Owner             *User      `json:"owner,omitempty"`
+	ForksCount        int        `json:"forks_count"`
+	ForkedFromProject *Project   `json:"forked_from_project,omitempty"`
 }
 
 // Namespace represents a GitLab namespace.
@@ -85,6 +93,7 @@ type MergeRequest struct {
 	Milestone       *Milestone `json:"milestone,omitempty"`
 	Assignees       []User     `json:"assignees,omitempty"`
 	Assignee        *User      `json:"assignee,omitempty"`
+	Reviewers       []User     `json:"reviewers,omitempty"`
 	Author          *User      `json:"author"`
 	MergedBy        *User      `json:"merged_by,omitempty"`
 	MergeStatus     string     `json:"merge_status"`
@@ -92,6 +101,7 @@ type MergeRequest struct {
 	MergeCommitSHA  string     `json:"merge_commit_sha,omitempty"`
 	Draft           bool       `json:"draft"`
 	WorkInProgress  bool       `json:"work_in_progress"`
+	Squash          bool       `json:"squash"`
 	WebURL          string     `json:"web_url"`
 	DiffRefs        *DiffRefs  `json:"diff_refs,omitempty"`
 }
@@ -0,0 +1,200 @@
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/redact"
+)
+
+// SanitizedHost replaces the GitLab host in recorded responses.
+const SanitizedHost = "gitlab.example.com"
+
+// secretParams are query parameters dropped from recorded endpoints.
+var secretParams = []string{"private_token", "access_token", "job_token"}
+
+// Recorder is an http.RoundTripper that sends requests to GitLab and records
+// them in a cassette file, which it rewrites after every response. Re-recording
+// a request replaces its earlier interaction.
+//
+// Cassettes are sanitized: request headers (and so tokens) are not recorded,
+// token query parameters are dropped, the GitLab host becomes SanitizedHost,
+// and bodies are masked with the recorder's redactor. Bodies that are not
+// valid UTF-8 are not recorded.
+type Recorder struct {
+	// OnSaveError, if set, is called when the cassette cannot be written.
+	// The response is returned regardless.
+	OnSaveError func(error)
+
+	path     string
+	next     http.RoundTripper
+	root     string
+	origin   string
+	host     string
+	redactor *redact.Redactor
+
+	mu           sync.Mutex
+	interactions []Interaction
+	index        map[string]int
+}
+
+// NewRecorder returns a Recorder for the GitLab at baseURL that sends
+// requests with next (http.DefaultTransport if nil) and records them to path.
+// Interactions already in the cassette are kept. A nil redactor masks the
+// builtin secret and personal data rules.
+func NewRecorder(path, baseURL string, next http.RoundTripper, redactor *redact.Redactor) (*Recorder, error) {
+	root, err := apiPath(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(baseURL)
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if redactor == nil {
+		redactor, _ = redact.New(append(append([]redact.Rule{}, redact.SecretRules...), redact.PIIRules...), nil)
+	}
+
+	r := &Recorder{
+		path:     path,
+		next:     next,
+		root:     root,
+		origin:   u.Scheme + "://" + u.Host,
+		host:     u.Host,
+		redactor: redactor,
+		index:    map[string]int{},
+	}
+	interactions, err := Load(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, interaction := range interactions {
+		r.add(interaction)
+	}
+	return r, nil
+}
+
+// Interactions returns the recorded interactions, in order.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Let the transport negotiate and undo compression, so the cassette
+	// holds readable bodies
+	if req.Header.Get("Accept-Encoding") != "" {
+		req = req.Clone(req.Context())
+		req.Header.Del("Accept-Encoding")
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	interaction := Interaction{
+		Method:     req.Method,
+		Endpoint:   r.sanitize(stripSecretParams(endpoint(r.root, req.URL))),
+		Pagination: parsePagination(resp.Header),
+	}
+	if resp.StatusCode != http.StatusOK {
+		interaction.Status = resp.StatusCode
+	}
+	for _, name := range []string{"Content-Type", "Content-Disposition"} {
+		if value := resp.Header.Get(name); value != "" {
+			if interaction.Headers == nil {
+				interaction.Headers = map[string]string{}
+			}
+			interaction.Headers[name] = value
+		}
+	}
+	r.setBody(&interaction, resp.Header.Get("Content-Type"), data)
+
+	r.mu.Lock()
+	r.add(interaction)
+	saveErr := Save(r.path, r.interactions)
+	r.mu.Unlock()
+	if saveErr != nil && r.OnSaveError != nil {
+		r.OnSaveError(saveErr)
+	}
+	return resp, nil
+}
+
+// add adds or replaces an interaction. r.mu must be held.
+func (r *Recorder) add(interaction Interaction) {
+	if i, ok := r.index[interaction.key()]; ok {
+		r.interactions[i] = interaction
+		return
+	}
+	r.index[interaction.key()] = len(r.interactions)
+	r.interactions = append(r.interactions, interaction)
+}
+
+// setBody records a sanitized response body as JSON or text.
+func (r *Recorder) setBody(interaction *Interaction, contentType string, data []byte) {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return
+	}
+	text := r.sanitize(string(data))
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if strings.HasSuffix(mediaType, "json") && json.Valid([]byte(text)) {
+		var compact bytes.Buffer
+		if json.Compact(&compact, []byte(text)) == nil {
+			interaction.Body = compact.Bytes()
+			return
+		}
+	}
+	interaction.Text = text
+}
+
+// sanitize replaces the GitLab host and masks secrets and personal data.
+func (r *Recorder) sanitize(text string) string {
+	if r.host != "" {
+		text = strings.ReplaceAll(text, r.origin, "https://"+SanitizedHost)
+		text = strings.ReplaceAll(text, r.host, SanitizedHost)
+	}
+	text, _ = r.redactor.Redact(text)
+	return text
+}
+
+// stripSecretParams drops token query parameters from an endpoint.
+func stripSecretParams(endpoint string) string {
+	path, query, found := strings.Cut(endpoint, "?")
+	if !found {
+		return endpoint
+	}
+	var kept []string
+	for _, param := range strings.Split(query, "&") {
+		name, _, _ := strings.Cut(param, "=")
+		secret := false
+		for _, s := range secretParams {
+			if name == s {
+				secret = true
+			}
+		}
+		if !secret {
+			kept = append(kept, param)
+		}
+	}
+	if len(kept) == 0 {
+		return path
+	}
+	return path + "?" + strings.Join(kept, "&")
+}
@@ -0,0 +1,101 @@
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Replayer is an http.RoundTripper answering GitLab requests from recorded
+// interactions, without network access. A request matches the interaction
+// with its method and endpoint, query string included; failing that, an
+// interaction for the same path without a query string. Requests without a
+// match get a 404.
+type Replayer struct {
+	root         string
+	interactions map[string]Interaction
+}
+
+// NewReplayer returns a Replayer for the GitLab at baseURL. Later
+// interactions for the same request replace earlier ones.
+func NewReplayer(baseURL string, interactions []Interaction) (*Replayer, error) {
+	root, err := apiPath(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replayer{root: root, interactions: map[string]Interaction{}}
+	for _, interaction := range interactions {
+		interaction.Method = strings.ToUpper(interaction.Method)
+		if interaction.Method == "" {
+			interaction.Method = http.MethodGet
+		}
+		r.interactions[interaction.key()] = interaction
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	endpoint := endpoint(r.root, req.URL)
+	interaction, ok := r.match(req.Method, endpoint)
+	if !ok {
+		message, _ := json.Marshal(map[string]string{
+			"message": fmt.Sprintf("vcr: no recorded interaction for %s %s", req.Method, endpoint),
+		})
+		interaction = Interaction{Status: http.StatusNotFound, Body: message}
+	}
+
+	header := http.Header{}
+	for name, value := range interaction.Headers {
+		header.Set(name, value)
+	}
+	if interaction.Pagination != nil {
+		setPagination(header, *interaction.Pagination)
+	}
+	body := []byte(interaction.Text)
+	if len(interaction.Body) > 0 {
+		body = interaction.Body
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", "application/json")
+		}
+	}
+	status := interaction.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// match finds the interaction for a request.
+func (r *Replayer) match(method, endpoint string) (Interaction, bool) {
+	if interaction, ok := r.interactions[method+" "+endpoint]; ok {
+		return interaction, true
+	}
+	if path, _, found := strings.Cut(endpoint, "?"); found {
+		interaction, ok := r.interactions[method+" "+path]
+		return interaction, ok
+	}
+	return Interaction{}, false
+}
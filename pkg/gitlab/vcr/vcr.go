@@ -0,0 +1,154 @@
+// Package vcr records GitLab API traffic to cassette files and replays it, so
+// tests cover the responses of a real GitLab without credentials.
+//
+// A Recorder is an http.RoundTripper passing requests on to GitLab and
+// saving a sanitized copy of each response; a Replayer answers requests from
+// a cassette without network access. Both plug into gitlab.WithHTTPClient:
+//
+//	recorder, err := vcr.NewRecorder("cassette.json", baseURL, http.DefaultTransport, nil)
+//	client := gitlab.NewClient(baseURL, token, gitlab.WithHTTPClient(&http.Client{Transport: recorder}))
+//
+// A cassette is a JSON array of interactions in the fixture format of
+// gitlabtest, so it can also be loaded with gitlabtest's LoadFixtures.
+package vcr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+// Interaction is a recorded request and its response. Endpoint is relative to
+// the API root (e.g., /projects/42?statistics=true); requests outside the API,
+// such as web paths, keep their full path.
+type Interaction struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	// Status is the HTTP status code; 0 means 200.
+	Status int `json:"status,omitempty"`
+	// Body is the response body when it is JSON, Text otherwise.
+	Body json.RawMessage `json:"body,omitempty"`
+	Text string          `json:"text,omitempty"`
+	// Headers are the Content-Type and Content-Disposition of the response.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Pagination holds the X-Page, X-Total and related headers.
+	Pagination *gitlab.PaginationInfo `json:"pagination,omitempty"`
+}
+
+// key identifies the request of an interaction.
+func (i Interaction) key() string {
+	return i.Method + " " + i.Endpoint
+}
+
+// Load reads a cassette file.
+func Load(path string) ([]Interaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes a cassette.
+func Parse(data []byte) ([]Interaction, error) {
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("invalid cassette: %w", err)
+	}
+	return interactions, nil
+}
+
+// Save writes a cassette file, replacing it atomically.
+func Save(path string, interactions []Interaction) error {
+	if interactions == nil {
+		interactions = []Interaction{}
+	}
+	data, err := json.MarshalIndent(interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cassette-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// apiPath returns the escaped path of the API root of baseURL, e.g. /api/v4
+// or /gitlab/api/v4.
+func apiPath(baseURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid GitLab URL %q: %w", baseURL, err)
+	}
+	path := u.EscapedPath()
+	if !strings.HasSuffix(path, "/api/v4") {
+		path += "/api/v4"
+	}
+	return path, nil
+}
+
+// endpoint returns the endpoint of a request to the API rooted at root.
+func endpoint(root string, u *url.URL) string {
+	path, ok := strings.CutPrefix(u.EscapedPath(), root)
+	if !ok {
+		// Web paths (e.g. uploads) are outside the API
+		path = u.EscapedPath()
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
+
+// parsePagination returns the pagination headers of a response, or nil
+// when it has none.
+func parsePagination(header http.Header) *gitlab.PaginationInfo {
+	if header.Get("X-Page") == "" && header.Get("X-Total-Pages") == "" {
+		return nil
+	}
+	atoi := func(name string) int {
+		n, _ := strconv.Atoi(header.Get(name))
+		return n
+	}
+	return &gitlab.PaginationInfo{
+		Page:       atoi("X-Page"),
+		PerPage:    atoi("X-Per-Page"),
+		Total:      atoi("X-Total"),
+		TotalPages: atoi("X-Total-Pages"),
+		NextPage:   atoi("X-Next-Page"),
+		PrevPage:   atoi("X-Prev-Page"),
+	}
+}
+
+// setPagination sets the pagination headers of a response.
+func setPagination(header http.Header, info gitlab.PaginationInfo) {
+	header.Set("X-Page", strconv.Itoa(info.Page))
+	header.Set("X-Per-Page", strconv.Itoa(info.PerPage))
+	header.Set("X-Total", strconv.Itoa(info.Total))
+	header.Set("X-Total-Pages", strconv.Itoa(info.TotalPages))
+	if info.NextPage > 0 {
+		header.Set("X-Next-Page", strconv.Itoa(info.NextPage))
+	}
+	if info.PrevPage > 0 {
+		header.Set("X-Prev-Page", strconv.Itoa(info.PrevPage))
+	}
+}
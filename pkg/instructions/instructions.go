@@ -7,6 +7,7 @@ package instructions
 
 import (
 	_ "embed"
+	"fmt"
 	"strings"
 )
 
@@ -24,6 +25,26 @@ type EnabledFeatures struct {
 	Pipelines  bool
 	Milestones bool
 	Wiki       bool
+
+	// Deployment describes this particular server instance. When set, a
+	// "This Deployment" section is appended to the instructions.
+	Deployment *Deployment
+}
+
+// ToolGroup is a named group of enabled tools.
+type ToolGroup struct {
+	Name  string
+	Tools []string
+}
+
+// Deployment holds the deployment-specific settings reported to clients.
+type Deployment struct {
+	ToolGroups        []ToolGroup
+	ReadOnly          bool
+	Demo              bool
+	DefaultNamespaces []string
+	DefaultProjectID  string
+	AllowedProjectIDs []string
 }
 
 // Generate creates the full instructions string based on enabled features.
@@ -40,9 +61,64 @@ func Generate(features EnabledFeatures) string {
 		parts = append(parts, strings.TrimSpace(terraformInstructions))
 	}
 
+	if features.Deployment != nil {
+		parts = append(parts, generateDeployment(features, features.Deployment))
+	}
+
 	return strings.Join(parts, "\n\n")
 }
 
+// generateDeployment renders the "This Deployment" section.
+func generateDeployment(features EnabledFeatures, d *Deployment) string {
+	var sb strings.Builder
+	sb.WriteString("## This Deployment\n\n")
+
+	if d.Demo {
+		sb.WriteString("- **Demo mode**: enabled. All GitLab data is synthetic: the `acme` group with the `acme/payments-api` and `acme/storefront` projects. Requests for anything else return 404, and changes are rejected.\n")
+	}
+	if d.ReadOnly {
+		sb.WriteString("- **Read-only mode**: enabled. Do not attempt create, update, delete or merge operations.\n")
+	} else {
+		sb.WriteString("- **Read-only mode**: disabled\n")
+	}
+	switch {
+	case len(d.DefaultNamespaces) == 1:
+		sb.WriteString(fmt.Sprintf("- **Default namespace**: `%s` (used when listing or creating projects without a namespace)\n", d.DefaultNamespaces[0]))
+	case len(d.DefaultNamespaces) > 1:
+		sb.WriteString(fmt.Sprintf("- **Default namespaces**: `%s` (searched in order, with subgroups, when listing projects without a namespace; the first is used when creating projects)\n", strings.Join(d.DefaultNamespaces, "`, `")))
+	}
+	if d.DefaultProjectID != "" {
+		sb.WriteString(fmt.Sprintf("- **Default project**: `%s` (used when `project_id` is omitted; `set_default_project` changes it for this session)\n", d.DefaultProjectID))
+	}
+	if len(d.AllowedProjectIDs) > 0 {
+		sb.WriteString(fmt.Sprintf("- **Project allowlist**: `%s`. Requests for other projects will be rejected.\n", strings.Join(d.AllowedProjectIDs, "`, `")))
+	}
+
+	var disabled []string
+	if !features.Pipelines {
+		disabled = append(disabled, "pipelines (USE_PIPELINE)")
+	}
+	if !features.Milestones {
+		disabled = append(disabled, "milestones (USE_MILESTONE)")
+	}
+	if !features.Wiki {
+		disabled = append(disabled, "wiki (USE_GITLAB_WIKI)")
+	}
+	if len(disabled) > 0 {
+		sb.WriteString(fmt.Sprintf("- **Disabled tool groups**: %s\n", strings.Join(disabled, ", ")))
+	}
+
+	if len(d.ToolGroups) > 0 {
+		sb.WriteString("\n### Enabled Tools\n\n")
+		sb.WriteString("| Group | Tools |\n|-------|-------|\n")
+		for _, group := range d.ToolGroups {
+			sb.WriteString(fmt.Sprintf("| %s | `%s` |\n", group.Name, strings.Join(group.Tools, "`, `")))
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
 // GenerateAll returns instructions with all features enabled.
 // Useful for documentation generation or when feature flags aren't relevant.
 func GenerateAll() string {
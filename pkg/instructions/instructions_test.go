@@ -65,3 +65,45 @@ func TestGenerate_NotEmpty(t *testing.T) {
 		t.Errorf("Expected instructions to be at least 100 chars, got %d", len(result))
 	}
 }
+
+func TestGenerate_Deployment(t *testing.T) {
+	result := Generate(EnabledFeatures{
+		Pipelines: true,
+		Deployment: &Deployment{
+			ReadOnly:          true,
+			Demo:              true,
+			DefaultNamespaces: []string{"my-group"},
+			AllowedProjectIDs: []string{"42", "my-group/app"},
+			ToolGroups: []ToolGroup{
+				{Name: "projects", Tools: []string{"get_project", "list_projects"}},
+			},
+		},
+	})
+
+	expected := []string{
+		"## This Deployment",
+		"**Read-only mode**: enabled",
+		"**Demo mode**: enabled",
+		"`my-group`",
+		"`42`, `my-group/app`",
+		"milestones (USE_MILESTONE)",
+		"| projects | `get_project`, `list_projects` |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected deployment instructions to contain %q", want)
+		}
+	}
+
+	if strings.Contains(result, "pipelines (USE_PIPELINE)") {
+		t.Error("Expected enabled pipelines not to be listed as disabled")
+	}
+}
+
+func TestGenerate_NoDeployment(t *testing.T) {
+	result := Generate(EnabledFeatures{})
+
+	if strings.Contains(result, "## This Deployment") {
+		t.Error("Expected no deployment section when Deployment is nil")
+	}
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuditEntry is one line of the audit log: a tool call that may have changed
+// something in GitLab.
+type AuditEntry struct {
+	Time               time.Time              `json:"time"`
+	RequestID          string                 `json:"request_id,omitempty"`
+	Tool               string                 `json:"tool"`
+	Project            string                 `json:"project,omitempty"`
+	Group              string                 `json:"group,omitempty"`
+	Arguments          map[string]interface{} `json:"arguments"`                     // as executed, after any policy rewrote them
+	RequestedArguments map[string]interface{} `json:"requested_arguments,omitempty"` // as sent, when a policy rewrote them
+	Principal          string                 `json:"principal,omitempty"`
+	Sudo               string                 `json:"sudo,omitempty"`
+	Outcome            string                 `json:"outcome"` // ok or error
+	Error              string                 `json:"error,omitempty"`
+	Status             int                    `json:"gitlab_status,omitempty"`
+	Requests           []AuditRequest         `json:"gitlab_requests,omitempty"`
+	DurationMs         int64                  `json:"duration_ms"`
+}
+
+// AuditRequest is a GitLab request made during an audited tool call.
+type AuditRequest struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	Status   int    `json:"status"`
+}
+
+// AuditLog appends AuditEntry values as JSON lines to a file, separately from
+// the debug log so that it is not affected by the log level or rotation.
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+	path string
+}
+
+// OpenAuditLog opens (or creates) the audit log at path for appending. The
+// file is created readable by its owner only.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	return &AuditLog{file: file, path: path}, nil
+}
+
+// Path returns the file the audit log writes to.
+func (a *AuditLog) Path() string {
+	return a.path
+}
+
+// Write appends an entry as a single line.
+func (a *AuditLog) Write(entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return fmt.Errorf("audit log %s is closed", a.path)
+	}
+	if _, err := a.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the audit log file.
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Close()
+	a.file = nil
+	return err
+}
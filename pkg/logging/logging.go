@@ -2,6 +2,9 @@ package logging
 
 import (
 	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
@@ -27,6 +30,8 @@ const (
 	SourceEnvironment ConfigSource = "environment"
 	// SourceFlag indicates the value came from a command-line flag
 	SourceFlag ConfigSource = "flag"
+	// SourceFile indicates the value came from the config file
+	SourceFile ConfigSource = "file"
 )
 
 const (
@@ -136,6 +141,13 @@ func ExpandPath(path string) string {
 	return path
 }
 
+// envFileKeys records the variables that were set from ~/.mcp_env, so that a
+// reload may update them without touching variables from the real environment.
+var (
+	envFileKeys   = make(map[string]bool)
+	envFileKeysMu sync.Mutex
+)
+
 // LoadEnvFile loads environment variables from ~/.mcp_env file.
 // The file format is simple KEY=VALUE pairs, one per line.
 // Lines starting with # are treated as comments.
@@ -143,6 +155,20 @@ func ExpandPath(path string) string {
 // Existing environment variables are NOT overwritten.
 // Returns the number of variables loaded and any error encountered.
 func LoadEnvFile() (int, error) {
+	return loadEnvFile(false)
+}
+
+// ReloadEnvFile re-reads ~/.mcp_env. Variables previously set from the file
+// are updated, or unset if they were removed from it; variables from the real
+// environment are still never overwritten.
+func ReloadEnvFile() (int, error) {
+	return loadEnvFile(true)
+}
+
+func loadEnvFile(reload bool) (int, error) {
+	envFileKeysMu.Lock()
+	defer envFileKeysMu.Unlock()
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return 0, nil // Silently skip if we can't get home dir
@@ -152,6 +178,12 @@ func LoadEnvFile() (int, error) {
 	file, err := os.Open(envFile)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if reload {
+				for key := range envFileKeys {
+					os.Unsetenv(key)
+					delete(envFileKeys, key)
+				}
+			}
 			return 0, nil // File doesn't exist, that's fine
 		}
 		return 0, fmt.Errorf("failed to open %s: %w", envFile, err)
@@ -159,6 +191,7 @@ func LoadEnvFile() (int, error) {
 	defer file.Close()
 
 	loaded := 0
+	seen := make(map[string]bool)
 	scanner := bufio.NewScanner(file)
 	lineNum := 0
 
@@ -193,9 +226,11 @@ func LoadEnvFile() (int, error) {
 			}
 		}
 
-		// Only set if not already set in environment
-		if os.Getenv(key) == "" {
+		// Only set if not already set in environment (or previously set from this file)
+		seen[key] = true
+		if os.Getenv(key) == "" || (reload && envFileKeys[key]) {
 			os.Setenv(key, value)
+			envFileKeys[key] = true
 			loaded++
 		}
 	}
@@ -204,6 +239,16 @@ func LoadEnvFile() (int, error) {
 		return loaded, fmt.Errorf("error reading %s: %w", envFile, err)
 	}
 
+	// Drop variables that were removed from the file since the last load
+	if reload {
+		for key := range envFileKeys {
+			if !seen[key] {
+				os.Unsetenv(key)
+				delete(envFileKeys, key)
+			}
+		}
+	}
+
 	return loaded, nil
 }
 
@@ -226,8 +271,9 @@ func Init(cfg Config) error {
 	return initErr
 }
 
-// NewLogger creates a new Logger instance
-func NewLogger(cfg Config) (*Logger, error) {
+// ResolveLogDir returns the directory NewLogger writes to for cfg, with ~
+// expanded and the app subfolder applied.
+func ResolveLogDir(cfg Config) string {
 	if cfg.AppName == "" {
 		cfg.AppName = "go-mcp-gitlab"
 	}
@@ -242,6 +288,16 @@ func NewLogger(cfg Config) (*Logger, error) {
 	if cfg.AddAppSubfolder {
 		logDir = filepath.Join(logDir, cfg.AppName)
 	}
+	return logDir
+}
+
+// NewLogger creates a new Logger instance
+func NewLogger(cfg Config) (*Logger, error) {
+	if cfg.AppName == "" {
+		cfg.AppName = "go-mcp-gitlab"
+	}
+
+	logDir := ResolveLogDir(cfg)
 
 	// Create log directory if it doesn't exist
 	if err := os.MkdirAll(logDir, 0755); err != nil {
@@ -287,8 +343,39 @@ func (l *Logger) SetLevel(level LogLevel) {
 	l.level = level
 }
 
+// requestIDKey is the context key for the per-request correlation ID
+type requestIDKey struct{}
+
+// WithRequestID returns a new context carrying the request correlation ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request correlation ID stored in ctx, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewRequestID generates a random 16-character hex request correlation ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
 // log writes a log entry if the level is enabled
 func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
+	l.logWithRequestID(level, "", format, args...)
+}
+
+// logWithRequestID writes a log entry tagged with request_id when one is provided
+func (l *Logger) logWithRequestID(level LogLevel, requestID string, format string, args ...interface{}) {
 	if l == nil || level > l.level {
 		return
 	}
@@ -298,6 +385,10 @@ func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
 
 	timestamp := time.Now().Format("2006-01-02T15:04:05.000Z07:00")
 	message := fmt.Sprintf(format, args...)
+	if requestID != "" {
+		l.logger.Printf("[%s] [%s] request_id=%s %s", timestamp, level.String(), requestID, message)
+		return
+	}
 	l.logger.Printf("[%s] [%s] %s", timestamp, level.String(), message)
 }
 
@@ -326,14 +417,39 @@ func (l *Logger) Debug(format string, args ...interface{}) {
 	l.log(LevelDebug, format, args...)
 }
 
+// ErrorContext logs an error message tagged with the request ID from ctx
+func (l *Logger) ErrorContext(ctx context.Context, format string, args ...interface{}) {
+	l.logWithRequestID(LevelError, RequestIDFromContext(ctx), format, args...)
+}
+
+// WarnContext logs a warning message tagged with the request ID from ctx
+func (l *Logger) WarnContext(ctx context.Context, format string, args ...interface{}) {
+	l.logWithRequestID(LevelWarn, RequestIDFromContext(ctx), format, args...)
+}
+
+// InfoContext logs an informational message tagged with the request ID from ctx
+func (l *Logger) InfoContext(ctx context.Context, format string, args ...interface{}) {
+	l.logWithRequestID(LevelInfo, RequestIDFromContext(ctx), format, args...)
+}
+
+// AccessContext logs API access operations tagged with the request ID from ctx
+func (l *Logger) AccessContext(ctx context.Context, format string, args ...interface{}) {
+	l.logWithRequestID(LevelAccess, RequestIDFromContext(ctx), format, args...)
+}
+
+// DebugContext logs debug information tagged with the request ID from ctx
+func (l *Logger) DebugContext(ctx context.Context, format string, args ...interface{}) {
+	l.logWithRequestID(LevelDebug, RequestIDFromContext(ctx), format, args...)
+}
+
 // ToolCall logs an MCP tool invocation
-func (l *Logger) ToolCall(toolName string, args map[string]interface{}) {
+func (l *Logger) ToolCall(ctx context.Context, toolName string, args map[string]interface{}) {
 	// Log tool name and argument keys only, never values that might contain sensitive data
 	argKeys := make([]string, 0, len(args))
 	for k := range args {
 		argKeys = append(argKeys, k)
 	}
-	l.Info("TOOL_CALL tool=%q args=%v", toolName, argKeys)
+	l.InfoContext(ctx, "TOOL_CALL tool=%q args=%v", toolName, argKeys)
 }
 
 // APICall logs a GitLab API call with method, endpoint, status code, and optional error
@@ -583,13 +699,13 @@ func truncateBody(body string, maxLen int) string {
 }
 
 // LogHTTPRequest logs HTTP request details at DEBUG level with secret redaction
-func (l *Logger) LogHTTPRequest(context string, req *HTTPRequestInfo, secrets ...string) {
+func (l *Logger) LogHTTPRequest(ctx context.Context, logContext string, req *HTTPRequestInfo, secrets ...string) {
 	if l == nil || LevelDebug > l.level {
 		return
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("HTTP_REQUEST context=%q", context))
+	sb.WriteString(fmt.Sprintf("HTTP_REQUEST context=%q", logContext))
 
 	if req != nil {
 		sb.WriteString(fmt.Sprintf(" method=%s url=%q", req.Method, req.URL))
@@ -604,17 +720,17 @@ func (l *Logger) LogHTTPRequest(context string, req *HTTPRequestInfo, secrets ..
 		}
 	}
 
-	l.Debug(sb.String())
+	l.DebugContext(ctx, "%s", sb.String())
 }
 
 // LogHTTPResponse logs HTTP response details at DEBUG level with secret redaction
-func (l *Logger) LogHTTPResponse(context string, resp *HTTPResponseInfo, duration time.Duration, secrets ...string) {
+func (l *Logger) LogHTTPResponse(ctx context.Context, logContext string, resp *HTTPResponseInfo, duration time.Duration, secrets ...string) {
 	if l == nil || LevelDebug > l.level {
 		return
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("HTTP_RESPONSE context=%q", context))
+	sb.WriteString(fmt.Sprintf("HTTP_RESPONSE context=%q", logContext))
 
 	if resp != nil {
 		sb.WriteString(fmt.Sprintf(" status=%d", resp.StatusCode))
@@ -630,17 +746,17 @@ func (l *Logger) LogHTTPResponse(context string, resp *HTTPResponseInfo, duratio
 	}
 
 	sb.WriteString(fmt.Sprintf(" duration=%s", duration))
-	l.Debug(sb.String())
+	l.DebugContext(ctx, "%s", sb.String())
 }
 
 // LogHTTPError logs detailed HTTP error information with secret redaction
-func (l *Logger) LogHTTPError(context string, req *HTTPRequestInfo, resp *HTTPResponseInfo, err error, secrets ...string) {
+func (l *Logger) LogHTTPError(ctx context.Context, logContext string, req *HTTPRequestInfo, resp *HTTPResponseInfo, err error, secrets ...string) {
 	if l == nil {
 		return
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("HTTP_ERROR context=%q", context))
+	sb.WriteString(fmt.Sprintf("HTTP_ERROR context=%q", logContext))
 
 	if req != nil {
 		sb.WriteString(fmt.Sprintf(" request.method=%s request.url=%q", req.Method, req.URL))
@@ -673,7 +789,7 @@ func (l *Logger) LogHTTPError(context string, req *HTTPRequestInfo, resp *HTTPRe
 		sb.WriteString(fmt.Sprintf(" error=%q", sanitizedErr))
 	}
 
-	l.Error(sb.String())
+	l.ErrorContext(ctx, "%s", sb.String())
 }
 
 // Global convenience functions that use the default logger
@@ -714,9 +830,9 @@ func Debug(format string, args ...interface{}) {
 }
 
 // ToolCall logs tool call using the default logger
-func ToolCall(toolName string, args map[string]interface{}) {
+func ToolCall(ctx context.Context, toolName string, args map[string]interface{}) {
 	if defaultLogger != nil {
-		defaultLogger.ToolCall(toolName, args)
+		defaultLogger.ToolCall(ctx, toolName, args)
 	}
 }
 
@@ -742,23 +858,23 @@ func APIResponse(endpoint string, statusCode int, duration time.Duration) {
 }
 
 // LogHTTPRequest logs HTTP request using the default logger
-func LogHTTPRequest(context string, req *HTTPRequestInfo, secrets ...string) {
+func LogHTTPRequest(ctx context.Context, logContext string, req *HTTPRequestInfo, secrets ...string) {
 	if defaultLogger != nil {
-		defaultLogger.LogHTTPRequest(context, req, secrets...)
+		defaultLogger.LogHTTPRequest(ctx, logContext, req, secrets...)
 	}
 }
 
 // LogHTTPResponse logs HTTP response using the default logger
-func LogHTTPResponse(context string, resp *HTTPResponseInfo, duration time.Duration, secrets ...string) {
+func LogHTTPResponse(ctx context.Context, logContext string, resp *HTTPResponseInfo, duration time.Duration, secrets ...string) {
 	if defaultLogger != nil {
-		defaultLogger.LogHTTPResponse(context, resp, duration, secrets...)
+		defaultLogger.LogHTTPResponse(ctx, logContext, resp, duration, secrets...)
 	}
 }
 
 // LogHTTPError logs HTTP error using the default logger
-func LogHTTPError(context string, req *HTTPRequestInfo, resp *HTTPResponseInfo, err error, secrets ...string) {
+func LogHTTPError(ctx context.Context, logContext string, req *HTTPRequestInfo, resp *HTTPResponseInfo, err error, secrets ...string) {
 	if defaultLogger != nil {
-		defaultLogger.LogHTTPError(context, req, resp, err, secrets...)
+		defaultLogger.LogHTTPError(ctx, logContext, req, resp, err, secrets...)
 	}
 }
 
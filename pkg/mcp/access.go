@@ -0,0 +1,31 @@
+package mcp
+
+import "context"
+
+// ToolAccess decides whether the caller of a request may see and call a tool,
+// e.g. based on the authenticated principal in ctx.
+type ToolAccess func(ctx context.Context, tool Tool) bool
+
+// SetToolAccess sets the per-request tool access check; nil allows every
+// registered tool. Tools the caller may not access are left out of tools/list
+// and reported as unknown by tools/call, so their names reveal nothing.
+func (s *Server) SetToolAccess(access ToolAccess) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolAccess = access
+}
+
+// visibleTools returns the registered tools the caller may access. The caller
+// must hold s.mu.
+func (s *Server) visibleTools(ctx context.Context) []Tool {
+	if s.toolAccess == nil {
+		return s.tools
+	}
+	visible := make([]Tool, 0, len(s.tools))
+	for _, tool := range s.tools {
+		if s.toolAccess(ctx, tool) {
+			visible = append(visible, tool)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+)
+
+// RequestCancelled is the JSON-RPC error code returned for cancelled requests
+// (same value as LSP's RequestCancelled).
+const RequestCancelled = -32800
+
+// requestKey returns a map key for a JSON-RPC request ID from the caller in
+// ctx. Request IDs are only unique within a session, so the key includes the
+// session and principal; the type is included so that the number 1 and the
+// string "1" do not collide.
+func requestKey(ctx context.Context, id interface{}) string {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	return fmt.Sprintf("%q|%q|%T:%v", SessionIDFromContext(ctx), principal, id, id)
+}
+
+// inflightRequest is a running tools/call that can be cancelled.
+type inflightRequest struct {
+	cancel context.CancelCauseFunc
+}
+
+// trackRequest registers an in-flight request and returns a context that is
+// cancelled by cancelRequest. The returned func must be called when the request completes.
+func (s *Server) trackRequest(ctx context.Context, id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	key := requestKey(ctx, id)
+	req := &inflightRequest{cancel: cancel}
+
+	s.inflightMu.Lock()
+	if s.inflight == nil {
+		s.inflight = make(map[string][]*inflightRequest)
+	}
+	s.inflight[key] = append(s.inflight[key], req)
+	s.inflightMu.Unlock()
+
+	return ctx, func() {
+		s.inflightMu.Lock()
+		// A client may reuse an ID while the first call is still running;
+		// only this request's entry is removed
+		reqs := s.inflight[key]
+		for i, r := range reqs {
+			if r == req {
+				reqs = append(reqs[:i:i], reqs[i+1:]...)
+				break
+			}
+		}
+		if len(reqs) == 0 {
+			delete(s.inflight, key)
+		} else {
+			s.inflight[key] = reqs
+		}
+		s.inflightMu.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancelRequest cancels the caller's in-flight requests with the given ID.
+// Unknown or already completed IDs are ignored, as the spec allows
+// cancellations to race completion.
+func (s *Server) cancelRequest(ctx context.Context, id interface{}, reason string) {
+	s.inflightMu.Lock()
+	reqs := append([]*inflightRequest(nil), s.inflight[requestKey(ctx, id)]...)
+	s.inflightMu.Unlock()
+	if len(reqs) == 0 {
+		return
+	}
+	if reason == "" {
+		reason = "cancelled by client"
+	}
+	for _, r := range reqs {
+		r.cancel(fmt.Errorf("%w: %s", context.Canceled, reason))
+	}
+}
+
+// handleCancellation processes notifications/cancelled ({"requestId", "reason"})
+// and the LSP-style $/cancelRequest ({"id"}). Only requests of the session and
+// principal in ctx can be cancelled.
+func (s *Server) handleCancellation(ctx context.Context, params interface{}) {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return
+	}
+	id, ok := paramsMap["requestId"]
+	if !ok {
+		id, ok = paramsMap["id"]
+	}
+	if !ok || id == nil {
+		return
+	}
+	reason, _ := paramsMap["reason"].(string)
+	s.cancelRequest(ctx, id, reason)
+}
+
+// cancelledError builds the JSON-RPC error for a request cancelled via ctx.
+func cancelledError(ctx context.Context, requestID string) *JSONRPCError {
+	reason := "Request cancelled"
+	if cause := context.Cause(ctx); cause != nil {
+		reason = fmt.Sprintf("Request cancelled: %v", cause)
+	}
+	return &JSONRPCError{
+		Code:    RequestCancelled,
+		Message: reason,
+		Data:    map[string]string{"request_id": requestID},
+	}
+}
+
+// isToolCall reports whether a raw stdio message is a tools/call request.
+func isToolCall(data []byte) bool {
+	var peek struct {
+		ID     interface{} `json:"id"`
+		Method string      `json:"method"`
+	}
+	if err := json.Unmarshal(data, &peek); err != nil {
+		return false
+	}
+	return peek.ID != nil && peek.Method == "tools/call"
+}
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ServerBusy is the JSON-RPC error code returned when the tool call queue is
+// full. In HTTP mode the response is sent with status 429 and Retry-After.
+const ServerBusy = -32029
+
+// busyRetryAfterSeconds is the retry hint sent with ServerBusy errors.
+const busyRetryAfterSeconds = 1
+
+// toolPool bounds the number of concurrently running tool calls. Calls beyond
+// the limit wait in a queue of bounded depth; calls beyond that are rejected.
+type toolPool struct {
+	slots    chan struct{}
+	maxQueue int64
+	queued   atomic.Int64
+}
+
+// SetConcurrencyLimit allows at most maxConcurrent tool calls to run at once,
+// with up to maxQueued more waiting for a slot. maxConcurrent <= 0 removes the
+// limit. Calls already running or queued are not affected.
+func (s *Server) SetConcurrencyLimit(maxConcurrent, maxQueued int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if maxConcurrent <= 0 {
+		s.pool = nil
+		return
+	}
+	if maxQueued < 0 {
+		maxQueued = 0
+	}
+	s.pool = &toolPool{
+		slots:    make(chan struct{}, maxConcurrent),
+		maxQueue: int64(maxQueued),
+	}
+}
+
+// acquireToolSlot waits for a free slot and returns the func that releases it.
+// It fails with ServerBusy when the queue is full, or with the context error
+// if ctx ends while waiting.
+func (s *Server) acquireToolSlot(ctx context.Context, requestID string) (func(), *JSONRPCError) {
+	s.mu.RLock()
+	pool := s.pool
+	s.mu.RUnlock()
+	if pool == nil {
+		return func() {}, nil
+	}
+
+	// Fast path: a slot is free
+	select {
+	case pool.slots <- struct{}{}:
+		return pool.release, nil
+	default:
+	}
+
+	if pool.queued.Add(1) > pool.maxQueue {
+		pool.queued.Add(-1)
+		return nil, &JSONRPCError{
+			Code:    ServerBusy,
+			Message: "Server busy: too many concurrent tool calls, retry later",
+			Data: map[string]interface{}{
+				"max_concurrent":      cap(pool.slots),
+				"max_queued":          pool.maxQueue,
+				"retry_after_seconds": busyRetryAfterSeconds,
+				"request_id":          requestID,
+			},
+		}
+	}
+	defer pool.queued.Add(-1)
+
+	select {
+	case pool.slots <- struct{}{}:
+		return pool.release, nil
+	case <-ctx.Done():
+		return nil, cancelledError(ctx, requestID)
+	}
+}
+
+func (p *toolPool) release() {
+	<-p.slots
+}
+
+// toolSlot is a concurrency slot shared by a tool call and the handler
+// goroutine a timeout leaves running, released once both have finished.
+// A nil *toolSlot holds nothing.
+type toolSlot struct {
+	refs    atomic.Int32
+	release func()
+}
+
+// newToolSlot returns a slot held by one owner.
+func newToolSlot(release func()) *toolSlot {
+	slot := &toolSlot{release: release}
+	slot.refs.Store(1)
+	return slot
+}
+
+// hold adds an owner to the slot.
+func (t *toolSlot) hold() {
+	if t != nil {
+		t.refs.Add(1)
+	}
+}
+
+// done removes an owner, releasing the slot after the last.
+func (t *toolSlot) done() {
+	if t != nil && t.refs.Add(-1) == 0 {
+		t.release()
+	}
+}
+
+// toolSlotKey is the context key of the call's toolSlot.
+type toolSlotKey struct{}
+
+// withToolSlot returns a context carrying slot.
+func withToolSlot(ctx context.Context, slot *toolSlot) context.Context {
+	return context.WithValue(ctx, toolSlotKey{}, slot)
+}
+
+// toolSlotFromContext returns the slot of the tool call, or nil.
+func toolSlotFromContext(ctx context.Context) *toolSlot {
+	slot, _ := ctx.Value(toolSlotKey{}).(*toolSlot)
+	return slot
+}
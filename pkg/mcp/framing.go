@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// maxMessageSize is the largest stdio message accepted, in either framing.
+const maxMessageSize = 10 * 1024 * 1024
+
+// stdioSplitter provides a bufio.SplitFunc (split) that accepts both
+// newline-delimited JSON and LSP-style framing ("Content-Length: N\r\n\r\n"
+// followed by N bytes of JSON). The framing is detected per message, so
+// clients may use either.
+type stdioSplitter struct {
+	// sawHeaders is set once a Content-Length framed message has been read,
+	// after which responses are framed the same way.
+	sawHeaders atomic.Bool
+}
+
+func (f *stdioSplitter) split(data []byte, atEOF bool) (int, []byte, error) {
+	// Skip blank lines and whitespace between messages
+	start := 0
+	for start < len(data) && isFramingSpace(data[start]) {
+		start++
+	}
+	if start == len(data) {
+		if atEOF {
+			return len(data), nil, nil
+		}
+		return start, nil, nil
+	}
+
+	if data[start] == '{' || data[start] == '[' {
+		return splitLine(data, start, atEOF)
+	}
+	return f.splitHeaders(data, start, atEOF)
+}
+
+// splitLine returns one newline-delimited message.
+func splitLine(data []byte, start int, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexByte(data[start:], '\n'); i >= 0 {
+		return start + i + 1, bytes.TrimRight(data[start:start+i], "\r"), nil
+	}
+	if atEOF {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
+
+// splitHeaders returns the body of one Content-Length framed message.
+func (f *stdioSplitter) splitHeaders(data []byte, start int, atEOF bool) (int, []byte, error) {
+	headerEnd, sepLen := bytes.Index(data[start:], []byte("\r\n\r\n")), 4
+	if lf := bytes.Index(data[start:], []byte("\n\n")); lf >= 0 && (headerEnd < 0 || lf < headerEnd) {
+		headerEnd, sepLen = lf, 2
+	}
+	if headerEnd < 0 {
+		if atEOF {
+			return 0, nil, fmt.Errorf("incomplete message headers")
+		}
+		return start, nil, nil
+	}
+
+	length := -1
+	for _, line := range strings.Split(string(data[start:start+headerEnd]), "\n") {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			return 0, nil, fmt.Errorf("invalid message header %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return 0, nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+			length = n
+		}
+	}
+	if length < 0 {
+		return 0, nil, fmt.Errorf("message headers missing Content-Length")
+	}
+	if length > maxMessageSize {
+		return 0, nil, fmt.Errorf("message of %d bytes exceeds limit of %d", length, maxMessageSize)
+	}
+
+	bodyStart := start + headerEnd + sepLen
+	if len(data) < bodyStart+length {
+		if atEOF {
+			return 0, nil, fmt.Errorf("incomplete message body")
+		}
+		return start, nil, nil
+	}
+
+	f.sawHeaders.Store(true)
+	return bodyStart + length, data[bodyStart : bodyStart+length], nil
+}
+
+func isFramingSpace(b byte) bool {
+	return b == '\n' || b == '\r' || b == ' ' || b == '\t'
+}
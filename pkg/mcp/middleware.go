@@ -0,0 +1,34 @@
+package mcp
+
+import "context"
+
+// ToolMiddleware decorates a tool as it is registered. It may change the tool
+// definition (e.g. add common input properties) and wrap its handler.
+type ToolMiddleware func(tool Tool, handler ToolHandler) (Tool, ToolHandler)
+
+// UseToolMiddleware appends middleware applied by RegisterTool to tools
+// registered afterwards. Middleware added first is outermost.
+func (s *Server) UseToolMiddleware(middleware ToolMiddleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.middleware = append(s.middleware, middleware)
+}
+
+// applyMiddleware wraps a tool with the registered middleware. The caller must
+// hold s.mu.
+func (s *Server) applyMiddleware(tool Tool, handler ToolHandler) (Tool, ToolHandler) {
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		tool, handler = s.middleware[i](tool, handler)
+	}
+	return tool, handler
+}
+
+// SetContextFunc sets a function deriving the context of every request before
+// it is handled, e.g. to attach the dependencies of the tool handlers
+// registered on this server. It replaces any earlier function; nil leaves the
+// context unchanged.
+func (s *Server) SetContextFunc(f func(context.Context) context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contextFunc = f
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+)
+
+// ProgressNotificationParams is the payload of a notifications/progress message.
+type ProgressNotificationParams struct {
+	ProgressToken interface{} `json:"progressToken"`
+	Progress      float64     `json:"progress"`
+	Total         float64     `json:"total,omitempty"`
+	Message       string      `json:"message,omitempty"`
+}
+
+// progressKey is the context key for the progress reporter of a tool call.
+type progressKey struct{}
+
+// progressReporter sends progress notifications for a single request.
+type progressReporter struct {
+	server *Server
+	token  interface{}
+
+	mu   sync.Mutex
+	last float64
+}
+
+// withProgress attaches a progress reporter for token to ctx.
+func withProgress(ctx context.Context, s *Server, token interface{}) context.Context {
+	return context.WithValue(ctx, progressKey{}, &progressReporter{server: s, token: token, last: -1})
+}
+
+// ReportProgress sends a notifications/progress message for the tool call
+// carried by ctx. It is a no-op when the client did not supply a progressToken
+// or the transport cannot deliver notifications (HTTP mode). total may be 0 if
+// unknown. Per the MCP spec progress must increase, so values that do not
+// exceed the previously reported progress are dropped.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	reporter, ok := ctx.Value(progressKey{}).(*progressReporter)
+	if !ok {
+		return
+	}
+
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if progress <= reporter.last {
+		return
+	}
+	reporter.last = progress
+
+	reporter.server.sendNotification("notifications/progress", ProgressNotificationParams{
+		ProgressToken: reporter.token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	})
+}
+
+// progressToken extracts params._meta.progressToken from a request, if present.
+func progressToken(params map[string]interface{}) interface{} {
+	meta, ok := params["_meta"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	switch token := meta["progressToken"].(type) {
+	case string, float64:
+		return token
+	}
+	return nil
+}
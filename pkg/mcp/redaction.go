@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Redactor masks sensitive text, returning the result and the number of
+// replacements. *redact.Redactor implements it.
+type Redactor interface {
+	Redact(text string) (string, int)
+}
+
+// SetRedactor sets the redactor applied to every tool result before it is
+// returned to the client; nil disables result redaction.
+func (s *Server) SetRedactor(redactor Redactor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.redactor = redactor
+}
+
+// redactResult masks the text and structured content of a tool result in
+// place and returns the number of replacements. Image data is left alone.
+func redactResult(result *CallToolResult, redactor Redactor) int {
+	if result == nil || redactor == nil {
+		return 0
+	}
+
+	total := 0
+	for i, item := range result.Content {
+		if item.Text == "" {
+			continue
+		}
+		text, n := redactor.Redact(item.Text)
+		// A match spanning JSON syntax (e.g. a quote) would corrupt the
+		// document, so JSON is then redacted value by value instead
+		if n > 0 && json.Valid([]byte(item.Text)) && !json.Valid([]byte(text)) {
+			if value, err := decodeJSON([]byte(item.Text)); err == nil {
+				value, n = redactValue(value, redactor)
+				text = item.Text
+				if n > 0 {
+					text = encodeJSON(value)
+				}
+			}
+		}
+		result.Content[i].Text = text
+		total += n
+	}
+
+	if result.StructuredContent != nil {
+		data, err := json.Marshal(result.StructuredContent)
+		if err == nil {
+			if value, err := decodeJSON(data); err == nil {
+				var n int
+				value, n = redactValue(value, redactor)
+				if n > 0 {
+					result.StructuredContent = value
+					total += n
+				}
+			}
+		}
+	}
+	return total
+}
+
+// redactValue masks the strings of a decoded JSON value, including object keys.
+func redactValue(value interface{}, redactor Redactor) (interface{}, int) {
+	switch v := value.(type) {
+	case string:
+		return redactor.Redact(v)
+	case []interface{}:
+		total := 0
+		for i, item := range v {
+			var n int
+			v[i], n = redactValue(item, redactor)
+			total += n
+		}
+		return v, total
+	case map[string]interface{}:
+		total := 0
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			key, n := redactor.Redact(key)
+			item, m := redactValue(item, redactor)
+			redacted[key] = item
+			total += n + m
+		}
+		return redacted, total
+	}
+	return value, 0
+}
+
+// decodeJSON decodes a JSON document, keeping numbers exact.
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	return value, err
+}
+
+// encodeJSON encodes a value without escaping HTML characters.
+func encodeJSON(value interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// logRedactions records how many values were masked in a tool result.
+func (s *Server) logRedactions(ctx context.Context, name string, count int) {
+	s.mu.RLock()
+	logger := s.logger
+	s.mu.RUnlock()
+	if logger != nil {
+		logger.InfoContext(ctx, "Redacted %d value(s) from the result of %s", count, name)
+		return
+	}
+	s.Log("Redacted %d value(s) from the result of %s", count, name)
+}
@@ -2,30 +2,107 @@ package mcp
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
-// ToolHandler is a function that handles a tool call
-type ToolHandler func(arguments map[string]interface{}) (*CallToolResult, error)
+// RequestIDHeader carries the request correlation ID. In HTTP mode a valid
+// incoming value is reused, otherwise one is generated; either way it is echoed
+// back on the response and forwarded to GitLab.
+const RequestIDHeader = "X-Request-Id"
+
+// LatestProtocolVersion is the newest MCP protocol revision implemented by the server.
+// It adds outputSchema on tools and structuredContent in tool results.
+const LatestProtocolVersion = "2025-06-18"
+
+// supportedProtocolVersions lists the protocol revisions accepted during initialize, newest first.
+var supportedProtocolVersions = []string{LatestProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// negotiateProtocolVersion returns the client's requested version if supported,
+// otherwise the latest version, per the MCP lifecycle specification.
+func negotiateProtocolVersion(requested string) string {
+	for _, v := range supportedProtocolVersions {
+		if v == requested {
+			return v
+		}
+	}
+	return LatestProtocolVersion
+}
+
+// maxRequestIDLength bounds caller-supplied request IDs accepted from RequestIDHeader.
+const maxRequestIDLength = 128
+
+// ToolHandler is a function that handles a tool call.
+// The context carries the tool call span and is canceled when the request is abandoned.
+type ToolHandler func(ctx context.Context, arguments map[string]interface{}) (*CallToolResult, error)
+
+// HealthCheck performs a dependency check for /health?check=deep.
+// It returns details to include in the response and whether the dependency is healthy.
+type HealthCheck func(ctx context.Context) (details interface{}, healthy bool)
 
 // Server represents an MCP server
 type Server struct {
 	name         string
 	version      string
 	instructions string
+	metrics      http.Handler
+	healthCheck  HealthCheck
 	tools        []Tool
 	handlers     map[string]ToolHandler
 	mu           sync.RWMutex
 	stdin        io.Reader
 	stdout       io.Writer
 	stderr       io.Writer
+
+	// writeMu serializes messages written to stdout
+	writeMu sync.Mutex
+	// framing detects the stdio framing used by the client (guarded by writeMu)
+	framing *stdioSplitter
+	// notifications is true when the transport can deliver server-initiated
+	// notifications (stdio); the HTTP transport only returns responses
+	notifications bool
+
+	// inflight holds running tools/call requests, keyed by requestKey
+	inflight   map[string][]*inflightRequest
+	inflightMu sync.Mutex
+
+	// toolsPageSize limits tools per tools/list page (0 = unlimited)
+	toolsPageSize int
+	// toolFilter, when set, decides which tools RegisterTool accepts
+	toolFilter func(name string) bool
+	// middleware decorates tools as RegisterTool adds them
+	middleware []ToolMiddleware
+	// toolTimeout is the default tool execution limit, toolTimeouts per-tool overrides
+	toolTimeout  time.Duration
+	toolTimeouts map[string]time.Duration
+	// readOnlyTool reports which tools only read (nil = ReadOnlyHint)
+	readOnlyTool func(tool Tool) bool
+	// pool limits concurrent tool calls (nil = unlimited)
+	pool *toolPool
+	// logger receives server-side events such as tool timeouts (stderr if nil)
+	logger *logging.Logger
+	// redactor masks sensitive text in tool results (nil = none)
+	redactor Redactor
+	// toolAccess hides tools from callers per request (nil = all tools)
+	toolAccess ToolAccess
+	// contextFunc derives the context of every request (nil = unchanged)
+	contextFunc func(context.Context) context.Context
+	// stats collects tool usage statistics
+	stats *ToolStats
 }
 
 // NewServer creates a new MCP server
@@ -38,9 +115,16 @@ func NewServer(name, version string) *Server {
 		stdin:    os.Stdin,
 		stdout:   os.Stdout,
 		stderr:   os.Stderr,
+		stats:    NewToolStats(0),
 	}
 }
 
+// ToolStats returns the usage statistics of the tools called since the
+// server was created. Tool handlers reach them with ToolStatsFromContext.
+func (s *Server) ToolStats() *ToolStats {
+	return s.stats
+}
+
 // SetInstructions sets the server instructions that will be returned during initialization.
 // These instructions guide LLM clients on how to use the server's tools effectively.
 func (s *Server) SetInstructions(instructions string) {
@@ -49,28 +133,112 @@ func (s *Server) SetInstructions(instructions string) {
 	s.instructions = instructions
 }
 
+// SetMetricsHandler sets the handler served at /metrics in HTTP mode.
+// If no handler is set, /metrics is not exposed.
+func (s *Server) SetMetricsHandler(handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics = handler
+}
+
+// SetLogger sets the logger used for server-side events such as tool timeouts.
+func (s *Server) SetLogger(logger *logging.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger = logger
+}
+
+// SetHealthCheck sets the dependency check run by /health?check=deep in HTTP mode.
+// Plain /health requests never run the check.
+func (s *Server) SetHealthCheck(check HealthCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.healthCheck = check
+}
+
+// SetToolFilter sets a predicate consulted by RegisterTool; tools for which it
+// returns false are not registered. It applies to tools registered afterwards.
+func (s *Server) SetToolFilter(filter func(name string) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolFilter = filter
+}
+
 // RegisterTool registers a tool with its handler
 func (s *Server) RegisterTool(tool Tool, handler ToolHandler) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.toolFilter != nil && !s.toolFilter(tool.Name) {
+		return
+	}
+	tool, handler = s.applyMiddleware(tool, handler)
 	s.tools = append(s.tools, tool)
 	s.handlers[tool.Name] = handler
 }
 
+// ToolNames returns the names of registered tools in registration order.
+func (s *Server) ToolNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, len(s.tools))
+	for i, tool := range s.tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+// RunIO is Run over in and out instead of stdin and stdout.
+func (s *Server) RunIO(in io.Reader, out io.Writer) error {
+	s.writeMu.Lock()
+	s.stdin, s.stdout = in, out
+	s.writeMu.Unlock()
+	return s.Run()
+}
+
 // Run starts the server and processes requests from stdin
 func (s *Server) Run() error {
+	s.mu.Lock()
+	s.notifications = true
+	s.mu.Unlock()
+
+	framing := &stdioSplitter{}
+	s.writeMu.Lock()
+	s.framing = framing
+	s.writeMu.Unlock()
+
 	scanner := bufio.NewScanner(s.stdin)
-	// Increase buffer size for large messages
+	// Increase buffer size for large messages (plus room for framing headers)
 	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 10*1024*1024)
+	scanner.Buffer(buf, maxMessageSize+4096)
+	scanner.Split(framing.split)
+
+	// A stdio server has a single client, hence a single session
+	sessionCtx := WithSessionID(context.Background(), stdioSessionID)
+
+	// Tool calls run concurrently so that cancellation notifications for them
+	// can still be read; everything else is handled in order.
+	var wg sync.WaitGroup
+	defer wg.Wait()
 
 	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
+		// Copy the message: the scanner reuses its buffer and tool calls run concurrently
+		data := append([]byte(nil), scanner.Bytes()...)
+		if len(data) == 0 {
+			continue
+		}
+
+		if isToolCall(data) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if response := s.handleMessage(sessionCtx, data); response != nil {
+					s.sendResponse(response)
+				}
+			}()
 			continue
 		}
 
-		response := s.handleMessage([]byte(line))
+		response := s.handleMessage(sessionCtx, data)
 		if response != nil {
 			s.sendResponse(response)
 		}
@@ -91,21 +259,35 @@ func (s *Server) RunHTTP(addr string) error {
 // RunHTTPWithAuthorizer starts the server in HTTP mode with a custom authorizer.
 // If authorizer is nil, falls back to environment-based token validation.
 func (s *Server) RunHTTPWithAuthorizer(addr string, authorizer auth.Authorizer) error {
+	handler := s.HTTPHandler(authorizer)
+	if auth.IsAuthEnabled() || authorizer != nil {
+		fmt.Fprintf(s.stderr, "GitLab MCP Server running on HTTP at %s (authentication enabled)\n", addr)
+	} else {
+		fmt.Fprintf(s.stderr, "GitLab MCP Server running on HTTP at %s (authentication disabled)\n", addr)
+	}
+	return http.ListenAndServe(addr, handler)
+}
+
+// HTTPHandler returns the handler RunHTTPWithAuthorizer serves: JSON-RPC
+// messages POSTed to /, plus /health and /metrics. It lets tests and
+// embedding programs serve the MCP endpoint with their own http.Server.
+func (s *Server) HTTPHandler(authorizer auth.Authorizer) http.Handler {
 	mux := http.NewServeMux()
 
 	// Health check endpoint (no auth required)
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{
-			"status":  "ok",
-			"version": s.version,
-		})
-	})
+	mux.HandleFunc("/health", s.handleHealth)
+
+	// Metrics endpoint (no auth required, like /health)
+	s.mu.RLock()
+	metricsHandler := s.metrics
+	s.mu.RUnlock()
+	if metricsHandler != nil {
+		mux.Handle("/metrics", metricsHandler)
+	}
 
 	// MCP endpoint handler
 	mcpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip non-root paths (already handled by /health)
+		// Skip non-root paths (already handled by /health and /metrics)
 		if r.URL.Path != "/" {
 			http.NotFound(w, r)
 			return
@@ -128,23 +310,57 @@ func (s *Server) RunHTTPWithAuthorizer(addr string, authorizer auth.Authorizer)
 			return
 		}
 
+		r = withRequestID(w, r)
+		r = withSession(w, r, body)
+
 		// Handle the message with request context for header-based credentials
 		response := s.handleMessageWithContext(r, body)
 		if response != nil {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(response)
+			writeHTTPResponse(w, response)
 		}
 	})
 
 	// Apply auth middleware
 	mux.Handle("/", auth.AuthMiddleware(authorizer, mcpHandler))
+	return mux
+}
 
-	if auth.IsAuthEnabled() || authorizer != nil {
-		fmt.Fprintf(s.stderr, "GitLab MCP Server running on HTTP at %s (authentication enabled)\n", addr)
-	} else {
-		fmt.Fprintf(s.stderr, "GitLab MCP Server running on HTTP at %s (authentication disabled)\n", addr)
+// writeHTTPResponse writes a JSON-RPC response. ServerBusy errors are sent
+// with status 429 and Retry-After so HTTP clients and proxies can back off.
+func writeHTTPResponse(w http.ResponseWriter, response *JSONRPCResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if response.Error != nil && response.Error.Code == ServerBusy {
+		w.Header().Set("Retry-After", strconv.Itoa(busyRetryAfterSeconds))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleHealth serves /health. With ?check=deep and a configured HealthCheck,
+// the check result is included under "checks" and failures return 503.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	check := s.healthCheck
+	s.mu.RUnlock()
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"version": s.version,
 	}
-	return http.ListenAndServe(addr, mux)
+	statusCode := http.StatusOK
+
+	if check != nil && r.URL.Query().Get("check") == "deep" {
+		details, healthy := check(r.Context())
+		response["checks"] = details
+		if !healthy {
+			response["status"] = "degraded"
+			statusCode = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
 }
 
 // handleMessageWithContext processes a message and stores request context for header-based credentials
@@ -153,16 +369,19 @@ func (s *Server) handleMessageWithContext(r *http.Request, data []byte) *JSONRPC
 	gitlabToken := r.Header.Get(auth.GitLabTokenHeader)
 	if gitlabToken != "" {
 		// Store in request context for tool handlers to access
-		ctx := auth.WithGitLabToken(r.Context(), gitlabToken)
+		tokenCtx := auth.WithGitLabToken(r.Context(), gitlabToken)
 		auth.SetCurrentGitLabToken(gitlabToken)
 		defer auth.ClearCurrentGitLabToken()
-		_ = ctx // Context is set via global for now since tool handlers don't have access to request
+		_ = tokenCtx // Context is set via global for now since tool handlers don't have access to request
 	}
 
-	return s.handleMessage(data)
+	// Continue any trace started by the caller (W3C traceparent header)
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+	return s.handleMessage(ctx, data)
 }
 
-func (s *Server) handleMessage(data []byte) *JSONRPCResponse {
+func (s *Server) handleMessage(ctx context.Context, data []byte) *JSONRPCResponse {
 	var request JSONRPCRequest
 	if err := json.Unmarshal(data, &request); err != nil {
 		return &JSONRPCResponse{
@@ -177,24 +396,60 @@ func (s *Server) handleMessage(data []byte) *JSONRPCResponse {
 
 	// Handle notifications (no ID)
 	if request.ID == nil {
-		s.handleNotification(&request)
+		s.handleNotification(ctx, &request)
 		return nil
 	}
 
-	return s.handleRequest(&request)
+	// Every request gets a correlation ID for logs, GitLab calls and error results
+	if logging.RequestIDFromContext(ctx) == "" {
+		ctx = logging.WithRequestID(ctx, logging.NewRequestID())
+	}
+
+	return s.handleRequest(ctx, &request)
+}
+
+// withRequestID accepts the caller's correlation ID (or generates one), echoes
+// it on the response and returns the request with the ID in its context.
+func withRequestID(w http.ResponseWriter, r *http.Request) *http.Request {
+	requestID := r.Header.Get(RequestIDHeader)
+	if !validRequestID(requestID) {
+		requestID = logging.NewRequestID()
+	}
+	w.Header().Set(RequestIDHeader, requestID)
+	return r.WithContext(logging.WithRequestID(r.Context(), requestID))
+}
+
+// validRequestID reports whether a caller-supplied request ID is safe to log and forward.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
 }
 
-func (s *Server) handleNotification(request *JSONRPCRequest) {
+func (s *Server) handleNotification(ctx context.Context, request *JSONRPCRequest) {
 	switch request.Method {
 	case "notifications/initialized":
 		// Client initialized notification, no action needed
 		fmt.Fprintln(s.stderr, "Client initialized")
-	case "notifications/cancelled":
-		// Request cancellation, no action needed for now
+	case "notifications/cancelled", "$/cancelRequest":
+		s.handleCancellation(ctx, request.Params)
 	}
 }
 
-func (s *Server) handleRequest(request *JSONRPCRequest) *JSONRPCResponse {
+func (s *Server) handleRequest(ctx context.Context, request *JSONRPCRequest) *JSONRPCResponse {
+	s.mu.RLock()
+	contextFunc := s.contextFunc
+	s.mu.RUnlock()
+	if contextFunc != nil {
+		ctx = contextFunc(ctx)
+	}
+
 	response := &JSONRPCResponse{
 		JSONRPC: "2.0",
 		ID:      request.ID,
@@ -204,15 +459,51 @@ func (s *Server) handleRequest(request *JSONRPCRequest) *JSONRPCResponse {
 	case "initialize":
 		response.Result = s.handleInitialize(request.Params)
 	case "tools/list":
-		response.Result = s.handleListTools()
+		result, rpcErr := s.handleListTools(ctx, request.Params)
+		if rpcErr != nil {
+			response.Error = rpcErr
+		} else {
+			response.Result = result
+		}
 	case "tools/call":
-		result, err := s.handleCallTool(request.Params)
-		if err != nil {
+		requestID := logging.RequestIDFromContext(ctx)
+		ctx, done := s.trackRequest(ctx, request.ID)
+		defer done()
+		release, busyErr := s.acquireToolSlot(ctx, requestID)
+		if busyErr != nil {
+			response.Error = busyErr
+			break
+		}
+		slot := newToolSlot(release)
+		ctx = withToolSlot(ctx, slot)
+		defer slot.done()
+		result, err := s.handleCallTool(ctx, request.Params)
+		var invalidArgs *InvalidArgumentsError
+		if errors.Is(ctx.Err(), context.Canceled) {
+			response.Error = cancelledError(ctx, requestID)
+		} else if errors.As(err, &invalidArgs) {
+			response.Error = &JSONRPCError{
+				Code:    InvalidParams,
+				Message: err.Error(),
+				Data: map[string]interface{}{
+					"request_id": requestID,
+					"tool":       invalidArgs.Tool,
+					"violations": invalidArgs.Violations,
+				},
+			}
+		} else if err != nil {
 			response.Error = &JSONRPCError{
 				Code:    InternalError,
 				Message: err.Error(),
+				Data:    map[string]string{"request_id": requestID},
 			}
 		} else {
+			if result != nil && result.IsError {
+				result.Content = append(result.Content, ContentItem{
+					Type: "text",
+					Text: fmt.Sprintf("request_id: %s", requestID),
+				})
+			}
 			response.Result = result
 		}
 	case "ping":
@@ -232,11 +523,16 @@ func (s *Server) handleInitialize(params interface{}) *InitializeResult {
 	instructions := s.instructions
 	s.mu.RUnlock()
 
+	requested := ""
+	if paramsMap, ok := params.(map[string]interface{}); ok {
+		requested, _ = paramsMap["protocolVersion"].(string)
+	}
+
 	return &InitializeResult{
-		ProtocolVersion: "2024-11-05",
+		ProtocolVersion: negotiateProtocolVersion(requested),
 		Capabilities: ServerCapabilities{
 			Tools: &ToolsCapability{
-				ListChanged: false,
+				ListChanged: true,
 			},
 		},
 		ServerInfo: ServerInfo{
@@ -247,15 +543,7 @@ func (s *Server) handleInitialize(params interface{}) *InitializeResult {
 	}
 }
 
-func (s *Server) handleListTools() *ListToolsResult {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return &ListToolsResult{
-		Tools: s.tools,
-	}
-}
-
-func (s *Server) handleCallTool(params interface{}) (*CallToolResult, error) {
+func (s *Server) handleCallTool(ctx context.Context, params interface{}) (*CallToolResult, error) {
 	paramsMap, ok := params.(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("invalid params type")
@@ -267,9 +555,27 @@ func (s *Server) handleCallTool(params interface{}) (*CallToolResult, error) {
 	}
 
 	arguments, _ := paramsMap["arguments"].(map[string]interface{})
+	token := progressToken(paramsMap)
 
 	s.mu.RLock()
 	handler, exists := s.handlers[name]
+	hasOutputSchema := false
+	readOnly := false
+	var inputSchema JSONSchema
+	for _, tool := range s.tools {
+		if tool.Name == name {
+			hasOutputSchema = tool.OutputSchema != nil
+			readOnly = s.isReadOnly(tool)
+			inputSchema = tool.InputSchema
+			if s.toolAccess != nil && !s.toolAccess(ctx, tool) {
+				exists = false
+			}
+			break
+		}
+	}
+	notifications := s.notifications
+	timeout := s.timeoutFor(name)
+	redactor := s.redactor
 	s.mu.RUnlock()
 
 	if !exists {
@@ -279,7 +585,51 @@ func (s *Server) handleCallTool(params interface{}) (*CallToolResult, error) {
 		}, nil
 	}
 
-	return handler(arguments)
+	if violations := ValidateArguments(inputSchema, arguments); len(violations) > 0 {
+		return nil, &InvalidArgumentsError{Tool: name, Violations: violations}
+	}
+
+	if token != nil && notifications {
+		ctx = withProgress(ctx, s, token)
+	}
+
+	ctx = context.WithValue(ctx, toolStatsKey{}, s.stats)
+	ctx, span := startToolSpan(ctx, name)
+	start := time.Now()
+	result, err := s.runWithTimeout(ctx, name, handler, arguments, timeout, readOnly)
+	elapsed := time.Since(start)
+	endToolSpan(ctx, span, name, elapsed, result, err)
+	if s.stats.Record(name, elapsed, err != nil || (result != nil && result.IsError)) {
+		s.logWarn(ctx, "Slow tool call: %s took %s", name, elapsed.Round(time.Millisecond))
+	}
+
+	if n := redactResult(result, redactor); n > 0 {
+		s.logRedactions(ctx, name, n)
+	}
+	if err == nil && hasOutputSchema {
+		setStructuredContent(result)
+	}
+
+	return result, err
+}
+
+// setStructuredContent fills StructuredContent from the JSON text of a successful
+// result, for tools that declare an OutputSchema. structuredContent must be an
+// object, so top-level arrays are wrapped as {"items": [...]}.
+func setStructuredContent(result *CallToolResult) {
+	if result == nil || result.IsError || result.StructuredContent != nil || len(result.Content) == 0 {
+		return
+	}
+	text := strings.TrimSpace(result.Content[0].Text)
+	if result.Content[0].Type != "text" || !json.Valid([]byte(text)) {
+		return
+	}
+	switch {
+	case strings.HasPrefix(text, "{"):
+		result.StructuredContent = json.RawMessage(text)
+	case strings.HasPrefix(text, "["):
+		result.StructuredContent = map[string]json.RawMessage{"items": json.RawMessage(text)}
+	}
 }
 
 func (s *Server) sendResponse(response *JSONRPCResponse) {
@@ -288,9 +638,47 @@ func (s *Server) sendResponse(response *JSONRPCResponse) {
 		fmt.Fprintf(s.stderr, "Error marshaling response: %v\n", err)
 		return
 	}
+	s.writeMessage(data)
+}
+
+// sendNotification writes a JSON-RPC notification to the client (stdio only).
+func (s *Server) sendNotification(method string, params interface{}) {
+	data, err := json.Marshal(JSONRPCRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		fmt.Fprintf(s.stderr, "Error marshaling notification: %v\n", err)
+		return
+	}
+	s.writeMessage(data)
+}
+
+// writeMessage writes one message to stdout, newline-delimited unless the
+// client has been sending Content-Length framed messages.
+func (s *Server) writeMessage(data []byte) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.framing != nil && s.framing.sawHeaders.Load() {
+		fmt.Fprintf(s.stdout, "Content-Length: %d\r\n\r\n%s", len(data), data)
+		return
+	}
 	fmt.Fprintln(s.stdout, string(data))
 }
 
+// logWarn logs a warning to the server logger, or to stderr if none is set.
+func (s *Server) logWarn(ctx context.Context, format string, args ...interface{}) {
+	s.mu.RLock()
+	logger := s.logger
+	s.mu.RUnlock()
+	if logger != nil {
+		logger.WarnContext(ctx, format, args...)
+		return
+	}
+	s.Log(format, args...)
+}
+
 // Log writes a message to stderr for debugging
 func (s *Server) Log(format string, args ...interface{}) {
 	fmt.Fprintf(s.stderr, format+"\n", args...)
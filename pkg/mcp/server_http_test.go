@@ -2,13 +2,16 @@ package mcp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
 )
 
 // createTestHandler creates an HTTP handler for the MCP server for testing purposes.
@@ -17,14 +20,12 @@ func createTestHandler(s *Server, authorizer auth.Authorizer) http.Handler {
 	mux := http.NewServeMux()
 
 	// Health check endpoint (no auth required)
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{
-			"status":  "ok",
-			"version": s.version,
-		})
-	})
+	mux.HandleFunc("/health", s.handleHealth)
+
+	// Metrics endpoint (no auth required)
+	if s.metrics != nil {
+		mux.Handle("/metrics", s.metrics)
+	}
 
 	// MCP endpoint handler
 	mcpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -50,10 +51,11 @@ func createTestHandler(s *Server, authorizer auth.Authorizer) http.Handler {
 			return
 		}
 
+		r = withRequestID(w, r)
+		r = withSession(w, r, body)
 		response := s.handleMessageWithContext(r, body)
 		if response != nil {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(response)
+			writeHTTPResponse(w, response)
 		}
 	})
 
@@ -101,6 +103,77 @@ func TestHTTPHealthEndpoint(t *testing.T) {
 	}
 }
 
+func TestHTTPHealthDeepCheck(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	calls := 0
+	server.SetHealthCheck(func(ctx context.Context) (interface{}, bool) {
+		calls++
+		return map[string]string{"gitlab": "unreachable"}, false
+	})
+
+	ts := httptest.NewServer(createTestHandler(server, nil))
+	defer ts.Close()
+
+	// Plain /health must not run the dependency check
+	resp, err := http.Get(ts.URL + "/health")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 0 {
+		t.Errorf("Expected 200 without running the check, got %d (calls=%d)", resp.StatusCode, calls)
+	}
+
+	resp, err = http.Get(ts.URL + "/health?check=deep")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", resp.StatusCode)
+	}
+	var result struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.Status != "degraded" {
+		t.Errorf("Expected status 'degraded', got %q", result.Status)
+	}
+	if result.Checks["gitlab"] != "unreachable" {
+		t.Errorf("Expected check details in response, got %v", result.Checks)
+	}
+}
+
+func TestHTTPMetricsEndpoint(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	server.SetMetricsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# TYPE mcp_tool_invocations_total counter\n"))
+	}))
+
+	// Metrics must be reachable without credentials even when auth is enabled
+	ts := httptest.NewServer(createTestHandler(server, &auth.MockAuthorizer{}))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if !bytes.Contains(body, []byte("mcp_tool_invocations_total")) {
+		t.Errorf("Expected metrics in body, got: %s", string(body))
+	}
+}
+
 func TestHTTPAuthMiddleware_MissingHeader(t *testing.T) {
 	server := NewServer("test-server", "1.0.0")
 
@@ -273,10 +346,10 @@ func TestHTTPMCPToolsList(t *testing.T) {
 		},
 	}
 
-	server.RegisterTool(tool1, func(args map[string]interface{}) (*CallToolResult, error) {
+	server.RegisterTool(tool1, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
 		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "tool1 result"}}}, nil
 	})
-	server.RegisterTool(tool2, func(args map[string]interface{}) (*CallToolResult, error) {
+	server.RegisterTool(tool2, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
 		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "tool2 result"}}}, nil
 	})
 
@@ -369,7 +442,7 @@ func TestHTTPMCPToolsCall(t *testing.T) {
 		},
 	}
 
-	server.RegisterTool(echoTool, func(args map[string]interface{}) (*CallToolResult, error) {
+	server.RegisterTool(echoTool, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
 		msg, _ := args["message"].(string)
 		return &CallToolResult{
 			Content: []ContentItem{{Type: "text", Text: "Echo: " + msg}},
@@ -523,3 +596,241 @@ func TestHTTPUnknownMethod(t *testing.T) {
 		t.Errorf("Expected method not found error code %d, got %d", MethodNotFound, rpcResponse.Error.Code)
 	}
 }
+
+func TestHTTPRequestID(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	var seen string
+	s.RegisterTool(Tool{
+		Name:        "failing_tool",
+		Description: "Always fails",
+		InputSchema: JSONSchema{Type: "object"},
+	}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		seen = logging.RequestIDFromContext(ctx)
+		return &CallToolResult{
+			Content: []ContentItem{{Type: "text", Text: "boom"}},
+			IsError: true,
+		}, nil
+	})
+	handler := createTestHandler(s, nil)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"failing_tool","arguments":{}}}`
+
+	t.Run("accepts caller ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(RequestIDHeader, "abc-123")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get(RequestIDHeader); got != "abc-123" {
+			t.Errorf("Expected echoed request ID abc-123, got %q", got)
+		}
+		if seen != "abc-123" {
+			t.Errorf("Expected handler context to carry abc-123, got %q", seen)
+		}
+
+		var resp struct {
+			Result CallToolResult `json:"result"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		last := resp.Result.Content[len(resp.Result.Content)-1]
+		if last.Text != "request_id: abc-123" {
+			t.Errorf("Expected error result to include request ID, got %q", last.Text)
+		}
+	})
+
+	t.Run("generates ID when missing or invalid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(RequestIDHeader, "has spaces")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		got := rec.Header().Get(RequestIDHeader)
+		if got == "" || got == "has spaces" {
+			t.Errorf("Expected a generated request ID, got %q", got)
+		}
+		if seen != got {
+			t.Errorf("Expected handler context to carry %q, got %q", got, seen)
+		}
+	})
+}
+
+func TestHTTPStructuredContent(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	s.RegisterTool(Tool{
+		Name:         "list_things",
+		InputSchema:  JSONSchema{Type: "object"},
+		OutputSchema: &JSONSchema{Type: "object", Properties: map[string]Property{"items": {Type: "array"}}},
+	}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: `[{"id": 1}]`}}}, nil
+	})
+	s.RegisterTool(Tool{
+		Name:        "plain_tool",
+		InputSchema: JSONSchema{Type: "object"},
+	}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: `{"id": 1}`}}}, nil
+	})
+	handler := createTestHandler(s, nil)
+
+	call := func(name string) map[string]interface{} {
+		body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"` + name + `","arguments":{}}}`
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		var resp struct {
+			Result map[string]interface{} `json:"result"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp.Result
+	}
+
+	structured, ok := call("list_things")["structuredContent"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected structuredContent for tool with outputSchema")
+	}
+	if items, ok := structured["items"].([]interface{}); !ok || len(items) != 1 {
+		t.Errorf("Expected array result wrapped as items, got %v", structured)
+	}
+
+	if _, ok := call("plain_tool")["structuredContent"]; ok {
+		t.Error("Expected no structuredContent for tool without outputSchema")
+	}
+}
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	tests := map[string]string{
+		"2024-11-05": "2024-11-05",
+		"2025-06-18": "2025-06-18",
+		"1999-01-01": LatestProtocolVersion,
+		"":           LatestProtocolVersion,
+	}
+	for requested, want := range tests {
+		if got := negotiateProtocolVersion(requested); got != want {
+			t.Errorf("negotiateProtocolVersion(%q) = %q, want %q", requested, got, want)
+		}
+	}
+}
+
+func TestHTTPConcurrencyLimit(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.RegisterTool(Tool{Name: "slow_tool", InputSchema: JSONSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			close(started)
+			<-release
+			return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "done"}}}, nil
+		})
+	s.SetConcurrencyLimit(1, 0)
+
+	handler := createTestHandler(s, nil)
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow_tool","arguments":{}}}`
+
+	first := make(chan int, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(call)))
+		first <- rec.Code
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(call)))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 429 with Retry-After while saturated, got %d", rec.Code)
+	}
+	var response JSONRPCResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil || response.Error == nil || response.Error.Code != ServerBusy {
+		t.Errorf("Expected ServerBusy error, got %s", rec.Body.String())
+	}
+
+	close(release)
+	if code := <-first; code != http.StatusOK {
+		t.Errorf("Expected the running call to succeed, got %d", code)
+	}
+}
+
+// namedAuthorizer authorizes every token and names its caller.
+type namedAuthorizer struct{ auth.MockAuthorizer }
+
+func (*namedAuthorizer) Principal(ctx context.Context, token string) string {
+	return "alice"
+}
+
+func TestHTTPAuthMiddleware_Principal(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	var seen string
+	s.RegisterTool(Tool{
+		Name:        "whoami",
+		Description: "Reports the caller",
+		InputSchema: JSONSchema{Type: "object"},
+	}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		seen, _ = auth.PrincipalFromContext(ctx)
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: seen}}}, nil
+	})
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"whoami","arguments":{}}}`
+
+	tests := []struct {
+		name       string
+		authorizer auth.Authorizer
+		want       string
+	}{
+		{"named by authorizer", &namedAuthorizer{}, "alice"},
+		{"token fingerprint", &auth.MockAuthorizer{}, auth.TokenPrincipal("secret-token")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = ""
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set("Authorization", "Bearer secret-token")
+			rec := httptest.NewRecorder()
+			createTestHandler(s, tt.authorizer).ServeHTTP(rec, req)
+
+			if seen != tt.want {
+				t.Errorf("Expected principal %q, got %q", tt.want, seen)
+			}
+			if strings.Contains(seen, "secret-token") {
+				t.Errorf("Principal leaked the token: %q", seen)
+			}
+		})
+	}
+}
+
+func TestHTTPSessionID(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	var seen string
+	s.RegisterTool(Tool{
+		Name:        "session",
+		Description: "Reports the session",
+		InputSchema: JSONSchema{Type: "object"},
+	}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		seen = SessionIDFromContext(ctx)
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: seen}}}, nil
+	})
+	handler := createTestHandler(s, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	sessionID := rec.Header().Get(SessionIDHeader)
+	if len(sessionID) != 32 {
+		t.Fatalf("Expected a session ID on initialize, got %q", sessionID)
+	}
+
+	call := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"session","arguments":{}}}`
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(call))
+	req.Header.Set(SessionIDHeader, sessionID)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if seen != sessionID {
+		t.Errorf("Expected tool context to carry session %q, got %q", sessionID, seen)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(call))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if seen != "" || rec.Header().Get(SessionIDHeader) != "" {
+		t.Errorf("Expected no session without the header, got %q", seen)
+	}
+}
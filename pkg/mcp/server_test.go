@@ -0,0 +1,739 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+)
+
+// runStdio feeds input lines to a stdio server and returns the decoded output messages.
+func runStdio(t *testing.T, s *Server, input string) []map[string]interface{} {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	s.stdin = strings.NewReader(input)
+	s.stdout = &stdout
+	s.stderr = &stderr
+
+	if err := s.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	var messages []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("Invalid JSON output %q: %v", line, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func TestStdioProgressNotifications(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	s.RegisterTool(Tool{Name: "slow_tool", InputSchema: JSONSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			ReportProgress(ctx, 0, 2, "starting")
+			ReportProgress(ctx, 1, 2, "halfway")
+			ReportProgress(ctx, 1, 2, "duplicate is dropped")
+			return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "done"}}}, nil
+		})
+
+	messages := runStdio(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow_tool","arguments":{},"_meta":{"progressToken":"tok-1"}}}`+"\n"+
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"slow_tool","arguments":{}}}`+"\n")
+
+	var progress []map[string]interface{}
+	responses := 0
+	for _, msg := range messages {
+		if msg["method"] == "notifications/progress" {
+			progress = append(progress, msg["params"].(map[string]interface{}))
+		} else if msg["id"] != nil {
+			responses++
+		}
+	}
+
+	if responses != 2 {
+		t.Errorf("Expected 2 responses, got %d", responses)
+	}
+	if len(progress) != 2 {
+		t.Fatalf("Expected 2 progress notifications (only for the call with a token), got %d: %v", len(progress), progress)
+	}
+	if progress[0]["progressToken"] != "tok-1" || progress[1]["progress"] != float64(1) || progress[1]["message"] != "halfway" {
+		t.Errorf("Unexpected progress notifications: %v", progress)
+	}
+}
+
+func TestReportProgressWithoutToken(t *testing.T) {
+	// Must be a safe no-op outside a tool call with a progress token
+	ReportProgress(context.Background(), 1, 2, "ignored")
+}
+
+func TestStdioCancelRequest(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	started := make(chan struct{})
+	s.RegisterTool(Tool{Name: "blocking_tool", InputSchema: JSONSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	stdin, writer := io.Pipe()
+	var stdout, stderr bytes.Buffer
+	s.stdin = stdin
+	s.stdout = &stdout
+	s.stderr = &stderr
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Run() }()
+
+	io.WriteString(writer, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"blocking_tool","arguments":{}}}`+"\n")
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Tool was not started")
+	}
+	io.WriteString(writer, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user aborted"}}`+"\n")
+	writer.Close()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Cancelled request did not complete")
+	}
+
+	var response JSONRPCResponse
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &response); err != nil {
+		t.Fatalf("Invalid response %q: %v", stdout.String(), err)
+	}
+	if response.Error == nil || response.Error.Code != RequestCancelled {
+		t.Fatalf("Expected RequestCancelled error, got %+v", response)
+	}
+	if !strings.Contains(response.Error.Message, "user aborted") {
+		t.Errorf("Expected cancellation reason in message, got %q", response.Error.Message)
+	}
+}
+
+func TestCancelUnknownRequest(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	// Cancellations may race completion and must be ignored
+	s.handleCancellation(context.Background(), map[string]interface{}{"id": "missing"})
+	s.handleCancellation(context.Background(), "not-an-object")
+}
+
+func TestCancelRequestScopedToSession(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	started := make(chan struct{}, 3)
+	s.RegisterTool(Tool{Name: "blocking_tool", InputSchema: JSONSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	// Three callers use the same request ID
+	alice := auth.WithPrincipal(WithSessionID(context.Background(), "session-a"), "alice")
+	bob := auth.WithPrincipal(WithSessionID(context.Background(), "session-b"), "bob")
+	mallory := auth.WithPrincipal(WithSessionID(context.Background(), "session-a"), "mallory")
+	call := []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"blocking_tool","arguments":{}}}`)
+	cancel := []byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`)
+
+	responses := make(map[string]chan *JSONRPCResponse)
+	for name, ctx := range map[string]context.Context{"alice": alice, "bob": bob} {
+		ch := make(chan *JSONRPCResponse, 1)
+		responses[name] = ch
+		go func(ctx context.Context) { ch <- s.handleMessage(ctx, call) }(ctx)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Tool was not started")
+		}
+	}
+
+	// Another principal in alice's session cannot cancel her call
+	s.handleMessage(mallory, cancel)
+	select {
+	case response := <-responses["alice"]:
+		t.Fatalf("Another principal cancelled alice's call: %+v", response)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.handleMessage(alice, cancel)
+	select {
+	case response := <-responses["alice"]:
+		if response.Error == nil || response.Error.Code != RequestCancelled {
+			t.Errorf("Expected alice's call to be cancelled, got %+v", response)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Cancelled request did not complete")
+	}
+	select {
+	case response := <-responses["bob"]:
+		t.Fatalf("Bob's call with the same ID was cancelled: %+v", response)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.handleMessage(bob, cancel)
+	select {
+	case <-responses["bob"]:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Bob's request was not cancelled")
+	}
+	if len(s.inflight) != 0 {
+		t.Errorf("Completed requests still tracked: %v", s.inflight)
+	}
+}
+
+func TestTrackRequestReusedID(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	ctx := WithSessionID(context.Background(), "session-a")
+	first, doneFirst := s.trackRequest(ctx, 1)
+	second, doneSecond := s.trackRequest(ctx, 1)
+
+	// The first call finishing does not untrack the second
+	doneFirst()
+	s.cancelRequest(ctx, 1, "")
+	if second.Err() == nil {
+		t.Error("Second request with a reused ID was not cancelled")
+	}
+	if !errors.Is(first.Err(), context.Canceled) {
+		t.Errorf("First request context = %v, want cancelled on completion", first.Err())
+	}
+	doneSecond()
+	if len(s.inflight) != 0 {
+		t.Errorf("Completed requests still tracked: %v", s.inflight)
+	}
+}
+
+func TestToolsListPagination(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		s.RegisterTool(Tool{Name: name, InputSchema: JSONSchema{Type: "object"}}, nil)
+	}
+	s.SetToolsPageSize(2)
+
+	var names []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("Pagination did not terminate")
+		}
+		var params interface{}
+		if cursor != "" {
+			params = map[string]interface{}{"cursor": cursor}
+		}
+		result, rpcErr := s.handleListTools(context.Background(), params)
+		if rpcErr != nil {
+			t.Fatalf("Unexpected error: %v", rpcErr.Message)
+		}
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+
+	if strings.Join(names, ",") != "a,b,c,d,e" {
+		t.Errorf("Expected all tools across pages, got %v", names)
+	}
+
+	if _, rpcErr := s.handleListTools(context.Background(), map[string]interface{}{"cursor": "bogus"}); rpcErr == nil || rpcErr.Code != InvalidParams {
+		t.Errorf("Expected InvalidParams for a bad cursor, got %+v", rpcErr)
+	}
+}
+
+func TestToolAccess(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	called := false
+	for _, name := range []string{"get_thing", "delete_thing"} {
+		s.RegisterTool(Tool{Name: name, InputSchema: JSONSchema{Type: "object"}}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			called = true
+			return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "done"}}}, nil
+		})
+	}
+	type viewerKey struct{}
+	s.SetToolAccess(func(ctx context.Context, tool Tool) bool {
+		return ctx.Value(viewerKey{}) == nil || strings.HasPrefix(tool.Name, "get_")
+	})
+	viewer := context.WithValue(context.Background(), viewerKey{}, true)
+
+	result, rpcErr := s.handleListTools(viewer, nil)
+	if rpcErr != nil || len(result.Tools) != 1 || result.Tools[0].Name != "get_thing" {
+		t.Errorf("Expected viewer to list only get_thing, got %+v %v", result, rpcErr)
+	}
+	if result, _ := s.handleListTools(context.Background(), nil); len(result.Tools) != 2 {
+		t.Errorf("Expected 2 tools without restriction, got %d", len(result.Tools))
+	}
+
+	denied, err := s.handleCallTool(viewer, map[string]interface{}{"name": "delete_thing"})
+	if err != nil || !denied.IsError || denied.Content[0].Text != "Unknown tool: delete_thing" {
+		t.Errorf("Expected hidden tool to be unknown, got %+v %v", denied, err)
+	}
+	if called {
+		t.Error("Hidden tool was called")
+	}
+	if allowed, err := s.handleCallTool(viewer, map[string]interface{}{"name": "get_thing"}); err != nil || allowed.IsError || !called {
+		t.Errorf("Expected visible tool to run, got %+v %v", allowed, err)
+	}
+}
+
+func TestContextFunc(t *testing.T) {
+	type tenantKey struct{}
+	s := NewServer("test-server", "1.0.0")
+	s.RegisterTool(Tool{Name: "whoami", InputSchema: JSONSchema{Type: "object"}}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: tenant}}}, nil
+	})
+	s.SetContextFunc(func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, tenantKey{}, "acme")
+	})
+
+	response := s.handleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"whoami"}}`))
+	result, ok := response.Result.(*CallToolResult)
+	if !ok || result.Content[0].Text != "acme" {
+		t.Errorf("Expected the handler to see the derived context, got %+v", response.Result)
+	}
+}
+
+func TestReplaceToolsNotifiesListChanged(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	s.RegisterTool(Tool{Name: "old_tool", InputSchema: JSONSchema{Type: "object"}}, nil)
+
+	var stdout bytes.Buffer
+	s.stdout = &stdout
+	s.notifications = true
+
+	register := func(srv *Server) {
+		srv.RegisterTool(Tool{Name: "new_tool", InputSchema: JSONSchema{Type: "object"}}, nil)
+	}
+	s.ReplaceTools(register)
+	s.ReplaceTools(register) // unchanged, no second notification
+
+	if names := s.ToolNames(); len(names) != 1 || names[0] != "new_tool" {
+		t.Errorf("Expected tools to be replaced, got %v", names)
+	}
+	if count := strings.Count(stdout.String(), `"notifications/tools/list_changed"`); count != 1 {
+		t.Errorf("Expected 1 list_changed notification, got %d: %s", count, stdout.String())
+	}
+}
+
+func TestStdioContentLengthFraming(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	ping := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	list := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+	input := "Content-Length: " + strconv.Itoa(len(ping)) + "\r\n\r\n" + ping +
+		list + "\n" + // newline-delimited messages are still accepted
+		"Content-Type: application/vscode-jsonrpc; charset=utf-8\r\ncontent-length: " + strconv.Itoa(len(ping)) + "\r\n\r\n" + ping
+
+	var stdout, stderr bytes.Buffer
+	s.stdin = strings.NewReader(input)
+	s.stdout = &stdout
+	s.stderr = &stderr
+	if err := s.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// Responses use Content-Length framing once the client has used it
+	reader := bufio.NewReader(&stdout)
+	for i := 0; i < 3; i++ {
+		header, err := reader.ReadString('\n')
+		if err != nil || !strings.HasPrefix(header, "Content-Length: ") {
+			t.Fatalf("Response %d: expected Content-Length header, got %q (%v)", i, header, err)
+		}
+		length, _ := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "Content-Length: ")))
+		if blank, _ := reader.ReadString('\n'); blank != "\r\n" {
+			t.Fatalf("Response %d: expected blank line after headers, got %q", i, blank)
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			t.Fatalf("Response %d: short body: %v", i, err)
+		}
+		var response JSONRPCResponse
+		if err := json.Unmarshal(body, &response); err != nil || response.Error != nil {
+			t.Fatalf("Response %d: unexpected body %s", i, body)
+		}
+	}
+}
+
+func TestStdioSplitterRejectsMissingContentLength(t *testing.T) {
+	f := &stdioSplitter{}
+	if _, _, err := f.split([]byte("Content-Type: application/json\r\n\r\n{}"), false); err == nil {
+		t.Error("Expected an error for headers without Content-Length")
+	}
+}
+
+func TestToolTimeout(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	release := make(chan struct{})
+	defer close(release)
+	s.RegisterTool(Tool{Name: "hung_tool", InputSchema: JSONSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			<-release // ignores ctx, like a stuck download
+			return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "late"}}}, nil
+		})
+	s.RegisterTool(Tool{Name: "fast_tool", InputSchema: JSONSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "ok"}}}, nil
+		})
+	s.SetToolTimeouts(time.Minute, map[string]time.Duration{"hung_tool": 20 * time.Millisecond})
+
+	result, err := s.handleCallTool(context.Background(), map[string]interface{}{"name": "hung_tool"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.IsError || len(result.Content) != 2 || !strings.Contains(result.Content[1].Text, `"timeout":true`) {
+		t.Errorf("Expected structured timeout result, got %+v", result)
+	}
+
+	result, err = s.handleCallTool(context.Background(), map[string]interface{}{"name": "fast_tool"})
+	if err != nil || result.IsError {
+		t.Errorf("Expected fast tool to succeed, got %+v, %v", result, err)
+	}
+}
+
+func TestToolTimeoutRetryable(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	hung := func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	s.RegisterTool(Tool{Name: "get_thing", InputSchema: JSONSchema{Type: "object"}, Annotations: &ToolAnnotations{ReadOnlyHint: true}}, hung)
+	s.RegisterTool(Tool{Name: "create_thing", InputSchema: JSONSchema{Type: "object"}}, hung)
+	s.SetToolTimeouts(10*time.Millisecond, nil)
+
+	for name, want := range map[string]bool{"get_thing": true, "create_thing": false} {
+		result, err := s.handleCallTool(context.Background(), map[string]interface{}{"name": name})
+		if err != nil || !result.IsError || len(result.Content) != 2 {
+			t.Fatalf("%s: expected a timeout result, got %+v, %v", name, result, err)
+		}
+		var details struct {
+			Error struct {
+				Retryable bool `json:"retryable"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(result.Content[1].Text), &details); err != nil {
+			t.Fatal(err)
+		}
+		if details.Error.Retryable != want {
+			t.Errorf("%s: retryable = %v, want %v", name, details.Error.Retryable, want)
+		}
+	}
+
+	// The server's read-only check overrides the annotation
+	s.SetReadOnlyTools(func(tool Tool) bool { return tool.Name == "create_thing" })
+	result, _ := s.handleCallTool(context.Background(), map[string]interface{}{"name": "create_thing"})
+	if !strings.Contains(result.Content[1].Text, `"retryable":true`) {
+		t.Errorf("Expected create_thing to be retryable, got %s", result.Content[1].Text)
+	}
+}
+
+func TestToolTimeoutKeepsSlot(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	s.RegisterTool(Tool{Name: "hung_tool", InputSchema: JSONSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			defer close(finished)
+			<-release // ignores ctx
+			return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "late"}}}, nil
+		})
+	s.RegisterTool(Tool{Name: "fast_tool", InputSchema: JSONSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "ok"}}}, nil
+		})
+	s.SetToolTimeouts(time.Minute, map[string]time.Duration{"hung_tool": 10 * time.Millisecond})
+	s.SetConcurrencyLimit(1, 0)
+
+	call := func(name string) *JSONRPCResponse {
+		return s.handleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+name+`"}}`))
+	}
+	if response := call("hung_tool"); response.Error != nil || !response.Result.(*CallToolResult).IsError {
+		t.Fatalf("Expected a timeout result, got %+v", response)
+	}
+	// The timed-out handler still runs, so it still holds the only slot
+	if response := call("fast_tool"); response.Error == nil || response.Error.Code != ServerBusy {
+		t.Fatalf("Expected ServerBusy while the handler runs, got %+v", response)
+	}
+
+	close(release)
+	<-finished
+	deadline := time.Now().Add(time.Second)
+	for {
+		response := call("fast_tool")
+		if response.Error == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the slot to be released, got %+v", response.Error)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestToolMiddleware(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	tag := func(label string) ToolMiddleware {
+		return func(tool Tool, handler ToolHandler) (Tool, ToolHandler) {
+			tool.Description += label
+			return tool, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+				result, err := handler(ctx, args)
+				result.Content[0].Text += label
+				return result, err
+			}
+		}
+	}
+	s.UseToolMiddleware(tag("-outer"))
+	s.UseToolMiddleware(tag("-inner"))
+
+	register := func(srv *Server) {
+		srv.RegisterTool(Tool{Name: "echo", InputSchema: JSONSchema{Type: "object"}},
+			func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+				return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "ok"}}}, nil
+			})
+	}
+	register(s)
+	s.ReplaceTools(register) // middleware also applies to replaced tools
+
+	if s.tools[0].Description != "-inner-outer" {
+		t.Errorf("Expected middleware to decorate the tool, got %q", s.tools[0].Description)
+	}
+	result, err := s.handleCallTool(context.Background(), map[string]interface{}{"name": "echo"})
+	if err != nil || result.Content[0].Text != "ok-inner-outer" {
+		t.Errorf("Expected the first middleware to be outermost, got %+v, %v", result, err)
+	}
+}
+
+func TestImageContentItem(t *testing.T) {
+	item := ImageContent([]byte("\x89PNG"), "image/png")
+	data, err := json.Marshal(item)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"type":"image","data":"iVBORw==","mimeType":"image/png"}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	// Text items keep their original shape
+	data, _ = json.Marshal(ContentItem{Type: "text", Text: "hi"})
+	if string(data) != `{"type":"text","text":"hi"}` {
+		t.Errorf("Unexpected text item %s", data)
+	}
+}
+
+// wordRedactor masks every occurrence of a word.
+type wordRedactor string
+
+func (w wordRedactor) Redact(text string) (string, int) {
+	return strings.ReplaceAll(text, string(w), "[REDACTED]"), strings.Count(text, string(w))
+}
+
+func TestRedactor(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	s.SetRedactor(wordRedactor("hunter2"))
+	s.RegisterTool(Tool{Name: "leak", InputSchema: JSONSchema{Type: "object"}, OutputSchema: &JSONSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			return &CallToolResult{Content: []ContentItem{{Type: "text", Text: `{"id": 12345678901234567, "password": "hunter2"}`}}}, nil
+		})
+
+	result, err := s.handleCallTool(context.Background(), map[string]interface{}{"name": "leak"})
+	if err != nil {
+		t.Fatalf("handleCallTool failed: %v", err)
+	}
+	if want := `{"id": 12345678901234567, "password": "[REDACTED]"}`; result.Content[0].Text != want {
+		t.Errorf("Expected redacted text %s, got %s", want, result.Content[0].Text)
+	}
+	if structured, _ := json.Marshal(result.StructuredContent); strings.Contains(string(structured), "hunter2") {
+		t.Errorf("Expected redacted structured content, got %s", structured)
+	}
+}
+
+// patternRedactor masks the matches of a regular expression.
+type patternRedactor struct{ *regexp.Regexp }
+
+func (p patternRedactor) Redact(text string) (string, int) {
+	return p.ReplaceAllString(text, "[REDACTED]"), len(p.FindAllStringIndex(text, -1))
+}
+
+func TestRedactResultKeepsJSONValid(t *testing.T) {
+	// The pattern swallows the closing quote in the raw JSON text
+	result := &CallToolResult{
+		Content: []ContentItem{{Type: "text", Text: `{"b": 12345678901234567, "a": "hunter2"}`}},
+	}
+	if n := redactResult(result, patternRedactor{regexp.MustCompile(`hunter2"?`)}); n != 1 {
+		t.Errorf("Expected 1 replacement, got %d", n)
+	}
+	if text := result.Content[0].Text; text != `{"a":"[REDACTED]","b":12345678901234567}` {
+		t.Errorf("Expected the JSON to be redacted value by value, got %s", text)
+	}
+}
+
+func TestValidateArguments(t *testing.T) {
+	schema := JSONSchema{
+		Type: "object",
+		Properties: map[string]Property{
+			"project_id": {Type: "string"},
+			"state":      {Type: "string", Enum: []string{"opened", "closed"}},
+			"per_page":   {Type: "integer", Minimum: IntPtr(1), Maximum: IntPtr(100)},
+			"draft":      {Type: "boolean"},
+			"labels":     {Type: "array", Items: &Property{Type: "string"}},
+			"options":    {Type: "object", Properties: map[string]Property{"depth": {Type: "integer"}}},
+		},
+		Required: []string{"project_id"},
+	}
+
+	valid := map[string]interface{}{
+		"project_id": "acme/api",
+		"state":      "opened",
+		"per_page":   float64(20),
+		"draft":      nil,
+		"labels":     []interface{}{"bug"},
+		"options":    map[string]interface{}{"depth": float64(2)},
+		"extra":      "ignored",
+	}
+	if violations := ValidateArguments(schema, valid); len(violations) != 0 {
+		t.Errorf("Expected no violations, got %+v", violations)
+	}
+
+	violations := ValidateArguments(schema, map[string]interface{}{
+		"state":    "merged",
+		"per_page": 2.5,
+		"draft":    "yes",
+		"labels":   []interface{}{"bug", float64(3)},
+		"options":  map[string]interface{}{"depth": "deep"},
+	})
+	want := []Violation{
+		{Path: "draft", Message: "must be a boolean, got string"},
+		{Path: "labels[1]", Message: "must be a string, got number"},
+		{Path: "options.depth", Message: "must be an integer, got string"},
+		{Path: "per_page", Message: "must be an integer, got 2.5"},
+		{Path: "project_id", Message: "is required"},
+		{Path: "state", Message: "must be one of: opened, closed"},
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("Expected %d violations, got %+v", len(want), violations)
+	}
+	for i := range want {
+		if violations[i] != want[i] {
+			t.Errorf("Violation %d: expected %+v, got %+v", i, want[i], violations[i])
+		}
+	}
+
+	if v := ValidateArguments(schema, map[string]interface{}{"project_id": "a", "per_page": float64(500)}); len(v) != 1 || v[0].Message != "must be at most 100" {
+		t.Errorf("Expected a maximum violation, got %+v", v)
+	}
+}
+
+func TestCallToolInvalidArguments(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	called := false
+	s.RegisterTool(Tool{
+		Name: "get_issue",
+		InputSchema: JSONSchema{
+			Type:       "object",
+			Properties: map[string]Property{"project_id": {Type: "string"}, "issue_iid": {Type: "integer"}},
+			Required:   []string{"project_id", "issue_iid"},
+		},
+	}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		called = true
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "ok"}}}, nil
+	})
+
+	response := s.handleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_issue","arguments":{"issue_iid":"7"}}}`))
+	if called {
+		t.Error("Expected the handler not to run")
+	}
+	if response.Error == nil || response.Error.Code != InvalidParams {
+		t.Fatalf("Expected an InvalidParams error, got %+v", response)
+	}
+	if want := "Invalid arguments for tool get_issue: issue_iid: must be an integer, got string; project_id: is required"; response.Error.Message != want {
+		t.Errorf("Expected message %q, got %q", want, response.Error.Message)
+	}
+	data, _ := json.Marshal(response.Error.Data)
+	if !strings.Contains(string(data), `"violations":[{"path":"issue_iid"`) {
+		t.Errorf("Expected the violations in the error data, got %s", data)
+	}
+}
+
+func TestToolStats(t *testing.T) {
+	stats := NewToolStats(time.Second)
+	for i := 0; i < 18; i++ {
+		stats.Record("list_issues", 20*time.Millisecond, false)
+	}
+	stats.Record("list_issues", 40*time.Millisecond, true)
+	if !stats.Record("list_issues", 2*time.Second, false) {
+		t.Error("Expected a 2s call to be slow")
+	}
+	stats.Record("get_project", 300*time.Millisecond, false)
+
+	report := stats.Report()
+	if report.Calls != 21 || report.Errors != 1 || report.SlowCalls != 1 || report.SlowThresholdMS != 1000 {
+		t.Errorf("Unexpected totals: %+v", report)
+	}
+	if len(report.Tools) != 2 || report.Tools[0].Name != "list_issues" {
+		t.Fatalf("Expected tools ordered by calls, got %+v", report.Tools)
+	}
+	issues := report.Tools[0]
+	if issues.Calls != 20 || issues.ErrorRate != 0.05 || issues.P50MS != 25 || issues.P95MS != 50 || issues.MaxMS != 2000 {
+		t.Errorf("Unexpected list_issues stats: %+v", issues)
+	}
+	want := []LatencyBucket{{LEMS: 25, Count: 18}, {LEMS: 50, Count: 1}, {LEMS: 2500, Count: 1}}
+	if len(issues.Histogram) != len(want) {
+		t.Fatalf("Histogram = %+v, want %+v", issues.Histogram, want)
+	}
+	for i := range want {
+		if issues.Histogram[i] != want[i] {
+			t.Errorf("Histogram = %+v, want %+v", issues.Histogram, want)
+		}
+	}
+	// get_project's only call is capped at its actual duration
+	if report.Tools[1].P95MS != 300 || len(report.Slowest) != 2 || report.Slowest[0] != "get_project" {
+		t.Errorf("Unexpected slowest tools: %+v, %v", report.Tools[1], report.Slowest)
+	}
+
+	summary := report.Summary()
+	if summary != "calls=21 errors=1 slow=1 top=[list_issues:20 get_project:1] slowest=[get_project:p95=300ms list_issues:p95=50ms]" {
+		t.Errorf("Unexpected summary: %s", summary)
+	}
+}
+
+func TestCallToolRecordsStats(t *testing.T) {
+	s := NewServer("test-server", "1.0.0")
+	var seen *ToolStats
+	s.RegisterTool(Tool{Name: "whoami", InputSchema: JSONSchema{Type: "object"}}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		seen = ToolStatsFromContext(ctx)
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "nope"}}, IsError: true}, nil
+	})
+
+	if _, err := s.handleCallTool(context.Background(), map[string]interface{}{"name": "whoami"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if seen != s.ToolStats() {
+		t.Error("Expected the handler to see the server's stats")
+	}
+	report := s.ToolStats().Report()
+	if len(report.Tools) != 1 || report.Tools[0].Calls != 1 || report.Tools[0].Errors != 1 {
+		t.Errorf("Expected the failed call to be recorded, got %+v", report.Tools)
+	}
+}
@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+)
+
+// SessionIDHeader is the Streamable HTTP header that identifies an MCP session.
+const SessionIDHeader = "Mcp-Session-Id"
+
+// stdioSessionID is the session of the single client of a stdio server.
+const stdioSessionID = "stdio"
+
+// sessionIDKey is the context key for the MCP session ID
+type sessionIDKey struct{}
+
+// WithSessionID returns a new context carrying the MCP session ID.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// SessionIDFromContext returns the MCP session ID, or "" when the request
+// belongs to no session (an HTTP client that sent no Mcp-Session-Id).
+func SessionIDFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionIDKey{}).(string)
+	return sessionID
+}
+
+// withSession attaches the caller's Mcp-Session-Id to the request context. A
+// new session ID is issued on the response to initialize, which clients then
+// send with their later requests.
+func withSession(w http.ResponseWriter, r *http.Request, data []byte) *http.Request {
+	sessionID := r.Header.Get(SessionIDHeader)
+	if !validRequestID(sessionID) {
+		if !isInitialize(data) {
+			return r
+		}
+		sessionID = newSessionID()
+	}
+	w.Header().Set(SessionIDHeader, sessionID)
+	return r.WithContext(WithSessionID(r.Context(), sessionID))
+}
+
+// isInitialize reports whether a message is an initialize request.
+func isInitialize(data []byte) bool {
+	var peek struct {
+		Method string `json:"method"`
+	}
+	return json.Unmarshal(data, &peek) == nil && peek.Method == "initialize"
+}
+
+// newSessionID returns a random, unguessable session ID.
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("mcp: crypto/rand failed: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
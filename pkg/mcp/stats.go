@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ToolStats collects per-tool call counts, error counts and latency
+// histograms in memory, from server start. It is safe for concurrent use.
+type ToolStats struct {
+	mu    sync.Mutex
+	since time.Time
+	slow  time.Duration
+	tools map[string]*toolCounters
+}
+
+// toolCounters are the statistics of one tool.
+type toolCounters struct {
+	calls, errors, slow int64
+	total, max          time.Duration
+	last                time.Time
+	// buckets counts calls by latencyBuckets; the last entry counts calls
+	// above the largest bound
+	buckets []int64
+}
+
+// NewToolStats returns an empty ToolStats counting calls longer than slow
+// as slow calls (0 disables).
+func NewToolStats(slow time.Duration) *ToolStats {
+	return &ToolStats{since: time.Now(), slow: slow, tools: map[string]*toolCounters{}}
+}
+
+// SetSlowThreshold changes the duration above which calls count as slow.
+func (s *ToolStats) SetSlowThreshold(slow time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.slow = slow
+}
+
+// Record adds a tool call and reports whether it was slow.
+func (s *ToolStats) Record(name string, duration time.Duration, failed bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.tools[name]
+	if c == nil {
+		c = &toolCounters{buckets: make([]int64, len(latencyBuckets)+1)}
+		s.tools[name] = c
+	}
+	c.calls++
+	if failed {
+		c.errors++
+	}
+	slow := s.slow > 0 && duration > s.slow
+	if slow {
+		c.slow++
+	}
+	c.total += duration
+	if duration > c.max {
+		c.max = duration
+	}
+	c.last = time.Now()
+	c.buckets[sort.SearchFloat64s(latencyBuckets, duration.Seconds())]++
+	return slow
+}
+
+// ToolStat is the usage of one tool. Percentiles are estimated from the
+// latency histogram: the upper bound of the bucket holding the percentile,
+// capped at the slowest call.
+type ToolStat struct {
+	Name       string          `json:"name"`
+	Calls      int64           `json:"calls"`
+	Errors     int64           `json:"errors"`
+	ErrorRate  float64         `json:"error_rate"`
+	SlowCalls  int64           `json:"slow_calls"`
+	MeanMS     int64           `json:"mean_ms"`
+	P50MS      int64           `json:"p50_ms"`
+	P95MS      int64           `json:"p95_ms"`
+	MaxMS      int64           `json:"max_ms"`
+	LastCalled time.Time       `json:"last_called"`
+	Histogram  []LatencyBucket `json:"histogram"`
+}
+
+// LatencyBucket counts the calls that took at most LEMS milliseconds and
+// longer than the previous bucket. The last bucket has no bound (LEMS 0).
+type LatencyBucket struct {
+	LEMS  int64 `json:"le_ms,omitempty"`
+	Count int64 `json:"count"`
+}
+
+// StatsReport is a snapshot of ToolStats.
+type StatsReport struct {
+	Since           time.Time `json:"since"`
+	UptimeSeconds   int64     `json:"uptime_seconds"`
+	SlowThresholdMS int64     `json:"slow_threshold_ms,omitempty"`
+	Calls           int64     `json:"calls"`
+	Errors          int64     `json:"errors"`
+	SlowCalls       int64     `json:"slow_calls"`
+	// Tools are the tools called at least once, most called first
+	Tools []ToolStat `json:"tools"`
+	// Slowest names the tools with the highest p95 latency, slowest first
+	Slowest []string `json:"slowest"`
+}
+
+// slowestTools is the number of tools listed in StatsReport.Slowest.
+const slowestTools = 5
+
+// Report returns a snapshot of the statistics.
+func (s *ToolStats) Report() StatsReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := StatsReport{
+		Since:           s.since.UTC().Truncate(time.Second),
+		UptimeSeconds:   int64(time.Since(s.since).Seconds()),
+		SlowThresholdMS: s.slow.Milliseconds(),
+		Tools:           []ToolStat{},
+		Slowest:         []string{},
+	}
+	for name, c := range s.tools {
+		stat := ToolStat{
+			Name:       name,
+			Calls:      c.calls,
+			Errors:     c.errors,
+			ErrorRate:  math.Round(float64(c.errors)/float64(c.calls)*1000) / 1000,
+			SlowCalls:  c.slow,
+			MeanMS:     (c.total / time.Duration(c.calls)).Milliseconds(),
+			P50MS:      c.percentile(0.5).Milliseconds(),
+			P95MS:      c.percentile(0.95).Milliseconds(),
+			MaxMS:      c.max.Milliseconds(),
+			LastCalled: c.last.UTC().Truncate(time.Second),
+		}
+		for i, count := range c.buckets {
+			if count == 0 {
+				continue
+			}
+			bucket := LatencyBucket{Count: count}
+			if i < len(latencyBuckets) {
+				bucket.LEMS = int64(latencyBuckets[i] * 1000)
+			}
+			stat.Histogram = append(stat.Histogram, bucket)
+		}
+		report.Calls += c.calls
+		report.Errors += c.errors
+		report.SlowCalls += c.slow
+		report.Tools = append(report.Tools, stat)
+	}
+
+	sort.Slice(report.Tools, func(i, j int) bool {
+		a, b := report.Tools[i], report.Tools[j]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		return a.Name < b.Name
+	})
+	slowest := append([]ToolStat(nil), report.Tools...)
+	sort.SliceStable(slowest, func(i, j int) bool { return slowest[i].P95MS > slowest[j].P95MS })
+	for i := 0; i < len(slowest) && i < slowestTools; i++ {
+		report.Slowest = append(report.Slowest, slowest[i].Name)
+	}
+	return report
+}
+
+// percentile estimates the duration below which the fraction q of calls took.
+func (c *toolCounters) percentile(q float64) time.Duration {
+	rank := int64(math.Ceil(q * float64(c.calls)))
+	var seen int64
+	for i, count := range c.buckets {
+		seen += count
+		if seen >= rank && i < len(latencyBuckets) {
+			bound := time.Duration(latencyBuckets[i] * float64(time.Second))
+			if bound > c.max {
+				return c.max
+			}
+			return bound
+		}
+	}
+	return c.max
+}
+
+// Summary renders the report on one line for the log, e.g.
+//
+//	calls=42 errors=3 slow=1 top=[list_issues:20 get_project:12] slowest=[get_pipeline_job_output:p95=2500ms]
+func (r StatsReport) Summary() string {
+	var top, slowest []string
+	p95 := map[string]int64{}
+	for i, stat := range r.Tools {
+		p95[stat.Name] = stat.P95MS
+		if i < slowestTools {
+			top = append(top, fmt.Sprintf("%s:%d", stat.Name, stat.Calls))
+		}
+	}
+	for _, name := range r.Slowest {
+		slowest = append(slowest, fmt.Sprintf("%s:p95=%dms", name, p95[name]))
+	}
+	return fmt.Sprintf("calls=%d errors=%d slow=%d top=[%s] slowest=[%s]",
+		r.Calls, r.Errors, r.SlowCalls, strings.Join(top, " "), strings.Join(slowest, " "))
+}
+
+// toolStatsKey is the context key of the server's ToolStats.
+type toolStatsKey struct{}
+
+// ToolStatsFromContext returns the usage statistics of the server running
+// the tool call carried by ctx, or nil.
+func ToolStatsFromContext(ctx context.Context) *ToolStats {
+	stats, _ := ctx.Value(toolStatsKey{}).(*ToolStats)
+	return stats
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans and metrics produced by the MCP server.
+const instrumentationName = "github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+
+// toolNameKey is the attribute key carrying the MCP tool name.
+const toolNameKey = attribute.Key("mcp.tool.name")
+
+// requestIDKey is the span attribute key carrying the request correlation ID.
+const requestIDKey = attribute.Key("mcp.request.id")
+
+var (
+	tracer = otel.Tracer(instrumentationName)
+	meter  = otel.Meter(instrumentationName)
+
+	// toolInvocations counts tool calls by tool name.
+	toolInvocations, _ = meter.Int64Counter(
+		"mcp.tool.invocations",
+		metric.WithDescription("Number of MCP tool invocations"),
+		metric.WithUnit("{call}"),
+	)
+
+	// toolErrors counts tool calls that failed or returned an error result.
+	toolErrors, _ = meter.Int64Counter(
+		"mcp.tool.errors",
+		metric.WithDescription("Number of MCP tool invocations that returned an error"),
+		metric.WithUnit("{call}"),
+	)
+
+	// toolDuration records tool execution time by tool name.
+	toolDuration, _ = meter.Float64Histogram(
+		"mcp.tool.duration",
+		metric.WithDescription("Duration of MCP tool invocations"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(latencyBuckets...),
+	)
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the tool duration
+// histogram buckets, shared by the OpenTelemetry metric and ToolStats.
+var latencyBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// startToolSpan starts a server span for a tool call.
+func startToolSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "tools/call "+name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			toolNameKey.String(name),
+			requestIDKey.String(logging.RequestIDFromContext(ctx)),
+		),
+	)
+}
+
+// endToolSpan finishes a tool call span and records tool metrics.
+func endToolSpan(ctx context.Context, span trace.Span, name string, duration time.Duration, result *CallToolResult, err error) {
+	attrs := metric.WithAttributes(toolNameKey.String(name))
+	failed := err != nil || (result != nil && result.IsError)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if failed {
+		span.SetStatus(codes.Error, "tool returned an error result")
+	}
+	span.End()
+
+	toolInvocations.Add(ctx, 1, attrs)
+	toolDuration.Record(ctx, duration.Seconds(), attrs)
+	if failed {
+		toolErrors.Add(ctx, 1, attrs)
+	}
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SetToolTimeouts sets the maximum execution time of tool calls: defaultTimeout
+// applies to every tool without an entry in overrides. Zero disables the limit.
+func (s *Server) SetToolTimeouts(defaultTimeout time.Duration, overrides map[string]time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolTimeout = defaultTimeout
+	s.toolTimeouts = overrides
+}
+
+// SetReadOnlyTools sets the check for tools that only read, whose timeouts are
+// safe to retry. nil treats the tools annotated with ReadOnlyHint as read-only.
+func (s *Server) SetReadOnlyTools(readOnly func(tool Tool) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readOnlyTool = readOnly
+}
+
+// isReadOnly reports whether a tool only reads. The caller must hold s.mu.
+func (s *Server) isReadOnly(tool Tool) bool {
+	if s.readOnlyTool != nil {
+		return s.readOnlyTool(tool)
+	}
+	return tool.Annotations != nil && tool.Annotations.ReadOnlyHint
+}
+
+// timeoutFor returns the timeout for a tool. The caller must hold s.mu.
+func (s *Server) timeoutFor(name string) time.Duration {
+	if timeout, ok := s.toolTimeouts[name]; ok {
+		return timeout
+	}
+	return s.toolTimeout
+}
+
+// toolOutcome carries a handler's return values across goroutines.
+type toolOutcome struct {
+	result *CallToolResult
+	err    error
+}
+
+// runWithTimeout runs handler with a deadline. When the deadline passes the call
+// returns a timeout result immediately, even if the handler ignores its context;
+// the handler's eventual output is discarded, but it keeps the call's
+// concurrency slot until it returns. readOnly marks the timeout retryable.
+func (s *Server) runWithTimeout(ctx context.Context, name string, handler ToolHandler, args map[string]interface{}, timeout time.Duration, readOnly bool) (*CallToolResult, error) {
+	if timeout <= 0 {
+		return handler(ctx, args)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan toolOutcome, 1)
+	slot := toolSlotFromContext(ctx)
+	slot.hold()
+	go func() {
+		defer slot.done()
+		result, err := handler(ctx, args)
+		done <- toolOutcome{result: result, err: err}
+	}()
+
+	var outcome toolOutcome
+	select {
+	case outcome = <-done:
+	case <-ctx.Done():
+		// A result that arrived as the deadline passed still counts
+		select {
+		case outcome = <-done:
+		default:
+			if parent.Err() != nil {
+				// The parent context ended first (e.g. the request was cancelled)
+				return nil, parent.Err()
+			}
+			return s.timedOut(ctx, name, timeout, time.Since(start), readOnly), nil
+		}
+	}
+
+	// A handler that gave up because its context expired also timed out
+	if errors.Is(outcome.err, context.DeadlineExceeded) && parent.Err() == nil {
+		return s.timedOut(ctx, name, timeout, time.Since(start), readOnly), nil
+	}
+	return outcome.result, outcome.err
+}
+
+// timedOut logs a tool call that exceeded its timeout and returns its result.
+func (s *Server) timedOut(ctx context.Context, name string, timeout, elapsed time.Duration, readOnly bool) *CallToolResult {
+	s.logWarn(ctx, "Tool %s timed out after %s (limit %s)", name, elapsed.Round(time.Millisecond), timeout)
+	return timeoutResult(name, timeout, elapsed, readOnly)
+}
+
+// timeoutResult builds the error result for a tool call that exceeded its
+// timeout, with a machine-readable error payload like GitLab API errors. Only
+// read-only tools are retryable: a write may still complete after the timeout.
+func timeoutResult(name string, timeout, elapsed time.Duration, readOnly bool) *CallToolResult {
+	hint := "The tool did not finish in time. Narrow the request (filters, smaller per_page, head/tail for logs) before retrying."
+	if !readOnly {
+		hint = "The tool did not finish in time and its changes may still be applied. Check the current state in GitLab before trying again."
+	}
+	details, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{
+			"timeout":         true,
+			"tool":            name,
+			"timeout_seconds": timeout.Seconds(),
+			"elapsed_ms":      elapsed.Milliseconds(),
+			"retryable":       readOnly,
+			"hint":            hint,
+		},
+	})
+	return &CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: fmt.Sprintf("Tool %s timed out after %s", name, timeout)},
+			{Type: "text", Text: string(details)},
+		},
+		IsError: true,
+	}
+}
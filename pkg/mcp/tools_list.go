@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+// SetToolsPageSize sets the maximum number of tools returned per tools/list
+// page. Zero (the default) returns all tools in a single page.
+func (s *Server) SetToolsPageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if size < 0 {
+		size = 0
+	}
+	s.toolsPageSize = size
+}
+
+// ReplaceTools atomically replaces the registered tools with those registered
+// by register, then notifies the client with notifications/tools/list_changed
+// if the set of tool names changed. In-flight calls keep their old handlers.
+func (s *Server) ReplaceTools(register func(*Server)) {
+	s.mu.RLock()
+	staging := &Server{handlers: make(map[string]ToolHandler), toolFilter: s.toolFilter, middleware: s.middleware}
+	s.mu.RUnlock()
+	register(staging)
+
+	s.mu.Lock()
+	changed := !sameToolNames(s.tools, staging.tools)
+	s.tools = staging.tools
+	s.handlers = staging.handlers
+	s.mu.Unlock()
+
+	if changed {
+		s.NotifyToolsListChanged()
+	}
+}
+
+// NotifyToolsListChanged sends notifications/tools/list_changed so the client
+// re-fetches tools/list. It is a no-op in HTTP mode, which cannot push messages.
+func (s *Server) NotifyToolsListChanged() {
+	s.mu.RLock()
+	enabled := s.notifications
+	s.mu.RUnlock()
+	if !enabled {
+		return
+	}
+	s.sendNotification("notifications/tools/list_changed", nil)
+}
+
+// handleListTools returns one page of the tools visible to the caller.
+// Cursors are opaque to clients and encode the offset of the next tool.
+func (s *Server) handleListTools(ctx context.Context, params interface{}) (*ListToolsResult, *JSONRPCError) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tools := s.visibleTools(ctx)
+
+	start := 0
+	if paramsMap, ok := params.(map[string]interface{}); ok {
+		if cursor, _ := paramsMap["cursor"].(string); cursor != "" {
+			offset, err := decodeCursor(cursor)
+			if err != nil || offset > len(tools) {
+				return nil, &JSONRPCError{Code: InvalidParams, Message: "Invalid cursor"}
+			}
+			start = offset
+		}
+	}
+
+	end := len(tools)
+	if s.toolsPageSize > 0 && start+s.toolsPageSize < end {
+		end = start + s.toolsPageSize
+	}
+
+	result := &ListToolsResult{Tools: tools[start:end]}
+	if end < len(tools) {
+		result.NextCursor = encodeCursor(end)
+	}
+	return result, nil
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("tools:%d", offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	var offset string
+	if _, err := fmt.Sscanf(string(raw), "tools:%s", &offset); err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(offset)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid cursor offset %q", offset)
+	}
+	return n, nil
+}
+
+// sameToolNames reports whether two tool lists have the same names in the same order.
+func sameToolNames(a, b []Tool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name {
+			return false
+		}
+	}
+	return true
+}
@@ -1,5 +1,7 @@
 package mcp
 
+import "encoding/base64"
+
 // JSON-RPC types
 type JSONRPCRequest struct {
 	JSONRPC string      `json:"jsonrpc"`
@@ -66,10 +68,13 @@ type InitializeResult struct {
 
 // Tool types
 type Tool struct {
-	Name        string           `json:"name"`
-	Description string           `json:"description,omitempty"`
-	InputSchema JSONSchema       `json:"inputSchema"`
-	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	InputSchema JSONSchema `json:"inputSchema"`
+	// OutputSchema describes structuredContent returned by the tool (protocol 2025-06-18+).
+	// When set, successful results must include conforming structuredContent.
+	OutputSchema *JSONSchema      `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
 }
 
 // ToolAnnotations provides hints about tool behavior for LLM clients.
@@ -109,7 +114,8 @@ func IntPtr(v int) *int {
 }
 
 type ListToolsResult struct {
-	Tools []Tool `json:"tools"`
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 type CallToolParams struct {
@@ -119,12 +125,27 @@ type CallToolParams struct {
 
 type CallToolResult struct {
 	Content []ContentItem `json:"content"`
-	IsError bool          `json:"isError,omitempty"`
+	// StructuredContent is the JSON object form of the result (protocol 2025-06-18+).
+	// Content should still carry the serialized JSON for older clients.
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
 }
 
 type ContentItem struct {
 	Type string `json:"type"`
 	Text string `json:"text,omitempty"`
+	// Data and MimeType carry the base64 payload of "image" items.
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// ImageContent returns an "image" content item holding data as base64.
+func ImageContent(data []byte, mimeType string) ContentItem {
+	return ContentItem{
+		Type:     "image",
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: mimeType,
+	}
 }
 
 // Error codes
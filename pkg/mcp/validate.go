@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Violation is an argument that does not match a tool's input schema.
+type Violation struct {
+	// Path is the argument, with array indexes and object keys for nested
+	// values (e.g., "labels[2]" or "options.mode").
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// InvalidArgumentsError is returned for a tools/call whose arguments do not
+// match the tool's input schema. It is sent as an InvalidParams error listing
+// every violation.
+type InvalidArgumentsError struct {
+	Tool       string
+	Violations []Violation
+}
+
+func (e *InvalidArgumentsError) Error() string {
+	problems := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		problems[i] = v.Path + ": " + v.Message
+	}
+	return fmt.Sprintf("Invalid arguments for tool %s: %s", e.Tool, strings.Join(problems, "; "))
+}
+
+// ValidateArguments checks tool arguments against an input schema: required
+// arguments, types, enums and minimum/maximum. Null values count as omitted
+// and arguments the schema does not declare are allowed. Violations are
+// sorted by path.
+func ValidateArguments(schema JSONSchema, args map[string]interface{}) []Violation {
+	var violations []Violation
+	for _, name := range schema.Required {
+		if value, ok := args[name]; !ok || value == nil {
+			violations = append(violations, Violation{Path: name, Message: "is required"})
+		}
+	}
+	for name, value := range args {
+		if property, ok := schema.Properties[name]; ok && value != nil {
+			violations = append(violations, validateValue(name, property, value)...)
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	return violations
+}
+
+// validateValue checks a non-null value against a property schema.
+func validateValue(path string, property Property, value interface{}) []Violation {
+	violation := func(format string, args ...interface{}) []Violation {
+		return []Violation{{Path: path, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	switch property.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return violation("must be a string, got %s", jsonType(value))
+		}
+		if len(property.Enum) > 0 && !containsString(property.Enum, s) {
+			return violation("must be one of: %s", strings.Join(property.Enum, ", "))
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			return violation("must be %s, got %s", article(property.Type), jsonType(value))
+		}
+		if property.Type == "integer" && n != math.Trunc(n) {
+			return violation("must be an integer, got %v", n)
+		}
+		if property.Minimum != nil && n < float64(*property.Minimum) {
+			return violation("must be at least %d", *property.Minimum)
+		}
+		if property.Maximum != nil && n > float64(*property.Maximum) {
+			return violation("must be at most %d", *property.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return violation("must be a boolean, got %s", jsonType(value))
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return violation("must be an array, got %s", jsonType(value))
+		}
+		if property.Items == nil {
+			return nil
+		}
+		var violations []Violation
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if item == nil {
+				violations = append(violations, Violation{Path: itemPath, Message: "must not be null"})
+				continue
+			}
+			violations = append(violations, validateValue(itemPath, *property.Items, item)...)
+		}
+		return violations
+	case "object":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return violation("must be an object, got %s", jsonType(value))
+		}
+		var violations []Violation
+		for name, field := range fields {
+			if p, ok := property.Properties[name]; ok && field != nil {
+				violations = append(violations, validateValue(path+"."+name, p, field)...)
+			}
+		}
+		return violations
+	}
+	return nil
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// article prefixes a type name with "a" or "an".
+func article(typeName string) string {
+	if strings.ContainsAny(typeName[:1], "aeiou") {
+		return "an " + typeName
+	}
+	return "a " + typeName
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+// Package redact masks secrets in text before it is returned to MCP clients.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule masks the matches of a pattern. When the pattern has a capture group,
+// only the first group is masked, so surrounding context (e.g. a variable
+// name) stays readable.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// SecretRules are the builtin rules for common credentials.
+var SecretRules = []Rule{
+	{"PRIVATE-KEY", regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----[\s\S]*?-----END [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`)},
+	{"AWS-ACCESS-KEY", regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16}\b`)},
+	{"AWS-SECRET-KEY", regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`)},
+	{"GITLAB-TOKEN", regexp.MustCompile(`\bgl(?:pat|dt|ptt|rt|soat|oas|cbt|ft|imt|agent|ffct|rtr)-[0-9A-Za-z_\-]{20,}`)},
+	{"GITLAB-RUNNER-TOKEN", regexp.MustCompile(`\bGR1348941[0-9A-Za-z_\-]{20,}`)},
+	{"GITHUB-TOKEN", regexp.MustCompile(`\b(?:gh[pousr]_[0-9A-Za-z]{36,}|github_pat_[0-9A-Za-z_]{60,})\b`)},
+	{"SLACK-TOKEN", regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z\-]{10,}`)},
+	{"JWT", regexp.MustCompile(`\beyJ[0-9A-Za-z_\-]{8,}\.eyJ[0-9A-Za-z_\-]{8,}\.[0-9A-Za-z_\-]{8,}`)},
+	{"AUTH-HEADER", regexp.MustCompile(`(?i)\bauthorization:\s*(?:bearer|basic|token)\s+([^\s'"]+)`)},
+	{"URL-PASSWORD", regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.\-]*://[^/\s:@]+:([^/\s@]+)@`)},
+}
+
+// PIIRules are the builtin rules for personal data, applied to tool results
+// but not to job logs.
+var PIIRules = []Rule{
+	{"EMAIL", regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}\b`)},
+}
+
+// Redactor masks the matches of a list of rules.
+type Redactor struct {
+	rules []Rule
+}
+
+// New returns a Redactor applying rules followed by the custom patterns,
+// which are named CUSTOM. It fails if a custom pattern does not compile.
+func New(rules []Rule, custom []string) (*Redactor, error) {
+	r := &Redactor{rules: append([]Rule{}, rules...)}
+	for _, pattern := range custom {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.rules = append(r.rules, Rule{Name: "CUSTOM", Pattern: re})
+	}
+	return r, nil
+}
+
+// Redact replaces each match with [<NAME>-REDACTED] and returns the result
+// and the number of replacements.
+func (r *Redactor) Redact(text string) (string, int) {
+	if r == nil {
+		return text, 0
+	}
+	total := 0
+	for _, rule := range r.rules {
+		var n int
+		text, n = rule.apply(text)
+		total += n
+	}
+	return text, total
+}
+
+// apply masks the matches of one rule.
+func (rule Rule) apply(text string) (string, int) {
+	matches := rule.Pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text, 0
+	}
+
+	replacement := "[" + rule.Name + "-REDACTED]"
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		// Mask only the first capture group when it matched
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		sb.WriteString(text[last:start])
+		sb.WriteString(replacement)
+		last = end
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), len(matches)
+}
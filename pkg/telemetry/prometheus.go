@@ -0,0 +1,187 @@
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// prometheusContentType is the Prometheus text exposition format version 0.0.4.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusHandler serves metrics collected by a ManualReader in the
+// Prometheus text exposition format. It lets the same OpenTelemetry
+// instruments feed both OTLP export and a scrapeable /metrics endpoint.
+type PrometheusHandler struct {
+	reader *sdkmetric.ManualReader
+}
+
+// ServeHTTP collects the current metric state and writes it as Prometheus text.
+func (h *PrometheusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var rm metricdata.ResourceMetrics
+	if err := h.reader.Collect(r.Context(), &rm); err != nil {
+		http.Error(w, fmt.Sprintf("failed to collect metrics: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	bw := bufio.NewWriter(w)
+	writePrometheus(bw, &rm)
+	bw.Flush()
+}
+
+// collect is used by tests to gather metrics without an HTTP round trip.
+func (h *PrometheusHandler) collect(ctx context.Context) (string, error) {
+	var rm metricdata.ResourceMetrics
+	if err := h.reader.Collect(ctx, &rm); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	writePrometheus(&sb, &rm)
+	return sb.String(), nil
+}
+
+// writePrometheus renders resource metrics in the Prometheus text format.
+// Metric and label names are sanitized (dots become underscores), counters get
+// a _total suffix, and second-based instruments get a _seconds suffix.
+func writePrometheus(w io.Writer, rm *metricdata.ResourceMetrics) {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			name := prometheusName(m.Name, m.Unit)
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				writeSum(w, name, m.Description, data.IsMonotonic, data.DataPoints)
+			case metricdata.Sum[float64]:
+				writeSum(w, name, m.Description, data.IsMonotonic, data.DataPoints)
+			case metricdata.Gauge[int64]:
+				writeGauge(w, name, m.Description, data.DataPoints)
+			case metricdata.Gauge[float64]:
+				writeGauge(w, name, m.Description, data.DataPoints)
+			case metricdata.Histogram[float64]:
+				writeHistogram(w, name, m.Description, data.DataPoints)
+			case metricdata.Histogram[int64]:
+				writeHistogram(w, name, m.Description, data.DataPoints)
+			}
+		}
+	}
+}
+
+func writeSum[N int64 | float64](w io.Writer, name, help string, monotonic bool, points []metricdata.DataPoint[N]) {
+	metricType := "gauge"
+	if monotonic {
+		name += "_total"
+		metricType = "counter"
+	}
+	writeHeader(w, name, help, metricType)
+	for _, dp := range sortedPoints(points) {
+		fmt.Fprintf(w, "%s%s %s\n", name, formatLabels(dp.Attributes, "", ""), formatValue(float64(dp.Value)))
+	}
+}
+
+func writeGauge[N int64 | float64](w io.Writer, name, help string, points []metricdata.DataPoint[N]) {
+	writeHeader(w, name, help, "gauge")
+	for _, dp := range sortedPoints(points) {
+		fmt.Fprintf(w, "%s%s %s\n", name, formatLabels(dp.Attributes, "", ""), formatValue(float64(dp.Value)))
+	}
+}
+
+func writeHistogram[N int64 | float64](w io.Writer, name, help string, points []metricdata.HistogramDataPoint[N]) {
+	writeHeader(w, name, help, "histogram")
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Attributes.Encoded(attribute.DefaultEncoder()) < points[j].Attributes.Encoded(attribute.DefaultEncoder())
+	})
+	for _, dp := range points {
+		var cumulative uint64
+		for i, bound := range dp.Bounds {
+			if i < len(dp.BucketCounts) {
+				cumulative += dp.BucketCounts[i]
+			}
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(dp.Attributes, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(dp.Attributes, "le", "+Inf"), dp.Count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", name, formatLabels(dp.Attributes, "", ""), formatValue(float64(dp.Sum)))
+		fmt.Fprintf(w, "%s_count%s %d\n", name, formatLabels(dp.Attributes, "", ""), dp.Count)
+	}
+}
+
+func writeHeader(w io.Writer, name, help, metricType string) {
+	if help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", name, strings.ReplaceAll(help, "\n", " "))
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+// sortedPoints orders data points by their attribute set for stable output.
+func sortedPoints[N int64 | float64](points []metricdata.DataPoint[N]) []metricdata.DataPoint[N] {
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Attributes.Encoded(attribute.DefaultEncoder()) < points[j].Attributes.Encoded(attribute.DefaultEncoder())
+	})
+	return points
+}
+
+// prometheusName converts an OpenTelemetry instrument name to a Prometheus metric name.
+func prometheusName(name, unit string) string {
+	n := sanitizeName(name)
+	if unit == "s" && !strings.HasSuffix(n, "_seconds") {
+		n += "_seconds"
+	}
+	return n
+}
+
+// sanitizeName replaces characters that are invalid in Prometheus names with underscores.
+func sanitizeName(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9' && i > 0:
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+// formatLabels renders an attribute set as a Prometheus label block.
+// If extraKey is non-empty, it is appended as an additional label (used for "le").
+func formatLabels(attrs attribute.Set, extraKey, extraValue string) string {
+	if attrs.Len() == 0 && extraKey == "" {
+		return ""
+	}
+	parts := make([]string, 0, attrs.Len()+1)
+	iter := attrs.Iter()
+	for iter.Next() {
+		kv := iter.Attribute()
+		parts = append(parts, fmt.Sprintf("%s=%s", sanitizeName(string(kv.Key)), strconv.Quote(kv.Value.Emit())))
+	}
+	if extraKey != "" {
+		parts = append(parts, fmt.Sprintf("%s=%s", extraKey, strconv.Quote(extraValue)))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// formatValue renders a sample value the way Prometheus expects.
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,110 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func newTestHandler(t *testing.T) (*PrometheusHandler, metric.Meter) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return &PrometheusHandler{reader: reader}, provider.Meter("test")
+}
+
+func TestPrometheus_Counter(t *testing.T) {
+	handler, meter := newTestHandler(t)
+	ctx := context.Background()
+
+	counter, err := meter.Int64Counter("mcp.tool.invocations", metric.WithDescription("Number of MCP tool invocations"))
+	if err != nil {
+		t.Fatalf("Failed to create counter: %v", err)
+	}
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("mcp.tool.name", "list_issues")))
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("mcp.tool.name", "get_project")))
+
+	output, err := handler.collect(ctx)
+	if err != nil {
+		t.Fatalf("Failed to collect: %v", err)
+	}
+
+	expected := []string{
+		"# HELP mcp_tool_invocations_total Number of MCP tool invocations",
+		"# TYPE mcp_tool_invocations_total counter",
+		`mcp_tool_invocations_total{mcp_tool_name="get_project"} 1`,
+		`mcp_tool_invocations_total{mcp_tool_name="list_issues"} 2`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestPrometheus_Histogram(t *testing.T) {
+	handler, meter := newTestHandler(t)
+	ctx := context.Background()
+
+	hist, err := meter.Float64Histogram("gitlab.api.duration",
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.1, 1),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create histogram: %v", err)
+	}
+	attrs := metric.WithAttributes(attribute.String("http.request.method", "GET"))
+	hist.Record(ctx, 0.05, attrs)
+	hist.Record(ctx, 0.5, attrs)
+	hist.Record(ctx, 2, attrs)
+
+	output, err := handler.collect(ctx)
+	if err != nil {
+		t.Fatalf("Failed to collect: %v", err)
+	}
+
+	expected := []string{
+		"# TYPE gitlab_api_duration_seconds histogram",
+		`gitlab_api_duration_seconds_bucket{http_request_method="GET",le="0.1"} 1`,
+		`gitlab_api_duration_seconds_bucket{http_request_method="GET",le="1"} 2`,
+		`gitlab_api_duration_seconds_bucket{http_request_method="GET",le="+Inf"} 3`,
+		`gitlab_api_duration_seconds_sum{http_request_method="GET"} 2.55`,
+		`gitlab_api_duration_seconds_count{http_request_method="GET"} 3`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestPrometheus_ServeHTTP(t *testing.T) {
+	handler, meter := newTestHandler(t)
+
+	gauge, err := meter.Int64UpDownCounter("queue.depth")
+	if err != nil {
+		t.Fatalf("Failed to create up-down counter: %v", err)
+	}
+	gauge.Add(context.Background(), 3)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != prometheusContentType {
+		t.Errorf("Expected Content-Type %q, got %q", prometheusContentType, ct)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "# TYPE queue_depth gauge") || !strings.Contains(body, "queue_depth 3") {
+		t.Errorf("Expected non-monotonic sum rendered as gauge, got:\n%s", body)
+	}
+}
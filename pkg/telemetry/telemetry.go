@@ -0,0 +1,127 @@
+// Package telemetry configures OpenTelemetry tracing and metrics export.
+// Exporters are configured entirely through the standard OTEL_* environment
+// variables (OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_HEADERS,
+// OTEL_SERVICE_NAME, OTEL_RESOURCE_ATTRIBUTES, etc.). When no OTLP endpoint
+// is configured, the global no-op providers are left in place and
+// instrumentation in pkg/mcp and pkg/gitlab costs next to nothing.
+//
+// In HTTP mode the same instruments can also be scraped from /metrics in the
+// Prometheus text format (see PrometheusHandler).
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// ShutdownFunc flushes and stops the configured providers.
+type ShutdownFunc func(ctx context.Context) error
+
+// Config controls which telemetry pipelines are installed.
+type Config struct {
+	ServiceName    string
+	ServiceVersion string
+	// Prometheus enables an in-process metrics reader served by Prometheus().
+	// It works independently of OTLP export.
+	Prometheus bool
+}
+
+// prometheusHandler is set by Init when Config.Prometheus is true.
+var prometheusHandler *PrometheusHandler
+
+// Prometheus returns the handler for the /metrics endpoint, or nil if
+// Prometheus metrics were not enabled in Init.
+func Prometheus() *PrometheusHandler {
+	return prometheusHandler
+}
+
+// Enabled reports whether OTLP export is configured via environment variables.
+// OTEL_SDK_DISABLED=true always disables export.
+func Enabled() bool {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("OTEL_SDK_DISABLED")), "true") {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT") != ""
+}
+
+// Init installs global tracer and meter providers. Traces and metrics are
+// exported via OTLP/HTTP when Enabled reports true; metrics are additionally
+// kept for Prometheus scraping when cfg.Prometheus is set. If neither is
+// enabled, Init is a no-op and returns a no-op ShutdownFunc.
+func Init(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+	otlpEnabled := Enabled()
+	if !otlpEnabled && !cfg.Prometheus {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// Attributes from OTEL_SERVICE_NAME / OTEL_RESOURCE_ATTRIBUTES override the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+		),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telemetry resource: %w", err)
+	}
+
+	meterOpts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	if cfg.Prometheus {
+		reader := sdkmetric.NewManualReader()
+		prometheusHandler = &PrometheusHandler{reader: reader}
+		meterOpts = append(meterOpts, sdkmetric.WithReader(reader))
+	}
+
+	var tracerProvider *sdktrace.TracerProvider
+	if otlpEnabled {
+		traceExporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		tracerProvider = sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(traceExporter),
+			sdktrace.WithResource(res),
+		)
+
+		metricExporter, err := otlpmetrichttp.New(ctx)
+		if err != nil {
+			_ = tracerProvider.Shutdown(ctx)
+			return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+		}
+		meterOpts = append(meterOpts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)))
+
+		otel.SetTracerProvider(tracerProvider)
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		))
+	}
+
+	meterProvider := sdkmetric.NewMeterProvider(meterOpts...)
+	otel.SetMeterProvider(meterProvider)
+
+	return func(ctx context.Context) error {
+		var traceErr error
+		if tracerProvider != nil {
+			traceErr = tracerProvider.Shutdown(ctx)
+		}
+		return errors.Join(traceErr, meterProvider.Shutdown(ctx))
+	}, nil
+}
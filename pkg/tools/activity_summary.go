@@ -0,0 +1,397 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// defaultActivityItems is how many items per section the activity summary
+// lists by default; the totals always count every item in the range.
+const defaultActivityItems = 20
+
+// ActivityTotals counts the activity in the summarized range.
+type ActivityTotals struct {
+	Commits             int `json:"commits"`
+	MergedMergeRequests int `json:"merged_merge_requests"`
+	ClosedIssues        int `json:"closed_issues"`
+	FailedPipelines     int `json:"failed_pipelines"`
+}
+
+// ActivityCommit is a commit, or a push of commits when the summary is
+// built from a user's push events.
+type ActivityCommit struct {
+	ShortID string     `json:"short_id,omitempty"`
+	Title   string     `json:"title"`
+	Author  string     `json:"author,omitempty"`
+	Ref     string     `json:"ref,omitempty"`
+	Count   int        `json:"count,omitempty"`
+	At      *time.Time `json:"at"`
+	WebURL  string     `json:"web_url,omitempty"`
+}
+
+// ActivityItem is a merged merge request or a closed issue. At is the merge
+// or close time.
+type ActivityItem struct {
+	IID    int        `json:"iid"`
+	Title  string     `json:"title"`
+	Author string     `json:"author,omitempty"`
+	By     string     `json:"by,omitempty"`
+	At     *time.Time `json:"at"`
+	WebURL string     `json:"web_url"`
+}
+
+// ActivityPipeline is a failed pipeline.
+type ActivityPipeline struct {
+	ID     int        `json:"id"`
+	Ref    string     `json:"ref"`
+	Source string     `json:"source,omitempty"`
+	User   string     `json:"user,omitempty"`
+	At     *time.Time `json:"at"`
+	WebURL string     `json:"web_url"`
+}
+
+// ActivitySummary is the response of the generate_activity_summary tool.
+// The item lists hold at most max_items entries each, newest first; Complete
+// is false when a collection exceeded maxCollectedItems.
+type ActivitySummary struct {
+	ProjectID           string             `json:"project_id,omitempty"`
+	Username            string             `json:"username,omitempty"`
+	Since               time.Time          `json:"since"`
+	Until               time.Time          `json:"until"`
+	GeneratedAt         time.Time          `json:"generated_at"`
+	Headline            string             `json:"headline"`
+	Totals              ActivityTotals     `json:"totals"`
+	CommitAuthors       map[string]int     `json:"commit_authors,omitempty"`
+	Commits             []ActivityCommit   `json:"commits"`
+	MergedMergeRequests []ActivityItem     `json:"merged_merge_requests"`
+	ClosedIssues        []ActivityItem     `json:"closed_issues"`
+	FailedPipelines     []ActivityPipeline `json:"failed_pipelines"`
+	Complete            bool               `json:"complete"`
+	Notes               []string           `json:"notes,omitempty"`
+}
+
+// parseActivityTime parses an ISO 8601 timestamp or a plain date. A plain
+// date used as the end of the range covers the whole day.
+func parseActivityTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an ISO 8601 timestamp nor a date (YYYY-MM-DD)", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, nil
+}
+
+// inRange reports whether t lies within [since, until].
+func inRange(t *time.Time, since, until time.Time) bool {
+	return t != nil && !t.Before(since) && !t.After(until)
+}
+
+// usernameOf returns the username of u, or "" for nil.
+func usernameOf(u *gitlab.User) string {
+	if u == nil {
+		return ""
+	}
+	return u.Username
+}
+
+// newestFirst sorts items by their time, newest first.
+func newestFirst[T any](items []T, at func(T) *time.Time) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := at(items[i]), at(items[j])
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.After(*b)
+	})
+}
+
+type generateActivitySummaryArgs struct {
+	ProjectID string `json:"project_id"`
+	Username  string `json:"username"`
+	Since     string `json:"since"`
+	Until     string `json:"until"`
+	MaxItems  int    `json:"max_items" validate:"min=1,max=100"`
+}
+
+// registerGenerateActivitySummary registers the generate_activity_summary tool.
+func registerGenerateActivitySummary(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "generate_activity_summary",
+			Description: "Summarize the activity of a project or a user over a date range for standup or status posts: commits, merged merge requests, closed issues and failed pipelines, as totals plus the newest items of each. With project_id and username, only that user's work in the project is counted (commits they authored, MRs they authored, issues assigned to them, pipelines they triggered). With only username, activity across all projects is summarized; commits come from the user's push events and pipelines are not included.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project). Required unless username is set",
+					},
+					"username": {
+						Type:        "string",
+						Description: "Only summarize this user's activity (e.g., jdoe). Required unless project_id is set",
+					},
+					"since": {
+						Type:        "string",
+						Description: "Start of the range (ISO 8601, e.g., 2024-01-01T09:00:00Z, or a date). Default: 24 hours before until",
+					},
+					"until": {
+						Type:        "string",
+						Description: "End of the range (ISO 8601 or a date, which covers the whole day). Default: now",
+					},
+					"max_items": {
+						Type:        "integer",
+						Description: "Items listed per section (max 100). Default: 20",
+						Default:     defaultActivityItems,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("generate_activity_summary", func(ctx context.Context, c *ToolContext, args generateActivitySummaryArgs) (*mcp.CallToolResult, error) {
+			if args.ProjectID == "" && args.Username == "" {
+				return ErrorResult("project_id or username is required")
+			}
+			maxItems := args.MaxItems
+			if maxItems == 0 {
+				maxItems = defaultActivityItems
+			}
+
+			now := time.Now().UTC()
+			until := now
+			if args.Until != "" {
+				t, err := parseActivityTime(args.Until, true)
+				if err != nil {
+					return ErrorResult("invalid until: " + err.Error())
+				}
+				until = t
+			}
+			since := until.Add(-24 * time.Hour)
+			if args.Since != "" {
+				t, err := parseActivityTime(args.Since, false)
+				if err != nil {
+					return ErrorResult("invalid since: " + err.Error())
+				}
+				since = t
+			}
+			if !since.Before(until) {
+				return ErrorResult("since must be before until")
+			}
+
+			summary := ActivitySummary{
+				ProjectID:           args.ProjectID,
+				Username:            args.Username,
+				Since:               since,
+				Until:               until,
+				GeneratedAt:         now,
+				Commits:             []ActivityCommit{},
+				MergedMergeRequests: []ActivityItem{},
+				ClosedIssues:        []ActivityItem{},
+				FailedPipelines:     []ActivityPipeline{},
+				Complete:            true,
+			}
+			sinceParam, untilParam := since.Format(time.RFC3339), until.Format(time.RFC3339)
+
+			// Project endpoints, or the instance-wide ones when summarizing a user
+			prefix := ""
+			scope := url.Values{}
+			if args.ProjectID != "" {
+				prefix = fmt.Sprintf("/projects/%s", url.PathEscape(args.ProjectID))
+			} else {
+				scope.Set("scope", "all")
+			}
+			query := func(params url.Values) string {
+				for key, values := range scope {
+					params[key] = values
+				}
+				return "?" + params.Encode()
+			}
+
+			mcp.ReportProgress(ctx, 0, 4, "Listing commits")
+			if args.ProjectID != "" {
+				params := url.Values{}
+				params.Set("since", sinceParam)
+				params.Set("until", untilParam)
+				params.Set("all", "true")
+				if args.Username != "" {
+					// The commits API filters by author name or email, not username
+					var users []gitlab.User
+					if err := c.Client.Get(ctx, "/users?username="+url.QueryEscape(args.Username), &users); err != nil {
+						return APIErrorResult("Failed to look up user", err)
+					}
+					if len(users) == 0 {
+						return ErrorResult(fmt.Sprintf("user %s not found", args.Username))
+					}
+					params.Set("author", users[0].Name)
+				}
+				summary.CommitAuthors = map[string]int{}
+				complete, err := streamPages(ctx, c.Client, prefix+"/repository/commits?"+params.Encode(), maxCollectedItems, func(commit gitlab.Commit) {
+					summary.Totals.Commits++
+					summary.CommitAuthors[commit.AuthorName]++
+					summary.Commits = append(summary.Commits, ActivityCommit{
+						ShortID: commit.ShortID,
+						Title:   commit.Title,
+						Author:  commit.AuthorName,
+						At:      commit.AuthoredDate,
+						WebURL:  commit.WebURL,
+					})
+				})
+				if err != nil {
+					return APIErrorResult("Failed to list commits", err)
+				}
+				summary.Complete = summary.Complete && complete
+			} else {
+				// Events are filtered by day, so the range is widened and then
+				// narrowed to the exact times
+				params := url.Values{}
+				params.Set("action", "pushed")
+				params.Set("after", since.AddDate(0, 0, -1).Format("2006-01-02"))
+				params.Set("before", until.AddDate(0, 0, 1).Format("2006-01-02"))
+				complete, err := streamPages(ctx, c.Client, fmt.Sprintf("/users/%s/events?%s", url.PathEscape(args.Username), params.Encode()), maxCollectedItems, func(event Event) {
+					if event.PushData == nil || !inRange(event.CreatedAt, since, until) {
+						return
+					}
+					summary.Totals.Commits += event.PushData.CommitCount
+					summary.Commits = append(summary.Commits, ActivityCommit{
+						Title: event.PushData.CommitTitle,
+						Ref:   event.PushData.Ref,
+						Count: event.PushData.CommitCount,
+						At:    event.CreatedAt,
+					})
+				})
+				if err != nil {
+					return APIErrorResult("Failed to list push events", err)
+				}
+				summary.Complete = summary.Complete && complete
+				summary.Notes = append(summary.Notes, "Commits are counted from the user's push events; each entry is one push.")
+			}
+
+			mcp.ReportProgress(ctx, 1, 4, "Listing merged merge requests")
+			params := url.Values{}
+			params.Set("state", "merged")
+			params.Set("updated_after", sinceParam)
+			if args.Username != "" {
+				params.Set("author_username", args.Username)
+			}
+			complete, err := streamPages(ctx, c.Client, prefix+"/merge_requests"+query(params), maxCollectedItems, func(mr gitlab.MergeRequest) {
+				// updated_after also matches MRs merged earlier and touched since
+				if !inRange(mr.MergedAt, since, until) {
+					return
+				}
+				summary.Totals.MergedMergeRequests++
+				summary.MergedMergeRequests = append(summary.MergedMergeRequests, ActivityItem{
+					IID:    mr.IID,
+					Title:  mr.Title,
+					Author: usernameOf(mr.Author),
+					By:     usernameOf(mr.MergedBy),
+					At:     mr.MergedAt,
+					WebURL: mr.WebURL,
+				})
+			})
+			if err != nil {
+				return APIErrorResult("Failed to list merged merge requests", err)
+			}
+			summary.Complete = summary.Complete && complete
+
+			mcp.ReportProgress(ctx, 2, 4, "Listing closed issues")
+			params = url.Values{}
+			params.Set("state", "closed")
+			params.Set("updated_after", sinceParam)
+			if args.Username != "" {
+				params.Set("assignee_username", args.Username)
+			}
+			complete, err = streamPages(ctx, c.Client, prefix+"/issues"+query(params), maxCollectedItems, func(issue gitlab.Issue) {
+				if !inRange(issue.ClosedAt, since, until) {
+					return
+				}
+				summary.Totals.ClosedIssues++
+				summary.ClosedIssues = append(summary.ClosedIssues, ActivityItem{
+					IID:    issue.IID,
+					Title:  issue.Title,
+					Author: usernameOf(issue.Author),
+					By:     usernameOf(issue.ClosedBy),
+					At:     issue.ClosedAt,
+					WebURL: issue.WebURL,
+				})
+			})
+			if err != nil {
+				return APIErrorResult("Failed to list closed issues", err)
+			}
+			summary.Complete = summary.Complete && complete
+
+			mcp.ReportProgress(ctx, 3, 4, "Listing failed pipelines")
+			if args.ProjectID != "" {
+				params = url.Values{}
+				params.Set("status", "failed")
+				params.Set("updated_after", sinceParam)
+				params.Set("updated_before", untilParam)
+				if args.Username != "" {
+					params.Set("username", args.Username)
+				}
+				complete, err = streamPages(ctx, c.Client, prefix+"/pipelines?"+params.Encode(), maxCollectedItems, func(pipeline gitlab.Pipeline) {
+					summary.Totals.FailedPipelines++
+					at := pipeline.FinishedAt
+					if at == nil {
+						at = pipeline.UpdatedAt
+					}
+					summary.FailedPipelines = append(summary.FailedPipelines, ActivityPipeline{
+						ID:     pipeline.ID,
+						Ref:    pipeline.Ref,
+						Source: pipeline.Source,
+						User:   usernameOf(pipeline.User),
+						At:     at,
+						WebURL: pipeline.WebURL,
+					})
+				})
+				if err != nil {
+					return APIErrorResult("Failed to list failed pipelines", err)
+				}
+				summary.Complete = summary.Complete && complete
+			} else {
+				summary.Notes = append(summary.Notes, "Failed pipelines are only summarized for a project; set project_id to include them.")
+			}
+			mcp.ReportProgress(ctx, 4, 4, "Summary complete")
+
+			newestFirst(summary.Commits, func(item ActivityCommit) *time.Time { return item.At })
+			newestFirst(summary.MergedMergeRequests, func(item ActivityItem) *time.Time { return item.At })
+			newestFirst(summary.ClosedIssues, func(item ActivityItem) *time.Time { return item.At })
+			newestFirst(summary.FailedPipelines, func(item ActivityPipeline) *time.Time { return item.At })
+			summary.Commits = summary.Commits[:min(len(summary.Commits), maxItems)]
+			summary.MergedMergeRequests = summary.MergedMergeRequests[:min(len(summary.MergedMergeRequests), maxItems)]
+			summary.ClosedIssues = summary.ClosedIssues[:min(len(summary.ClosedIssues), maxItems)]
+			summary.FailedPipelines = summary.FailedPipelines[:min(len(summary.FailedPipelines), maxItems)]
+			summary.Headline = activitySummaryLine(summary.Totals)
+			if !summary.Complete {
+				summary.Notes = append(summary.Notes, fmt.Sprintf("Some sections exceeded %d items; totals are lower bounds.", maxCollectedItems))
+			}
+			return JSONResult(summary)
+		}),
+	)
+}
+
+// activitySummaryLine renders the totals as one line, e.g. "3 commits, 1
+// merged MRs, 0 closed issues, 2 failed pipelines".
+func activitySummaryLine(totals ActivityTotals) string {
+	parts := []string{
+		fmt.Sprintf("%d commits", totals.Commits),
+		fmt.Sprintf("%d merged MRs", totals.MergedMergeRequests),
+		fmt.Sprintf("%d closed issues", totals.ClosedIssues),
+		fmt.Sprintf("%d failed pipelines", totals.FailedPipelines),
+	}
+	return strings.Join(parts, ", ")
+}
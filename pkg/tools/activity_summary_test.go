@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGenerateActivitySummary(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/users", http.StatusOK, []map[string]interface{}{
+		{"id": 7, "username": "jdoe", "name": "Jane Doe"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/repository/commits", http.StatusOK, []map[string]interface{}{
+		{"short_id": "a1b2c3d", "title": "Fix login", "author_name": "Jane Doe", "authored_date": "2026-10-15T10:00:00Z"},
+		{"short_id": "e4f5a6b", "title": "Add audit log", "author_name": "Jane Doe", "authored_date": "2026-10-15T15:00:00Z"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/merge_requests", http.StatusOK, []map[string]interface{}{
+		{"iid": 12, "title": "Audit log", "author": map[string]interface{}{"username": "jdoe"}, "merged_at": "2026-10-15T16:00:00Z"},
+		{"iid": 9, "title": "Merged last week", "merged_at": "2026-10-08T16:00:00Z"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/issues", http.StatusOK, []map[string]interface{}{
+		{"iid": 30, "title": "Login broken", "closed_at": "2026-10-15T11:00:00Z", "closed_by": map[string]interface{}{"username": "jdoe"}},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/pipelines", http.StatusOK, []map[string]interface{}{
+		{"id": 501, "ref": "main", "status": "failed", "updated_at": "2026-10-15T12:00:00Z"},
+	})
+
+	result := callTool(t, tc, "generate_activity_summary", map[string]interface{}{
+		"project_id": "acme/api",
+		"username":   "jdoe",
+		"since":      "2026-10-15",
+		"until":      "2026-10-15",
+	})
+	if result.IsError {
+		t.Fatalf("generate_activity_summary failed: %s", resultText(t, result))
+	}
+	var got ActivitySummary
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	want := ActivityTotals{Commits: 2, MergedMergeRequests: 1, ClosedIssues: 1, FailedPipelines: 1}
+	if got.Totals != want {
+		t.Errorf("totals = %+v, want %+v", got.Totals, want)
+	}
+	if len(got.Commits) != 2 || got.Commits[0].ShortID != "e4f5a6b" {
+		t.Errorf("commits = %+v, want newest first", got.Commits)
+	}
+	if len(got.MergedMergeRequests) != 1 || got.MergedMergeRequests[0].IID != 12 {
+		t.Errorf("merged merge requests = %+v", got.MergedMergeRequests)
+	}
+	if got.Headline != "2 commits, 1 merged MRs, 1 closed issues, 1 failed pipelines" {
+		t.Errorf("headline = %q", got.Headline)
+	}
+
+	var query map[string]string
+	for _, req := range client.Requests() {
+		if strings.Contains(req.Endpoint, "/repository/commits?") {
+			query = map[string]string{}
+			values, _ := url.ParseQuery(req.Endpoint[strings.Index(req.Endpoint, "?")+1:])
+			for key := range values {
+				query[key] = values.Get(key)
+			}
+		}
+	}
+	if query["author"] != "Jane Doe" || query["since"] != "2026-10-15T00:00:00Z" || query["until"] != "2026-10-15T23:59:59Z" {
+		t.Errorf("commits query = %v", query)
+	}
+
+	result = callTool(t, tc, "generate_activity_summary", map[string]interface{}{})
+	if !result.IsError {
+		t.Error("expected an error without project_id or username")
+	}
+}
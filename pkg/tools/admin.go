@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// GitLabVersion is the response of the get_gitlab_version tool.
+type GitLabVersion struct {
+	Version    string `json:"version"`
+	Revision   string `json:"revision"`
+	Enterprise bool   `json:"enterprise"`
+	KAS        *struct {
+		Enabled     bool   `json:"enabled"`
+		ExternalURL string `json:"externalUrl,omitempty"`
+		Version     string `json:"version,omitempty"`
+	} `json:"kas,omitempty"`
+}
+
+// BroadcastMessage is an announcement shown to the users of an instance.
+type BroadcastMessage struct {
+	ID                 int        `json:"id"`
+	Message            string     `json:"message"`
+	StartsAt           *time.Time `json:"starts_at"`
+	EndsAt             *time.Time `json:"ends_at"`
+	Active             bool       `json:"active"`
+	BroadcastType      string     `json:"broadcast_type"`
+	Theme              string     `json:"theme,omitempty"`
+	TargetPath         string     `json:"target_path,omitempty"`
+	TargetAccessLevels []int      `json:"target_access_levels,omitempty"`
+	Dismissable        bool       `json:"dismissable"`
+}
+
+// InstanceSettings is the response of the get_instance_settings tool.
+// String settings whose name suggests a secret are masked; Masked lists them.
+type InstanceSettings struct {
+	Settings map[string]interface{} `json:"settings"`
+	Masked   []string               `json:"masked,omitempty"`
+}
+
+type getInstanceSettingsArgs struct {
+	Keys []string `json:"keys"`
+}
+
+// registerGetInstanceSettings registers the get_instance_settings tool.
+func registerGetInstanceSettings(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_instance_settings",
+			Description: "Get the application settings of the GitLab instance, such as sign-up restrictions, default visibility, size limits and CI/CD defaults. Requires an administrator token. Settings holding secrets (tokens, keys, passwords) are masked. Pass keys to return only those settings.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"keys": {
+						Type:        "array",
+						Description: "Names of the settings to return, e.g. [\"signup_enabled\", \"default_project_visibility\"]. Default: all",
+						Items:       &mcp.Property{Type: "string"},
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_instance_settings", func(ctx context.Context, c *ToolContext, args getInstanceSettingsArgs) (*mcp.CallToolResult, error) {
+			var settings map[string]interface{}
+			if err := c.Client.Get(ctx, "/application/settings", &settings); err != nil {
+				return APIErrorResult("Failed to get instance settings", err)
+			}
+			if len(args.Keys) > 0 {
+				selected := make(map[string]interface{}, len(args.Keys))
+				for _, key := range args.Keys {
+					if value, ok := settings[key]; ok {
+						selected[key] = value
+					}
+				}
+				settings = selected
+			}
+
+			result := InstanceSettings{Settings: settings}
+			for key, value := range settings {
+				if text, ok := value.(string); ok && text != "" && auditSecretArgPattern.MatchString(key) {
+					settings[key] = auditMaskedValue
+					result.Masked = append(result.Masked, key)
+				}
+			}
+			sort.Strings(result.Masked)
+			return JSONResult(result)
+		}),
+	)
+}
+
+// initAdminTools registers the instance administration tools.
+func initAdminTools(server *mcp.Server) {
+	registerGetGitlabVersion(server)
+	registerGetInstanceSettings(server)
+	registerListBroadcastMessages(server)
+	registerGetBroadcastMessage(server)
+	registerCreateBroadcastMessage(server)
+	registerUpdateBroadcastMessage(server)
+	registerDeleteBroadcastMessage(server)
+}
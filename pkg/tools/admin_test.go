@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAdminTools(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/application/settings", 200, `{"signup_enabled": false, "default_project_visibility": "internal",
+		"akismet_api_key": "abc123", "recaptcha_private_key": "", "max_personal_access_token_lifetime": 90}`)
+	client.Handle("POST", "/broadcast_messages", 201, `{"id": 3, "message": "Maintenance", "broadcast_type": "banner", "active": false, "target_access_levels": [30, 40]}`)
+
+	result := callTool(t, tc, "get_instance_settings", map[string]interface{}{})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	var settings InstanceSettings
+	if err := json.Unmarshal([]byte(resultText(t, result)), &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Settings["akismet_api_key"] != auditMaskedValue || !reflect.DeepEqual(settings.Masked, []string{"akismet_api_key"}) {
+		t.Errorf("settings = %v, masked = %v", settings.Settings, settings.Masked)
+	}
+	if settings.Settings["max_personal_access_token_lifetime"] != float64(90) || settings.Settings["signup_enabled"] != false {
+		t.Errorf("settings = %v", settings.Settings)
+	}
+
+	result = callTool(t, tc, "get_instance_settings", map[string]interface{}{"keys": []interface{}{"signup_enabled", "nope"}})
+	settings = InstanceSettings{}
+	if err := json.Unmarshal([]byte(resultText(t, result)), &settings); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(settings.Settings, map[string]interface{}{"signup_enabled": false}) {
+		t.Errorf("selected settings = %v", settings.Settings)
+	}
+
+	result = callTool(t, tc, "create_broadcast_message", map[string]interface{}{
+		"message": "Maintenance", "ends_at": "2024-06-01T23:00:00Z", "target_access_levels": []interface{}{"30", "40"}, "dismissable": false,
+	})
+	if result.IsError {
+		t.Fatalf("create_broadcast_message failed: %s", resultText(t, result))
+	}
+	requests := client.Requests()
+	var body map[string]interface{}
+	if err := json.Unmarshal(requests[len(requests)-1].Body, &body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	want := map[string]interface{}{"message": "Maintenance", "ends_at": "2024-06-01T23:00:00Z", "target_access_levels": []interface{}{"30", "40"}, "dismissable": false}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("request body = %v, want %v", body, want)
+	}
+}
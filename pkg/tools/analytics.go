@@ -0,0 +1,494 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+const (
+	// defaultAnalyticsDays is the period analytics cover when no start date
+	// is given.
+	defaultAnalyticsDays = 30
+	// defaultValueStreamMergeRequests is how many merged merge requests the
+	// value stream summary reads by default.
+	defaultValueStreamMergeRequests = 100
+	// maxValueStreamDeployments caps the deployments read per project to
+	// find when merge requests were deployed.
+	maxValueStreamDeployments = 100
+)
+
+// doraMetrics are the metrics of the DORA API, in the order they are
+// reported.
+var doraMetrics = []string{"deployment_frequency", "lead_time_for_changes", "time_to_restore_service", "change_failure_rate"}
+
+// analyticsScope returns the endpoint prefix of a project or group, and
+// describes it, e.g. "project acme/api".
+func analyticsScope(projectID, groupID string) (string, string, error) {
+	switch {
+	case projectID != "" && groupID != "":
+		return "", "", fmt.Errorf("pass either project_id or group_id, not both")
+	case projectID != "":
+		return "/projects/" + url.PathEscape(projectID), "project " + projectID, nil
+	case groupID != "":
+		return "/groups/" + url.PathEscape(groupID), "group " + groupID, nil
+	}
+	return "", "", fmt.Errorf("project_id or group_id is required")
+}
+
+// analyticsPeriod parses the since and until arguments of an analytics
+// tool. The period ends now and lasts defaultAnalyticsDays by default.
+func analyticsPeriod(sinceArg, untilArg string) (time.Time, time.Time, error) {
+	until := time.Now().UTC()
+	if untilArg != "" {
+		var err error
+		if until, err = parseActivityTime(untilArg, true); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	since := until.AddDate(0, 0, -defaultAnalyticsDays)
+	if sinceArg != "" {
+		var err error
+		if since, err = parseActivityTime(sinceArg, false); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	return since, until, nil
+}
+
+// mergedMergeRequests returns up to limit merge requests of a project or
+// group (see analyticsScope) merged within [since, until], most recently
+// merged first, and whether there are no more.
+func mergedMergeRequests(ctx context.Context, client gitlab.API, prefix string, since, until time.Time, limit int) ([]gitlab.MergeRequest, bool, error) {
+	params := url.Values{}
+	params.Set("state", "merged")
+	params.Set("scope", "all")
+	params.Set("merged_after", since.Format(time.RFC3339))
+	params.Set("merged_before", until.Format(time.RFC3339))
+	params.Set("order_by", "merged_at")
+	params.Set("sort", "desc")
+	all, complete, err := collectPages[gitlab.MergeRequest](ctx, client, prefix+"/merge_requests?"+params.Encode(), limit)
+	if err != nil {
+		return nil, false, err
+	}
+	// Instances older than GitLab 15.11 ignore merged_after and merged_before
+	mrs := all[:0]
+	for _, mr := range all {
+		if mr.CreatedAt != nil && inRange(mr.MergedAt, since, until) {
+			mrs = append(mrs, mr)
+		}
+	}
+	return mrs, complete, nil
+}
+
+// formatSeconds renders a duration in the largest fitting unit, e.g.
+// "45 minutes", "5.5 hours" or "3.2 days".
+func formatSeconds(seconds float64) string {
+	switch {
+	case seconds < 3600:
+		return fmt.Sprintf("%.0f minutes", seconds/60)
+	case seconds < 48*3600:
+		return fmt.Sprintf("%.1f hours", seconds/3600)
+	}
+	return fmt.Sprintf("%.1f days", seconds/86400)
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating
+// between the two nearest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// DORAValue is the value of a DORA metric for one interval. Date is empty
+// for the whole period; Value is nil when there was no data.
+type DORAValue struct {
+	Date  string   `json:"date,omitempty"`
+	Value *float64 `json:"value"`
+}
+
+// DORAMetric is one metric of the get_dora_metrics tool. Deployment
+// frequency counts deployments, lead time and time to restore are medians in
+// seconds, and change failure rate is a fraction of deployments.
+type DORAMetric struct {
+	Metric  string      `json:"metric"`
+	Summary string      `json:"summary"`
+	Values  []DORAValue `json:"values"`
+}
+
+// DORAMetrics is the response of the get_dora_metrics tool.
+type DORAMetrics struct {
+	Scope    string       `json:"scope"`
+	Interval string       `json:"interval"`
+	Metrics  []DORAMetric `json:"metrics"`
+}
+
+// doraSummary describes the values of a metric in one line.
+func doraSummary(metric string, values []DORAValue) string {
+	var known []float64
+	for _, v := range values {
+		if v.Value != nil {
+			known = append(known, *v.Value)
+		}
+	}
+	if len(known) == 0 {
+		return "no data"
+	}
+	format := func(value float64) string {
+		switch metric {
+		case "deployment_frequency":
+			return fmt.Sprintf("%g deployments", value)
+		case "change_failure_rate":
+			return fmt.Sprintf("%.1f%% of deployments", value*100)
+		}
+		return formatSeconds(value)
+	}
+	if len(values) == 1 {
+		return format(known[0])
+	}
+	if metric == "deployment_frequency" {
+		var total float64
+		for _, value := range known {
+			total += value
+		}
+		return fmt.Sprintf("%s over %d intervals", format(total), len(values))
+	}
+	low, high := known[0], known[0]
+	for _, value := range known {
+		low, high = min(low, value), max(high, value)
+	}
+	return fmt.Sprintf("from %s to %s across %d intervals with data", format(low), format(high), len(known))
+}
+
+type getDORAMetricsArgs struct {
+	ProjectID        string   `json:"project_id"`
+	GroupID          string   `json:"group_id"`
+	Metrics          []string `json:"metrics"`
+	StartDate        string   `json:"start_date"`
+	EndDate          string   `json:"end_date"`
+	Interval         string   `json:"interval" validate:"oneof=all monthly daily"`
+	EnvironmentTiers []string `json:"environment_tiers"`
+}
+
+// registerGetDORAMetrics registers the get_dora_metrics tool.
+func registerGetDORAMetrics(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_dora_metrics",
+			Description: "Get the DORA metrics of a project or group from GitLab's DORA API: deployment frequency (successful deployments), lead time for changes (median seconds from merge to deployment), time to restore service (median seconds an incident stayed open) and change failure rate (fraction of deployments that caused an incident). Each metric comes with a readable summary. Needs GitLab Ultimate and at least the Reporter role.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project). Either this or group_id is required",
+					},
+					"group_id": {
+						Type:        "string",
+						Description: "The group ID or URL-encoded path, to get the metrics of all its projects",
+					},
+					"metrics": {
+						Type:        "array",
+						Description: "Metrics to get: deployment_frequency, lead_time_for_changes, time_to_restore_service or change_failure_rate. Default: all four",
+						Items:       &mcp.Property{Type: "string", Enum: doraMetrics},
+					},
+					"start_date": {
+						Type:        "string",
+						Description: "First day of the period (YYYY-MM-DD). Default: 3 months before end_date",
+					},
+					"end_date": {
+						Type:        "string",
+						Description: "Last day of the period (YYYY-MM-DD). Default: today",
+					},
+					"interval": {
+						Type:        "string",
+						Description: "all for one value over the period, or a monthly or daily series. Default: all",
+						Enum:        []string{"all", "monthly", "daily"},
+					},
+					"environment_tiers": {
+						Type:        "array",
+						Description: "Environment tiers counted as deployments, e.g. [\"production\", \"staging\"]. Default: production",
+						Items:       &mcp.Property{Type: "string"},
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_dora_metrics", func(ctx context.Context, c *ToolContext, args getDORAMetricsArgs) (*mcp.CallToolResult, error) {
+			prefix, scope, err := analyticsScope(args.ProjectID, args.GroupID)
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			metrics := args.Metrics
+			if len(metrics) == 0 {
+				metrics = doraMetrics
+			}
+			for _, metric := range metrics {
+				if !containsString(doraMetrics, metric) {
+					return ErrorResult(fmt.Sprintf("unknown metric %q; use one of %s", metric, strings.Join(doraMetrics, ", ")))
+				}
+			}
+			interval := args.Interval
+			if interval == "" {
+				interval = "all"
+			}
+
+			result := DORAMetrics{Scope: scope, Interval: interval, Metrics: []DORAMetric{}}
+			for _, metric := range metrics {
+				params := url.Values{}
+				params.Set("metric", metric)
+				params.Set("interval", interval)
+				if args.StartDate != "" {
+					params.Set("start_date", args.StartDate)
+				}
+				if args.EndDate != "" {
+					params.Set("end_date", args.EndDate)
+				}
+				for _, tier := range args.EnvironmentTiers {
+					params.Add("environment_tiers[]", tier)
+				}
+				var values []DORAValue
+				if err := c.Client.Get(ctx, prefix+"/dora/metrics?"+params.Encode(), &values); err != nil {
+					return APIErrorResult(fmt.Sprintf("Failed to get %s", metric), err)
+				}
+				if values == nil {
+					values = []DORAValue{}
+				}
+				result.Metrics = append(result.Metrics, DORAMetric{Metric: metric, Summary: doraSummary(metric, values), Values: values})
+			}
+			return JSONResult(result)
+		}),
+	)
+}
+
+// ValueStreamStage is one stage of the get_value_stream_summary tool, over
+// the merge requests that went through it. Durations are in seconds.
+type ValueStreamStage struct {
+	Name           string  `json:"name"`
+	Start          string  `json:"start"`
+	End            string  `json:"end"`
+	Count          int     `json:"count"`
+	MedianSeconds  float64 `json:"median_seconds,omitempty"`
+	AverageSeconds float64 `json:"average_seconds,omitempty"`
+	Summary        string  `json:"summary"`
+}
+
+// ValueStreamSummary is the response of the get_value_stream_summary tool.
+type ValueStreamSummary struct {
+	Scope         string    `json:"scope"`
+	Since         time.Time `json:"since"`
+	Until         time.Time `json:"until"`
+	Environment   string    `json:"environment"`
+	MergeRequests int       `json:"merge_requests"`
+	Deployed      int       `json:"deployed"`
+	// Complete is false when the period had more merged merge requests
+	// than max_merge_requests
+	Complete bool               `json:"complete"`
+	Stages   []ValueStreamStage `json:"stages"`
+	Notes    []string           `json:"notes,omitempty"`
+}
+
+// newValueStreamStage summarizes the durations of a stage.
+func newValueStreamStage(name, start, end string, durations []float64) ValueStreamStage {
+	stage := ValueStreamStage{Name: name, Start: start, End: end, Count: len(durations), Summary: "no merge requests"}
+	if len(durations) == 0 {
+		return stage
+	}
+	sort.Float64s(durations)
+	var total float64
+	for _, d := range durations {
+		total += d
+	}
+	stage.MedianSeconds = percentile(durations, 50)
+	stage.AverageSeconds = total / float64(len(durations))
+	stage.Summary = fmt.Sprintf("median %s, average %s over %d merge requests", formatSeconds(stage.MedianSeconds), formatSeconds(stage.AverageSeconds), stage.Count)
+	return stage
+}
+
+// valueStreamDeployment is the part of a GitLab deployment the value stream
+// summary reads.
+type valueStreamDeployment struct {
+	ID         int        `json:"id"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// firstDeployments returns when each merge request of a project, by ID, was
+// first deployed to an environment after since. It reads at most
+// maxValueStreamDeployments deployments and reports whether it read them all.
+func firstDeployments(ctx context.Context, client gitlab.API, projectID int, environment string, since time.Time) (map[int]time.Time, bool, error) {
+	params := url.Values{}
+	params.Set("environment", environment)
+	params.Set("status", "success")
+	params.Set("finished_after", since.Format(time.RFC3339))
+	params.Set("order_by", "finished_at")
+	params.Set("sort", "asc")
+	deployments, complete, err := collectPages[valueStreamDeployment](ctx, client, fmt.Sprintf("/projects/%d/deployments?%s", projectID, params.Encode()), maxValueStreamDeployments)
+	if err != nil {
+		return nil, false, err
+	}
+	deployed := map[int]time.Time{}
+	for _, deployment := range deployments {
+		if deployment.FinishedAt == nil {
+			continue
+		}
+		mrs, _, err := collectPages[gitlab.MergeRequest](ctx, client, fmt.Sprintf("/projects/%d/deployments/%d/merge_requests", projectID, deployment.ID), maxCollectedItems)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, mr := range mrs {
+			if _, seen := deployed[mr.ID]; !seen {
+				deployed[mr.ID] = *deployment.FinishedAt
+			}
+		}
+	}
+	return deployed, complete, nil
+}
+
+type getValueStreamSummaryArgs struct {
+	ProjectID        string `json:"project_id"`
+	GroupID          string `json:"group_id"`
+	Since            string `json:"since"`
+	Until            string `json:"until"`
+	Environment      string `json:"environment"`
+	MaxMergeRequests int    `json:"max_merge_requests" validate:"min=1,max=500"`
+}
+
+// registerGetValueStreamSummary registers the get_value_stream_summary tool.
+func registerGetValueStreamSummary(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_value_stream_summary",
+			Description: "Summarize the value stream of a project or group over the merge requests merged in a period, stage by stage: code (first commit to merge request opened), review (opened to merged) and staging (merged to first successful deployment to the environment), each with its median and average duration. Computed from merge requests, commits and deployments, so it works on every tier; GitLab's own value stream analytics can differ in how it picks the start and end of a stage.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project). Either this or group_id is required",
+					},
+					"group_id": {
+						Type:        "string",
+						Description: "The group ID or URL-encoded path, to cover the merge requests of all its projects",
+					},
+					"since": {
+						Type:        "string",
+						Description: "Start of the period, as ISO 8601 or YYYY-MM-DD. Default: 30 days before until",
+					},
+					"until": {
+						Type:        "string",
+						Description: "End of the period, as ISO 8601 or YYYY-MM-DD (the whole day). Default: now",
+					},
+					"environment": {
+						Type:        "string",
+						Description: "The environment whose deployments end the staging stage. Default: production",
+					},
+					"max_merge_requests": {
+						Type:        "integer",
+						Description: "Merged merge requests read, most recently merged first (max 500). Default: 100",
+						Default:     defaultValueStreamMergeRequests,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(500),
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_value_stream_summary", func(ctx context.Context, c *ToolContext, args getValueStreamSummaryArgs) (*mcp.CallToolResult, error) {
+			prefix, scope, err := analyticsScope(args.ProjectID, args.GroupID)
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			since, until, err := analyticsPeriod(args.Since, args.Until)
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			environment := args.Environment
+			if environment == "" {
+				environment = "production"
+			}
+			limit := args.MaxMergeRequests
+			if limit == 0 {
+				limit = defaultValueStreamMergeRequests
+			}
+
+			mrs, complete, err := mergedMergeRequests(ctx, c.Client, prefix, since, until, limit)
+			if err != nil {
+				return APIErrorResult("Failed to list merged merge requests", err)
+			}
+
+			result := ValueStreamSummary{Scope: scope, Since: since, Until: until, Environment: environment, Complete: complete}
+			var code, review, staging []float64
+			deployments := map[int]map[int]time.Time{}
+			for i, mr := range mrs {
+				result.MergeRequests++
+				mcp.ReportProgress(ctx, float64(i+1), float64(len(mrs)), fmt.Sprintf("Read %d of %d merge requests", i+1, len(mrs)))
+				review = append(review, mr.MergedAt.Sub(*mr.CreatedAt).Seconds())
+
+				commits, _, err := collectPages[gitlab.Commit](ctx, c.Client, fmt.Sprintf("/projects/%d/merge_requests/%d/commits", mr.ProjectID, mr.IID), maxCollectedItems)
+				if err != nil {
+					return APIErrorResult(fmt.Sprintf("Failed to list the commits of merge request %d!%d", mr.ProjectID, mr.IID), err)
+				}
+				var first *time.Time
+				for _, commit := range commits {
+					if commit.AuthoredDate != nil && (first == nil || commit.AuthoredDate.Before(*first)) {
+						first = commit.AuthoredDate
+					}
+				}
+				if first != nil && first.Before(*mr.CreatedAt) {
+					code = append(code, mr.CreatedAt.Sub(*first).Seconds())
+				}
+
+				deployed, ok := deployments[mr.ProjectID]
+				if !ok {
+					var all bool
+					deployed, all, err = firstDeployments(ctx, c.Client, mr.ProjectID, environment, since)
+					if err != nil {
+						return APIErrorResult(fmt.Sprintf("Failed to list the deployments of project %d", mr.ProjectID), err)
+					}
+					if !all {
+						result.Notes = append(result.Notes, fmt.Sprintf("project %d has more than %d deployments to %s in the period; only the first were read", mr.ProjectID, maxValueStreamDeployments, environment))
+					}
+					deployments[mr.ProjectID] = deployed
+				}
+				if at, ok := deployed[mr.ID]; ok && !at.Before(*mr.MergedAt) {
+					result.Deployed++
+					staging = append(staging, at.Sub(*mr.MergedAt).Seconds())
+				}
+			}
+
+			result.Stages = []ValueStreamStage{
+				newValueStreamStage("code", "first commit", "merge request opened", code),
+				newValueStreamStage("review", "merge request opened", "merged", review),
+				newValueStreamStage("staging", "merged", "deployed to "+environment, staging),
+			}
+			if result.MergeRequests > 0 && result.Deployed == 0 {
+				result.Notes = append(result.Notes, fmt.Sprintf("no merge request was deployed to %s; check the environment name", environment))
+			}
+			return JSONResult(result)
+		}),
+	)
+}
+
+// initAnalyticsTools registers the delivery analytics tools.
+func initAnalyticsTools(server *mcp.Server) {
+	registerGetDORAMetrics(server)
+	registerGetValueStreamSummary(server)
+	registerMRCycleTimeReport(server)
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetDORAMetrics(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/groups/acme/dora/metrics", 200, `[{"date": "2024-04-01", "value": 86400}, {"date": "2024-05-01", "value": null}, {"date": "2024-06-01", "value": 7200}]`)
+
+	res := callTool(t, tc, "get_dora_metrics", map[string]interface{}{
+		"group_id": "acme", "metrics": []interface{}{"lead_time_for_changes"}, "interval": "monthly", "environment_tiers": []interface{}{"production", "staging"},
+	})
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, res))
+	}
+	var metrics DORAMetrics
+	if err := json.Unmarshal([]byte(resultText(t, res)), &metrics); err != nil {
+		t.Fatal(err)
+	}
+	if metrics.Scope != "group acme" || len(metrics.Metrics) != 1 || len(metrics.Metrics[0].Values) != 3 {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+	if want := "from 2.0 hours to 24.0 hours across 2 intervals with data"; metrics.Metrics[0].Summary != want {
+		t.Errorf("summary = %q, want %q", metrics.Metrics[0].Summary, want)
+	}
+	query, _ := url.ParseQuery(strings.SplitN(client.Requests()[0].Endpoint, "?", 2)[1])
+	if query.Get("metric") != "lead_time_for_changes" || query.Get("interval") != "monthly" || !reflect.DeepEqual(query["environment_tiers[]"], []string{"production", "staging"}) {
+		t.Errorf("query = %v", query)
+	}
+
+	for message, args := range map[string]map[string]interface{}{
+		"project_id or group_id is required": {},
+		"pass either project_id or group_id": {"project_id": "acme/api", "group_id": "acme"},
+		`unknown metric "mttr"`:              {"project_id": "acme/api", "metrics": []interface{}{"mttr"}},
+	} {
+		res := callTool(t, tc, "get_dora_metrics", args)
+		if text := resultText(t, res); !res.IsError || !strings.Contains(text, message) {
+			t.Errorf("error = %s, want %q", text, message)
+		}
+	}
+}
+
+func TestGetValueStreamSummary(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/acme%2Fapi/merge_requests", 200, `[
+		{"id": 101, "iid": 2, "project_id": 42, "created_at": "2024-05-03T00:00:00Z", "merged_at": "2024-05-03T06:00:00Z"},
+		{"id": 100, "iid": 1, "project_id": 42, "created_at": "2024-05-01T10:00:00Z", "merged_at": "2024-05-02T10:00:00Z"}
+	]`)
+	client.Handle("GET", "/projects/42/merge_requests/1/commits", 200, `[{"id": "b", "authored_date": "2024-05-01T09:00:00Z"}, {"id": "a", "authored_date": "2024-05-01T08:00:00Z"}]`)
+	client.Handle("GET", "/projects/42/merge_requests/2/commits", 200, `[{"id": "c", "authored_date": "2024-05-02T06:00:00Z"}]`)
+	client.Handle("GET", "/projects/42/deployments", 200, `[{"id": 7, "finished_at": "2024-05-02T12:00:00Z"}]`)
+	client.Handle("GET", "/projects/42/deployments/7/merge_requests", 200, `[{"id": 100, "iid": 1}]`)
+
+	res := callTool(t, tc, "get_value_stream_summary", map[string]interface{}{"project_id": "acme/api", "since": "2024-05-01", "until": "2024-05-31"})
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, res))
+	}
+	var summary ValueStreamSummary
+	if err := json.Unmarshal([]byte(resultText(t, res)), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.MergeRequests != 2 || summary.Deployed != 1 || !summary.Complete || len(summary.Stages) != 3 {
+		t.Fatalf("summary = %+v", summary)
+	}
+	for i, want := range []struct {
+		name   string
+		count  int
+		median float64
+	}{{"code", 2, 10 * 3600}, {"review", 2, 15 * 3600}, {"staging", 1, 2 * 3600}} {
+		stage := summary.Stages[i]
+		if stage.Name != want.name || stage.Count != want.count || stage.MedianSeconds != want.median {
+			t.Errorf("stage %d = %+v, want %s with %d merge requests and median %v", i, stage, want.name, want.count, want.median)
+		}
+	}
+	if want := "median 15.0 hours, average 15.0 hours over 2 merge requests"; summary.Stages[1].Summary != want {
+		t.Errorf("review summary = %q, want %q", summary.Stages[1].Summary, want)
+	}
+	query, _ := url.ParseQuery(strings.SplitN(client.Requests()[0].Endpoint, "?", 2)[1])
+	if query.Get("state") != "merged" || query.Get("merged_after") != "2024-05-01T00:00:00Z" || query.Get("merged_before") != "2024-05-31T23:59:59Z" {
+		t.Errorf("query = %v", query)
+	}
+}
@@ -0,0 +1,313 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// DecodeArgs decodes tool arguments into a struct of type T and validates it.
+// Arguments are matched to fields by their json tag; arguments without a field
+// are ignored. Numbers are accepted for string fields (a project ID sent as
+// 42) and numeric strings for number fields. Fields whose zero value must be
+// told apart from an omitted argument are pointers.
+//
+// The validate tag of a field holds comma-separated rules:
+//
+//	required     the argument is present and not empty
+//	min=N, max=N the number is at least / at most N
+//	oneof=a b c  the string is one of the listed values
+//
+// All problems are reported in one error, e.g. "project_id is required;
+// per_page must be at most 100".
+func DecodeArgs[T any](args map[string]interface{}) (T, error) {
+	var decoded T
+	value := reflect.ValueOf(&decoded).Elem()
+	if value.Kind() != reflect.Struct {
+		return decoded, fmt.Errorf("DecodeArgs: %T is not a struct", decoded)
+	}
+
+	var problems []string
+	for _, field := range argFields(value.Type()) {
+		raw, present := args[field.name]
+		if present && raw != nil {
+			if err := setArg(value.FieldByIndex(field.index), raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s %v", field.name, err))
+				continue
+			}
+		}
+		problems = append(problems, field.validate(value.FieldByIndex(field.index))...)
+	}
+	if len(problems) > 0 {
+		return decoded, errors.New(strings.Join(problems, "; "))
+	}
+	return decoded, nil
+}
+
+// argField is a struct field filled from a tool argument.
+type argField struct {
+	index    []int
+	name     string
+	required bool
+	min, max *float64
+	oneOf    []string
+}
+
+// argFields returns the fields of an argument struct type, including those of
+// embedded structs such as PageArgs.
+func argFields(t reflect.Type) []argField {
+	var fields []argField
+	for _, sf := range reflect.VisibleFields(t) {
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && name == "" {
+			continue
+		}
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		field := argField{index: sf.Index, name: name}
+		for _, rule := range strings.Split(sf.Tag.Get("validate"), ",") {
+			key, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+			switch key {
+			case "required":
+				field.required = true
+			case "min", "max":
+				n, err := strconv.ParseFloat(param, 64)
+				if err != nil {
+					panic(fmt.Sprintf("tools: invalid %s rule on %s.%s", key, t.Name(), sf.Name))
+				}
+				if key == "min" {
+					field.min = &n
+				} else {
+					field.max = &n
+				}
+			case "oneof":
+				field.oneOf = strings.Fields(param)
+			case "":
+			default:
+				panic(fmt.Sprintf("tools: unknown validate rule %q on %s.%s", key, t.Name(), sf.Name))
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// validate checks a decoded field against its rules. A zero value counts as
+// omitted unless the field is a pointer, so required fields that accept 0 or
+// false are pointers.
+func (f argField) validate(v reflect.Value) []string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if f.required {
+				return []string{f.name + " is required"}
+			}
+			return nil
+		}
+		v = v.Elem()
+	} else if v.IsZero() {
+		if f.required {
+			return []string{f.name + " is required"}
+		}
+		return nil
+	}
+	if f.required && (v.Kind() == reflect.String || v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+		return []string{f.name + " is required"}
+	}
+
+	var problems []string
+	if isNumberKind(v.Kind()) {
+		n := numberValue(v)
+		if f.min != nil && n < *f.min {
+			problems = append(problems, fmt.Sprintf("%s must be at least %s", f.name, formatNumber(*f.min)))
+		}
+		if f.max != nil && n > *f.max {
+			problems = append(problems, fmt.Sprintf("%s must be at most %s", f.name, formatNumber(*f.max)))
+		}
+	}
+	if len(f.oneOf) > 0 && v.Kind() == reflect.String {
+		found := false
+		for _, allowed := range f.oneOf {
+			found = found || v.String() == allowed
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s must be one of: %s", f.name, strings.Join(f.oneOf, ", ")))
+		}
+	}
+	return problems
+}
+
+// setArg stores a decoded JSON value in a field, converting between the
+// representations clients commonly use.
+func setArg(v reflect.Value, raw interface{}) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setArg(elem.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch {
+	case v.Kind() == reflect.String:
+		switch r := raw.(type) {
+		case string:
+			v.SetString(r)
+		case float64:
+			v.SetString(formatNumber(r))
+		case int:
+			v.SetString(strconv.Itoa(r))
+		default:
+			return errors.New("must be a string")
+		}
+	case v.Kind() == reflect.Bool:
+		switch r := raw.(type) {
+		case bool:
+			v.SetBool(r)
+		case string:
+			b, err := strconv.ParseBool(r)
+			if err != nil {
+				return errors.New("must be a boolean")
+			}
+			v.SetBool(b)
+		default:
+			return errors.New("must be a boolean")
+		}
+	case isNumberKind(v.Kind()):
+		n, ok := toFloat(raw)
+		if !ok {
+			return errors.New("must be a number")
+		}
+		if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+			v.SetFloat(n)
+			return nil
+		}
+		if n != math.Trunc(n) {
+			return errors.New("must be an integer")
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Interface:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return errors.New("must be an array")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setArg(slice.Index(i), item); err != nil {
+				return fmt.Errorf("item %d %v", i, err)
+			}
+		}
+		v.Set(slice)
+	default:
+		// Objects and free-form values go through JSON
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return fmt.Errorf("is invalid: %v", err)
+		}
+		target := reflect.New(v.Type())
+		if err := json.Unmarshal(data, target.Interface()); err != nil {
+			return fmt.Errorf("is invalid: %v", err)
+		}
+		v.Set(target.Elem())
+	}
+	return nil
+}
+
+// toFloat converts a JSON number, or a string holding one, to float64.
+func toFloat(raw interface{}) (float64, bool) {
+	switch r := raw.(type) {
+	case float64:
+		return r, true
+	case int:
+		return float64(r), true
+	case int64:
+		return float64(r), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(r), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// isNumberKind reports whether k is an integer or floating point kind.
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// numberValue returns a number field as float64.
+func numberValue(v reflect.Value) float64 {
+	if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+		return v.Float()
+	}
+	return float64(v.Int())
+}
+
+// formatNumber formats n without a fraction when it is whole.
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// PageArgs are the pagination arguments of list tools, embedded in their
+// argument structs.
+type PageArgs struct {
+	Page    int `json:"page" validate:"min=1"`
+	PerPage int `json:"per_page" validate:"min=1,max=100"`
+}
+
+// setParams adds the page and per_page query parameters that were given.
+func (p PageArgs) setParams(params url.Values) {
+	if p.Page > 0 {
+		params.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.PerPage > 0 {
+		params.Set("per_page", strconv.Itoa(p.PerPage))
+	}
+}
+
+var (
+	argTypesMu sync.Mutex
+	// argTypes are the argument structs of the tools registered with
+	// withArgs, by tool name, so tests can check them against the schemas
+	argTypes = map[string]reflect.Type{}
+)
+
+// withArgs returns the handler of a tool taking typed arguments: it gets the
+// tool context, logs the call and decodes the arguments into T, returning an
+// error result when they are invalid.
+func withArgs[T any](name string, handler func(ctx context.Context, c *ToolContext, args T) (*mcp.CallToolResult, error)) mcp.ToolHandler {
+	argTypesMu.Lock()
+	argTypes[name] = reflect.TypeOf((*T)(nil)).Elem()
+	argTypesMu.Unlock()
+
+	return func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		c := FromContext(ctx)
+		if c == nil {
+			return ErrorResult("tool context not initialized")
+		}
+		c.Logger.ToolCall(ctx, name, args)
+
+		decoded, err := DecodeArgs[T](args)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		return handler(ctx, c, decoded)
+	}
+}
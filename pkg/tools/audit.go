@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/redact"
+)
+
+const (
+	// auditMaskedValue replaces arguments whose name suggests a secret.
+	auditMaskedValue = "[MASKED]"
+	// maxAuditValueLength is the length above which string arguments (file
+	// contents, descriptions) are shortened in the audit log.
+	maxAuditValueLength = 256
+)
+
+// auditSecretArgPattern matches argument names whose values are never logged.
+var auditSecretArgPattern = regexp.MustCompile(`(?i)token|password|passwd|secret|credential|private_key|api_key`)
+
+// auditedCallKey is the context key of the auditedCall of a tool call.
+type auditedCallKey struct{}
+
+// auditedCall holds the arguments a tool call was executed with, when a
+// policy rewrote them (see PolicyMiddleware).
+type auditedCall struct {
+	executed map[string]interface{}
+}
+
+// recordExecutedArguments tells the audit log that the call in ctx runs with
+// args instead of the arguments the client sent.
+func recordExecutedArguments(ctx context.Context, args map[string]interface{}) {
+	if call, ok := ctx.Value(auditedCallKey{}).(*auditedCall); ok {
+		call.executed = args
+	}
+}
+
+// AuditMiddleware records every call of a tool that is not read-only (see
+// isReadOnlyTool) in the audit log, once the call has finished. Arguments are
+// masked (see auditArguments) and the GitLab requests made by the call are
+// listed with their status codes. When a policy rewrote the arguments, the
+// executed ones are logged along with those the client sent. Calls made as
+// another user (see SudoMiddleware) name that user.
+func AuditMiddleware(audit *logging.AuditLog) mcp.ToolMiddleware {
+	return func(tool mcp.Tool, handler mcp.ToolHandler) (mcp.Tool, mcp.ToolHandler) {
+		if audit == nil || isReadOnlyTool(tool) {
+			return tool, handler
+		}
+
+		wrapped := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			ctx, recorder := gitlab.WithResponseRecorder(ctx)
+			call := &auditedCall{}
+			ctx = context.WithValue(ctx, auditedCallKey{}, call)
+			result, err := handler(ctx, args)
+
+			executed := args
+			if call.executed != nil {
+				executed = call.executed
+			}
+			entry := logging.AuditEntry{
+				Time:       start.UTC(),
+				RequestID:  logging.RequestIDFromContext(ctx),
+				Tool:       tool.Name,
+				Project:    GetString(executed, "project_id", ""),
+				Group:      GetString(executed, "group_id", ""),
+				Arguments:  auditArguments(ctx, executed),
+				Outcome:    "ok",
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if call.executed != nil {
+				entry.RequestedArguments = auditArguments(ctx, args)
+			}
+			if principal, ok := auth.PrincipalFromContext(ctx); ok {
+				entry.Principal = principal
+			}
+			if _, sudo := tool.InputSchema.Properties["sudo"]; sudo {
+				entry.Sudo = GetString(executed, "sudo", "")
+			}
+			switch {
+			case err != nil:
+				entry.Outcome = "error"
+				entry.Error = err.Error()
+			case result != nil && result.IsError:
+				entry.Outcome = "error"
+				if len(result.Content) > 0 {
+					entry.Error = result.Content[0].Text
+				}
+			}
+			for _, response := range recorder.Responses() {
+				entry.Requests = append(entry.Requests, logging.AuditRequest{
+					Method:   response.Method,
+					Endpoint: response.Endpoint,
+					Status:   response.StatusCode,
+				})
+				// The first failure explains the outcome; otherwise the
+				// last response is the one that made the change
+				if entry.Status < 400 {
+					entry.Status = response.StatusCode
+				}
+			}
+
+			if writeErr := audit.Write(entry); writeErr != nil {
+				if c := FromContext(ctx); c != nil && c.Logger != nil {
+					c.Logger.ErrorContext(ctx, "Failed to audit %s: %v", tool.Name, writeErr)
+				}
+			}
+			return result, err
+		}
+		return tool, wrapped
+	}
+}
+
+// auditArguments returns a copy of the tool arguments that is safe to log:
+// values of secret-looking arguments and of CI variables are masked, secrets
+// inside strings are redacted with the job log rules (including custom
+// patterns), and long strings are shortened.
+func auditArguments(ctx context.Context, args map[string]interface{}) map[string]interface{} {
+	redactor := builtinLogRedactor
+	if c := FromContext(ctx); c != nil && c.Config != nil && c.Config.LogRedactor != nil {
+		redactor = c.Config.LogRedactor
+	}
+	masked, _ := auditValue(redactor, "", args).(map[string]interface{})
+	if masked == nil {
+		masked = map[string]interface{}{}
+	}
+	return masked
+}
+
+// auditValue masks one argument value, recursing into objects and arrays.
+func auditValue(redactor *redact.Redactor, name string, value interface{}) interface{} {
+	if name != "" && auditSecretArgPattern.MatchString(name) {
+		return auditMaskedValue
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		// Variables ({"key": ..., "value": ...}) may hold secrets of any name
+		_, isVariable := v["key"]
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isVariable && key == "value" {
+				out[key] = auditMaskedValue
+				continue
+			}
+			out[key] = auditValue(redactor, key, item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = auditValue(redactor, "", item)
+		}
+		return out
+	case string:
+		text, _ := redactor.Redact(v)
+		if len(text) > maxAuditValueLength {
+			cut := maxAuditValueLength
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+			text = fmt.Sprintf("%s... (%d bytes)", text[:cut], len(v))
+		}
+		return text
+	default:
+		return v
+	}
+}
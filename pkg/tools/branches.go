@@ -2,6 +2,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -35,12 +36,12 @@ func registerCreateBranch(server *mcp.Server) {
 				Required: []string{"project_id", "branch", "ref"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("create_branch", args)
+			c.Logger.ToolCall(ctx, "create_branch", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -65,8 +66,8 @@ func registerCreateBranch(server *mcp.Server) {
 			}
 
 			var result gitlab.Branch
-			if err := c.Client.Post(endpoint, requestBody, &result); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create branch: %v", err))
+			if err := c.Client.Post(ctx, endpoint, requestBody, &result); err != nil {
+				return APIErrorResult("Failed to create branch", err)
 			}
 
 			return JSONResult(result)
@@ -123,12 +124,12 @@ func registerListCommits(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("list_commits", args)
+			c.Logger.ToolCall(ctx, "list_commits", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -169,11 +170,12 @@ func registerListCommits(server *mcp.Server) {
 			}
 
 			var commits []gitlab.Commit
-			if err := c.Client.Get(endpoint, &commits); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list commits: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &commits)
+			if err != nil {
+				return APIErrorResult("Failed to list commits", err)
 			}
 
-			return JSONResult(commits)
+			return PagedJSONResult(commits, pagination)
 		},
 	)
 }
@@ -202,12 +204,12 @@ func registerGetCommit(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_commit", args)
+			c.Logger.ToolCall(ctx, "get_commit", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -225,8 +227,8 @@ func registerGetCommit(server *mcp.Server) {
 			)
 
 			var commit gitlab.Commit
-			if err := c.Client.Get(endpoint, &commit); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get commit: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &commit); err != nil {
+				return APIErrorResult("Failed to get commit", err)
 			}
 
 			return JSONResult(commit)
@@ -234,6 +236,189 @@ func registerGetCommit(server *mcp.Server) {
 	)
 }
 
+// registerGetMergeBase registers the get_merge_base tool.
+func registerGetMergeBase(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_merge_base",
+			Description: "Get the common ancestor (merge base) commit of two or more refs. Use it to find where a branch diverged or whether one ref already contains another.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"refs": {
+						Type:        "array",
+						Description: "Two or more commit SHAs, branch names or tags",
+						Items:       &mcp.Property{Type: "string"},
+					},
+				},
+				Required: []string{"project_id", "refs"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_merge_base", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			refs := GetStringArray(args, "refs")
+			if len(refs) < 2 {
+				return ErrorResult("refs must contain at least two refs")
+			}
+
+			params := url.Values{}
+			for _, ref := range refs {
+				params.Add("refs[]", ref)
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/repository/merge_base?%s",
+				url.PathEscape(projectID),
+				params.Encode(),
+			)
+
+			var commit gitlab.Commit
+			if err := c.Client.Get(ctx, endpoint, &commit); err != nil {
+				return APIErrorResult("failed to get merge base", err)
+			}
+
+			return JSONResult(commit)
+		},
+	)
+}
+
+// CommitRef is a branch or tag that contains a commit.
+type CommitRef struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// CommitContainment answers whether a ref contains a commit.
+type CommitContainment struct {
+	SHA       string `json:"sha"`
+	Ref       string `json:"ref"`
+	Contained bool   `json:"contained"`
+	RefType   string `json:"ref_type,omitempty"`
+	Complete  bool   `json:"complete"`
+}
+
+// registerGetCommitRefs registers the get_commit_refs tool.
+func registerGetCommitRefs(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_commit_refs",
+			Description: "List the branches and tags that contain a commit. Set ref to answer directly whether one branch or tag (e.g., a release branch) contains the commit.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"sha": {
+						Type:        "string",
+						Description: "The commit SHA",
+					},
+					"type": {
+						Type:        "string",
+						Description: "Scope of refs: branch, tag or all (default: all)",
+						Enum:        []string{"branch", "tag", "all"},
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Branch or tag name to check. When set, returns {contained: true|false} instead of the ref list",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id", "sha"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_commit_refs", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			sha := GetString(args, "sha", "")
+			if sha == "" {
+				return ErrorResult("sha is required")
+			}
+
+			params := url.Values{}
+			params.Set("type", GetString(args, "type", "all"))
+
+			endpoint := fmt.Sprintf("/projects/%s/repository/commits/%s/refs",
+				url.PathEscape(projectID),
+				url.PathEscape(sha),
+			)
+
+			if ref := GetString(args, "ref", ""); ref != "" {
+				refs, complete, err := collectPages[CommitRef](ctx, c.Client, endpoint+"?"+params.Encode(), maxCollectedItems)
+				if err != nil {
+					return APIErrorResult("failed to get commit refs", err)
+				}
+				result := CommitContainment{SHA: sha, Ref: ref, Complete: complete}
+				for _, r := range refs {
+					if r.Name == ref {
+						result.Contained = true
+						result.RefType = r.Type
+						result.Complete = true
+						break
+					}
+				}
+				return JSONResult(result)
+			}
+
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", strconv.Itoa(page))
+			}
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", strconv.Itoa(perPage))
+			}
+
+			var refs []CommitRef
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint+"?"+params.Encode(), &refs)
+			if err != nil {
+				return APIErrorResult("failed to get commit refs", err)
+			}
+
+			return PagedJSONResult(refs, pagination)
+		},
+	)
+}
+
 // registerGetCommitDiff registers the get_commit_diff tool.
 func registerGetCommitDiff(server *mcp.Server) {
 	server.RegisterTool(
@@ -271,12 +456,12 @@ func registerGetCommitDiff(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_commit_diff", args)
+			c.Logger.ToolCall(ctx, "get_commit_diff", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -309,11 +494,12 @@ func registerGetCommitDiff(server *mcp.Server) {
 			}
 
 			var diffs []gitlab.Diff
-			if err := c.Client.Get(endpoint, &diffs); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get commit diff: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &diffs)
+			if err != nil {
+				return APIErrorResult("Failed to get commit diff", err)
 			}
 
-			return JSONResult(diffs)
+			return PagedJSONResult(diffs, pagination)
 		},
 	)
 }
@@ -361,12 +547,12 @@ func registerListReleases(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("list_releases", args)
+			c.Logger.ToolCall(ctx, "list_releases", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -399,11 +585,12 @@ func registerListReleases(server *mcp.Server) {
 			}
 
 			var releases []gitlab.Release
-			if err := c.Client.Get(endpoint, &releases); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list releases: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &releases)
+			if err != nil {
+				return APIErrorResult("Failed to list releases", err)
 			}
 
-			return JSONResult(releases)
+			return PagedJSONResult(releases, pagination)
 		},
 	)
 }
@@ -413,10 +600,10 @@ func registerDownloadAttachment(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "download_attachment",
-			Description: "Download an uploaded file/attachment from a GitLab project",
+			Description: "Download an uploaded file/attachment from a GitLab project. Text is returned as is and binaries (images, archives) as base64, with the detected content type; large files can be written to a temporary file instead.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
-				Properties: map[string]mcp.Property{
+				Properties: withDownloadOutputProperties(map[string]mcp.Property{
 					"project_id": {
 						Type:        "string",
 						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
@@ -429,19 +616,19 @@ func registerDownloadAttachment(server *mcp.Server) {
 						Type:        "string",
 						Description: "The filename of the upload",
 					},
-				},
+				}),
 				Required: []string{"project_id", "secret", "filename"},
 			},
 			Annotations: &mcp.ToolAnnotations{
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("download_attachment", args)
+			c.Logger.ToolCall(ctx, "download_attachment", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -464,14 +651,7 @@ func registerDownloadAttachment(server *mcp.Server) {
 				url.PathEscape(filename),
 			)
 
-			// For file downloads, we get raw content as a string
-			var content string
-			if err := c.Client.Get(endpoint, &content); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to download attachment: %v", err))
-			}
-
-			// Return the file content as text
-			return TextResult(content)
+			return downloadResult(ctx, c, endpoint, filename, args)
 		},
 	)
 }
@@ -482,6 +662,9 @@ func RegisterBranchTools(server *mcp.Server) {
 	registerListCommits(server)
 	registerGetCommit(server)
 	registerGetCommitDiff(server)
+	registerGetMergeBase(server)
+	registerGetCommitRefs(server)
+	registerWaitForCommitStatus(server)
 	registerListReleases(server)
 	registerDownloadAttachment(server)
 }
@@ -0,0 +1,394 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// manifestFiles are the manifests bump_dependency looks for at the root of the
+// repository when no manifest_path is given, in this order.
+var manifestFiles = []string{"go.mod", "package.json", "requirements.txt"}
+
+// errDependencyNotFound is returned by bumpManifest when the manifest does not
+// declare the package.
+var errDependencyNotFound = errors.New("dependency not found")
+
+// packageJSONSections are the package.json sections that declare dependencies.
+var packageJSONSections = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+// DependencyBump is the response of the bump_dependency tool.
+type DependencyBump struct {
+	ManifestPath string `json:"manifest_path"`
+	Package      string `json:"package"`
+	FromVersion  string `json:"from_version"`
+	ToVersion    string `json:"to_version"`
+	Diff         string `json:"diff"`
+	// Notes lists follow-up work the bump leaves to CI or the reviewer, such
+	// as lock files that are not updated
+	Notes           []string `json:"notes,omitempty"`
+	DryRun          bool     `json:"dry_run,omitempty"`
+	Branch          string   `json:"branch,omitempty"`
+	CommitID        string   `json:"commit_id,omitempty"`
+	MergeRequestIID int      `json:"merge_request_iid,omitempty"`
+	MergeRequestURL string   `json:"merge_request_url,omitempty"`
+}
+
+type bumpDependencyArgs struct {
+	ProjectID    string `json:"project_id" validate:"required"`
+	Package      string `json:"package" validate:"required"`
+	Version      string `json:"version" validate:"required"`
+	ManifestPath string `json:"manifest_path"`
+	Ref          string `json:"ref"`
+	Branch       string `json:"branch"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Labels       string `json:"labels"`
+	Draft        bool   `json:"draft"`
+	DryRun       bool   `json:"dry_run"`
+}
+
+// registerBumpDependency registers the bump_dependency tool.
+func registerBumpDependency(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "bump_dependency",
+			Description: "Update one package to a new version in a go.mod, package.json or requirements.txt and open a merge request for it, like a single Renovate update. Without manifest_path the manifests at the repository root are searched for the package. The line is edited in place, keeping formatting, comments and range operators (^, ~); lock files such as go.sum or package-lock.json are not regenerated. Returns the diff and the merge request URL; dry_run only returns the diff.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"package": {
+						Type:        "string",
+						Description: "The package to update, as named in the manifest (e.g., github.com/stretchr/testify, lodash, requests)",
+					},
+					"version": {
+						Type:        "string",
+						Description: "The new version (e.g., v1.9.0, 4.17.21, 2.32.3). requirements.txt also accepts a specifier such as >=2.32",
+					},
+					"manifest_path": {
+						Type:        "string",
+						Description: "Path of the manifest to edit (e.g., services/api/go.mod). Default: the first of go.mod, package.json and requirements.txt at the root that declares the package",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "The branch to update and open the merge request against (optional, default: the project's default branch)",
+					},
+					"branch": {
+						Type:        "string",
+						Description: "The name of the new branch (optional, default: deps/<package>-<version>)",
+					},
+					"title": {
+						Type:        "string",
+						Description: "The merge request title and commit message (optional, default: Bump <package> from <old> to <new>)",
+					},
+					"description": {
+						Type:        "string",
+						Description: "The merge request description (optional, default: a summary with the diff)",
+					},
+					"labels": {
+						Type:        "string",
+						Description: "Comma-separated labels for the merge request (e.g., dependencies)",
+					},
+					"draft": {
+						Type:        "boolean",
+						Description: "Open the merge request as a draft (optional, default: false)",
+					},
+					"dry_run": {
+						Type:        "boolean",
+						Description: "Only return the diff, without creating a branch or merge request (optional, default: false)",
+					},
+				},
+				Required: []string{"project_id", "package", "version"},
+			},
+		},
+		withArgs("bump_dependency", func(ctx context.Context, c *ToolContext, args bumpDependencyArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot bump dependency: server is in read-only mode")
+			}
+
+			encodedProjectID := url.PathEscape(args.ProjectID)
+			ref := args.Ref
+			if ref == "" {
+				var project gitlab.Project
+				if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s", encodedProjectID), &project); err != nil {
+					return APIErrorResult("Failed to get project default branch", err)
+				}
+				ref = project.DefaultBranch
+			}
+
+			candidates := manifestFiles
+			if args.ManifestPath != "" {
+				candidates = []string{strings.TrimPrefix(args.ManifestPath, "/")}
+			}
+			var (
+				manifest          FileResponse
+				original, updated string
+				from, to          string
+				found             bool
+			)
+			for _, candidate := range candidates {
+				endpoint := fmt.Sprintf("/projects/%s/repository/files/%s?ref=%s", encodedProjectID, url.PathEscape(candidate), url.QueryEscape(ref))
+				if err := c.Client.Get(ctx, endpoint, &manifest); err != nil {
+					if gitlab.IsNotFound(err) && args.ManifestPath == "" {
+						continue
+					}
+					return APIErrorResult("Failed to get "+candidate, err)
+				}
+				content, err := base64.StdEncoding.DecodeString(manifest.Content)
+				if err != nil {
+					return APIErrorResult("Failed to decode "+candidate, err)
+				}
+				original = string(content)
+				updated, from, to, err = bumpManifest(candidate, original, args.Package, args.Version)
+				if errors.Is(err, errDependencyNotFound) && args.ManifestPath == "" {
+					continue
+				}
+				if err != nil {
+					return ErrorResult(fmt.Sprintf("cannot update %s: %v", candidate, err))
+				}
+				manifest.FilePath = candidate
+				found = true
+				break
+			}
+			if !found {
+				return ErrorResult(fmt.Sprintf("%s is not declared in %s on %s; pass manifest_path if the manifest is elsewhere", args.Package, strings.Join(manifestFiles, ", "), ref))
+			}
+
+			bump := DependencyBump{
+				ManifestPath: manifest.FilePath,
+				Package:      args.Package,
+				FromVersion:  from,
+				ToVersion:    to,
+				Notes:        manifestNotes(manifest.FilePath),
+				DryRun:       args.DryRun,
+			}
+			if updated == original {
+				return ErrorResult(fmt.Sprintf("%s is already at %s in %s", args.Package, to, manifest.FilePath))
+			}
+			ops, _ := lineDiff(splitLines(original), splitLines(updated))
+			bump.Diff = fmt.Sprintf("--- a/%s\n+++ b/%s\n%s", manifest.FilePath, manifest.FilePath, unifiedDiff(ops, 3))
+			if args.DryRun {
+				return JSONResult(bump)
+			}
+
+			title := args.Title
+			if title == "" {
+				title = fmt.Sprintf("Bump %s from %s to %s", args.Package, from, to)
+			}
+			description := args.Description
+			if description == "" {
+				description = bumpDescription(bump)
+			}
+			branch := args.Branch
+			if branch == "" {
+				branch = dependencyBranch(args.Package, to)
+			}
+			attributes := map[string]interface{}{}
+			if args.Labels != "" {
+				attributes["labels"] = args.Labels
+			}
+
+			change, message, err := proposeChange(ctx, c, changeProposal{
+				ProjectID:     args.ProjectID,
+				Branch:        branch,
+				Ref:           ref,
+				CommitMessage: title,
+				Actions: []CommitAction{{
+					Action:       "update",
+					FilePath:     manifest.FilePath,
+					Content:      updated,
+					LastCommitID: manifest.LastCommitID,
+				}},
+				Title:              title,
+				Description:        description,
+				Draft:              args.Draft,
+				RemoveSourceBranch: true,
+				Attributes:         attributes,
+			})
+			if err != nil {
+				return APIErrorResult(message, err)
+			}
+			bump.Branch = branch
+			bump.CommitID = change.Commit.ID
+			bump.MergeRequestIID = change.MergeRequest.IID
+			bump.MergeRequestURL = change.MergeRequest.WebURL
+			return JSONResult(bump)
+		}),
+	)
+}
+
+// bumpManifest sets the version of pkg in the manifest at filePath, returning
+// the updated content and the old and new version as written in the file.
+func bumpManifest(filePath, content, pkg, version string) (string, string, string, error) {
+	name := path.Base(filePath)
+	switch {
+	case name == "go.mod":
+		return bumpGoMod(content, pkg, version)
+	case name == "package.json":
+		return bumpPackageJSON(content, pkg, version)
+	case strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt"):
+		return bumpRequirements(content, pkg, version)
+	default:
+		return "", "", "", fmt.Errorf("unsupported manifest %s (expected go.mod, package.json or requirements*.txt)", name)
+	}
+}
+
+// bumpGoMod updates a require directive, in a require block or on its own
+// line. Versions without the v prefix get one.
+func bumpGoMod(content, module, version string) (string, string, string, error) {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	lines := strings.SplitAfter(content, "\n")
+	inRequire := false
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch {
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(", fields[0] == "require(":
+			inRequire = true
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		}
+		if !inRequire {
+			if fields[0] != "require" {
+				continue
+			}
+			fields = fields[1:]
+		}
+		if len(fields) < 2 || fields[0] != module {
+			continue
+		}
+		from := fields[1]
+		at := strings.Index(line, module) + len(module)
+		lines[i] = line[:at] + strings.Replace(line[at:], from, version, 1)
+		return strings.Join(lines, ""), from, version, nil
+	}
+	return "", "", "", errDependencyNotFound
+}
+
+// bumpPackageJSON updates every declaration of pkg in the dependency sections
+// of a package.json. The range operator of the old version is kept when the
+// new version has none.
+func bumpPackageJSON(content, pkg, version string) (string, string, string, error) {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return "", "", "", fmt.Errorf("invalid package.json: %w", err)
+	}
+	from := ""
+	for _, section := range packageJSONSections {
+		var deps map[string]string
+		if raw, ok := manifest[section]; !ok || json.Unmarshal(raw, &deps) != nil {
+			continue
+		}
+		if declared, ok := deps[pkg]; ok {
+			from = declared
+			break
+		}
+	}
+	if from == "" {
+		return "", "", "", errDependencyNotFound
+	}
+
+	if version != "" && version[0] >= '0' && version[0] <= '9' && (strings.HasPrefix(from, "^") || strings.HasPrefix(from, "~")) {
+		version = from[:1] + version
+	}
+	declaration := regexp.MustCompile(`("` + regexp.QuoteMeta(pkg) + `"\s*:\s*")[^"]*(")`)
+	return declaration.ReplaceAllString(content, "${1}"+strings.ReplaceAll(version, "$", "$$")+"${2}"), from, version, nil
+}
+
+// requirementName matches the project name and extras at the start of a
+// requirements.txt line.
+var requirementName = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?`)
+
+// pythonNameSeparators are the runs of characters PEP 503 treats as one "-".
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// branchNameUnsafe matches the characters dependencyBranch replaces.
+var branchNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// bumpRequirements pins pkg to version (==version unless version starts with
+// an operator), keeping extras, environment markers and comments. Names are
+// compared normalized, so Foo_Bar matches foo-bar.
+func bumpRequirements(content, pkg, version string) (string, string, string, error) {
+	if !strings.ContainsAny(version[:1], "=<>!~") {
+		version = "==" + version
+	}
+	want := normalizePythonName(pkg)
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		body := strings.TrimRight(line, "\r\n")
+		match := requirementName.FindStringSubmatchIndex(body)
+		if match == nil || normalizePythonName(body[match[2]:match[3]]) != want {
+			continue
+		}
+		rest := body[match[1]:]
+		end := len(rest)
+		if marker := strings.IndexAny(rest, ";#"); marker >= 0 {
+			end = marker
+		}
+		from := strings.TrimSpace(rest[:end])
+		suffix := rest[end:]
+		if suffix != "" && !strings.HasPrefix(suffix, " ") {
+			suffix = " " + suffix
+		}
+		lines[i] = body[:match[1]] + version + suffix + line[len(body):]
+		return strings.Join(lines, ""), from, version, nil
+	}
+	return "", "", "", errDependencyNotFound
+}
+
+// normalizePythonName normalizes a Python project name as in PEP 503.
+func normalizePythonName(name string) string {
+	return strings.ToLower(pythonNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// manifestNotes returns the follow-up work a bump of a manifest leaves to CI
+// or the reviewer.
+func manifestNotes(filePath string) []string {
+	switch path.Base(filePath) {
+	case "go.mod":
+		return []string{"go.sum is not updated; run go mod tidy before merging"}
+	case "package.json":
+		return []string{"Lock files (package-lock.json, yarn.lock, pnpm-lock.yaml) are not updated"}
+	}
+	return nil
+}
+
+// bumpDescription is the default merge request description of a bump.
+func bumpDescription(bump DependencyBump) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Updates `%s` in `%s` from `%s` to `%s`.\n\n", bump.Package, bump.ManifestPath, bump.FromVersion, bump.ToVersion)
+	for _, note := range bump.Notes {
+		fmt.Fprintf(&sb, "- %s\n", note)
+	}
+	if len(bump.Notes) > 0 {
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "```diff\n%s```\n", bump.Diff)
+	return sb.String()
+}
+
+// dependencyBranch returns the default branch name of a bump, e.g.
+// deps/lodash-4.17.21 or deps/github.com-stretchr-testify-v1.9.0.
+func dependencyBranch(pkg, version string) string {
+	name := strings.Trim(branchNameUnsafe.ReplaceAllString(pkg, "-"), "-")
+	return "deps/" + name + "-" + strings.Trim(branchNameUnsafe.ReplaceAllString(version, ""), ".-")
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBumpDependencyReadOnlyMode(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.ReadOnlyMode = true
+	result := callTool(t, tc, "bump_dependency", map[string]interface{}{
+		"project_id": "42",
+		"package":    "github.com/acme/lib",
+		"version":    "v1.2.0",
+	})
+	if !result.IsError || !strings.Contains(resultText(t, result), "server is in read-only mode") {
+		t.Errorf("bump_dependency in read-only mode = %q, want a read-only error", resultText(t, result))
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("read-only mode sent %d requests, want none", len(requests))
+	}
+}
+
+func TestBumpManifest(t *testing.T) {
+	tests := []struct {
+		name, path, content, pkg, version string
+		want, from                        string
+	}{
+		{
+			name:    "go.mod require block",
+			path:    "go.mod",
+			content: "module example.com/app\n\nrequire (\n\tgithub.com/stretchr/testify v1.8.4\n\tgolang.org/x/text v0.14.0 // indirect\n)\n",
+			pkg:     "golang.org/x/text", version: "0.15.0",
+			want: "module example.com/app\n\nrequire (\n\tgithub.com/stretchr/testify v1.8.4\n\tgolang.org/x/text v0.15.0 // indirect\n)\n",
+			from: "v0.14.0",
+		},
+		{
+			name:    "go.mod single require",
+			path:    "svc/go.mod",
+			content: "module example.com/svc\n\nrequire github.com/google/uuid v1.5.0\n",
+			pkg:     "github.com/google/uuid", version: "v1.6.0",
+			want: "module example.com/svc\n\nrequire github.com/google/uuid v1.6.0\n",
+			from: "v1.5.0",
+		},
+		{
+			name:    "package.json keeps the range operator",
+			path:    "package.json",
+			content: "{\n  \"name\": \"web\",\n  \"dependencies\": {\n    \"lodash\": \"^4.17.15\",\n    \"react\": \"18.2.0\"\n  }\n}\n",
+			pkg:     "lodash", version: "4.17.21",
+			want: "{\n  \"name\": \"web\",\n  \"dependencies\": {\n    \"lodash\": \"^4.17.21\",\n    \"react\": \"18.2.0\"\n  }\n}\n",
+			from: "^4.17.15",
+		},
+		{
+			name:    "requirements.txt keeps extras and markers",
+			path:    "requirements.txt",
+			content: "# pinned\nDjango==4.2.1\nrequests[socks]>=2.28 ; python_version >= \"3.8\"\n",
+			pkg:     "Requests", version: "2.32.3",
+			want: "# pinned\nDjango==4.2.1\nrequests[socks]==2.32.3 ; python_version >= \"3.8\"\n",
+			from: ">=2.28",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, from, _, err := bumpManifest(tt.path, tt.content, tt.pkg, tt.version)
+			if err != nil {
+				t.Fatalf("bumpManifest: %v", err)
+			}
+			if got != tt.want || from != tt.from {
+				t.Errorf("bumpManifest = %q (from %q), want %q (from %q)", got, from, tt.want, tt.from)
+			}
+		})
+	}
+
+	if _, _, _, err := bumpManifest("go.mod", "module x\n\nrequire a.b/c v1.0.0\n", "a.b/d", "v2.0.0"); err != errDependencyNotFound {
+		t.Errorf("bumpManifest of a missing module: err = %v, want errDependencyNotFound", err)
+	}
+}
+
+func TestBumpDependency(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/42", http.StatusOK, map[string]interface{}{"id": 42, "default_branch": "main"})
+	client.Handle(http.MethodGet, "/projects/42/repository/files/go.mod?ref=main", http.StatusOK, map[string]interface{}{
+		"file_path":      "go.mod",
+		"content":        base64.StdEncoding.EncodeToString([]byte("module example.com/app\n")),
+		"last_commit_id": "aaa111",
+	})
+	client.Handle(http.MethodGet, "/projects/42/repository/files/package.json?ref=main", http.StatusOK, map[string]interface{}{
+		"file_path":      "package.json",
+		"content":        base64.StdEncoding.EncodeToString([]byte("{\n  \"devDependencies\": {\n    \"eslint\": \"~8.50.0\"\n  }\n}\n")),
+		"last_commit_id": "bbb222",
+	})
+	client.Handle(http.MethodPost, "/projects/42/repository/branches", http.StatusCreated, map[string]interface{}{"name": "deps/eslint-8.57.0"})
+	client.Handle(http.MethodPost, "/projects/42/repository/commits", http.StatusCreated, map[string]interface{}{"id": "ccc333", "short_id": "ccc333"})
+	client.Handle(http.MethodPost, "/projects/42/merge_requests", http.StatusCreated, map[string]interface{}{"iid": 7, "web_url": "https://gitlab.example.com/app/-/merge_requests/7"})
+
+	result := callTool(t, tc, "bump_dependency", map[string]interface{}{
+		"project_id": "42",
+		"package":    "eslint",
+		"version":    "8.57.0",
+		"labels":     "dependencies",
+	})
+	if result.IsError {
+		t.Fatalf("bump_dependency failed: %s", resultText(t, result))
+	}
+	var got DependencyBump
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if got.ManifestPath != "package.json" || got.FromVersion != "~8.50.0" || got.ToVersion != "~8.57.0" || got.MergeRequestIID != 7 || got.Branch != "deps/eslint-8.57.0" {
+		t.Errorf("bump = %+v", got)
+	}
+	if !strings.Contains(got.Diff, "-    \"eslint\": \"~8.50.0\"\n+    \"eslint\": \"~8.57.0\"\n") {
+		t.Errorf("diff = %q", got.Diff)
+	}
+
+	var commit CommitRequest
+	var mr map[string]interface{}
+	for _, req := range client.Requests() {
+		switch req.Endpoint {
+		case "/projects/42/repository/commits":
+			if err := json.Unmarshal(req.Body, &commit); err != nil {
+				t.Fatalf("commit body: %v", err)
+			}
+		case "/projects/42/merge_requests":
+			if err := json.Unmarshal(req.Body, &mr); err != nil {
+				t.Fatalf("merge request body: %v", err)
+			}
+		}
+	}
+	if len(commit.Actions) != 1 || commit.Actions[0].FilePath != "package.json" || commit.Actions[0].LastCommitID != "bbb222" {
+		t.Errorf("commit = %+v", commit)
+	}
+	if mr["target_branch"] != "main" || mr["labels"] != "dependencies" || mr["title"] != "Bump eslint from ~8.50.0 to ~8.57.0" {
+		t.Errorf("merge request = %v", mr)
+	}
+}
@@ -0,0 +1,450 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// ciGlobalKeywords are the top-level .gitlab-ci.yml keys that are not jobs.
+var ciGlobalKeywords = map[string]bool{
+	"default":       true,
+	"include":       true,
+	"stages":        true,
+	"variables":     true,
+	"workflow":      true,
+	"image":         true,
+	"services":      true,
+	"cache":         true,
+	"before_script": true,
+	"after_script":  true,
+}
+
+// CIInclude is a file included into the pipeline configuration.
+type CIInclude struct {
+	Type           string `json:"type"`
+	Location       string `json:"location"`
+	Blob           string `json:"blob,omitempty"`
+	Raw            string `json:"raw,omitempty"`
+	ContextProject string `json:"context_project,omitempty"`
+	ContextSHA     string `json:"context_sha,omitempty"`
+}
+
+// CIConfigJob is a job of the merged configuration. Stage is "test" when the
+// job does not set one, as in GitLab.
+type CIConfigJob struct {
+	Name  string `json:"name"`
+	Stage string `json:"stage"`
+}
+
+// ciLintJob is a job as listed by the CI lint API with include_jobs=true.
+type ciLintJob struct {
+	Name         string      `json:"name"`
+	Stage        string      `json:"stage"`
+	When         string      `json:"when"`
+	AllowFailure bool        `json:"allow_failure"`
+	Environment  interface{} `json:"environment,omitempty"`
+	Needs        interface{} `json:"needs,omitempty"`
+}
+
+// environmentName returns the environment of a lint job, which is a name or
+// an object with a name.
+func (j ciLintJob) environmentName() string {
+	switch env := j.Environment.(type) {
+	case string:
+		return env
+	case map[string]interface{}:
+		name, _ := env["name"].(string)
+		return name
+	}
+	return ""
+}
+
+// ciLintResult is the response of the project CI lint API.
+type ciLintResult struct {
+	Valid      bool        `json:"valid"`
+	Errors     []string    `json:"errors"`
+	Warnings   []string    `json:"warnings"`
+	MergedYAML string      `json:"merged_yaml"`
+	Includes   []CIInclude `json:"includes"`
+	Jobs       []ciLintJob `json:"jobs"`
+}
+
+// ciLintOptions selects the configuration to lint: the .gitlab-ci.yml at
+// ContentRef, or Content when set. DryRun simulates pipeline creation on
+// DryRunRef, which evaluates rules.
+type ciLintOptions struct {
+	Content     string
+	ContentRef  string
+	DryRun      bool
+	DryRunRef   string
+	IncludeJobs bool
+}
+
+// lintCIConfig runs the project CI lint API.
+func lintCIConfig(ctx context.Context, client gitlab.API, projectID string, opts ciLintOptions) (*ciLintResult, error) {
+	endpoint := fmt.Sprintf("/projects/%s/ci/lint", url.PathEscape(projectID))
+	var result ciLintResult
+	if opts.Content != "" {
+		body := map[string]interface{}{
+			"content":      opts.Content,
+			"dry_run":      opts.DryRun,
+			"include_jobs": opts.IncludeJobs,
+		}
+		if opts.DryRunRef != "" {
+			body["ref"] = opts.DryRunRef
+		}
+		if err := client.Post(ctx, endpoint, body, &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+
+	params := url.Values{}
+	if opts.ContentRef != "" {
+		params.Set("content_ref", opts.ContentRef)
+	}
+	if opts.DryRun {
+		params.Set("dry_run", "true")
+		if opts.DryRunRef != "" {
+			params.Set("dry_run_ref", opts.DryRunRef)
+		}
+	}
+	if opts.IncludeJobs {
+		params.Set("include_jobs", "true")
+	}
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	if err := client.Get(ctx, endpoint, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ciConfigJobs returns the jobs of a merged configuration in file order,
+// skipping global keywords and hidden (".") jobs, and the YAML node of each
+// job by name.
+func ciConfigJobs(mergedYAML string) ([]CIConfigJob, map[string]*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(mergedYAML), &doc); err != nil {
+		return nil, nil, fmt.Errorf("parsing merged YAML: %w", err)
+	}
+	jobs := []CIConfigJob{}
+	nodes := map[string]*yaml.Node{}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return jobs, nodes, nil
+	}
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		name, value := root.Content[i].Value, root.Content[i+1]
+		if ciGlobalKeywords[name] || strings.HasPrefix(name, ".") || value.Kind != yaml.MappingNode {
+			continue
+		}
+		job := CIConfigJob{Name: name, Stage: "test"}
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			if value.Content[j].Value == "stage" {
+				job.Stage = value.Content[j+1].Value
+			}
+		}
+		jobs = append(jobs, job)
+		nodes[name] = value
+	}
+	return jobs, nodes, nil
+}
+
+// yamlMapping returns the mapping "key: value".
+func yamlMapping(key string, value *yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Value: key}, value}}
+}
+
+// marshalCIYAML renders node with the two-space indent of .gitlab-ci.yml.
+func marshalCIYAML(node *yaml.Node) (string, error) {
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// MergedCIConfig is the response of the get_merged_ci_config tool.
+type MergedCIConfig struct {
+	Valid    bool          `json:"valid"`
+	Errors   []string      `json:"errors"`
+	Warnings []string      `json:"warnings"`
+	Includes []CIInclude   `json:"includes"`
+	Jobs     []CIConfigJob `json:"jobs"`
+	// MergedYAML is the whole expanded configuration, or the definition of
+	// job when one was requested
+	MergedYAML string `json:"merged_yaml"`
+	Job        string `json:"job,omitempty"`
+}
+
+type getMergedCIConfigArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	Ref       string `json:"ref"`
+	Content   string `json:"content"`
+	Job       string `json:"job"`
+}
+
+// registerGetMergedCIConfig registers the get_merged_ci_config tool.
+func registerGetMergedCIConfig(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_merged_ci_config",
+			Description: "Get the fully expanded CI/CD configuration of a project after include, extends, !reference and anchors are resolved, as the CI lint API computes it. Returns the merged YAML, the included files, the job names with their stages, and lint errors and warnings. Set job to return only that job's expanded definition; set content to expand an edited .gitlab-ci.yml before committing it.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Branch, tag or commit SHA whose .gitlab-ci.yml is expanded. Default: the default branch",
+					},
+					"content": {
+						Type:        "string",
+						Description: "A .gitlab-ci.yml to expand instead of the committed one; includes resolve against the project",
+					},
+					"job": {
+						Type:        "string",
+						Description: "Only return the expanded definition of this job (e.g., deploy-production)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_merged_ci_config", func(ctx context.Context, c *ToolContext, args getMergedCIConfigArgs) (*mcp.CallToolResult, error) {
+			lint, err := lintCIConfig(ctx, c.Client, args.ProjectID, ciLintOptions{Content: args.Content, ContentRef: args.Ref})
+			if err != nil {
+				return APIErrorResult("Failed to expand CI configuration", err)
+			}
+			config := MergedCIConfig{
+				Valid:      lint.Valid,
+				Errors:     nonNilStrings(lint.Errors),
+				Warnings:   nonNilStrings(lint.Warnings),
+				Includes:   lint.Includes,
+				Jobs:       []CIConfigJob{},
+				MergedYAML: lint.MergedYAML,
+			}
+			if config.Includes == nil {
+				config.Includes = []CIInclude{}
+			}
+			// An invalid configuration has no merged YAML
+			if lint.MergedYAML == "" {
+				if args.Job != "" && lint.Valid {
+					return ErrorResult(fmt.Sprintf("job %s not found: the configuration has no jobs", args.Job))
+				}
+				return JSONResult(config)
+			}
+
+			jobs, nodes, err := ciConfigJobs(lint.MergedYAML)
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			config.Jobs = jobs
+			if args.Job != "" {
+				node, ok := nodes[args.Job]
+				if !ok {
+					names := make([]string, len(jobs))
+					for i, job := range jobs {
+						names[i] = job.Name
+					}
+					return ErrorResult(fmt.Sprintf("job %s not found; jobs: %s", args.Job, strings.Join(names, ", ")))
+				}
+				out, err := marshalCIYAML(yamlMapping(args.Job, node))
+				if err != nil {
+					return ErrorResult(fmt.Sprintf("rendering job %s: %v", args.Job, err))
+				}
+				config.Job = args.Job
+				config.MergedYAML = out
+			}
+			return JSONResult(config)
+		}),
+	)
+}
+
+// nonNilStrings returns values, or an empty slice for nil so it encodes as [].
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// SimulatedJob is a job of a simulated pipeline. Conditions holds the job's
+// rules, only and except as YAML, which decide whether it is created.
+type SimulatedJob struct {
+	Name         string `json:"name"`
+	Stage        string `json:"stage"`
+	When         string `json:"when,omitempty"`
+	AllowFailure bool   `json:"allow_failure,omitempty"`
+	Environment  string `json:"environment,omitempty"`
+	Conditions   string `json:"conditions,omitempty"`
+	// Reason says why an excluded job would not be created
+	Reason string `json:"reason,omitempty"`
+}
+
+// SimulatedPipeline is the response of the simulate_pipeline tool.
+type SimulatedPipeline struct {
+	Ref      string         `json:"ref,omitempty"`
+	Valid    bool           `json:"valid"`
+	Errors   []string       `json:"errors"`
+	Warnings []string       `json:"warnings"`
+	Stages   []string       `json:"stages"`
+	Jobs     []SimulatedJob `json:"jobs"`
+	Excluded []SimulatedJob `json:"excluded"`
+	Notes    []string       `json:"notes,omitempty"`
+}
+
+// jobConditions renders the rules, only and except of a job, or "" when it
+// has none.
+func jobConditions(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	conditions := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case "rules", "only", "except":
+			conditions.Content = append(conditions.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	if len(conditions.Content) == 0 {
+		return ""
+	}
+	out, err := marshalCIYAML(conditions)
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// hasKey reports whether a mapping node has key.
+func hasKey(node *yaml.Node, key string) bool {
+	for i := 0; node != nil && i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+type simulatePipelineArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	Ref       string `json:"ref"`
+	Content   string `json:"content"`
+}
+
+// registerSimulatePipeline registers the simulate_pipeline tool.
+func registerSimulatePipeline(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "simulate_pipeline",
+			Description: "Simulate creating a pipeline on a branch or tag without running it (CI lint dry run) to see which jobs would be created, by stage, and which would not. Each job carries its rules/only/except so the outcome can be explained. Set content to check an edited .gitlab-ci.yml before pushing it; otherwise the configuration committed on ref is used.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Branch or tag the pipeline is simulated for. Default: the default branch",
+					},
+					"content": {
+						Type:        "string",
+						Description: "A .gitlab-ci.yml to simulate instead of the one committed on ref",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("simulate_pipeline", func(ctx context.Context, c *ToolContext, args simulatePipelineArgs) (*mcp.CallToolResult, error) {
+			lint, err := lintCIConfig(ctx, c.Client, args.ProjectID, ciLintOptions{
+				Content:     args.Content,
+				ContentRef:  args.Ref,
+				DryRun:      true,
+				DryRunRef:   args.Ref,
+				IncludeJobs: true,
+			})
+			if err != nil {
+				return APIErrorResult("Failed to simulate pipeline", err)
+			}
+			result := SimulatedPipeline{
+				Ref:      args.Ref,
+				Valid:    lint.Valid,
+				Errors:   nonNilStrings(lint.Errors),
+				Warnings: nonNilStrings(lint.Warnings),
+				Stages:   []string{},
+				Jobs:     []SimulatedJob{},
+				Excluded: []SimulatedJob{},
+				Notes:    []string{"rules:changes and merge request variables are evaluated as for a push to ref, so merge request pipelines can differ."},
+			}
+			if !lint.Valid {
+				return JSONResult(result)
+			}
+
+			// The merged YAML lists every job, the dry run only the created ones
+			configured := []CIConfigJob{}
+			nodes := map[string]*yaml.Node{}
+			if lint.MergedYAML != "" {
+				if configured, nodes, err = ciConfigJobs(lint.MergedYAML); err != nil {
+					return ErrorResult(err.Error())
+				}
+			}
+
+			created := map[string]bool{}
+			for _, job := range lint.Jobs {
+				created[job.Name] = true
+				if !containsString(result.Stages, job.Stage) {
+					result.Stages = append(result.Stages, job.Stage)
+				}
+				result.Jobs = append(result.Jobs, SimulatedJob{
+					Name:         job.Name,
+					Stage:        job.Stage,
+					When:         job.When,
+					AllowFailure: job.AllowFailure,
+					Environment:  job.environmentName(),
+					Conditions:   jobConditions(nodes[job.Name]),
+				})
+			}
+			for _, job := range configured {
+				if created[job.Name] {
+					continue
+				}
+				excluded := SimulatedJob{Name: job.Name, Stage: job.Stage, Conditions: jobConditions(nodes[job.Name])}
+				switch node := nodes[job.Name]; {
+				case hasKey(node, "rules"):
+					excluded.Reason = "no rules entry matched, or the matching entry has when: never"
+				case hasKey(node, "only") || hasKey(node, "except"):
+					excluded.Reason = "only/except exclude the ref"
+				default:
+					excluded.Reason = "not created by the dry run (e.g., it needs an excluded job)"
+				}
+				result.Excluded = append(result.Excluded, excluded)
+			}
+			if len(result.Jobs) == 0 && len(result.Errors) == 0 {
+				result.Notes = append(result.Notes, "No jobs would run; workflow:rules may prevent the pipeline on this ref.")
+			}
+			return JSONResult(result)
+		}),
+	)
+}
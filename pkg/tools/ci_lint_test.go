@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetMergedCIConfig(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.UsePipeline = true
+	merged := "stages:\n- build\n- deploy\n.deploy:\n  image: alpine\nbuild:\n  stage: build\n  script:\n  - make\nlint:\n  script:\n  - make lint\ndeploy-production:\n  image: alpine\n  stage: deploy\n  script:\n  - ./deploy.sh\n"
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/ci/lint?content_ref=main", http.StatusOK, map[string]interface{}{
+		"valid":       true,
+		"merged_yaml": merged,
+		"includes":    []map[string]interface{}{{"type": "file", "location": "/templates/deploy.yml", "context_project": "acme/ci-templates"}},
+	})
+
+	result := callTool(t, tc, "get_merged_ci_config", map[string]interface{}{"project_id": "acme/api", "ref": "main"})
+	if result.IsError {
+		t.Fatalf("get_merged_ci_config failed: %s", resultText(t, result))
+	}
+	var got MergedCIConfig
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	want := []CIConfigJob{{"build", "build"}, {"lint", "test"}, {"deploy-production", "deploy"}}
+	if fmt.Sprint(got.Jobs) != fmt.Sprint(want) {
+		t.Errorf("jobs = %v, want %v", got.Jobs, want)
+	}
+	if len(got.Includes) != 1 || got.MergedYAML != merged || len(got.Errors) != 0 {
+		t.Errorf("config = %+v", got)
+	}
+
+	result = callTool(t, tc, "get_merged_ci_config", map[string]interface{}{"project_id": "acme/api", "ref": "main", "job": "deploy-production"})
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if want := "deploy-production:\n  image: alpine\n  stage: deploy\n  script:\n    - ./deploy.sh\n"; got.MergedYAML != want {
+		t.Errorf("job YAML = %q, want %q", got.MergedYAML, want)
+	}
+
+	result = callTool(t, tc, "get_merged_ci_config", map[string]interface{}{"project_id": "acme/api", "ref": "main", "job": "test"})
+	if text := resultText(t, result); !result.IsError || !strings.Contains(text, "build, lint, deploy-production") {
+		t.Errorf("unknown job result = %q", text)
+	}
+
+	client.Handle(http.MethodPost, "/projects/acme%2Fapi/ci/lint", http.StatusOK, map[string]interface{}{
+		"valid": false, "errors": []string{"jobs:build config contains unknown keys: scrpt"},
+	})
+	result = callTool(t, tc, "get_merged_ci_config", map[string]interface{}{"project_id": "acme/api", "content": "build:\n  scrpt: make\n"})
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if got.Valid || len(got.Errors) != 1 || len(got.Jobs) != 0 {
+		t.Errorf("invalid content = %+v", got)
+	}
+}
+
+func TestSimulatePipeline(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.UsePipeline = true
+	merged := "build:\n  stage: build\n  script: make\ndeploy:\n  stage: deploy\n  script: ./deploy.sh\n  rules:\n  - if: $CI_COMMIT_TAG\nnightly:\n  script: ./nightly.sh\n  only:\n  - schedules\n"
+	client.Handle(http.MethodPost, "/projects/acme%2Fapi/ci/lint", http.StatusOK, map[string]interface{}{
+		"valid":       true,
+		"merged_yaml": merged,
+		"jobs": []map[string]interface{}{
+			{"name": "build", "stage": "build", "when": "on_success"},
+		},
+	})
+
+	content := "build:\n  stage: build\n  script: make\n"
+	result := callTool(t, tc, "simulate_pipeline", map[string]interface{}{"project_id": "acme/api", "ref": "feature", "content": content})
+	if result.IsError {
+		t.Fatalf("simulate_pipeline failed: %s", resultText(t, result))
+	}
+	var got SimulatedPipeline
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if len(got.Jobs) != 1 || got.Jobs[0].Name != "build" || fmt.Sprint(got.Stages) != "[build]" {
+		t.Errorf("jobs = %+v, stages = %v", got.Jobs, got.Stages)
+	}
+	if len(got.Excluded) != 2 {
+		t.Fatalf("excluded = %+v, want deploy and nightly", got.Excluded)
+	}
+	if deploy := got.Excluded[0]; deploy.Name != "deploy" || !strings.Contains(deploy.Reason, "rules") || deploy.Conditions != "rules:\n  - if: $CI_COMMIT_TAG\n" {
+		t.Errorf("deploy = %+v", deploy)
+	}
+	if nightly := got.Excluded[1]; nightly.Name != "nightly" || nightly.Stage != "test" || !strings.Contains(nightly.Reason, "only/except") {
+		t.Errorf("nightly = %+v", nightly)
+	}
+
+	requests := client.Requests()
+	var body map[string]interface{}
+	if err := json.Unmarshal(requests[len(requests)-1].Body, &body); err != nil {
+		t.Fatalf("lint body: %v", err)
+	}
+	if body["content"] != content || body["dry_run"] != true || body["include_jobs"] != true || body["ref"] != "feature" {
+		t.Errorf("lint body = %v", body)
+	}
+}
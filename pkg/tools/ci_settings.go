@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// JobTokenProject is a project on a job token allowlist.
+type JobTokenProject struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+// JobTokenGroup is a group on a job token allowlist.
+type JobTokenGroup struct {
+	ID       int    `json:"id"`
+	FullPath string `json:"full_path"`
+}
+
+// JobTokenScope is who may use CI job tokens to access the project. When
+// InboundEnabled is true, only jobs of the project itself and of the
+// allowlisted projects and groups may.
+type JobTokenScope struct {
+	InboundEnabled    bool              `json:"inbound_enabled"`
+	AllowlistProjects []JobTokenProject `json:"allowlist_projects"`
+	AllowlistGroups   []JobTokenGroup   `json:"allowlist_groups"`
+}
+
+// CISettings is the response of the get_ci_settings and update_ci_settings
+// tools. BuildTimeout is in seconds.
+type CISettings struct {
+	ProjectID                  string         `json:"project_id"`
+	BuildsAccessLevel          string         `json:"builds_access_level"`
+	BuildTimeout               int            `json:"build_timeout"`
+	AutoCancelPendingPipelines string         `json:"auto_cancel_pending_pipelines"`
+	CIConfigPath               string         `json:"ci_config_path"`
+	CIDefaultGitDepth          int            `json:"ci_default_git_depth"`
+	CIForwardDeploymentEnabled bool           `json:"ci_forward_deployment_enabled"`
+	JobTokenScope              *JobTokenScope `json:"job_token_scope,omitempty"`
+	Notes                      []string       `json:"notes,omitempty"`
+}
+
+// getCISettings reads the CI settings and job token scope of a project. The
+// job token scope needs the Maintainer role; without it a note is returned.
+func getCISettings(ctx context.Context, client gitlab.API, projectID string) (*CISettings, error) {
+	project := url.PathEscape(projectID)
+	settings := CISettings{}
+	if err := client.Get(ctx, "/projects/"+project, &settings); err != nil {
+		return nil, err
+	}
+	settings.ProjectID = projectID
+
+	scope := JobTokenScope{AllowlistProjects: []JobTokenProject{}, AllowlistGroups: []JobTokenGroup{}}
+	if err := client.Get(ctx, fmt.Sprintf("/projects/%s/job_token_scope", project), &scope); err != nil {
+		if gitlab.IsForbidden(err) {
+			settings.Notes = append(settings.Notes, "the job token scope needs the Maintainer role and was skipped")
+			return &settings, nil
+		}
+		return nil, err
+	}
+	projects, _, err := collectPages[JobTokenProject](ctx, client, fmt.Sprintf("/projects/%s/job_token_scope/allowlist", project), maxCollectedItems)
+	if err != nil {
+		return nil, err
+	}
+	groups, _, err := collectPages[JobTokenGroup](ctx, client, fmt.Sprintf("/projects/%s/job_token_scope/groups_allowlist", project), maxCollectedItems)
+	if err != nil {
+		return nil, err
+	}
+	scope.AllowlistProjects = append(scope.AllowlistProjects, projects...)
+	scope.AllowlistGroups = append(scope.AllowlistGroups, groups...)
+	settings.JobTokenScope = &scope
+	return &settings, nil
+}
+
+// resolveNamespaceID returns the numeric ID of a project or group given by ID
+// or path; kind is "projects" or "groups".
+func resolveNamespaceID(ctx context.Context, client gitlab.API, kind, ref string) (int, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return id, nil
+	}
+	var found struct {
+		ID int `json:"id"`
+	}
+	if err := client.Get(ctx, fmt.Sprintf("/%s/%s", kind, url.PathEscape(ref)), &found); err != nil {
+		return 0, err
+	}
+	return found.ID, nil
+}
+
+type getCISettingsArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+}
+
+// registerGetCISettings registers the get_ci_settings tool.
+func registerGetCISettings(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_ci_settings",
+			Description: "Get the CI/CD settings of a project: who can see pipelines (builds_access_level), the default job timeout, auto-cancel of redundant pipelines, the CI config path and git depth, and the CI job token scope with its project and group allowlists.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		withArgs("get_ci_settings", func(ctx context.Context, c *ToolContext, args getCISettingsArgs) (*mcp.CallToolResult, error) {
+			settings, err := getCISettings(ctx, c.Client, args.ProjectID)
+			if err != nil {
+				return APIErrorResult("Failed to get CI settings", err)
+			}
+			return JSONResult(settings)
+		}),
+	)
+}
+
+type updateCISettingsArgs struct {
+	ProjectID                  string   `json:"project_id" validate:"required"`
+	BuildsAccessLevel          string   `json:"builds_access_level" validate:"oneof=disabled private enabled"`
+	BuildTimeout               int      `json:"build_timeout" validate:"min=600,max=2592000"`
+	AutoCancelPendingPipelines string   `json:"auto_cancel_pending_pipelines" validate:"oneof=enabled disabled"`
+	JobTokenScope              string   `json:"job_token_scope" validate:"oneof=enabled disabled"`
+	AllowlistAddProjects       []string `json:"allowlist_add_projects"`
+	AllowlistRemoveProjects    []string `json:"allowlist_remove_projects"`
+	AllowlistAddGroups         []string `json:"allowlist_add_groups"`
+	AllowlistRemoveGroups      []string `json:"allowlist_remove_groups"`
+}
+
+// registerUpdateCISettings registers the update_ci_settings tool.
+func registerUpdateCISettings(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "update_ci_settings",
+			Description: "Update the CI/CD settings of a project: builds_access_level, the default job timeout, auto-cancel of redundant pipelines, whether the CI job token scope is enforced, and the projects and groups on the job token allowlist. Only the given settings change; the resulting settings are returned. Changes are applied in that order and stop at the first failure.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"builds_access_level": {
+						Type:        "string",
+						Description: "Who can see pipelines and jobs: disabled, private (project members) or enabled (everyone with access to the project)",
+						Enum:        []string{"disabled", "private", "enabled"},
+					},
+					"build_timeout": {
+						Type:        "integer",
+						Description: "Default job timeout in seconds (600 to 2592000, i.e. 10 minutes to 30 days)",
+						Minimum:     mcp.IntPtr(600),
+						Maximum:     mcp.IntPtr(2592000),
+					},
+					"auto_cancel_pending_pipelines": {
+						Type:        "string",
+						Description: "Cancel redundant pipelines when a newer one starts on the same ref",
+						Enum:        []string{"enabled", "disabled"},
+					},
+					"job_token_scope": {
+						Type:        "string",
+						Description: "enabled limits job token access to this project to the allowlist; disabled allows any project",
+						Enum:        []string{"enabled", "disabled"},
+					},
+					"allowlist_add_projects": {
+						Type:        "array",
+						Description: "Projects (IDs or paths) whose job tokens may access this project",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"allowlist_remove_projects": {
+						Type:        "array",
+						Description: "Projects (IDs or paths) to remove from the job token allowlist",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"allowlist_add_groups": {
+						Type:        "array",
+						Description: "Groups (IDs or paths) whose projects' job tokens may access this project",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"allowlist_remove_groups": {
+						Type:        "array",
+						Description: "Groups (IDs or paths) to remove from the job token allowlist",
+						Items:       &mcp.Property{Type: "string"},
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		withArgs("update_ci_settings", func(ctx context.Context, c *ToolContext, args updateCISettingsArgs) (*mcp.CallToolResult, error) {
+			project := url.PathEscape(args.ProjectID)
+
+			body := map[string]interface{}{}
+			if args.BuildsAccessLevel != "" {
+				body["builds_access_level"] = args.BuildsAccessLevel
+			}
+			if args.BuildTimeout != 0 {
+				body["build_timeout"] = args.BuildTimeout
+			}
+			if args.AutoCancelPendingPipelines != "" {
+				body["auto_cancel_pending_pipelines"] = args.AutoCancelPendingPipelines
+			}
+			if len(body) == 0 && args.JobTokenScope == "" && len(args.AllowlistAddProjects)+len(args.AllowlistRemoveProjects)+len(args.AllowlistAddGroups)+len(args.AllowlistRemoveGroups) == 0 {
+				return ErrorResult("no settings to update")
+			}
+
+			if len(body) > 0 {
+				if err := c.Client.Put(ctx, "/projects/"+project, body, nil); err != nil {
+					return APIErrorResult("Failed to update project CI settings", err)
+				}
+			}
+			if args.JobTokenScope != "" {
+				scope := map[string]interface{}{"enabled": args.JobTokenScope == "enabled"}
+				if err := c.Client.Patch(ctx, fmt.Sprintf("/projects/%s/job_token_scope", project), scope, nil); err != nil {
+					return APIErrorResult("Failed to update the job token scope", err)
+				}
+			}
+
+			allowlist := []struct {
+				refs        []string
+				kind, list  string
+				targetField string
+				add         bool
+			}{
+				{args.AllowlistAddProjects, "projects", "allowlist", "target_project_id", true},
+				{args.AllowlistRemoveProjects, "projects", "allowlist", "target_project_id", false},
+				{args.AllowlistAddGroups, "groups", "groups_allowlist", "target_group_id", true},
+				{args.AllowlistRemoveGroups, "groups", "groups_allowlist", "target_group_id", false},
+			}
+			for _, change := range allowlist {
+				endpoint := fmt.Sprintf("/projects/%s/job_token_scope/%s", project, change.list)
+				for _, ref := range change.refs {
+					id, err := resolveNamespaceID(ctx, c.Client, change.kind, ref)
+					if err != nil {
+						return APIErrorResult(fmt.Sprintf("Failed to resolve %s", ref), err)
+					}
+					if change.add {
+						err = c.Client.Post(ctx, endpoint, map[string]interface{}{change.targetField: id}, nil)
+					} else {
+						err = c.Client.Delete(ctx, fmt.Sprintf("%s/%d", endpoint, id))
+					}
+					if err != nil {
+						action := "add"
+						if !change.add {
+							action = "remove"
+						}
+						return APIErrorResult(fmt.Sprintf("Failed to %s %s on the job token allowlist", action, ref), err)
+					}
+				}
+			}
+
+			settings, err := getCISettings(ctx, c.Client, args.ProjectID)
+			if err != nil {
+				return APIErrorResult("Settings were updated, but reading them back failed", err)
+			}
+			return JSONResult(settings)
+		}),
+	)
+}
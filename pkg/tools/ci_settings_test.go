@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUpdateCISettings(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.UsePipeline = true
+	client.Handle(http.MethodPut, "/projects/acme%2Fapi", http.StatusOK, map[string]interface{}{"id": 42})
+	client.Handle(http.MethodPatch, "/projects/acme%2Fapi/job_token_scope", http.StatusNoContent, nil)
+	client.Handle(http.MethodGet, "/projects/acme%2Fdeployer", http.StatusOK, map[string]interface{}{"id": 77})
+	client.Handle(http.MethodPost, "/projects/acme%2Fapi/job_token_scope/allowlist", http.StatusCreated, map[string]interface{}{})
+	client.Handle(http.MethodDelete, "/projects/acme%2Fapi/job_token_scope/groups_allowlist/9", http.StatusNoContent, nil)
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi", http.StatusOK, map[string]interface{}{
+		"id": 42, "builds_access_level": "private", "build_timeout": 1800, "auto_cancel_pending_pipelines": "enabled",
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/job_token_scope", http.StatusOK, map[string]interface{}{"inbound_enabled": true})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/job_token_scope/allowlist", http.StatusOK, []map[string]interface{}{
+		{"id": 77, "path_with_namespace": "acme/deployer"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/job_token_scope/groups_allowlist", http.StatusOK, []interface{}{})
+
+	result := callTool(t, tc, "update_ci_settings", map[string]interface{}{
+		"project_id":              "acme/api",
+		"build_timeout":           1800,
+		"job_token_scope":         "enabled",
+		"allowlist_add_projects":  []interface{}{"acme/deployer"},
+		"allowlist_remove_groups": []interface{}{"9"},
+	})
+	if result.IsError {
+		t.Fatalf("update_ci_settings failed: %s", resultText(t, result))
+	}
+	if unmatched := client.Unmatched(); len(unmatched) > 0 {
+		t.Errorf("requests without fixtures: %v", unmatched)
+	}
+	var got CISettings
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if got.BuildTimeout != 1800 || got.JobTokenScope == nil || !got.JobTokenScope.InboundEnabled || len(got.JobTokenScope.AllowlistProjects) != 1 {
+		t.Errorf("settings = %+v", got)
+	}
+
+	bodies := map[string]string{}
+	for _, req := range client.Requests() {
+		if req.Method != http.MethodGet {
+			bodies[req.Method+" "+req.Endpoint] = strings.TrimSpace(string(req.Body))
+		}
+	}
+	want := map[string]string{
+		"PUT /projects/acme%2Fapi":                                       `{"build_timeout":1800}`,
+		"PATCH /projects/acme%2Fapi/job_token_scope":                     `{"enabled":true}`,
+		"POST /projects/acme%2Fapi/job_token_scope/allowlist":            `{"target_project_id":77}`,
+		"DELETE /projects/acme%2Fapi/job_token_scope/groups_allowlist/9": "",
+	}
+	if fmt.Sprint(bodies) != fmt.Sprint(want) {
+		t.Errorf("writes = %v, want %v", bodies, want)
+	}
+
+	result = callTool(t, tc, "update_ci_settings", map[string]interface{}{"project_id": "acme/api"})
+	if !result.IsError {
+		t.Error("expected an error without settings to update")
+	}
+}
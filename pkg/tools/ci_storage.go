@@ -0,0 +1,279 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+const (
+	// defaultStoragePipelines is how many recent pipelines per project the
+	// storage report scans by default.
+	defaultStoragePipelines = 20
+	// defaultStorageOffenders is how many offenders the report lists by default.
+	defaultStorageOffenders = 10
+)
+
+// CIStorageProject is the artifact storage of one project's scanned
+// pipelines. Job logs are counted apart from artifacts since they are kept
+// regardless of expiry. JobArtifactsSize is GitLab's total for the project,
+// including pipelines that were not scanned.
+type CIStorageProject struct {
+	Project           string `json:"project"`
+	PipelinesScanned  int    `json:"pipelines_scanned"`
+	JobsWithArtifacts int    `json:"jobs_with_artifacts"`
+	ArtifactBytes     int64  `json:"artifact_bytes"`
+	NeverExpireBytes  int64  `json:"never_expire_bytes"`
+	LogBytes          int64  `json:"log_bytes"`
+	JobArtifactsSize  int64  `json:"job_artifacts_size,omitempty"`
+	Error             string `json:"error,omitempty"`
+}
+
+// ArtifactOffender is a job name of a project and the artifacts its jobs
+// left in the scanned pipelines.
+type ArtifactOffender struct {
+	Project      string `json:"project"`
+	JobName      string `json:"job_name"`
+	Jobs         int    `json:"jobs"`
+	TotalBytes   int64  `json:"total_bytes"`
+	LargestBytes int64  `json:"largest_bytes"`
+	// NeverExpire counts the jobs whose artifacts have no expiry date
+	NeverExpire   int        `json:"never_expire"`
+	LatestJobID   int        `json:"latest_job_id"`
+	LatestExpires *time.Time `json:"latest_expires,omitempty"`
+}
+
+// CIStorageReport is the response of the ci_storage_report tool.
+type CIStorageReport struct {
+	GeneratedAt         time.Time `json:"generated_at"`
+	PipelinesPerProject int       `json:"pipelines_per_project"`
+	// ProjectsTruncated is true when the group has more than max_projects
+	// projects
+	ProjectsTruncated bool               `json:"projects_truncated,omitempty"`
+	ArtifactBytes     int64              `json:"artifact_bytes"`
+	NeverExpireBytes  int64              `json:"never_expire_bytes"`
+	Projects          []CIStorageProject `json:"projects"`
+	Offenders         []ArtifactOffender `json:"offenders"`
+}
+
+// artifactJob is a job listed with its artifacts.
+type artifactJob struct {
+	gitlab.Job
+	Artifacts []struct {
+		FileType string `json:"file_type"`
+		Size     int64  `json:"size"`
+	} `json:"artifacts"`
+	ArtifactsExpireAt *time.Time `json:"artifacts_expire_at"`
+}
+
+// projectArtifactStorage scans the recent pipelines of a project and returns
+// its storage row and its offenders by job name.
+func projectArtifactStorage(ctx context.Context, client gitlab.API, projectID string, pipelines int) (CIStorageProject, []ArtifactOffender, error) {
+	row := CIStorageProject{}
+	project := url.PathEscape(projectID)
+
+	var p struct {
+		Statistics *struct {
+			JobArtifactsSize int64 `json:"job_artifacts_size"`
+		} `json:"statistics"`
+	}
+	if err := client.Get(ctx, fmt.Sprintf("/projects/%s?statistics=true", project), &p); err != nil {
+		return row, nil, fmt.Errorf("project: %w", err)
+	}
+	if p.Statistics != nil {
+		row.JobArtifactsSize = p.Statistics.JobArtifactsSize
+	}
+
+	var recent []gitlab.Pipeline
+	if err := client.Get(ctx, fmt.Sprintf("/projects/%s/pipelines?order_by=id&sort=desc&per_page=%d", project, pipelines), &recent); err != nil {
+		return row, nil, fmt.Errorf("pipelines: %w", err)
+	}
+	byName := map[string]*ArtifactOffender{}
+	var names []string
+	for _, pipeline := range recent {
+		endpoint := fmt.Sprintf("/projects/%s/pipelines/%d/jobs?include_retried=true", project, pipeline.ID)
+		_, err := streamPages(ctx, client, endpoint, maxCollectedItems, func(job artifactJob) {
+			var size int64
+			for _, artifact := range job.Artifacts {
+				if artifact.FileType == "trace" {
+					row.LogBytes += artifact.Size
+					continue
+				}
+				size += artifact.Size
+			}
+			if size == 0 {
+				return
+			}
+			row.JobsWithArtifacts++
+			row.ArtifactBytes += size
+
+			offender, ok := byName[job.Name]
+			if !ok {
+				offender = &ArtifactOffender{Project: projectID, JobName: job.Name}
+				byName[job.Name] = offender
+				names = append(names, job.Name)
+			}
+			offender.Jobs++
+			offender.TotalBytes += size
+			offender.LargestBytes = max(offender.LargestBytes, size)
+			if job.ID > offender.LatestJobID {
+				offender.LatestJobID = job.ID
+				offender.LatestExpires = job.ArtifactsExpireAt
+			}
+			if job.ArtifactsExpireAt == nil {
+				offender.NeverExpire++
+				row.NeverExpireBytes += size
+			}
+		})
+		if err != nil {
+			return row, nil, fmt.Errorf("jobs of pipeline %d: %w", pipeline.ID, err)
+		}
+		row.PipelinesScanned++
+	}
+
+	offenders := make([]ArtifactOffender, 0, len(names))
+	for _, name := range names {
+		offenders = append(offenders, *byName[name])
+	}
+	return row, offenders, nil
+}
+
+type ciStorageReportArgs struct {
+	ProjectIDs       []string `json:"project_ids"`
+	GroupID          string   `json:"group_id"`
+	IncludeSubgroups bool     `json:"include_subgroups"`
+	Pipelines        int      `json:"pipelines" validate:"min=1,max=100"`
+	Top              int      `json:"top" validate:"min=1,max=100"`
+	MaxProjects      int      `json:"max_projects" validate:"min=1,max=100"`
+	Concurrency      int      `json:"concurrency" validate:"min=1,max=10"`
+}
+
+// registerCIStorageReport registers the ci_storage_report tool.
+func registerCIStorageReport(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "ci_storage_report",
+			Description: "Report job artifact storage for projects or a whole group by scanning their recent pipelines in parallel: artifact bytes per project, bytes whose artifacts never expire, job log bytes, and GitLab's total artifact size per project. The biggest offenders are the job names whose artifacts add up to the most bytes, with how many of their jobs keep artifacts forever, to find where expire_in is missing or too long.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_ids": {
+						Type:        "array",
+						Description: "Projects to scan, as IDs or paths (e.g., [\"acme/api\", \"42\"]). Either this or group_id is required",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"group_id": {
+						Type:        "string",
+						Description: "Scan every non-archived project of this group (ID or path)",
+					},
+					"include_subgroups": {
+						Type:        "boolean",
+						Description: "With group_id, include projects of subgroups. Default: false",
+					},
+					"pipelines": {
+						Type:        "integer",
+						Description: "Recent pipelines scanned per project (max 100). Default: 20",
+						Default:     defaultStoragePipelines,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+					"top": {
+						Type:        "integer",
+						Description: "Offenders listed (max 100). Default: 10",
+						Default:     defaultStorageOffenders,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+					"max_projects": {
+						Type:        "integer",
+						Description: "With group_id, the most projects scanned (max 100). Default: 50",
+						Default:     50,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(maxFanOutProjects),
+					},
+					"concurrency": {
+						Type:        "integer",
+						Description: "Projects scanned at once (max 10). Default: 5",
+						Default:     defaultFanOutWorkers,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(maxFanOutWorkers),
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("ci_storage_report", func(ctx context.Context, c *ToolContext, args ciStorageReportArgs) (*mcp.CallToolResult, error) {
+			pipelines := args.Pipelines
+			if pipelines == 0 {
+				pipelines = defaultStoragePipelines
+			}
+			top := args.Top
+			if top == 0 {
+				top = defaultStorageOffenders
+			}
+
+			report := CIStorageReport{
+				GeneratedAt:         time.Now().UTC(),
+				PipelinesPerProject: pipelines,
+				Projects:            []CIStorageProject{},
+				Offenders:           []ArtifactOffender{},
+			}
+			projects := args.ProjectIDs
+			switch {
+			case len(projects) > 0 && args.GroupID != "":
+				return ErrorResult("pass either project_ids or group_id, not both")
+			case len(projects) > maxFanOutProjects:
+				return ErrorResult(fmt.Sprintf("at most %d project_ids can be scanned at once", maxFanOutProjects))
+			case args.GroupID != "":
+				maxProjects := args.MaxProjects
+				if maxProjects == 0 {
+					maxProjects = 50
+				}
+				var err error
+				projects, report.ProjectsTruncated, err = groupProjectPaths(ctx, c.Client, args.GroupID, args.IncludeSubgroups, maxProjects)
+				if err != nil {
+					return APIErrorResult(fmt.Sprintf("Failed to list the projects of group %s", args.GroupID), err)
+				}
+			case len(projects) == 0:
+				return ErrorResult("project_ids or group_id is required")
+			}
+
+			type storage struct {
+				row       CIStorageProject
+				offenders []ArtifactOffender
+			}
+			results := fanOut(ctx, projects, args.Concurrency, func(ctx context.Context, projectID string) (storage, error) {
+				row, offenders, err := projectArtifactStorage(ctx, c.Client, projectID, pipelines)
+				return storage{row: row, offenders: offenders}, err
+			})
+			for _, r := range results {
+				row := r.Value.row
+				row.Project = r.ProjectID
+				if r.Err != nil {
+					row.Error = r.Err.Error()
+				} else {
+					report.Offenders = append(report.Offenders, r.Value.offenders...)
+				}
+				report.ArtifactBytes += row.ArtifactBytes
+				report.NeverExpireBytes += row.NeverExpireBytes
+				report.Projects = append(report.Projects, row)
+			}
+
+			sort.SliceStable(report.Projects, func(i, j int) bool {
+				return report.Projects[i].ArtifactBytes > report.Projects[j].ArtifactBytes
+			})
+			sort.SliceStable(report.Offenders, func(i, j int) bool {
+				return report.Offenders[i].TotalBytes > report.Offenders[j].TotalBytes
+			})
+			report.Offenders = report.Offenders[:min(len(report.Offenders), top)]
+			return JSONResult(report)
+		}),
+	)
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCIStorageReport(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi", http.StatusOK, map[string]interface{}{
+		"id": 1, "statistics": map[string]interface{}{"job_artifacts_size": 9000},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/pipelines", http.StatusOK, []map[string]interface{}{{"id": 11}, {"id": 10}})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/pipelines/11/jobs", http.StatusOK, []map[string]interface{}{
+		{"id": 111, "name": "build", "artifacts": []map[string]interface{}{{"file_type": "archive", "size": 1000}, {"file_type": "trace", "size": 50}}},
+		{"id": 112, "name": "test", "artifacts": []map[string]interface{}{{"file_type": "junit", "size": 20}}, "artifacts_expire_at": "2026-11-01T00:00:00Z"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/pipelines/10/jobs", http.StatusOK, []map[string]interface{}{
+		{"id": 101, "name": "build", "artifacts": []map[string]interface{}{{"file_type": "archive", "size": 1500}}},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fdocs", http.StatusForbidden, map[string]interface{}{"message": "403 Forbidden"})
+
+	result := callTool(t, tc, "ci_storage_report", map[string]interface{}{"project_ids": []interface{}{"acme/docs", "acme/api"}})
+	if result.IsError {
+		t.Fatalf("ci_storage_report failed: %s", resultText(t, result))
+	}
+	var got CIStorageReport
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if got.ArtifactBytes != 2520 || got.NeverExpireBytes != 2500 || len(got.Projects) != 2 {
+		t.Errorf("report = %+v", got)
+	}
+	api := got.Projects[0]
+	if api.Project != "acme/api" || api.PipelinesScanned != 2 || api.JobsWithArtifacts != 3 || api.LogBytes != 50 || api.JobArtifactsSize != 9000 {
+		t.Errorf("acme/api = %+v", api)
+	}
+	if docs := got.Projects[1]; docs.Project != "acme/docs" || docs.Error == "" {
+		t.Errorf("acme/docs = %+v, want an error", docs)
+	}
+	if len(got.Offenders) != 2 {
+		t.Fatalf("offenders = %+v", got.Offenders)
+	}
+	if build := got.Offenders[0]; build.JobName != "build" || build.Jobs != 2 || build.TotalBytes != 2500 || build.LargestBytes != 1500 || build.NeverExpire != 2 || build.LatestJobID != 111 {
+		t.Errorf("build offender = %+v", build)
+	}
+	if test := got.Offenders[1]; test.JobName != "test" || test.NeverExpire != 0 || test.LatestExpires == nil {
+		t.Errorf("test offender = %+v", test)
+	}
+}
@@ -0,0 +1,363 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// maskedVariableValue replaces the values of CI variables unless they are
+// explicitly requested.
+const maskedVariableValue = "[MASKED]"
+
+// CIVariable is a CI/CD variable definition and where it comes from. Level is
+// "instance", "group", "project" or "schedule"; Source names the group
+// path, project or schedule.
+type CIVariable struct {
+	Key              string `json:"key"`
+	Value            string `json:"value"`
+	VariableType     string `json:"variable_type,omitempty"`
+	Level            string `json:"level"`
+	Source           string `json:"source"`
+	Protected        bool   `json:"protected,omitempty"`
+	Masked           bool   `json:"masked,omitempty"`
+	EnvironmentScope string `json:"environment_scope,omitempty"`
+	// Overrides lists the lower-precedence definitions of the key this one
+	// wins over, e.g. "group acme"
+	Overrides []string `json:"overrides,omitempty"`
+	// Reason says why a definition does not apply to the ref
+	Reason string `json:"reason,omitempty"`
+}
+
+// ScheduleOverride is a pipeline schedule on the ref whose variables take
+// precedence over all other levels in the pipelines it starts.
+type ScheduleOverride struct {
+	ID          int          `json:"id"`
+	Description string       `json:"description"`
+	Active      bool         `json:"active"`
+	Variables   []CIVariable `json:"variables"`
+}
+
+// ResolvedCIVariables is the response of the resolve_ci_variables tool.
+type ResolvedCIVariables struct {
+	ProjectID    string `json:"project_id"`
+	Ref          string `json:"ref"`
+	ProtectedRef bool   `json:"protected_ref"`
+	Environment  string `json:"environment,omitempty"`
+	ValuesShown  bool   `json:"values_shown"`
+	// Variables are the effective variables of a pipeline on the ref, by key
+	Variables []CIVariable `json:"variables"`
+	// NotApplied are definitions excluded by protection or environment scope
+	NotApplied []CIVariable       `json:"not_applied"`
+	Schedules  []ScheduleOverride `json:"schedules"`
+	Notes      []string           `json:"notes,omitempty"`
+}
+
+// ciVariable is a CI/CD variable as returned by the variables APIs.
+type ciVariable struct {
+	Key              string `json:"key"`
+	Value            string `json:"value"`
+	VariableType     string `json:"variable_type"`
+	Protected        bool   `json:"protected"`
+	Masked           bool   `json:"masked"`
+	Hidden           bool   `json:"hidden"`
+	EnvironmentScope string `json:"environment_scope"`
+}
+
+// pipelineSchedule is the part of a pipeline schedule the resolver reads.
+type pipelineSchedule struct {
+	ID          int          `json:"id"`
+	Description string       `json:"description"`
+	Ref         string       `json:"ref"`
+	Active      bool         `json:"active"`
+	Variables   []ciVariable `json:"variables"`
+}
+
+// variableDefinition is a variable at one level; rank orders the levels from
+// instance (0) over the groups, root first, to the project.
+type variableDefinition struct {
+	variable ciVariable
+	level    string
+	source   string
+	rank     int
+}
+
+// environmentScopeMatches reports whether a variable's environment scope
+// applies to environment. "*" matches any environment, including none;
+// other scopes may use * as a wildcard and need an environment.
+func environmentScopeMatches(scope, environment string) bool {
+	if scope == "" || scope == "*" {
+		return true
+	}
+	if environment == "" {
+		return false
+	}
+	parts := strings.Split(scope, "*")
+	if len(parts) == 1 {
+		return scope == environment
+	}
+	if !strings.HasPrefix(environment, parts[0]) {
+		return false
+	}
+	rest := environment[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	return strings.HasSuffix(rest, parts[len(parts)-1])
+}
+
+// scopeSpecificity ranks environment scopes: an exact scope beats a
+// wildcard, which beats "*".
+func scopeSpecificity(scope string) int {
+	switch {
+	case scope == "" || scope == "*":
+		return 0
+	case strings.Contains(scope, "*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// describe names a definition in Overrides, e.g. "group acme" or
+// "project acme/api (environment_scope production)".
+func (d variableDefinition) describe() string {
+	name := d.level
+	if d.source != "" {
+		name += " " + d.source
+	}
+	if scope := d.variable.EnvironmentScope; scope != "" && scope != "*" {
+		name += fmt.Sprintf(" (environment_scope %s)", scope)
+	}
+	return name
+}
+
+// resolveVariables picks the effective definition of every key: the highest
+// level wins, and within a level the most specific environment scope.
+// Definitions that cannot apply to the ref are returned as not applied.
+func resolveVariables(definitions []variableDefinition, protectedRef bool, environment string, showValues bool) (effective, notApplied []CIVariable) {
+	byKey := map[string][]variableDefinition{}
+	var keys []string
+	for _, d := range definitions {
+		if _, ok := byKey[d.variable.Key]; !ok {
+			keys = append(keys, d.variable.Key)
+		}
+		byKey[d.variable.Key] = append(byKey[d.variable.Key], d)
+	}
+	sort.Strings(keys)
+
+	effective, notApplied = []CIVariable{}, []CIVariable{}
+	for _, key := range keys {
+		var applicable []variableDefinition
+		for _, d := range byKey[key] {
+			switch {
+			case d.variable.Protected && !protectedRef:
+				v := d.output(showValues)
+				v.Reason = "protected variable; the ref is not protected"
+				notApplied = append(notApplied, v)
+			case !environmentScopeMatches(d.variable.EnvironmentScope, environment):
+				v := d.output(showValues)
+				v.Reason = "environment_scope does not match"
+				if environment == "" {
+					v.Reason = "environment_scope needs an environment"
+				}
+				notApplied = append(notApplied, v)
+			default:
+				applicable = append(applicable, d)
+			}
+		}
+		if len(applicable) == 0 {
+			continue
+		}
+		sort.SliceStable(applicable, func(i, j int) bool {
+			if applicable[i].rank != applicable[j].rank {
+				return applicable[i].rank > applicable[j].rank
+			}
+			return scopeSpecificity(applicable[i].variable.EnvironmentScope) > scopeSpecificity(applicable[j].variable.EnvironmentScope)
+		})
+		winner := applicable[0].output(showValues)
+		for _, d := range applicable[1:] {
+			winner.Overrides = append(winner.Overrides, d.describe())
+		}
+		effective = append(effective, winner)
+	}
+	return effective, notApplied
+}
+
+// output converts a definition to its response form, masking the value
+// unless showValues is set and GitLab does not mask or hide it.
+func (d variableDefinition) output(showValues bool) CIVariable {
+	value := d.variable.Value
+	if !showValues || d.variable.Masked || d.variable.Hidden {
+		value = maskedVariableValue
+	}
+	return CIVariable{
+		Key:              d.variable.Key,
+		Value:            value,
+		VariableType:     d.variable.VariableType,
+		Level:            d.level,
+		Source:           d.source,
+		Protected:        d.variable.Protected,
+		Masked:           d.variable.Masked || d.variable.Hidden,
+		EnvironmentScope: d.variable.EnvironmentScope,
+	}
+}
+
+// refIsProtected reports whether ref is a protected branch or tag.
+func refIsProtected(ctx context.Context, client gitlab.API, project, ref string) (bool, error) {
+	var branch gitlab.Branch
+	err := client.Get(ctx, fmt.Sprintf("/projects/%s/repository/branches/%s", project, url.PathEscape(ref)), &branch)
+	if err == nil {
+		return branch.Protected, nil
+	}
+	if !gitlab.IsNotFound(err) {
+		return false, err
+	}
+	var tag gitlab.Tag
+	if err := client.Get(ctx, fmt.Sprintf("/projects/%s/repository/tags/%s", project, url.PathEscape(ref)), &tag); err != nil {
+		return false, err
+	}
+	return tag.Protected, nil
+}
+
+type resolveCIVariablesArgs struct {
+	ProjectID   string `json:"project_id" validate:"required"`
+	Ref         string `json:"ref"`
+	Environment string `json:"environment"`
+	ShowValues  bool   `json:"show_values"`
+}
+
+// registerResolveCIVariables registers the resolve_ci_variables tool.
+func registerResolveCIVariables(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "resolve_ci_variables",
+			Description: "Resolve the effective CI/CD variables of a pipeline on a project ref: instance, group (root to closest subgroup) and project variables are merged by precedence, protected variables are dropped on unprotected refs and environment-scoped ones applied for the given environment. Each variable names its level and the definitions it overrides; pipeline schedules on the ref are listed with their variable overrides. Values are masked unless show_values=true, and variables masked or hidden in GitLab always stay masked. Instance and group levels are skipped with a note when the token cannot read them.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Branch or tag the pipeline runs on. Default: the default branch",
+					},
+					"environment": {
+						Type:        "string",
+						Description: "Environment the job deploys to (e.g., production), for environment-scoped variables",
+					},
+					"show_values": {
+						Type:        "boolean",
+						Description: "Return the values of variables not masked in GitLab (default: false)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("resolve_ci_variables", func(ctx context.Context, c *ToolContext, args resolveCIVariablesArgs) (*mcp.CallToolResult, error) {
+			project := url.PathEscape(args.ProjectID)
+			var p gitlab.Project
+			if err := c.Client.Get(ctx, "/projects/"+project, &p); err != nil {
+				return APIErrorResult("Failed to get project", err)
+			}
+			ref := args.Ref
+			if ref == "" {
+				ref = p.DefaultBranch
+			}
+
+			result := ResolvedCIVariables{
+				ProjectID:   args.ProjectID,
+				Ref:         ref,
+				Environment: args.Environment,
+				ValuesShown: args.ShowValues,
+				Schedules:   []ScheduleOverride{},
+			}
+			protected, err := refIsProtected(ctx, c.Client, project, ref)
+			if err != nil {
+				return APIErrorResult(fmt.Sprintf("Failed to get ref %s", ref), err)
+			}
+			result.ProtectedRef = protected
+
+			// Levels in ascending precedence; a level the token cannot read
+			// is noted and skipped
+			type level struct {
+				name, source, endpoint string
+			}
+			levels := []level{{name: "instance", endpoint: "/admin/ci/variables"}}
+			if p.Namespace != nil && p.Namespace.Kind == "group" {
+				segments := strings.Split(p.Namespace.FullPath, "/")
+				for i := range segments {
+					group := strings.Join(segments[:i+1], "/")
+					levels = append(levels, level{name: "group", source: group, endpoint: fmt.Sprintf("/groups/%s/variables", url.PathEscape(group))})
+				}
+			}
+			levels = append(levels, level{name: "project", source: p.PathWithNamespace, endpoint: fmt.Sprintf("/projects/%s/variables", project)})
+
+			var definitions []variableDefinition
+			for rank, l := range levels {
+				variables, complete, err := collectPages[ciVariable](ctx, c.Client, l.endpoint, maxCollectedItems)
+				if err != nil {
+					if gitlab.IsForbidden(err) || gitlab.IsUnauthorized(err) || gitlab.IsNotFound(err) {
+						name := strings.TrimSpace(l.name + " " + l.source)
+						result.Notes = append(result.Notes, fmt.Sprintf("%s variables are not readable with this token and were skipped", name))
+						continue
+					}
+					return APIErrorResult(fmt.Sprintf("Failed to list %s variables", l.name), err)
+				}
+				if !complete {
+					result.Notes = append(result.Notes, fmt.Sprintf("only the first %d %s variables were read", maxCollectedItems, l.name))
+				}
+				for _, v := range variables {
+					definitions = append(definitions, variableDefinition{variable: v, level: l.name, source: l.source, rank: rank})
+				}
+			}
+			result.Variables, result.NotApplied = resolveVariables(definitions, protected, args.Environment, args.ShowValues)
+
+			effective := map[string]CIVariable{}
+			for _, v := range result.Variables {
+				effective[v.Key] = v
+			}
+			schedules, _, err := collectPages[pipelineSchedule](ctx, c.Client, fmt.Sprintf("/projects/%s/pipeline_schedules", project), maxCollectedItems)
+			if err != nil {
+				if !gitlab.IsForbidden(err) {
+					return APIErrorResult("Failed to list pipeline schedules", err)
+				}
+				result.Notes = append(result.Notes, "pipeline schedules are not readable with this token and were skipped")
+			}
+			for _, s := range schedules {
+				if strings.TrimPrefix(strings.TrimPrefix(s.Ref, "refs/heads/"), "refs/tags/") != ref {
+					continue
+				}
+				// The list omits schedule variables
+				var detail pipelineSchedule
+				if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s/pipeline_schedules/%d", project, s.ID), &detail); err != nil {
+					return APIErrorResult(fmt.Sprintf("Failed to get pipeline schedule %d", s.ID), err)
+				}
+				override := ScheduleOverride{ID: s.ID, Description: s.Description, Active: s.Active, Variables: []CIVariable{}}
+				for _, v := range detail.Variables {
+					out := variableDefinition{variable: v, level: "schedule", source: fmt.Sprintf("#%d", s.ID)}.output(args.ShowValues)
+					if shadowed, ok := effective[v.Key]; ok {
+						out.Overrides = []string{strings.TrimSpace(shadowed.Level + " " + shadowed.Source)}
+					}
+					override.Variables = append(override.Variables, out)
+				}
+				result.Schedules = append(result.Schedules, override)
+			}
+			return JSONResult(result)
+		}),
+	)
+}
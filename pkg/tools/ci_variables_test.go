@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResolveCIVariables(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.UsePipeline = true
+	client.Handle(http.MethodGet, "/projects/acme%2Fplatform%2Fapi", http.StatusOK, map[string]interface{}{
+		"id": 42, "path_with_namespace": "acme/platform/api", "default_branch": "main",
+		"namespace": map[string]interface{}{"kind": "group", "full_path": "acme/platform"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fplatform%2Fapi/repository/branches/main", http.StatusOK, map[string]interface{}{
+		"name": "main", "protected": false,
+	})
+	client.Handle(http.MethodGet, "/admin/ci/variables", http.StatusForbidden, map[string]interface{}{"message": "403 Forbidden"})
+	client.Handle(http.MethodGet, "/groups/acme/variables", http.StatusOK, []map[string]interface{}{
+		{"key": "LOG_LEVEL", "value": "info", "environment_scope": "*"},
+		{"key": "DEPLOY_TOKEN", "value": "s3cret", "protected": true, "environment_scope": "*"},
+	})
+	client.Handle(http.MethodGet, "/groups/acme%2Fplatform/variables", http.StatusOK, []map[string]interface{}{
+		{"key": "LOG_LEVEL", "value": "warn", "environment_scope": "*"},
+		{"key": "API_URL", "value": "https://prod.example.com", "environment_scope": "production"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fplatform%2Fapi/variables", http.StatusOK, []map[string]interface{}{
+		{"key": "API_URL", "value": "https://review.example.com", "environment_scope": "review/*"},
+		{"key": "SIGNING_KEY", "value": "abc", "masked": true, "environment_scope": "*"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fplatform%2Fapi/pipeline_schedules", http.StatusOK, []map[string]interface{}{
+		{"id": 3, "description": "Nightly", "ref": "refs/heads/main", "active": true},
+		{"id": 4, "description": "Release", "ref": "release", "active": true},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fplatform%2Fapi/pipeline_schedules/3", http.StatusOK, map[string]interface{}{
+		"id": 3, "variables": []map[string]interface{}{{"key": "LOG_LEVEL", "value": "debug"}},
+	})
+
+	result := callTool(t, tc, "resolve_ci_variables", map[string]interface{}{
+		"project_id":  "acme/platform/api",
+		"environment": "review/feature-x",
+		"show_values": true,
+	})
+	if result.IsError {
+		t.Fatalf("resolve_ci_variables failed: %s", resultText(t, result))
+	}
+	if unmatched := client.Unmatched(); len(unmatched) > 0 {
+		t.Errorf("requests without fixtures: %v", unmatched)
+	}
+	var got ResolvedCIVariables
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if got.Ref != "main" || got.ProtectedRef {
+		t.Errorf("ref = %s (protected %v), want unprotected main", got.Ref, got.ProtectedRef)
+	}
+	effective := map[string]CIVariable{}
+	for _, v := range got.Variables {
+		effective[v.Key] = v
+	}
+	if v := effective["LOG_LEVEL"]; v.Value != "warn" || v.Source != "acme/platform" || len(v.Overrides) != 1 || v.Overrides[0] != "group acme" {
+		t.Errorf("LOG_LEVEL = %+v, want acme/platform overriding group acme", v)
+	}
+	if v := effective["API_URL"]; v.Value != "https://review.example.com" || v.Level != "project" {
+		t.Errorf("API_URL = %+v, want the project's review/* value", v)
+	}
+	if v := effective["SIGNING_KEY"]; v.Value != maskedVariableValue || !v.Masked {
+		t.Errorf("SIGNING_KEY = %+v, want a masked value", v)
+	}
+	if _, ok := effective["DEPLOY_TOKEN"]; ok || len(got.NotApplied) != 2 {
+		t.Errorf("not applied = %+v, want DEPLOY_TOKEN (protected) and the production API_URL", got.NotApplied)
+	}
+	if len(got.Schedules) != 1 || got.Schedules[0].ID != 3 || got.Schedules[0].Variables[0].Overrides[0] != "group acme/platform" {
+		t.Errorf("schedules = %+v", got.Schedules)
+	}
+	if len(got.Notes) != 1 || !strings.Contains(got.Notes[0], "instance") {
+		t.Errorf("notes = %v, want the skipped instance level", got.Notes)
+	}
+
+	result = callTool(t, tc, "resolve_ci_variables", map[string]interface{}{"project_id": "acme/platform/api"})
+	if text := resultText(t, result); strings.Contains(text, "warn") {
+		t.Errorf("values shown without show_values: %s", text)
+	}
+}
+
+func TestEnvironmentScopeMatches(t *testing.T) {
+	tests := []struct {
+		scope, environment string
+		want               bool
+	}{
+		{"*", "", true},
+		{"*", "production", true},
+		{"production", "production", true},
+		{"production", "staging", false},
+		{"production", "", false},
+		{"review/*", "review/feature-x", true},
+		{"review/*", "production", false},
+		{"*-eu", "prod-eu", true},
+		{"*-eu", "prod-us", false},
+	}
+	for _, tt := range tests {
+		if got := environmentScopeMatches(tt.scope, tt.environment); got != tt.want {
+			t.Errorf("environmentScopeMatches(%q, %q) = %v, want %v", tt.scope, tt.environment, got, tt.want)
+		}
+	}
+}
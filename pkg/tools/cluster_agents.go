@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// ClusterAgent is a GitLab agent for Kubernetes registered to a project.
+type ClusterAgent struct {
+	ID              int        `json:"id"`
+	Name            string     `json:"name"`
+	CreatedAt       *time.Time `json:"created_at"`
+	CreatedByUserID int        `json:"created_by_user_id"`
+	ConfigProject   struct {
+		ID                int    `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"config_project"`
+}
+
+// AgentToken is a token of a GitLab agent for Kubernetes. Token is only set
+// in the response to its creation.
+type AgentToken struct {
+	ID              int        `json:"id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description,omitempty"`
+	AgentID         int        `json:"agent_id"`
+	Status          string     `json:"status"`
+	CreatedAt       *time.Time `json:"created_at"`
+	CreatedByUserID int        `json:"created_by_user_id"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	Token           string     `json:"token,omitempty"`
+}
+
+// AgentAccess lists the projects and groups an agent's config grants access
+// to, through ci_access (CI/CD jobs) or user_access (users).
+type AgentAccess struct {
+	Projects []string `json:"projects,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+// AgentConfig is the response of the get_cluster_agent_config tool. Without
+// a config file, the agent runs with GitLab's defaults and grants no access.
+type AgentConfig struct {
+	Agent      ClusterAgent `json:"agent"`
+	Path       string       `json:"path"`
+	Ref        string       `json:"ref"`
+	Exists     bool         `json:"exists"`
+	Content    string       `json:"content,omitempty"`
+	YAMLError  string       `json:"yaml_error,omitempty"`
+	CIAccess   *AgentAccess `json:"ci_access,omitempty"`
+	UserAccess *AgentAccess `json:"user_access,omitempty"`
+}
+
+// agentAccessConfig is the ci_access or user_access section of an agent's
+// config file.
+type agentAccessConfig struct {
+	Projects []struct {
+		ID string `yaml:"id"`
+	} `yaml:"projects"`
+	Groups []struct {
+		ID string `yaml:"id"`
+	} `yaml:"groups"`
+}
+
+// access returns the projects and groups of the section, or nil when it is
+// not in the config.
+func (a *agentAccessConfig) access() *AgentAccess {
+	if a == nil {
+		return nil
+	}
+	access := &AgentAccess{}
+	for _, project := range a.Projects {
+		access.Projects = append(access.Projects, project.ID)
+	}
+	for _, group := range a.Groups {
+		access.Groups = append(access.Groups, group.ID)
+	}
+	return access
+}
+
+type getClusterAgentConfigArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	AgentID   int    `json:"agent_id" validate:"required,min=1"`
+	Ref       string `json:"ref"`
+}
+
+// registerGetClusterAgentConfig registers the get_cluster_agent_config tool.
+func registerGetClusterAgentConfig(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_cluster_agent_config",
+			Description: "Get the configuration file of a GitLab agent for Kubernetes, .gitlab/agents/<agent name>/config.yaml in its config project, along with the projects and groups it grants access to through ci_access and user_access. Reports a YAML error if the file does not parse, and exists: false if the agent has no config file and runs with GitLab's defaults.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"agent_id": {
+						Type:        "integer",
+						Description: "The ID of the agent, from list_cluster_agents",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Branch, tag or commit to read the file at. Default: the config project's default branch, which the agent reads",
+					},
+				},
+				Required: []string{"project_id", "agent_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_cluster_agent_config", func(ctx context.Context, c *ToolContext, args getClusterAgentConfigArgs) (*mcp.CallToolResult, error) {
+			var agent ClusterAgent
+			if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s/cluster_agents/%d", url.PathEscape(args.ProjectID), args.AgentID), &agent); err != nil {
+				return APIErrorResult("Failed to get cluster agent", err)
+			}
+			configProject := url.PathEscape(agent.ConfigProject.PathWithNamespace)
+			if agent.ConfigProject.ID != 0 {
+				configProject = fmt.Sprint(agent.ConfigProject.ID)
+			}
+			ref := args.Ref
+			if ref == "" {
+				var project gitlab.Project
+				if err := c.Client.Get(ctx, "/projects/"+configProject, &project); err != nil {
+					return APIErrorResult("Failed to get config project", err)
+				}
+				ref = project.DefaultBranch
+			}
+
+			result := AgentConfig{Agent: agent, Path: fmt.Sprintf(".gitlab/agents/%s/config.yaml", agent.Name), Ref: ref}
+			content, err := c.Client.GetText(ctx, fmt.Sprintf("/projects/%s/repository/files/%s/raw?ref=%s", configProject, url.PathEscape(result.Path), url.QueryEscape(ref)))
+			if gitlab.IsNotFound(err) {
+				return JSONResult(result)
+			}
+			if err != nil {
+				return APIErrorResult("Failed to get "+result.Path, err)
+			}
+			result.Exists, result.Content = true, content
+
+			var config struct {
+				CIAccess   *agentAccessConfig `yaml:"ci_access"`
+				UserAccess *agentAccessConfig `yaml:"user_access"`
+			}
+			if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+				result.YAMLError = err.Error()
+				return JSONResult(result)
+			}
+			result.CIAccess = config.CIAccess.access()
+			result.UserAccess = config.UserAccess.access()
+			return JSONResult(result)
+		}),
+	)
+}
+
+// initKubernetesTools registers the GitLab agent for Kubernetes tools.
+func initKubernetesTools(server *mcp.Server) {
+	registerListClusterAgents(server)
+	registerGetClusterAgentConfig(server)
+	registerGetAgentTokens(server)
+	registerCreateAgentToken(server)
+	registerRevokeAgentToken(server)
+}
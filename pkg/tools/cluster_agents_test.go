@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAgentTokenWritesReadOnlyMode(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.ReadOnlyMode = true
+	for _, call := range []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"create_agent_token", map[string]interface{}{"project_id": "42", "agent_id": 3, "name": "ci"}},
+		{"revoke_agent_token", map[string]interface{}{"project_id": "42", "agent_id": 3, "token_id": 9}},
+	} {
+		result := callTool(t, tc, call.name, call.args)
+		if !result.IsError || !strings.Contains(resultText(t, result), "server is in read-only mode") {
+			t.Errorf("%s in read-only mode = %q, want a read-only error", call.name, resultText(t, result))
+		}
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("read-only mode sent %d requests, want none", len(requests))
+	}
+}
+
+func TestGetClusterAgentConfig(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/acme%2Finfra/cluster_agents/3", 200, `{"id": 3, "name": "prod", "config_project": {"id": 42, "path_with_namespace": "acme/infra"}}`)
+	client.Handle("GET", "/projects/42", 200, `{"id": 42, "default_branch": "main"}`)
+	client.Handle("GET", "/projects/42/repository/files/.gitlab%2Fagents%2Fprod%2Fconfig.yaml/raw", 200, "ci_access:\n  projects:\n    - id: acme/api\n  groups:\n    - id: acme/services\n")
+
+	result := callTool(t, tc, "get_cluster_agent_config", map[string]interface{}{"project_id": "acme/infra", "agent_id": 3})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	var config AgentConfig
+	if err := json.Unmarshal([]byte(resultText(t, result)), &config); err != nil {
+		t.Fatal(err)
+	}
+	if !config.Exists || config.Path != ".gitlab/agents/prod/config.yaml" || config.Ref != "main" || config.UserAccess != nil {
+		t.Errorf("config = %+v", config)
+	}
+	if want := (&AgentAccess{Projects: []string{"acme/api"}, Groups: []string{"acme/services"}}); !reflect.DeepEqual(config.CIAccess, want) {
+		t.Errorf("ci_access = %+v, want %+v", config.CIAccess, want)
+	}
+
+	// An agent without a config file runs with the defaults
+	tc, client = newTestContext(t)
+	client.Handle("GET", "/projects/acme%2Finfra/cluster_agents/3", 200, `{"id": 3, "name": "prod", "config_project": {"id": 42, "path_with_namespace": "acme/infra"}}`)
+	client.Handle("GET", "/projects/42/repository/files/.gitlab%2Fagents%2Fprod%2Fconfig.yaml/raw", 404, `{"message": "404 File Not Found"}`)
+	result = callTool(t, tc, "get_cluster_agent_config", map[string]interface{}{"project_id": "acme/infra", "agent_id": 3, "ref": "staging"})
+	config = AgentConfig{}
+	if err := json.Unmarshal([]byte(resultText(t, result)), &config); err != nil || result.IsError {
+		t.Fatalf("unexpected result: %s", resultText(t, result))
+	}
+	if config.Exists || config.Ref != "staging" || config.Content != "" {
+		t.Errorf("config = %+v", config)
+	}
+}
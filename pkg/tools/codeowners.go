@@ -0,0 +1,474 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// codeOwnersPaths are the locations GitLab reads CODEOWNERS from, in order.
+var codeOwnersPaths = []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// defaultMaxReviewers is how many reviewers suggest_reviewers assigns by
+// default.
+const defaultMaxReviewers = 3
+
+// codeOwnersSectionHeader matches a section header such as
+// "^[Docs][2] @docs-team": optional marker, name, approvals, default owners.
+var codeOwnersSectionHeader = regexp.MustCompile(`^(\^)?\[([^\]]+)\](?:\[(\d+)\])?\s*(.*)$`)
+
+// codeOwnersRule is one pattern line of a CODEOWNERS file.
+type codeOwnersRule struct {
+	pattern string
+	match   *regexp.Regexp
+	owners  []string
+}
+
+// codeOwnersSection is a section of a CODEOWNERS file; rules before the first
+// header belong to the unnamed default section.
+type codeOwnersSection struct {
+	name      string
+	optional  bool
+	approvals int
+	rules     []codeOwnersRule
+}
+
+// parseCodeOwners parses a GitLab CODEOWNERS file. Sections with the same
+// name are merged, as GitLab does. Lines whose pattern cannot be compiled are
+// skipped.
+func parseCodeOwners(content string) []*codeOwnersSection {
+	current := &codeOwnersSection{}
+	sections := []*codeOwnersSection{current}
+	byName := map[string]*codeOwnersSection{"": current}
+	var defaultOwners []string
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := codeOwnersSectionHeader.FindStringSubmatch(line); m != nil {
+			key := strings.ToLower(m[2])
+			section, ok := byName[key]
+			if !ok {
+				section = &codeOwnersSection{name: m[2]}
+				byName[key] = section
+				sections = append(sections, section)
+			}
+			section.optional = m[1] == "^"
+			if m[3] != "" {
+				section.approvals, _ = strconv.Atoi(m[3])
+			}
+			current = section
+			defaultOwners = strings.Fields(m[4])
+			continue
+		}
+
+		fields := splitCodeOwnersLine(line)
+		owners := fields[1:]
+		if len(owners) == 0 {
+			owners = defaultOwners
+		}
+		match, err := codeOwnersRegexp(fields[0])
+		if err != nil {
+			continue
+		}
+		current.rules = append(current.rules, codeOwnersRule{pattern: fields[0], match: match, owners: owners})
+	}
+	return sections
+}
+
+// splitCodeOwnersLine splits a rule line at unescaped whitespace, unescaping
+// "\ " and "\#" in the pattern.
+func splitCodeOwnersLine(line string) []string {
+	var fields []string
+	var sb strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			i++
+			sb.WriteByte(line[i])
+		case line[i] == ' ' || line[i] == '\t':
+			if sb.Len() > 0 {
+				fields = append(fields, sb.String())
+				sb.Reset()
+			}
+		default:
+			sb.WriteByte(line[i])
+		}
+	}
+	if sb.Len() > 0 {
+		fields = append(fields, sb.String())
+	}
+	return fields
+}
+
+// codeOwnersRegexp compiles a CODEOWNERS pattern. Patterns without a leading
+// slash match in any directory, * and ? stay within a path segment, ** spans
+// segments, and a pattern matching a directory matches everything under it.
+func codeOwnersRegexp(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/")
+	p := strings.TrimSuffix(strings.TrimPrefix(pattern, "/"), "/")
+	if p == "" {
+		return regexp.Compile(".*")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case p[i] == '*':
+			sb.WriteString("[^/]*")
+		case p[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	if strings.HasSuffix(pattern, "/") {
+		sb.WriteString("/.*$")
+	} else {
+		sb.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(sb.String())
+}
+
+// owners returns the rule of the section that applies to path, the last
+// matching one, or nil.
+func (s *codeOwnersSection) owners(path string) *codeOwnersRule {
+	for i := len(s.rules) - 1; i >= 0; i-- {
+		if s.rules[i].match.MatchString(path) {
+			return &s.rules[i]
+		}
+	}
+	return nil
+}
+
+// CodeOwnersMatch is a CODEOWNERS rule that applies to changed files.
+type CodeOwnersMatch struct {
+	Section           string   `json:"section,omitempty"`
+	Optional          bool     `json:"optional,omitempty"`
+	ApprovalsRequired int      `json:"approvals_required,omitempty"`
+	Pattern           string   `json:"pattern"`
+	Owners            []string `json:"owners"`
+	Files             []string `json:"files"`
+}
+
+// ReviewerCandidate is a code owner of files changed by a merge request.
+type ReviewerCandidate struct {
+	Owner string `json:"owner"`
+	// Kind is "user", "group", "email" or "role"; "@name" owners are "user"
+	// unless resolving them found no such user
+	Kind     string   `json:"kind"`
+	Files    int      `json:"files"`
+	Sections []string `json:"sections,omitempty"`
+	// Required is true when the owner is named in a section that is not
+	// optional
+	Required        bool `json:"required"`
+	AlreadyReviewer bool `json:"already_reviewer,omitempty"`
+	Assigned        bool `json:"assigned,omitempty"`
+}
+
+// ReviewerSuggestion is the response of the suggest_reviewers tool.
+type ReviewerSuggestion struct {
+	MergeRequestIID int                 `json:"merge_request_iid"`
+	CodeOwnersPath  string              `json:"codeowners_path"`
+	Ref             string              `json:"ref"`
+	ChangedFiles    int                 `json:"changed_files"`
+	FilesComplete   bool                `json:"files_complete"`
+	UnownedFiles    []string            `json:"unowned_files,omitempty"`
+	Rules           []CodeOwnersMatch   `json:"rules"`
+	Candidates      []ReviewerCandidate `json:"candidates"`
+	Assigned        []string            `json:"assigned,omitempty"`
+}
+
+type suggestReviewersArgs struct {
+	ProjectID       string `json:"project_id" validate:"required"`
+	MergeRequestIID int    `json:"merge_request_iid" validate:"required"`
+	Ref             string `json:"ref"`
+	Assign          bool   `json:"assign"`
+	MaxReviewers    int    `json:"max_reviewers" validate:"min=1,max=10"`
+}
+
+// registerSuggestReviewers registers the suggest_reviewers tool.
+func registerSuggestReviewers(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "suggest_reviewers",
+			Description: "Suggest reviewers for a merge request from CODEOWNERS: the file (CODEOWNERS, docs/CODEOWNERS or .gitlab/CODEOWNERS on the target branch) is matched against the changed paths with GitLab's rules (sections, last match wins, ^optional sections), and the owners are ranked by the number of files they own. The MR author is excluded. With assign=true the top user candidates are added as reviewers.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"merge_request_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the merge request",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "The branch to read CODEOWNERS from (optional, default: the MR's target branch)",
+					},
+					"assign": {
+						Type:        "boolean",
+						Description: "Add the top user candidates as reviewers, keeping existing reviewers (optional, default: false)",
+					},
+					"max_reviewers": {
+						Type:        "integer",
+						Description: "With assign, the most reviewers added (max 10). Default: 3",
+						Default:     defaultMaxReviewers,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(10),
+					},
+				},
+				Required: []string{"project_id", "merge_request_iid"},
+			},
+		},
+		withArgs("suggest_reviewers", func(ctx context.Context, c *ToolContext, args suggestReviewersArgs) (*mcp.CallToolResult, error) {
+			if args.Assign && c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot assign reviewers: server is in read-only mode")
+			}
+
+			encodedProjectID := url.PathEscape(args.ProjectID)
+			mrEndpoint := fmt.Sprintf("/projects/%s/merge_requests/%d", encodedProjectID, args.MergeRequestIID)
+
+			var mr gitlab.MergeRequest
+			if err := c.Client.Get(ctx, mrEndpoint, &mr); err != nil {
+				return APIErrorResult("Failed to get merge request", err)
+			}
+			ref := args.Ref
+			if ref == "" {
+				ref = mr.TargetBranch
+			}
+
+			var content, codeOwnersPath string
+			for _, candidate := range codeOwnersPaths {
+				text, err := c.Client.GetText(ctx, fmt.Sprintf("/projects/%s/repository/files/%s/raw?ref=%s", encodedProjectID, url.PathEscape(candidate), url.QueryEscape(ref)))
+				if gitlab.IsNotFound(err) {
+					continue
+				}
+				if err != nil {
+					return APIErrorResult("Failed to get "+candidate, err)
+				}
+				content, codeOwnersPath = text, candidate
+				break
+			}
+			if codeOwnersPath == "" {
+				return ErrorResult(fmt.Sprintf("no CODEOWNERS file in %s on %s", strings.Join(codeOwnersPaths, ", "), ref))
+			}
+
+			var paths []string
+			seen := map[string]bool{}
+			complete, err := streamPages(ctx, c.Client, mrEndpoint+"/diffs", maxCollectedItems, func(diff gitlab.Diff) {
+				for _, p := range []string{diff.OldPath, diff.NewPath} {
+					if p != "" && !seen[p] {
+						seen[p] = true
+						paths = append(paths, p)
+					}
+				}
+			})
+			if err != nil {
+				return APIErrorResult("Failed to list merge request diffs", err)
+			}
+
+			suggestion := suggestReviewers(parseCodeOwners(content), paths, mr)
+			suggestion.MergeRequestIID = args.MergeRequestIID
+			suggestion.CodeOwnersPath = codeOwnersPath
+			suggestion.Ref = ref
+			suggestion.FilesComplete = complete
+
+			if args.Assign {
+				maxReviewers := args.MaxReviewers
+				if maxReviewers == 0 {
+					maxReviewers = defaultMaxReviewers
+				}
+				if err := assignCodeOwners(ctx, c, mrEndpoint, mr, &suggestion, maxReviewers); err != nil {
+					return APIErrorResult("Failed to assign reviewers", err)
+				}
+			}
+			return JSONResult(suggestion)
+		}),
+	)
+}
+
+// suggestReviewers matches the changed paths of mr against the CODEOWNERS
+// sections and ranks the owners: owners of required sections first, then by
+// files owned.
+func suggestReviewers(sections []*codeOwnersSection, paths []string, mr gitlab.MergeRequest) ReviewerSuggestion {
+	suggestion := ReviewerSuggestion{ChangedFiles: len(paths), Rules: []CodeOwnersMatch{}, Candidates: []ReviewerCandidate{}}
+	author := ""
+	if mr.Author != nil {
+		author = "@" + strings.ToLower(mr.Author.Username)
+	}
+	reviewers := map[string]bool{}
+	for _, reviewer := range mr.Reviewers {
+		reviewers["@"+strings.ToLower(reviewer.Username)] = true
+	}
+
+	candidates := map[string]*ReviewerCandidate{}
+	files := map[string]map[string]bool{}
+	for _, section := range sections {
+		matches := map[*codeOwnersRule]*CodeOwnersMatch{}
+		var order []*codeOwnersRule
+		for _, p := range paths {
+			rule := section.owners(p)
+			if rule == nil {
+				continue
+			}
+			if matches[rule] == nil {
+				matches[rule] = &CodeOwnersMatch{
+					Section:           section.name,
+					Optional:          section.optional,
+					ApprovalsRequired: section.approvals,
+					Pattern:           rule.pattern,
+					Owners:            rule.owners,
+				}
+				order = append(order, rule)
+			}
+			matches[rule].Files = append(matches[rule].Files, p)
+
+			for _, owner := range rule.owners {
+				key := strings.ToLower(owner)
+				if key == author {
+					continue
+				}
+				candidate := candidates[key]
+				if candidate == nil {
+					candidate = &ReviewerCandidate{Owner: owner, Kind: codeOwnerKind(owner), AlreadyReviewer: reviewers[key]}
+					candidates[key] = candidate
+					files[key] = map[string]bool{}
+				}
+				files[key][p] = true
+				if !section.optional {
+					candidate.Required = true
+				}
+				if section.name != "" && !containsString(candidate.Sections, section.name) {
+					candidate.Sections = append(candidate.Sections, section.name)
+				}
+			}
+		}
+		for _, rule := range order {
+			suggestion.Rules = append(suggestion.Rules, *matches[rule])
+		}
+	}
+
+	for _, p := range paths {
+		owned := false
+		for _, section := range sections {
+			if section.owners(p) != nil {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			suggestion.UnownedFiles = append(suggestion.UnownedFiles, p)
+		}
+	}
+
+	for key, candidate := range candidates {
+		candidate.Files = len(files[key])
+		suggestion.Candidates = append(suggestion.Candidates, *candidate)
+	}
+	sort.Slice(suggestion.Candidates, func(i, j int) bool {
+		a, b := suggestion.Candidates[i], suggestion.Candidates[j]
+		if a.Required != b.Required {
+			return a.Required
+		}
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return a.Owner < b.Owner
+	})
+	return suggestion
+}
+
+// codeOwnerKind classifies a CODEOWNERS owner entry.
+func codeOwnerKind(owner string) string {
+	switch {
+	case strings.HasPrefix(owner, "@@"):
+		return "role"
+	case strings.HasPrefix(owner, "@") && strings.Contains(owner, "/"):
+		return "group"
+	case strings.HasPrefix(owner, "@"):
+		return "user"
+	default:
+		return "email"
+	}
+}
+
+// containsString reports whether values contains value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// assignCodeOwners resolves the top user and email candidates to users and
+// adds up to maxReviewers of them to the reviewers of mr. Candidates that do
+// not resolve to a user are marked as groups.
+func assignCodeOwners(ctx context.Context, c *ToolContext, mrEndpoint string, mr gitlab.MergeRequest, suggestion *ReviewerSuggestion, maxReviewers int) error {
+	reviewerIDs := make([]int, 0, len(mr.Reviewers)+maxReviewers)
+	for _, reviewer := range mr.Reviewers {
+		reviewerIDs = append(reviewerIDs, reviewer.ID)
+	}
+
+	added := 0
+	for i := range suggestion.Candidates {
+		if added == maxReviewers {
+			break
+		}
+		candidate := &suggestion.Candidates[i]
+		if candidate.AlreadyReviewer || (candidate.Kind != "user" && candidate.Kind != "email") {
+			continue
+		}
+		params := url.Values{}
+		if candidate.Kind == "user" {
+			params.Set("username", strings.TrimPrefix(candidate.Owner, "@"))
+		} else {
+			params.Set("search", candidate.Owner)
+		}
+		var users []gitlab.User
+		if err := c.Client.Get(ctx, "/users?"+params.Encode(), &users); err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			if candidate.Kind == "user" {
+				// A top-level group named like a user
+				candidate.Kind = "group"
+			}
+			continue
+		}
+		if mr.Author != nil && users[0].ID == mr.Author.ID {
+			continue
+		}
+		reviewerIDs = append(reviewerIDs, users[0].ID)
+		candidate.Assigned = true
+		suggestion.Assigned = append(suggestion.Assigned, users[0].Username)
+		added++
+	}
+	if added == 0 {
+		return nil
+	}
+	return c.Client.Put(ctx, mrEndpoint, map[string]interface{}{"reviewer_ids": reviewerIDs}, nil)
+}
@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+func TestCodeOwnersRegexp(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"*", "cmd/main.go", true},
+		{"*.md", "docs/guide/intro.md", true},
+		{"/README.md", "docs/README.md", false},
+		{"README.md", "docs/README.md", true},
+		{"/docs/", "docs/api/index.md", true},
+		{"/docs/", "src/docs/x.md", false},
+		{"/internal", "internal/auth/token.go", true},
+		{"/lib/*.go", "lib/sub/x.go", false},
+		{"/lib/**/*.go", "lib/sub/deep/x.go", true},
+		{"/lib/**/*.go", "lib/x.go", true},
+		{"config?.yml", "app/config1.yml", true},
+	}
+	for _, tt := range tests {
+		re, err := codeOwnersRegexp(tt.pattern)
+		if err != nil {
+			t.Fatalf("codeOwnersRegexp(%q): %v", tt.pattern, err)
+		}
+		if got := re.MatchString(tt.path); got != tt.want {
+			t.Errorf("%q matches %q = %t, want %t", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestReviewers(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/42/merge_requests/5", http.StatusOK, map[string]interface{}{
+		"iid":           5,
+		"target_branch": "main",
+		"author":        map[string]interface{}{"id": 1, "username": "alice"},
+		"reviewers":     []map[string]interface{}{{"id": 9, "username": "dave"}},
+	})
+	client.Handle(http.MethodGet, "/projects/42/repository/files/CODEOWNERS/raw?ref=main", http.StatusNotFound, `{"message":"404 File Not Found"}`)
+	client.Handle(http.MethodGet, "/projects/42/repository/files/docs%2FCODEOWNERS/raw?ref=main", http.StatusNotFound, `{"message":"404 File Not Found"}`)
+	client.Handle(http.MethodGet, "/projects/42/repository/files/.gitlab%2FCODEOWNERS/raw?ref=main", http.StatusOK, strings.Join([]string{
+		"# Default owners",
+		"* @alice",
+		"/pkg/ @bob @acme/backend",
+		"/pkg/legacy/ @carol",
+		"",
+		"^[Docs] @dave",
+		"*.md",
+	}, "\n"))
+	client.Handle(http.MethodGet, "/projects/42/merge_requests/5/diffs", http.StatusOK, []map[string]interface{}{
+		{"old_path": "pkg/api/server.go", "new_path": "pkg/api/server.go"},
+		{"old_path": "pkg/legacy/old.go", "new_path": "pkg/legacy/old.go"},
+		{"old_path": "pkg/api/README.md", "new_path": "pkg/api/README.md"},
+		{"old_path": "main.go", "new_path": "main.go"},
+	})
+	client.Handle(http.MethodGet, "/users?username=bob", http.StatusOK, []map[string]interface{}{{"id": 2, "username": "bob"}})
+	client.Handle(http.MethodGet, "/users?username=carol", http.StatusOK, []map[string]interface{}{{"id": 3, "username": "carol"}})
+	client.Handle(http.MethodPut, "/projects/42/merge_requests/5", http.StatusOK, map[string]interface{}{"iid": 5})
+
+	result := callTool(t, tc, "suggest_reviewers", map[string]interface{}{
+		"project_id":        "42",
+		"merge_request_iid": float64(5),
+		"assign":            true,
+		"max_reviewers":     float64(2),
+	})
+	if result.IsError {
+		t.Fatalf("suggest_reviewers failed: %s", resultText(t, result))
+	}
+	var got ReviewerSuggestion
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if got.CodeOwnersPath != ".gitlab/CODEOWNERS" || got.ChangedFiles != 4 || len(got.UnownedFiles) != 0 {
+		t.Errorf("suggestion = %+v", got)
+	}
+
+	var owners []string
+	for _, candidate := range got.Candidates {
+		owners = append(owners, fmt.Sprintf("%s:%s:%d:%t", candidate.Owner, candidate.Kind, candidate.Files, candidate.Required))
+	}
+	want := "@acme/backend:group:2:true,@bob:user:2:true,@carol:user:1:true,@dave:user:1:false"
+	if strings.Join(owners, ",") != want {
+		t.Errorf("candidates = %v, want %s", owners, want)
+	}
+	if strings.Join(got.Assigned, ",") != "bob,carol" {
+		t.Errorf("assigned = %v, want bob,carol", got.Assigned)
+	}
+
+	requests := client.Requests()
+	last := requests[len(requests)-1]
+	if last.Method != http.MethodPut || string(last.Body) != `{"reviewer_ids":[9,2,3]}` {
+		t.Errorf("last request = %s %s %s", last.Method, last.Endpoint, last.Body)
+	}
+}
+
+func TestParseCodeOwners(t *testing.T) {
+	sections := parseCodeOwners(strings.Join([]string{
+		"  # comment",
+		"/docs/my\\ guide.md @writer",
+		"\\#notes.txt @alice",
+		"",
+		"[Backend][2] @backend-lead",
+		"/api/",
+		"/api/public/ @alice @bob",
+		"^[Docs] @docs",
+		"*.md",
+		"[backend]",
+		"/db/ @dba",
+	}, "\n"))
+
+	var got []string
+	for _, section := range sections {
+		var rules []string
+		for _, rule := range section.rules {
+			rules = append(rules, rule.pattern+"="+strings.Join(rule.owners, "+"))
+		}
+		got = append(got, fmt.Sprintf("%s optional=%t approvals=%d [%s]", section.name, section.optional, section.approvals, strings.Join(rules, ", ")))
+	}
+	want := []string{
+		" optional=false approvals=0 [/docs/my guide.md=@writer, #notes.txt=@alice]",
+		// Sections with the same name are merged, case-insensitively
+		"Backend optional=false approvals=2 [/api/=@backend-lead, /api/public/=@alice+@bob, /db/=@dba]",
+		"Docs optional=true approvals=0 [*.md=@docs]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("sections =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// The last matching rule of a section applies
+	backend := sections[1]
+	for path, want := range map[string]string{
+		"api/public/v1.go":   "/api/public/",
+		"api/internal/db.go": "/api/",
+		"web/index.html":     "",
+	} {
+		got := ""
+		if rule := backend.owners(path); rule != nil {
+			got = rule.pattern
+		}
+		if got != want {
+			t.Errorf("owners of %s = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestSuggestReviewersCandidates(t *testing.T) {
+	sections := parseCodeOwners(strings.Join([]string{
+		"* @alice",
+		"/api/ @bob @Alice dev@example.com @@maintainers",
+		"^[Docs]",
+		"*.md @carol @bob",
+	}, "\n"))
+	mr := gitlab.MergeRequest{
+		Author:    &gitlab.User{Username: "ALICE"},
+		Reviewers: []gitlab.User{{Username: "carol"}},
+	}
+	got := suggestReviewers(sections, []string{"api/server.go", "api/README.md", "main.go"}, mr)
+
+	// The author is never a candidate, in any letter case
+	var owners []string
+	for _, candidate := range got.Candidates {
+		owners = append(owners, fmt.Sprintf("%s:%s:%d:%t:%t:%v", candidate.Owner, candidate.Kind, candidate.Files, candidate.Required, candidate.AlreadyReviewer, candidate.Sections))
+	}
+	want := "@@maintainers:role:2:true:false:[],@bob:user:2:true:false:[Docs],dev@example.com:email:2:true:false:[],@carol:user:1:false:true:[Docs]"
+	if strings.Join(owners, ",") != want {
+		t.Errorf("candidates = %v\nwant %s", owners, want)
+	}
+	if len(got.UnownedFiles) != 0 || len(got.Rules) != 3 {
+		t.Errorf("unowned = %v, rules = %+v", got.UnownedFiles, got.Rules)
+	}
+
+	// Files no section owns are listed
+	if got := suggestReviewers(parseCodeOwners("/api/ @bob"), []string{"api/x.go", "web/y.js"}, mr); strings.Join(got.UnownedFiles, ",") != "web/y.js" {
+		t.Errorf("unowned files = %v, want web/y.js", got.UnownedFiles)
+	}
+}
+
+func TestSuggestReviewersNoCodeOwners(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/42/merge_requests/5", http.StatusOK, map[string]interface{}{"iid": 5, "target_branch": "main"})
+	for _, path := range []string{"CODEOWNERS", "docs%2FCODEOWNERS", ".gitlab%2FCODEOWNERS"} {
+		client.Handle(http.MethodGet, "/projects/42/repository/files/"+path+"/raw?ref=main", http.StatusNotFound, `{"message":"404 File Not Found"}`)
+	}
+
+	result := callTool(t, tc, "suggest_reviewers", map[string]interface{}{"project_id": "42", "merge_request_iid": 5})
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "no CODEOWNERS file in") {
+		t.Errorf("result = %q, want a missing CODEOWNERS error", result.Content[0].Text)
+	}
+}
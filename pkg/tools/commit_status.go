@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+const (
+	// defaultStatusWait is how long wait_for_commit_status polls by default.
+	defaultStatusWait = 4 * time.Minute
+	// statusWaitMargin is kept free before the tool call's own deadline so the
+	// tool can still report the last status it saw.
+	statusWaitMargin = 5 * time.Second
+)
+
+// CommitStatus is a status reported on a commit, by GitLab CI or an external system.
+type CommitStatus struct {
+	ID          int        `json:"id"`
+	SHA         string     `json:"sha"`
+	Ref         string     `json:"ref"`
+	Status      string     `json:"status"`
+	Name        string     `json:"name"`
+	TargetURL   string     `json:"target_url,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Coverage    *float64   `json:"coverage,omitempty"`
+	PipelineID  int        `json:"pipeline_id,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// CommitStatusWait is the result of the wait_for_commit_status tool. Outcome
+// is success, failed, canceled or skipped when the status finished, or timeout.
+type CommitStatusWait struct {
+	SHA           string        `json:"sha"`
+	Name          string        `json:"name"`
+	Outcome       string        `json:"outcome"`
+	Status        *CommitStatus `json:"status,omitempty"`
+	Polls         int           `json:"polls"`
+	WaitedSeconds int           `json:"waited_seconds"`
+}
+
+// isFinalCommitStatus reports whether a commit status will no longer change.
+func isFinalCommitStatus(status string) bool {
+	switch status {
+	case "success", "failed", "canceled", "skipped":
+		return true
+	}
+	return false
+}
+
+// registerWaitForCommitStatus registers the wait_for_commit_status tool.
+func registerWaitForCommitStatus(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "wait_for_commit_status",
+			Description: "Wait until a named commit status (e.g., an external check such as a security scanner or deployment system) finishes on a commit. Polls until the status is success, failed, canceled or skipped, or until timeout_seconds pass; a status that does not exist yet is waited for too. Returns outcome=timeout rather than an error when time runs out, so the call can simply be repeated. Use it to gate merges on systems outside GitLab CI.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"sha": {
+						Type:        "string",
+						Description: "The commit SHA, or a branch or tag name resolved to its current commit",
+					},
+					"name": {
+						Type:        "string",
+						Description: "The status context name to wait for (e.g., security/scan)",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Only consider statuses reported for this branch or tag (optional)",
+					},
+					"timeout_seconds": {
+						Type:        "integer",
+						Description: "How long to wait (optional, default: 240); also limited by the server's tool timeout",
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(3600),
+					},
+					"poll_interval_seconds": {
+						Type:        "integer",
+						Description: "Seconds between checks (optional, default: 15)",
+						Minimum:     mcp.IntPtr(5),
+						Maximum:     mcp.IntPtr(300),
+					},
+				},
+				Required: []string{"project_id", "sha", "name"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "wait_for_commit_status", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			sha := GetString(args, "sha", "")
+			if sha == "" {
+				return ErrorResult("sha is required")
+			}
+			name := GetString(args, "name", "")
+			if name == "" {
+				return ErrorResult("name is required")
+			}
+			timeout := time.Duration(GetInt(args, "timeout_seconds", int(defaultStatusWait/time.Second))) * time.Second
+			interval := time.Duration(GetInt(args, "poll_interval_seconds", 15)) * time.Second
+			if interval < 5*time.Second {
+				return ErrorResult("poll_interval_seconds must be at least 5")
+			}
+			encodedProjectID := url.PathEscape(projectID)
+
+			// Pin a branch or tag to its current commit so a later push does
+			// not change what is being waited for
+			var commit gitlab.Commit
+			if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s/repository/commits/%s", encodedProjectID, url.PathEscape(sha)), &commit); err != nil {
+				return APIErrorResult("Failed to get commit", err)
+			}
+
+			start := time.Now()
+			deadline := start.Add(timeout)
+			if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Add(-statusWaitMargin).Before(deadline) {
+				deadline = ctxDeadline.Add(-statusWaitMargin)
+			}
+
+			params := url.Values{}
+			params.Set("name", name)
+			params.Set("all", "true")
+			if ref := GetString(args, "ref", ""); ref != "" {
+				params.Set("ref", ref)
+			}
+			endpoint := fmt.Sprintf("/projects/%s/repository/commits/%s/statuses?%s", encodedProjectID, url.PathEscape(commit.ID), params.Encode())
+
+			result := CommitStatusWait{SHA: commit.ID, Name: name}
+			for {
+				// Statuses are retried by reporting a new one; the newest counts.
+				// all=true returns every retry, so the list is paged through
+				var newest *CommitStatus
+				if _, err := streamPages(ctx, c.Client, endpoint, maxCollectedItems, func(status CommitStatus) {
+					if newest == nil || status.ID > newest.ID {
+						newest = &status
+					}
+				}); err != nil {
+					return APIErrorResult("Failed to get commit statuses", err)
+				}
+				result.Polls++
+				result.WaitedSeconds = int(time.Since(start).Seconds())
+				result.Status = newest
+				if result.Status != nil && isFinalCommitStatus(result.Status.Status) {
+					result.Outcome = result.Status.Status
+					return JSONResult(result)
+				}
+
+				current := "not reported yet"
+				if result.Status != nil {
+					current = result.Status.Status
+				}
+				if !time.Now().Add(interval).Before(deadline) {
+					result.Outcome = "timeout"
+					return JSONResult(result)
+				}
+				mcp.ReportProgress(ctx, time.Since(start).Seconds(), deadline.Sub(start).Seconds(), fmt.Sprintf("%s is %s", name, current))
+
+				timer := time.NewTimer(interval)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				}
+			}
+		},
+	)
+}
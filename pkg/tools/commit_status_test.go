@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/gitlabtest"
+)
+
+func TestWaitForCommitStatusPages(t *testing.T) {
+	// The HTTP fake paginates like GitLab, 20 statuses per page by default,
+	// so the newest status is only seen by paging through every retry
+	tc, _ := newTestContext(t)
+	server := gitlabtest.NewServer(t)
+	tc.Client = server.NewGitLabClient()
+
+	const sha = "0b4bc9a49b562e85de7cc9e834518ea6828729b9"
+	server.Handle(http.MethodGet, "/projects/acme%2Fapi/repository/commits/main", http.StatusOK, map[string]string{"id": sha})
+	statuses := make([]CommitStatus, 130)
+	for i := range statuses {
+		statuses[i] = CommitStatus{ID: i + 1, SHA: sha, Name: "security/scan", Status: "failed"}
+	}
+	statuses[len(statuses)-1].Status = "success"
+	server.Handle(http.MethodGet, "/projects/acme%2Fapi/repository/commits/"+sha+"/statuses", http.StatusOK, statuses)
+
+	result := callTool(t, tc, "wait_for_commit_status", map[string]interface{}{
+		"project_id": "acme/api",
+		"sha":        "main",
+		"name":       "security/scan",
+	})
+	var wait CommitStatusWait
+	if err := json.Unmarshal([]byte(resultText(t, result)), &wait); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if wait.Outcome != "success" || wait.Status == nil || wait.Status.ID != 130 || wait.SHA != sha || wait.Polls != 1 {
+		t.Errorf("wait = %+v, want the success reported last", wait)
+	}
+
+	var pages []string
+	for _, request := range server.Requests() {
+		if strings.Contains(request.Endpoint, "/statuses") {
+			pages = append(pages, request.Endpoint)
+		}
+	}
+	if len(pages) != 2 {
+		t.Fatalf("status requests = %v, want two pages", pages)
+	}
+	for i, endpoint := range pages {
+		if !strings.Contains(endpoint, fmt.Sprintf("page=%d&per_page=100", i+1)) || !strings.Contains(endpoint, "all=true") {
+			t.Errorf("request %d = %s, want page %d of 100 with all=true", i, endpoint, i+1)
+		}
+	}
+}
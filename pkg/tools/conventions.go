@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// ConventionRule is a convention checked by validate_conventions. Source is
+// "argument" or "config".
+type ConventionRule struct {
+	Subject string `json:"subject"`
+	Pattern string `json:"pattern"`
+	Source  string `json:"source"`
+}
+
+// ConventionViolation is a commit title, source branch or merge request title
+// that does not match its rule. Ref is the commit SHA for commits.
+type ConventionViolation struct {
+	Subject string `json:"subject"`
+	Ref     string `json:"ref,omitempty"`
+	Value   string `json:"value"`
+	Pattern string `json:"pattern"`
+}
+
+// ConventionReport is the response of the validate_conventions tool.
+type ConventionReport struct {
+	MergeRequestIID int                   `json:"merge_request_iid"`
+	Passed          bool                  `json:"passed"`
+	Rules           []ConventionRule      `json:"rules"`
+	CommitsChecked  int                   `json:"commits_checked"`
+	CommitsSkipped  int                   `json:"commits_skipped,omitempty"`
+	Violations      []ConventionViolation `json:"violations"`
+}
+
+// conventionRules returns the rules to check: each pattern argument, else the
+// configured one. Preset names are kept as given in the rules and expanded
+// when compiled.
+func conventionRules(cfg *config.Config, commit, branch, title string) []ConventionRule {
+	var configured [3]string
+	if cfg != nil {
+		configured = [3]string{cfg.CommitPattern, cfg.BranchPattern, cfg.TitlePattern}
+	}
+	var rules []ConventionRule
+	for i, subject := range []string{"commit", "branch", "title"} {
+		argument := [3]string{commit, branch, title}[i]
+		switch {
+		case argument != "":
+			rules = append(rules, ConventionRule{Subject: subject, Pattern: argument, Source: "argument"})
+		case configured[i] != "":
+			rules = append(rules, ConventionRule{Subject: subject, Pattern: configured[i], Source: "config"})
+		}
+	}
+	return rules
+}
+
+type validateConventionsArgs struct {
+	ProjectID           string `json:"project_id" validate:"required"`
+	MergeRequestIID     int    `json:"merge_request_iid" validate:"required,min=1"`
+	CommitPattern       string `json:"commit_pattern"`
+	BranchPattern       string `json:"branch_pattern"`
+	TitlePattern        string `json:"title_pattern"`
+	IncludeMergeCommits bool   `json:"include_merge_commits"`
+}
+
+// registerValidateConventions registers the validate_conventions tool.
+func registerValidateConventions(server *mcp.Server) {
+	presets := make([]string, 0, len(config.ConventionPresets))
+	for name := range config.ConventionPresets {
+		presets = append(presets, name)
+	}
+	sort.Strings(presets)
+	presetHint := fmt.Sprintf("A regular expression, or a preset: %s", strings.Join(presets, ", "))
+
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "validate_conventions",
+			Description: "Check a merge request against naming conventions before merging: every commit title, the source branch and the MR title are matched against regular expressions, and each mismatch is returned as a violation; passed is true when there are none. Patterns given as arguments override the server's GITLAB_COMMIT_PATTERN, GITLAB_BRANCH_PATTERN and GITLAB_MR_TITLE_PATTERN; subjects without a pattern are not checked. Presets: conventional (Conventional Commits, e.g. \"feat(api): add search\") and ticket (a key like ABC-123 first). Merge commits are skipped unless include_merge_commits is set.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"merge_request_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the merge request",
+					},
+					"commit_pattern": {
+						Type:        "string",
+						Description: "Pattern every commit title must match. " + presetHint,
+					},
+					"branch_pattern": {
+						Type:        "string",
+						Description: "Pattern the source branch must match, e.g. ^(feature|fix)/[A-Z]+-[0-9]+. " + presetHint,
+					},
+					"title_pattern": {
+						Type:        "string",
+						Description: "Pattern the merge request title must match. " + presetHint,
+					},
+					"include_merge_commits": {
+						Type:        "boolean",
+						Description: "Also check commits with more than one parent. Default: false",
+					},
+				},
+				Required: []string{"project_id", "merge_request_iid"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("validate_conventions", func(ctx context.Context, c *ToolContext, args validateConventionsArgs) (*mcp.CallToolResult, error) {
+			rules := conventionRules(c.Config, args.CommitPattern, args.BranchPattern, args.TitlePattern)
+			if len(rules) == 0 {
+				return ErrorResult("no conventions to check: pass commit_pattern, branch_pattern or title_pattern, or configure GITLAB_COMMIT_PATTERN, GITLAB_BRANCH_PATTERN or GITLAB_MR_TITLE_PATTERN")
+			}
+			patterns := map[string]*regexp.Regexp{}
+			for _, rule := range rules {
+				re, err := regexp.Compile(config.ConventionPattern(rule.Pattern))
+				if err != nil {
+					return ErrorResult(fmt.Sprintf("invalid %s pattern %q: %v", rule.Subject, rule.Pattern, err))
+				}
+				patterns[rule.Subject] = re
+			}
+
+			mrEndpoint := fmt.Sprintf("/projects/%s/merge_requests/%d", url.PathEscape(args.ProjectID), args.MergeRequestIID)
+			var mr gitlab.MergeRequest
+			if err := c.Client.Get(ctx, mrEndpoint, &mr); err != nil {
+				return APIErrorResult("Failed to get merge request", err)
+			}
+
+			report := ConventionReport{MergeRequestIID: args.MergeRequestIID, Rules: rules, Violations: []ConventionViolation{}}
+			check := func(subject, ref, value string) {
+				re := patterns[subject]
+				if re != nil && !re.MatchString(value) {
+					report.Violations = append(report.Violations, ConventionViolation{Subject: subject, Ref: ref, Value: value, Pattern: re.String()})
+				}
+			}
+			check("branch", "", mr.SourceBranch)
+			check("title", "", mr.Title)
+
+			if patterns["commit"] != nil {
+				commits, _, err := collectPages[gitlab.Commit](ctx, c.Client, mrEndpoint+"/commits", maxCollectedItems)
+				if err != nil {
+					return APIErrorResult("Failed to get merge request commits", err)
+				}
+				// GitLab lists the newest commit first; report oldest first
+				for i := len(commits) - 1; i >= 0; i-- {
+					commit := commits[i]
+					if len(commit.ParentIDs) > 1 && !args.IncludeMergeCommits {
+						report.CommitsSkipped++
+						continue
+					}
+					report.CommitsChecked++
+					check("commit", commit.ShortID, commit.Title)
+				}
+			}
+			report.Passed = len(report.Violations) == 0
+			return JSONResult(report)
+		}),
+	)
+}
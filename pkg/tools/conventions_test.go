@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateConventions(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.BranchPattern = `^(feature|fix)/`
+	client.Handle("GET", "/projects/42/merge_requests/7", 200, `{"iid": 7, "title": "Add search", "source_branch": "search"}`)
+	client.Handle("GET", "/projects/42/merge_requests/7/commits", 200, `[
+		{"id": "c3", "short_id": "c3", "title": "Merge branch 'main' into search", "parent_ids": ["c2", "m1"]},
+		{"id": "c2", "short_id": "c2", "title": "wip", "parent_ids": ["c1"]},
+		{"id": "c1", "short_id": "c1", "title": "feat(search): add index", "parent_ids": ["c0"]}
+	]`)
+
+	result := callTool(t, tc, "validate_conventions", map[string]interface{}{
+		"project_id": "42", "merge_request_iid": 7, "commit_pattern": "conventional",
+	})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	var report ConventionReport
+	if err := json.Unmarshal([]byte(resultText(t, result)), &report); err != nil {
+		t.Fatal(err)
+	}
+	wantRules := []ConventionRule{
+		{Subject: "commit", Pattern: "conventional", Source: "argument"},
+		{Subject: "branch", Pattern: `^(feature|fix)/`, Source: "config"},
+	}
+	if !reflect.DeepEqual(report.Rules, wantRules) {
+		t.Errorf("rules = %+v", report.Rules)
+	}
+	if report.Passed || report.CommitsChecked != 2 || report.CommitsSkipped != 1 {
+		t.Errorf("passed = %v, checked = %d, skipped = %d", report.Passed, report.CommitsChecked, report.CommitsSkipped)
+	}
+	var got []string
+	for _, v := range report.Violations {
+		got = append(got, v.Subject+":"+v.Ref+":"+v.Value)
+	}
+	if want := []string{"branch::search", "commit:c2:wip"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("violations = %v, want %v", got, want)
+	}
+
+	tc.Config.BranchPattern = ""
+	result = callTool(t, tc, "validate_conventions", map[string]interface{}{"project_id": "42", "merge_request_iid": 7})
+	if !result.IsError || !strings.Contains(resultText(t, result), "no conventions") {
+		t.Errorf("result without rules = %s", resultText(t, result))
+	}
+	result = callTool(t, tc, "validate_conventions", map[string]interface{}{"project_id": "42", "merge_request_iid": 7, "title_pattern": "["})
+	if !result.IsError || !strings.Contains(resultText(t, result), "invalid title pattern") {
+		t.Errorf("result with a bad pattern = %s", resultText(t, result))
+	}
+}
@@ -0,0 +1,218 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// sessionIdleTimeout is how long a session's default project is kept after
+// its last use.
+const sessionIdleTimeout = 24 * time.Hour
+
+// defaultProjectNote is appended to the project_id description of tools that
+// fall back to the default project.
+const defaultProjectNote = " Optional when a default project is set (see set_default_project)."
+
+// sessionProject is the default project of one session.
+type sessionProject struct {
+	projectID string
+	lastUsed  time.Time
+}
+
+// sessionProjects holds the defaults set with set_default_project, by
+// sessionKey.
+type sessionProjects struct {
+	mu       sync.Mutex
+	projects map[string]*sessionProject
+}
+
+func newSessionProjects() *sessionProjects {
+	return &sessionProjects{projects: make(map[string]*sessionProject)}
+}
+
+// DefaultProject is the result of the set_default_project tool. Scope is
+// "session" for a default set in this session, "server" for the configured
+// GITLAB_DEFAULT_PROJECT, or "none".
+type DefaultProject struct {
+	Scope             string `json:"scope"`
+	ProjectID         int    `json:"project_id,omitempty"`
+	PathWithNamespace string `json:"path_with_namespace,omitempty"`
+	WebURL            string `json:"web_url,omitempty"`
+}
+
+// sessionKey identifies the caller's session: the MCP session of the
+// authenticated principal, or the principal alone when the client sent no
+// session ID. It is empty when neither is known.
+func sessionKey(ctx context.Context) string {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	if sessionID := mcp.SessionIDFromContext(ctx); sessionID != "" {
+		return principal + "|" + sessionID
+	}
+	return principal
+}
+
+// defaultProjectID returns the project used when a tool call omits
+// project_id: the session default, else GITLAB_DEFAULT_PROJECT. The second
+// result is the scope of the default.
+func defaultProjectID(ctx context.Context) (string, string) {
+	c := FromContext(ctx)
+	if c == nil {
+		return "", "none"
+	}
+	if key := sessionKey(ctx); key != "" && c.sessions != nil {
+		if projectID, ok := c.sessions.get(key); ok {
+			return projectID, "session"
+		}
+	}
+	if c.Config != nil && c.Config.DefaultProjectID != "" {
+		return c.Config.DefaultProjectID, "server"
+	}
+	return "", "none"
+}
+
+// get returns the default of a session and marks it as used.
+func (s *sessionProjects) get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	project, ok := s.projects[key]
+	if !ok {
+		return "", false
+	}
+	project.lastUsed = time.Now()
+	return project.projectID, true
+}
+
+// set stores (or, for an empty projectID, clears) the session default and
+// drops defaults of idle sessions.
+func (s *sessionProjects) set(key, projectID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, project := range s.projects {
+		if now.Sub(project.lastUsed) > sessionIdleTimeout {
+			delete(s.projects, k)
+		}
+	}
+	if projectID == "" {
+		delete(s.projects, key)
+		return
+	}
+	s.projects[key] = &sessionProject{projectID: projectID, lastUsed: now}
+}
+
+// DefaultProjectMiddleware lets tools that require project_id be called
+// without it: the argument is filled in from the default project, and
+// project_id is no longer listed as required. Tools whose project_id is an
+// optional filter are left alone.
+func DefaultProjectMiddleware(tool mcp.Tool, handler mcp.ToolHandler) (mcp.Tool, mcp.ToolHandler) {
+	property, hasProject := tool.InputSchema.Properties["project_id"]
+	required := -1
+	for i, name := range tool.InputSchema.Required {
+		if name == "project_id" {
+			required = i
+		}
+	}
+	if !hasProject || required < 0 {
+		return tool, handler
+	}
+
+	properties := make(map[string]mcp.Property, len(tool.InputSchema.Properties))
+	for name, p := range tool.InputSchema.Properties {
+		properties[name] = p
+	}
+	property.Description += defaultProjectNote
+	properties["project_id"] = property
+	tool.InputSchema.Properties = properties
+	tool.InputSchema.Required = append(append([]string{}, tool.InputSchema.Required[:required]...), tool.InputSchema.Required[required+1:]...)
+
+	wrapped := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		if GetString(args, "project_id", "") == "" {
+			projectID, _ := defaultProjectID(ctx)
+			if projectID == "" {
+				return ErrorResult("project_id is required (no default project is set; call set_default_project or pass project_id)")
+			}
+			withProject := make(map[string]interface{}, len(args)+1)
+			for key, value := range args {
+				withProject[key] = value
+			}
+			withProject["project_id"] = projectID
+			args = withProject
+		}
+		return handler(ctx, args)
+	}
+	return tool, wrapped
+}
+
+// registerSetDefaultProject registers the set_default_project tool.
+func registerSetDefaultProject(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "set_default_project",
+			Description: "Set the default project for the rest of this session, so later tool calls can omit project_id. The project is looked up first, so a typo fails here rather than later. Call without project_id to show the current default, or with clear=true (which ignores project_id) to remove the session default (the server's GITLAB_DEFAULT_PROJECT, if any, applies again).",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"clear": {
+						Type:        "boolean",
+						Description: "Remove the session default instead of setting one",
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "set_default_project", args)
+
+			projectID := GetString(args, "project_id", "")
+			clearDefault := GetBool(args, "clear", false)
+			var project *gitlab.Project
+			if projectID != "" || clearDefault {
+				key := sessionKey(ctx)
+				if key == "" {
+					return ErrorResult(fmt.Sprintf("no session to store a default in: send the %s header returned by initialize, or enable authentication", mcp.SessionIDHeader))
+				}
+				if clearDefault {
+					c.sessions.set(key, "")
+				} else {
+					project = &gitlab.Project{}
+					if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s", url.PathEscape(projectID)), project); err != nil {
+						return APIErrorResult("Failed to get project", err)
+					}
+					c.sessions.set(key, project.PathWithNamespace)
+				}
+			}
+
+			current, scope := defaultProjectID(ctx)
+			result := DefaultProject{Scope: scope}
+			if project == nil && current != "" {
+				project = &gitlab.Project{}
+				if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s", url.PathEscape(current)), project); err != nil {
+					return APIErrorResult("Failed to get default project", err)
+				}
+			}
+			if project != nil {
+				result.ProjectID = project.ID
+				result.PathWithNamespace = project.PathWithNamespace
+				result.WebURL = project.WebURL
+			}
+			return JSONResult(result)
+		},
+	)
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+func TestSessionDefaultProject(t *testing.T) {
+	// Two stdio servers in one process: both callers are in the "stdio" session
+	first, _ := newTestContext(t)
+	second, _ := newTestContext(t)
+	firstCtx := mcp.WithSessionID(WithToolContext(context.Background(), first), "stdio")
+	secondCtx := mcp.WithSessionID(WithToolContext(context.Background(), second), "stdio")
+
+	first.sessions.set(sessionKey(firstCtx), "acme/api")
+	if project, scope := defaultProjectID(firstCtx); project != "acme/api" || scope != "session" {
+		t.Errorf("default project = %q (%s), want acme/api from the session", project, scope)
+	}
+	if project, scope := defaultProjectID(secondCtx); project != "" || scope != "none" {
+		t.Errorf("other server's default project = %q (%s), want none", project, scope)
+	}
+	second.Config.DefaultProjectID = "acme/web"
+	if project, scope := defaultProjectID(secondCtx); project != "acme/web" || scope != "server" {
+		t.Errorf("other server's default project = %q (%s), want acme/web from the server", project, scope)
+	}
+
+	// Session defaults survive a configuration reload
+	reloaded, err := first.Reconfigure(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloadedCtx := mcp.WithSessionID(WithToolContext(context.Background(), reloaded), "stdio")
+	if project, _ := defaultProjectID(reloadedCtx); project != "acme/api" {
+		t.Errorf("default project after reload = %q, want acme/api", project)
+	}
+
+	first.sessions.set(sessionKey(firstCtx), "")
+	if project, scope := defaultProjectID(firstCtx); project != "" || scope != "none" {
+		t.Errorf("cleared default project = %q (%s), want none", project, scope)
+	}
+}
+
+func TestDefaultProjectMiddleware(t *testing.T) {
+	tc, _ := newTestContext(t)
+	ctx := mcp.WithSessionID(WithToolContext(context.Background(), tc), "session-a")
+	tc.sessions.set(sessionKey(ctx), "acme/api")
+
+	var got map[string]interface{}
+	handler := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		got = args
+		return TextResult("ok")
+	}
+	tool, wrapped := DefaultProjectMiddleware(mcp.Tool{
+		Name: "get_issue",
+		InputSchema: mcp.JSONSchema{
+			Type:       "object",
+			Properties: map[string]mcp.Property{"project_id": {Type: "string"}, "issue_iid": {Type: "integer"}},
+			Required:   []string{"project_id", "issue_iid"},
+		},
+	}, handler)
+	if len(tool.InputSchema.Required) != 1 || tool.InputSchema.Required[0] != "issue_iid" {
+		t.Errorf("required = %v, want project_id dropped", tool.InputSchema.Required)
+	}
+
+	args := map[string]interface{}{"issue_iid": 7}
+	wrapped(ctx, args)
+	if got["project_id"] != "acme/api" || got["issue_iid"] != 7 || len(args) != 1 {
+		t.Errorf("tool called with %v (caller's arguments %v), want the session default added", got, args)
+	}
+	wrapped(ctx, map[string]interface{}{"project_id": "acme/web"})
+	if got["project_id"] != "acme/web" {
+		t.Errorf("explicit project_id replaced by %v", got["project_id"])
+	}
+
+	// Another session of the same server has no default
+	other := mcp.WithSessionID(WithToolContext(context.Background(), tc), "session-b")
+	if result, _ := wrapped(other, map[string]interface{}{"issue_iid": 7}); !result.IsError {
+		t.Errorf("call without a default project = %q, want an error", resultText(t, result))
+	}
+}
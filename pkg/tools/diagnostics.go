@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// nearLimitRatio is the fraction of remaining budget below which callers are told to slow down.
+const nearLimitRatio = 0.1
+
+// RateLimitStatus is the response of the get_rate_limit_status tool.
+type RateLimitStatus struct {
+	EndpointClasses []gitlab.RateLimitInfo `json:"endpoint_classes"`
+	Limiter         *gitlab.LimiterBudget  `json:"limiter"`
+	NearLimit       bool                   `json:"near_limit"`
+	Advice          string                 `json:"advice"`
+}
+
+// ConnectivityCheck is the outcome of a single request made by CheckConnectivity.
+type ConnectivityCheck struct {
+	Name       string `json:"name"`
+	Endpoint   string `json:"endpoint"`
+	OK         bool   `json:"ok"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	LatencyMS  int64  `json:"latency_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ConnectivityReport is the response of the gitlab_connectivity_check tool.
+type ConnectivityReport struct {
+	OK             bool                `json:"ok"`
+	BaseURL        string              `json:"base_url"`
+	Reachable      bool                `json:"reachable"`
+	TokenValid     bool                `json:"token_valid"`
+	User           *gitlab.User        `json:"user,omitempty"`
+	APIVersion     string              `json:"api_version"`
+	GitLabVersion  string              `json:"gitlab_version,omitempty"`
+	GitLabRevision string              `json:"gitlab_revision,omitempty"`
+	LatencyMS      int64               `json:"latency_ms"`
+	Checks         []ConnectivityCheck `json:"checks"`
+}
+
+// CheckConnectivity verifies that the GitLab API is reachable and the token is
+// valid (GET /user), and reads the instance version (GET /version). LatencyMS is
+// the round trip of the /user request. The report is OK when the token is valid.
+func CheckConnectivity(ctx context.Context, client gitlab.API) *ConnectivityReport {
+	report := &ConnectivityReport{
+		BaseURL:    client.BaseURL(),
+		APIVersion: "v4",
+	}
+
+	var user gitlab.User
+	userCheck := runConnectivityCheck(ctx, client, "token", "/user", &user)
+	report.Checks = append(report.Checks, userCheck)
+	report.LatencyMS = userCheck.LatencyMS
+	if userCheck.OK {
+		report.TokenValid = true
+		report.User = &user
+	}
+
+	var version gitlab.Version
+	versionCheck := runConnectivityCheck(ctx, client, "version", "/version", &version)
+	report.Checks = append(report.Checks, versionCheck)
+	if versionCheck.OK {
+		report.GitLabVersion = version.Version
+		report.GitLabRevision = version.Revision
+	}
+
+	// Any HTTP response, even an error status, proves the base URL is reachable
+	report.Reachable = userCheck.HTTPStatus > 0 || versionCheck.HTTPStatus > 0
+	report.OK = report.TokenValid
+	return report
+}
+
+// runConnectivityCheck performs one timed GET request for CheckConnectivity.
+func runConnectivityCheck(ctx context.Context, client gitlab.API, name, endpoint string, result interface{}) ConnectivityCheck {
+	check := ConnectivityCheck{Name: name, Endpoint: endpoint}
+
+	start := time.Now()
+	err := client.Get(ctx, endpoint, result)
+	check.LatencyMS = time.Since(start).Milliseconds()
+
+	if err == nil {
+		check.OK = true
+		check.HTTPStatus = 200
+		return check
+	}
+
+	check.Error = err.Error()
+	var apiErr *gitlab.APIError
+	if errors.As(err, &apiErr) {
+		check.HTTPStatus = apiErr.StatusCode
+	}
+	return check
+}
+
+// initDiagnosticTools registers server diagnostic tools.
+// Includes: get_rate_limit_status, gitlab_connectivity_check, get_server_stats
+func initDiagnosticTools(server *mcp.Server) {
+	registerGetRateLimitStatus(server)
+	registerGitLabConnectivityCheck(server)
+	registerGetServerStats(server)
+}
+
+// registerGetRateLimitStatus registers the get_rate_limit_status tool.
+func registerGetRateLimitStatus(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_rate_limit_status",
+			Description: "Report GitLab rate limit state without calling GitLab: the most recent RateLimit-* headers per endpoint class (projects, search, files, raw, ...) and the remaining budget of the client-side limiter (GITLAB_RATE_LIMIT). Use it to self-throttle before issuing many requests.",
+			InputSchema: mcp.JSONSchema{
+				Type:       "object",
+				Properties: map[string]mcp.Property{},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_rate_limit_status", args)
+
+			status := RateLimitStatus{
+				EndpointClasses: c.Client.RateLimitStatus(),
+				Limiter:         c.Client.LimiterBudget(),
+			}
+
+			for _, info := range status.EndpointClasses {
+				if info.Limit > 0 && float64(info.Remaining) < float64(info.Limit)*nearLimitRatio {
+					status.NearLimit = true
+				}
+			}
+			if status.Limiter != nil && status.Limiter.Available < float64(status.Limiter.Burst)*nearLimitRatio {
+				status.NearLimit = true
+			}
+
+			switch {
+			case status.NearLimit:
+				status.Advice = "Close to a rate limit: batch work, prefer list endpoints over per-item calls, and pause until reset_at where given."
+			case len(status.EndpointClasses) == 0:
+				status.Advice = "No RateLimit headers observed yet; GitLab may not send them for this instance or no requests have been made."
+			default:
+				status.Advice = "Rate limit budget is healthy."
+			}
+
+			return JSONResult(status)
+		},
+	)
+}
+
+// registerGitLabConnectivityCheck registers the gitlab_connectivity_check tool.
+func registerGitLabConnectivityCheck(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "gitlab_connectivity_check",
+			Description: "Diagnose the GitLab connection: verifies the base URL is reachable, the token is valid (GET /user), reads the GitLab version, and measures latency. Good first call in a session or when other tools fail unexpectedly.",
+			InputSchema: mcp.JSONSchema{
+				Type:       "object",
+				Properties: map[string]mcp.Property{},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "gitlab_connectivity_check", args)
+
+			return JSONResult(CheckConnectivity(ctx, c.Client))
+		},
+	)
+}
+
+type serverStatsArgs struct {
+	Tool string `json:"tool"`
+}
+
+// registerGetServerStats registers the get_server_stats tool.
+func registerGetServerStats(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_server_stats",
+			Description: "Report which tools have been called since the server started: call and error counts, slow calls, mean/p50/p95/max latency and a latency histogram per tool, most called first, plus the slowest tools by p95. Does not call GitLab.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"tool": {
+						Type:        "string",
+						Description: "Only report this tool (e.g., get_pipeline_job_output)",
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_server_stats", func(ctx context.Context, c *ToolContext, args serverStatsArgs) (*mcp.CallToolResult, error) {
+			stats := mcp.ToolStatsFromContext(ctx)
+			if stats == nil {
+				return ErrorResult("tool usage statistics are not available")
+			}
+
+			report := stats.Report()
+			if args.Tool != "" {
+				var tools []mcp.ToolStat
+				for _, tool := range report.Tools {
+					if tool.Name == args.Tool {
+						tools = append(tools, tool)
+					}
+				}
+				if len(tools) == 0 {
+					return TextResult(fmt.Sprintf("Tool %s has not been called since %s", args.Tool, report.Since.Format(time.RFC3339)))
+				}
+				report.Tools = tools
+			}
+			return JSONResult(report)
+		}),
+	)
+}
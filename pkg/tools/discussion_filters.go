@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+// maxNoteExcerpt is the length, in characters, of note excerpts in compact
+// discussion listings.
+const maxNoteExcerpt = 200
+
+// NoteSummary is a note in the compact mode of mr_discussions.
+type NoteSummary struct {
+	ID           int    `json:"id"`
+	DiscussionID string `json:"discussion_id"`
+	Author       string `json:"author"`
+	Excerpt      string `json:"excerpt"`
+	Resolvable   bool   `json:"resolvable"`
+	Resolved     bool   `json:"resolved"`
+}
+
+// discussionResolved reports whether a discussion can be resolved, and
+// whether all its resolvable notes are.
+func discussionResolved(d Discussion) (resolvable, resolved bool) {
+	resolved = true
+	for _, note := range d.Notes {
+		if note.Resolvable {
+			resolvable = true
+			resolved = resolved && note.Resolved
+		}
+	}
+	return resolvable, resolvable && resolved
+}
+
+// discussionFilter selects discussions by resolved state ("resolved" or
+// "unresolved"; empty for any) and by the username of one of their note
+// authors (empty for any).
+type discussionFilter struct {
+	State  string
+	Author string
+}
+
+// active reports whether the filter selects anything less than all
+// discussions.
+func (f discussionFilter) active() bool {
+	return f.State != "" || f.Author != ""
+}
+
+// match reports whether a discussion passes the filter. Discussions that
+// cannot be resolved match neither state.
+func (f discussionFilter) match(d Discussion) bool {
+	if f.State != "" {
+		resolvable, resolved := discussionResolved(d)
+		if !resolvable || resolved != (f.State == "resolved") {
+			return false
+		}
+	}
+	if f.Author == "" {
+		return true
+	}
+	for _, note := range d.Notes {
+		if note.Author != nil && strings.EqualFold(note.Author.Username, f.Author) {
+			return true
+		}
+	}
+	return false
+}
+
+// pageOf returns one page of items and its pagination, for lists filtered
+// locally rather than by GitLab.
+func pageOf[T any](items []T, page, perPage int) ([]T, *gitlab.PaginationInfo) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 20
+	}
+	pagination := &gitlab.PaginationInfo{Page: page, PerPage: perPage, Total: len(items), TotalPages: (len(items) + perPage - 1) / perPage}
+	if page > 1 {
+		pagination.PrevPage = page - 1
+	}
+	if page < pagination.TotalPages {
+		pagination.NextPage = page + 1
+	}
+	start := min((page-1)*perPage, len(items))
+	return items[start:min(start+perPage, len(items))], pagination
+}
+
+// noteExcerpt returns the start of a note body on one line.
+func noteExcerpt(body string) string {
+	excerpt := strings.Join(strings.Fields(body), " ")
+	if runes := []rune(excerpt); len(runes) > maxNoteExcerpt {
+		excerpt = string(runes[:maxNoteExcerpt]) + "…"
+	}
+	return excerpt
+}
+
+// summarizeNotes flattens discussions into their non-system notes.
+func summarizeNotes(discussions []Discussion) []NoteSummary {
+	summaries := []NoteSummary{}
+	for _, d := range discussions {
+		for _, note := range d.Notes {
+			if note.System {
+				continue
+			}
+			summary := NoteSummary{
+				ID:           note.ID,
+				DiscussionID: d.ID,
+				Excerpt:      noteExcerpt(note.Body),
+				Resolvable:   note.Resolvable,
+				Resolved:     note.Resolved,
+			}
+			if note.Author != nil {
+				summary.Author = note.Author.Username
+			}
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+const (
+	// defaultMaxInlineBytes limits downloads returned in the tool result.
+	defaultMaxInlineBytes = 10 << 20
+	// defaultMaxFileBytes limits downloads written to a temporary file.
+	defaultMaxFileBytes = 1 << 30
+)
+
+// DownloadedFile is the result of a download tool. Content is set for text and
+// base64 output; Path is set when the file was written to disk. Images are
+// returned as a separate image content item.
+type DownloadedFile struct {
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Encoding    string `json:"encoding,omitempty"`
+	Content     string `json:"content,omitempty"`
+	Path        string `json:"path,omitempty"`
+}
+
+// downloadOutputProperties are the output arguments shared by the download tools.
+var downloadOutputProperties = map[string]mcp.Property{
+	"output": {
+		Type:        "string",
+		Description: "How to return the file: auto (text for readable text, an image for PNG/JPEG/GIF/WebP pictures, base64 otherwise), text, base64, image, or file to write it to a temporary file on the server and return its path (stdio mode only). Default: auto",
+		Enum:        []string{"auto", "text", "base64", "image", "file"},
+	},
+	"max_bytes": {
+		Type:        "integer",
+		Description: "Refuse files larger than this many bytes (optional, default: 10 MiB inline, 1 GiB for output=file)",
+		Minimum:     mcp.IntPtr(1),
+	},
+}
+
+// withDownloadOutputProperties adds downloadOutputProperties to a tool's input properties.
+func withDownloadOutputProperties(properties map[string]mcp.Property) map[string]mcp.Property {
+	for name, property := range downloadOutputProperties {
+		properties[name] = property
+	}
+	return properties
+}
+
+// downloadResult downloads endpoint as described by the downloadOutputProperties
+// arguments. name is used when GitLab does not send a filename.
+func downloadResult(ctx context.Context, c *ToolContext, endpoint, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	output := GetString(args, "output", "auto")
+	switch output {
+	case "auto", "text", "base64", "image", "file":
+	default:
+		return ErrorResult("output must be one of: auto, text, base64, image, file")
+	}
+
+	if output == "file" {
+		return downloadToTempFile(ctx, c, endpoint, name, int64(GetInt(args, "max_bytes", defaultMaxFileBytes)))
+	}
+
+	maxBytes := int64(GetInt(args, "max_bytes", defaultMaxInlineBytes))
+	var buf bytes.Buffer
+	info, err := c.Client.GetBinary(ctx, endpoint, &buf, maxBytes)
+	if errors.Is(err, gitlab.ErrResponseTooLarge) {
+		return ErrorResult(fmt.Sprintf("%v; use output=file or raise max_bytes", err))
+	}
+	if err != nil {
+		return APIErrorResult("Failed to download file", err)
+	}
+
+	result := DownloadedFile{
+		Filename:    info.Filename,
+		ContentType: info.ContentType,
+		Size:        info.Size,
+	}
+	if result.Filename == "" {
+		result.Filename = name
+	}
+
+	isText := gitlab.IsTextContentType(info.ContentType) && utf8.Valid(buf.Bytes())
+	switch {
+	case output == "image" && !isImageContentType(info.ContentType):
+		return ErrorResult(fmt.Sprintf("%s is %s, not a PNG, JPEG, GIF or WebP image; use output=base64", result.Filename, info.ContentType))
+	case output == "image" || (output == "auto" && isImageContentType(info.ContentType)):
+		return ImageResult(result, buf.Bytes(), info.ContentType)
+	case output == "text" && !utf8.Valid(buf.Bytes()):
+		return ErrorResult(fmt.Sprintf("%s (%s) is not valid UTF-8 text; use output=base64", result.Filename, info.ContentType))
+	case output == "text" || (output == "auto" && isText):
+		result.Encoding = "text"
+		result.Content = buf.String()
+	default:
+		result.Encoding = "base64"
+		result.Content = base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+	return JSONResult(result)
+}
+
+// downloadToTempFile streams endpoint into a new temporary file. Like other
+// local paths, this is refused in HTTP mode.
+func downloadToTempFile(ctx context.Context, c *ToolContext, endpoint, name string, maxBytes int64) (*mcp.CallToolResult, error) {
+	if c.Config != nil && c.Config.HTTPMode {
+		return ErrorResult("output=file is not supported in HTTP mode: the server's filesystem is not the client's")
+	}
+
+	// The name is only a hint; keep it from escaping the temp directory
+	pattern := "gitlab-*"
+	if base := filepath.Base(name); base != "." && base != string(filepath.Separator) {
+		pattern += "-" + strings.ReplaceAll(base, "*", "_")
+	}
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to create temporary file: %v", err))
+	}
+
+	info, err := c.Client.GetBinary(ctx, endpoint, file, maxBytes)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write %s: %w", file.Name(), closeErr)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		if errors.Is(err, gitlab.ErrResponseTooLarge) {
+			return ErrorResult(fmt.Sprintf("%v; raise max_bytes", err))
+		}
+		return APIErrorResult("Failed to download file", err)
+	}
+
+	result := DownloadedFile{
+		Filename:    info.Filename,
+		ContentType: info.ContentType,
+		Size:        info.Size,
+		Path:        file.Name(),
+	}
+	if result.Filename == "" {
+		result.Filename = name
+	}
+	return JSONResult(result)
+}
+
+// isImageContentType reports whether a media type is a picture MCP clients
+// can display as image content.
+func isImageContentType(contentType string) bool {
+	switch contentType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
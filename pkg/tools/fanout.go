@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+const (
+	// defaultFanOutWorkers is how many projects a fan-out queries at once.
+	defaultFanOutWorkers = 5
+	// maxFanOutWorkers caps the concurrency callers can ask for, so one tool
+	// call cannot monopolize the GitLab rate limit.
+	maxFanOutWorkers = 10
+	// maxFanOutProjects caps the projects of one fan-out.
+	maxFanOutProjects = 100
+)
+
+// projectResult is the outcome of a fanned-out call for one project.
+type projectResult[T any] struct {
+	ProjectID string
+	Value     T
+	Err       error
+}
+
+// fanOut calls fn for each project with at most workers calls in flight, and
+// returns the results in the order of projects. A failing project does not
+// stop the others; once ctx is done, the projects not yet started fail with
+// the context's error. Progress is reported as projects complete.
+func fanOut[T any](ctx context.Context, projects []string, workers int, fn func(ctx context.Context, projectID string) (T, error)) []projectResult[T] {
+	if workers < 1 {
+		workers = defaultFanOutWorkers
+	}
+	results := make([]projectResult[T], len(projects))
+	next := make(chan int)
+	var done atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(projects)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				result := projectResult[T]{ProjectID: projects[i]}
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+				} else {
+					result.Value, result.Err = fn(ctx, projects[i])
+				}
+				results[i] = result
+				n := done.Add(1)
+				mcp.ReportProgress(ctx, float64(n), float64(len(projects)), fmt.Sprintf("Queried %d of %d projects", n, len(projects)))
+			}
+		}()
+	}
+	for i := range projects {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// groupProjectPaths returns the paths of the non-archived projects of a
+// group, up to limit, and whether the group has more.
+func groupProjectPaths(ctx context.Context, client gitlab.API, groupID string, includeSubgroups bool, limit int) ([]string, bool, error) {
+	params := url.Values{}
+	params.Set("archived", "false")
+	params.Set("order_by", "path")
+	params.Set("sort", "asc")
+	if includeSubgroups {
+		params.Set("include_subgroups", "true")
+	}
+	var paths []string
+	complete, err := streamPages(ctx, client, fmt.Sprintf("/groups/%s/projects?%s", url.PathEscape(groupID), params.Encode()), limit, func(project gitlab.Project) {
+		paths = append(paths, project.PathWithNamespace)
+	})
+	return paths, !complete, err
+}
+
+// MultiProjectItem is one item returned by multi_project_query, tagged with
+// the project it came from.
+type MultiProjectItem struct {
+	Project string          `json:"project"`
+	Item    json.RawMessage `json:"item"`
+}
+
+// MultiProjectStatus is the outcome of multi_project_query for one project.
+type MultiProjectStatus struct {
+	Project string `json:"project"`
+	Count   int    `json:"count"`
+	// Truncated is true when the project has more items than per_project
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// MultiProjectResult is the response of the multi_project_query tool.
+type MultiProjectResult struct {
+	Resource string `json:"resource"`
+	Query    string `json:"query,omitempty"`
+	// ProjectsTruncated is true when the group has more than max_projects
+	// projects
+	ProjectsTruncated bool                 `json:"projects_truncated,omitempty"`
+	Failed            int                  `json:"failed"`
+	Projects          []MultiProjectStatus `json:"projects"`
+	Items             []MultiProjectItem   `json:"items"`
+}
+
+// projectResourcePattern matches the project sub-resources multi_project_query
+// may read, e.g. "merge_requests" or "repository/branches".
+var projectResourcePattern = regexp.MustCompile(`^[a-z_]+(/[A-Za-z0-9_.%-]+)*$`)
+
+type multiProjectQueryArgs struct {
+	ProjectIDs       []string `json:"project_ids"`
+	GroupID          string   `json:"group_id"`
+	IncludeSubgroups bool     `json:"include_subgroups"`
+	Resource         string   `json:"resource" validate:"required"`
+	Query            string   `json:"query"`
+	PerProject       int      `json:"per_project" validate:"min=1,max=100"`
+	MaxProjects      int      `json:"max_projects" validate:"min=1,max=100"`
+	Concurrency      int      `json:"concurrency" validate:"min=1,max=10"`
+}
+
+// registerMultiProjectQuery registers the multi_project_query tool.
+func registerMultiProjectQuery(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "multi_project_query",
+			Description: "Read the same project resource from many projects at once and merge the results, instead of one call per project. Projects are queried in parallel with a bounded worker pool; a failing project is reported without failing the others. Example: resource=merge_requests, query=state=opened&author_username=alice over a group lists someone's open MRs across all its projects.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_ids": {
+						Type:        "array",
+						Description: "Projects to query, as IDs or paths (e.g., [\"acme/api\", \"42\"]). Either this or group_id is required",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"group_id": {
+						Type:        "string",
+						Description: "Query every non-archived project of this group (ID or path)",
+					},
+					"include_subgroups": {
+						Type:        "boolean",
+						Description: "With group_id, include projects of subgroups. Default: false",
+					},
+					"resource": {
+						Type:        "string",
+						Description: "Project sub-resource to GET, relative to /projects/:id/ (e.g., merge_requests, issues, pipelines, repository/branches, releases)",
+					},
+					"query": {
+						Type:        "string",
+						Description: "Query string passed to every request (e.g., state=opened&labels=bug). Pagination parameters are set by per_project",
+					},
+					"per_project": {
+						Type:        "integer",
+						Description: "Items read per project (max 100). Default: 20",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+					"max_projects": {
+						Type:        "integer",
+						Description: "With group_id, the most projects queried (max 100). Default: 50",
+						Default:     50,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(maxFanOutProjects),
+					},
+					"concurrency": {
+						Type:        "integer",
+						Description: "Projects queried at once (max 10). Default: 5",
+						Default:     defaultFanOutWorkers,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(maxFanOutWorkers),
+					},
+				},
+				Required: []string{"resource"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("multi_project_query", func(ctx context.Context, c *ToolContext, args multiProjectQueryArgs) (*mcp.CallToolResult, error) {
+			resource := strings.Trim(args.Resource, "/")
+			if !projectResourcePattern.MatchString(resource) || strings.Contains(resource, "..") {
+				return ErrorResult("resource must be a project sub-resource path such as merge_requests or repository/branches")
+			}
+			query, err := url.ParseQuery(args.Query)
+			if err != nil {
+				return ErrorResult(fmt.Sprintf("query is not a valid query string: %v", err))
+			}
+			perProject := args.PerProject
+			if perProject == 0 {
+				perProject = 20
+			}
+			query.Del("page")
+			query.Set("per_page", fmt.Sprintf("%d", perProject))
+
+			result := MultiProjectResult{Resource: resource, Query: args.Query, Projects: []MultiProjectStatus{}, Items: []MultiProjectItem{}}
+			projects := args.ProjectIDs
+			switch {
+			case len(projects) > 0 && args.GroupID != "":
+				return ErrorResult("pass either project_ids or group_id, not both")
+			case len(projects) > maxFanOutProjects:
+				return ErrorResult(fmt.Sprintf("at most %d project_ids can be queried at once", maxFanOutProjects))
+			case args.GroupID != "":
+				maxProjects := args.MaxProjects
+				if maxProjects == 0 {
+					maxProjects = 50
+				}
+				projects, result.ProjectsTruncated, err = groupProjectPaths(ctx, c.Client, args.GroupID, args.IncludeSubgroups, maxProjects)
+				if err != nil {
+					return APIErrorResult(fmt.Sprintf("Failed to list the projects of group %s", args.GroupID), err)
+				}
+			case len(projects) == 0:
+				return ErrorResult("project_ids or group_id is required")
+			}
+
+			type page struct {
+				items     []json.RawMessage
+				truncated bool
+			}
+			results := fanOut(ctx, projects, args.Concurrency, func(ctx context.Context, projectID string) (page, error) {
+				endpoint := fmt.Sprintf("/projects/%s/%s?%s", url.PathEscape(projectID), resource, query.Encode())
+				var body json.RawMessage
+				pagination, err := c.Client.GetWithPagination(ctx, endpoint, &body)
+				if err != nil {
+					return page{}, err
+				}
+				var items []json.RawMessage
+				if json.Unmarshal(body, &items) != nil {
+					// A single object, e.g. resource=languages
+					items = []json.RawMessage{body}
+				}
+				return page{items: items, truncated: pagination != nil && pagination.NextPage > 0}, nil
+			})
+
+			for _, r := range results {
+				status := MultiProjectStatus{Project: r.ProjectID, Count: len(r.Value.items), Truncated: r.Value.truncated}
+				if r.Err != nil {
+					status.Error = r.Err.Error()
+					result.Failed++
+				}
+				for _, item := range r.Value.items {
+					result.Items = append(result.Items, MultiProjectItem{Project: r.ProjectID, Item: item})
+				}
+				result.Projects = append(result.Projects, status)
+			}
+			return JSONResult(result)
+		}),
+	)
+}
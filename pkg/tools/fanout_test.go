@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/gitlabtest"
+)
+
+func TestMultiProjectQuery(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/groups/acme/projects", http.StatusOK, []map[string]interface{}{
+		{"id": 1, "path_with_namespace": "acme/api"},
+		{"id": 2, "path_with_namespace": "acme/legacy"},
+		{"id": 3, "path_with_namespace": "acme/web"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/merge_requests", http.StatusOK, []map[string]interface{}{{"iid": 1}, {"iid": 2}})
+	client.Handle(http.MethodGet, "/projects/acme%2Flegacy/merge_requests", http.StatusForbidden, `{"message":"403 Forbidden"}`)
+	client.AddRoute(gitlabtest.Route{
+		Endpoint:   "/projects/acme%2Fweb/merge_requests",
+		Body:       json.RawMessage(`[{"iid":7}]`),
+		Pagination: &gitlab.PaginationInfo{Page: 1, NextPage: 2},
+	})
+
+	result := callTool(t, tc, "multi_project_query", map[string]interface{}{
+		"group_id":    "acme",
+		"resource":    "merge_requests",
+		"query":       "state=opened&page=3",
+		"per_project": float64(2),
+		"concurrency": float64(2),
+	})
+	if result.IsError {
+		t.Fatalf("multi_project_query failed: %s", resultText(t, result))
+	}
+	var got MultiProjectResult
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+
+	if got.Failed != 1 || len(got.Projects) != 3 {
+		t.Fatalf("failed = %d, projects = %+v", got.Failed, got.Projects)
+	}
+	want := []MultiProjectStatus{
+		{Project: "acme/api", Count: 2},
+		{Project: "acme/legacy", Error: got.Projects[1].Error},
+		{Project: "acme/web", Count: 1, Truncated: true},
+	}
+	for i, status := range got.Projects {
+		if status != want[i] {
+			t.Errorf("projects[%d] = %+v, want %+v", i, status, want[i])
+		}
+	}
+	if got.Projects[1].Error == "" {
+		t.Error("acme/legacy has no error")
+	}
+	var sources []string
+	for _, item := range got.Items {
+		sources = append(sources, item.Project)
+	}
+	if strings.Join(sources, ",") != "acme/api,acme/api,acme/web" {
+		t.Errorf("item projects = %v", sources)
+	}
+	for _, req := range client.Requests() {
+		if strings.HasPrefix(req.Endpoint, "/projects/") && !strings.HasSuffix(req.Endpoint, "?per_page=2&state=opened") {
+			t.Errorf("request %s, want per_page=2&state=opened without page", req.Endpoint)
+		}
+	}
+}
+
+func TestMultiProjectQuery_InvalidResource(t *testing.T) {
+	tc, client := newTestContext(t)
+	result := callTool(t, tc, "multi_project_query", map[string]interface{}{
+		"project_ids": []interface{}{"acme/api"},
+		"resource":    "../../users",
+	})
+	if !result.IsError {
+		t.Errorf("multi_project_query accepted resource ../../users: %s", resultText(t, result))
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("requests = %+v, want none", requests)
+	}
+}
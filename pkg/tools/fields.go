@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// fieldsProperty is the common input property added to every tool by
+// ResultMiddleware.
+var fieldsProperty = mcp.Property{
+	Type:        "array",
+	Description: "Return only these keys of the JSON result (of each item for lists), e.g. [\"iid\",\"title\",\"state\"]. Dotted paths select nested keys, e.g. \"author.username\". Omit for the full result.",
+	Items:       &mcp.Property{Type: "string"},
+}
+
+// ResultMiddleware adds the fields argument to every tool, and the format
+// argument to list tools, and post-processes JSON results: the fields
+// projection is applied first, then list results are rendered in the requested
+// format (see formatList) or, for JSON, responses larger than MaxResponseBytes
+// are truncated (see truncateJSON).
+func ResultMiddleware(tool mcp.Tool, handler mcp.ToolHandler) (mcp.Tool, mcp.ToolHandler) {
+	_, hasFields := tool.InputSchema.Properties["fields"]
+	_, hasFormat := tool.InputSchema.Properties["format"]
+	listFormat := isListTool(tool.Name) && !hasFormat
+	if !hasFields || listFormat {
+		// The registered schema may be shared, so it is copied before adding
+		properties := make(map[string]mcp.Property, len(tool.InputSchema.Properties)+2)
+		for name, property := range tool.InputSchema.Properties {
+			properties[name] = property
+		}
+		if !hasFields {
+			properties["fields"] = fieldsProperty
+		}
+		if listFormat {
+			properties["format"] = listFormatProperty
+		}
+		tool.InputSchema.Properties = properties
+	}
+
+	// Keys required by the output schema survive projection so that
+	// structuredContent still validates
+	var required []string
+	if tool.OutputSchema != nil {
+		required = tool.OutputSchema.Required
+	}
+
+	wrapped := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		format := GetString(args, "format", formatJSON)
+		if listFormat && format != formatJSON && format != formatText && format != formatMarkdownTable {
+			return ErrorResult("format must be one of: json, text, markdown-table")
+		}
+
+		result, err := handler(ctx, args)
+		if err != nil || result == nil || result.IsError || len(result.Content) == 0 || result.Content[0].Type != "text" {
+			return result, err
+		}
+
+		text := result.Content[0].Text
+		fields := GetStringArray(args, "fields")
+		if len(fields) > 0 {
+			if projected := projectJSON([]byte(text), append(append([]string{}, fields...), required...)); projected != nil {
+				text = string(projected)
+			}
+		}
+		if listFormat && format != formatJSON {
+			if rendered := formatList([]byte(text), format, fields); rendered != "" {
+				// Clients still get the JSON as structuredContent when the
+				// tool declares an output schema
+				if tool.OutputSchema != nil {
+					result.StructuredContent = structuredJSON(text)
+				}
+				result.Content[0].Text = rendered
+				return result, nil
+			}
+		}
+		if limit := maxResponseBytes(ctx); limit > 0 && len(text) > limit {
+			if truncated := truncateJSON([]byte(text), limit); truncated != nil {
+				if c := FromContext(ctx); c != nil && c.Logger != nil {
+					c.Logger.InfoContext(ctx, "Response of %s truncated from %d to %d bytes (limit %d)", tool.Name, len(text), len(truncated), limit)
+				}
+				text = string(truncated)
+			}
+		}
+		result.Content[0].Text = text
+		return result, nil
+	}
+	return tool, wrapped
+}
+
+// projectJSON keeps only the given fields of a JSON result. Fields apply to
+// each element of a top-level array, to each element of the arrays in a list
+// envelope (an object with a "pagination" key), or else to the object itself.
+// It returns nil if data is not a JSON object or array.
+func projectJSON(data []byte, fields []string) []byte {
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil
+	}
+
+	paths := make([][]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			paths = append(paths, strings.Split(field, "."))
+		}
+	}
+
+	var projected interface{}
+	switch v := generic.(type) {
+	case []interface{}:
+		projected = projectValue(v, paths)
+	case map[string]interface{}:
+		if _, isEnvelope := v["pagination"]; isEnvelope {
+			envelope := make(map[string]interface{}, len(v))
+			for key, value := range v {
+				if items, ok := value.([]interface{}); ok {
+					envelope[key] = projectValue(items, paths)
+				} else {
+					envelope[key] = value
+				}
+			}
+			projected = envelope
+		} else {
+			projected = projectValue(v, paths)
+		}
+	default:
+		return nil
+	}
+
+	out, err := json.MarshalIndent(projected, "", "  ")
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// projectValue applies paths to a decoded JSON value. Arrays are projected
+// element-wise; values other than objects are returned unchanged.
+func projectValue(value interface{}, paths [][]string) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = projectValue(item, paths)
+		}
+		return out
+
+	case map[string]interface{}:
+		whole := make(map[string]bool)
+		nested := make(map[string][][]string)
+		for _, path := range paths {
+			if len(path) == 1 {
+				whole[path[0]] = true
+			} else {
+				nested[path[0]] = append(nested[path[0]], path[1:])
+			}
+		}
+
+		out := make(map[string]interface{})
+		for key, item := range v {
+			switch {
+			case whole[key]:
+				out[key] = item
+			case nested[key] != nil:
+				out[key] = projectValue(item, nested[key])
+			}
+		}
+		return out
+	}
+	return value
+}
+
+// structuredJSON returns JSON text as structuredContent, which must be an
+// object: top-level arrays are wrapped as {"items": [...]}.
+func structuredJSON(text string) interface{} {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "[") {
+		return map[string]json.RawMessage{"items": json.RawMessage(text)}
+	}
+	return json.RawMessage(text)
+}
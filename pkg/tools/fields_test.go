@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+func TestProjectJSON(t *testing.T) {
+	issue := `{"iid": 1, "title": "Bug", "state": "opened", "author": {"id": 5, "username": "alice", "name": "Alice"}}`
+	tests := []struct {
+		name   string
+		data   string
+		fields []string
+		want   string
+	}{
+		{
+			name:   "object",
+			data:   issue,
+			fields: []string{"iid", "title"},
+			want:   `{"iid": 1, "title": "Bug"}`,
+		},
+		{
+			name:   "dotted path",
+			data:   issue,
+			fields: []string{"iid", "author.username"},
+			want:   `{"iid": 1, "author": {"username": "alice"}}`,
+		},
+		{
+			name:   "whole object and a path into it",
+			data:   issue,
+			fields: []string{"author", "author.username"},
+			want:   `{"author": {"id": 5, "username": "alice", "name": "Alice"}}`,
+		},
+		{
+			name:   "path into a nested array",
+			data:   `{"iid": 1, "assignees": [{"id": 5, "username": "alice"}, {"id": 6, "username": "bob"}]}`,
+			fields: []string{"assignees.username"},
+			want:   `{"assignees": [{"username": "alice"}, {"username": "bob"}]}`,
+		},
+		{
+			name:   "top-level array",
+			data:   "[" + issue + "," + issue + "]",
+			fields: []string{" title ", ""},
+			want:   `[{"title": "Bug"}, {"title": "Bug"}]`,
+		},
+		{
+			name:   "list envelope",
+			data:   `{"issues": [` + issue + `], "pagination": {"page": 1, "next_page": 2}, "count": 1}`,
+			fields: []string{"iid"},
+			want:   `{"issues": [{"iid": 1}], "pagination": {"page": 1, "next_page": 2}, "count": 1}`,
+		},
+		{
+			name:   "missing fields",
+			data:   issue,
+			fields: []string{"milestone", "author.email"},
+			want:   `{"author": {}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := projectJSON([]byte(tt.data), tt.fields)
+			if out == nil {
+				t.Fatal("projectJSON returned nil")
+			}
+			var got, want interface{}
+			json.Unmarshal(out, &got)
+			json.Unmarshal([]byte(tt.want), &want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("projectJSON = %s, want %s", out, tt.want)
+			}
+		})
+	}
+
+	for _, data := range []string{`"text"`, `42`, `not json`} {
+		if out := projectJSON([]byte(data), []string{"iid"}); out != nil {
+			t.Errorf("projectJSON(%s) = %s, want nil", data, out)
+		}
+	}
+}
+
+func TestResultMiddleware(t *testing.T) {
+	response := `{"iid": 1, "title": "Bug", "state": "opened", "web_url": "https://gitlab.example.com/issues/1"}`
+	handler := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return TextResult(response)
+	}
+	schema := mcp.JSONSchema{Type: "object", Properties: map[string]mcp.Property{"project_id": {Type: "string"}}}
+	tool, wrapped := ResultMiddleware(mcp.Tool{
+		Name:         "get_issue",
+		InputSchema:  schema,
+		OutputSchema: &mcp.JSONSchema{Type: "object", Required: []string{"iid", "web_url"}},
+	}, handler)
+
+	if _, ok := tool.InputSchema.Properties["fields"]; !ok {
+		t.Error("fields was not added to the input schema")
+	}
+	if _, ok := tool.InputSchema.Properties["format"]; ok {
+		t.Error("format was added to a tool that does not list")
+	}
+
+	// Keys required by the output schema are kept
+	result, err := wrapped(context.Background(), map[string]interface{}{"fields": []interface{}{"title"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	json.Unmarshal([]byte(resultText(t, result)), &got)
+	want := map[string]interface{}{"iid": float64(1), "title": "Bug", "web_url": "https://gitlab.example.com/issues/1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("projected result = %v, want %v", got, want)
+	}
+
+	// Without fields the result is unchanged
+	result, _ = wrapped(context.Background(), nil)
+	if text := resultText(t, result); text != response {
+		t.Errorf("result without fields = %s", text)
+	}
+}
+
+func TestResultMiddlewareCopiesSchema(t *testing.T) {
+	handler := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return TextResult("[]")
+	}
+	// A tool declaring its own fields still gets format, and the shared
+	// properties of the registered tool are left alone
+	properties := map[string]mcp.Property{"fields": fieldsProperty, "project_id": {Type: "string"}}
+	tool, _ := ResultMiddleware(mcp.Tool{Name: "list_issues", InputSchema: mcp.JSONSchema{Type: "object", Properties: properties}}, handler)
+
+	if _, ok := tool.InputSchema.Properties["format"]; !ok {
+		t.Error("format was not added to a list tool")
+	}
+	if len(properties) != 2 {
+		t.Errorf("the original properties were modified: %v", properties)
+	}
+
+	properties = map[string]mcp.Property{"project_id": {Type: "string"}}
+	ResultMiddleware(mcp.Tool{Name: "list_issues", InputSchema: mcp.JSONSchema{Type: "object", Properties: properties}}, handler)
+	if len(properties) != 1 {
+		t.Errorf("the original properties were modified: %v", properties)
+	}
+}
+
+func TestResultMiddlewareInvalidFormat(t *testing.T) {
+	var called bool
+	handler := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		called = true
+		return TextResult("[]")
+	}
+	_, wrapped := ResultMiddleware(mcp.Tool{Name: "list_issues"}, handler)
+	result, _ := wrapped(context.Background(), map[string]interface{}{"format": "csv"})
+	if !result.IsError || called {
+		t.Errorf("format csv = %q (called %v), want an error before the call", resultText(t, result), called)
+	}
+}
@@ -2,9 +2,15 @@
 package tools
 
 import (
+	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"path"
+	"strings"
 
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
@@ -22,6 +28,8 @@ type FileResponse struct {
 	BlobID        string `json:"blob_id"`
 	CommitID      string `json:"commit_id"`
 	LastCommitID  string `json:"last_commit_id"`
+	// ExecuteFilemode is true for executable files
+	ExecuteFilemode bool `json:"execute_filemode"`
 }
 
 // FileCreateUpdateResponse represents the response from file create/update operations.
@@ -30,17 +38,50 @@ type FileCreateUpdateResponse struct {
 	Branch   string `json:"branch"`
 }
 
+// FileConflict describes a file that changed after the commit the caller based
+// its edit on. It is returned as {"conflict": {...}} by create_or_update_file.
+type FileConflict struct {
+	FilePath             string `json:"file_path"`
+	Branch               string `json:"branch"`
+	ExpectedLastCommitID string `json:"expected_last_commit_id"`
+	CurrentLastCommitID  string `json:"current_last_commit_id,omitempty"`
+	CurrentBlobID        string `json:"current_blob_id,omitempty"`
+	CurrentContentSHA256 string `json:"current_content_sha256,omitempty"`
+	Deleted              bool   `json:"deleted,omitempty"`
+	Hint                 string `json:"hint"`
+}
+
 // CommitAction represents an action to perform in a commit.
 type CommitAction struct {
 	Action   string `json:"action"`
 	FilePath string `json:"file_path"`
 	Content  string `json:"content,omitempty"`
 	Encoding string `json:"encoding,omitempty"`
+	// PreviousPath is the original path of a moved file.
+	PreviousPath string `json:"previous_path,omitempty"`
+	// ExecuteFilemode sets or clears the executable bit in a chmod action.
+	ExecuteFilemode *bool `json:"execute_filemode,omitempty"`
+	// LastCommitID makes the action fail if the file changed after that commit.
+	LastCommitID string `json:"last_commit_id,omitempty"`
+}
+
+// MarshalJSON sends content with create and update actions even when it is
+// empty, since GitLab rejects those actions without it.
+func (a CommitAction) MarshalJSON() ([]byte, error) {
+	type action CommitAction
+	if a.Content != "" || (a.Action != "create" && a.Action != "update") {
+		return json.Marshal(action(a))
+	}
+	return json.Marshal(struct {
+		action
+		Content string `json:"content"`
+	}{action: action(a)})
 }
 
 // CommitRequest represents a request to create a commit with multiple file changes.
 type CommitRequest struct {
 	Branch        string         `json:"branch"`
+	StartBranch   string         `json:"start_branch,omitempty"`
 	CommitMessage string         `json:"commit_message"`
 	Actions       []CommitAction `json:"actions"`
 	AuthorEmail   string         `json:"author_email,omitempty"`
@@ -73,7 +114,7 @@ func registerGetFileContents(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "get_file_contents",
-			Description: "Get the contents of a file from a GitLab repository. Returns file content (decoded from base64), file metadata, blob ID, and last commit ID. Use ref to get file from specific branch/tag/commit.",
+			Description: "Get the contents of a file from a GitLab repository. Returns file content (decoded from base64), file metadata, blob ID, last commit ID, and whether the file is executable. Use ref to get file from specific branch/tag/commit. The content of a symlink is its target path; set follow_symlinks to detect symlinks and return the file they point to instead.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -89,6 +130,10 @@ func registerGetFileContents(server *mcp.Server) {
 						Type:        "string",
 						Description: "The name of branch, tag, or commit (optional, defaults to default branch)",
 					},
+					"follow_symlinks": {
+						Type:        "boolean",
+						Description: "Look up the file's mode and, if it is a symlink, return the contents of its target (adds mode, symlink_target and resolved_path). Default: false",
+					},
 				},
 				Required: []string{"project_id", "file_path"},
 			},
@@ -96,12 +141,12 @@ func registerGetFileContents(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_file_contents", args)
+			c.Logger.ToolCall(ctx, "get_file_contents", args)
 
 			// Extract required parameters
 			projectID := GetString(args, "project_id", "")
@@ -116,6 +161,7 @@ func registerGetFileContents(server *mcp.Server) {
 
 			// Extract optional parameters
 			ref := GetString(args, "ref", "")
+			followSymlinks := GetBool(args, "follow_symlinks", false)
 
 			// Build the endpoint with URL-encoded project_id and file_path
 			encodedProjectID := url.PathEscape(projectID)
@@ -132,27 +178,49 @@ func registerGetFileContents(server *mcp.Server) {
 
 			// Make API request
 			var fileResp FileResponse
-			if err := ctx.Client.Get(endpoint, &fileResp); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get file contents: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &fileResp); err != nil {
+				return APIErrorResult("Failed to get file contents", err)
 			}
 
 			// Decode base64 content
 			decodedContent, err := base64.StdEncoding.DecodeString(fileResp.Content)
 			if err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to decode file content: %v", err))
+				return APIErrorResult("Failed to decode file content", err)
+			}
+
+			var link *symlinkResolution
+			if followSymlinks {
+				link, err = followSymlink(ctx, c.Client, encodedProjectID, fileResp.FilePath, fileResp.Ref, string(decodedContent))
+				if err != nil {
+					return APIErrorResult("Failed to follow symlink", err)
+				}
+				if link.ResolvedPath != "" {
+					fileResp, decodedContent = link.file, link.content
+				}
 			}
 
 			// Build response
 			result := map[string]interface{}{
-				"file_name":      fileResp.FileName,
-				"file_path":      fileResp.FilePath,
-				"size":           fileResp.Size,
-				"ref":            fileResp.Ref,
-				"blob_id":        fileResp.BlobID,
-				"commit_id":      fileResp.CommitID,
-				"last_commit_id": fileResp.LastCommitID,
-				"content_sha256": fileResp.ContentSHA256,
-				"content":        string(decodedContent),
+				"file_name":        fileResp.FileName,
+				"file_path":        fileResp.FilePath,
+				"size":             fileResp.Size,
+				"ref":              fileResp.Ref,
+				"blob_id":          fileResp.BlobID,
+				"commit_id":        fileResp.CommitID,
+				"last_commit_id":   fileResp.LastCommitID,
+				"content_sha256":   fileResp.ContentSHA256,
+				"content":          string(decodedContent),
+				"execute_filemode": fileResp.ExecuteFilemode,
+			}
+			if link != nil {
+				result["mode"] = link.Mode
+				if link.Target != "" {
+					result["symlink_target"] = link.Target
+					result["resolved_path"] = link.ResolvedPath
+				}
+				if link.Note != "" {
+					result["note"] = link.Note
+				}
 			}
 
 			return JSONResult(result)
@@ -165,7 +233,7 @@ func registerCreateOrUpdateFile(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "create_or_update_file",
-			Description: "Create a new file or update an existing file in a GitLab repository",
+			Description: "Create a new file or update an existing file in a GitLab repository. Pass the last_commit_id from get_file_contents to fail with a structured conflict (current blob and commit IDs) instead of overwriting changes made since.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -197,16 +265,20 @@ func registerCreateOrUpdateFile(server *mcp.Server) {
 						Type:        "string",
 						Description: "The commit author's name (optional)",
 					},
+					"last_commit_id": {
+						Type:        "string",
+						Description: "The file's last_commit_id the edit is based on (optional). The update is refused with a conflict if the file changed or was deleted since",
+					},
 				},
 				Required: []string{"project_id", "file_path", "content", "branch", "commit_message"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("create_or_update_file", args)
+			c.Logger.ToolCall(ctx, "create_or_update_file", args)
 
 			// Extract required parameters
 			projectID := GetString(args, "project_id", "")
@@ -235,6 +307,7 @@ func registerCreateOrUpdateFile(server *mcp.Server) {
 			// Extract optional parameters
 			authorEmail := GetString(args, "author_email", "")
 			authorName := GetString(args, "author_name", "")
+			lastCommitID := GetString(args, "last_commit_id", "")
 
 			// Build the endpoint with URL-encoded project_id and file_path
 			encodedProjectID := url.PathEscape(projectID)
@@ -245,15 +318,22 @@ func registerCreateOrUpdateFile(server *mcp.Server) {
 			checkEndpoint := fmt.Sprintf("%s?ref=%s", endpoint, url.QueryEscape(branch))
 			var existingFile FileResponse
 			fileExists := true
-			if err := ctx.Client.Get(checkEndpoint, &existingFile); err != nil {
+			if err := c.Client.Get(ctx, checkEndpoint, &existingFile); err != nil {
 				if gitlab.IsNotFound(err) {
 					fileExists = false
+				} else if lastCommitID != "" {
+					// A conflict check needs the current file
+					return APIErrorResult("Failed to check file", err)
 				} else {
 					// For other errors, assume file doesn't exist and try to create
 					fileExists = false
 				}
 			}
 
+			if lastCommitID != "" && (!fileExists || existingFile.LastCommitID != lastCommitID) {
+				return fileConflictResult(filePath, branch, lastCommitID, existingFile, !fileExists)
+			}
+
 			// Encode content as base64
 			encodedContent := base64.StdEncoding.EncodeToString([]byte(content))
 
@@ -271,6 +351,9 @@ func registerCreateOrUpdateFile(server *mcp.Server) {
 			if authorName != "" {
 				requestBody["author_name"] = authorName
 			}
+			if lastCommitID != "" {
+				requestBody["last_commit_id"] = lastCommitID
+			}
 
 			var response FileCreateUpdateResponse
 			var action string
@@ -278,14 +361,20 @@ func registerCreateOrUpdateFile(server *mcp.Server) {
 			if fileExists {
 				// Update existing file with PUT
 				action = "updated"
-				if err := ctx.Client.Put(endpoint, requestBody, &response); err != nil {
-					return ErrorResult(fmt.Sprintf("Failed to update file: %v", err))
+				if err := c.Client.Put(ctx, endpoint, requestBody, &response); err != nil {
+					// The file changed between the check above and the update
+					if lastCommitID != "" && isFileChangedError(err) {
+						var current FileResponse
+						getErr := c.Client.Get(ctx, checkEndpoint, &current)
+						return fileConflictResult(filePath, branch, lastCommitID, current, gitlab.IsNotFound(getErr))
+					}
+					return APIErrorResult("Failed to update file", err)
 				}
 			} else {
 				// Create new file with POST
 				action = "created"
-				if err := ctx.Client.Post(endpoint, requestBody, &response); err != nil {
-					return ErrorResult(fmt.Sprintf("Failed to create file: %v", err))
+				if err := c.Client.Post(ctx, endpoint, requestBody, &response); err != nil {
+					return APIErrorResult("Failed to create file", err)
 				}
 			}
 
@@ -302,12 +391,97 @@ func registerCreateOrUpdateFile(server *mcp.Server) {
 	)
 }
 
+// commitActionsProperty is the input schema of a list of commit actions, shared
+// by push_files and propose_change.
+var commitActionsProperty = mcp.Property{
+	Type:        "array",
+	Description: "Array of file actions to perform",
+	Items: &mcp.Property{
+		Type: "object",
+		Properties: map[string]mcp.Property{
+			"action": {
+				Type:        "string",
+				Description: "The action to perform: create, update, delete, move, or chmod",
+				Enum:        []string{"create", "update", "delete", "move", "chmod"},
+			},
+			"file_path": {
+				Type:        "string",
+				Description: "The path of the file (the new path for move)",
+			},
+			"content": {
+				Type:        "string",
+				Description: "The file content (required for create and update; optional for move, where omitting it keeps the content)",
+			},
+			"previous_path": {
+				Type:        "string",
+				Description: "The original path of the file (required for move)",
+			},
+			"execute_filemode": {
+				Type:        "boolean",
+				Description: "Whether the file is executable (required for chmod)",
+			},
+			"last_commit_id": {
+				Type:        "string",
+				Description: "Last known commit ID of the file; the commit fails if the file changed since (optional, for update, delete, move and chmod)",
+			},
+		},
+	},
+}
+
+// isFileChangedError reports whether err is GitLab refusing a file update
+// because last_commit_id no longer matches the file.
+func isFileChangedError(err error) bool {
+	var apiErr *gitlab.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusConflict {
+		return true
+	}
+	return apiErr.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(apiErr.Message), "has changed")
+}
+
+// fileConflictResult creates an error CallToolResult for an edit based on a
+// stale last_commit_id. Like APIErrorResult, the first content item is text and
+// the second carries a FileConflict as JSON of the form {"conflict": {...}}.
+func fileConflictResult(filePath, branch, expected string, current FileResponse, deleted bool) (*mcp.CallToolResult, error) {
+	conflict := FileConflict{
+		FilePath:             filePath,
+		Branch:               branch,
+		ExpectedLastCommitID: expected,
+		Deleted:              deleted,
+		Hint:                 "re-read the file with get_file_contents, merge your change into the current content and retry with its last_commit_id",
+	}
+	message := fmt.Sprintf("Conflict: %s on %s changed after commit %s", filePath, branch, expected)
+	if deleted {
+		message = fmt.Sprintf("Conflict: %s on %s was deleted after commit %s", filePath, branch, expected)
+	} else {
+		conflict.CurrentLastCommitID = current.LastCommitID
+		conflict.CurrentBlobID = current.BlobID
+		conflict.CurrentContentSHA256 = current.ContentSHA256
+		if current.LastCommitID != "" {
+			message += fmt.Sprintf(" (now at %s)", current.LastCommitID)
+		}
+	}
+
+	result, _ := ErrorResult(message)
+	jsonBytes, err := json.Marshal(map[string]FileConflict{"conflict": conflict})
+	if err != nil {
+		return result, nil
+	}
+	result.Content = append(result.Content, mcp.ContentItem{
+		Type: "text",
+		Text: string(jsonBytes),
+	})
+	return result, nil
+}
+
 // registerPushFiles registers the push_files tool.
 func registerPushFiles(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "push_files",
-			Description: "Push multiple files to a GitLab repository in a single commit",
+			Description: "Push multiple file changes to a GitLab repository in a single commit: create, update, delete, move (rename, optionally with new content) and chmod (set the executable bit). Set last_commit_id on an action to fail the commit instead of overwriting a file someone changed since.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -323,27 +497,7 @@ func registerPushFiles(server *mcp.Server) {
 						Type:        "string",
 						Description: "The commit message",
 					},
-					"actions": {
-						Type:        "array",
-						Description: "Array of file actions to perform",
-						Items: &mcp.Property{
-							Type: "object",
-							Properties: map[string]mcp.Property{
-								"action": {
-									Type:        "string",
-									Description: "The action to perform: create, update, or delete",
-								},
-								"file_path": {
-									Type:        "string",
-									Description: "The path of the file",
-								},
-								"content": {
-									Type:        "string",
-									Description: "The file content (not required for delete action)",
-								},
-							},
-						},
-					},
+					"actions": commitActionsProperty,
 					"author_email": {
 						Type:        "string",
 						Description: "The commit author's email address (optional)",
@@ -356,12 +510,12 @@ func registerPushFiles(server *mcp.Server) {
 				Required: []string{"project_id", "branch", "commit_message", "actions"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("push_files", args)
+			c.Logger.ToolCall(ctx, "push_files", args)
 
 			// Extract required parameters
 			projectID := GetString(args, "project_id", "")
@@ -391,16 +545,10 @@ func registerPushFiles(server *mcp.Server) {
 			// Parse actions
 			actions, err := parseCommitActions(actionsRaw)
 			if err != nil {
-				return ErrorResult(fmt.Sprintf("Invalid actions parameter: %v", err))
+				return APIErrorResult("Invalid actions parameter", err)
 			}
 
-			// Encode content for each action that has content
-			for i := range actions {
-				if actions[i].Content != "" && actions[i].Action != "delete" {
-					actions[i].Content = base64.StdEncoding.EncodeToString([]byte(actions[i].Content))
-					actions[i].Encoding = "base64"
-				}
-			}
+			encodeCommitActions(actions)
 
 			// Build the endpoint with URL-encoded project_id
 			encodedProjectID := url.PathEscape(projectID)
@@ -416,8 +564,8 @@ func registerPushFiles(server *mcp.Server) {
 			}
 
 			var response CommitResponse
-			if err := ctx.Client.Post(endpoint, commitRequest, &response); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to push files: %v", err))
+			if err := c.Client.Post(ctx, endpoint, commitRequest, &response); err != nil {
+				return APIErrorResult("Failed to push files", err)
 			}
 
 			// Build response
@@ -445,29 +593,21 @@ func registerUploadMarkdown(server *mcp.Server) {
 			Description: "Upload a file to a GitLab project and get a markdown link for use in issues/MRs",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
-				Properties: map[string]mcp.Property{
+				Properties: withUploadFileProperties(map[string]mcp.Property{
 					"project_id": {
 						Type:        "string",
 						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
 					},
-					"file": {
-						Type:        "string",
-						Description: "The file content encoded as base64",
-					},
-					"filename": {
-						Type:        "string",
-						Description: "The name of the file to upload",
-					},
-				},
-				Required: []string{"project_id", "file", "filename"},
+				}),
+				Required: []string{"project_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("upload_markdown", args)
+			c.Logger.ToolCall(ctx, "upload_markdown", args)
 
 			// Extract required parameters
 			projectID := GetString(args, "project_id", "")
@@ -475,37 +615,20 @@ func registerUploadMarkdown(server *mcp.Server) {
 				return ErrorResult("project_id is required")
 			}
 
-			fileContent := GetString(args, "file", "")
-			if fileContent == "" {
-				return ErrorResult("file is required")
-			}
-
-			filename := GetString(args, "filename", "")
-			if filename == "" {
-				return ErrorResult("filename is required")
-			}
-
-			// Decode base64 file content
-			decodedContent, err := base64.StdEncoding.DecodeString(fileContent)
+			file, closer, err := openUpload(c, args, "file")
 			if err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to decode file content: %v", err))
+				return ErrorResult(err.Error())
 			}
+			defer closer.Close()
 
 			// Build the endpoint with URL-encoded project_id
 			encodedProjectID := url.PathEscape(projectID)
 			endpoint := fmt.Sprintf("/projects/%s/uploads", encodedProjectID)
 
-			// Prepare request body
-			// Note: GitLab's uploads API typically requires multipart/form-data
-			// For simplicity, we'll use a JSON body approach that some GitLab versions support
-			requestBody := map[string]interface{}{
-				"file":     base64.StdEncoding.EncodeToString(decodedContent),
-				"filename": filename,
-			}
-
+			// The uploads API only accepts multipart/form-data
 			var response UploadResponse
-			if err := ctx.Client.Post(endpoint, requestBody, &response); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to upload file: %v", err))
+			if err := c.Client.PostMultipart(ctx, endpoint, nil, file, &response); err != nil {
+				return APIErrorResult("Failed to upload file", err)
 			}
 
 			// Build response
@@ -522,12 +645,111 @@ func registerUploadMarkdown(server *mcp.Server) {
 }
 
 // RegisterFileTools registers all file-related tools with the MCP server.
-// Includes: get_file_contents, create_or_update_file, push_files, upload_markdown
+// Includes: get_file_contents, create_or_update_file, push_files, upload_markdown,
+// propose_change, apply_patch, bump_dependency, get_submodules,
+// get_files_matching
 func RegisterFileTools(server *mcp.Server) {
 	registerGetFileContents(server)
 	registerCreateOrUpdateFile(server)
 	registerPushFiles(server)
 	registerUploadMarkdown(server)
+	registerProposeChange(server)
+	registerApplyPatch(server)
+	registerBumpDependency(server)
+	registerGetSubmodules(server)
+	registerGetFilesMatching(server)
+}
+
+// maxSymlinkHops bounds how many symlinks in a chain get_file_contents
+// follows, so a cycle cannot loop forever.
+const maxSymlinkHops = 8
+
+// symlinkResolution is the mode of a file read with follow_symlinks and, for
+// a symlink, the file it resolves to. ResolvedPath is empty when the link
+// could not be followed; Note says why.
+type symlinkResolution struct {
+	Mode         string
+	Target       string
+	ResolvedPath string
+	Note         string
+	file         FileResponse
+	content      []byte
+}
+
+// treeMode returns the git mode of a file from the tree listing of its
+// directory, or "" if the file is not listed there.
+func treeMode(ctx context.Context, client gitlab.API, encodedProjectID, filePath, ref string) (string, error) {
+	params := url.Values{}
+	params.Set("ref", ref)
+	if dir := path.Dir(filePath); dir != "." {
+		params.Set("path", dir)
+	}
+	mode := ""
+	_, err := streamPages(ctx, client, fmt.Sprintf("/projects/%s/repository/tree?%s", encodedProjectID, params.Encode()), maxCollectedItems, func(node gitlab.TreeNode) {
+		if node.Path == filePath {
+			mode = node.Mode
+		}
+	})
+	return mode, err
+}
+
+// followSymlink looks up the mode of a file read from the files API and, if
+// it is a symlink (whose content is the target path), reads the files the
+// chain of links points to.
+func followSymlink(ctx context.Context, client gitlab.API, encodedProjectID, filePath, ref, content string) (*symlinkResolution, error) {
+	mode, err := treeMode(ctx, client, encodedProjectID, filePath, ref)
+	if err != nil {
+		return nil, err
+	}
+	link := &symlinkResolution{Mode: mode}
+	if mode != fileModeSymlink {
+		return link, nil
+	}
+	link.Target = content
+
+	current, target := filePath, content
+	for hop := 0; hop < maxSymlinkHops; hop++ {
+		resolved := path.Clean(path.Join(path.Dir(current), target))
+		if path.IsAbs(target) || resolved == ".." || strings.HasPrefix(resolved, "../") {
+			link.Note = fmt.Sprintf("symlink %s points outside the repository (%s)", current, target)
+			return link, nil
+		}
+		var file FileResponse
+		endpoint := fmt.Sprintf("/projects/%s/repository/files/%s?ref=%s", encodedProjectID, url.PathEscape(resolved), url.QueryEscape(ref))
+		if err := client.Get(ctx, endpoint, &file); err != nil {
+			if gitlab.IsNotFound(err) {
+				link.Note = fmt.Sprintf("symlink %s points to %s, which is not a file at %s", current, resolved, ref)
+				return link, nil
+			}
+			return nil, err
+		}
+		decoded, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			return nil, err
+		}
+		mode, err := treeMode(ctx, client, encodedProjectID, resolved, ref)
+		if err != nil {
+			return nil, err
+		}
+		if mode != fileModeSymlink {
+			link.ResolvedPath, link.file, link.content = resolved, file, decoded
+			return link, nil
+		}
+		current, target = resolved, string(decoded)
+	}
+	link.Note = fmt.Sprintf("gave up after %d chained symlinks", maxSymlinkHops)
+	return link, nil
+}
+
+// encodeCommitActions base64-encodes the content of each action that has
+// content, so binary-unsafe characters survive the JSON request.
+func encodeCommitActions(actions []CommitAction) {
+	for i := range actions {
+		if actions[i].Content != "" && actions[i].Action != "delete" {
+			actions[i].Content = base64.StdEncoding.EncodeToString([]byte(actions[i].Content))
+			actions[i].Encoding = "base64"
+		}
+	}
 }
 
 // parseCommitActions parses the actions parameter into a slice of CommitAction.
@@ -560,13 +782,39 @@ func parseCommitActions(actionsRaw interface{}) ([]CommitAction, error) {
 		}
 		action.FilePath = filePath
 
-		// Get content (optional for delete)
+		switch actionType {
+		case "create", "update", "delete", "move", "chmod":
+		default:
+			return nil, fmt.Errorf("action at index %d has unknown action %q (expected create, update, delete, move or chmod)", i, actionType)
+		}
+
+		// Get content (required for create and update, optional for move)
 		if content, ok := actionMap["content"].(string); ok {
 			action.Content = content
-		} else if actionType != "delete" {
+		} else if actionType == "create" || actionType == "update" {
 			return nil, fmt.Errorf("action at index %d missing required 'content' field for %s action", i, actionType)
 		}
 
+		if actionType == "move" {
+			previousPath, ok := actionMap["previous_path"].(string)
+			if !ok || previousPath == "" {
+				return nil, fmt.Errorf("action at index %d missing required 'previous_path' field for move action", i)
+			}
+			action.PreviousPath = previousPath
+		}
+
+		if actionType == "chmod" {
+			executable, ok := actionMap["execute_filemode"].(bool)
+			if !ok {
+				return nil, fmt.Errorf("action at index %d missing required 'execute_filemode' field for chmod action", i)
+			}
+			action.ExecuteFilemode = &executable
+		}
+
+		if lastCommitID, ok := actionMap["last_commit_id"].(string); ok {
+			action.LastCommitID = lastCommitID
+		}
+
 		actions = append(actions, action)
 	}
 
@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+const (
+	// maxTreeEntries caps how many tree entries get_files_matching scans, so
+	// a glob over a huge monorepo stays bounded.
+	maxTreeEntries = 20000
+	// defaultMatchingFiles is how many matching files are fetched by default.
+	defaultMatchingFiles = 20
+	// defaultMatchingBytes is the default content budget of one response.
+	defaultMatchingBytes = 200000
+)
+
+// MatchedFile is a file returned by get_files_matching. Size is the full
+// size of the file; Truncated is set when Content holds only its beginning.
+// A symlink has no content, only its target.
+type MatchedFile struct {
+	Path          string `json:"path"`
+	Size          int    `json:"size"`
+	Content       string `json:"content"`
+	Truncated     bool   `json:"truncated,omitempty"`
+	Binary        bool   `json:"binary,omitempty"`
+	Executable    bool   `json:"executable,omitempty"`
+	SymlinkTarget string `json:"symlink_target,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// FilesMatching is the response of the get_files_matching tool. Matched
+// counts all matching files; only the first max_files are in Files.
+type FilesMatching struct {
+	Glob      string `json:"glob"`
+	Ref       string `json:"ref,omitempty"`
+	Matched   int    `json:"matched"`
+	Returned  int    `json:"returned"`
+	BytesUsed int    `json:"bytes_used"`
+	// TreeComplete is false when the tree had more than maxTreeEntries
+	// entries and some files were not considered
+	TreeComplete bool          `json:"tree_complete"`
+	Files        []MatchedFile `json:"files"`
+}
+
+// globBaseDir returns the directory a glob is confined to, the segments
+// before the first one with a wildcard, or "" for the whole repository.
+func globBaseDir(pattern string) string {
+	segments := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	var base []string
+	for _, segment := range segments[:len(segments)-1] {
+		if strings.ContainsAny(segment, "*?") {
+			break
+		}
+		base = append(base, segment)
+	}
+	return strings.Join(base, "/")
+}
+
+// cutUTF8 cuts s to at most n bytes without splitting a UTF-8 sequence.
+func cutUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := s[:n]
+	for len(cut) > 0 && !utf8.ValidString(cut) {
+		cut = cut[:len(cut)-1]
+	}
+	return cut
+}
+
+type getFilesMatchingArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	Glob      string `json:"glob" validate:"required"`
+	Ref       string `json:"ref"`
+	MaxFiles  int    `json:"max_files" validate:"min=1,max=100"`
+	MaxBytes  int    `json:"max_bytes" validate:"min=1,max=1000000"`
+}
+
+// registerGetFilesMatching registers the get_files_matching tool.
+func registerGetFilesMatching(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_files_matching",
+			Description: "Get the contents of all files matching a glob in one call, e.g. every *.tf file or every Dockerfile, instead of listing the tree and fetching files one by one. Files are returned in tree order up to max_files, sharing a budget of max_bytes: a file that does not fit is cut and marked truncated, and later files come back empty and truncated. Binary files are listed without content, and symlinks with only their target. matched counts all matching files; when it exceeds returned, narrow the glob.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"glob": {
+						Type:        "string",
+						Description: "Glob matched against file paths: * and ? stay within a directory, ** spans directories (e.g., **/*.tf or services/*/Dockerfile). A glob without a slash matches the file name in any directory",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Branch name, tag, or commit SHA (default: default branch)",
+					},
+					"max_files": {
+						Type:        "integer",
+						Description: "Most files whose contents are returned (max 100). Default: 20",
+						Default:     defaultMatchingFiles,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+					"max_bytes": {
+						Type:        "integer",
+						Description: "Total bytes of content returned across all files (max 1000000). Default: 200000",
+						Default:     defaultMatchingBytes,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(1000000),
+					},
+				},
+				Required: []string{"project_id", "glob"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_files_matching", func(ctx context.Context, c *ToolContext, args getFilesMatchingArgs) (*mcp.CallToolResult, error) {
+			glob, err := globRegexp(args.Glob)
+			if err != nil {
+				return ErrorResult(fmt.Sprintf("invalid glob %q: %v", args.Glob, err))
+			}
+			maxFiles := args.MaxFiles
+			if maxFiles == 0 {
+				maxFiles = defaultMatchingFiles
+			}
+			budget := args.MaxBytes
+			if budget == 0 {
+				budget = defaultMatchingBytes
+			}
+			encodedProjectID := url.PathEscape(args.ProjectID)
+
+			params := url.Values{}
+			params.Set("recursive", "true")
+			if args.Ref != "" {
+				params.Set("ref", args.Ref)
+			}
+			if base := globBaseDir(args.Glob); base != "" {
+				params.Set("path", base)
+			}
+			var matches []gitlab.TreeNode
+			complete, err := streamPages(ctx, c.Client, fmt.Sprintf("/projects/%s/repository/tree?%s", encodedProjectID, params.Encode()), maxTreeEntries, func(node gitlab.TreeNode) {
+				if node.Type == "blob" && glob.MatchString(node.Path) {
+					matches = append(matches, node)
+				}
+			})
+			if err != nil && !gitlab.IsNotFound(err) {
+				return APIErrorResult("Failed to get repository tree", err)
+			}
+
+			result := FilesMatching{
+				Glob:         args.Glob,
+				Ref:          args.Ref,
+				Matched:      len(matches),
+				TreeComplete: complete,
+				Files:        []MatchedFile{},
+			}
+			matches = matches[:min(len(matches), maxFiles)]
+
+			ref := args.Ref
+			if ref == "" {
+				ref = "HEAD"
+			}
+			for i, node := range matches {
+				mcp.ReportProgress(ctx, float64(i), float64(len(matches)), "Fetching "+node.Path)
+				file := MatchedFile{Path: node.Path, Executable: node.Mode == fileModeExecutable}
+				content, err := c.Client.GetText(ctx, fmt.Sprintf("/projects/%s/repository/files/%s/raw?ref=%s", encodedProjectID, url.PathEscape(node.Path), url.QueryEscape(ref)))
+				switch {
+				case err != nil:
+					file.Error = err.Error()
+				case node.Mode == fileModeSymlink:
+					file.SymlinkTarget = content
+				case !utf8.ValidString(content) || strings.ContainsRune(content, 0):
+					file.Size = len(content)
+					file.Binary = true
+				default:
+					file.Size = len(content)
+					file.Content = cutUTF8(content, budget-result.BytesUsed)
+					file.Truncated = len(file.Content) < len(content)
+					result.BytesUsed += len(file.Content)
+				}
+				result.Files = append(result.Files, file)
+			}
+			result.Returned = len(result.Files)
+			return JSONResult(result)
+		}),
+	)
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGetFilesMatching(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/42/repository/tree?path=infra&recursive=true&ref=main&page=1&per_page=100", 200, `[
+		{"id": "1", "name": "main.tf", "type": "blob", "path": "infra/main.tf"},
+		{"id": "2", "name": "prod", "type": "tree", "path": "infra/prod"},
+		{"id": "3", "name": "vars.tf", "type": "blob", "path": "infra/prod/vars.tf"},
+		{"id": "4", "name": "logo.tf", "type": "blob", "path": "infra/prod/logo.tf"},
+		{"id": "5", "name": "README.md", "type": "blob", "path": "infra/prod/README.md"},
+		{"id": "6", "name": "extra.tf", "type": "blob", "path": "infra/prod/extra.tf"}
+	]`)
+	client.Handle("GET", "/projects/42/repository/files/infra%2Fmain.tf/raw?ref=main", 200, `terraform {}`)
+	client.Handle("GET", "/projects/42/repository/files/infra%2Fprod%2Fvars.tf/raw?ref=main", 200, `variable "region" {}`)
+	client.Handle("GET", "/projects/42/repository/files/infra%2Fprod%2Flogo.tf/raw?ref=main", 200, "\x00\x01binary")
+
+	result := callTool(t, tc, "get_files_matching", map[string]interface{}{
+		"project_id": "42", "glob": "infra/**/*.tf", "ref": "main", "max_files": 3, "max_bytes": 20,
+	})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	var got FilesMatching
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []MatchedFile{
+		{Path: "infra/main.tf", Size: 12, Content: "terraform {}"},
+		{Path: "infra/prod/vars.tf", Size: 20, Content: `variable`, Truncated: true},
+		{Path: "infra/prod/logo.tf", Size: 8, Binary: true},
+	}
+	if !reflect.DeepEqual(got.Files, want) {
+		t.Errorf("files = %+v\nwant %+v", got.Files, want)
+	}
+	if got.Matched != 4 || got.Returned != 3 || got.BytesUsed != 20 || !got.TreeComplete {
+		t.Errorf("matched = %d, returned = %d, bytes used = %d, tree complete = %v", got.Matched, got.Returned, got.BytesUsed, got.TreeComplete)
+	}
+	if unmatched := client.Unmatched(); len(unmatched) > 0 {
+		t.Errorf("unmatched requests: %v", unmatched)
+	}
+}
+
+func TestGlobBaseDir(t *testing.T) {
+	tests := []struct {
+		glob string
+		want string
+	}{
+		{"*.tf", ""},
+		{"**/*.tf", ""},
+		{"infra/**/*.tf", "infra"},
+		{"/infra/prod/*.tf", "infra/prod"},
+		{"services/*/Dockerfile", "services"},
+		{"services/api?/Dockerfile", "services"},
+		{"docs/README.md", "docs"},
+	}
+	for _, tt := range tests {
+		if got := globBaseDir(tt.glob); got != tt.want {
+			t.Errorf("globBaseDir(%q) = %q, want %q", tt.glob, got, tt.want)
+		}
+	}
+}
+
+func TestCutUTF8(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 5, "hello"},
+		{"hello", 3, "hel"},
+		{"héllo", 2, "h"},
+		{"héllo", 3, "hé"},
+		{"日本", 5, "日"},
+		{"日本", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := cutUTF8(tt.s, tt.n); got != tt.want {
+			t.Errorf("cutUTF8(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestGetFilesMatchingEdgeCases(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/42/repository/tree?recursive=true&page=1&per_page=100", 200, `[
+		{"id": "1", "name": "Dockerfile", "type": "blob", "path": "Dockerfile"},
+		{"id": "2", "name": "Dockerfile", "type": "blob", "path": "api/Dockerfile", "mode": "120000"},
+		{"id": "3", "name": "Dockerfile", "type": "blob", "path": "web/Dockerfile"},
+		{"id": "4", "name": "Dockerfile", "type": "blob", "path": "worker/Dockerfile"}
+	]`)
+	// Without a ref, files are read at HEAD
+	client.Handle("GET", "/projects/42/repository/files/Dockerfile/raw?ref=HEAD", 200, "FROM alpine")
+	client.Handle("GET", "/projects/42/repository/files/api%2FDockerfile/raw?ref=HEAD", 200, "../Dockerfile")
+	client.Handle("GET", "/projects/42/repository/files/web%2FDockerfile/raw?ref=HEAD", 403, `{"message": "403 Forbidden"}`)
+	client.Handle("GET", "/projects/42/repository/files/worker%2FDockerfile/raw?ref=HEAD", 200, "FROM golang")
+
+	// A glob without a slash matches file names in any directory
+	result := callTool(t, tc, "get_files_matching", map[string]interface{}{"project_id": "42", "glob": "Dockerfile", "max_bytes": 11})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	var got FilesMatching
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Files) != 4 {
+		t.Fatalf("files = %+v, want 4", got.Files)
+	}
+	if f := got.Files[0]; f.Content != "FROM alpine" || f.Truncated {
+		t.Errorf("first file = %+v, want its whole content", f)
+	}
+	if f := got.Files[1]; f.SymlinkTarget != "../Dockerfile" || f.Content != "" || f.Size != 0 {
+		t.Errorf("symlink = %+v, want only its target", f)
+	}
+	if f := got.Files[2]; f.Error == "" || f.Content != "" {
+		t.Errorf("unreadable file = %+v, want its error", f)
+	}
+	// The budget is spent, so the last file comes back empty
+	if f := got.Files[3]; f.Content != "" || !f.Truncated || f.Size != 11 {
+		t.Errorf("file over the budget = %+v, want empty and truncated", f)
+	}
+	if got.BytesUsed != 11 {
+		t.Errorf("bytes used = %d, want 11", got.BytesUsed)
+	}
+}
+
+func TestGetFilesMatchingMissingDirectory(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/42/repository/tree?path=infra&recursive=true&page=1&per_page=100", 404, `{"message": "404 Tree Not Found"}`)
+
+	result := callTool(t, tc, "get_files_matching", map[string]interface{}{"project_id": "42", "glob": "infra/*.tf"})
+	if result.IsError {
+		t.Fatalf("a missing directory failed: %s", result.Content[0].Text)
+	}
+	var got FilesMatching
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Matched != 0 || len(got.Files) != 0 {
+		t.Errorf("result = %+v, want no files", got)
+	}
+}
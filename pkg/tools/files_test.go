@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/gitlabtest"
+)
+
+// racingClient runs beforePut ahead of each PUT, to change the fake between
+// a tool's read and its write.
+type racingClient struct {
+	*gitlabtest.Client
+	beforePut func()
+}
+
+func (c racingClient) Put(ctx context.Context, endpoint string, body, result interface{}) error {
+	c.beforePut()
+	return c.Client.Put(ctx, endpoint, body, result)
+}
+
+func TestCreateOrUpdateFileConflict(t *testing.T) {
+	const endpoint = "/projects/42/repository/files/README.md"
+	checked := FileResponse{FilePath: "README.md", BlobID: "b1", LastCommitID: "c1"}
+	pushed := FileResponse{FilePath: "README.md", BlobID: "b2", LastCommitID: "c2", ContentSHA256: "5e8f"}
+	tests := []struct {
+		name string
+		// check is the file seen before the update, push replaces it before the PUT
+		check      *FileResponse
+		push       *FileResponse
+		putStatus  int
+		putMessage string
+		wantPut    bool
+		want       FileConflict
+	}{
+		{
+			name:       "409 from GitLab",
+			check:      &checked,
+			push:       &pushed,
+			putStatus:  http.StatusConflict,
+			putMessage: "409 Conflict",
+			wantPut:    true,
+			want:       FileConflict{CurrentLastCommitID: "c2", CurrentBlobID: "b2", CurrentContentSHA256: "5e8f"},
+		},
+		{
+			name:       "400 file has changed",
+			check:      &checked,
+			push:       &pushed,
+			putStatus:  http.StatusBadRequest,
+			putMessage: "You are attempting to update a file that has changed since you started editing it.",
+			wantPut:    true,
+			want:       FileConflict{CurrentLastCommitID: "c2", CurrentBlobID: "b2", CurrentContentSHA256: "5e8f"},
+		},
+		{
+			name:      "deleted before the update",
+			check:     &checked,
+			putStatus: http.StatusConflict,
+			wantPut:   true,
+			want:      FileConflict{Deleted: true},
+		},
+		{
+			name:  "stale before the update",
+			check: &pushed,
+			want:  FileConflict{CurrentLastCommitID: "c2", CurrentBlobID: "b2", CurrentContentSHA256: "5e8f"},
+		},
+		{
+			name: "deleted before the check",
+			want: FileConflict{Deleted: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, client := newTestContext(t)
+			if tt.check != nil {
+				client.Handle(http.MethodGet, endpoint, http.StatusOK, *tt.check)
+			}
+			client.Handle(http.MethodPut, endpoint, tt.putStatus, map[string]string{"message": tt.putMessage})
+			tc.Client = racingClient{Client: client, beforePut: func() {
+				if tt.push != nil {
+					client.Handle(http.MethodGet, endpoint, http.StatusOK, *tt.push)
+				} else {
+					client.Handle(http.MethodGet, endpoint, http.StatusNotFound, `{"message": "404 File Not Found"}`)
+				}
+			}}
+
+			result := callTool(t, tc, "create_or_update_file", map[string]interface{}{
+				"project_id":     "42",
+				"file_path":      "README.md",
+				"branch":         "main",
+				"content":        "new",
+				"commit_message": "Update README",
+				"last_commit_id": "c1",
+			})
+			if !result.IsError || len(result.Content) != 2 || !strings.HasPrefix(result.Content[0].Text, "Conflict: README.md on main") {
+				t.Fatalf("result = %+v, want a conflict error", result.Content)
+			}
+			var got map[string]FileConflict
+			if err := json.Unmarshal([]byte(result.Content[1].Text), &got); err != nil {
+				t.Fatalf("decode conflict: %v", err)
+			}
+			want := tt.want
+			want.FilePath, want.Branch, want.ExpectedLastCommitID = "README.md", "main", "c1"
+			want.Hint = got["conflict"].Hint
+			if !reflect.DeepEqual(got["conflict"], want) || want.Hint == "" {
+				t.Errorf("conflict = %+v, want %+v", got["conflict"], want)
+			}
+
+			var puts int
+			for _, request := range client.Requests() {
+				if request.Method == http.MethodPut {
+					puts++
+					if !strings.Contains(string(request.Body), `"last_commit_id":"c1"`) {
+						t.Errorf("PUT body = %s, want last_commit_id sent", request.Body)
+					}
+				}
+			}
+			if (puts == 1) != tt.wantPut || puts > 1 {
+				t.Errorf("sent %d PUT requests, want an update: %v", puts, tt.wantPut)
+			}
+		})
+	}
+}
+
+func TestCreateOrUpdateFileOtherErrors(t *testing.T) {
+	// Without last_commit_id a 409 is an ordinary API error
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/42/repository/files/README.md", http.StatusOK, FileResponse{LastCommitID: "c1"})
+	client.Handle(http.MethodPut, "/projects/42/repository/files/README.md", http.StatusConflict, `{"message": "409 Conflict"}`)
+
+	result := callTool(t, tc, "create_or_update_file", map[string]interface{}{
+		"project_id":     "42",
+		"file_path":      "README.md",
+		"branch":         "main",
+		"content":        "new",
+		"commit_message": "Update README",
+	})
+	if !result.IsError || strings.Contains(result.Content[len(result.Content)-1].Text, `"conflict"`) {
+		t.Errorf("result = %+v, want a plain API error", result.Content)
+	}
+}
@@ -0,0 +1,271 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// maxInlineFindings caps the findings of one post_inline_findings call.
+const maxInlineFindings = 100
+
+// InlineFinding is one finding to post on a merge request, e.g. a linter
+// result.
+type InlineFinding struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Rule     string `json:"rule,omitempty"`
+}
+
+// PostedFinding is the outcome of posting one finding. Status is "inline"
+// (a positioned draft note), "general" (a draft note without position, for
+// lines outside the diff), "skipped" or "failed".
+type PostedFinding struct {
+	Index       int    `json:"index"`
+	Path        string `json:"path"`
+	Line        int    `json:"line"`
+	Status      string `json:"status"`
+	DraftNoteID int    `json:"draft_note_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// PostedFindings is the response of the post_inline_findings tool.
+type PostedFindings struct {
+	MergeRequestIID int             `json:"merge_request_iid"`
+	Inline          int             `json:"inline"`
+	General         int             `json:"general"`
+	Skipped         int             `json:"skipped"`
+	Failed          int             `json:"failed"`
+	Published       bool            `json:"published,omitempty"`
+	Findings        []PostedFinding `json:"findings"`
+}
+
+// diffLines maps the new line numbers shown in a file diff to their old line
+// numbers; added lines map to 0.
+type diffLines map[int]int
+
+// parseDiffLines returns the lines of the new file shown in a GitLab file
+// diff, which consists of hunks without file headers.
+func parseDiffLines(diff string) (diffLines, error) {
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	shown := diffLines{}
+	for i := 0; i < len(lines); {
+		if !strings.HasPrefix(lines[i], "@@") {
+			i++
+			continue
+		}
+		hunk, next, err := parseHunk(lines, i)
+		if err != nil {
+			return nil, err
+		}
+		oldLine, newLine := hunk.oldStart, hunk.newStart
+		for _, op := range hunk.ops {
+			switch op.kind {
+			case ' ':
+				shown[newLine] = oldLine
+				oldLine++
+				newLine++
+			case '-':
+				oldLine++
+			case '+':
+				shown[newLine] = 0
+				newLine++
+			}
+		}
+		i = next
+	}
+	return shown, nil
+}
+
+// findingBody renders a finding as a note, e.g. "**warning** `SA4006`:
+// value of err is never used".
+func findingBody(finding InlineFinding, withLocation bool) string {
+	var sb strings.Builder
+	if finding.Severity != "" {
+		fmt.Fprintf(&sb, "**%s** ", finding.Severity)
+	}
+	if finding.Rule != "" {
+		fmt.Fprintf(&sb, "`%s` ", finding.Rule)
+	}
+	if withLocation {
+		fmt.Fprintf(&sb, "in `%s` line %d", finding.Path, finding.Line)
+	}
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return finding.Message
+	}
+	return text + ": " + finding.Message
+}
+
+type postInlineFindingsArgs struct {
+	ProjectID       string          `json:"project_id" validate:"required"`
+	MergeRequestIID int             `json:"merge_request_iid" validate:"required"`
+	Findings        []InlineFinding `json:"findings" validate:"required"`
+	OutsideDiff     string          `json:"outside_diff" validate:"oneof=general skip"`
+	Publish         bool            `json:"publish"`
+}
+
+// registerPostInlineFindings registers the post_inline_findings tool.
+func registerPostInlineFindings(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "post_inline_findings",
+			Description: "Post findings (e.g. from a linter run on the MR branch) as draft notes on the changed lines of a merge request in one batch. Positions are resolved from the MR's latest diff: a line shown in the diff gets an inline note, other lines a general draft note naming the file and line (or are skipped). Draft notes stay private until published; set publish=true to publish the whole review at once.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"merge_request_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the merge request",
+					},
+					"findings": {
+						Type:        "array",
+						Description: "The findings to post (max 100), each with path, line (in the MR's version of the file) and message",
+						Items: &mcp.Property{
+							Type: "object",
+							Properties: map[string]mcp.Property{
+								"path": {
+									Type:        "string",
+									Description: "File path in the repository (e.g., pkg/api/server.go)",
+								},
+								"line": {
+									Type:        "integer",
+									Description: "Line number in the new version of the file",
+								},
+								"severity": {
+									Type:        "string",
+									Description: "Severity shown in bold (e.g., error, warning, info)",
+								},
+								"message": {
+									Type:        "string",
+									Description: "The finding",
+								},
+								"rule": {
+									Type:        "string",
+									Description: "The linter rule or check ID (optional)",
+								},
+							},
+						},
+					},
+					"outside_diff": {
+						Type:        "string",
+						Description: "What to do with findings on lines not shown in the diff: 'general' posts a draft note naming the file and line, 'skip' drops them. Default: general",
+						Enum:        []string{"general", "skip"},
+					},
+					"publish": {
+						Type:        "boolean",
+						Description: "Publish all of your pending draft notes on the MR after posting (optional, default: false)",
+					},
+				},
+				Required: []string{"project_id", "merge_request_iid", "findings"},
+			},
+		},
+		withArgs("post_inline_findings", func(ctx context.Context, c *ToolContext, args postInlineFindingsArgs) (*mcp.CallToolResult, error) {
+			if len(args.Findings) > maxInlineFindings {
+				return ErrorResult(fmt.Sprintf("at most %d findings can be posted at once", maxInlineFindings))
+			}
+			for i, finding := range args.Findings {
+				if finding.Path == "" || finding.Line < 1 || finding.Message == "" {
+					return ErrorResult(fmt.Sprintf("findings item %d needs a path, a line of at least 1 and a message", i))
+				}
+			}
+
+			mrEndpoint := fmt.Sprintf("/projects/%s/merge_requests/%d", url.PathEscape(args.ProjectID), args.MergeRequestIID)
+			var mr gitlab.MergeRequest
+			if err := c.Client.Get(ctx, mrEndpoint, &mr); err != nil {
+				return APIErrorResult("Failed to get merge request", err)
+			}
+			if mr.DiffRefs == nil {
+				return ErrorResult("the merge request has no diff yet")
+			}
+
+			files := map[string]gitlab.Diff{}
+			shown := map[string]diffLines{}
+			if _, err := streamPages(ctx, c.Client, mrEndpoint+"/diffs", maxCollectedItems, func(diff gitlab.Diff) {
+				if !diff.DeletedFile {
+					files[diff.NewPath] = diff
+				}
+			}); err != nil {
+				return APIErrorResult("Failed to list merge request diffs", err)
+			}
+
+			result := PostedFindings{MergeRequestIID: args.MergeRequestIID, Findings: make([]PostedFinding, 0, len(args.Findings))}
+			for i, finding := range args.Findings {
+				mcp.ReportProgress(ctx, float64(i), float64(len(args.Findings)), fmt.Sprintf("Posting finding %d of %d", i+1, len(args.Findings)))
+				posted := PostedFinding{Index: i, Path: finding.Path, Line: finding.Line}
+
+				body := map[string]interface{}{"note": findingBody(finding, false)}
+				oldLine, inDiff := -1, false
+				if diff, ok := files[finding.Path]; ok {
+					if shown[finding.Path] == nil {
+						lines, err := parseDiffLines(diff.Diff)
+						if err != nil {
+							// A collapsed or truncated diff has no usable lines
+							lines = diffLines{}
+						}
+						shown[finding.Path] = lines
+					}
+					oldLine, inDiff = shown[finding.Path][finding.Line]
+				}
+				switch {
+				case inDiff:
+					position := map[string]interface{}{
+						"position_type": "text",
+						"base_sha":      mr.DiffRefs.BaseSHA,
+						"start_sha":     mr.DiffRefs.StartSHA,
+						"head_sha":      mr.DiffRefs.HeadSHA,
+						"old_path":      files[finding.Path].OldPath,
+						"new_path":      finding.Path,
+						"new_line":      finding.Line,
+					}
+					if oldLine > 0 {
+						position["old_line"] = oldLine
+					}
+					body["position"] = position
+					posted.Status = "inline"
+				case args.OutsideDiff == "skip":
+					posted.Status = "skipped"
+					result.Skipped++
+					result.Findings = append(result.Findings, posted)
+					continue
+				default:
+					body["note"] = findingBody(finding, true)
+					posted.Status = "general"
+				}
+
+				var note DraftNote
+				if err := c.Client.Post(ctx, mrEndpoint+"/draft_notes", body, &note); err != nil {
+					posted.Status = "failed"
+					posted.Error = err.Error()
+					result.Failed++
+				} else if posted.Status == "inline" {
+					posted.DraftNoteID = note.ID
+					result.Inline++
+				} else {
+					posted.DraftNoteID = note.ID
+					result.General++
+				}
+				result.Findings = append(result.Findings, posted)
+			}
+			mcp.ReportProgress(ctx, float64(len(args.Findings)), float64(len(args.Findings)), "Findings posted")
+
+			if args.Publish && result.Inline+result.General > 0 {
+				if err := c.Client.Post(ctx, mrEndpoint+"/draft_notes/bulk_publish", nil, nil); err != nil {
+					return APIErrorResult(fmt.Sprintf("Failed to publish draft notes (%d were created)", result.Inline+result.General), err)
+				}
+				result.Published = true
+			}
+			return JSONResult(result)
+		}),
+	)
+}
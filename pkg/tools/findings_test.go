@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPostInlineFindings(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/42/merge_requests/5", http.StatusOK, map[string]interface{}{
+		"iid":       5,
+		"diff_refs": map[string]interface{}{"base_sha": "base1", "start_sha": "start1", "head_sha": "head1"},
+	})
+	client.Handle(http.MethodGet, "/projects/42/merge_requests/5/diffs", http.StatusOK, []map[string]interface{}{{
+		"old_path": "main.go",
+		"new_path": "main.go",
+		"diff":     "@@ -10,3 +10,4 @@ func main() {\n \tx := 1\n-\ty := 2\n+\ty := 3\n+\tz := 4\n \treturn\n",
+	}})
+	client.Handle(http.MethodPost, "/projects/42/merge_requests/5/draft_notes", http.StatusCreated, map[string]interface{}{"id": 77})
+	client.Handle(http.MethodPost, "/projects/42/merge_requests/5/draft_notes/bulk_publish", http.StatusNoContent, nil)
+
+	result := callTool(t, tc, "post_inline_findings", map[string]interface{}{
+		"project_id":        "42",
+		"merge_request_iid": float64(5),
+		"findings": []interface{}{
+			map[string]interface{}{"path": "main.go", "line": float64(12), "severity": "warning", "rule": "SA4006", "message": "z is never used"},
+			map[string]interface{}{"path": "main.go", "line": float64(13), "severity": "info", "message": "context line"},
+			map[string]interface{}{"path": "main.go", "line": float64(40), "severity": "error", "message": "outside the diff"},
+		},
+		"publish": true,
+	})
+	if result.IsError {
+		t.Fatalf("post_inline_findings failed: %s", resultText(t, result))
+	}
+	var got PostedFindings
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if got.Inline != 2 || got.General != 1 || got.Failed != 0 || !got.Published {
+		t.Errorf("result = %+v", got)
+	}
+
+	var bodies []map[string]interface{}
+	for _, req := range client.Requests() {
+		if req.Endpoint == "/projects/42/merge_requests/5/draft_notes" {
+			var body map[string]interface{}
+			if err := json.Unmarshal(req.Body, &body); err != nil {
+				t.Fatalf("draft note body: %v", err)
+			}
+			bodies = append(bodies, body)
+		}
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("posted %d draft notes, want 3", len(bodies))
+	}
+	added, _ := bodies[0]["position"].(map[string]interface{})
+	if bodies[0]["note"] != "**warning** `SA4006`: z is never used" || added["new_line"] != float64(12) || added["old_line"] != nil || added["head_sha"] != "head1" {
+		t.Errorf("added line note = %v", bodies[0])
+	}
+	contextLine, _ := bodies[1]["position"].(map[string]interface{})
+	if contextLine["new_line"] != float64(13) || contextLine["old_line"] != float64(12) {
+		t.Errorf("context line note = %v", bodies[1])
+	}
+	if bodies[2]["position"] != nil || bodies[2]["note"] != "**error** in `main.go` line 40: outside the diff" {
+		t.Errorf("general note = %v", bodies[2])
+	}
+}
+
+func TestFindingBody(t *testing.T) {
+	tests := []struct {
+		finding      InlineFinding
+		withLocation bool
+		want         string
+	}{
+		{InlineFinding{Message: "unused"}, false, "unused"},
+		{InlineFinding{Severity: "error", Message: "unused"}, false, "**error**: unused"},
+		{InlineFinding{Rule: "G104", Message: "unchecked error"}, false, "`G104`: unchecked error"},
+		{InlineFinding{Path: "a.go", Line: 3, Message: "unused"}, true, "in `a.go` line 3: unused"},
+	}
+	for _, tt := range tests {
+		if got := findingBody(tt.finding, tt.withLocation); got != tt.want {
+			t.Errorf("findingBody(%+v, %t) = %q, want %q", tt.finding, tt.withLocation, got, tt.want)
+		}
+	}
+}
+
+func TestParseDiffLines(t *testing.T) {
+	diff := "@@ -1,3 +1,2 @@\n a\n-b\n c\n@@ -20,2 +19,3 @@ func f() {\n x\n+y\n z\n"
+	got, err := parseDiffLines(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Removed lines are not shown in the new file; added lines have no old line
+	want := diffLines{1: 1, 2: 3, 19: 20, 20: 0, 21: 21}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDiffLines = %v, want %v", got, want)
+	}
+
+	if got, err := parseDiffLines(""); err != nil || len(got) != 0 {
+		t.Errorf("parseDiffLines of an empty diff = %v, %v", got, err)
+	}
+}
+
+func TestPostInlineFindingsEdgeCases(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/42/merge_requests/5", http.StatusOK, map[string]interface{}{
+		"iid":       5,
+		"diff_refs": map[string]interface{}{"base_sha": "base1", "start_sha": "start1", "head_sha": "head1"},
+	})
+	client.Handle(http.MethodGet, "/projects/42/merge_requests/6", http.StatusOK, map[string]interface{}{"iid": 6})
+	client.Handle(http.MethodGet, "/projects/42/merge_requests/5/diffs", http.StatusOK, []map[string]interface{}{
+		{"old_path": "old.go", "new_path": "old.go", "deleted_file": true, "diff": "@@ -1,1 +0,0 @@\n-package old\n"},
+		{"old_path": "big.go", "new_path": "big.go", "diff": ""},
+	})
+	client.Handle(http.MethodPost, "/projects/42/merge_requests/5/draft_notes", http.StatusForbidden, map[string]interface{}{"message": "403 Forbidden"})
+
+	finding := func(path string, line int) map[string]interface{} {
+		return map[string]interface{}{"path": path, "line": line, "severity": "warning", "message": "check this"}
+	}
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+		want    PostedFindings
+	}{
+		{
+			name:    "finding without a line",
+			args:    map[string]interface{}{"merge_request_iid": 5, "findings": []interface{}{finding("a.go", 1), finding("b.go", 0)}},
+			wantErr: "findings item 1 needs a path, a line of at least 1 and a message",
+		},
+		{
+			name:    "merge request without a diff",
+			args:    map[string]interface{}{"merge_request_iid": 6, "findings": []interface{}{finding("a.go", 1)}},
+			wantErr: "the merge request has no diff yet",
+		},
+		{
+			// Deleted and collapsed files have no lines to comment on
+			name: "skipped outside the diff",
+			args: map[string]interface{}{"merge_request_iid": 5, "outside_diff": "skip", "publish": true, "findings": []interface{}{finding("old.go", 1), finding("big.go", 10)}},
+			want: PostedFindings{MergeRequestIID: 5, Skipped: 2},
+		},
+		{
+			name: "failed draft notes",
+			args: map[string]interface{}{"merge_request_iid": 5, "publish": true, "findings": []interface{}{finding("big.go", 10)}},
+			want: PostedFindings{MergeRequestIID: 5, Failed: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["project_id"] = "42"
+			result := callTool(t, tc, "post_inline_findings", tt.args)
+			if tt.wantErr != "" {
+				if !result.IsError || result.Content[0].Text != tt.wantErr {
+					t.Errorf("result = %q, want %q", result.Content[0].Text, tt.wantErr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("post_inline_findings failed: %s", result.Content[0].Text)
+			}
+			var got PostedFindings
+			if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+				t.Fatal(err)
+			}
+			got.Findings = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// Nothing was posted, so nothing was published
+	for _, req := range client.Requests() {
+		if req.Endpoint == "/projects/42/merge_requests/5/draft_notes/bulk_publish" {
+			t.Error("published draft notes although none were created")
+		}
+	}
+}
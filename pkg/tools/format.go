@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// Output formats accepted by the format argument of list tools.
+const (
+	formatJSON          = "json"
+	formatText          = "text"
+	formatMarkdownTable = "markdown-table"
+)
+
+// listFormatProperty is the common format input property added to list tools
+// by ResultMiddleware.
+var listFormatProperty = mcp.Property{
+	Type:        "string",
+	Description: "Output format: 'json' (default), 'text' for one terse line per item, or 'markdown-table'. Columns follow fields if given, otherwise a summary (iid/id, title/name, state/status, ref, author, updated_at).",
+	Enum:        []string{formatJSON, formatText, formatMarkdownTable},
+}
+
+// listToolsWithoutPrefix are list tools whose names do not start with "list_".
+var listToolsWithoutPrefix = map[string]bool{
+	"my_issues":                        true,
+	"my_merge_requests":                true,
+	"search_repositories":              true,
+	"mr_discussions":                   true,
+	"get_users":                        true,
+	"get_project_events":               true,
+	"get_user_contribution_events":     true,
+	"get_commit_refs":                  true,
+	"get_milestone_issues":             true,
+	"get_milestone_merge_requests":     true,
+	"get_merge_request_commits":        true,
+	"get_merge_request_participants":   true,
+	"get_merge_request_closes_issues":  true,
+	"get_issue_related_merge_requests": true,
+	"release_dashboard":                true,
+}
+
+// isListTool reports whether a tool returns a collection and gets the shared
+// format argument.
+func isListTool(name string) bool {
+	return strings.HasPrefix(name, "list_") || listToolsWithoutPrefix[name]
+}
+
+// summaryColumns are the default columns of text and table output. For each
+// group, the first key present in the first item is used.
+var summaryColumns = [][]string{
+	{"iid", "short_id", "id"},
+	{"title", "path_with_namespace", "name", "username"},
+	{"state", "status"},
+	{"ref", "source_branch"},
+	{"author.username", "user.username", "author_name"},
+	{"updated_at", "created_at"},
+}
+
+// formatList renders the items of a JSON list result as text or a markdown
+// table. Items are the elements of a top-level array or of the largest array
+// in an object; pagination info, if present, is appended. It returns "" if
+// data is not a list of objects.
+func formatList(data []byte, format string, fields []string) string {
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return ""
+	}
+
+	var items []interface{}
+	var pagination map[string]interface{}
+	switch v := generic.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		if field := largestField(v); field != "" {
+			items, _ = v[field].([]interface{})
+		}
+		pagination, _ = v["pagination"].(map[string]interface{})
+	}
+	if items == nil {
+		return ""
+	}
+
+	columns := fields
+	if len(columns) == 0 {
+		columns = defaultColumns(items)
+	}
+	if len(columns) == 0 && len(items) > 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	switch {
+	case len(items) == 0:
+	case format == formatMarkdownTable:
+		sb.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+		sb.WriteString(strings.Repeat("|---", len(columns)) + "|\n")
+		for _, item := range items {
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				cells[i] = strings.ReplaceAll(formatCell(lookupPath(item, column)), "|", "\\|")
+			}
+			sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	default:
+		for _, item := range items {
+			parts := make([]string, 0, len(columns))
+			for _, column := range columns {
+				value := formatCell(lookupPath(item, column))
+				if value == "" {
+					continue
+				}
+				if strings.ContainsAny(value, " \t") {
+					value = fmt.Sprintf("%q", value)
+				}
+				parts = append(parts, column+"="+value)
+			}
+			sb.WriteString(strings.Join(parts, " ") + "\n")
+		}
+	}
+
+	if len(items) > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(fmt.Sprintf("%d items", len(items)))
+	if pagination != nil {
+		sb.WriteString(fmt.Sprintf(" | page %s of %s | total %s",
+			formatCell(pagination["page"]), formatCell(pagination["total_pages"]), formatCell(pagination["total"])))
+		if next := formatCell(pagination["next_page"]); next != "" {
+			sb.WriteString(" | next_page " + next)
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// defaultColumns picks the summary columns present in the first object item,
+// falling back to its scalar keys.
+func defaultColumns(items []interface{}) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	first, ok := items[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var columns []string
+	for _, group := range summaryColumns {
+		for _, key := range group {
+			if value := lookupPath(first, key); value != nil {
+				columns = append(columns, key)
+				break
+			}
+		}
+	}
+	if len(columns) > 0 {
+		return columns
+	}
+
+	for key, value := range first {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+		default:
+			columns = append(columns, key)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// lookupPath returns the value at a dotted path in a decoded JSON object.
+func lookupPath(value interface{}, path string) interface{} {
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = obj[key]
+	}
+	return value
+}
+
+// formatCell renders a decoded JSON value on a single line.
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.Join(strings.Fields(v), " ")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, formatCell(item))
+		}
+		return strings.Join(parts, ",")
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
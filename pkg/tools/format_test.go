@@ -0,0 +1,105 @@
+package tools
+
+import "testing"
+
+func TestFormatList(t *testing.T) {
+	issues := `[
+		{"iid": 1, "title": "Fix  login\nredirect", "state": "opened", "author": {"username": "alice"}, "labels": ["bug", "p1"], "updated_at": "2024-05-01"},
+		{"iid": 2, "title": "a | b", "state": "closed", "author": {"username": "bob"}, "labels": [], "updated_at": "2024-05-02"}
+	]`
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		fields []string
+		want   string
+	}{
+		{
+			name:   "text with summary columns",
+			data:   issues,
+			format: formatText,
+			want: `iid=1 title="Fix login redirect" state=opened author.username=alice updated_at=2024-05-01
+iid=2 title="a | b" state=closed author.username=bob updated_at=2024-05-02
+
+2 items
+`,
+		},
+		{
+			name:   "markdown table escapes pipes",
+			data:   issues,
+			format: formatMarkdownTable,
+			want: `| iid | title | state | author.username | updated_at |
+|---|---|---|---|---|
+| 1 | Fix login redirect | opened | alice | 2024-05-01 |
+| 2 | a \| b | closed | bob | 2024-05-02 |
+
+2 items
+`,
+		},
+		{
+			name:   "fields choose the columns",
+			data:   issues,
+			format: formatMarkdownTable,
+			fields: []string{"title", "labels", "milestone.title"},
+			want: `| title | labels | milestone.title |
+|---|---|---|
+| Fix login redirect | bug,p1 |  |
+| a \| b |  |  |
+
+2 items
+`,
+		},
+		{
+			name:   "text omits empty fields",
+			data:   issues,
+			format: formatText,
+			fields: []string{"iid", "labels"},
+			want: `iid=1 labels=bug,p1
+iid=2
+
+2 items
+`,
+		},
+		{
+			name:   "list envelope with pagination",
+			data:   `{"pipelines": [{"id": 10, "status": "success", "ref": "main"}], "pagination": {"page": 1, "total_pages": 3, "total": 25, "next_page": 2}}`,
+			format: formatText,
+			want: `id=10 status=success ref=main
+
+1 items | page 1 of 3 | total 25 | next_page 2
+`,
+		},
+		{
+			name:   "scalar keys without summary columns",
+			data:   `[{"zeta": true, "alpha": 1.5, "nested": {"x": 1}}]`,
+			format: formatText,
+			want: `alpha=1.5 zeta=true
+
+1 items
+`,
+		},
+		{
+			name:   "empty list",
+			data:   `[]`,
+			format: formatMarkdownTable,
+			want:   "0 items\n",
+		},
+		{
+			name:   "not a list",
+			data:   `{"iid": 1}`,
+			format: formatText,
+		},
+		{
+			name:   "list of scalars",
+			data:   `["main", "develop"]`,
+			format: formatText,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatList([]byte(tt.data), tt.format, tt.fields); got != tt.want {
+				t.Errorf("formatList =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// FreezePeriod is a deploy freeze window defined by two cron expressions.
+type FreezePeriod struct {
+	ID           int        `json:"id"`
+	FreezeStart  string     `json:"freeze_start"`
+	FreezeEnd    string     `json:"freeze_end"`
+	CronTimezone string     `json:"cron_timezone"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// ActiveFreeze is a freeze period that is in effect.
+type ActiveFreeze struct {
+	FreezePeriod
+	StartedAt time.Time  `json:"started_at"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+}
+
+// DeployFreezeStatus is the response of the get_deploy_freeze_status tool.
+type DeployFreezeStatus struct {
+	ProjectID  string         `json:"project_id"`
+	CheckedAt  time.Time      `json:"checked_at"`
+	InFreeze   bool           `json:"in_freeze"`
+	Active     []ActiveFreeze `json:"active"`
+	NextFreeze *time.Time     `json:"next_freeze_start,omitempty"`
+	Periods    int            `json:"periods"`
+	Warnings   []string       `json:"warnings,omitempty"`
+}
+
+// cronSearchWindow bounds how far cronSchedule.previous and next search.
+const cronSearchWindow = 366 * 24 * time.Hour
+
+// cronSchedule is a parsed five-field cron expression.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// cronNames maps month and weekday names to numbers.
+var cronNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// parseCron parses a standard five-field cron expression (minute hour
+// day-of-month month day-of-week) with lists, ranges, steps and names. "?"
+// is accepted as "*".
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", expr)
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+	// Day-of-week 7 is Sunday.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+	return &cronSchedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: cronWildcard(fields[2]), dowAny: cronWildcard(fields[4]),
+	}, nil
+}
+
+// cronWildcard reports whether a day field is unrestricted for the
+// day-of-month/day-of-week OR rule. As in cron, a field starting with "*"
+// counts, so "*/1" and "*/2" do while "1-31" does not.
+func cronWildcard(field string) bool {
+	return strings.HasPrefix(field, "*") || strings.HasPrefix(field, "?")
+}
+
+// parseCronField parses one cron field into a bit set of allowed values.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, stepText, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			part, step = base, n
+		}
+		low, high := min, max
+		if part != "*" && part != "?" {
+			lowText, highText, isRange := strings.Cut(part, "-")
+			var err error
+			if low, err = cronValue(lowText); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = cronValue(highText); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+		for v := low; v <= high; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// cronValue parses a cron number or month/weekday name.
+func cronValue(text string) (int, error) {
+	if n, ok := cronNames[strings.ToLower(text)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", text)
+	}
+	return n, nil
+}
+
+// matches reports whether t (at minute precision) fires the schedule.
+func (s *cronSchedule) matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 || s.hour&(1<<uint(t.Hour())) == 0 || s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	// As in cron, a restricted day-of-month and day-of-week match either one.
+	if !s.domAny && !s.dowAny {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// previous returns the latest fire time at or before t.
+func (s *cronSchedule) previous(t time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute)
+	for limit := t.Add(-cronSearchWindow); !t.Before(limit); t = t.Add(-time.Minute) {
+		if s.matches(t) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// next returns the earliest fire time after t.
+func (s *cronSchedule) next(t time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for limit := t.Add(cronSearchWindow); !t.After(limit); t = t.Add(time.Minute) {
+		if s.matches(t) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// registerCreateFreezePeriod registers the create_freeze_period tool.
+func registerCreateFreezePeriod(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "create_freeze_period",
+			Description: "Create a deploy freeze period. During the freeze, GitLab sets CI_DEPLOY_FREEZE in pipelines so deploy jobs can skip themselves.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"freeze_start": {
+						Type:        "string",
+						Description: "Cron expression for the start of the freeze (e.g., '0 23 * * 5' for Friday 23:00)",
+					},
+					"freeze_end": {
+						Type:        "string",
+						Description: "Cron expression for the end of the freeze (e.g., '0 7 * * 1' for Monday 07:00)",
+					},
+					"cron_timezone": {
+						Type:        "string",
+						Description: "IANA timezone of the cron expressions (e.g., Europe/Berlin, default: UTC)",
+					},
+				},
+				Required: []string{"project_id", "freeze_start", "freeze_end"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "create_freeze_period", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			body := map[string]interface{}{}
+			for _, key := range []string{"freeze_start", "freeze_end"} {
+				value := GetString(args, key, "")
+				if value == "" {
+					return ErrorResult(key + " is required")
+				}
+				if _, err := parseCron(value); err != nil {
+					return ErrorResult(fmt.Sprintf("invalid %s: %v", key, err))
+				}
+				body[key] = value
+			}
+			if timezone := GetString(args, "cron_timezone", ""); timezone != "" {
+				body["cron_timezone"] = timezone
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/freeze_periods", url.PathEscape(projectID))
+
+			var period FreezePeriod
+			if err := c.Client.Post(ctx, endpoint, body, &period); err != nil {
+				return APIErrorResult("failed to create freeze period", err)
+			}
+
+			return JSONResult(period)
+		},
+	)
+}
+
+// registerGetDeployFreezeStatus registers the get_deploy_freeze_status tool.
+func registerGetDeployFreezeStatus(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_deploy_freeze_status",
+			Description: "Check whether a project is in a deploy freeze right now by evaluating its freeze periods. Returns in_freeze, the active periods with their start and end, and the next freeze start. Call before playing manual deploy jobs.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"at": {
+						Type:        "string",
+						Description: "Check this time instead of now (RFC 3339, e.g., 2024-12-24T10:00:00Z)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_deploy_freeze_status", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			now := time.Now().UTC()
+			if at := GetString(args, "at", ""); at != "" {
+				parsed, err := time.Parse(time.RFC3339, at)
+				if err != nil {
+					return ErrorResult("at must be an RFC 3339 timestamp")
+				}
+				now = parsed.UTC()
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/freeze_periods", url.PathEscape(projectID))
+			periods, _, err := collectPages[FreezePeriod](ctx, c.Client, endpoint, maxCollectedItems)
+			if err != nil {
+				return APIErrorResult("failed to list freeze periods", err)
+			}
+
+			status := DeployFreezeStatus{
+				ProjectID: projectID,
+				CheckedAt: now,
+				Active:    []ActiveFreeze{},
+				Periods:   len(periods),
+			}
+			for _, period := range periods {
+				location := time.UTC
+				if period.CronTimezone != "" {
+					if loaded, err := time.LoadLocation(period.CronTimezone); err == nil {
+						location = loaded
+					} else {
+						status.Warnings = append(status.Warnings, fmt.Sprintf("freeze period %d: unknown timezone %q, evaluated in UTC", period.ID, period.CronTimezone))
+					}
+				}
+				start, err := parseCron(period.FreezeStart)
+				if err == nil {
+					var end *cronSchedule
+					if end, err = parseCron(period.FreezeEnd); err == nil {
+						local := now.In(location)
+						lastStart, started := start.previous(local)
+						lastEnd, ended := end.previous(local)
+						if started && (!ended || lastStart.After(lastEnd)) {
+							active := ActiveFreeze{FreezePeriod: period, StartedAt: lastStart.UTC()}
+							if endsAt, ok := end.next(local); ok {
+								endsAt = endsAt.UTC()
+								active.EndsAt = &endsAt
+							}
+							status.Active = append(status.Active, active)
+						} else if nextStart, ok := start.next(local); ok {
+							nextStart = nextStart.UTC()
+							if status.NextFreeze == nil || nextStart.Before(*status.NextFreeze) {
+								status.NextFreeze = &nextStart
+							}
+						}
+					}
+				}
+				if err != nil {
+					status.Warnings = append(status.Warnings, fmt.Sprintf("freeze period %d: %v", period.ID, err))
+				}
+			}
+			status.InFreeze = len(status.Active) > 0
+
+			return JSONResult(status)
+		},
+	)
+}
+
+// initFreezePeriodTools registers the deploy freeze period tools.
+func initFreezePeriodTools(server *mcp.Server) {
+	registerListFreezePeriods(server)
+	registerCreateFreezePeriod(server)
+	registerDeleteFreezePeriod(server)
+	registerGetDeployFreezeStatus(server)
+}
@@ -0,0 +1,289 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr       string
+		match      []string
+		noMatch    []string
+		wantDomAny bool
+		wantDowAny bool
+	}{
+		{
+			// Ranges, steps and names
+			expr:       "*/15 9-17 * * mon-fri",
+			match:      []string{"2024-05-06T09:00:00Z", "2024-05-10T17:45:00Z"},
+			noMatch:    []string{"2024-05-06T09:10:00Z", "2024-05-06T18:00:00Z", "2024-05-04T12:00:00Z"},
+			wantDomAny: true,
+		},
+		{
+			// Lists, and a step over a range
+			expr:       "0 0-12/6,20 * jan,jun *",
+			match:      []string{"2024-06-01T00:00:00Z", "2024-06-01T06:00:00Z", "2024-06-01T12:00:00Z", "2024-01-31T20:00:00Z"},
+			noMatch:    []string{"2024-06-01T18:00:00Z", "2024-05-01T00:00:00Z"},
+			wantDomAny: true,
+			wantDowAny: true,
+		},
+		{
+			// A step from a single value runs to the end of the range
+			expr:       "5/20 * * * *",
+			match:      []string{"2024-05-01T10:05:00Z", "2024-05-01T10:25:00Z", "2024-05-01T10:45:00Z"},
+			noMatch:    []string{"2024-05-01T10:00:00Z", "2024-05-01T10:20:00Z"},
+			wantDomAny: true,
+			wantDowAny: true,
+		},
+		{
+			// Both days restricted: either one matches
+			expr:    "0 0 13 * fri",
+			match:   []string{"2024-05-03T00:00:00Z", "2024-05-13T00:00:00Z"},
+			noMatch: []string{"2024-05-14T00:00:00Z"},
+		},
+		{
+			// A range covering every day is still a restriction
+			expr:    "0 0 1-31 * fri",
+			match:   []string{"2024-05-14T00:00:00Z", "2024-05-17T00:00:00Z"},
+			noMatch: []string{"2024-05-14T01:00:00Z"},
+		},
+		{
+			// Wildcards with a step or "?" leave only the other day field
+			expr:       "0 0 */1 * fri",
+			match:      []string{"2024-05-03T00:00:00Z"},
+			noMatch:    []string{"2024-05-13T00:00:00Z", "2024-05-14T00:00:00Z"},
+			wantDomAny: true,
+		},
+		{
+			expr:       "0 0 ? * fri",
+			match:      []string{"2024-05-03T00:00:00Z"},
+			noMatch:    []string{"2024-05-14T00:00:00Z"},
+			wantDomAny: true,
+		},
+		{
+			expr:       "0 0 1 * ?",
+			match:      []string{"2024-06-01T00:00:00Z"},
+			noMatch:    []string{"2024-06-07T00:00:00Z"},
+			wantDowAny: true,
+		},
+		{
+			// Day-of-week 7 is Sunday
+			expr:       "0 0 * * 7",
+			match:      []string{"2024-05-05T00:00:00Z"},
+			noMatch:    []string{"2024-05-06T00:00:00Z"},
+			wantDomAny: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron: %v", err)
+			}
+			if s.domAny != tt.wantDomAny || s.dowAny != tt.wantDowAny {
+				t.Errorf("domAny, dowAny = %v, %v, want %v, %v", s.domAny, s.dowAny, tt.wantDomAny, tt.wantDowAny)
+			}
+			for _, at := range tt.match {
+				if !s.matches(mustParseTime(t, at)) {
+					t.Errorf("does not match %s", at)
+				}
+			}
+			for _, at := range tt.noMatch {
+				if s.matches(mustParseTime(t, at)) {
+					t.Errorf("matches %s", at)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: "0 0 * *", wantErr: "must have 5 fields"},
+		{expr: "60 * * * *", wantErr: "out of range 0-59"},
+		{expr: "0 0 0 * *", wantErr: "out of range 1-31"},
+		{expr: "0 0 * * 8", wantErr: "out of range 0-7"},
+		{expr: "0 5-1 * * *", wantErr: "out of range"},
+		{expr: "*/0 * * * *", wantErr: "invalid step"},
+		{expr: "0 0 * foo *", wantErr: `invalid value "foo"`},
+	}
+	for _, tt := range tests {
+		if _, err := parseCron(tt.expr); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseCron(%q) = %v, want %q", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCronPreviousNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no timezone data: %v", err)
+	}
+	tests := []struct {
+		name         string
+		expr         string
+		location     *time.Location
+		at           string
+		wantPrevious string
+		wantNext     string
+	}{
+		{
+			name:         "weekly",
+			expr:         "0 23 * * 5",
+			location:     time.UTC,
+			at:           "2024-05-08T12:00:00Z",
+			wantPrevious: "2024-05-03T23:00:00Z",
+			wantNext:     "2024-05-10T23:00:00Z",
+		},
+		{
+			// previous includes t, next does not
+			name:         "at a fire time",
+			expr:         "0 23 * * 5",
+			location:     time.UTC,
+			at:           "2024-05-10T23:00:30Z",
+			wantPrevious: "2024-05-10T23:00:00Z",
+			wantNext:     "2024-05-17T23:00:00Z",
+		},
+		{
+			name:         "timezone",
+			expr:         "0 9 * * *",
+			location:     berlin,
+			at:           "2024-05-08T12:00:00Z",
+			wantPrevious: "2024-05-08T07:00:00Z",
+			wantNext:     "2024-05-09T07:00:00Z",
+		},
+		{
+			// Clocks go from 02:00 CET to 03:00 CEST on 31 March
+			name:         "across the spring DST change",
+			expr:         "0 3 * * *",
+			location:     berlin,
+			at:           "2024-03-31T00:30:00Z",
+			wantPrevious: "2024-03-30T02:00:00Z",
+			wantNext:     "2024-03-31T01:00:00Z",
+		},
+		{
+			// Clocks go from 03:00 CEST back to 02:00 CET on 27 October, so
+			// 02:30 happens twice
+			name:         "across the autumn DST change",
+			expr:         "30 2 * * *",
+			location:     berlin,
+			at:           "2024-10-27T02:00:00Z",
+			wantPrevious: "2024-10-27T01:30:00Z",
+			wantNext:     "2024-10-28T01:30:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			at := mustParseTime(t, tt.at).In(tt.location)
+			previous, ok := s.previous(at)
+			if !ok || !previous.Equal(mustParseTime(t, tt.wantPrevious)) {
+				t.Errorf("previous = %v (%v), want %s", previous.UTC(), ok, tt.wantPrevious)
+			}
+			next, ok := s.next(at)
+			if !ok || !next.Equal(mustParseTime(t, tt.wantNext)) {
+				t.Errorf("next = %v (%v), want %s", next.UTC(), ok, tt.wantNext)
+			}
+		})
+	}
+
+	// February 30th never comes
+	s, _ := parseCron("0 0 30 2 *")
+	if _, ok := s.next(mustParseTime(t, "2024-01-01T00:00:00Z")); ok {
+		t.Error("next found a fire time for 30 February")
+	}
+}
+
+func TestGetDeployFreezeStatus(t *testing.T) {
+	periods := `[
+		{"id": 1, "freeze_start": "0 23 * * 5", "freeze_end": "0 7 * * 1", "cron_timezone": "UTC"},
+		{"id": 2, "freeze_start": "0 0 24 12 *", "freeze_end": "0 0 27 12 *", "cron_timezone": "Europe/Berlin"},
+		{"id": 3, "freeze_start": "bad", "freeze_end": "0 7 * * 1"},
+		{"id": 4, "freeze_start": "0 9 * * *", "freeze_end": "0 17 * * *", "cron_timezone": "Mars/Olympus"}
+	]`
+	tests := []struct {
+		name       string
+		at         string
+		wantActive map[int][2]string // period ID to started_at and ends_at
+		wantNext   string
+	}{
+		{
+			name: "weekend freeze",
+			at:   "2024-05-04T12:00:00Z",
+			wantActive: map[int][2]string{
+				1: {"2024-05-03T23:00:00Z", "2024-05-06T07:00:00Z"},
+				4: {"2024-05-04T09:00:00Z", "2024-05-04T17:00:00Z"},
+			},
+			wantNext: "2024-12-23T23:00:00Z",
+		},
+		{
+			name:       "at the start",
+			at:         "2024-05-03T23:00:00Z",
+			wantActive: map[int][2]string{1: {"2024-05-03T23:00:00Z", "2024-05-06T07:00:00Z"}},
+			wantNext:   "2024-05-04T09:00:00Z",
+		},
+		{
+			// The end is not part of the window
+			name:       "at the end",
+			at:         "2024-05-06T07:00:00Z",
+			wantActive: map[int][2]string{},
+			wantNext:   "2024-05-06T09:00:00Z",
+		},
+		{
+			name:       "christmas",
+			at:         "2024-12-25T12:00:00Z",
+			wantActive: map[int][2]string{2: {"2024-12-23T23:00:00Z", "2024-12-26T23:00:00Z"}, 4: {"2024-12-25T09:00:00Z", "2024-12-25T17:00:00Z"}},
+			wantNext:   "2024-12-27T23:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, client := newTestContext(t)
+			client.Handle("GET", "/projects/acme%2Fapi/freeze_periods", 200, periods)
+
+			res := callTool(t, tc, "get_deploy_freeze_status", map[string]interface{}{"project_id": "acme/api", "at": tt.at})
+			if res.IsError {
+				t.Fatalf("unexpected error: %s", resultText(t, res))
+			}
+			var status DeployFreezeStatus
+			if err := json.Unmarshal([]byte(resultText(t, res)), &status); err != nil {
+				t.Fatal(err)
+			}
+			active := map[int][2]string{}
+			for _, a := range status.Active {
+				active[a.ID] = [2]string{a.StartedAt.Format(time.RFC3339), a.EndsAt.Format(time.RFC3339)}
+			}
+			if !reflect.DeepEqual(active, tt.wantActive) {
+				t.Errorf("active = %v, want %v", active, tt.wantActive)
+			}
+			if status.InFreeze != (len(tt.wantActive) > 0) || status.Periods != 4 {
+				t.Errorf("in_freeze = %v, periods = %d", status.InFreeze, status.Periods)
+			}
+			if status.NextFreeze == nil || status.NextFreeze.Format(time.RFC3339) != tt.wantNext {
+				t.Errorf("next freeze = %v, want %s", status.NextFreeze, tt.wantNext)
+			}
+			if len(status.Warnings) != 2 || !strings.Contains(status.Warnings[0], "freeze period 3") || !strings.Contains(status.Warnings[1], `unknown timezone "Mars/Olympus"`) {
+				t.Errorf("warnings = %q", status.Warnings)
+			}
+		})
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
@@ -0,0 +1,434 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// groupWikiIDProperty is the group_id argument shared by the group wiki tools.
+var groupWikiIDProperty = mcp.Property{
+	Type:        "string",
+	Description: "The ID or URL-encoded path of the group. Falls back to GITLAB_DEFAULT_NAMESPACE if not set.",
+}
+
+// groupWikiGroupID returns the group_id argument or the configured default namespace.
+func groupWikiGroupID(c *ToolContext, args map[string]interface{}) string {
+	groupID := GetString(args, "group_id", "")
+	if groupID == "" && c.Config != nil {
+		groupID = c.Config.DefaultNamespace
+	}
+	return groupID
+}
+
+// registerListGroupWikiPages registers the list_group_wiki_pages tool.
+func registerListGroupWikiPages(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_group_wiki_pages",
+			Description: "List all wiki pages of a GitLab group wiki (GitLab Premium). Uses GITLAB_DEFAULT_NAMESPACE if group_id is not provided.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"group_id": groupWikiIDProperty,
+					"with_content": {
+						Type:        "boolean",
+						Description: "Include page content in the response (optional, default: false)",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination (optional, default: 1)",
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of results per page (optional, default: 20, max: 100)",
+					},
+				},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "list_group_wiki_pages", args)
+
+			groupID := groupWikiGroupID(c, args)
+			if groupID == "" {
+				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
+			}
+
+			endpoint := fmt.Sprintf("/groups/%s/wikis", url.PathEscape(groupID))
+
+			// Build query parameters
+			params := url.Values{}
+			if GetBool(args, "with_content", false) {
+				params.Set("with_content", "true")
+			}
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", fmt.Sprintf("%d", page))
+			}
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			}
+
+			if len(params) > 0 {
+				endpoint = fmt.Sprintf("%s?%s", endpoint, params.Encode())
+			}
+
+			var wikiPages []WikiPage
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &wikiPages)
+			if err != nil {
+				return APIErrorResult("Failed to list group wiki pages", err)
+			}
+
+			return PagedJSONResult(wikiPages, pagination)
+		},
+	)
+}
+
+// registerGetGroupWikiPage registers the get_group_wiki_page tool.
+func registerGetGroupWikiPage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_group_wiki_page",
+			Description: "Get a specific page of a GitLab group wiki (GitLab Premium)",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"group_id": groupWikiIDProperty,
+					"slug": {
+						Type:        "string",
+						Description: "The URL-encoded slug of the wiki page (e.g., 'home' or 'getting-started')",
+					},
+				},
+				Required: []string{"slug"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_group_wiki_page", args)
+
+			groupID := groupWikiGroupID(c, args)
+			if groupID == "" {
+				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
+			}
+
+			slug := GetString(args, "slug", "")
+			if slug == "" {
+				return ErrorResult("slug is required")
+			}
+
+			endpoint := fmt.Sprintf("/groups/%s/wikis/%s", url.PathEscape(groupID), url.PathEscape(slug))
+
+			var wikiPage WikiPage
+			if err := c.Client.Get(ctx, endpoint, &wikiPage); err != nil {
+				return APIErrorResult("Failed to get group wiki page", err)
+			}
+
+			return JSONResult(wikiPage)
+		},
+	)
+}
+
+// registerCreateGroupWikiPage registers the create_group_wiki_page tool.
+func registerCreateGroupWikiPage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "create_group_wiki_page",
+			Description: "Create a new page in a GitLab group wiki (GitLab Premium)",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"group_id": groupWikiIDProperty,
+					"title": {
+						Type:        "string",
+						Description: "The title of the wiki page",
+					},
+					"content": {
+						Type:        "string",
+						Description: "The content of the wiki page",
+					},
+					"format": {
+						Type:        "string",
+						Description: "The format of the wiki page: markdown, rdoc, asciidoc, or org (optional, default: markdown)",
+					},
+				},
+				Required: []string{"title", "content"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "create_group_wiki_page", args)
+
+			// Check read-only mode
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot create group wiki page: server is in read-only mode")
+			}
+
+			groupID := groupWikiGroupID(c, args)
+			if groupID == "" {
+				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
+			}
+
+			title := GetString(args, "title", "")
+			if title == "" {
+				return ErrorResult("title is required")
+			}
+
+			content := GetString(args, "content", "")
+			if content == "" {
+				return ErrorResult("content is required")
+			}
+
+			requestBody := map[string]interface{}{
+				"title":   title,
+				"content": content,
+			}
+
+			if format := GetString(args, "format", ""); format != "" {
+				validFormats := map[string]bool{"markdown": true, "rdoc": true, "asciidoc": true, "org": true}
+				if !validFormats[format] {
+					return ErrorResult("format must be one of: markdown, rdoc, asciidoc, org")
+				}
+				requestBody["format"] = format
+			}
+
+			endpoint := fmt.Sprintf("/groups/%s/wikis", url.PathEscape(groupID))
+
+			var wikiPage WikiPage
+			if err := c.Client.Post(ctx, endpoint, requestBody, &wikiPage); err != nil {
+				return APIErrorResult("Failed to create group wiki page", err)
+			}
+
+			return JSONResult(wikiPage)
+		},
+	)
+}
+
+// registerUpdateGroupWikiPage registers the update_group_wiki_page tool.
+func registerUpdateGroupWikiPage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "update_group_wiki_page",
+			Description: "Update an existing page in a GitLab group wiki (GitLab Premium)",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"group_id": groupWikiIDProperty,
+					"slug": {
+						Type:        "string",
+						Description: "The URL-encoded slug of the wiki page to update",
+					},
+					"title": {
+						Type:        "string",
+						Description: "The new title of the wiki page (optional)",
+					},
+					"content": {
+						Type:        "string",
+						Description: "The new content of the wiki page (optional)",
+					},
+					"format": {
+						Type:        "string",
+						Description: "The format of the wiki page: markdown, rdoc, asciidoc, or org (optional)",
+					},
+				},
+				Required: []string{"slug"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "update_group_wiki_page", args)
+
+			// Check read-only mode
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot update group wiki page: server is in read-only mode")
+			}
+
+			groupID := groupWikiGroupID(c, args)
+			if groupID == "" {
+				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
+			}
+
+			slug := GetString(args, "slug", "")
+			if slug == "" {
+				return ErrorResult("slug is required")
+			}
+
+			title := GetString(args, "title", "")
+			content := GetString(args, "content", "")
+			format := GetString(args, "format", "")
+
+			// At least one update field must be provided
+			if title == "" && content == "" && format == "" {
+				return ErrorResult("at least one of title, content, or format must be provided")
+			}
+
+			requestBody := make(map[string]interface{})
+			if title != "" {
+				requestBody["title"] = title
+			}
+			if content != "" {
+				requestBody["content"] = content
+			}
+			if format != "" {
+				validFormats := map[string]bool{"markdown": true, "rdoc": true, "asciidoc": true, "org": true}
+				if !validFormats[format] {
+					return ErrorResult("format must be one of: markdown, rdoc, asciidoc, org")
+				}
+				requestBody["format"] = format
+			}
+
+			endpoint := fmt.Sprintf("/groups/%s/wikis/%s", url.PathEscape(groupID), url.PathEscape(slug))
+
+			var wikiPage WikiPage
+			if err := c.Client.Put(ctx, endpoint, requestBody, &wikiPage); err != nil {
+				return APIErrorResult("Failed to update group wiki page", err)
+			}
+
+			return JSONResult(wikiPage)
+		},
+	)
+}
+
+// registerDeleteGroupWikiPage registers the delete_group_wiki_page tool.
+func registerDeleteGroupWikiPage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "delete_group_wiki_page",
+			Description: "Delete a page from a GitLab group wiki (GitLab Premium)",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"group_id": groupWikiIDProperty,
+					"slug": {
+						Type:        "string",
+						Description: "The URL-encoded slug of the wiki page to delete",
+					},
+				},
+				Required: []string{"slug"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "delete_group_wiki_page", args)
+
+			// Check read-only mode
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot delete group wiki page: server is in read-only mode")
+			}
+
+			groupID := groupWikiGroupID(c, args)
+			if groupID == "" {
+				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
+			}
+
+			slug := GetString(args, "slug", "")
+			if slug == "" {
+				return ErrorResult("slug is required")
+			}
+
+			endpoint := fmt.Sprintf("/groups/%s/wikis/%s", url.PathEscape(groupID), url.PathEscape(slug))
+
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("Failed to delete group wiki page", err)
+			}
+
+			result := map[string]interface{}{
+				"message": fmt.Sprintf("Group wiki page '%s' successfully deleted", slug),
+				"slug":    slug,
+			}
+
+			return JSONResult(result)
+		},
+	)
+}
+
+// registerUploadGroupWikiAttachment registers the upload_group_wiki_attachment tool.
+func registerUploadGroupWikiAttachment(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "upload_group_wiki_attachment",
+			Description: "Upload an attachment to a GitLab group wiki and get a markdown link (GitLab Premium)",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: withUploadFileProperties(map[string]mcp.Property{
+					"group_id": groupWikiIDProperty,
+					"branch": {
+						Type:        "string",
+						Description: "The branch to upload to (optional, defaults to wiki default branch)",
+					},
+				}),
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "upload_group_wiki_attachment", args)
+
+			// Check read-only mode
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot upload group wiki attachment: server is in read-only mode")
+			}
+
+			groupID := groupWikiGroupID(c, args)
+			if groupID == "" {
+				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
+			}
+
+			file, closer, err := openUpload(c, args, "file")
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			defer closer.Close()
+
+			fields := map[string]string{}
+			if branch := GetString(args, "branch", ""); branch != "" {
+				fields["branch"] = branch
+			}
+
+			endpoint := fmt.Sprintf("/groups/%s/wikis/attachments", url.PathEscape(groupID))
+
+			var response WikiAttachmentResponse
+			if err := c.Client.PostMultipart(ctx, endpoint, fields, file, &response); err != nil {
+				return APIErrorResult("Failed to upload group wiki attachment", err)
+			}
+
+			result := map[string]interface{}{
+				"file_name": response.FileName,
+				"file_path": response.FilePath,
+				"branch":    response.Branch,
+				"url":       response.Link.URL,
+				"markdown":  response.Link.Markdown,
+			}
+
+			return JSONResult(result)
+		},
+	)
+}
+
+// initGroupWikiTools registers all group wiki tools with the MCP server.
+func initGroupWikiTools(server *mcp.Server) {
+	registerListGroupWikiPages(server)
+	registerGetGroupWikiPage(server)
+	registerCreateGroupWikiPage(server)
+	registerUpdateGroupWikiPage(server)
+	registerDeleteGroupWikiPage(server)
+	registerUploadGroupWikiAttachment(server)
+}
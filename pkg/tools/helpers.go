@@ -2,9 +2,13 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
 )
 
@@ -148,8 +152,51 @@ func ErrorResult(message string) (*mcp.CallToolResult, error) {
 	}, nil
 }
 
+// ErrorDetails is the machine-readable payload attached to error results for
+// failed GitLab API calls, so agents can branch on status instead of parsing text.
+type ErrorDetails struct {
+	HTTPStatus        int    `json:"http_status"`
+	GitLabMessage     string `json:"gitlab_message"`
+	Endpoint          string `json:"endpoint"`
+	Retryable         bool   `json:"retryable"`
+	RetryAfterSeconds int    `json:"retry_after_seconds,omitempty"`
+	Hint              string `json:"hint,omitempty"`
+}
+
+// APIErrorResult creates an error CallToolResult for a failed operation.
+// The first content item is "<message>: <err>" as with ErrorResult; when err is
+// a GitLab API error, a second item carries ErrorDetails as JSON of the form
+// {"error": {...}}.
+func APIErrorResult(message string, err error) (*mcp.CallToolResult, error) {
+	result, _ := ErrorResult(fmt.Sprintf("%s: %v", message, err))
+
+	var apiErr *gitlab.APIError
+	if !errors.As(err, &apiErr) {
+		return result, nil
+	}
+
+	details := ErrorDetails{
+		HTTPStatus:        apiErr.StatusCode,
+		GitLabMessage:     apiErr.Message,
+		Endpoint:          apiErr.Endpoint,
+		Retryable:         apiErr.Retryable(),
+		RetryAfterSeconds: int(apiErr.RetryAfter.Seconds()),
+		Hint:              apiErr.Hint(),
+	}
+	jsonBytes, jsonErr := json.Marshal(map[string]ErrorDetails{"error": details})
+	if jsonErr != nil {
+		return result, nil
+	}
+	result.Content = append(result.Content, mcp.ContentItem{
+		Type: "text",
+		Text: string(jsonBytes),
+	})
+	return result, nil
+}
+
 // JSONResult creates a successful CallToolResult with JSON-encoded data.
-// The data is marshaled with indentation for readability.
+// The data is marshaled with indentation for readability. Field projection and
+// truncation of large responses are applied by ResultMiddleware.
 func JSONResult(data interface{}) (*mcp.CallToolResult, error) {
 	jsonBytes, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -166,3 +213,107 @@ func JSONResult(data interface{}) (*mcp.CallToolResult, error) {
 		IsError: false,
 	}, nil
 }
+
+// ImageResult creates a successful CallToolResult with metadata as JSON text
+// followed by an image content item, so clients that cannot show images still
+// get a description of the picture.
+func ImageResult(metadata interface{}, data []byte, mimeType string) (*mcp.CallToolResult, error) {
+	result, err := JSONResult(metadata)
+	if err != nil || result.IsError {
+		return result, err
+	}
+	result.Content = append(result.Content, mcp.ImageContent(data, mimeType))
+	return result, nil
+}
+
+// ListResult is the envelope returned by list tools: one page of items and the
+// pagination info from GitLab's response headers (null if not paginated).
+type ListResult struct {
+	Items      interface{}            `json:"items"`
+	Pagination *gitlab.PaginationInfo `json:"pagination"`
+}
+
+// PagedJSONResult creates a successful CallToolResult for a list tool, with
+// items wrapped in the standard {"items": [...], "pagination": {...}} envelope.
+func PagedJSONResult(items interface{}, pagination *gitlab.PaginationInfo) (*mcp.CallToolResult, error) {
+	return JSONResult(ListResult{Items: items, Pagination: pagination})
+}
+
+// eachItem calls fn with each item of one page of a list endpoint, decoded
+// one at a time as the response arrives. fn may return gitlab.ErrStop to skip
+// the rest of the page.
+func eachItem[T any](ctx context.Context, client gitlab.API, endpoint string, fn func(T) error) (*gitlab.PaginationInfo, error) {
+	return client.GetEach(ctx, endpoint, func(raw json.RawMessage) error {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		return fn(item)
+	})
+}
+
+// maxCollectedItems caps how many items collectPages fetches for computed
+// reports, so a single tool call stays bounded on very large projects.
+const maxCollectedItems = 1000
+
+// collectPages fetches successive pages of a list endpoint (without page or
+// per_page parameters) until the last page or until limit items were read. It
+// reports whether the whole collection was read.
+func collectPages[T any](ctx context.Context, client gitlab.API, endpoint string, limit int) ([]T, bool, error) {
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	var items []T
+	for page := 1; ; page++ {
+		var batch []T
+		pagination, err := client.GetWithPagination(ctx, fmt.Sprintf("%s%spage=%d&per_page=100", endpoint, separator, page), &batch)
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, batch...)
+		last := pagination == nil || pagination.NextPage == 0 || len(batch) == 0
+		if len(items) >= limit {
+			return items[:limit], last && len(items) == limit, nil
+		}
+		if last {
+			return items, true, nil
+		}
+	}
+}
+
+// streamPages is collectPages for reports that only aggregate the items: fn
+// is called with each item as it is decoded, so neither a page nor the whole
+// collection is held in memory.
+func streamPages[T any](ctx context.Context, client gitlab.API, endpoint string, limit int, fn func(T)) (bool, error) {
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	read := 0
+	for page := 1; ; page++ {
+		batch, truncated := 0, false
+		pagination, err := eachItem(ctx, client, fmt.Sprintf("%s%spage=%d&per_page=100", endpoint, separator, page), func(item T) error {
+			if read >= limit {
+				truncated = true
+				return gitlab.ErrStop
+			}
+			read++
+			batch++
+			fn(item)
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+		last := pagination == nil || pagination.NextPage == 0 || batch == 0
+		if truncated || (read >= limit && !last) {
+			return false, nil
+		}
+		if last {
+			return true, nil
+		}
+	}
+}
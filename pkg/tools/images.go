@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// maxAvatarBytes bounds project avatar downloads; GitLab caps avatars at 200 KB.
+const maxAvatarBytes = 1 << 20
+
+// ProjectAvatar is the metadata returned with a project avatar image.
+type ProjectAvatar struct {
+	ProjectID   string `json:"project_id"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	// Note explains why no image is attached, if none is.
+	Note string `json:"note,omitempty"`
+}
+
+// registerGetProjectAvatar registers the get_project_avatar tool.
+func registerGetProjectAvatar(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_project_avatar",
+			Description: "Get a project's avatar as an image. Avatars hosted outside the GitLab instance (e.g., Gravatar) are returned as a URL only.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_project_avatar", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			encodedProjectID := url.PathEscape(projectID)
+
+			var buf bytes.Buffer
+			avatar := ProjectAvatar{ProjectID: projectID}
+
+			// GET /projects/:id/avatar exists since GitLab 16.9
+			info, err := c.Client.GetBinary(ctx, fmt.Sprintf("/projects/%s/avatar", encodedProjectID), &buf, maxAvatarBytes)
+			if gitlab.IsNotFound(err) {
+				var project struct {
+					AvatarURL string `json:"avatar_url"`
+				}
+				if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s", encodedProjectID), &project); err != nil {
+					return APIErrorResult("Failed to get project", err)
+				}
+				avatar.AvatarURL = project.AvatarURL
+				if project.AvatarURL == "" {
+					avatar.Note = "the project has no avatar"
+					return JSONResult(avatar)
+				}
+				// Only send the token to the GitLab instance itself
+				webURL := c.Client.WebURL()
+				if !strings.HasPrefix(project.AvatarURL, webURL+"/") {
+					avatar.Note = "the avatar is hosted outside the GitLab instance"
+					return JSONResult(avatar)
+				}
+				buf.Reset()
+				info, err = c.Client.GetWebBinary(ctx, strings.TrimPrefix(project.AvatarURL, webURL), &buf, maxAvatarBytes)
+			}
+			if err != nil {
+				return APIErrorResult("Failed to download project avatar", err)
+			}
+
+			avatar.ContentType = info.ContentType
+			avatar.Size = info.Size
+			if !isImageContentType(info.ContentType) {
+				avatar.Note = fmt.Sprintf("the avatar is %s, which cannot be shown as image content", info.ContentType)
+				return JSONResult(avatar)
+			}
+			return ImageResult(avatar, buf.Bytes(), info.ContentType)
+		},
+	)
+}
@@ -0,0 +1,437 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// ProjectExportStatus is the export state of a project.
+type ProjectExportStatus struct {
+	ID                int        `json:"id"`
+	Name              string     `json:"name"`
+	PathWithNamespace string     `json:"path_with_namespace"`
+	CreatedAt         *time.Time `json:"created_at"`
+	ExportStatus      string     `json:"export_status"`
+	Links             *struct {
+		APIURL string `json:"api_url"`
+		WebURL string `json:"web_url"`
+	} `json:"_links,omitempty"`
+}
+
+// ProjectImportStatus is the import state of a project.
+type ProjectImportStatus struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	ImportStatus      string `json:"import_status"`
+	ImportType        string `json:"import_type,omitempty"`
+	CorrelationID     string `json:"correlation_id,omitempty"`
+	ImportError       string `json:"import_error,omitempty"`
+	FailedRelations   []struct {
+		ID           int        `json:"id"`
+		CreatedAt    *time.Time `json:"created_at"`
+		ExceptionMsg string     `json:"exception_message"`
+		Source       string     `json:"source"`
+		RelationName string     `json:"relation_name"`
+	} `json:"failed_relations,omitempty"`
+}
+
+// localPath resolves a path argument on the server's filesystem. Local files
+// are only available to stdio clients, which run on the same machine.
+func localPath(c *ToolContext, args map[string]interface{}, key string) (string, error) {
+	if c.Config != nil && c.Config.HTTPMode {
+		return "", fmt.Errorf("%s is not supported in HTTP mode: the server's filesystem is not the client's", key)
+	}
+	path := GetString(args, key, "")
+	if path == "" {
+		return "", fmt.Errorf("%s is required", key)
+	}
+	return filepath.Abs(config.ExpandPath(path))
+}
+
+// setImportParams copies the shared import arguments into fields.
+func setImportParams(args map[string]interface{}, fields map[string]string) {
+	for _, key := range []string{"path", "name", "namespace"} {
+		if value := GetString(args, key, ""); value != "" {
+			fields[key] = value
+		}
+	}
+	if GetBool(args, "overwrite", false) {
+		fields["overwrite"] = "true"
+	}
+}
+
+// importProperties are the target project arguments shared by the import tools.
+var importProperties = map[string]mcp.Property{
+	"path": {
+		Type:        "string",
+		Description: "Path of the new project",
+	},
+	"name": {
+		Type:        "string",
+		Description: "Name of the new project (default: path)",
+	},
+	"namespace": {
+		Type:        "string",
+		Description: "ID or path of the namespace to import into (default: the current user's namespace)",
+	},
+	"overwrite": {
+		Type:        "boolean",
+		Description: "Overwrite an existing project with the same path (default: false)",
+	},
+}
+
+// withImportProperties returns properties extended with importProperties.
+func withImportProperties(properties map[string]mcp.Property) map[string]mcp.Property {
+	for name, property := range importProperties {
+		properties[name] = property
+	}
+	return properties
+}
+
+// registerScheduleProjectExport registers the schedule_project_export tool.
+func registerScheduleProjectExport(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "schedule_project_export",
+			Description: "Start an asynchronous export of a project (repository, issues, MRs, wiki and settings). Poll get_project_export_status until export_status is finished, then fetch the archive with download_project_export.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"description": {
+						Type:        "string",
+						Description: "Override the project description in the export",
+					},
+					"upload_url": {
+						Type:        "string",
+						Description: "Upload the finished archive to this URL (e.g., a pre-signed object storage URL) instead of keeping it for download",
+					},
+					"upload_http_method": {
+						Type:        "string",
+						Description: "HTTP method for upload_url (default: PUT)",
+						Enum:        []string{"PUT", "POST"},
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "schedule_project_export", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			body := map[string]interface{}{}
+			if description := GetString(args, "description", ""); description != "" {
+				body["description"] = description
+			}
+			if uploadURL := GetString(args, "upload_url", ""); uploadURL != "" {
+				upload := map[string]interface{}{"url": uploadURL}
+				if method := GetString(args, "upload_http_method", ""); method != "" {
+					upload["http_method"] = method
+				}
+				body["upload"] = upload
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/export", url.PathEscape(projectID))
+
+			var response map[string]interface{}
+			if err := c.Client.Post(ctx, endpoint, body, &response); err != nil {
+				return APIErrorResult("failed to schedule project export", err)
+			}
+
+			return TextResult(fmt.Sprintf("Export of project %s scheduled. Poll get_project_export_status until export_status is \"finished\".", projectID))
+		},
+	)
+}
+
+// registerGetProjectExportStatus registers the get_project_export_status tool.
+func registerGetProjectExportStatus(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_project_export_status",
+			Description: "Get the status of a project export: none, queued, started, regeneration_in_progress or finished. A finished export includes its download links.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_project_export_status", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/export", url.PathEscape(projectID))
+
+			var status ProjectExportStatus
+			if err := c.Client.Get(ctx, endpoint, &status); err != nil {
+				return APIErrorResult("failed to get project export status", err)
+			}
+
+			return JSONResult(status)
+		},
+	)
+}
+
+// registerDownloadProjectExport registers the download_project_export tool.
+func registerDownloadProjectExport(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "download_project_export",
+			Description: "Download a finished project export archive (.tar.gz) to a file on the machine running this server. Not available in HTTP mode.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"output_path": {
+						Type:        "string",
+						Description: "Local file path to write the archive to (~ is expanded)",
+					},
+					"overwrite": {
+						Type:        "boolean",
+						Description: "Replace output_path if it already exists (default: false)",
+					},
+				},
+				Required: []string{"project_id", "output_path"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "download_project_export", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			outputPath, err := localPath(c, args, "output_path")
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+
+			flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+			if GetBool(args, "overwrite", false) {
+				flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+			}
+			file, err := os.OpenFile(outputPath, flags, 0o600)
+			if errors.Is(err, os.ErrExist) {
+				return ErrorResult(fmt.Sprintf("%s already exists; set overwrite=true to replace it", outputPath))
+			}
+			if err != nil {
+				return ErrorResult(fmt.Sprintf("failed to create %s: %v", outputPath, err))
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/export/download", url.PathEscape(projectID))
+			written, _, err := c.Client.Download(ctx, endpoint, file)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(outputPath)
+				return APIErrorResult("failed to download project export", err)
+			}
+
+			return JSONResult(map[string]interface{}{
+				"project_id": projectID,
+				"path":       outputPath,
+				"bytes":      written,
+			})
+		},
+	)
+}
+
+// registerImportProjectFromFile registers the import_project_from_file tool.
+func registerImportProjectFromFile(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "import_project_from_file",
+			Description: "Create a project by importing an export archive from a file on the machine running this server. The import runs asynchronously; poll get_project_import_status. Not available in HTTP mode.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: withImportProperties(map[string]mcp.Property{
+					"file_path": {
+						Type:        "string",
+						Description: "Local path of the export archive (.tar.gz, ~ is expanded)",
+					},
+				}),
+				Required: []string{"file_path", "path"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "import_project_from_file", args)
+
+			filePath, err := localPath(c, args, "file_path")
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			if GetString(args, "path", "") == "" {
+				return ErrorResult("path is required")
+			}
+
+			file, err := os.Open(filePath)
+			if err != nil {
+				return ErrorResult(fmt.Sprintf("failed to open %s: %v", filePath, err))
+			}
+			defer file.Close()
+
+			fields := map[string]string{}
+			setImportParams(args, fields)
+
+			var status ProjectImportStatus
+			err = c.Client.PostMultipart(ctx, "/projects/import", fields, gitlab.MultipartFile{
+				Field:  "file",
+				Name:   filepath.Base(filePath),
+				Reader: file,
+			}, &status)
+			if err != nil {
+				return APIErrorResult("failed to import project", err)
+			}
+
+			return JSONResult(status)
+		},
+	)
+}
+
+// registerImportProjectFromURL registers the import_project_from_url tool.
+func registerImportProjectFromURL(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "import_project_from_url",
+			Description: "Create a project by importing an export archive that GitLab downloads from a URL (e.g., a pre-signed object storage URL). The import runs asynchronously; poll get_project_import_status.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: withImportProperties(map[string]mcp.Property{
+					"url": {
+						Type:        "string",
+						Description: "URL of the export archive, reachable from the GitLab server",
+					},
+				}),
+				Required: []string{"url", "path"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "import_project_from_url", args)
+
+			archiveURL := GetString(args, "url", "")
+			if archiveURL == "" {
+				return ErrorResult("url is required")
+			}
+			if GetString(args, "path", "") == "" {
+				return ErrorResult("path is required")
+			}
+
+			fields := map[string]string{"url": archiveURL}
+			setImportParams(args, fields)
+
+			var status ProjectImportStatus
+			if err := c.Client.Post(ctx, "/projects/remote-import", fields, &status); err != nil {
+				return APIErrorResult("failed to import project", err)
+			}
+
+			return JSONResult(status)
+		},
+	)
+}
+
+// registerGetProjectImportStatus registers the get_project_import_status tool.
+func registerGetProjectImportStatus(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_project_import_status",
+			Description: "Get the status of a project import: none, scheduled, started, finished or failed, with the error and failed relations if any.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_project_import_status", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/import", url.PathEscape(projectID))
+
+			var status ProjectImportStatus
+			if err := c.Client.Get(ctx, endpoint, &status); err != nil {
+				return APIErrorResult("failed to get project import status", err)
+			}
+
+			return JSONResult(status)
+		},
+	)
+}
+
+// initImportExportTools registers the project import and export tools.
+func initImportExportTools(server *mcp.Server) {
+	registerScheduleProjectExport(server)
+	registerGetProjectExportStatus(server)
+	registerDownloadProjectExport(server)
+	registerImportProjectFromFile(server)
+	registerImportProjectFromURL(server)
+	registerGetProjectImportStatus(server)
+}
@@ -0,0 +1,41 @@
+// Command gentools generates the registration code of the tools declared in
+// pkg/tools/manifest.yaml. It is run by go generate in pkg/tools:
+//
+//	go generate ./pkg/tools
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/tools/internal/toolgen"
+)
+
+func main() {
+	manifestPath := flag.String("manifest", "manifest.yaml", "manifest file")
+	outPath := flag.String("out", "manifest_gen.go", "generated Go file")
+	flag.Parse()
+
+	if err := run(*manifestPath, *outPath); err != nil {
+		fmt.Fprintf(os.Stderr, "gentools: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(manifestPath, outPath string) error {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return err
+	}
+	manifest, err := toolgen.Parse(data)
+	if err != nil {
+		return err
+	}
+	code, err := toolgen.Generate(manifest, filepath.Base(manifestPath))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, code, 0644)
+}
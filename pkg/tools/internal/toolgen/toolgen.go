@@ -0,0 +1,549 @@
+// Package toolgen generates the registration code of simple GitLab tools from
+// the declarative manifest in pkg/tools/manifest.yaml.
+//
+// A manifest entry describes one tool: its name and description, the HTTP
+// method and endpoint, its parameters and where each goes (path, query or
+// body), and how the response is returned. The generated code uses the same
+// helpers as the hand-written tools (withArgs, PageArgs, APIErrorResult,
+// JSONResult, PagedJSONResult and TextResult). Tools that need more than one
+// request or extra logic stay hand-written.
+package toolgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is the content of a manifest file.
+type Manifest struct {
+	// Params holds shared parameter definitions, which tools reference with
+	// YAML anchors.
+	Params map[string]Param `yaml:"params"`
+	Tools  []Tool           `yaml:"tools"`
+}
+
+// Tool describes a tool backed by a single GitLab API request.
+type Tool struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Method is the HTTP method: GET, POST, PUT or DELETE.
+	Method string `yaml:"method"`
+	// Endpoint is relative to the API root, with path parameters in braces
+	// (e.g., /projects/{project_id}/freeze_periods/{freeze_period_id}).
+	Endpoint string `yaml:"endpoint"`
+	// Paginated adds the page and per_page arguments to a GET tool and
+	// returns the pagination metadata with the items.
+	Paginated   bool    `yaml:"paginated"`
+	ReadOnly    bool    `yaml:"read_only"`
+	Destructive bool    `yaml:"destructive"`
+	Params      []Param `yaml:"params"`
+	// Result is the Go type the response is decoded into (e.g.,
+	// "[]FreezePeriod"). Tools without a result (usually DELETE) return
+	// Message.
+	Result string `yaml:"result"`
+	// Message is the text of a successful tool without a result. Parameters
+	// in braces are replaced by their value.
+	Message string `yaml:"message"`
+	// Error prefixes the message of a failed request.
+	Error string `yaml:"error"`
+}
+
+// Param is a tool argument.
+type Param struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"`
+	Description string   `yaml:"description"`
+	Required    bool     `yaml:"required"`
+	Enum        []string `yaml:"enum"`
+	Minimum     *int     `yaml:"minimum"`
+	Maximum     *int     `yaml:"maximum"`
+	// In is where the argument goes: path (the default for parameters of
+	// the endpoint), query (the default for GET and DELETE) or body.
+	In string `yaml:"in"`
+}
+
+// Parse decodes and checks a manifest.
+func Parse(data []byte) (*Manifest, error) {
+	var manifest Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	names := map[string]bool{}
+	for i := range manifest.Tools {
+		tool := &manifest.Tools[i]
+		if names[tool.Name] {
+			return nil, fmt.Errorf("tool %s: defined twice", tool.Name)
+		}
+		names[tool.Name] = true
+		if err := tool.check(); err != nil {
+			return nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+	}
+	return &manifest, nil
+}
+
+// pathParamPattern matches the parameters of an endpoint.
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// check validates a tool and fills in the parameter defaults.
+func (t *Tool) check() error {
+	if !regexp.MustCompile(`^[a-z][a-z0-9_]*$`).MatchString(t.Name) {
+		return fmt.Errorf("invalid name")
+	}
+	if t.Description == "" || t.Error == "" {
+		return fmt.Errorf("description and error are required")
+	}
+	switch t.Method {
+	case "GET", "POST", "PUT", "DELETE":
+	default:
+		return fmt.Errorf("unsupported method %q", t.Method)
+	}
+	if (t.Result == "") == (t.Message == "") {
+		return fmt.Errorf("exactly one of result and message is required")
+	}
+	if t.Paginated && (t.Method != "GET" || !strings.HasPrefix(t.Result, "[]")) {
+		return fmt.Errorf("only GET tools returning a list can be paginated")
+	}
+
+	inPath := map[string]bool{}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(t.Endpoint, -1) {
+		inPath[match[1]] = true
+	}
+	declared := map[string]bool{}
+	for i := range t.Params {
+		p := &t.Params[i]
+		declared[p.Name] = true
+		switch p.Type {
+		case "string", "integer", "boolean":
+		case "array":
+			// Arrays are lists of strings
+		default:
+			return fmt.Errorf("param %s: unsupported type %q", p.Name, p.Type)
+		}
+		if p.Description == "" {
+			return fmt.Errorf("param %s: description is required", p.Name)
+		}
+		if p.In == "" {
+			switch {
+			case inPath[p.Name]:
+				p.In = "path"
+			case t.Method == "GET" || t.Method == "DELETE":
+				p.In = "query"
+			default:
+				p.In = "body"
+			}
+		}
+		switch p.In {
+		case "path":
+			if !inPath[p.Name] {
+				return fmt.Errorf("param %s: not in the endpoint", p.Name)
+			}
+			if !p.Required || (p.Type != "string" && p.Type != "integer") {
+				return fmt.Errorf("param %s: path parameters must be required strings or integers", p.Name)
+			}
+		case "query":
+		case "body":
+			if t.Method == "GET" || t.Method == "DELETE" {
+				return fmt.Errorf("param %s: %s requests have no body", p.Name, t.Method)
+			}
+		default:
+			return fmt.Errorf("param %s: invalid location %q", p.Name, p.In)
+		}
+		if t.Paginated && (p.Name == "page" || p.Name == "per_page") {
+			return fmt.Errorf("param %s: added by paginated", p.Name)
+		}
+	}
+	for name := range inPath {
+		if !declared[name] {
+			return fmt.Errorf("endpoint parameter %s is not declared", name)
+		}
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(t.Message, -1) {
+		if !declared[match[1]] {
+			return fmt.Errorf("message parameter %s is not declared", match[1])
+		}
+	}
+	return nil
+}
+
+// Generate returns the formatted Go source registering the tools of a
+// manifest. source names the manifest in the generated header.
+func Generate(manifest *Manifest, source string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, map[string]interface{}{
+		"Source": source,
+		"Tools":  manifest.Tools,
+	}); err != nil {
+		return nil, err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, buf.Bytes())
+	}
+	return code, nil
+}
+
+// goName converts a snake_case name to a Go identifier (project_id →
+// ProjectID).
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		switch part {
+		case "id", "iid", "url", "sha", "api", "ci":
+			b.WriteString(strings.ToUpper(part))
+		default:
+			if part != "" {
+				b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+			}
+		}
+	}
+	return b.String()
+}
+
+// lowerFirst lowercases the first letter of s.
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// goType is the argument struct field type of a parameter. Booleans are
+// pointers so that false is sent when given.
+func goType(p Param) string {
+	switch p.Type {
+	case "integer":
+		return "int"
+	case "boolean":
+		return "*bool"
+	case "array":
+		return "[]string"
+	}
+	return "string"
+}
+
+// structTag is the struct tag of a parameter field.
+func structTag(p Param) string {
+	var rules []string
+	if p.Required {
+		rules = append(rules, "required")
+	}
+	if p.Minimum != nil {
+		rules = append(rules, fmt.Sprintf("min=%d", *p.Minimum))
+	}
+	if p.Maximum != nil {
+		rules = append(rules, fmt.Sprintf("max=%d", *p.Maximum))
+	}
+	if len(p.Enum) > 0 {
+		rules = append(rules, "oneof="+strings.Join(p.Enum, " "))
+	}
+	tag := fmt.Sprintf(`json:"%s"`, p.Name)
+	if len(rules) > 0 {
+		tag += fmt.Sprintf(` validate:"%s"`, strings.Join(rules, ","))
+	}
+	return "`" + tag + "`"
+}
+
+// endpointExpr is the Go expression building the endpoint of a tool.
+func endpointExpr(t Tool) string {
+	params := map[string]Param{}
+	for _, p := range t.Params {
+		params[p.Name] = p
+	}
+	var args []string
+	format := pathParamPattern.ReplaceAllStringFunc(t.Endpoint, func(match string) string {
+		p := params[match[1:len(match)-1]]
+		if p.Type == "integer" {
+			args = append(args, "args."+goName(p.Name))
+			return "%d"
+		}
+		args = append(args, fmt.Sprintf("url.PathEscape(args.%s)", goName(p.Name)))
+		return "%s"
+	})
+	if len(args) == 0 {
+		return fmt.Sprintf("%q", format)
+	}
+	return fmt.Sprintf("fmt.Sprintf(%q, %s)", format, strings.Join(args, ", "))
+}
+
+// messageExpr is the Go expression building the message of a tool.
+func messageExpr(t Tool) string {
+	params := map[string]Param{}
+	for _, p := range t.Params {
+		params[p.Name] = p
+	}
+	var args []string
+	format := pathParamPattern.ReplaceAllStringFunc(strings.ReplaceAll(t.Message, "%", "%%"), func(match string) string {
+		p := params[match[1:len(match)-1]]
+		args = append(args, "args."+goName(p.Name))
+		if p.Type == "integer" {
+			return "%d"
+		}
+		return "%s"
+	})
+	if len(args) == 0 {
+		return fmt.Sprintf("%q", format)
+	}
+	return fmt.Sprintf("fmt.Sprintf(%q, %s)", format, strings.Join(args, ", "))
+}
+
+// readOnlyError is the error of a tool that changes GitLab, called while the
+// server is in read-only mode.
+func readOnlyError(t Tool) string {
+	lower := strings.ToLower(t.Error)
+	if !strings.HasPrefix(lower, "failed to ") {
+		return fmt.Sprintf("cannot run %s: server is in read-only mode", t.Name)
+	}
+	return fmt.Sprintf("cannot %s: server is in read-only mode", t.Error[len("failed to "):])
+}
+
+// paramsIn returns the parameters of a tool at a location, by name.
+func paramsIn(t Tool, in string) []Param {
+	var params []Param
+	for _, p := range t.Params {
+		if p.In == in {
+			params = append(params, p)
+		}
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
+
+var fileTemplate = template.Must(template.New("file").Funcs(template.FuncMap{
+	"goName":        goName,
+	"lowerFirst":    lowerFirst,
+	"goType":        goType,
+	"structTag":     structTag,
+	"endpointExpr":  endpointExpr,
+	"messageExpr":   messageExpr,
+	"readOnlyError": readOnlyError,
+	"paramsIn":      paramsIn,
+	"needsFmt": func(tools []Tool) bool {
+		for _, t := range tools {
+			if strings.Contains(endpointExpr(t), "fmt.") || (t.Message != "" && strings.Contains(messageExpr(t), "fmt.")) {
+				return true
+			}
+		}
+		return false
+	},
+	"needsURL": func(tools []Tool) bool {
+		for _, t := range tools {
+			if t.Paginated || strings.Contains(endpointExpr(t), "url.") || len(paramsIn(t, "query")) > 0 {
+				return true
+			}
+		}
+		return false
+	},
+	"needsStrconv": func(tools []Tool) bool {
+		for _, t := range tools {
+			for _, p := range paramsIn(t, "query") {
+				if p.Type == "integer" || p.Type == "boolean" {
+					return true
+				}
+			}
+		}
+		return false
+	},
+}).Parse(`// Code generated by toolgen from {{.Source}}; DO NOT EDIT.
+
+package tools
+
+import (
+	"context"
+{{- if needsFmt .Tools}}
+	"fmt"
+{{- end}}
+{{- if needsURL .Tools}}
+	"net/url"
+{{- end}}
+{{- if needsStrconv .Tools}}
+	"strconv"
+{{- end}}
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+{{range .Tools}}{{$args := printf "%sArgs" (lowerFirst (goName .Name))}}
+type {{$args}} struct {
+{{- if .Paginated}}
+	PageArgs
+{{- end}}
+{{- range .Params}}
+	{{goName .Name}} {{goType .}} {{structTag .}}
+{{- end}}
+}
+
+// register{{goName .Name}} registers the {{.Name}} tool.
+func register{{goName .Name}}(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        {{printf "%q" .Name}},
+			Description: {{printf "%q" .Description}},
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+{{- range .Params}}
+					{{printf "%q" .Name}}: {
+						Type:        {{printf "%q" .Type}},
+						Description: {{printf "%q" .Description}},
+{{- if .Enum}}
+						Enum:        []string{ {{- range $i, $v := .Enum}}{{if $i}}, {{end}}{{printf "%q" $v}}{{end -}} },
+{{- end}}
+{{- if .Minimum}}
+						Minimum:     mcp.IntPtr({{.Minimum}}),
+{{- end}}
+{{- if .Maximum}}
+						Maximum:     mcp.IntPtr({{.Maximum}}),
+{{- end}}
+{{- if eq .Type "array"}}
+						Items:       &mcp.Property{Type: "string"},
+{{- end}}
+					},
+{{- end}}
+{{- if .Paginated}}
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+{{- end}}
+				},
+{{- $required := false}}{{range .Params}}{{if .Required}}{{$required = true}}{{end}}{{end}}
+{{- if $required}}
+				Required: []string{ {{- $first := true}}{{range .Params}}{{if .Required}}{{if not $first}}, {{end}}{{$first = false}}{{printf "%q" .Name}}{{end}}{{end -}} },
+{{- end}}
+			},
+{{- if or .ReadOnly .Destructive}}
+			Annotations: &mcp.ToolAnnotations{
+{{- if .ReadOnly}}
+				ReadOnlyHint: true,
+{{- end}}
+{{- if .Destructive}}
+				DestructiveHint: true,
+{{- end}}
+			},
+{{- end}}
+		},
+		withArgs({{printf "%q" .Name}}, func(ctx context.Context, c *ToolContext, args {{$args}}) (*mcp.CallToolResult, error) {
+{{- if ne .Method "GET"}}
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult({{printf "%q" (readOnlyError .)}})
+			}
+{{end}}
+			endpoint := {{endpointExpr .}}
+{{- $query := paramsIn . "query"}}
+{{- if or $query .Paginated}}
+
+			params := url.Values{}
+{{- if .Paginated}}
+			args.setParams(params)
+{{- end}}
+{{- range $query}}
+{{- if eq .Type "string"}}
+			if args.{{goName .Name}} != "" {
+				params.Set({{printf "%q" .Name}}, args.{{goName .Name}})
+			}
+{{- else if eq .Type "integer"}}
+			if args.{{goName .Name}} != 0 {
+				params.Set({{printf "%q" .Name}}, strconv.Itoa(args.{{goName .Name}}))
+			}
+{{- else if eq .Type "boolean"}}
+{{- if .Required}}
+			params.Set({{printf "%q" .Name}}, strconv.FormatBool(*args.{{goName .Name}}))
+{{- else}}
+			if args.{{goName .Name}} != nil {
+				params.Set({{printf "%q" .Name}}, strconv.FormatBool(*args.{{goName .Name}}))
+			}
+{{- end}}
+{{- else}}
+			for _, value := range args.{{goName .Name}} {
+				params.Add({{printf "%q" (printf "%s[]" .Name)}}, value)
+			}
+{{- end}}
+{{- end}}
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+{{- end}}
+{{- $body := paramsIn . "body"}}
+{{- if $body}}
+
+			body := map[string]interface{}{}
+{{- range $body}}
+{{- if .Required}}
+			body[{{printf "%q" .Name}}] = args.{{goName .Name}}
+{{- else if eq .Type "string"}}
+			if args.{{goName .Name}} != "" {
+				body[{{printf "%q" .Name}}] = args.{{goName .Name}}
+			}
+{{- else if eq .Type "integer"}}
+			if args.{{goName .Name}} != 0 {
+				body[{{printf "%q" .Name}}] = args.{{goName .Name}}
+			}
+{{- else if eq .Type "boolean"}}
+			if args.{{goName .Name}} != nil {
+				body[{{printf "%q" .Name}}] = *args.{{goName .Name}}
+			}
+{{- else}}
+			if len(args.{{goName .Name}}) > 0 {
+				body[{{printf "%q" .Name}}] = args.{{goName .Name}}
+			}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .Result}}
+
+			var result {{.Result}}
+{{- if .Paginated}}
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &result)
+			if err != nil {
+				return APIErrorResult({{printf "%q" .Error}}, err)
+			}
+
+			return PagedJSONResult(result, pagination)
+{{- else}}
+{{- if eq .Method "GET"}}
+			if err := c.Client.Get(ctx, endpoint, &result); err != nil {
+{{- else if eq .Method "POST"}}
+			if err := c.Client.Post(ctx, endpoint, {{if $body}}body{{else}}nil{{end}}, &result); err != nil {
+{{- else if eq .Method "PUT"}}
+			if err := c.Client.Put(ctx, endpoint, {{if $body}}body{{else}}nil{{end}}, &result); err != nil {
+{{- end}}
+				return APIErrorResult({{printf "%q" .Error}}, err)
+			}
+
+			return JSONResult(result)
+{{- end}}
+{{- else}}
+
+{{- if eq .Method "DELETE"}}
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+{{- else if eq .Method "GET"}}
+			if err := c.Client.Get(ctx, endpoint, nil); err != nil {
+{{- else if eq .Method "POST"}}
+			if err := c.Client.Post(ctx, endpoint, {{if $body}}body{{else}}nil{{end}}, nil); err != nil {
+{{- else}}
+			if err := c.Client.Put(ctx, endpoint, {{if $body}}body{{else}}nil{{end}}, nil); err != nil {
+{{- end}}
+				return APIErrorResult({{printf "%q" .Error}}, err)
+			}
+
+			return TextResult({{messageExpr .}})
+{{- end}}
+		}),
+	)
+}
+{{end}}`))
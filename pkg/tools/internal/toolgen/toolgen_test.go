@@ -0,0 +1,92 @@
+package toolgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{"unknown field", `tools: [{name: a, colour: red}]`, "field colour not found"},
+		{"undeclared path parameter", `
+tools:
+  - {name: get_a, description: d, error: e, method: GET, endpoint: "/a/{id}", result: A}`, "endpoint parameter id is not declared"},
+		{"optional path parameter", `
+tools:
+  - name: get_a
+    description: d
+    error: e
+    method: GET
+    endpoint: "/a/{id}"
+    result: A
+    params: [{name: id, type: integer, description: d}]`, "path parameters must be required"},
+		{"body on DELETE", `
+tools:
+  - name: delete_a
+    description: d
+    error: e
+    method: DELETE
+    endpoint: /a
+    message: deleted
+    params: [{name: force, type: boolean, description: d, in: body}]`, "DELETE requests have no body"},
+		{"result and message", `
+tools:
+  - {name: get_a, description: d, error: e, method: GET, endpoint: /a, result: A, message: m}`, "exactly one of result and message"},
+		{"paginated object", `
+tools:
+  - {name: get_a, description: d, error: e, method: GET, endpoint: /a, result: A, paginated: true}`, "only GET tools returning a list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.manifest))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_BodyAndQuery(t *testing.T) {
+	manifest, err := Parse([]byte(`
+tools:
+  - name: create_widget
+    description: Create a widget.
+    error: Failed to create widget
+    method: POST
+    endpoint: /projects/{project_id}/widgets
+    result: Widget
+    params:
+      - {name: project_id, type: string, description: p, required: true}
+      - {name: title, type: string, description: t, required: true}
+      - {name: size, type: integer, description: s, minimum: 1}
+      - {name: shared, type: boolean, description: s}
+      - {name: tags, type: array, description: t}
+      - {name: dry_run, type: boolean, description: d, in: query}
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	code, err := Generate(manifest, "test.yaml")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, want := range []string{
+		"// Code generated by toolgen from test.yaml; DO NOT EDIT.",
+		"Size      int      `json:\"size\" validate:\"min=1\"`",
+		"Shared    *bool    `json:\"shared\"`",
+		`params.Set("dry_run", strconv.FormatBool(*args.DryRun))`,
+		`body["title"] = args.Title`,
+		`body["shared"] = *args.Shared`,
+		`Items:       &mcp.Property{Type: "string"},`,
+		`c.Client.Post(ctx, endpoint, body, &result)`,
+		`return ErrorResult("cannot create widget: server is in read-only mode")`,
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("generated code lacks %q:\n%s", want, code)
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -22,15 +23,99 @@ type IssueLink struct {
 
 // Note: Discussion type is defined in merge_requests.go
 
+// issueFilterProperties are the advanced issue filters shared by issue list tools.
+var issueFilterProperties = map[string]mcp.Property{
+	"author_username": {
+		Type:        "string",
+		Description: "Return issues created by this username",
+	},
+	"assignee_username": {
+		Type:        "string",
+		Description: "Return issues assigned to this username",
+	},
+	"search": {
+		Type:        "string",
+		Description: "Search issues against their title and description",
+	},
+	"created_after": {
+		Type:        "string",
+		Description: "Return issues created on or after this time (ISO 8601, e.g. 2024-01-15T00:00:00Z)",
+	},
+	"created_before": {
+		Type:        "string",
+		Description: "Return issues created on or before this time (ISO 8601)",
+	},
+	"updated_after": {
+		Type:        "string",
+		Description: "Return issues updated on or after this time (ISO 8601)",
+	},
+	"updated_before": {
+		Type:        "string",
+		Description: "Return issues updated on or before this time (ISO 8601)",
+	},
+	"order_by": {
+		Type:        "string",
+		Description: "Order issues by this field (default: created_at)",
+		Enum:        []string{"created_at", "updated_at", "priority", "due_date", "relative_position", "label_priority", "milestone_due", "popularity", "weight", "title"},
+	},
+	"sort": {
+		Type:        "string",
+		Description: "Sort order (default: desc)",
+		Enum:        []string{"asc", "desc"},
+	},
+	"iids": {
+		Type:        "array",
+		Description: "Return only the issues with these IIDs",
+		Items:       &mcp.Property{Type: "integer"},
+	},
+	"confidential": {
+		Type:        "boolean",
+		Description: "Filter confidential (true) or public (false) issues",
+	},
+	"my_reaction_emoji": {
+		Type:        "string",
+		Description: "Return issues the authenticated user reacted to with this emoji name, or None/Any",
+	},
+}
+
+// withIssueFilters adds issueFilterProperties to a tool's input properties.
+func withIssueFilters(properties map[string]mcp.Property) map[string]mcp.Property {
+	for name, property := range issueFilterProperties {
+		properties[name] = property
+	}
+	return properties
+}
+
+// setIssueFilterParams copies the issueFilterProperties arguments to GitLab
+// query parameters.
+func setIssueFilterParams(args map[string]interface{}, params url.Values) {
+	for _, key := range []string{"author_username", "assignee_username", "search", "created_after", "created_before",
+		"updated_after", "updated_before", "order_by", "sort", "my_reaction_emoji"} {
+		if value := GetString(args, key, ""); value != "" {
+			params.Set(key, value)
+		}
+	}
+
+	for _, iid := range getIssueIntArray(args, "iids") {
+		params.Add("iids[]", strconv.Itoa(iid))
+	}
+
+	if confidential, exists := args["confidential"]; exists {
+		if boolVal, ok := confidential.(bool); ok {
+			params.Set("confidential", strconv.FormatBool(boolVal))
+		}
+	}
+}
+
 // registerListIssues registers the list_issues tool.
 func registerListIssues(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "list_issues",
-			Description: "List issues in a GitLab project. Returns a paginated list of issues with optional filtering by state, labels, milestone, and scope.",
+			Description: "List issues in a GitLab project. Returns a paginated list of issues with optional filtering by state, labels, milestone, scope, author, assignee, text search, creation/update dates, IIDs and confidentiality, and sorting.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
-				Properties: map[string]mcp.Property{
+				Properties: withIssueFilters(map[string]mcp.Property{
 					"project_id": {
 						Type:        "string",
 						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
@@ -66,16 +151,17 @@ func registerListIssues(server *mcp.Server) {
 						Minimum:     mcp.IntPtr(1),
 						Maximum:     mcp.IntPtr(100),
 					},
-				},
+				}),
 				Required: []string{"project_id"},
 			},
+			OutputSchema: pagedOutputSchema(issueOutputProperty),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("list_issues", args)
+			c.Logger.ToolCall(ctx, "list_issues", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -101,6 +187,8 @@ func registerListIssues(server *mcp.Server) {
 				params.Set("scope", scope)
 			}
 
+			setIssueFilterParams(args, params)
+
 			if page := GetInt(args, "page", 0); page > 0 {
 				params.Set("page", strconv.Itoa(page))
 			}
@@ -115,11 +203,12 @@ func registerListIssues(server *mcp.Server) {
 			}
 
 			var issues []gitlab.Issue
-			if err := ctx.Client.Get(endpoint, &issues); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to list issues: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &issues)
+			if err != nil {
+				return APIErrorResult("failed to list issues", err)
 			}
 
-			return JSONResult(issues)
+			return PagedJSONResult(issues, pagination)
 		},
 	)
 }
@@ -158,13 +247,14 @@ func registerMyIssues(server *mcp.Server) {
 					},
 				},
 			},
+			OutputSchema: pagedOutputSchema(issueOutputProperty),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("my_issues", args)
+			c.Logger.ToolCall(ctx, "my_issues", args)
 
 			// Build query parameters
 			params := url.Values{}
@@ -191,11 +281,114 @@ func registerMyIssues(server *mcp.Server) {
 			}
 
 			var issues []gitlab.Issue
-			if err := ctx.Client.Get(endpoint, &issues); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to list issues: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &issues)
+			if err != nil {
+				return APIErrorResult("failed to list issues", err)
+			}
+
+			return PagedJSONResult(issues, pagination)
+		},
+	)
+}
+
+// registerListGroupIssues registers the list_group_issues tool.
+func registerListGroupIssues(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_group_issues",
+			Description: "List issues across all projects in a GitLab group and its subgroups, with the same filters as list_issues. Uses GITLAB_DEFAULT_NAMESPACE if group_id is not provided.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: withIssueFilters(map[string]mcp.Property{
+					"group_id": {
+						Type:        "string",
+						Description: "The ID or URL-encoded path of the group. Falls back to GITLAB_DEFAULT_NAMESPACE if not set.",
+					},
+					"state": {
+						Type:        "string",
+						Description: "Filter issues by state: opened, closed, or all",
+						Enum:        []string{"opened", "closed", "all"},
+					},
+					"labels": {
+						Type:        "string",
+						Description: "Comma-separated list of label names to filter by",
+					},
+					"milestone": {
+						Type:        "string",
+						Description: "Milestone title to filter by",
+					},
+					"scope": {
+						Type:        "string",
+						Description: "Scope of issues: all, assigned_to_me, or created_by_me",
+						Enum:        []string{"all", "assigned_to_me", "created_by_me"},
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				}),
+			},
+			OutputSchema: pagedOutputSchema(issueOutputProperty),
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "list_group_issues", args)
+
+			// Determine group: explicit arg > config default
+			groupID := GetString(args, "group_id", "")
+			if groupID == "" && c.Config != nil {
+				groupID = c.Config.DefaultNamespace
+			}
+			if groupID == "" {
+				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
+			}
+
+			// Build query parameters
+			params := url.Values{}
+
+			for _, key := range []string{"state", "labels", "milestone", "scope"} {
+				if value := GetString(args, key, ""); value != "" {
+					params.Set(key, value)
+				}
+			}
+
+			setIssueFilterParams(args, params)
+
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", strconv.Itoa(page))
+			}
+
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", strconv.Itoa(perPage))
+			}
+
+			endpoint := fmt.Sprintf("/groups/%s/issues", url.PathEscape(groupID))
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var issues []gitlab.Issue
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &issues)
+			if err != nil {
+				return APIErrorResult("failed to list group issues", err)
 			}
 
-			return JSONResult(issues)
+			return PagedJSONResult(issues, pagination)
 		},
 	)
 }
@@ -220,13 +413,14 @@ func registerGetIssue(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "issue_iid"},
 			},
+			OutputSchema: objectOutputSchema(issueOutputProperty, "id", "iid"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_issue", args)
+			c.Logger.ToolCall(ctx, "get_issue", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -244,8 +438,8 @@ func registerGetIssue(server *mcp.Server) {
 			)
 
 			var issue gitlab.Issue
-			if err := ctx.Client.Get(endpoint, &issue); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to get issue: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &issue); err != nil {
+				return APIErrorResult("failed to get issue", err)
 			}
 
 			return JSONResult(issue)
@@ -290,13 +484,14 @@ func registerCreateIssue(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "title"},
 			},
+			OutputSchema: objectOutputSchema(issueOutputProperty, "id", "iid"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("create_issue", args)
+			c.Logger.ToolCall(ctx, "create_issue", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -332,8 +527,8 @@ func registerCreateIssue(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/issues", url.PathEscape(projectID))
 
 			var issue gitlab.Issue
-			if err := ctx.Client.Post(endpoint, body, &issue); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to create issue: %v", err))
+			if err := c.Client.Post(ctx, endpoint, body, &issue); err != nil {
+				return APIErrorResult("failed to create issue", err)
 			}
 
 			return JSONResult(issue)
@@ -387,13 +582,14 @@ func registerUpdateIssue(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "issue_iid"},
 			},
+			OutputSchema: objectOutputSchema(issueOutputProperty, "id", "iid"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("update_issue", args)
+			c.Logger.ToolCall(ctx, "update_issue", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -438,8 +634,8 @@ func registerUpdateIssue(server *mcp.Server) {
 			)
 
 			var issue gitlab.Issue
-			if err := ctx.Client.Put(endpoint, body, &issue); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to update issue: %v", err))
+			if err := c.Client.Put(ctx, endpoint, body, &issue); err != nil {
+				return APIErrorResult("failed to update issue", err)
 			}
 
 			return JSONResult(issue)
@@ -468,12 +664,12 @@ func registerDeleteIssue(server *mcp.Server) {
 				Required: []string{"project_id", "issue_iid"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("delete_issue", args)
+			c.Logger.ToolCall(ctx, "delete_issue", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -490,8 +686,8 @@ func registerDeleteIssue(server *mcp.Server) {
 				issueIID,
 			)
 
-			if err := ctx.Client.Delete(endpoint); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to delete issue: %v", err))
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("failed to delete issue", err)
 			}
 
 			return TextResult(fmt.Sprintf("Issue #%d deleted successfully", issueIID))
@@ -520,12 +716,12 @@ func registerListIssueLinks(server *mcp.Server) {
 				Required: []string{"project_id", "issue_iid"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("list_issue_links", args)
+			c.Logger.ToolCall(ctx, "list_issue_links", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -543,8 +739,8 @@ func registerListIssueLinks(server *mcp.Server) {
 			)
 
 			var links []IssueLink
-			if err := ctx.Client.Get(endpoint, &links); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to list issue links: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &links); err != nil {
+				return APIErrorResult("failed to list issue links", err)
 			}
 
 			return JSONResult(links)
@@ -577,12 +773,12 @@ func registerGetIssueLink(server *mcp.Server) {
 				Required: []string{"project_id", "issue_iid", "link_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_issue_link", args)
+			c.Logger.ToolCall(ctx, "get_issue_link", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -606,8 +802,8 @@ func registerGetIssueLink(server *mcp.Server) {
 			)
 
 			var link IssueLink
-			if err := ctx.Client.Get(endpoint, &link); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to get issue link: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &link); err != nil {
+				return APIErrorResult("failed to get issue link", err)
 			}
 
 			return JSONResult(link)
@@ -649,12 +845,12 @@ func registerCreateIssueLink(server *mcp.Server) {
 				Required: []string{"project_id", "issue_iid", "target_project_id", "target_issue_iid"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("create_issue_link", args)
+			c.Logger.ToolCall(ctx, "create_issue_link", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -692,8 +888,8 @@ func registerCreateIssueLink(server *mcp.Server) {
 			)
 
 			var link IssueLink
-			if err := ctx.Client.Post(endpoint, body, &link); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to create issue link: %v", err))
+			if err := c.Client.Post(ctx, endpoint, body, &link); err != nil {
+				return APIErrorResult("failed to create issue link", err)
 			}
 
 			return JSONResult(link)
@@ -726,12 +922,12 @@ func registerDeleteIssueLink(server *mcp.Server) {
 				Required: []string{"project_id", "issue_iid", "link_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("delete_issue_link", args)
+			c.Logger.ToolCall(ctx, "delete_issue_link", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -754,8 +950,8 @@ func registerDeleteIssueLink(server *mcp.Server) {
 				linkID,
 			)
 
-			if err := ctx.Client.Delete(endpoint); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to delete issue link: %v", err))
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("failed to delete issue link", err)
 			}
 
 			return TextResult(fmt.Sprintf("Issue link %d deleted successfully", linkID))
@@ -797,12 +993,12 @@ func registerListIssueDiscussions(server *mcp.Server) {
 				Required: []string{"project_id", "issue_iid"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("list_issue_discussions", args)
+			c.Logger.ToolCall(ctx, "list_issue_discussions", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -834,22 +1030,320 @@ func registerListIssueDiscussions(server *mcp.Server) {
 			}
 
 			var discussions []Discussion
-			if err := ctx.Client.Get(endpoint, &discussions); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to list issue discussions: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &discussions)
+			if err != nil {
+				return APIErrorResult("failed to list issue discussions", err)
+			}
+
+			return PagedJSONResult(discussions, pagination)
+		},
+	)
+}
+
+// registerGetIssueRelatedMergeRequests registers the get_issue_related_merge_requests tool.
+func registerGetIssueRelatedMergeRequests(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_issue_related_merge_requests",
+			Description: "List merge requests related to an issue: MRs that mention it or will close it when merged. Use this to check whether a fix is already in flight before starting work on a bug.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"issue_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the issue within the project",
+					},
+					"closing_only": {
+						Type:        "boolean",
+						Description: "If true, return only the MRs that will close the issue when merged (e.g. via 'Closes #123')",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id", "issue_iid"},
+			},
+			OutputSchema: pagedOutputSchema(mergeRequestOutputProperty),
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_issue_related_merge_requests", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			issueIID := GetInt(args, "issue_iid", 0)
+			if issueIID == 0 {
+				return ErrorResult("issue_iid is required")
+			}
+
+			// Build query parameters
+			params := url.Values{}
+
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", strconv.Itoa(page))
+			}
+
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", strconv.Itoa(perPage))
+			}
+
+			relation := "related_merge_requests"
+			if GetBool(args, "closing_only", false) {
+				relation = "closed_by"
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/issues/%d/%s",
+				url.PathEscape(projectID),
+				issueIID,
+				relation,
+			)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var mergeRequests []gitlab.MergeRequest
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &mergeRequests)
+			if err != nil {
+				return APIErrorResult("failed to get related merge requests", err)
+			}
+
+			return PagedJSONResult(mergeRequests, pagination)
+		},
+	)
+}
+
+// registerMoveIssue registers the move_issue tool.
+func registerMoveIssue(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "move_issue",
+			Description: "Move an issue to another project. The original issue is closed and linked to the new one, which gets a new IID in the target project. Notes, labels that exist in the target and other metadata are carried over.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"issue_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the issue within the project",
+					},
+					"to_project_id": {
+						Type:        "string",
+						Description: "The target project - either a numeric ID or URL-encoded path",
+					},
+				},
+				Required: []string{"project_id", "issue_iid", "to_project_id"},
+			},
+			OutputSchema: objectOutputSchema(issueOutputProperty, "id", "iid"),
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "move_issue", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			issueIID := GetInt(args, "issue_iid", 0)
+			if issueIID == 0 {
+				return ErrorResult("issue_iid is required")
+			}
+
+			toProjectID := GetString(args, "to_project_id", "")
+			if toProjectID == "" {
+				return ErrorResult("to_project_id is required")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/issues/%d/move", url.PathEscape(projectID), issueIID)
+			body := map[string]interface{}{
+				"to_project_id": toProjectID,
+			}
+
+			var issue gitlab.Issue
+			if err := c.Client.Post(ctx, endpoint, body, &issue); err != nil {
+				return APIErrorResult("failed to move issue", err)
+			}
+
+			return JSONResult(issue)
+		},
+	)
+}
+
+// registerCloneIssue registers the clone_issue tool.
+func registerCloneIssue(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "clone_issue",
+			Description: "Copy an issue to another project (or the same one), leaving the original open. Returns the new issue.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"issue_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the issue within the project",
+					},
+					"to_project_id": {
+						Type:        "string",
+						Description: "The target project - either a numeric ID or URL-encoded path",
+					},
+					"with_notes": {
+						Type:        "boolean",
+						Description: "Also copy the issue's notes (comments). Default: false",
+					},
+				},
+				Required: []string{"project_id", "issue_iid", "to_project_id"},
+			},
+			OutputSchema: objectOutputSchema(issueOutputProperty, "id", "iid"),
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "clone_issue", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			issueIID := GetInt(args, "issue_iid", 0)
+			if issueIID == 0 {
+				return ErrorResult("issue_iid is required")
+			}
+
+			toProjectID := GetString(args, "to_project_id", "")
+			if toProjectID == "" {
+				return ErrorResult("to_project_id is required")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/issues/%d/clone", url.PathEscape(projectID), issueIID)
+			body := map[string]interface{}{
+				"to_project_id": toProjectID,
+				"with_notes":    GetBool(args, "with_notes", false),
+			}
+
+			var issue gitlab.Issue
+			if err := c.Client.Post(ctx, endpoint, body, &issue); err != nil {
+				return APIErrorResult("failed to clone issue", err)
+			}
+
+			return JSONResult(issue)
+		},
+	)
+}
+
+// registerPromoteIssueToEpic registers the promote_issue_to_epic tool.
+func registerPromoteIssueToEpic(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "promote_issue_to_epic",
+			Description: "Promote an issue to an epic in the project's parent group (GitLab Premium). The issue is closed and linked to the new epic. Runs the /promote quick action, as GitLab has no dedicated API for it.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"issue_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the issue within the project",
+					},
+				},
+				Required: []string{"project_id", "issue_iid"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "promote_issue_to_epic", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			issueIID := GetInt(args, "issue_iid", 0)
+			if issueIID == 0 {
+				return ErrorResult("issue_iid is required")
+			}
+
+			issueEndpoint := fmt.Sprintf("/projects/%s/issues/%d", url.PathEscape(projectID), issueIID)
+			var before gitlab.Issue
+			if err := c.Client.Get(ctx, issueEndpoint, &before); err != nil {
+				return APIErrorResult("failed to get issue", err)
+			}
+
+			notesEndpoint := fmt.Sprintf("/projects/%s/issues/%d/notes", url.PathEscape(projectID), issueIID)
+			var commandResult map[string]interface{}
+			if err := c.Client.Post(ctx, notesEndpoint, map[string]interface{}{"body": "/promote"}, &commandResult); err != nil {
+				return APIErrorResult("failed to promote issue", err)
+			}
+
+			// Quick actions fail silently; promotion closes the issue, so re-read it to confirm
+			var issue gitlab.Issue
+			if err := c.Client.Get(ctx, issueEndpoint, &issue); err != nil {
+				return APIErrorResult("failed to get issue after promotion", err)
+			}
+
+			if before.State != "closed" && issue.State != "closed" {
+				return ErrorResult("issue was not promoted: the /promote quick action had no effect (requires GitLab Premium, a project in a group, and permission to create epics)")
 			}
 
-			return JSONResult(discussions)
+			return JSONResult(map[string]interface{}{
+				"promoted":         true,
+				"issue":            issue,
+				"commands_changes": commandResult["commands_changes"],
+			})
 		},
 	)
 }
 
 // RegisterIssueTools registers all issue-related tools with the MCP server.
-// Includes: list_issues, my_issues, get_issue, create_issue, update_issue,
+// Includes: list_issues, my_issues, list_group_issues, get_issue, create_issue, update_issue,
 // delete_issue, list_issue_links, get_issue_link, create_issue_link,
-// delete_issue_link, list_issue_discussions
+// delete_issue_link, list_issue_discussions, delete_issue_note, get_issue_related_merge_requests,
+// move_issue, clone_issue, promote_issue_to_epic, transition_issue
 func RegisterIssueTools(server *mcp.Server) {
 	registerListIssues(server)
 	registerMyIssues(server)
+	registerListGroupIssues(server)
 	registerGetIssue(server)
 	registerCreateIssue(server)
 	registerUpdateIssue(server)
@@ -859,6 +1353,12 @@ func RegisterIssueTools(server *mcp.Server) {
 	registerCreateIssueLink(server)
 	registerDeleteIssueLink(server)
 	registerListIssueDiscussions(server)
+	registerDeleteIssueNote(server)
+	registerGetIssueRelatedMergeRequests(server)
+	registerMoveIssue(server)
+	registerCloneIssue(server)
+	registerPromoteIssueToEpic(server)
+	registerTransitionIssue(server)
 }
 
 // getIssueIntArray extracts an integer array from arguments map.
@@ -0,0 +1,214 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// jobScopes are the job statuses accepted by the jobs API scope filter.
+var jobScopes = []string{"created", "pending", "running", "failed", "success", "canceled", "skipped", "waiting_for_resource", "manual"}
+
+// JobSearchResult is the result of list_project_jobs when ref, name or date
+// filters are applied. GitLab cannot filter jobs by these, so the newest jobs
+// are scanned and filtered here; Complete reports whether every job that could
+// match was scanned.
+type JobSearchResult struct {
+	Items    []gitlab.Job `json:"items"`
+	Scanned  int          `json:"scanned"`
+	Complete bool         `json:"complete"`
+}
+
+// listProjectJobsOutputSchema covers both the paged and the filtered results.
+var listProjectJobsOutputSchema = func() *mcp.JSONSchema {
+	schema := pagedOutputSchema(jobOutputProperty)
+	schema.Properties["scanned"] = mcp.Property{Type: "integer", Description: "Jobs scanned when filtering by ref, name or date"}
+	schema.Properties["complete"] = mcp.Property{Type: "boolean", Description: "Whether all candidate jobs were scanned"}
+	return schema
+}()
+
+// registerListProjectJobs registers the list_project_jobs tool.
+func registerListProjectJobs(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_project_jobs",
+			Description: "List a project's CI/CD jobs across all pipelines, newest first, e.g. to find the last successful deploy job on main without walking pipelines. Filter by scope (status) and, optionally, by ref, job name and creation date; these last filters scan up to max_scanned recent jobs and return the first per_page matches, with pagination replaced by scanned/complete.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"scope": {
+						Type:        "array",
+						Description: "Only return jobs with these statuses, e.g. [\"success\"] (optional)",
+						Items: &mcp.Property{
+							Type: "string",
+							Enum: jobScopes,
+						},
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Only return jobs that ran for this branch or tag (optional)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Only return jobs with this exact name, e.g. deploy-production (optional)",
+					},
+					"created_after": {
+						Type:        "string",
+						Description: "Only return jobs created on or after this time (ISO 8601, e.g. 2024-01-15T00:00:00Z)",
+					},
+					"created_before": {
+						Type:        "string",
+						Description: "Only return jobs created on or before this time (ISO 8601)",
+					},
+					"max_scanned": {
+						Type:        "integer",
+						Description: "Recent jobs to scan when filtering by ref, name or date (optional, default: 1000)",
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(5000),
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination (ignored when filtering by ref, name or date)",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: listProjectJobsOutputSchema,
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "list_project_jobs", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			var createdAfter, createdBefore time.Time
+			for key, target := range map[string]*time.Time{"created_after": &createdAfter, "created_before": &createdBefore} {
+				if value := GetString(args, key, ""); value != "" {
+					parsed, err := time.Parse(time.RFC3339, value)
+					if err != nil {
+						return ErrorResult(fmt.Sprintf("%s must be an ISO 8601 timestamp, e.g. 2024-01-15T00:00:00Z", key))
+					}
+					*target = parsed
+				}
+			}
+			ref := GetString(args, "ref", "")
+			name := GetString(args, "name", "")
+			perPage := GetInt(args, "per_page", 20)
+
+			params := url.Values{}
+			for _, scope := range GetStringArray(args, "scope") {
+				params.Add("scope[]", scope)
+			}
+			endpoint := fmt.Sprintf("/projects/%s/jobs", url.PathEscape(projectID))
+
+			if ref == "" && name == "" && createdAfter.IsZero() && createdBefore.IsZero() {
+				if page := GetInt(args, "page", 0); page > 0 {
+					params.Set("page", fmt.Sprintf("%d", page))
+				}
+				params.Set("per_page", fmt.Sprintf("%d", perPage))
+
+				var jobs []gitlab.Job
+				pagination, err := c.Client.GetWithPagination(ctx, endpoint+"?"+params.Encode(), &jobs)
+				if err != nil {
+					return APIErrorResult("Failed to list project jobs", err)
+				}
+				return PagedJSONResult(jobs, pagination)
+			}
+
+			filter := func(job gitlab.Job) bool {
+				if ref != "" && job.Ref != ref {
+					return false
+				}
+				if name != "" && job.Name != name {
+					return false
+				}
+				if !createdBefore.IsZero() && job.CreatedAt != nil && job.CreatedAt.After(createdBefore) {
+					return false
+				}
+				return createdAfter.IsZero() || (job.CreatedAt != nil && !job.CreatedAt.Before(createdAfter))
+			}
+			// Jobs are listed newest first, so older ones can never match
+			tooOld := func(job gitlab.Job) bool {
+				return !createdAfter.IsZero() && job.CreatedAt != nil && job.CreatedAt.Before(createdAfter)
+			}
+
+			result, err := scanJobs(ctx, c, endpoint, params, GetInt(args, "max_scanned", maxCollectedItems), perPage, filter, tooOld)
+			if err != nil {
+				return APIErrorResult("Failed to list project jobs", err)
+			}
+			return JSONResult(result)
+		},
+	)
+}
+
+// scanJobs reads jobs page by page until limit matching jobs were found, a job
+// is past the stop condition, the list ends, or maxScanned jobs were read.
+func scanJobs(ctx context.Context, c *ToolContext, endpoint string, params url.Values, maxScanned, limit int, match, stop func(gitlab.Job) bool) (*JobSearchResult, error) {
+	result := &JobSearchResult{Items: []gitlab.Job{}}
+	base := endpoint + "?"
+	if len(params) > 0 {
+		base += params.Encode() + "&"
+	}
+
+	for page := 1; ; page++ {
+		// Jobs are decoded one at a time; only matching ones are kept
+		batch, done := 0, false
+		pagination, err := eachItem(ctx, c.Client, fmt.Sprintf("%spage=%d&per_page=100", base, page), func(job gitlab.Job) error {
+			batch++
+			if result.Scanned >= maxScanned {
+				done = true
+				return gitlab.ErrStop
+			}
+			result.Scanned++
+			if stop(job) {
+				result.Complete, done = true, true
+				return gitlab.ErrStop
+			}
+			if match(job) {
+				result.Items = append(result.Items, job)
+				if len(result.Items) >= limit {
+					result.Complete, done = true, true
+					return gitlab.ErrStop
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return result, nil
+		}
+		if pagination == nil || pagination.NextPage == 0 || batch == 0 {
+			result.Complete = true
+			return result, nil
+		}
+		mcp.ReportProgress(ctx, float64(result.Scanned), float64(maxScanned), fmt.Sprintf("Scanned %d jobs", result.Scanned))
+	}
+}
@@ -2,6 +2,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -63,12 +64,12 @@ func registerListLabels(server *mcp.Server) {
 				Required: []string{"project_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("list_labels", args)
+			c.Logger.ToolCall(ctx, "list_labels", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -104,11 +105,12 @@ func registerListLabels(server *mcp.Server) {
 			}
 
 			var labels []Label
-			if err := ctx.Client.Get(endpoint, &labels); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to list labels: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &labels)
+			if err != nil {
+				return APIErrorResult("failed to list labels", err)
 			}
 
-			return JSONResult(labels)
+			return PagedJSONResult(labels, pagination)
 		},
 	)
 }
@@ -137,12 +139,12 @@ func registerGetLabel(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_label", args)
+			c.Logger.ToolCall(ctx, "get_label", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -160,8 +162,8 @@ func registerGetLabel(server *mcp.Server) {
 			)
 
 			var label Label
-			if err := ctx.Client.Get(endpoint, &label); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to get label: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &label); err != nil {
+				return APIErrorResult("failed to get label", err)
 			}
 
 			return JSONResult(label)
@@ -202,12 +204,12 @@ func registerCreateLabel(server *mcp.Server) {
 				Required: []string{"project_id", "name", "color"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("create_label", args)
+			c.Logger.ToolCall(ctx, "create_label", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -243,8 +245,8 @@ func registerCreateLabel(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/labels", url.PathEscape(projectID))
 
 			var label Label
-			if err := ctx.Client.Post(endpoint, body, &label); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to create label: %v", err))
+			if err := c.Client.Post(ctx, endpoint, body, &label); err != nil {
+				return APIErrorResult("failed to create label", err)
 			}
 
 			return JSONResult(label)
@@ -289,12 +291,12 @@ func registerUpdateLabel(server *mcp.Server) {
 				Required: []string{"project_id", "label_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("update_label", args)
+			c.Logger.ToolCall(ctx, "update_label", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -337,8 +339,8 @@ func registerUpdateLabel(server *mcp.Server) {
 			)
 
 			var label Label
-			if err := ctx.Client.Put(endpoint, body, &label); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to update label: %v", err))
+			if err := c.Client.Put(ctx, endpoint, body, &label); err != nil {
+				return APIErrorResult("failed to update label", err)
 			}
 
 			return JSONResult(label)
@@ -367,12 +369,12 @@ func registerDeleteLabel(server *mcp.Server) {
 				Required: []string{"project_id", "label_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("delete_label", args)
+			c.Logger.ToolCall(ctx, "delete_label", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -389,8 +391,8 @@ func registerDeleteLabel(server *mcp.Server) {
 				url.PathEscape(labelID),
 			)
 
-			if err := ctx.Client.Delete(endpoint); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to delete label: %v", err))
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("failed to delete label", err)
 			}
 
 			return TextResult(fmt.Sprintf("Label '%s' deleted successfully", labelID))
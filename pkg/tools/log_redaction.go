@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/redact"
+)
+
+// builtinLogRedactor masks the builtin secret rules when the configuration
+// provides no redactor.
+var builtinLogRedactor, _ = redact.New(redact.SecretRules, nil)
+
+// redactLogsByDefault reports whether job logs are redacted unless a tool call
+// opts out (GITLAB_REDACT_LOGS).
+func redactLogsByDefault(c *ToolContext) bool {
+	return c.Config == nil || c.Config.RedactLogs
+}
+
+// redactJobLog masks secrets in a job log when enabled and returns the log and
+// the number of masked secrets.
+func redactJobLog(ctx context.Context, c *ToolContext, trace string, enabled bool) (string, int) {
+	if !enabled {
+		return trace, 0
+	}
+	redactor := builtinLogRedactor
+	if c.Config != nil && c.Config.LogRedactor != nil {
+		redactor = c.Config.LogRedactor
+	}
+	trace, n := redactor.Redact(trace)
+	if n > 0 {
+		c.Logger.InfoContext(ctx, "Redacted %d secret(s) from a job log", n)
+	}
+	return trace, n
+}
@@ -0,0 +1,5 @@
+package tools
+
+// The tools declared in manifest.yaml are generated into manifest_gen.go by
+// internal/toolgen.
+//go:generate go run ./internal/gentools -manifest manifest.yaml -out manifest_gen.go
@@ -0,0 +1,1083 @@
+// Code generated by toolgen from manifest.yaml; DO NOT EDIT.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+type deleteForkRelationshipArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+}
+
+// registerDeleteForkRelationship registers the delete_fork_relationship tool.
+func registerDeleteForkRelationship(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "delete_fork_relationship",
+			Description: "Remove the fork relationship of a project, turning it into a standalone project. Merge requests to the former upstream can no longer be opened from it. Requires Owner role.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier of the fork - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		withArgs("delete_fork_relationship", func(ctx context.Context, c *ToolContext, args deleteForkRelationshipArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot delete fork relationship: server is in read-only mode")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/fork", url.PathEscape(args.ProjectID))
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("Failed to delete fork relationship", err)
+			}
+
+			return TextResult(fmt.Sprintf("Fork relationship of project %s removed", args.ProjectID))
+		}),
+	)
+}
+
+type listProjectMembersArgs struct {
+	PageArgs
+	ProjectID string `json:"project_id" validate:"required"`
+}
+
+// registerListProjectMembers registers the list_project_members tool.
+func registerListProjectMembers(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_project_members",
+			Description: "List all members of a GitLab project. Returns an array of member objects with username, name, access level (10=Guest, 20=Reporter, 30=Developer, 40=Maintainer, 50=Owner), and membership details.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("list_project_members", func(ctx context.Context, c *ToolContext, args listProjectMembersArgs) (*mcp.CallToolResult, error) {
+			endpoint := fmt.Sprintf("/projects/%s/members", url.PathEscape(args.ProjectID))
+
+			params := url.Values{}
+			args.setParams(params)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var result []Member
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &result)
+			if err != nil {
+				return APIErrorResult("Failed to list project members", err)
+			}
+
+			return PagedJSONResult(result, pagination)
+		}),
+	)
+}
+
+type listFreezePeriodsArgs struct {
+	PageArgs
+	ProjectID string `json:"project_id" validate:"required"`
+}
+
+// registerListFreezePeriods registers the list_freeze_periods tool.
+func registerListFreezePeriods(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_freeze_periods",
+			Description: "List the deploy freeze periods of a project. Each period is a pair of cron expressions (freeze_start, freeze_end) in cron_timezone. Use get_deploy_freeze_status to check whether a freeze is in effect now.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("list_freeze_periods", func(ctx context.Context, c *ToolContext, args listFreezePeriodsArgs) (*mcp.CallToolResult, error) {
+			endpoint := fmt.Sprintf("/projects/%s/freeze_periods", url.PathEscape(args.ProjectID))
+
+			params := url.Values{}
+			args.setParams(params)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var result []FreezePeriod
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &result)
+			if err != nil {
+				return APIErrorResult("failed to list freeze periods", err)
+			}
+
+			return PagedJSONResult(result, pagination)
+		}),
+	)
+}
+
+type deleteFreezePeriodArgs struct {
+	ProjectID      string `json:"project_id" validate:"required"`
+	FreezePeriodID int    `json:"freeze_period_id" validate:"required,min=1"`
+}
+
+// registerDeleteFreezePeriod registers the delete_freeze_period tool.
+func registerDeleteFreezePeriod(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "delete_freeze_period",
+			Description: "Delete a deploy freeze period.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"freeze_period_id": {
+						Type:        "integer",
+						Description: "The ID of the freeze period",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"project_id", "freeze_period_id"},
+			},
+		},
+		withArgs("delete_freeze_period", func(ctx context.Context, c *ToolContext, args deleteFreezePeriodArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot delete freeze period: server is in read-only mode")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/freeze_periods/%d", url.PathEscape(args.ProjectID), args.FreezePeriodID)
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("failed to delete freeze period", err)
+			}
+
+			return TextResult(fmt.Sprintf("Freeze period %d deleted", args.FreezePeriodID))
+		}),
+	)
+}
+
+type getGitlabVersionArgs struct {
+}
+
+// registerGetGitlabVersion registers the get_gitlab_version tool.
+func registerGetGitlabVersion(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_gitlab_version",
+			Description: "Get the version and revision of the GitLab instance, whether it is the Enterprise Edition, and the version of its agent server (KAS). Use it to check whether an API feature is available before relying on it.",
+			InputSchema: mcp.JSONSchema{
+				Type:       "object",
+				Properties: map[string]mcp.Property{},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_gitlab_version", func(ctx context.Context, c *ToolContext, args getGitlabVersionArgs) (*mcp.CallToolResult, error) {
+			endpoint := "/version"
+
+			var result GitLabVersion
+			if err := c.Client.Get(ctx, endpoint, &result); err != nil {
+				return APIErrorResult("Failed to get GitLab version", err)
+			}
+
+			return JSONResult(result)
+		}),
+	)
+}
+
+type listBroadcastMessagesArgs struct {
+	PageArgs
+}
+
+// registerListBroadcastMessages registers the list_broadcast_messages tool.
+func registerListBroadcastMessages(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_broadcast_messages",
+			Description: "List the broadcast messages of the GitLab instance, the banners and notifications shown to users, with their schedule, target paths and access levels, and whether each is active now.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("list_broadcast_messages", func(ctx context.Context, c *ToolContext, args listBroadcastMessagesArgs) (*mcp.CallToolResult, error) {
+			endpoint := "/broadcast_messages"
+
+			params := url.Values{}
+			args.setParams(params)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var result []BroadcastMessage
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &result)
+			if err != nil {
+				return APIErrorResult("Failed to list broadcast messages", err)
+			}
+
+			return PagedJSONResult(result, pagination)
+		}),
+	)
+}
+
+type getBroadcastMessageArgs struct {
+	BroadcastMessageID int `json:"broadcast_message_id" validate:"required,min=1"`
+}
+
+// registerGetBroadcastMessage registers the get_broadcast_message tool.
+func registerGetBroadcastMessage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_broadcast_message",
+			Description: "Get one broadcast message of the GitLab instance.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"broadcast_message_id": {
+						Type:        "integer",
+						Description: "The ID of the broadcast message",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"broadcast_message_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_broadcast_message", func(ctx context.Context, c *ToolContext, args getBroadcastMessageArgs) (*mcp.CallToolResult, error) {
+			endpoint := fmt.Sprintf("/broadcast_messages/%d", args.BroadcastMessageID)
+
+			var result BroadcastMessage
+			if err := c.Client.Get(ctx, endpoint, &result); err != nil {
+				return APIErrorResult("Failed to get broadcast message", err)
+			}
+
+			return JSONResult(result)
+		}),
+	)
+}
+
+type createBroadcastMessageArgs struct {
+	Message            string   `json:"message" validate:"required"`
+	StartsAt           string   `json:"starts_at"`
+	EndsAt             string   `json:"ends_at"`
+	BroadcastType      string   `json:"broadcast_type" validate:"oneof=banner notification"`
+	Theme              string   `json:"theme" validate:"oneof=indigo light-indigo blue light-blue green light-green red light-red dark light"`
+	TargetPath         string   `json:"target_path"`
+	TargetAccessLevels []string `json:"target_access_levels"`
+	Dismissable        *bool    `json:"dismissable"`
+}
+
+// registerCreateBroadcastMessage registers the create_broadcast_message tool.
+func registerCreateBroadcastMessage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "create_broadcast_message",
+			Description: "Create a broadcast message shown to the users of the GitLab instance, e.g. a maintenance announcement. Without starts_at it shows from now, and without ends_at for one hour. Requires an administrator token.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"message": {
+						Type:        "string",
+						Description: "The message to show, in Markdown",
+					},
+					"starts_at": {
+						Type:        "string",
+						Description: "When the message starts showing, in ISO 8601 (e.g., 2024-06-01T22:00:00Z)",
+					},
+					"ends_at": {
+						Type:        "string",
+						Description: "When the message stops showing, in ISO 8601",
+					},
+					"broadcast_type": {
+						Type:        "string",
+						Description: "banner (a bar at the top of every page) or notification (a dismissable popup). Default: banner",
+						Enum:        []string{"banner", "notification"},
+					},
+					"theme": {
+						Type:        "string",
+						Description: "Color theme of the message",
+						Enum:        []string{"indigo", "light-indigo", "blue", "light-blue", "green", "light-green", "red", "light-red", "dark", "light"},
+					},
+					"target_path": {
+						Type:        "string",
+						Description: "Only show the message on paths matching this pattern, e.g. */my-group/*",
+					},
+					"target_access_levels": {
+						Type:        "array",
+						Description: "Only show the message to members with these access levels: 10 (guest), 20 (reporter), 30 (developer), 40 (maintainer) or 50 (owner)",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"dismissable": {
+						Type:        "boolean",
+						Description: "Whether users can dismiss the message",
+					},
+				},
+				Required: []string{"message"},
+			},
+		},
+		withArgs("create_broadcast_message", func(ctx context.Context, c *ToolContext, args createBroadcastMessageArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot create broadcast message: server is in read-only mode")
+			}
+
+			endpoint := "/broadcast_messages"
+
+			body := map[string]interface{}{}
+			if args.BroadcastType != "" {
+				body["broadcast_type"] = args.BroadcastType
+			}
+			if args.Dismissable != nil {
+				body["dismissable"] = *args.Dismissable
+			}
+			if args.EndsAt != "" {
+				body["ends_at"] = args.EndsAt
+			}
+			body["message"] = args.Message
+			if args.StartsAt != "" {
+				body["starts_at"] = args.StartsAt
+			}
+			if len(args.TargetAccessLevels) > 0 {
+				body["target_access_levels"] = args.TargetAccessLevels
+			}
+			if args.TargetPath != "" {
+				body["target_path"] = args.TargetPath
+			}
+			if args.Theme != "" {
+				body["theme"] = args.Theme
+			}
+
+			var result BroadcastMessage
+			if err := c.Client.Post(ctx, endpoint, body, &result); err != nil {
+				return APIErrorResult("Failed to create broadcast message", err)
+			}
+
+			return JSONResult(result)
+		}),
+	)
+}
+
+type updateBroadcastMessageArgs struct {
+	BroadcastMessageID int      `json:"broadcast_message_id" validate:"required,min=1"`
+	Message            string   `json:"message"`
+	StartsAt           string   `json:"starts_at"`
+	EndsAt             string   `json:"ends_at"`
+	BroadcastType      string   `json:"broadcast_type" validate:"oneof=banner notification"`
+	Theme              string   `json:"theme" validate:"oneof=indigo light-indigo blue light-blue green light-green red light-red dark light"`
+	TargetPath         string   `json:"target_path"`
+	TargetAccessLevels []string `json:"target_access_levels"`
+	Dismissable        *bool    `json:"dismissable"`
+}
+
+// registerUpdateBroadcastMessage registers the update_broadcast_message tool.
+func registerUpdateBroadcastMessage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "update_broadcast_message",
+			Description: "Update a broadcast message of the GitLab instance. Only the given fields change; set ends_at to now to end an announcement early. Requires an administrator token.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"broadcast_message_id": {
+						Type:        "integer",
+						Description: "The ID of the broadcast message",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"message": {
+						Type:        "string",
+						Description: "The message to show, in Markdown",
+					},
+					"starts_at": {
+						Type:        "string",
+						Description: "When the message starts showing, in ISO 8601 (e.g., 2024-06-01T22:00:00Z)",
+					},
+					"ends_at": {
+						Type:        "string",
+						Description: "When the message stops showing, in ISO 8601",
+					},
+					"broadcast_type": {
+						Type:        "string",
+						Description: "banner (a bar at the top of every page) or notification (a dismissable popup). Default: banner",
+						Enum:        []string{"banner", "notification"},
+					},
+					"theme": {
+						Type:        "string",
+						Description: "Color theme of the message",
+						Enum:        []string{"indigo", "light-indigo", "blue", "light-blue", "green", "light-green", "red", "light-red", "dark", "light"},
+					},
+					"target_path": {
+						Type:        "string",
+						Description: "Only show the message on paths matching this pattern, e.g. */my-group/*",
+					},
+					"target_access_levels": {
+						Type:        "array",
+						Description: "Only show the message to members with these access levels: 10 (guest), 20 (reporter), 30 (developer), 40 (maintainer) or 50 (owner)",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"dismissable": {
+						Type:        "boolean",
+						Description: "Whether users can dismiss the message",
+					},
+				},
+				Required: []string{"broadcast_message_id"},
+			},
+		},
+		withArgs("update_broadcast_message", func(ctx context.Context, c *ToolContext, args updateBroadcastMessageArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot update broadcast message: server is in read-only mode")
+			}
+
+			endpoint := fmt.Sprintf("/broadcast_messages/%d", args.BroadcastMessageID)
+
+			body := map[string]interface{}{}
+			if args.BroadcastType != "" {
+				body["broadcast_type"] = args.BroadcastType
+			}
+			if args.Dismissable != nil {
+				body["dismissable"] = *args.Dismissable
+			}
+			if args.EndsAt != "" {
+				body["ends_at"] = args.EndsAt
+			}
+			if args.Message != "" {
+				body["message"] = args.Message
+			}
+			if args.StartsAt != "" {
+				body["starts_at"] = args.StartsAt
+			}
+			if len(args.TargetAccessLevels) > 0 {
+				body["target_access_levels"] = args.TargetAccessLevels
+			}
+			if args.TargetPath != "" {
+				body["target_path"] = args.TargetPath
+			}
+			if args.Theme != "" {
+				body["theme"] = args.Theme
+			}
+
+			var result BroadcastMessage
+			if err := c.Client.Put(ctx, endpoint, body, &result); err != nil {
+				return APIErrorResult("Failed to update broadcast message", err)
+			}
+
+			return JSONResult(result)
+		}),
+	)
+}
+
+type deleteBroadcastMessageArgs struct {
+	BroadcastMessageID int `json:"broadcast_message_id" validate:"required,min=1"`
+}
+
+// registerDeleteBroadcastMessage registers the delete_broadcast_message tool.
+func registerDeleteBroadcastMessage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "delete_broadcast_message",
+			Description: "Delete a broadcast message of the GitLab instance. Requires an administrator token.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"broadcast_message_id": {
+						Type:        "integer",
+						Description: "The ID of the broadcast message",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"broadcast_message_id"},
+			},
+		},
+		withArgs("delete_broadcast_message", func(ctx context.Context, c *ToolContext, args deleteBroadcastMessageArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot delete broadcast message: server is in read-only mode")
+			}
+
+			endpoint := fmt.Sprintf("/broadcast_messages/%d", args.BroadcastMessageID)
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("Failed to delete broadcast message", err)
+			}
+
+			return TextResult(fmt.Sprintf("Broadcast message %d deleted", args.BroadcastMessageID))
+		}),
+	)
+}
+
+type listSshKeysArgs struct {
+	PageArgs
+}
+
+// registerListSshKeys registers the list_ssh_keys tool.
+func registerListSshKeys(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_ssh_keys",
+			Description: "List the SSH keys of the authenticated user, with their ID, title, public key, usage type and expiry date.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("list_ssh_keys", func(ctx context.Context, c *ToolContext, args listSshKeysArgs) (*mcp.CallToolResult, error) {
+			endpoint := "/user/keys"
+
+			params := url.Values{}
+			args.setParams(params)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var result []SSHKey
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &result)
+			if err != nil {
+				return APIErrorResult("Failed to list SSH keys", err)
+			}
+
+			return PagedJSONResult(result, pagination)
+		}),
+	)
+}
+
+type addSshKeyArgs struct {
+	Title     string `json:"title" validate:"required"`
+	Key       string `json:"key" validate:"required"`
+	ExpiresAt string `json:"expires_at"`
+	UsageType string `json:"usage_type" validate:"oneof=auth signing auth_and_signing"`
+}
+
+// registerAddSshKey registers the add_ssh_key tool.
+func registerAddSshKey(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "add_ssh_key",
+			Description: "Add an SSH public key to the authenticated user, or to the user given as sudo when the server allows it, e.g. when onboarding a developer. Keys of types GitLab does not allow on the instance are rejected.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"title": {
+						Type:        "string",
+						Description: "A title for the key, e.g. the machine it is used on",
+					},
+					"key": {
+						Type:        "string",
+						Description: "The public key, e.g. \"ssh-ed25519 AAAA... user@host\"",
+					},
+					"expires_at": {
+						Type:        "string",
+						Description: "When the key expires, in ISO 8601 (e.g., 2025-12-31T00:00:00Z). Default: never",
+					},
+					"usage_type": {
+						Type:        "string",
+						Description: "What the key may be used for. Default: auth_and_signing",
+						Enum:        []string{"auth", "signing", "auth_and_signing"},
+					},
+				},
+				Required: []string{"title", "key"},
+			},
+		},
+		withArgs("add_ssh_key", func(ctx context.Context, c *ToolContext, args addSshKeyArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot add SSH key: server is in read-only mode")
+			}
+
+			endpoint := "/user/keys"
+
+			body := map[string]interface{}{}
+			if args.ExpiresAt != "" {
+				body["expires_at"] = args.ExpiresAt
+			}
+			body["key"] = args.Key
+			body["title"] = args.Title
+			if args.UsageType != "" {
+				body["usage_type"] = args.UsageType
+			}
+
+			var result SSHKey
+			if err := c.Client.Post(ctx, endpoint, body, &result); err != nil {
+				return APIErrorResult("Failed to add SSH key", err)
+			}
+
+			return JSONResult(result)
+		}),
+	)
+}
+
+type deleteSshKeyArgs struct {
+	KeyID int `json:"key_id" validate:"required,min=1"`
+}
+
+// registerDeleteSshKey registers the delete_ssh_key tool.
+func registerDeleteSshKey(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "delete_ssh_key",
+			Description: "Delete an SSH key of the authenticated user. Git operations over SSH with that key stop working.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"key_id": {
+						Type:        "integer",
+						Description: "The ID of the key, from list_ssh_keys",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"key_id"},
+			},
+		},
+		withArgs("delete_ssh_key", func(ctx context.Context, c *ToolContext, args deleteSshKeyArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot delete SSH key: server is in read-only mode")
+			}
+
+			endpoint := fmt.Sprintf("/user/keys/%d", args.KeyID)
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("Failed to delete SSH key", err)
+			}
+
+			return TextResult(fmt.Sprintf("SSH key %d deleted", args.KeyID))
+		}),
+	)
+}
+
+type listGpgKeysArgs struct {
+	PageArgs
+}
+
+// registerListGpgKeys registers the list_gpg_keys tool.
+func registerListGpgKeys(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_gpg_keys",
+			Description: "List the GPG keys of the authenticated user, used to verify the signatures of their commits.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("list_gpg_keys", func(ctx context.Context, c *ToolContext, args listGpgKeysArgs) (*mcp.CallToolResult, error) {
+			endpoint := "/user/gpg_keys"
+
+			params := url.Values{}
+			args.setParams(params)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var result []GPGKey
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &result)
+			if err != nil {
+				return APIErrorResult("Failed to list GPG keys", err)
+			}
+
+			return PagedJSONResult(result, pagination)
+		}),
+	)
+}
+
+type addGpgKeyArgs struct {
+	Key string `json:"key" validate:"required"`
+}
+
+// registerAddGpgKey registers the add_gpg_key tool.
+func registerAddGpgKey(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "add_gpg_key",
+			Description: "Add a GPG public key to the authenticated user, or to the user given as sudo when the server allows it, so commits signed with it show as verified. The key must carry an email address verified on the account.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"key": {
+						Type:        "string",
+						Description: "The ASCII-armored public key, starting with -----BEGIN PGP PUBLIC KEY BLOCK-----",
+					},
+				},
+				Required: []string{"key"},
+			},
+		},
+		withArgs("add_gpg_key", func(ctx context.Context, c *ToolContext, args addGpgKeyArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot add GPG key: server is in read-only mode")
+			}
+
+			endpoint := "/user/gpg_keys"
+
+			body := map[string]interface{}{}
+			body["key"] = args.Key
+
+			var result GPGKey
+			if err := c.Client.Post(ctx, endpoint, body, &result); err != nil {
+				return APIErrorResult("Failed to add GPG key", err)
+			}
+
+			return JSONResult(result)
+		}),
+	)
+}
+
+type deleteGpgKeyArgs struct {
+	KeyID int `json:"key_id" validate:"required,min=1"`
+}
+
+// registerDeleteGpgKey registers the delete_gpg_key tool.
+func registerDeleteGpgKey(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "delete_gpg_key",
+			Description: "Delete a GPG key of the authenticated user. Commits signed with it no longer show as verified.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"key_id": {
+						Type:        "integer",
+						Description: "The ID of the key, from list_gpg_keys",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"key_id"},
+			},
+		},
+		withArgs("delete_gpg_key", func(ctx context.Context, c *ToolContext, args deleteGpgKeyArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot delete GPG key: server is in read-only mode")
+			}
+
+			endpoint := fmt.Sprintf("/user/gpg_keys/%d", args.KeyID)
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("Failed to delete GPG key", err)
+			}
+
+			return TextResult(fmt.Sprintf("GPG key %d deleted", args.KeyID))
+		}),
+	)
+}
+
+type listClusterAgentsArgs struct {
+	PageArgs
+	ProjectID string `json:"project_id" validate:"required"`
+}
+
+// registerListClusterAgents registers the list_cluster_agents tool.
+func registerListClusterAgents(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_cluster_agents",
+			Description: "List the GitLab agents for Kubernetes registered to a project, with the project holding each agent's configuration. Use get_cluster_agent_config to read an agent's config file and get_agent_tokens to check its tokens are in use.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("list_cluster_agents", func(ctx context.Context, c *ToolContext, args listClusterAgentsArgs) (*mcp.CallToolResult, error) {
+			endpoint := fmt.Sprintf("/projects/%s/cluster_agents", url.PathEscape(args.ProjectID))
+
+			params := url.Values{}
+			args.setParams(params)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var result []ClusterAgent
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &result)
+			if err != nil {
+				return APIErrorResult("Failed to list cluster agents", err)
+			}
+
+			return PagedJSONResult(result, pagination)
+		}),
+	)
+}
+
+type getAgentTokensArgs struct {
+	PageArgs
+	ProjectID string `json:"project_id" validate:"required"`
+	AgentID   int    `json:"agent_id" validate:"required,min=1"`
+}
+
+// registerGetAgentTokens registers the get_agent_tokens tool.
+func registerGetAgentTokens(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_agent_tokens",
+			Description: "List the active tokens of a GitLab agent for Kubernetes with their status and when each was last used. An agent whose tokens have never been used is not connected to a cluster.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"agent_id": {
+						Type:        "integer",
+						Description: "The ID of the agent, from list_cluster_agents",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id", "agent_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_agent_tokens", func(ctx context.Context, c *ToolContext, args getAgentTokensArgs) (*mcp.CallToolResult, error) {
+			endpoint := fmt.Sprintf("/projects/%s/cluster_agents/%d/tokens", url.PathEscape(args.ProjectID), args.AgentID)
+
+			params := url.Values{}
+			args.setParams(params)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var result []AgentToken
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &result)
+			if err != nil {
+				return APIErrorResult("Failed to list agent tokens", err)
+			}
+
+			return PagedJSONResult(result, pagination)
+		}),
+	)
+}
+
+type createAgentTokenArgs struct {
+	ProjectID   string `json:"project_id" validate:"required"`
+	AgentID     int    `json:"agent_id" validate:"required,min=1"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+// registerCreateAgentToken registers the create_agent_token tool.
+func registerCreateAgentToken(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "create_agent_token",
+			Description: "Create a token for a GitLab agent for Kubernetes, to install or reconnect the agent in a cluster (helm install ... --set config.token=<token>). The token value is only returned once, by this call; it is masked when the server redacts output. An agent can have at most two active tokens, so revoke an unused one first to rotate.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"agent_id": {
+						Type:        "integer",
+						Description: "The ID of the agent, from list_cluster_agents",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"name": {
+						Type:        "string",
+						Description: "The name of the token, e.g. the cluster it is for",
+					},
+					"description": {
+						Type:        "string",
+						Description: "What the token is used for",
+					},
+				},
+				Required: []string{"project_id", "agent_id", "name"},
+			},
+		},
+		withArgs("create_agent_token", func(ctx context.Context, c *ToolContext, args createAgentTokenArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot create agent token: server is in read-only mode")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/cluster_agents/%d/tokens", url.PathEscape(args.ProjectID), args.AgentID)
+
+			body := map[string]interface{}{}
+			if args.Description != "" {
+				body["description"] = args.Description
+			}
+			body["name"] = args.Name
+
+			var result AgentToken
+			if err := c.Client.Post(ctx, endpoint, body, &result); err != nil {
+				return APIErrorResult("Failed to create agent token", err)
+			}
+
+			return JSONResult(result)
+		}),
+	)
+}
+
+type revokeAgentTokenArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	AgentID   int    `json:"agent_id" validate:"required,min=1"`
+	TokenID   int    `json:"token_id" validate:"required,min=1"`
+}
+
+// registerRevokeAgentToken registers the revoke_agent_token tool.
+func registerRevokeAgentToken(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "revoke_agent_token",
+			Description: "Revoke a token of a GitLab agent for Kubernetes. The agent installed with it disconnects from GitLab.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"agent_id": {
+						Type:        "integer",
+						Description: "The ID of the agent, from list_cluster_agents",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"token_id": {
+						Type:        "integer",
+						Description: "The ID of the token, from get_agent_tokens",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"project_id", "agent_id", "token_id"},
+			},
+		},
+		withArgs("revoke_agent_token", func(ctx context.Context, c *ToolContext, args revokeAgentTokenArgs) (*mcp.CallToolResult, error) {
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot revoke agent token: server is in read-only mode")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/cluster_agents/%d/tokens/%d", url.PathEscape(args.ProjectID), args.AgentID, args.TokenID)
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("Failed to revoke agent token", err)
+			}
+
+			return TextResult(fmt.Sprintf("Token %d of agent %d revoked", args.TokenID, args.AgentID))
+		}),
+	)
+}
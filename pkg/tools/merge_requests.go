@@ -1,8 +1,10 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
@@ -38,15 +40,167 @@ type Discussion struct {
 	Notes          []gitlab.Note `json:"notes"`
 }
 
+// mergeRequestFilterProperties are the advanced merge request filters shared by
+// merge request list tools.
+var mergeRequestFilterProperties = map[string]mcp.Property{
+	"labels": {
+		Type:        "string",
+		Description: "Comma-separated list of label names; MRs must have all of them. None or Any match MRs without or with any label",
+	},
+	"milestone": {
+		Type:        "string",
+		Description: "Milestone title to filter by, or None/Any",
+	},
+	"author_id": {
+		Type:        "integer",
+		Description: "Return MRs created by this user ID (mutually exclusive with author_username)",
+	},
+	"author_username": {
+		Type:        "string",
+		Description: "Return MRs created by this username",
+	},
+	"assignee_username": {
+		Type:        "string",
+		Description: "Return MRs assigned to this username",
+	},
+	"reviewer_id": {
+		Type:        "string",
+		Description: "Return MRs with this user ID as a reviewer, or None/Any (mutually exclusive with reviewer_username)",
+	},
+	"reviewer_username": {
+		Type:        "string",
+		Description: "Return MRs with this username as a reviewer",
+	},
+	"source_branch": {
+		Type:        "string",
+		Description: "Return MRs with this source branch",
+	},
+	"target_branch": {
+		Type:        "string",
+		Description: "Return MRs with this target branch",
+	},
+	"search": {
+		Type:        "string",
+		Description: "Search MRs against their title and description",
+	},
+	"wip": {
+		Type:        "string",
+		Description: "Filter by draft status: yes for drafts only, no to exclude drafts",
+		Enum:        []string{"yes", "no"},
+	},
+	"created_after": {
+		Type:        "string",
+		Description: "Return MRs created on or after this time (ISO 8601, e.g. 2024-01-15T00:00:00Z)",
+	},
+	"created_before": {
+		Type:        "string",
+		Description: "Return MRs created on or before this time (ISO 8601)",
+	},
+	"updated_after": {
+		Type:        "string",
+		Description: "Return MRs updated on or after this time (ISO 8601)",
+	},
+	"updated_before": {
+		Type:        "string",
+		Description: "Return MRs updated on or before this time (ISO 8601)",
+	},
+}
+
+// withMergeRequestFilters adds mergeRequestFilterProperties to a tool's input
+// properties.
+func withMergeRequestFilters(properties map[string]mcp.Property) map[string]mcp.Property {
+	for name, property := range mergeRequestFilterProperties {
+		properties[name] = property
+	}
+	return properties
+}
+
+// setMergeRequestFilterParams copies the mergeRequestFilterProperties arguments
+// to GitLab query parameters.
+func setMergeRequestFilterParams(args map[string]interface{}, params url.Values) {
+	for _, key := range []string{"labels", "milestone", "author_username", "assignee_username", "reviewer_id",
+		"reviewer_username", "source_branch", "target_branch", "search", "wip",
+		"created_after", "created_before", "updated_after", "updated_before"} {
+		if value := GetString(args, key, ""); value != "" {
+			params.Set(key, value)
+		}
+	}
+
+	if authorID := GetInt(args, "author_id", 0); authorID > 0 {
+		params.Set("author_id", fmt.Sprintf("%d", authorID))
+	}
+}
+
+// mergeRequestAttributeProperties are the optional merge request attributes
+// shared by create_merge_request and update_merge_request.
+var mergeRequestAttributeProperties = map[string]mcp.Property{
+	"assignee_ids": {
+		Type:        "array",
+		Description: "User IDs to assign the merge request to (replaces existing assignees; empty array unassigns all)",
+		Items:       &mcp.Property{Type: "integer"},
+	},
+	"reviewer_ids": {
+		Type:        "array",
+		Description: "User IDs to request reviews from (replaces existing reviewers; empty array removes all)",
+		Items:       &mcp.Property{Type: "integer"},
+	},
+	"labels": {
+		Type:        "string",
+		Description: "Comma-separated list of label names (replaces existing labels; empty string removes all)",
+	},
+	"milestone_id": {
+		Type:        "integer",
+		Description: "The ID of a milestone to assign the merge request to (0 unassigns)",
+	},
+	"squash": {
+		Type:        "boolean",
+		Description: "Squash commits into a single commit when merging",
+	},
+	"allow_collaboration": {
+		Type:        "boolean",
+		Description: "Allow commits from members who can merge to the target branch (fork MRs only)",
+	},
+}
+
+// withMergeRequestAttributes adds mergeRequestAttributeProperties to a tool's
+// input properties.
+func withMergeRequestAttributes(properties map[string]mcp.Property) map[string]mcp.Property {
+	for name, property := range mergeRequestAttributeProperties {
+		properties[name] = property
+	}
+	return properties
+}
+
+// setMergeRequestAttributes copies the mergeRequestAttributeProperties arguments
+// that were provided to a create or update request body.
+func setMergeRequestAttributes(args map[string]interface{}, body map[string]interface{}) {
+	for _, key := range []string{"assignee_ids", "reviewer_ids"} {
+		if _, exists := args[key]; exists {
+			body[key] = getIssueIntArray(args, key)
+		}
+	}
+	if labels, ok := args["labels"].(string); ok {
+		body["labels"] = labels
+	}
+	if _, exists := args["milestone_id"]; exists {
+		body["milestone_id"] = GetInt(args, "milestone_id", 0)
+	}
+	for _, key := range []string{"squash", "allow_collaboration"} {
+		if value, ok := args[key].(bool); ok {
+			body[key] = value
+		}
+	}
+}
+
 // registerListMergeRequests registers the list_merge_requests tool.
 func registerListMergeRequests(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "list_merge_requests",
-			Description: "List merge requests for a project. Returns a paginated array of MR objects with title, description, state, author, and source/target branches. Use state filter to find open/merged MRs.",
+			Description: "List merge requests for a project. Returns a paginated array of MR objects with title, description, state, author, and source/target branches. Use state filter to find open/merged MRs; filter by labels, milestone, author, reviewer, branches, search text, draft status and dates to build review queues server-side.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
-				Properties: map[string]mcp.Property{
+				Properties: withMergeRequestFilters(map[string]mcp.Property{
 					"project_id": {
 						Type:        "string",
 						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
@@ -84,19 +238,20 @@ func registerListMergeRequests(server *mcp.Server) {
 						Minimum:     mcp.IntPtr(1),
 						Maximum:     mcp.IntPtr(100),
 					},
-				},
+				}),
 				Required: []string{"project_id"},
 			},
+			OutputSchema: pagedOutputSchema(mergeRequestOutputProperty),
 			Annotations: &mcp.ToolAnnotations{
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("list_merge_requests", args)
+			c.Logger.ToolCall(ctx, "list_merge_requests", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -116,6 +271,7 @@ func registerListMergeRequests(server *mcp.Server) {
 			if sort := GetString(args, "sort", ""); sort != "" {
 				params.Set("sort", sort)
 			}
+			setMergeRequestFilterParams(args, params)
 			if page := GetInt(args, "page", 0); page > 0 {
 				params.Set("page", fmt.Sprintf("%d", page))
 			}
@@ -129,17 +285,195 @@ func registerListMergeRequests(server *mcp.Server) {
 			}
 
 			var mergeRequests []gitlab.MergeRequest
-			pagination, err := c.Client.GetWithPagination(endpoint, &mergeRequests)
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &mergeRequests)
 			if err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list merge requests: %v", err))
+				return APIErrorResult("Failed to list merge requests", err)
 			}
 
-			result := map[string]interface{}{
-				"merge_requests": mergeRequests,
-				"pagination":     pagination,
+			return PagedJSONResult(mergeRequests, pagination)
+		},
+	)
+}
+
+// registerListGroupMergeRequests registers the list_group_merge_requests tool.
+func registerListGroupMergeRequests(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_group_merge_requests",
+			Description: "List merge requests across all projects in a GitLab group and its subgroups, with the same filters as list_merge_requests. Uses GITLAB_DEFAULT_NAMESPACE if group_id is not provided.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: withMergeRequestFilters(map[string]mcp.Property{
+					"group_id": {
+						Type:        "string",
+						Description: "The ID or URL-encoded path of the group. Falls back to GITLAB_DEFAULT_NAMESPACE if not set.",
+					},
+					"state": {
+						Type:        "string",
+						Description: "Filter by state: opened, closed, merged, or all",
+						Enum:        []string{"opened", "closed", "merged", "all"},
+					},
+					"scope": {
+						Type:        "string",
+						Description: "Filter by scope: created_by_me, assigned_to_me, or all",
+						Enum:        []string{"created_by_me", "assigned_to_me", "all"},
+					},
+					"order_by": {
+						Type:        "string",
+						Description: "Order by: created_at or updated_at",
+						Enum:        []string{"created_at", "updated_at"},
+					},
+					"sort": {
+						Type:        "string",
+						Description: "Sort order: asc or desc",
+						Enum:        []string{"asc", "desc"},
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				}),
+			},
+			OutputSchema: pagedOutputSchema(mergeRequestOutputProperty),
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
 			}
+			c.Logger.ToolCall(ctx, "list_group_merge_requests", args)
 
-			return JSONResult(result)
+			// Determine group: explicit arg > config default
+			groupID := GetString(args, "group_id", "")
+			if groupID == "" && c.Config != nil {
+				groupID = c.Config.DefaultNamespace
+			}
+			if groupID == "" {
+				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
+			}
+
+			params := url.Values{}
+			for _, key := range []string{"state", "scope", "order_by", "sort"} {
+				if value := GetString(args, key, ""); value != "" {
+					params.Set(key, value)
+				}
+			}
+			setMergeRequestFilterParams(args, params)
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", fmt.Sprintf("%d", page))
+			}
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			}
+
+			endpoint := fmt.Sprintf("/groups/%s/merge_requests", url.PathEscape(groupID))
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var mergeRequests []gitlab.MergeRequest
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &mergeRequests)
+			if err != nil {
+				return APIErrorResult("Failed to list group merge requests", err)
+			}
+
+			return PagedJSONResult(mergeRequests, pagination)
+		},
+	)
+}
+
+// registerMyMergeRequests registers the my_merge_requests tool.
+func registerMyMergeRequests(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "my_merge_requests",
+			Description: "List merge requests of the authenticated user across all projects. Defaults to MRs assigned to you; use scope=created_by_me for MRs you authored, or reviewer_username=<your username> for your review queue.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: withMergeRequestFilters(map[string]mcp.Property{
+					"state": {
+						Type:        "string",
+						Description: "Filter by state: opened, closed, merged, or all",
+						Enum:        []string{"opened", "closed", "merged", "all"},
+					},
+					"scope": {
+						Type:        "string",
+						Description: "Filter by scope: created_by_me, assigned_to_me (default), or all",
+						Enum:        []string{"created_by_me", "assigned_to_me", "all"},
+					},
+					"order_by": {
+						Type:        "string",
+						Description: "Order by: created_at or updated_at",
+						Enum:        []string{"created_at", "updated_at"},
+					},
+					"sort": {
+						Type:        "string",
+						Description: "Sort order: asc or desc",
+						Enum:        []string{"asc", "desc"},
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				}),
+			},
+			OutputSchema: pagedOutputSchema(mergeRequestOutputProperty),
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "my_merge_requests", args)
+
+			params := url.Values{}
+			params.Set("scope", GetString(args, "scope", "assigned_to_me"))
+			for _, key := range []string{"state", "order_by", "sort"} {
+				if value := GetString(args, key, ""); value != "" {
+					params.Set(key, value)
+				}
+			}
+			setMergeRequestFilterParams(args, params)
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", fmt.Sprintf("%d", page))
+			}
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			}
+
+			endpoint := "/merge_requests?" + params.Encode()
+
+			var mergeRequests []gitlab.MergeRequest
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &mergeRequests)
+			if err != nil {
+				return APIErrorResult("Failed to list merge requests", err)
+			}
+
+			return PagedJSONResult(mergeRequests, pagination)
 		},
 	)
 }
@@ -168,13 +502,14 @@ func registerGetMergeRequest(server *mcp.Server) {
 				},
 				Required: []string{"project_id"},
 			},
+			OutputSchema: objectOutputSchema(mergeRequestOutputProperty, "id", "iid"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_merge_request", args)
+			c.Logger.ToolCall(ctx, "get_merge_request", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -192,8 +527,8 @@ func registerGetMergeRequest(server *mcp.Server) {
 
 			if mrIID > 0 {
 				endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d", url.PathEscape(projectID), mrIID)
-				if err := c.Client.Get(endpoint, &mr); err != nil {
-					return ErrorResult(fmt.Sprintf("Failed to get merge request: %v", err))
+				if err := c.Client.Get(ctx, endpoint, &mr); err != nil {
+					return APIErrorResult("Failed to get merge request", err)
 				}
 			} else {
 				// Search by branch name
@@ -203,8 +538,8 @@ func registerGetMergeRequest(server *mcp.Server) {
 				endpoint := fmt.Sprintf("/projects/%s/merge_requests?%s", url.PathEscape(projectID), params.Encode())
 
 				var mergeRequests []gitlab.MergeRequest
-				if err := c.Client.Get(endpoint, &mergeRequests); err != nil {
-					return ErrorResult(fmt.Sprintf("Failed to search merge requests: %v", err))
+				if err := c.Client.Get(ctx, endpoint, &mergeRequests); err != nil {
+					return APIErrorResult("Failed to search merge requests", err)
 				}
 
 				if len(mergeRequests) == 0 {
@@ -223,10 +558,10 @@ func registerCreateMergeRequest(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "create_merge_request",
-			Description: "Create a new merge request in a project.",
+			Description: "Create a new merge request in a project, optionally with assignees, reviewers, labels, milestone and squash settings.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
-				Properties: map[string]mcp.Property{
+				Properties: withMergeRequestAttributes(map[string]mcp.Property{
 					"project_id": {
 						Type:        "string",
 						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
@@ -249,22 +584,23 @@ func registerCreateMergeRequest(server *mcp.Server) {
 					},
 					"assignee_id": {
 						Type:        "integer",
-						Description: "The ID of the user to assign the merge request to",
+						Description: "The ID of the user to assign the merge request to (use assignee_ids for several)",
 					},
 					"remove_source_branch": {
 						Type:        "boolean",
 						Description: "Whether to remove the source branch after merge",
 					},
-				},
+				}),
 				Required: []string{"project_id", "source_branch", "target_branch", "title"},
 			},
+			OutputSchema: objectOutputSchema(mergeRequestOutputProperty, "id", "iid"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("create_merge_request", args)
+			c.Logger.ToolCall(ctx, "create_merge_request", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -298,12 +634,13 @@ func registerCreateMergeRequest(server *mcp.Server) {
 			if removeSource := GetBool(args, "remove_source_branch", false); removeSource {
 				body["remove_source_branch"] = true
 			}
+			setMergeRequestAttributes(args, body)
 
 			endpoint := fmt.Sprintf("/projects/%s/merge_requests", url.PathEscape(projectID))
 
 			var mr gitlab.MergeRequest
-			if err := c.Client.Post(endpoint, body, &mr); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create merge request: %v", err))
+			if err := c.Client.Post(ctx, endpoint, body, &mr); err != nil {
+				return APIErrorResult("Failed to create merge request", err)
 			}
 
 			return JSONResult(mr)
@@ -316,10 +653,10 @@ func registerUpdateMergeRequest(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "update_merge_request",
-			Description: "Update an existing merge request.",
+			Description: "Update an existing merge request: title, description, target branch, state, assignees, reviewers, labels, milestone and squash settings. Only provided fields change.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
-				Properties: map[string]mcp.Property{
+				Properties: withMergeRequestAttributes(map[string]mcp.Property{
 					"project_id": {
 						Type:        "string",
 						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
@@ -342,23 +679,24 @@ func registerUpdateMergeRequest(server *mcp.Server) {
 					},
 					"assignee_id": {
 						Type:        "integer",
-						Description: "The ID of the user to assign the merge request to",
+						Description: "The ID of the user to assign the merge request to (use assignee_ids for several)",
 					},
 					"state_event": {
 						Type:        "string",
 						Description: "State event: close or reopen",
 						Enum:        []string{"close", "reopen"},
 					},
-				},
+				}),
 				Required: []string{"project_id", "merge_request_iid"},
 			},
+			OutputSchema: objectOutputSchema(mergeRequestOutputProperty, "id", "iid"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("update_merge_request", args)
+			c.Logger.ToolCall(ctx, "update_merge_request", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -385,12 +723,13 @@ func registerUpdateMergeRequest(server *mcp.Server) {
 			if stateEvent := GetString(args, "state_event", ""); stateEvent != "" {
 				body["state_event"] = stateEvent
 			}
+			setMergeRequestAttributes(args, body)
 
 			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d", url.PathEscape(projectID), mrIID)
 
 			var mr gitlab.MergeRequest
-			if err := c.Client.Put(endpoint, body, &mr); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to update merge request: %v", err))
+			if err := c.Client.Put(ctx, endpoint, body, &mr); err != nil {
+				return APIErrorResult("Failed to update merge request", err)
 			}
 
 			return JSONResult(mr)
@@ -403,7 +742,7 @@ func registerMergeMergeRequest(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "merge_merge_request",
-			Description: "Merge a merge request.",
+			Description: "Merge a merge request. Commit messages left out are generated from the project's merge and squash commit templates; use get_merge_commit_templates to preview them.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -419,6 +758,10 @@ func registerMergeMergeRequest(server *mcp.Server) {
 						Type:        "string",
 						Description: "Custom merge commit message",
 					},
+					"squash_commit_message": {
+						Type:        "string",
+						Description: "Custom squash commit message, used when the commits are squashed",
+					},
 					"squash": {
 						Type:        "boolean",
 						Description: "Whether to squash commits before merging",
@@ -430,13 +773,14 @@ func registerMergeMergeRequest(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "merge_request_iid"},
 			},
+			OutputSchema: objectOutputSchema(mergeRequestOutputProperty, "id", "iid"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("merge_merge_request", args)
+			c.Logger.ToolCall(ctx, "merge_merge_request", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -451,6 +795,9 @@ func registerMergeMergeRequest(server *mcp.Server) {
 			if commitMsg := GetString(args, "merge_commit_message", ""); commitMsg != "" {
 				body["merge_commit_message"] = commitMsg
 			}
+			if squashMsg := GetString(args, "squash_commit_message", ""); squashMsg != "" {
+				body["squash_commit_message"] = squashMsg
+			}
 			if _, exists := args["squash"]; exists {
 				body["squash"] = GetBool(args, "squash", false)
 			}
@@ -461,8 +808,8 @@ func registerMergeMergeRequest(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/merge", url.PathEscape(projectID), mrIID)
 
 			var mr gitlab.MergeRequest
-			if err := c.Client.Put(endpoint, body, &mr); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to merge merge request: %v", err))
+			if err := c.Client.Put(ctx, endpoint, body, &mr); err != nil {
+				return APIErrorResult("Failed to merge merge request", err)
 			}
 
 			return JSONResult(mr)
@@ -491,12 +838,12 @@ func registerGetMergeRequestDiffs(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_merge_request_diffs", args)
+			c.Logger.ToolCall(ctx, "get_merge_request_diffs", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -510,8 +857,8 @@ func registerGetMergeRequestDiffs(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/diffs", url.PathEscape(projectID), mrIID)
 
 			var diffs []gitlab.Diff
-			if err := c.Client.Get(endpoint, &diffs); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get merge request diffs: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &diffs); err != nil {
+				return APIErrorResult("Failed to get merge request diffs", err)
 			}
 
 			return JSONResult(diffs)
@@ -553,12 +900,12 @@ func registerListMergeRequestDiffs(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("list_merge_request_diffs", args)
+			c.Logger.ToolCall(ctx, "list_merge_request_diffs", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -583,17 +930,244 @@ func registerListMergeRequestDiffs(server *mcp.Server) {
 			}
 
 			var diffs []gitlab.Diff
-			pagination, err := c.Client.GetWithPagination(endpoint, &diffs)
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &diffs)
 			if err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list merge request diffs: %v", err))
+				return APIErrorResult("Failed to list merge request diffs", err)
 			}
 
-			result := map[string]interface{}{
-				"diffs":      diffs,
-				"pagination": pagination,
+			return PagedJSONResult(diffs, pagination)
+		},
+	)
+}
+
+// registerGetMergeRequestCommits registers the get_merge_request_commits tool.
+func registerGetMergeRequestCommits(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_merge_request_commits",
+			Description: "List the commits of a merge request (SHA, title, author, dates) without fetching diffs. Useful for review summaries.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"merge_request_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the merge request",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id", "merge_request_iid"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
 			}
+			c.Logger.ToolCall(ctx, "get_merge_request_commits", args)
 
-			return JSONResult(result)
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			mrIID := GetInt(args, "merge_request_iid", 0)
+			if mrIID == 0 {
+				return ErrorResult("merge_request_iid is required")
+			}
+
+			params := url.Values{}
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", fmt.Sprintf("%d", page))
+			}
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/commits", url.PathEscape(projectID), mrIID)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var commits []gitlab.Commit
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &commits)
+			if err != nil {
+				return APIErrorResult("Failed to get merge request commits", err)
+			}
+
+			return PagedJSONResult(commits, pagination)
+		},
+	)
+}
+
+// registerGetMergeRequestParticipants registers the get_merge_request_participants tool.
+func registerGetMergeRequestParticipants(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_merge_request_participants",
+			Description: "List the users involved in a merge request: author, assignees, reviewers and commenters.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"merge_request_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the merge request",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id", "merge_request_iid"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_merge_request_participants", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			mrIID := GetInt(args, "merge_request_iid", 0)
+			if mrIID == 0 {
+				return ErrorResult("merge_request_iid is required")
+			}
+
+			params := url.Values{}
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", fmt.Sprintf("%d", page))
+			}
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/participants", url.PathEscape(projectID), mrIID)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var participants []gitlab.User
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &participants)
+			if err != nil {
+				return APIErrorResult("Failed to get merge request participants", err)
+			}
+
+			return PagedJSONResult(participants, pagination)
+		},
+	)
+}
+
+// registerGetMergeRequestClosesIssues registers the get_merge_request_closes_issues tool.
+func registerGetMergeRequestClosesIssues(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_merge_request_closes_issues",
+			Description: "List the issues that a merge request will close when merged, based on closing patterns such as 'Closes #123' in its description and commits.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"merge_request_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the merge request",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id", "merge_request_iid"},
+			},
+			OutputSchema: pagedOutputSchema(issueOutputProperty),
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_merge_request_closes_issues", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			mrIID := GetInt(args, "merge_request_iid", 0)
+			if mrIID == 0 {
+				return ErrorResult("merge_request_iid is required")
+			}
+
+			params := url.Values{}
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", fmt.Sprintf("%d", page))
+			}
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/closes_issues", url.PathEscape(projectID), mrIID)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var issues []gitlab.Issue
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &issues)
+			if err != nil {
+				return APIErrorResult("Failed to get issues closed by merge request", err)
+			}
+
+			return PagedJSONResult(issues, pagination)
 		},
 	)
 }
@@ -627,12 +1201,12 @@ func registerGetBranchDiffs(server *mcp.Server) {
 				Required: []string{"project_id", "from", "to"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_branch_diffs", args)
+			c.Logger.ToolCall(ctx, "get_branch_diffs", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -657,8 +1231,8 @@ func registerGetBranchDiffs(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/repository/compare?%s", url.PathEscape(projectID), params.Encode())
 
 			var result CompareResult
-			if err := c.Client.Get(endpoint, &result); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to compare branches: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &result); err != nil {
+				return APIErrorResult("Failed to compare branches", err)
 			}
 
 			return JSONResult(result)
@@ -696,12 +1270,12 @@ func registerCreateNote(server *mcp.Server) {
 				Required: []string{"project_id", "noteable_type", "noteable_iid", "body"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("create_note", args)
+			c.Logger.ToolCall(ctx, "create_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -735,8 +1309,8 @@ func registerCreateNote(server *mcp.Server) {
 			}
 
 			var note gitlab.Note
-			if err := c.Client.Post(endpoint, requestBody, &note); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create note: %v", err))
+			if err := c.Client.Post(ctx, endpoint, requestBody, &note); err != nil {
+				return APIErrorResult("Failed to create note", err)
 			}
 
 			return JSONResult(note)
@@ -808,12 +1382,12 @@ func registerCreateMergeRequestThread(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid", "body"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("create_merge_request_thread", args)
+			c.Logger.ToolCall(ctx, "create_merge_request_thread", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -839,8 +1413,8 @@ func registerCreateMergeRequestThread(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/discussions", url.PathEscape(projectID), mrIID)
 
 			var discussion Discussion
-			if err := c.Client.Post(endpoint, requestBody, &discussion); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create discussion thread: %v", err))
+			if err := c.Client.Post(ctx, endpoint, requestBody, &discussion); err != nil {
+				return APIErrorResult("Failed to create discussion thread", err)
 			}
 
 			return JSONResult(discussion)
@@ -853,7 +1427,7 @@ func registerMRDiscussions(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "mr_discussions",
-			Description: "List all discussions (threads) on a merge request.",
+			Description: "List the discussions (threads) on a merge request. Filter by state to get only the unresolved (or resolved) threads, or by author to get the threads a user took part in; filtered results are paged after filtering. Set compact to get just the notes, without system notes, as id, discussion_id, author, a one-line excerpt and resolved state.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -865,6 +1439,19 @@ func registerMRDiscussions(server *mcp.Server) {
 						Type:        "integer",
 						Description: "The internal ID of the merge request",
 					},
+					"state": {
+						Type:        "string",
+						Description: "Only threads that are unresolved or resolved. Threads that cannot be resolved, such as plain comments, are left out",
+						Enum:        []string{"unresolved", "resolved"},
+					},
+					"author": {
+						Type:        "string",
+						Description: "Only threads with a note by this username",
+					},
+					"compact": {
+						Type:        "boolean",
+						Description: "Return the notes of the threads as id, discussion_id, author, excerpt and resolved instead of full discussions. Default: false",
+					},
 					"page": {
 						Type:        "integer",
 						Description: "Page number for pagination",
@@ -873,7 +1460,7 @@ func registerMRDiscussions(server *mcp.Server) {
 					},
 					"per_page": {
 						Type:        "integer",
-						Description: "Number of items per page",
+						Description: "Number of threads per page",
 						Default:     20,
 						Minimum:     mcp.IntPtr(1),
 						Maximum:     mcp.IntPtr(100),
@@ -882,12 +1469,12 @@ func registerMRDiscussions(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("mr_discussions", args)
+			c.Logger.ToolCall(ctx, "mr_discussions", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -898,30 +1485,61 @@ func registerMRDiscussions(server *mcp.Server) {
 				return ErrorResult("merge_request_iid is required")
 			}
 
-			params := url.Values{}
-			if page := GetInt(args, "page", 0); page > 0 {
-				params.Set("page", fmt.Sprintf("%d", page))
+			filter := discussionFilter{
+				State:  GetString(args, "state", ""),
+				Author: strings.TrimPrefix(GetString(args, "author", ""), "@"),
 			}
-			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
-				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			if filter.State != "" && filter.State != "resolved" && filter.State != "unresolved" {
+				return ErrorResult("state must be unresolved or resolved")
 			}
+			page, perPage := GetInt(args, "page", 0), GetInt(args, "per_page", 0)
 
 			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/discussions", url.PathEscape(projectID), mrIID)
-			if len(params) > 0 {
-				endpoint += "?" + params.Encode()
-			}
+			result := map[string]interface{}{}
 
 			var discussions []Discussion
-			pagination, err := c.Client.GetWithPagination(endpoint, &discussions)
-			if err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list discussions: %v", err))
+			var pagination *gitlab.PaginationInfo
+			if filter.active() {
+				// GitLab cannot filter discussions, so all of them are read
+				// and the matches paged here
+				all, complete, err := collectPages[Discussion](ctx, c.Client, endpoint, maxCollectedItems)
+				if err != nil {
+					return APIErrorResult("Failed to list discussions", err)
+				}
+				matched := []Discussion{}
+				for _, d := range all {
+					if filter.match(d) {
+						matched = append(matched, d)
+					}
+				}
+				discussions, pagination = pageOf(matched, page, perPage)
+				if !complete {
+					result["complete"] = false
+				}
+			} else {
+				params := url.Values{}
+				if page > 0 {
+					params.Set("page", fmt.Sprintf("%d", page))
+				}
+				if perPage > 0 {
+					params.Set("per_page", fmt.Sprintf("%d", perPage))
+				}
+				if len(params) > 0 {
+					endpoint += "?" + params.Encode()
+				}
+				var err error
+				pagination, err = c.Client.GetWithPagination(ctx, endpoint, &discussions)
+				if err != nil {
+					return APIErrorResult("Failed to list discussions", err)
+				}
 			}
 
-			result := map[string]interface{}{
-				"discussions": discussions,
-				"pagination":  pagination,
+			result["pagination"] = pagination
+			if GetBool(args, "compact", false) {
+				result["items"] = summarizeNotes(discussions)
+			} else {
+				result["items"] = discussions
 			}
-
 			return JSONResult(result)
 		},
 	)
@@ -960,12 +1578,12 @@ func registerUpdateMergeRequestNote(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid", "discussion_id", "note_id", "body"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("update_merge_request_note", args)
+			c.Logger.ToolCall(ctx, "update_merge_request_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -996,8 +1614,8 @@ func registerUpdateMergeRequestNote(server *mcp.Server) {
 			}
 
 			var note gitlab.Note
-			if err := c.Client.Put(endpoint, requestBody, &note); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to update note: %v", err))
+			if err := c.Client.Put(ctx, endpoint, requestBody, &note); err != nil {
+				return APIErrorResult("Failed to update note", err)
 			}
 
 			return JSONResult(note)
@@ -1034,12 +1652,12 @@ func registerCreateMergeRequestNote(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid", "discussion_id", "body"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("create_merge_request_note", args)
+			c.Logger.ToolCall(ctx, "create_merge_request_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -1066,8 +1684,8 @@ func registerCreateMergeRequestNote(server *mcp.Server) {
 			}
 
 			var note gitlab.Note
-			if err := c.Client.Post(endpoint, requestBody, &note); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create note: %v", err))
+			if err := c.Client.Post(ctx, endpoint, requestBody, &note); err != nil {
+				return APIErrorResult("Failed to create note", err)
 			}
 
 			return JSONResult(note)
@@ -1096,12 +1714,12 @@ func registerListDraftNotes(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("list_draft_notes", args)
+			c.Logger.ToolCall(ctx, "list_draft_notes", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -1115,8 +1733,8 @@ func registerListDraftNotes(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/draft_notes", url.PathEscape(projectID), mrIID)
 
 			var draftNotes []DraftNote
-			if err := c.Client.Get(endpoint, &draftNotes); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list draft notes: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &draftNotes); err != nil {
+				return APIErrorResult("Failed to list draft notes", err)
 			}
 
 			return JSONResult(draftNotes)
@@ -1149,12 +1767,12 @@ func registerGetDraftNote(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid", "draft_note_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_draft_note", args)
+			c.Logger.ToolCall(ctx, "get_draft_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -1173,8 +1791,8 @@ func registerGetDraftNote(server *mcp.Server) {
 				url.PathEscape(projectID), mrIID, draftNoteID)
 
 			var draftNote DraftNote
-			if err := c.Client.Get(endpoint, &draftNote); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get draft note: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &draftNote); err != nil {
+				return APIErrorResult("Failed to get draft note", err)
 			}
 
 			return JSONResult(draftNote)
@@ -1211,12 +1829,12 @@ func registerCreateDraftNote(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid", "body"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("create_draft_note", args)
+			c.Logger.ToolCall(ctx, "create_draft_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -1242,8 +1860,8 @@ func registerCreateDraftNote(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/draft_notes", url.PathEscape(projectID), mrIID)
 
 			var draftNote DraftNote
-			if err := c.Client.Post(endpoint, requestBody, &draftNote); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create draft note: %v", err))
+			if err := c.Client.Post(ctx, endpoint, requestBody, &draftNote); err != nil {
+				return APIErrorResult("Failed to create draft note", err)
 			}
 
 			return JSONResult(draftNote)
@@ -1255,19 +1873,31 @@ func registerCreateDraftNote(server *mcp.Server) {
 // This function is called by RegisterMergeRequestTools in registry.go.
 func initMergeRequestTools(server *mcp.Server) {
 	registerListMergeRequests(server)
+	registerListGroupMergeRequests(server)
+	registerMyMergeRequests(server)
 	registerGetMergeRequest(server)
 	registerCreateMergeRequest(server)
 	registerUpdateMergeRequest(server)
 	registerMergeMergeRequest(server)
+	registerGetMergeCommitTemplates(server)
 	registerGetMergeRequestDiffs(server)
 	registerListMergeRequestDiffs(server)
+	registerGetMergeRequestCommits(server)
+	registerGetMergeRequestParticipants(server)
+	registerSuggestReviewers(server)
+	registerValidateConventions(server)
+	registerGetMergeRequestClosesIssues(server)
 	registerGetBranchDiffs(server)
 	registerCreateNote(server)
+	registerUpsertNote(server)
 	registerCreateMergeRequestThread(server)
 	registerMRDiscussions(server)
 	registerUpdateMergeRequestNote(server)
 	registerCreateMergeRequestNote(server)
+	registerDeleteNote(server)
+	registerDeleteMergeRequestNote(server)
 	registerListDraftNotes(server)
 	registerGetDraftNote(server)
 	registerCreateDraftNote(server)
+	registerPostInlineFindings(server)
 }
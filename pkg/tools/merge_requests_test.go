@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/gitlabtest"
+)
+
+func TestMRDiscussionsFilters(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/acme%2Fapi/merge_requests/7/discussions", 200, `[
+		{"id": "d1", "notes": [
+			{"id": 1, "body": "Rename this\nplease", "author": {"username": "alice"}, "resolvable": true, "resolved": false},
+			{"id": 2, "body": "Done", "author": {"username": "bob"}, "resolvable": true, "resolved": false}
+		]},
+		{"id": "d2", "notes": [{"id": 3, "body": "Typo", "author": {"username": "carol"}, "resolvable": true, "resolved": true}]},
+		{"id": "d3", "individual_note": true, "notes": [{"id": 4, "body": "LGTM", "author": {"username": "Alice"}}]},
+		{"id": "d4", "individual_note": true, "notes": [{"id": 5, "body": "added 1 commit", "system": true, "author": {"username": "bob"}}]}
+	]`)
+
+	ids := func(args map[string]interface{}) ([]string, map[string]json.RawMessage) {
+		t.Helper()
+		args["project_id"], args["merge_request_iid"] = "acme/api", 7
+		res := callTool(t, tc, "mr_discussions", args)
+		if res.IsError {
+			t.Fatalf("unexpected error: %s", resultText(t, res))
+		}
+		var result map[string]json.RawMessage
+		var discussions []Discussion
+		if err := json.Unmarshal([]byte(resultText(t, res)), &result); err != nil {
+			t.Fatal(err)
+		}
+		json.Unmarshal(result["items"], &discussions)
+		var got []string
+		for _, d := range discussions {
+			got = append(got, d.ID)
+		}
+		return got, result
+	}
+
+	for _, tt := range []struct {
+		args map[string]interface{}
+		want []string
+	}{
+		{map[string]interface{}{"state": "unresolved"}, []string{"d1"}},
+		{map[string]interface{}{"state": "resolved"}, []string{"d2"}},
+		{map[string]interface{}{"author": "@alice"}, []string{"d1", "d3"}},
+		{map[string]interface{}{}, []string{"d1", "d2", "d3", "d4"}},
+	} {
+		if got, _ := ids(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("mr_discussions(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+
+	got, result := ids(map[string]interface{}{"author": "alice", "page": 2, "per_page": 1})
+	var pagination gitlab.PaginationInfo
+	json.Unmarshal(result["pagination"], &pagination)
+	if !reflect.DeepEqual(got, []string{"d3"}) || pagination.Total != 2 || pagination.PrevPage != 1 || pagination.NextPage != 0 {
+		t.Errorf("page 2 = %v, pagination = %+v", got, pagination)
+	}
+
+	_, result = ids(map[string]interface{}{"state": "unresolved", "compact": true})
+	var notes []NoteSummary
+	json.Unmarshal(result["items"], &notes)
+	want := []NoteSummary{
+		{ID: 1, DiscussionID: "d1", Author: "alice", Excerpt: "Rename this please", Resolvable: true},
+		{ID: 2, DiscussionID: "d1", Author: "bob", Excerpt: "Done", Resolvable: true},
+	}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("compact notes = %+v, want %+v", notes, want)
+	}
+}
+
+func TestMergeRequestListEnvelope(t *testing.T) {
+	tests := []struct {
+		tool     string
+		args     map[string]interface{}
+		endpoint string
+		body     string
+	}{
+		{"list_merge_requests", map[string]interface{}{}, "/projects/acme%2Fapi/merge_requests", `[{"iid": 7}, {"iid": 8}]`},
+		{"list_merge_request_diffs", map[string]interface{}{"merge_request_iid": 7}, "/projects/acme%2Fapi/merge_requests/7/diffs", `[{"new_path": "a.go"}, {"new_path": "b.go"}]`},
+		{"mr_discussions", map[string]interface{}{"merge_request_iid": 7}, "/projects/acme%2Fapi/merge_requests/7/discussions", `[{"id": "d1", "notes": []}, {"id": "d2", "notes": []}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			tc, client := newTestContext(t)
+			client.AddRoute(gitlabtest.Route{
+				Method:     "GET",
+				Endpoint:   tt.endpoint,
+				Body:       json.RawMessage(tt.body),
+				Pagination: &gitlab.PaginationInfo{Page: 1, PerPage: 2, Total: 3, TotalPages: 2, NextPage: 2},
+			})
+			tt.args["project_id"] = "acme/api"
+			items, pagination := listItems(t, callTool(t, tc, tt.tool, tt.args))
+			if len(items) != 2 || pagination == nil || pagination.NextPage != 2 || pagination.Total != 3 {
+				t.Errorf("%d items, pagination %+v", len(items), pagination)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// GitLab's messages for projects without their own templates.
+const (
+	defaultMergeCommitTemplate  = "Merge branch '%{source_branch}' into '%{target_branch}'\n\n%{title}\n\n%{issues}\n\nSee merge request %{reference}"
+	defaultSquashCommitTemplate = "%{first_multiline_commit}"
+)
+
+// templatePlaceholder matches a %{name} placeholder of a commit template.
+var templatePlaceholder = regexp.MustCompile(`%\{(\w+)\}`)
+
+// CommitTemplates is the response of the get_merge_commit_templates tool.
+// The templates are empty when the project uses GitLab's defaults. The
+// rendered messages are set when a merge request is given;
+// UnresolvedPlaceholders lists placeholders that need data this server does
+// not read, left as written.
+type CommitTemplates struct {
+	ProjectID              string   `json:"project_id"`
+	MergeMethod            string   `json:"merge_method"`
+	SquashOption           string   `json:"squash_option"`
+	MergeCommitTemplate    string   `json:"merge_commit_template"`
+	SquashCommitTemplate   string   `json:"squash_commit_template"`
+	MergeRequestIID        int      `json:"merge_request_iid,omitempty"`
+	MergeCommitMessage     string   `json:"merge_commit_message,omitempty"`
+	SquashCommitMessage    string   `json:"squash_commit_message,omitempty"`
+	UnresolvedPlaceholders []string `json:"unresolved_placeholders,omitempty"`
+}
+
+// renderCommitTemplate fills the placeholders of a commit template. Unknown
+// placeholders are kept and returned; lines left empty by empty values are
+// dropped along with the blank lines around them.
+func renderCommitTemplate(template string, values map[string]string) (string, []string) {
+	var unresolved []string
+	rendered := templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		value, ok := values[name]
+		if !ok {
+			if !containsString(unresolved, placeholder) {
+				unresolved = append(unresolved, placeholder)
+			}
+			return placeholder
+		}
+		return value
+	})
+	var paragraphs []string
+	for _, paragraph := range strings.Split(rendered, "\n\n") {
+		if strings.TrimSpace(paragraph) != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n"), unresolved
+}
+
+// toSentence joins references the way GitLab lists closed issues, e.g.
+// "#1, #2, and #3".
+func toSentence(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " and " + items[1]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + ", and " + items[len(items)-1]
+}
+
+// commitTemplateValues reads the data of a merge request that commit
+// templates refer to, keyed by placeholder name.
+func commitTemplateValues(ctx context.Context, client gitlab.API, projectPath, mrEndpoint string) (map[string]string, error) {
+	var mr gitlab.MergeRequest
+	if err := client.Get(ctx, mrEndpoint, &mr); err != nil {
+		return nil, err
+	}
+	commits, _, err := collectPages[gitlab.Commit](ctx, client, mrEndpoint+"/commits", maxCollectedItems)
+	if err != nil {
+		return nil, err
+	}
+	closes, _, err := collectPages[gitlab.Issue](ctx, client, mrEndpoint+"/closes_issues", maxCollectedItems)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{
+		"source_branch":     mr.SourceBranch,
+		"target_branch":     mr.TargetBranch,
+		"title":             mr.Title,
+		"description":       mr.Description,
+		"reference":         fmt.Sprintf("%s!%d", projectPath, mr.IID),
+		"local_reference":   fmt.Sprintf("!%d", mr.IID),
+		"url":               mr.WebURL,
+		"source_project_id": fmt.Sprint(mr.SourceProjectID),
+		"issues":            "",
+	}
+	if len(closes) > 0 {
+		refs := make([]string, len(closes))
+		for i, issue := range closes {
+			refs[i] = fmt.Sprintf("#%d", issue.IID)
+		}
+		values["issues"] = "Closes " + toSentence(refs)
+	}
+
+	// GitLab lists the newest commit first
+	var messages []string
+	values["first_commit"], values["first_multiline_commit"] = "", mr.Title
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		message := strings.TrimSpace(commit.Message)
+		if values["first_commit"] == "" {
+			values["first_commit"] = message
+		}
+		if len(commit.ParentIDs) > 1 {
+			continue
+		}
+		messages = append(messages, "* "+message)
+		if values["first_multiline_commit"] == mr.Title && strings.Contains(message, "\n") {
+			values["first_multiline_commit"] = message
+		}
+	}
+	values["all_commits"] = strings.Join(messages, "\n\n")
+	return values, nil
+}
+
+type getMergeCommitTemplatesArgs struct {
+	ProjectID       string `json:"project_id" validate:"required"`
+	MergeRequestIID int    `json:"merge_request_iid" validate:"min=1"`
+}
+
+// registerGetMergeCommitTemplates registers the get_merge_commit_templates tool.
+func registerGetMergeCommitTemplates(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_merge_commit_templates",
+			Description: "Get a project's merge commit and squash commit message templates, its merge method and squash option. With merge_request_iid, also render both messages for that merge request (placeholders such as %{title}, %{issues}, %{reference}, %{first_multiline_commit} and %{all_commits} are filled in), so the agent can review or adjust them before passing merge_commit_message or squash_commit_message to merge_merge_request. Omitting those messages on merge lets GitLab apply the templates itself.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"merge_request_iid": {
+						Type:        "integer",
+						Description: "Render the templates for this merge request",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_merge_commit_templates", func(ctx context.Context, c *ToolContext, args getMergeCommitTemplatesArgs) (*mcp.CallToolResult, error) {
+			encodedProjectID := url.PathEscape(args.ProjectID)
+			var project struct {
+				PathWithNamespace    string `json:"path_with_namespace"`
+				MergeMethod          string `json:"merge_method"`
+				SquashOption         string `json:"squash_option"`
+				MergeCommitTemplate  string `json:"merge_commit_template"`
+				SquashCommitTemplate string `json:"squash_commit_template"`
+			}
+			if err := c.Client.Get(ctx, "/projects/"+encodedProjectID, &project); err != nil {
+				return APIErrorResult("Failed to get project", err)
+			}
+			result := CommitTemplates{
+				ProjectID:            args.ProjectID,
+				MergeMethod:          project.MergeMethod,
+				SquashOption:         project.SquashOption,
+				MergeCommitTemplate:  project.MergeCommitTemplate,
+				SquashCommitTemplate: project.SquashCommitTemplate,
+			}
+			if args.MergeRequestIID == 0 {
+				return JSONResult(result)
+			}
+
+			mrEndpoint := fmt.Sprintf("/projects/%s/merge_requests/%d", encodedProjectID, args.MergeRequestIID)
+			values, err := commitTemplateValues(ctx, c.Client, project.PathWithNamespace, mrEndpoint)
+			if err != nil {
+				return APIErrorResult("Failed to get merge request", err)
+			}
+			mergeTemplate := project.MergeCommitTemplate
+			if mergeTemplate == "" {
+				mergeTemplate = defaultMergeCommitTemplate
+			}
+			squashTemplate := project.SquashCommitTemplate
+			if squashTemplate == "" {
+				squashTemplate = defaultSquashCommitTemplate
+			}
+			var unresolved, more []string
+			result.MergeRequestIID = args.MergeRequestIID
+			result.MergeCommitMessage, unresolved = renderCommitTemplate(mergeTemplate, values)
+			result.SquashCommitMessage, more = renderCommitTemplate(squashTemplate, values)
+			for _, placeholder := range more {
+				if !containsString(unresolved, placeholder) {
+					unresolved = append(unresolved, placeholder)
+				}
+			}
+			result.UnresolvedPlaceholders = unresolved
+			return JSONResult(result)
+		}),
+	)
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGetMergeCommitTemplates(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/42", 200, `{"path_with_namespace": "acme/shop", "merge_method": "merge", "squash_option": "default_on",
+		"merge_commit_template": "%{title} (%{local_reference})\n\n%{issues}\n\nApproved-by: %{approved_by}"}`)
+	client.Handle("GET", "/projects/42/merge_requests/7", 200, `{"iid": 7, "title": "Add search", "source_branch": "search", "target_branch": "main"}`)
+	client.Handle("GET", "/projects/42/merge_requests/7/commits", 200, `[
+		{"id": "c2", "message": "Fix index\n\nRebuild on start", "parent_ids": ["c1"]},
+		{"id": "c1", "message": "Add index", "parent_ids": ["c0"]}
+	]`)
+	client.Handle("GET", "/projects/42/merge_requests/7/closes_issues", 200, `[]`)
+
+	result := callTool(t, tc, "get_merge_commit_templates", map[string]interface{}{"project_id": "42", "merge_request_iid": 7})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	var templates CommitTemplates
+	if err := json.Unmarshal([]byte(resultText(t, result)), &templates); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Add search (!7)\n\nApproved-by: %{approved_by}"; templates.MergeCommitMessage != want {
+		t.Errorf("merge commit message = %q, want %q", templates.MergeCommitMessage, want)
+	}
+	if want := "Fix index\n\nRebuild on start"; templates.SquashCommitMessage != want {
+		t.Errorf("squash commit message = %q, want %q", templates.SquashCommitMessage, want)
+	}
+	if want := []string{"%{approved_by}"}; !reflect.DeepEqual(templates.UnresolvedPlaceholders, want) {
+		t.Errorf("unresolved = %v, want %v", templates.UnresolvedPlaceholders, want)
+	}
+
+	client.Handle("PUT", "/projects/42/merge_requests/7/merge", 200, `{"id": 1, "iid": 7}`)
+	result = callTool(t, tc, "merge_merge_request", map[string]interface{}{
+		"project_id": "42", "merge_request_iid": 7, "squash": true, "squash_commit_message": templates.SquashCommitMessage,
+	})
+	if result.IsError {
+		t.Fatalf("merge_merge_request failed: %s", resultText(t, result))
+	}
+	requests := client.Requests()
+	var body map[string]interface{}
+	if err := json.Unmarshal(requests[len(requests)-1].Body, &body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body["squash_commit_message"] != templates.SquashCommitMessage || body["squash"] != true {
+		t.Errorf("request body = %v", body)
+	}
+}
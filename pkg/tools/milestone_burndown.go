@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// maxBurndownDays caps the days of a computed burndown.
+const maxBurndownDays = 366
+
+// BurndownDay is the state of a milestone's issues at the end of one day.
+// Total is the burnup scope, Open the burndown.
+type BurndownDay struct {
+	Date   string `json:"date"`
+	Total  int    `json:"total"`
+	Closed int    `json:"closed"`
+	Open   int    `json:"open"`
+	// IdealOpen falls linearly from the open issues of the first day to
+	// zero on the due date
+	IdealOpen *float64 `json:"ideal_open,omitempty"`
+}
+
+// MilestoneBurndown is the response of the get_milestone_burndown tool.
+type MilestoneBurndown struct {
+	Milestone string `json:"milestone"`
+	StartDate string `json:"start_date"`
+	DueDate   string `json:"due_date,omitempty"`
+	Issues    int    `json:"issues"`
+	Closed    int    `json:"closed"`
+	Open      int    `json:"open"`
+	// Complete is false when the milestone has more issues than were read
+	Complete bool          `json:"complete"`
+	Summary  string        `json:"summary"`
+	Days     []BurndownDay `json:"days"`
+	Notes    []string      `json:"notes,omitempty"`
+}
+
+// burndownDays counts, at the end of each day from start to end, the issues
+// created and closed by then. An issue closed and reopened only counts as
+// closed if it is closed now.
+func burndownDays(issues []gitlab.Issue, start, end time.Time) []BurndownDay {
+	days := []BurndownDay{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		endOfDay := day.AddDate(0, 0, 1)
+		row := BurndownDay{Date: day.Format("2006-01-02")}
+		for _, issue := range issues {
+			if issue.CreatedAt == nil || !issue.CreatedAt.Before(endOfDay) {
+				continue
+			}
+			row.Total++
+			if issue.ClosedAt != nil && issue.State == "closed" && issue.ClosedAt.Before(endOfDay) {
+				row.Closed++
+			}
+		}
+		row.Open = row.Total - row.Closed
+		days = append(days, row)
+	}
+	return days
+}
+
+type getMilestoneBurndownArgs struct {
+	ProjectID   string `json:"project_id" validate:"required"`
+	MilestoneID int    `json:"milestone_id" validate:"required,min=1"`
+}
+
+// registerGetMilestoneBurndown registers the get_milestone_burndown tool.
+func registerGetMilestoneBurndown(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_milestone_burndown",
+			Description: "Compute a burndown and burnup chart for a milestone from the created and closed dates of its issues, so it works on GitLab Free where get_milestone_burndown_events is not available. For each day from the milestone's start date (or creation) to its due date (or today), returns the issues in scope, closed and open, and the ideal open count falling to zero on the due date. Issues count from when they were created, not when they joined the milestone.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The ID or URL-encoded path of the project",
+					},
+					"milestone_id": {
+						Type:        "integer",
+						Description: "The ID of the milestone",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"project_id", "milestone_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_milestone_burndown", func(ctx context.Context, c *ToolContext, args getMilestoneBurndownArgs) (*mcp.CallToolResult, error) {
+			endpoint := fmt.Sprintf("/projects/%s/milestones/%d", url.PathEscape(args.ProjectID), args.MilestoneID)
+			var milestone gitlab.Milestone
+			if err := c.Client.Get(ctx, endpoint, &milestone); err != nil {
+				return APIErrorResult("failed to get milestone", err)
+			}
+			issues, complete, err := collectPages[gitlab.Issue](ctx, c.Client, endpoint+"/issues", maxCollectedItems)
+			if err != nil {
+				return APIErrorResult("failed to get milestone issues", err)
+			}
+
+			today := time.Now().UTC().Truncate(24 * time.Hour)
+			result := MilestoneBurndown{Milestone: milestone.Title, DueDate: milestone.DueDate, Issues: len(issues), Complete: complete}
+			var start time.Time
+			if milestone.StartDate != "" {
+				if start, err = time.Parse("2006-01-02", milestone.StartDate); err != nil {
+					return ErrorResult(fmt.Sprintf("milestone has an invalid start date %q", milestone.StartDate))
+				}
+			} else if milestone.CreatedAt != nil {
+				start = milestone.CreatedAt.UTC().Truncate(24 * time.Hour)
+			} else {
+				start = today
+			}
+			end := today
+			var due time.Time
+			if milestone.DueDate != "" {
+				if due, err = time.Parse("2006-01-02", milestone.DueDate); err != nil {
+					return ErrorResult(fmt.Sprintf("milestone has an invalid due date %q", milestone.DueDate))
+				}
+				if due.Before(end) {
+					end = due
+				}
+			}
+			if start.After(end) {
+				start = end
+			}
+			if days := int(end.Sub(start).Hours() / 24); days >= maxBurndownDays {
+				start = end.AddDate(0, 0, -(maxBurndownDays - 1))
+				result.Notes = append(result.Notes, fmt.Sprintf("only the last %d days are shown", maxBurndownDays))
+			}
+			result.StartDate = start.Format("2006-01-02")
+
+			for _, issue := range issues {
+				if issue.State == "closed" {
+					result.Closed++
+				}
+			}
+			result.Open = result.Issues - result.Closed
+			result.Days = burndownDays(issues, start, end)
+			if !due.IsZero() && due.After(start) {
+				initial := float64(result.Days[0].Open)
+				span := due.Sub(start).Hours() / 24
+				for i := range result.Days {
+					ideal := initial * max(0, 1-float64(i)/span)
+					result.Days[i].IdealOpen = &ideal
+				}
+			}
+
+			result.Summary = fmt.Sprintf("%d of %d issues closed, %d open", result.Closed, result.Issues, result.Open)
+			switch {
+			case due.IsZero():
+				result.Summary += ", no due date"
+			case due.Before(today) && result.Open > 0:
+				result.Summary += fmt.Sprintf(", %d days past the due date", int(today.Sub(due).Hours()/24))
+			case !due.Before(today):
+				result.Summary += fmt.Sprintf(", %d days left", int(due.Sub(today).Hours()/24))
+			}
+			if !complete {
+				result.Notes = append(result.Notes, fmt.Sprintf("only the first %d issues were read", maxCollectedItems))
+			}
+			return JSONResult(result)
+		}),
+	)
+}
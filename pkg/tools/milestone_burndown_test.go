@@ -0,0 +1,154 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetMilestoneBurndown(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.UseMilestone = true
+	client.Handle("GET", "/projects/acme%2Fapi/milestones/5", 200, `{"id": 5, "title": "Sprint 12", "start_date": "2024-05-01", "due_date": "2024-05-04"}`)
+	client.Handle("GET", "/projects/acme%2Fapi/milestones/5/issues", 200, `[
+		{"id": 1, "iid": 1, "state": "closed", "created_at": "2024-04-20T10:00:00Z", "closed_at": "2024-05-02T09:00:00Z"},
+		{"id": 2, "iid": 2, "state": "opened", "created_at": "2024-04-28T10:00:00Z", "closed_at": "2024-05-01T09:00:00Z"},
+		{"id": 3, "iid": 3, "state": "closed", "created_at": "2024-05-03T10:00:00Z", "closed_at": "2024-05-03T18:00:00Z"},
+		{"id": 4, "iid": 4, "state": "opened", "created_at": "2024-05-03T11:00:00Z"}
+	]`)
+
+	res := callTool(t, tc, "get_milestone_burndown", map[string]interface{}{"project_id": "acme/api", "milestone_id": 5})
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, res))
+	}
+	var burndown MilestoneBurndown
+	if err := json.Unmarshal([]byte(resultText(t, res)), &burndown); err != nil {
+		t.Fatal(err)
+	}
+	if burndown.Issues != 4 || burndown.Closed != 2 || burndown.Open != 2 || !burndown.Complete {
+		t.Errorf("burndown = %+v", burndown)
+	}
+	if !strings.HasPrefix(burndown.Summary, "2 of 4 issues closed, 2 open, ") {
+		t.Errorf("summary = %q", burndown.Summary)
+	}
+	// Issue 2 was reopened, so it never counts as closed
+	var got [][3]int
+	for _, day := range burndown.Days {
+		got = append(got, [3]int{day.Total, day.Closed, day.Open})
+	}
+	if want := [][3]int{{2, 0, 2}, {2, 1, 1}, {4, 2, 2}, {4, 2, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("days (total, closed, open) = %v, want %v", got, want)
+	}
+	if first, last := burndown.Days[0], burndown.Days[3]; first.Date != "2024-05-01" || *first.IdealOpen != 2 || last.Date != "2024-05-04" || *last.IdealOpen != 0 {
+		t.Errorf("first day = %+v, last day = %+v", first, last)
+	}
+}
+
+func TestGetMilestoneBurndownDates(t *testing.T) {
+	// Without a due date the chart runs until today
+	created := time.Now().UTC().AddDate(0, 0, -3)
+	tests := []struct {
+		name      string
+		milestone string
+		wantErr   string
+		wantStart string
+		wantDays  int
+		wantNote  string
+		wantIdeal bool
+		wantSum   string
+	}{
+		{
+			name:      "invalid start date",
+			milestone: `{"id": 5, "title": "Sprint", "start_date": "May 1st", "due_date": "2024-05-04"}`,
+			wantErr:   `milestone has an invalid start date "May 1st"`,
+		},
+		{
+			name:      "invalid due date",
+			milestone: `{"id": 5, "title": "Sprint", "start_date": "2024-05-01", "due_date": "2024-13-01"}`,
+			wantErr:   `milestone has an invalid due date "2024-13-01"`,
+		},
+		{
+			name:      "start after the due date",
+			milestone: `{"id": 5, "title": "Sprint", "start_date": "2024-05-09", "due_date": "2024-05-04"}`,
+			wantStart: "2024-05-04",
+			wantDays:  1,
+			wantSum:   "1 of 2 issues closed, 1 open, ",
+		},
+		{
+			name:      "longer than the day cap",
+			milestone: `{"id": 5, "title": "Roadmap", "start_date": "2020-01-01", "due_date": "2024-05-04"}`,
+			wantStart: "2023-05-05",
+			wantDays:  maxBurndownDays,
+			wantNote:  "only the last 366 days are shown",
+			wantIdeal: true,
+			wantSum:   "1 of 2 issues closed, 1 open, ",
+		},
+		{
+			name:      "no due date",
+			milestone: fmt.Sprintf(`{"id": 5, "title": "Backlog", "created_at": %q}`, created.Format(time.RFC3339)),
+			wantStart: created.Format("2006-01-02"),
+			wantDays:  4,
+			wantSum:   "1 of 2 issues closed, 1 open, no due date",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, client := newTestContext(t)
+			tc.Config.UseMilestone = true
+			client.Handle("GET", "/projects/acme%2Fapi/milestones/5", 200, tt.milestone)
+			client.Handle("GET", "/projects/acme%2Fapi/milestones/5/issues", 200, `[
+				{"id": 1, "iid": 1, "state": "closed", "created_at": "2024-04-20T10:00:00Z", "closed_at": "2024-05-02T09:00:00Z"},
+				{"id": 2, "iid": 2, "state": "opened"}
+			]`)
+
+			res := callTool(t, tc, "get_milestone_burndown", map[string]interface{}{"project_id": "acme/api", "milestone_id": 5})
+			if tt.wantErr != "" {
+				if !res.IsError || res.Content[0].Text != tt.wantErr {
+					t.Errorf("result = %q, want %q", res.Content[0].Text, tt.wantErr)
+				}
+				return
+			}
+			if res.IsError {
+				t.Fatalf("unexpected error: %s", res.Content[0].Text)
+			}
+			var burndown MilestoneBurndown
+			if err := json.Unmarshal([]byte(resultText(t, res)), &burndown); err != nil {
+				t.Fatal(err)
+			}
+			if burndown.StartDate != tt.wantStart || burndown.Days[0].Date != tt.wantStart {
+				t.Errorf("start = %s, first day %s, want %s", burndown.StartDate, burndown.Days[0].Date, tt.wantStart)
+			}
+			if tt.wantDays != 0 && len(burndown.Days) != tt.wantDays {
+				t.Errorf("%d days, want %d", len(burndown.Days), tt.wantDays)
+			}
+			if notes := strings.Join(burndown.Notes, "; "); notes != tt.wantNote {
+				t.Errorf("notes = %q, want %q", notes, tt.wantNote)
+			}
+			if hasIdeal := burndown.Days[0].IdealOpen != nil; hasIdeal != tt.wantIdeal {
+				t.Errorf("ideal line present = %v, want %v", hasIdeal, tt.wantIdeal)
+			}
+			if !strings.HasPrefix(burndown.Summary, tt.wantSum) {
+				t.Errorf("summary = %q, want it to start with %q", burndown.Summary, tt.wantSum)
+			}
+			// The issue without a creation date is never in scope
+			if last := burndown.Days[len(burndown.Days)-1]; last.Total != 1 || last.Closed != 1 {
+				t.Errorf("last day = %+v, want the one dated issue, closed", last)
+			}
+		})
+	}
+}
+
+func TestGetMilestoneBurndownAPIError(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.UseMilestone = true
+	client.Handle("GET", "/projects/acme%2Fapi/milestones/5", 200, `{"id": 5, "title": "Sprint 12", "start_date": "2024-05-01", "due_date": "2024-05-04"}`)
+	client.Handle("GET", "/projects/acme%2Fapi/milestones/5/issues", 500, `{"message": "500 Internal Server Error"}`)
+
+	res := callTool(t, tc, "get_milestone_burndown", map[string]interface{}{"project_id": "acme/api", "milestone_id": 5})
+	if !res.IsError || !strings.HasPrefix(res.Content[0].Text, "failed to get milestone issues") {
+		t.Errorf("result = %q, want the issues request to fail", res.Content[0].Text)
+	}
+}
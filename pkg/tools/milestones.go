@@ -2,6 +2,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -51,12 +52,12 @@ func registerListMilestones(server *mcp.Server) {
 				Required: []string{"project_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("list_milestones", args)
+			c.Logger.ToolCall(ctx, "list_milestones", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -88,11 +89,12 @@ func registerListMilestones(server *mcp.Server) {
 			}
 
 			var milestones []gitlab.Milestone
-			if err := ctx.Client.Get(endpoint, &milestones); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to list milestones: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &milestones)
+			if err != nil {
+				return APIErrorResult("failed to list milestones", err)
 			}
 
-			return JSONResult(milestones)
+			return PagedJSONResult(milestones, pagination)
 		},
 	)
 }
@@ -118,12 +120,12 @@ func registerGetMilestone(server *mcp.Server) {
 				Required: []string{"project_id", "milestone_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_milestone", args)
+			c.Logger.ToolCall(ctx, "get_milestone", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -141,8 +143,8 @@ func registerGetMilestone(server *mcp.Server) {
 			)
 
 			var milestone gitlab.Milestone
-			if err := ctx.Client.Get(endpoint, &milestone); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to get milestone: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &milestone); err != nil {
+				return APIErrorResult("failed to get milestone", err)
 			}
 
 			return JSONResult(milestone)
@@ -183,12 +185,12 @@ func registerCreateMilestone(server *mcp.Server) {
 				Required: []string{"project_id", "title"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("create_milestone", args)
+			c.Logger.ToolCall(ctx, "create_milestone", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -220,8 +222,8 @@ func registerCreateMilestone(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/milestones", url.PathEscape(projectID))
 
 			var milestone gitlab.Milestone
-			if err := ctx.Client.Post(endpoint, body, &milestone); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to create milestone: %v", err))
+			if err := c.Client.Post(ctx, endpoint, body, &milestone); err != nil {
+				return APIErrorResult("failed to create milestone", err)
 			}
 
 			return JSONResult(milestone)
@@ -271,12 +273,12 @@ func registerEditMilestone(server *mcp.Server) {
 				Required: []string{"project_id", "milestone_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("edit_milestone", args)
+			c.Logger.ToolCall(ctx, "edit_milestone", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -317,8 +319,8 @@ func registerEditMilestone(server *mcp.Server) {
 			)
 
 			var milestone gitlab.Milestone
-			if err := ctx.Client.Put(endpoint, body, &milestone); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to edit milestone: %v", err))
+			if err := c.Client.Put(ctx, endpoint, body, &milestone); err != nil {
+				return APIErrorResult("failed to edit milestone", err)
 			}
 
 			return JSONResult(milestone)
@@ -347,12 +349,12 @@ func registerDeleteMilestone(server *mcp.Server) {
 				Required: []string{"project_id", "milestone_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("delete_milestone", args)
+			c.Logger.ToolCall(ctx, "delete_milestone", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -369,8 +371,8 @@ func registerDeleteMilestone(server *mcp.Server) {
 				milestoneID,
 			)
 
-			if err := ctx.Client.Delete(endpoint); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to delete milestone: %v", err))
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("failed to delete milestone", err)
 			}
 
 			return TextResult(fmt.Sprintf("Milestone %d deleted successfully", milestoneID))
@@ -407,12 +409,12 @@ func registerGetMilestoneIssues(server *mcp.Server) {
 				Required: []string{"project_id", "milestone_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_milestone_issues", args)
+			c.Logger.ToolCall(ctx, "get_milestone_issues", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -444,11 +446,12 @@ func registerGetMilestoneIssues(server *mcp.Server) {
 			}
 
 			var issues []gitlab.Issue
-			if err := ctx.Client.Get(endpoint, &issues); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to get milestone issues: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &issues)
+			if err != nil {
+				return APIErrorResult("failed to get milestone issues", err)
 			}
 
-			return JSONResult(issues)
+			return PagedJSONResult(issues, pagination)
 		},
 	)
 }
@@ -482,12 +485,12 @@ func registerGetMilestoneMergeRequests(server *mcp.Server) {
 				Required: []string{"project_id", "milestone_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_milestone_merge_requests", args)
+			c.Logger.ToolCall(ctx, "get_milestone_merge_requests", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -519,11 +522,12 @@ func registerGetMilestoneMergeRequests(server *mcp.Server) {
 			}
 
 			var mergeRequests []gitlab.MergeRequest
-			if err := ctx.Client.Get(endpoint, &mergeRequests); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to get milestone merge requests: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &mergeRequests)
+			if err != nil {
+				return APIErrorResult("failed to get milestone merge requests", err)
 			}
 
-			return JSONResult(mergeRequests)
+			return PagedJSONResult(mergeRequests, pagination)
 		},
 	)
 }
@@ -549,12 +553,12 @@ func registerPromoteMilestone(server *mcp.Server) {
 				Required: []string{"project_id", "milestone_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("promote_milestone", args)
+			c.Logger.ToolCall(ctx, "promote_milestone", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -572,8 +576,8 @@ func registerPromoteMilestone(server *mcp.Server) {
 			)
 
 			var milestone gitlab.Milestone
-			if err := ctx.Client.Post(endpoint, nil, &milestone); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to promote milestone: %v", err))
+			if err := c.Client.Post(ctx, endpoint, nil, &milestone); err != nil {
+				return APIErrorResult("failed to promote milestone", err)
 			}
 
 			return JSONResult(milestone)
@@ -586,7 +590,7 @@ func registerGetMilestoneBurndownEvents(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "get_milestone_burndown_events",
-			Description: "Get the burndown chart events for a specific milestone. This endpoint is only available in GitLab Premium/Ultimate.",
+			Description: "Get the burndown chart events for a specific milestone. This endpoint is only available in GitLab Premium/Ultimate; on GitLab Free, use get_milestone_burndown.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -610,12 +614,12 @@ func registerGetMilestoneBurndownEvents(server *mcp.Server) {
 				Required: []string{"project_id", "milestone_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_milestone_burndown_events", args)
+			c.Logger.ToolCall(ctx, "get_milestone_burndown_events", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -647,11 +651,12 @@ func registerGetMilestoneBurndownEvents(server *mcp.Server) {
 			}
 
 			var events []BurndownEvent
-			if err := ctx.Client.Get(endpoint, &events); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to get milestone burndown events: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &events)
+			if err != nil {
+				return APIErrorResult("failed to get milestone burndown events", err)
 			}
 
-			return JSONResult(events)
+			return PagedJSONResult(events, pagination)
 		},
 	)
 }
@@ -667,4 +672,5 @@ func initMilestoneTools(server *mcp.Server) {
 	registerGetMilestoneMergeRequests(server)
 	registerPromoteMilestone(server)
 	registerGetMilestoneBurndownEvents(server)
+	registerGetMilestoneBurndown(server)
 }
@@ -0,0 +1,35 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPushMirrorWritesReadOnlyMode(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.ReadOnlyMode = true
+
+	for _, call := range []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"create_push_mirror", map[string]interface{}{"project_id": "42", "url": "https://mirror.example.com/acme/api.git"}},
+		{"update_push_mirror", map[string]interface{}{"project_id": "42", "mirror_id": 3, "enabled": true}},
+		{"sync_push_mirror", map[string]interface{}{"project_id": "42", "mirror_id": 3}},
+	} {
+		result := callTool(t, tc, call.name, call.args)
+		if !result.IsError || !strings.Contains(resultText(t, result), "server is in read-only mode") {
+			t.Errorf("%s in read-only mode = %q, want a read-only error", call.name, resultText(t, result))
+		}
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("read-only mode sent %d requests, want none", len(requests))
+	}
+
+	// list_push_mirrors still works
+	client.Handle(http.MethodGet, "/projects/42/remote_mirrors", http.StatusOK, []map[string]interface{}{{"id": 3}})
+	if result := callTool(t, tc, "list_push_mirrors", map[string]interface{}{"project_id": "42"}); result.IsError {
+		t.Errorf("list_push_mirrors in read-only mode failed: %s", resultText(t, result))
+	}
+}
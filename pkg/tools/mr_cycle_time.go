@@ -0,0 +1,238 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// defaultCycleTimeSlowest is how many of the slowest merge requests the
+// cycle time report lists by default.
+const defaultCycleTimeSlowest = 5
+
+// reviewSystemNotes are the starts of the system notes that record a review
+// by someone other than the author.
+var reviewSystemNotes = []string{"approved this merge request", "requested changes", "left review comments"}
+
+// CycleTimeStats summarizes one measure of the mr_cycle_time_report tool
+// over the merge requests it applies to. Durations are in seconds.
+type CycleTimeStats struct {
+	Count   int     `json:"count"`
+	P50     float64 `json:"p50"`
+	P75     float64 `json:"p75"`
+	P90     float64 `json:"p90"`
+	Average float64 `json:"average"`
+	Summary string  `json:"summary"`
+}
+
+// newCycleTimeStats summarizes values, rendering them with format.
+func newCycleTimeStats(values []float64, format func(float64) string) CycleTimeStats {
+	stats := CycleTimeStats{Count: len(values), Summary: "no merge requests"}
+	if len(values) == 0 {
+		return stats
+	}
+	sort.Float64s(values)
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	stats.P50, stats.P75, stats.P90 = percentile(values, 50), percentile(values, 75), percentile(values, 90)
+	stats.Average = total / float64(len(values))
+	stats.Summary = fmt.Sprintf("p50 %s, p75 %s, p90 %s, average %s over %d merge requests",
+		format(stats.P50), format(stats.P75), format(stats.P90), format(stats.Average), stats.Count)
+	return stats
+}
+
+// MRCycleTime is the cycle time of one merge request. TimeToFirstReview is
+// nil when nobody but the author reviewed it.
+type MRCycleTime struct {
+	Reference         string   `json:"reference"`
+	Title             string   `json:"title"`
+	Author            string   `json:"author"`
+	WebURL            string   `json:"web_url"`
+	TimeToFirstReview *float64 `json:"time_to_first_review,omitempty"`
+	TimeToMerge       float64  `json:"time_to_merge"`
+	ReviewIterations  int      `json:"review_iterations"`
+}
+
+// MRCycleTimeReport is the response of the mr_cycle_time_report tool.
+type MRCycleTimeReport struct {
+	Scope         string    `json:"scope"`
+	Since         time.Time `json:"since"`
+	Until         time.Time `json:"until"`
+	MergeRequests int       `json:"merge_requests"`
+	// Complete is false when the period had more merged merge requests
+	// than max_merge_requests
+	Complete          bool           `json:"complete"`
+	Unreviewed        int            `json:"unreviewed"`
+	TimeToFirstReview CycleTimeStats `json:"time_to_first_review"`
+	TimeToMerge       CycleTimeStats `json:"time_to_merge"`
+	ReviewIterations  CycleTimeStats `json:"review_iterations"`
+	Slowest           []MRCycleTime  `json:"slowest"`
+}
+
+// isReviewNote reports whether a note records a review of a merge request
+// by someone other than its author.
+func isReviewNote(note gitlab.Note, author int) bool {
+	if note.Author == nil || note.Author.ID == author {
+		return false
+	}
+	if !note.System {
+		return true
+	}
+	for _, prefix := range reviewSystemNotes {
+		if strings.HasPrefix(note.Body, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// mrCycleTime measures a merged merge request from its notes: the first
+// review is the first comment, approval or change request by someone other
+// than the author, and each push of commits after it is a review iteration.
+func mrCycleTime(ctx context.Context, client gitlab.API, mr gitlab.MergeRequest) (MRCycleTime, error) {
+	row := MRCycleTime{
+		Reference:   fmt.Sprintf("%d!%d", mr.ProjectID, mr.IID),
+		Title:       mr.Title,
+		WebURL:      mr.WebURL,
+		TimeToMerge: mr.MergedAt.Sub(*mr.CreatedAt).Seconds(),
+	}
+	author := 0
+	if mr.Author != nil {
+		author, row.Author = mr.Author.ID, mr.Author.Username
+	}
+	endpoint := fmt.Sprintf("/projects/%d/merge_requests/%d/notes?order_by=created_at&sort=asc", mr.ProjectID, mr.IID)
+	notes, _, err := collectPages[gitlab.Note](ctx, client, endpoint, maxCollectedItems)
+	if err != nil {
+		return row, err
+	}
+	var reviewed *time.Time
+	for _, note := range notes {
+		if note.CreatedAt == nil || note.CreatedAt.After(*mr.MergedAt) {
+			continue
+		}
+		if reviewed == nil && isReviewNote(note, author) {
+			reviewed = note.CreatedAt
+			seconds := note.CreatedAt.Sub(*mr.CreatedAt).Seconds()
+			row.TimeToFirstReview = &seconds
+			continue
+		}
+		if reviewed != nil && note.System && strings.HasPrefix(note.Body, "added ") && strings.Contains(note.Body, " commit") {
+			row.ReviewIterations++
+		}
+	}
+	return row, nil
+}
+
+type mrCycleTimeReportArgs struct {
+	ProjectID        string `json:"project_id"`
+	GroupID          string `json:"group_id"`
+	Since            string `json:"since"`
+	Until            string `json:"until"`
+	MaxMergeRequests int    `json:"max_merge_requests" validate:"min=1,max=500"`
+	Slowest          int    `json:"slowest" validate:"min=1,max=50"`
+}
+
+// registerMRCycleTimeReport registers the mr_cycle_time_report tool.
+func registerMRCycleTimeReport(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "mr_cycle_time_report",
+			Description: "Measure review and merge times over the merge requests of a project or group merged in a period: time to first review (opened to the first comment, approval or change request by someone other than the author), time to merge (opened to merged) and review iterations (pushes after the first review), each as p50, p75, p90 and average. Durations are in seconds with readable summaries. Also counts merge requests merged without review and lists the slowest to merge.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project). Either this or group_id is required",
+					},
+					"group_id": {
+						Type:        "string",
+						Description: "The group ID or URL-encoded path, to cover the merge requests of all its projects",
+					},
+					"since": {
+						Type:        "string",
+						Description: "Start of the period, as ISO 8601 or YYYY-MM-DD. Default: 30 days before until",
+					},
+					"until": {
+						Type:        "string",
+						Description: "End of the period, as ISO 8601 or YYYY-MM-DD (the whole day). Default: now",
+					},
+					"max_merge_requests": {
+						Type:        "integer",
+						Description: "Merged merge requests read, most recently merged first (max 500). Default: 100",
+						Default:     defaultValueStreamMergeRequests,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(500),
+					},
+					"slowest": {
+						Type:        "integer",
+						Description: "Slowest merge requests listed (max 50). Default: 5",
+						Default:     defaultCycleTimeSlowest,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(50),
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("mr_cycle_time_report", func(ctx context.Context, c *ToolContext, args mrCycleTimeReportArgs) (*mcp.CallToolResult, error) {
+			prefix, scope, err := analyticsScope(args.ProjectID, args.GroupID)
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			since, until, err := analyticsPeriod(args.Since, args.Until)
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			limit := args.MaxMergeRequests
+			if limit == 0 {
+				limit = defaultValueStreamMergeRequests
+			}
+			slowest := args.Slowest
+			if slowest == 0 {
+				slowest = defaultCycleTimeSlowest
+			}
+
+			mrs, complete, err := mergedMergeRequests(ctx, c.Client, prefix, since, until, limit)
+			if err != nil {
+				return APIErrorResult("Failed to list merged merge requests", err)
+			}
+
+			report := MRCycleTimeReport{Scope: scope, Since: since, Until: until, MergeRequests: len(mrs), Complete: complete, Slowest: []MRCycleTime{}}
+			var firstReview, merge, iterations []float64
+			for i, mr := range mrs {
+				mcp.ReportProgress(ctx, float64(i+1), float64(len(mrs)), fmt.Sprintf("Read %d of %d merge requests", i+1, len(mrs)))
+				row, err := mrCycleTime(ctx, c.Client, mr)
+				if err != nil {
+					return APIErrorResult(fmt.Sprintf("Failed to list the notes of merge request %s", row.Reference), err)
+				}
+				merge = append(merge, row.TimeToMerge)
+				if row.TimeToFirstReview == nil {
+					report.Unreviewed++
+				} else {
+					firstReview = append(firstReview, *row.TimeToFirstReview)
+					iterations = append(iterations, float64(row.ReviewIterations))
+				}
+				report.Slowest = append(report.Slowest, row)
+			}
+
+			report.TimeToFirstReview = newCycleTimeStats(firstReview, formatSeconds)
+			report.TimeToMerge = newCycleTimeStats(merge, formatSeconds)
+			report.ReviewIterations = newCycleTimeStats(iterations, func(v float64) string { return fmt.Sprintf("%.1f", v) })
+			sort.SliceStable(report.Slowest, func(i, j int) bool {
+				return report.Slowest[i].TimeToMerge > report.Slowest[j].TimeToMerge
+			})
+			report.Slowest = report.Slowest[:min(len(report.Slowest), slowest)]
+			return JSONResult(report)
+		}),
+	)
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMRCycleTimeReport(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/acme%2Fapi/merge_requests", 200, `[
+		{"id": 103, "iid": 3, "project_id": 42, "author": {"id": 1, "username": "dev"}, "created_at": "2024-05-06T00:00:00Z", "merged_at": "2024-05-06T01:00:00Z"},
+		{"id": 102, "iid": 2, "project_id": 42, "author": {"id": 1, "username": "dev"}, "created_at": "2024-05-05T00:00:00Z", "merged_at": "2024-05-05T02:00:00Z"},
+		{"id": 101, "iid": 1, "project_id": 42, "author": {"id": 1, "username": "dev"}, "created_at": "2024-05-01T00:00:00Z", "merged_at": "2024-05-03T00:00:00Z"},
+		{"id": 100, "iid": 9, "project_id": 42, "author": {"id": 1, "username": "dev"}, "created_at": "2024-03-01T00:00:00Z", "merged_at": "2024-03-02T00:00:00Z"}
+	]`)
+	client.Handle("GET", "/projects/42/merge_requests/1/notes", 200, `[
+		{"id": 1, "body": "Ready for review", "author": {"id": 1}, "created_at": "2024-05-01T01:00:00Z"},
+		{"id": 2, "body": "Please rename this", "author": {"id": 2}, "created_at": "2024-05-01T04:00:00Z"},
+		{"id": 3, "body": "added 1 commit\n\n* abc - Rename", "system": true, "author": {"id": 1}, "created_at": "2024-05-01T06:00:00Z"},
+		{"id": 4, "body": "approved this merge request", "system": true, "author": {"id": 2}, "created_at": "2024-05-02T00:00:00Z"}
+	]`)
+	client.Handle("GET", "/projects/42/merge_requests/2/notes", 200, `[
+		{"id": 5, "body": "approved this merge request", "system": true, "author": {"id": 2}, "created_at": "2024-05-05T01:00:00Z"}
+	]`)
+	client.Handle("GET", "/projects/42/merge_requests/3/notes", 200, `[
+		{"id": 6, "body": "added 1 commit", "system": true, "author": {"id": 1}, "created_at": "2024-05-06T00:30:00Z"}
+	]`)
+
+	res := callTool(t, tc, "mr_cycle_time_report", map[string]interface{}{"project_id": "acme/api", "since": "2024-05-01", "until": "2024-05-31", "slowest": 1})
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, res))
+	}
+	var report MRCycleTimeReport
+	if err := json.Unmarshal([]byte(resultText(t, res)), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.MergeRequests != 3 || report.Unreviewed != 1 || !report.Complete {
+		t.Errorf("merge requests = %d, unreviewed = %d, complete = %v", report.MergeRequests, report.Unreviewed, report.Complete)
+	}
+	if report.TimeToMerge.Count != 3 || report.TimeToMerge.P50 != 2*3600 {
+		t.Errorf("time to merge = %+v", report.TimeToMerge)
+	}
+	if report.TimeToFirstReview.Count != 2 || report.TimeToFirstReview.P50 != 2.5*3600 {
+		t.Errorf("time to first review = %+v", report.TimeToFirstReview)
+	}
+	if report.ReviewIterations.P50 != 0.5 || report.ReviewIterations.P90 != 0.9 {
+		t.Errorf("review iterations = %+v", report.ReviewIterations)
+	}
+	if want := "p50 2.5 hours, p75 3.2 hours, p90 3.7 hours, average 2.5 hours over 2 merge requests"; report.TimeToFirstReview.Summary != want {
+		t.Errorf("summary = %q, want %q", report.TimeToFirstReview.Summary, want)
+	}
+	if len(report.Slowest) != 1 || report.Slowest[0].Reference != "42!1" || report.Slowest[0].ReviewIterations != 1 || report.Slowest[0].Author != "dev" {
+		t.Errorf("slowest = %+v", report.Slowest)
+	}
+}
@@ -0,0 +1,222 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// defaultUsageWarnPercent is the usage at which get_namespace_usage warns by
+// default.
+const defaultUsageWarnPercent = 90
+
+// NamespaceDetails is a namespace as returned by get_namespace. The plan,
+// seat and trial fields are only set on GitLab.com and self-managed instances
+// with a license, for top-level namespaces the token can administer.
+type NamespaceDetails struct {
+	gitlab.Namespace
+	ParentID                    *int   `json:"parent_id"`
+	MembersCountWithDescendants *int   `json:"members_count_with_descendants,omitempty"`
+	BillableMembersCount        *int   `json:"billable_members_count,omitempty"`
+	SeatsInUse                  *int   `json:"seats_in_use,omitempty"`
+	MaxSeatsUsed                *int   `json:"max_seats_used,omitempty"`
+	Plan                        string `json:"plan,omitempty"`
+	Trial                       bool   `json:"trial,omitempty"`
+	TrialEndsOn                 string `json:"trial_ends_on,omitempty"`
+	EndDate                     string `json:"end_date,omitempty"`
+	ProjectsCount               *int   `json:"projects_count,omitempty"`
+	RootRepositorySize          *int64 `json:"root_repository_size,omitempty"`
+}
+
+// namespaceSubscription is the subscription of a top-level namespace.
+type namespaceSubscription struct {
+	Plan struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	} `json:"plan"`
+	Usage struct {
+		SeatsInSubscription *int `json:"seats_in_subscription"`
+		SeatsInUse          *int `json:"seats_in_use"`
+		MaxSeatsUsed        *int `json:"max_seats_used"`
+		SeatsOwed           *int `json:"seats_owed"`
+	} `json:"usage"`
+	Billing struct {
+		SubscriptionEndDate string `json:"subscription_end_date"`
+	} `json:"billing"`
+}
+
+// SeatUsage is the seat part of a namespace's usage. InSubscription, Owed
+// and Remaining are only known when the token can read the subscription.
+type SeatUsage struct {
+	InUse          *int     `json:"in_use,omitempty"`
+	MaxUsed        *int     `json:"max_used,omitempty"`
+	Billable       *int     `json:"billable,omitempty"`
+	InSubscription *int     `json:"in_subscription,omitempty"`
+	Owed           *int     `json:"owed,omitempty"`
+	Remaining      *int     `json:"remaining,omitempty"`
+	UsagePercent   *float64 `json:"usage_percent,omitempty"`
+}
+
+// StorageUsage is the storage part of a namespace's usage: the statistics of
+// its projects summed as in get_group_statistics.
+type StorageUsage struct {
+	Projects          int               `json:"projects"`
+	WithoutStatistics int               `json:"without_statistics,omitempty"`
+	Complete          bool              `json:"complete"`
+	Summary           string            `json:"summary"`
+	Totals            ProjectStatistics `json:"totals"`
+	LimitBytes        int64             `json:"limit_bytes,omitempty"`
+	UsagePercent      *float64          `json:"usage_percent,omitempty"`
+}
+
+// NamespaceUsage is the response of the get_namespace_usage tool.
+type NamespaceUsage struct {
+	Namespace           string       `json:"namespace"`
+	Kind                string       `json:"kind"`
+	Plan                string       `json:"plan,omitempty"`
+	SubscriptionEndDate string       `json:"subscription_end_date,omitempty"`
+	Seats               SeatUsage    `json:"seats"`
+	Storage             StorageUsage `json:"storage"`
+	Warnings            []string     `json:"warnings,omitempty"`
+}
+
+// percentOf returns used as a percentage of limit, rounded to one decimal.
+func percentOf(used, limit int64) float64 {
+	return float64(used*1000/limit) / 10
+}
+
+type getNamespaceUsageArgs struct {
+	NamespaceID     string `json:"namespace_id" validate:"required"`
+	StorageLimitGiB int    `json:"storage_limit_gib" validate:"min=1"`
+	WarnPercent     int    `json:"warn_percent" validate:"min=1,max=100"`
+}
+
+// registerGetNamespaceUsage registers the get_namespace_usage tool.
+func registerGetNamespaceUsage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_namespace_usage",
+			Description: "Check how close a namespace is to its seat and storage limits. Returns its plan, seats in use, maximum seats used and billable members (with the seats in the subscription, seats owed and seats remaining when the token can read the subscription), and the storage of all its projects summed as in get_group_statistics. Warnings list what is at or above warn_percent of its limit, or over it. GitLab does not report storage limits through its API, so pass storage_limit_gib to compare storage against one.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"namespace_id": {
+						Type:        "string",
+						Description: "The ID or URL-encoded path of a group or user namespace",
+					},
+					"storage_limit_gib": {
+						Type:        "integer",
+						Description: "The namespace's storage limit in GiB, e.g. from its plan",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"warn_percent": {
+						Type:        "integer",
+						Description: "Warn when usage reaches this percentage of a limit. Default: 90",
+						Default:     defaultUsageWarnPercent,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"namespace_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_namespace_usage", func(ctx context.Context, c *ToolContext, args getNamespaceUsageArgs) (*mcp.CallToolResult, error) {
+			warnPercent := args.WarnPercent
+			if warnPercent == 0 {
+				warnPercent = defaultUsageWarnPercent
+			}
+			var namespace NamespaceDetails
+			if err := c.Client.Get(ctx, "/namespaces/"+url.PathEscape(args.NamespaceID), &namespace); err != nil {
+				return APIErrorResult("Failed to get namespace", err)
+			}
+			usage := NamespaceUsage{
+				Namespace: namespace.FullPath,
+				Kind:      namespace.Kind,
+				Plan:      namespace.Plan,
+				Seats: SeatUsage{
+					InUse:    namespace.SeatsInUse,
+					MaxUsed:  namespace.MaxSeatsUsed,
+					Billable: namespace.BillableMembersCount,
+				},
+			}
+
+			// Reading the subscription needs more than reading the namespace,
+			// so its seat counts are left out when it is not allowed
+			if namespace.ParentID == nil {
+				var subscription namespaceSubscription
+				err := c.Client.Get(ctx, fmt.Sprintf("/namespaces/%d/gitlab_subscription", namespace.ID), &subscription)
+				switch {
+				case err == nil:
+					if subscription.Plan.Name != "" {
+						usage.Plan = subscription.Plan.Name
+					}
+					usage.SubscriptionEndDate = subscription.Billing.SubscriptionEndDate
+					if subscription.Usage.SeatsInUse != nil {
+						usage.Seats.InUse = subscription.Usage.SeatsInUse
+					}
+					if subscription.Usage.MaxSeatsUsed != nil {
+						usage.Seats.MaxUsed = subscription.Usage.MaxSeatsUsed
+					}
+					usage.Seats.InSubscription = subscription.Usage.SeatsInSubscription
+					usage.Seats.Owed = subscription.Usage.SeatsOwed
+				case gitlab.IsNotFound(err) || gitlab.IsForbidden(err) || gitlab.IsUnauthorized(err):
+				default:
+					return APIErrorResult("Failed to get namespace subscription", err)
+				}
+			}
+			if seats := usage.Seats; seats.InUse != nil && seats.InSubscription != nil && *seats.InSubscription > 0 {
+				remaining := *seats.InSubscription - *seats.InUse
+				percent := percentOf(int64(*seats.InUse), int64(*seats.InSubscription))
+				usage.Seats.Remaining, usage.Seats.UsagePercent = &remaining, &percent
+				switch {
+				case remaining < 0:
+					usage.Warnings = append(usage.Warnings, fmt.Sprintf("%d seats in use, %d over the %d in the subscription", *seats.InUse, -remaining, *seats.InSubscription))
+				case percent >= float64(warnPercent):
+					usage.Warnings = append(usage.Warnings, fmt.Sprintf("%d of %d seats in use (%.1f%%), %d remaining", *seats.InUse, *seats.InSubscription, percent, remaining))
+				}
+			}
+			if owed := usage.Seats.Owed; owed != nil && *owed > 0 {
+				usage.Warnings = append(usage.Warnings, fmt.Sprintf("%d seats owed", *owed))
+			}
+
+			endpoint := fmt.Sprintf("/groups/%s/projects?include_subgroups=true&statistics=true", url.PathEscape(namespace.FullPath))
+			if namespace.Kind == "user" {
+				endpoint = fmt.Sprintf("/users/%s/projects?statistics=true", url.PathEscape(namespace.Path))
+			}
+			complete, err := streamPages(ctx, c.Client, endpoint, maxCollectedItems, func(project projectWithStatistics) {
+				usage.Storage.Projects++
+				if project.Statistics == nil {
+					usage.Storage.WithoutStatistics++
+					return
+				}
+				usage.Storage.Totals.add(*project.Statistics)
+			})
+			if err != nil {
+				return APIErrorResult(fmt.Sprintf("Failed to list the projects of namespace %s", namespace.FullPath), err)
+			}
+			usage.Storage.Complete = complete
+			usage.Storage.Summary = fmt.Sprintf("%d projects: %s", usage.Storage.Projects, usage.Storage.Totals.summary())
+			if args.StorageLimitGiB > 0 {
+				limit := int64(args.StorageLimitGiB) << 30
+				percent := percentOf(usage.Storage.Totals.StorageSize, limit)
+				usage.Storage.LimitBytes, usage.Storage.UsagePercent = limit, &percent
+				switch {
+				case usage.Storage.Totals.StorageSize > limit:
+					usage.Warnings = append(usage.Warnings, fmt.Sprintf("storage %s is over the %d GiB limit", formatBytes(usage.Storage.Totals.StorageSize), args.StorageLimitGiB))
+				case percent >= float64(warnPercent):
+					usage.Warnings = append(usage.Warnings, fmt.Sprintf("storage %s is %.1f%% of the %d GiB limit", formatBytes(usage.Storage.Totals.StorageSize), percent, args.StorageLimitGiB))
+				}
+			}
+			if usage.Storage.WithoutStatistics > 0 {
+				usage.Warnings = append(usage.Warnings, fmt.Sprintf("storage leaves out %d projects whose statistics the token cannot read", usage.Storage.WithoutStatistics))
+			}
+			return JSONResult(usage)
+		}),
+	)
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetNamespaceUsage(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/namespaces/acme", 200, `{"id": 7, "path": "acme", "kind": "group", "full_path": "acme", "parent_id": null,
+		"plan": "premium", "billable_members_count": 47, "seats_in_use": 47, "max_seats_used": 48}`)
+	client.Handle("GET", "/namespaces/7/gitlab_subscription", 200, `{"plan": {"code": "premium", "name": "Premium"},
+		"usage": {"seats_in_subscription": 50, "seats_in_use": 47, "max_seats_used": 48, "seats_owed": 0},
+		"billing": {"subscription_end_date": "2025-01-31"}}`)
+	client.Handle("GET", "/groups/acme/projects", 200, `[
+		{"id": 1, "path_with_namespace": "acme/api", "statistics": {"storage_size": 966367642}},
+		{"id": 2, "path_with_namespace": "acme/web"}
+	]`)
+
+	res := callTool(t, tc, "get_namespace_usage", map[string]interface{}{"namespace_id": "acme", "storage_limit_gib": 1})
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, res))
+	}
+	var usage NamespaceUsage
+	if err := json.Unmarshal([]byte(resultText(t, res)), &usage); err != nil {
+		t.Fatal(err)
+	}
+	if usage.Plan != "Premium" || usage.SubscriptionEndDate != "2025-01-31" {
+		t.Errorf("plan = %q, end date = %q", usage.Plan, usage.SubscriptionEndDate)
+	}
+	if usage.Seats.Remaining == nil || *usage.Seats.Remaining != 3 || *usage.Seats.UsagePercent != 94 {
+		t.Errorf("seats = %+v", usage.Seats)
+	}
+	if usage.Storage.Projects != 2 || usage.Storage.WithoutStatistics != 1 || *usage.Storage.UsagePercent != 90 {
+		t.Errorf("storage = %+v", usage.Storage)
+	}
+	want := []string{
+		"47 of 50 seats in use (94.0%), 3 remaining",
+		"storage 921.6 MiB is 90.0% of the 1 GiB limit",
+		"storage leaves out 1 projects whose statistics the token cannot read",
+	}
+	if !reflect.DeepEqual(usage.Warnings, want) {
+		t.Errorf("warnings = %q, want %q", usage.Warnings, want)
+	}
+	query, _ := url.ParseQuery(strings.SplitN(client.Requests()[2].Endpoint, "?", 2)[1])
+	if query.Get("statistics") != "true" || query.Get("include_subgroups") != "true" {
+		t.Errorf("query = %v", query)
+	}
+
+	// Without access to the subscription, the namespace's own seat counts are used
+	tc, client = newTestContext(t)
+	client.Handle("GET", "/namespaces/acme", 200, `{"id": 7, "path": "acme", "kind": "group", "full_path": "acme", "seats_in_use": 12}`)
+	client.Handle("GET", "/namespaces/7/gitlab_subscription", 403, `{"message": "403 Forbidden"}`)
+	client.Handle("GET", "/groups/acme/projects", 200, `[]`)
+	res = callTool(t, tc, "get_namespace_usage", map[string]interface{}{"namespace_id": "acme"})
+	usage = NamespaceUsage{}
+	if err := json.Unmarshal([]byte(resultText(t, res)), &usage); err != nil || res.IsError {
+		t.Fatalf("unexpected result: %s", resultText(t, res))
+	}
+	if usage.Seats.InUse == nil || *usage.Seats.InUse != 12 || usage.Seats.Remaining != nil || len(usage.Warnings) != 0 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
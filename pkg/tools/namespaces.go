@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
@@ -38,12 +39,12 @@ func registerListNamespaces(server *mcp.Server) {
 				},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("list_namespaces", args)
+			c.Logger.ToolCall(ctx, "list_namespaces", args)
 
 			params := url.Values{}
 
@@ -63,11 +64,12 @@ func registerListNamespaces(server *mcp.Server) {
 			}
 
 			var namespaces []gitlab.Namespace
-			if err := c.Client.Get(endpoint, &namespaces); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list namespaces: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &namespaces)
+			if err != nil {
+				return APIErrorResult("Failed to list namespaces", err)
 			}
 
-			return JSONResult(namespaces)
+			return PagedJSONResult(namespaces, pagination)
 		},
 	)
 }
@@ -77,7 +79,7 @@ func registerGetNamespace(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "get_namespace",
-			Description: "Get details of a specific namespace by ID or path",
+			Description: "Get details of a specific namespace by ID or path, including its parent, member and project counts and, for top-level namespaces on licensed instances or GitLab.com, its plan, trial, seats in use and maximum seats used. Use get_namespace_usage to check seat and storage limits.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -89,12 +91,12 @@ func registerGetNamespace(server *mcp.Server) {
 				Required: []string{"namespace_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_namespace", args)
+			c.Logger.ToolCall(ctx, "get_namespace", args)
 
 			namespaceID := GetString(args, "namespace_id", "")
 			if namespaceID == "" {
@@ -103,9 +105,9 @@ func registerGetNamespace(server *mcp.Server) {
 
 			endpoint := fmt.Sprintf("/namespaces/%s", url.PathEscape(namespaceID))
 
-			var namespace gitlab.Namespace
-			if err := c.Client.Get(endpoint, &namespace); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get namespace: %v", err))
+			var namespace NamespaceDetails
+			if err := c.Client.Get(ctx, endpoint, &namespace); err != nil {
+				return APIErrorResult("Failed to get namespace", err)
 			}
 
 			return JSONResult(namespace)
@@ -130,12 +132,12 @@ func registerVerifyNamespace(server *mcp.Server) {
 				Required: []string{"namespace_path"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("verify_namespace", args)
+			c.Logger.ToolCall(ctx, "verify_namespace", args)
 
 			namespacePath := GetString(args, "namespace_path", "")
 			if namespacePath == "" {
@@ -145,8 +147,8 @@ func registerVerifyNamespace(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/namespaces/%s/exists", url.PathEscape(namespacePath))
 
 			var response NamespaceExistsResponse
-			if err := c.Client.Get(endpoint, &response); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to verify namespace: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &response); err != nil {
+				return APIErrorResult("Failed to verify namespace", err)
 			}
 
 			return JSONResult(response)
@@ -155,9 +157,10 @@ func registerVerifyNamespace(server *mcp.Server) {
 }
 
 // RegisterNamespaceTools registers all namespace-related tools with the MCP server.
-// Includes: list_namespaces, get_namespace, verify_namespace
+// Includes: list_namespaces, get_namespace, get_namespace_usage, verify_namespace
 func initNamespaceTools(server *mcp.Server) {
 	registerListNamespaces(server)
 	registerGetNamespace(server)
+	registerGetNamespaceUsage(server)
 	registerVerifyNamespace(server)
 }
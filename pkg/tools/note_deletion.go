@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// deleteNote deletes a note of an issue or merge request. GitLab has no
+// endpoint to delete a discussion; a thread goes away with its last note.
+func deleteNote(ctx context.Context, c *ToolContext, projectID, noteableType string, noteableIID, noteID int) (*mcp.CallToolResult, error) {
+	if c.Config != nil && c.Config.ReadOnlyMode {
+		return ErrorResult("cannot delete note: server is in read-only mode")
+	}
+
+	var endpoint, noteable string
+	switch noteableType {
+	case "issue":
+		endpoint = fmt.Sprintf("/projects/%s/issues/%d/notes/%d", url.PathEscape(projectID), noteableIID, noteID)
+		noteable = fmt.Sprintf("issue #%d", noteableIID)
+	case "merge_request":
+		endpoint = fmt.Sprintf("/projects/%s/merge_requests/%d/notes/%d", url.PathEscape(projectID), noteableIID, noteID)
+		noteable = fmt.Sprintf("merge request !%d", noteableIID)
+	default:
+		return ErrorResult("noteable_type must be 'issue' or 'merge_request'")
+	}
+
+	if err := c.Client.Delete(ctx, endpoint); err != nil {
+		return APIErrorResult("Failed to delete note", err)
+	}
+	return TextResult(fmt.Sprintf("Note %d deleted from %s", noteID, noteable))
+}
+
+type deleteNoteArgs struct {
+	ProjectID    string `json:"project_id" validate:"required"`
+	NoteableType string `json:"noteable_type" validate:"required,oneof=issue merge_request"`
+	NoteableIID  int    `json:"noteable_iid" validate:"required,min=1"`
+	NoteID       int    `json:"note_id" validate:"required,min=1"`
+}
+
+// registerDeleteNote registers the delete_note tool.
+func registerDeleteNote(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "delete_note",
+			Description: "Delete a note (comment) from an issue or merge request, such as an outdated comment posted earlier. Deleting a thread's first note leaves its replies; a thread is removed once all its notes are deleted. System notes cannot be deleted. This action is irreversible.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"noteable_type": {
+						Type:        "string",
+						Description: "The type of noteable: issue or merge_request",
+						Enum:        []string{"issue", "merge_request"},
+					},
+					"noteable_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the issue or merge request",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"note_id": {
+						Type:        "integer",
+						Description: "The ID of the note to delete",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"project_id", "noteable_type", "noteable_iid", "note_id"},
+			},
+		},
+		withArgs("delete_note", func(ctx context.Context, c *ToolContext, args deleteNoteArgs) (*mcp.CallToolResult, error) {
+			return deleteNote(ctx, c, args.ProjectID, args.NoteableType, args.NoteableIID, args.NoteID)
+		}),
+	)
+}
+
+type deleteMergeRequestNoteArgs struct {
+	ProjectID       string `json:"project_id" validate:"required"`
+	MergeRequestIID int    `json:"merge_request_iid" validate:"required,min=1"`
+	NoteID          int    `json:"note_id" validate:"required,min=1"`
+}
+
+// registerDeleteMergeRequestNote registers the delete_merge_request_note tool.
+func registerDeleteMergeRequestNote(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "delete_merge_request_note",
+			Description: "Delete a note from a merge request, including a note in a discussion thread (note IDs are listed by mr_discussions). A thread is removed once all its notes are deleted. This action is irreversible.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"merge_request_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the merge request",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"note_id": {
+						Type:        "integer",
+						Description: "The ID of the note to delete",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"project_id", "merge_request_iid", "note_id"},
+			},
+		},
+		withArgs("delete_merge_request_note", func(ctx context.Context, c *ToolContext, args deleteMergeRequestNoteArgs) (*mcp.CallToolResult, error) {
+			return deleteNote(ctx, c, args.ProjectID, "merge_request", args.MergeRequestIID, args.NoteID)
+		}),
+	)
+}
+
+type deleteIssueNoteArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	IssueIID  int    `json:"issue_iid" validate:"required,min=1"`
+	NoteID    int    `json:"note_id" validate:"required,min=1"`
+}
+
+// registerDeleteIssueNote registers the delete_issue_note tool.
+func registerDeleteIssueNote(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "delete_issue_note",
+			Description: "Delete a note from an issue, including a note in a discussion thread (note IDs are listed by list_issue_discussions). A thread is removed once all its notes are deleted. This action is irreversible.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"issue_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the issue within the project",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"note_id": {
+						Type:        "integer",
+						Description: "The ID of the note to delete",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"project_id", "issue_iid", "note_id"},
+			},
+		},
+		withArgs("delete_issue_note", func(ctx context.Context, c *ToolContext, args deleteIssueNoteArgs) (*mcp.CallToolResult, error) {
+			return deleteNote(ctx, c, args.ProjectID, "issue", args.IssueIID, args.NoteID)
+		}),
+	)
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDeleteNoteTools(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodDelete, "/projects/acme%2Fapi/merge_requests/7/notes/20", http.StatusNoContent, nil)
+	client.Handle(http.MethodDelete, "/projects/acme%2Fapi/issues/3/notes/21", http.StatusNoContent, nil)
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"delete_note", map[string]interface{}{"project_id": "acme/api", "noteable_type": "merge_request", "noteable_iid": 7, "note_id": 20}, "Note 20 deleted from merge request !7"},
+		{"delete_note", map[string]interface{}{"project_id": "acme/api", "noteable_type": "issue", "noteable_iid": 3, "note_id": 21}, "Note 21 deleted from issue #3"},
+		{"delete_merge_request_note", map[string]interface{}{"project_id": "acme/api", "merge_request_iid": 7, "note_id": 20}, "Note 20 deleted from merge request !7"},
+		{"delete_issue_note", map[string]interface{}{"project_id": "acme/api", "issue_iid": 3, "note_id": 21}, "Note 21 deleted from issue #3"},
+	}
+	for _, tt := range tests {
+		result := callTool(t, tc, tt.name, tt.args)
+		if got := resultText(t, result); result.IsError || got != tt.want {
+			t.Errorf("%s = %q (error %v), want %q", tt.name, got, result.IsError, tt.want)
+		}
+	}
+
+	tc.Config.ReadOnlyMode = true
+	requests := len(client.Requests())
+	result := callTool(t, tc, "delete_issue_note", map[string]interface{}{"project_id": "acme/api", "issue_iid": 3, "note_id": 21})
+	if !result.IsError || !strings.Contains(resultText(t, result), "read-only mode") {
+		t.Errorf("delete_issue_note in read-only mode = %q, want a read-only error", resultText(t, result))
+	}
+	if len(client.Requests()) != requests {
+		t.Error("delete_issue_note in read-only mode sent a request")
+	}
+}
+
+func TestDeleteNoteErrors(t *testing.T) {
+	tc, client := newTestContext(t)
+	// GitLab refuses to delete system notes
+	client.Handle(http.MethodDelete, "/projects/acme%2Fapi/issues/3/notes/1", http.StatusForbidden, map[string]interface{}{"message": "403 Forbidden"})
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		want     string
+		requests int
+	}{
+		{"unknown noteable type", map[string]interface{}{"project_id": "acme/api", "noteable_type": "epic", "noteable_iid": 3, "note_id": 21}, "noteable_type must be one of: issue, merge_request", 0},
+		{"missing note ID", map[string]interface{}{"project_id": "acme/api", "noteable_type": "issue", "noteable_iid": 3, "note_id": 0}, "note_id is required", 0},
+		{"system note", map[string]interface{}{"project_id": "acme/api", "noteable_type": "issue", "noteable_iid": 3, "note_id": 1}, "Failed to delete note: GitLab API error: 403 Forbidden", 1},
+		{"deleted note", map[string]interface{}{"project_id": "acme/api", "noteable_type": "issue", "noteable_iid": 3, "note_id": 99}, "(status: 404", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(client.Requests())
+			result := callTool(t, tc, "delete_note", tt.args)
+			if !result.IsError || !strings.Contains(result.Content[0].Text, tt.want) {
+				t.Errorf("delete_note = %q, want an error containing %q", result.Content[0].Text, tt.want)
+			}
+			if sent := len(client.Requests()) - before; sent != tt.requests {
+				t.Errorf("sent %d requests, want %d", sent, tt.requests)
+			}
+		})
+	}
+}
+
+func TestDeleteNoteNestedProject(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodDelete, "/projects/acme%2Fplatform%2Fapi/merge_requests/7/notes/20", http.StatusNoContent, nil)
+	result := callTool(t, tc, "delete_merge_request_note", map[string]interface{}{"project_id": "acme/platform/api", "merge_request_iid": 7, "note_id": 20})
+	if result.IsError {
+		t.Errorf("delete_merge_request_note in a subgroup = %q", result.Content[0].Text)
+	}
+}
@@ -1,8 +1,10 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
@@ -37,12 +39,12 @@ func registerUpdateDraftNote(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid", "draft_note_id", "body"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("update_draft_note", args)
+			c.Logger.ToolCall(ctx, "update_draft_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -69,8 +71,8 @@ func registerUpdateDraftNote(server *mcp.Server) {
 			}
 
 			var draftNote DraftNote
-			if err := ctx.Client.Put(endpoint, requestBody, &draftNote); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to update draft note: %v", err))
+			if err := c.Client.Put(ctx, endpoint, requestBody, &draftNote); err != nil {
+				return APIErrorResult("failed to update draft note", err)
 			}
 
 			return JSONResult(draftNote)
@@ -103,12 +105,12 @@ func registerDeleteDraftNote(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid", "draft_note_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("delete_draft_note", args)
+			c.Logger.ToolCall(ctx, "delete_draft_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -126,8 +128,8 @@ func registerDeleteDraftNote(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/draft_notes/%d",
 				url.PathEscape(projectID), mrIID, draftNoteID)
 
-			if err := ctx.Client.Delete(endpoint); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to delete draft note: %v", err))
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("failed to delete draft note", err)
 			}
 
 			return TextResult(fmt.Sprintf("Draft note %d deleted successfully", draftNoteID))
@@ -160,12 +162,12 @@ func registerPublishDraftNote(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid", "draft_note_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("publish_draft_note", args)
+			c.Logger.ToolCall(ctx, "publish_draft_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -185,8 +187,8 @@ func registerPublishDraftNote(server *mcp.Server) {
 
 			// PUT request with empty body to publish
 			var result interface{}
-			if err := ctx.Client.Put(endpoint, nil, &result); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to publish draft note: %v", err))
+			if err := c.Client.Put(ctx, endpoint, nil, &result); err != nil {
+				return APIErrorResult("failed to publish draft note", err)
 			}
 
 			return TextResult(fmt.Sprintf("Draft note %d published successfully", draftNoteID))
@@ -215,12 +217,12 @@ func registerBulkPublishDraftNotes(server *mcp.Server) {
 				Required: []string{"project_id", "merge_request_iid"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("bulk_publish_draft_notes", args)
+			c.Logger.ToolCall(ctx, "bulk_publish_draft_notes", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -236,8 +238,8 @@ func registerBulkPublishDraftNotes(server *mcp.Server) {
 
 			// POST request with empty body to bulk publish
 			var result interface{}
-			if err := ctx.Client.Post(endpoint, nil, &result); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to bulk publish draft notes: %v", err))
+			if err := c.Client.Post(ctx, endpoint, nil, &result); err != nil {
+				return APIErrorResult("failed to bulk publish draft notes", err)
 			}
 
 			return TextResult("All draft notes published successfully")
@@ -278,12 +280,12 @@ func registerUpdateIssueNote(server *mcp.Server) {
 				Required: []string{"project_id", "issue_iid", "discussion_id", "note_id", "body"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("update_issue_note", args)
+			c.Logger.ToolCall(ctx, "update_issue_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -314,8 +316,8 @@ func registerUpdateIssueNote(server *mcp.Server) {
 			}
 
 			var note gitlab.Note
-			if err := ctx.Client.Put(endpoint, requestBody, &note); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to update issue note: %v", err))
+			if err := c.Client.Put(ctx, endpoint, requestBody, &note); err != nil {
+				return APIErrorResult("failed to update issue note", err)
 			}
 
 			return JSONResult(note)
@@ -352,12 +354,12 @@ func registerCreateIssueNote(server *mcp.Server) {
 				Required: []string{"project_id", "issue_iid", "discussion_id", "body"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("create_issue_note", args)
+			c.Logger.ToolCall(ctx, "create_issue_note", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -384,8 +386,8 @@ func registerCreateIssueNote(server *mcp.Server) {
 			}
 
 			var note gitlab.Note
-			if err := ctx.Client.Post(endpoint, requestBody, &note); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to create issue note: %v", err))
+			if err := c.Client.Post(ctx, endpoint, requestBody, &note); err != nil {
+				return APIErrorResult("failed to create issue note", err)
 			}
 
 			return JSONResult(note)
@@ -393,6 +395,129 @@ func registerCreateIssueNote(server *mcp.Server) {
 	)
 }
 
+// registerUpsertNote registers the upsert_note tool.
+func registerUpsertNote(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "upsert_note",
+			Description: "Create or update a note (comment) on an issue or merge request, keyed by a hidden HTML marker such as <!-- mcp:review-summary -->. If a note containing the marker exists, it is updated in place; otherwise a new note is created. Use this instead of create_note for comments a workflow may post again, so re-runs do not stack duplicates.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"noteable_type": {
+						Type:        "string",
+						Description: "The type of noteable: issue or merge_request",
+						Enum:        []string{"issue", "merge_request"},
+					},
+					"noteable_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the issue or merge request",
+					},
+					"marker": {
+						Type:        "string",
+						Description: "An HTML comment identifying the note (e.g., <!-- mcp:review-summary -->); prepended to body if body does not contain it",
+					},
+					"body": {
+						Type:        "string",
+						Description: "The content of the note",
+					},
+				},
+				Required: []string{"project_id", "noteable_type", "noteable_iid", "marker", "body"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "upsert_note", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			noteableType := GetString(args, "noteable_type", "")
+			if noteableType == "" {
+				return ErrorResult("noteable_type is required")
+			}
+			noteableIID := GetInt(args, "noteable_iid", 0)
+			if noteableIID == 0 {
+				return ErrorResult("noteable_iid is required")
+			}
+			marker := strings.TrimSpace(GetString(args, "marker", ""))
+			if marker == "" {
+				return ErrorResult("marker is required")
+			}
+			// A marker outside an HTML comment would show in the rendered note
+			if !strings.HasPrefix(marker, "<!--") || !strings.HasSuffix(marker, "-->") {
+				return ErrorResult("marker must be an HTML comment, e.g. <!-- mcp:review-summary -->")
+			}
+			body := GetString(args, "body", "")
+			if body == "" {
+				return ErrorResult("body is required")
+			}
+
+			var endpoint string
+			switch noteableType {
+			case "issue":
+				endpoint = fmt.Sprintf("/projects/%s/issues/%d/notes", url.PathEscape(projectID), noteableIID)
+			case "merge_request":
+				endpoint = fmt.Sprintf("/projects/%s/merge_requests/%d/notes", url.PathEscape(projectID), noteableIID)
+			default:
+				return ErrorResult("noteable_type must be 'issue' or 'merge_request'")
+			}
+
+			note, action, err := upsertNoteByMarker(ctx, c, endpoint, marker, body)
+			if err != nil {
+				return APIErrorResult("Failed to upsert note", err)
+			}
+
+			return JSONResult(map[string]interface{}{
+				"action": action,
+				"note":   note,
+			})
+		},
+	)
+}
+
+// upsertNoteByMarker updates the newest note under notesEndpoint whose body
+// contains marker, or creates a note if there is none, so re-running a workflow
+// replaces its comment instead of adding another. The marker is prepended to
+// body if missing. It returns the note and whether it was "created" or "updated".
+func upsertNoteByMarker(ctx context.Context, c *ToolContext, notesEndpoint, marker, body string) (*gitlab.Note, string, error) {
+	if !strings.Contains(body, marker) {
+		body = marker + "\n" + body
+	}
+
+	notes, _, err := collectPages[gitlab.Note](ctx, c.Client, notesEndpoint+"?sort=desc&order_by=created_at", maxCollectedItems)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list notes: %w", err)
+	}
+
+	requestBody := map[string]interface{}{
+		"body": body,
+	}
+	var note gitlab.Note
+	for _, existing := range notes {
+		if existing.System || !strings.Contains(existing.Body, marker) {
+			continue
+		}
+		if err := c.Client.Put(ctx, fmt.Sprintf("%s/%d", notesEndpoint, existing.ID), requestBody, &note); err != nil {
+			return nil, "", fmt.Errorf("failed to update note %d: %w", existing.ID, err)
+		}
+		return &note, "updated", nil
+	}
+
+	if err := c.Client.Post(ctx, notesEndpoint, requestBody, &note); err != nil {
+		return nil, "", fmt.Errorf("failed to create note: %w", err)
+	}
+	return &note, "created", nil
+}
+
 // RegisterNoteTools registers all note-related tools with the MCP server.
 // Includes: update_draft_note, delete_draft_note, publish_draft_note,
 // bulk_publish_draft_notes, update_issue_note, create_issue_note
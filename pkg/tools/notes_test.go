@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+func TestUpsertNoteIsRegistered(t *testing.T) {
+	server := mcp.NewServer("test", "0.0.0")
+	RegisterMergeRequestTools(server)
+	for _, name := range server.ToolNames() {
+		if name == "upsert_note" {
+			return
+		}
+	}
+	t.Error("upsert_note is not registered with the merge request tools")
+}
+
+func TestUpsertNoteByMarker(t *testing.T) {
+	const marker = "<!-- mcp:summary -->"
+	tests := []struct {
+		name       string
+		notes      string
+		body       string
+		wantAction string
+		wantMethod string
+		wantPath   string
+		wantBody   string
+	}{
+		{
+			name:       "updates the newest marked note",
+			notes:      `[{"id": 30, "body": "unrelated"}, {"id": 20, "body": "<!-- mcp:summary -->\nold"}, {"id": 10, "body": "<!-- mcp:summary -->\nolder"}]`,
+			body:       "new",
+			wantAction: "updated",
+			wantMethod: http.MethodPut,
+			wantPath:   "/api/v4/projects/acme%2Fapi/merge_requests/7/notes/20",
+			wantBody:   marker + "\nnew",
+		},
+		{
+			name:       "skips system notes",
+			notes:      `[{"id": 20, "body": "<!-- mcp:summary --> changed the description", "system": true}]`,
+			body:       "new",
+			wantAction: "created",
+			wantMethod: http.MethodPost,
+			wantPath:   "/api/v4/projects/acme%2Fapi/merge_requests/7/notes",
+			wantBody:   marker + "\nnew",
+		},
+		{
+			name:       "keeps a marker already in the body",
+			notes:      `[]`,
+			body:       "new\n" + marker,
+			wantAction: "created",
+			wantMethod: http.MethodPost,
+			wantPath:   "/api/v4/projects/acme%2Fapi/merge_requests/7/notes",
+			wantBody:   "new\n" + marker,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method, path, body string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == http.MethodGet {
+					w.Write([]byte(tt.notes))
+					return
+				}
+				var request map[string]string
+				json.NewDecoder(r.Body).Decode(&request)
+				method, path, body = r.Method, r.URL.EscapedPath(), request["body"]
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": 40, "body": request["body"]})
+			}))
+			defer srv.Close()
+
+			c := NewToolContext(gitlab.NewClient(srv.URL, "token"), nil, nil)
+			note, action, err := upsertNoteByMarker(context.Background(), c, "/projects/acme%2Fapi/merge_requests/7/notes", marker, tt.body)
+			if err != nil {
+				t.Fatalf("upsertNoteByMarker: %v", err)
+			}
+			if action != tt.wantAction {
+				t.Errorf("action = %q, want %q", action, tt.wantAction)
+			}
+			if method != tt.wantMethod || path != tt.wantPath {
+				t.Errorf("request = %s %s, want %s %s", method, path, tt.wantMethod, tt.wantPath)
+			}
+			if body != tt.wantBody || note.Body != tt.wantBody {
+				t.Errorf("body = %q, note body = %q, want %q", body, note.Body, tt.wantBody)
+			}
+		})
+	}
+}
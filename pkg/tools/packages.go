@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// PackageFile represents a file in the GitLab package registry.
+type PackageFile struct {
+	ID         int        `json:"id"`
+	PackageID  int        `json:"package_id"`
+	FileName   string     `json:"file_name"`
+	Size       int64      `json:"size"`
+	FileMD5    string     `json:"file_md5,omitempty"`
+	FileSHA1   string     `json:"file_sha1,omitempty"`
+	FileSHA256 string     `json:"file_sha256,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
+
+// registerUploadGenericPackage registers the upload_generic_package tool.
+func registerUploadGenericPackage(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "upload_generic_package",
+			Description: "Upload a file to the project's generic package registry (e.g., a release binary). Returns the stored package file with checksums and the download_url, which can be attached to a release as an asset link.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: withUploadFileProperties(map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"package_name": {
+						Type:        "string",
+						Description: "The package name (letters, digits, dots, dashes and underscores)",
+					},
+					"package_version": {
+						Type:        "string",
+						Description: "The package version (e.g., 1.2.3)",
+					},
+					"status": {
+						Type:        "string",
+						Description: "Package status: default, or hidden to keep it out of the package list (optional)",
+						Enum:        []string{"default", "hidden"},
+					},
+				}),
+				Required: []string{"project_id", "package_name", "package_version"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "upload_generic_package", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			packageName := GetString(args, "package_name", "")
+			if packageName == "" {
+				return ErrorResult("package_name is required")
+			}
+			packageVersion := GetString(args, "package_version", "")
+			if packageVersion == "" {
+				return ErrorResult("package_version is required")
+			}
+
+			file, closer, err := openUpload(c, args, "file")
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			defer closer.Close()
+
+			path := fmt.Sprintf("/projects/%s/packages/generic/%s/%s/%s",
+				url.PathEscape(projectID),
+				url.PathEscape(packageName),
+				url.PathEscape(packageVersion),
+				url.PathEscape(file.Name),
+			)
+			params := url.Values{}
+			params.Set("select", "package_file")
+			if status := GetString(args, "status", ""); status != "" {
+				params.Set("status", status)
+			}
+
+			// The generic package registry takes the raw file as the PUT body
+			mcp.ReportProgress(ctx, 0, 1, fmt.Sprintf("Uploading %s", file.Name))
+			var packageFile PackageFile
+			if err := c.Client.PutFile(ctx, path+"?"+params.Encode(), file.Reader, &packageFile); err != nil {
+				return APIErrorResult("Failed to upload package file", err)
+			}
+
+			return JSONResult(map[string]interface{}{
+				"package_file": packageFile,
+				"download_url": c.Client.BaseURL() + path,
+			})
+		},
+	)
+}
+
+// initPackageTools registers the package registry tools.
+func initPackageTools(server *mcp.Server) {
+	registerUploadGenericPackage(server)
+}
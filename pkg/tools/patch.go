@@ -0,0 +1,478 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// filePatch is the part of a unified diff that changes one file. An empty
+// oldPath means the file is created; an empty newPath means it is deleted.
+type filePatch struct {
+	oldPath string
+	newPath string
+	hunks   []patchHunk
+}
+
+// patchHunk is one "@@ -l,s +l,s @@" section of a file patch.
+type patchHunk struct {
+	oldStart int
+	oldCount int
+	newStart int
+	newCount int
+	ops      []diffOp
+	// oldNoNewline and newNoNewline record a "\ No newline at end of file"
+	// marker after the last old or new line of the hunk.
+	oldNoNewline bool
+	newNoNewline bool
+}
+
+// PatchedFile summarizes the change apply_patch makes to one file.
+type PatchedFile struct {
+	Action       string `json:"action"`
+	FilePath     string `json:"file_path"`
+	PreviousPath string `json:"previous_path,omitempty"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+}
+
+// registerApplyPatch registers the apply_patch tool.
+func registerApplyPatch(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "apply_patch",
+			Description: "Apply a unified diff (e.g., git diff output) to a branch as a single commit. The current files are fetched, the hunks are applied (tolerating shifted line numbers, not changed context), and the result is pushed as create/update/delete/move actions. Use dry_run to check that the patch applies without committing. Binary patches are not supported.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"branch": {
+						Type:        "string",
+						Description: "The name of the branch to commit to",
+					},
+					"start_branch": {
+						Type:        "string",
+						Description: "Create branch from this branch and apply the patch to its files (optional, branch must not exist yet)",
+					},
+					"patch": {
+						Type:        "string",
+						Description: "The unified diff to apply. Paths may carry git's a/ and b/ prefixes",
+					},
+					"commit_message": {
+						Type:        "string",
+						Description: "The commit message",
+					},
+					"author_email": {
+						Type:        "string",
+						Description: "The commit author's email address (optional)",
+					},
+					"author_name": {
+						Type:        "string",
+						Description: "The commit author's name (optional)",
+					},
+					"dry_run": {
+						Type:        "boolean",
+						Description: "Only check that the patch applies and list the resulting file actions (optional, default: false)",
+					},
+				},
+				Required: []string{"project_id", "branch", "patch", "commit_message"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "apply_patch", args)
+
+			// Check read-only mode
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot apply patch: server is in read-only mode")
+			}
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			branch := GetString(args, "branch", "")
+			if branch == "" {
+				return ErrorResult("branch is required")
+			}
+			patch := GetString(args, "patch", "")
+			if patch == "" {
+				return ErrorResult("patch is required")
+			}
+			commitMessage := GetString(args, "commit_message", "")
+			if commitMessage == "" {
+				return ErrorResult("commit_message is required")
+			}
+			startBranch := GetString(args, "start_branch", "")
+
+			files, err := parseUnifiedDiff(patch)
+			if err != nil {
+				return ErrorResult(fmt.Sprintf("invalid patch: %v", err))
+			}
+
+			ref := branch
+			if startBranch != "" {
+				ref = startBranch
+			}
+			encodedProjectID := url.PathEscape(projectID)
+
+			var actions []CommitAction
+			summary := make([]PatchedFile, 0, len(files))
+			for i, file := range files {
+				mcp.ReportProgress(ctx, float64(i), float64(len(files)), fmt.Sprintf("Applying patch to %s", file.displayPath()))
+
+				patched := PatchedFile{FilePath: file.newPath}
+				for _, hunk := range file.hunks {
+					for _, op := range hunk.ops {
+						switch op.kind {
+						case '+':
+							patched.Additions++
+						case '-':
+							patched.Deletions++
+						}
+					}
+				}
+
+				// lastCommitID guards against the file changing between the fetch and the commit
+				original, lastCommitID := "", ""
+				if file.oldPath != "" {
+					endpoint := fmt.Sprintf("/projects/%s/repository/files/%s?ref=%s", encodedProjectID, url.PathEscape(file.oldPath), url.QueryEscape(ref))
+					var fileResp FileResponse
+					if err := c.Client.Get(ctx, endpoint, &fileResp); err != nil {
+						return APIErrorResult("Failed to get "+file.oldPath, err)
+					}
+					decoded, err := base64.StdEncoding.DecodeString(fileResp.Content)
+					if err != nil {
+						return APIErrorResult("Failed to decode "+file.oldPath, err)
+					}
+					original = string(decoded)
+					lastCommitID = fileResp.LastCommitID
+				}
+
+				content, err := applyHunks(original, file.hunks)
+				if err != nil {
+					return ErrorResult(fmt.Sprintf("patch does not apply to %s: %v", file.displayPath(), err))
+				}
+
+				switch {
+				case file.newPath == "":
+					patched.Action = "delete"
+					patched.FilePath = file.oldPath
+					actions = append(actions, CommitAction{Action: "delete", FilePath: file.oldPath, LastCommitID: lastCommitID})
+				case file.oldPath == "":
+					patched.Action = "create"
+					actions = append(actions, CommitAction{Action: "create", FilePath: file.newPath, Content: content})
+				case file.oldPath != file.newPath:
+					patched.Action = "move"
+					patched.PreviousPath = file.oldPath
+					actions = append(actions, CommitAction{Action: "move", FilePath: file.newPath, PreviousPath: file.oldPath, Content: content, LastCommitID: lastCommitID})
+				default:
+					patched.Action = "update"
+					actions = append(actions, CommitAction{Action: "update", FilePath: file.newPath, Content: content, LastCommitID: lastCommitID})
+				}
+				summary = append(summary, patched)
+			}
+
+			if GetBool(args, "dry_run", false) {
+				return JSONResult(map[string]interface{}{
+					"applies": true,
+					"files":   summary,
+				})
+			}
+
+			encodeCommitActions(actions)
+			commitRequest := CommitRequest{
+				Branch:        branch,
+				StartBranch:   startBranch,
+				CommitMessage: commitMessage,
+				Actions:       actions,
+				AuthorEmail:   GetString(args, "author_email", ""),
+				AuthorName:    GetString(args, "author_name", ""),
+			}
+
+			var response CommitResponse
+			endpoint := fmt.Sprintf("/projects/%s/repository/commits", encodedProjectID)
+			if err := c.Client.Post(ctx, endpoint, commitRequest, &response); err != nil {
+				return APIErrorResult("Failed to commit patch", err)
+			}
+
+			return JSONResult(map[string]interface{}{
+				"commit_id":    response.ID,
+				"short_id":     response.ShortID,
+				"title":        response.Title,
+				"author_name":  response.AuthorName,
+				"author_email": response.AuthorEmail,
+				"web_url":      response.WebURL,
+				"files":        summary,
+			})
+		},
+	)
+}
+
+// displayPath returns the path a file patch is best referred to by.
+func (f filePatch) displayPath() string {
+	if f.newPath != "" {
+		return f.newPath
+	}
+	return f.oldPath
+}
+
+// parseUnifiedDiff splits a unified diff into per-file patches. It understands
+// plain "diff -u" output as well as git's extended headers, including pure
+// renames that carry no hunks.
+func parseUnifiedDiff(patch string) ([]filePatch, error) {
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+
+	var files []filePatch
+	var current *filePatch
+	// Paths announced by a git "rename from/to" header, used when no ---/+++ lines follow
+	var renameFrom, renameTo string
+
+	flushRename := func() {
+		if current == nil && renameFrom != "" && renameTo != "" {
+			files = append(files, filePatch{oldPath: renameFrom, newPath: renameTo})
+		}
+		renameFrom, renameTo = "", ""
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushRename()
+			current = nil
+		case strings.HasPrefix(line, "rename from "):
+			renameFrom = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			renameTo = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			return nil, fmt.Errorf("line %d: binary patches are not supported", i+1)
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			oldPath := patchPath(strings.TrimPrefix(line, "--- "), "a/")
+			newPath := patchPath(strings.TrimPrefix(lines[i+1], "+++ "), "b/")
+			if oldPath == "" && newPath == "" {
+				return nil, fmt.Errorf("line %d: both sides of the file header are /dev/null", i+1)
+			}
+			files = append(files, filePatch{oldPath: oldPath, newPath: newPath})
+			current = &files[len(files)-1]
+			renameFrom, renameTo = "", ""
+			i++
+		case strings.HasPrefix(line, "@@ "):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: hunk without a ---/+++ file header", i+1)
+			}
+			hunk, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			current.hunks = append(current.hunks, hunk)
+			i = next - 1
+		}
+	}
+	flushRename()
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no file changes found")
+	}
+	for _, file := range files {
+		if len(file.hunks) == 0 && file.oldPath == file.newPath {
+			return nil, fmt.Errorf("%s: no hunks", file.displayPath())
+		}
+	}
+	return files, nil
+}
+
+// patchPath extracts the file path from a ---/+++ header value, dropping a
+// trailing timestamp and the given git prefix. /dev/null yields "".
+func patchPath(value, prefix string) string {
+	if tab := strings.IndexByte(value, '\t'); tab >= 0 {
+		value = value[:tab]
+	}
+	value = strings.TrimSpace(value)
+	if value == "/dev/null" {
+		return ""
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	return strings.TrimPrefix(value, prefix)
+}
+
+// parseHunk parses the hunk whose header is lines[start] and returns it with
+// the index of the first line after it.
+func parseHunk(lines []string, start int) (patchHunk, int, error) {
+	var hunk patchHunk
+	header := lines[start]
+	end := strings.Index(header[3:], " @@")
+	if end < 0 {
+		return hunk, 0, fmt.Errorf("line %d: malformed hunk header %q", start+1, header)
+	}
+	ranges := strings.Fields(header[3 : 3+end])
+	if len(ranges) != 2 || !strings.HasPrefix(ranges[0], "-") || !strings.HasPrefix(ranges[1], "+") {
+		return hunk, 0, fmt.Errorf("line %d: malformed hunk header %q", start+1, header)
+	}
+	var err error
+	if hunk.oldStart, hunk.oldCount, err = parseHunkRange(ranges[0][1:]); err != nil {
+		return hunk, 0, fmt.Errorf("line %d: %w", start+1, err)
+	}
+	if hunk.newStart, hunk.newCount, err = parseHunkRange(ranges[1][1:]); err != nil {
+		return hunk, 0, fmt.Errorf("line %d: %w", start+1, err)
+	}
+
+	oldSeen, newSeen := 0, 0
+	i := start + 1
+	for ; i < len(lines) && (oldSeen < hunk.oldCount || newSeen < hunk.newCount); i++ {
+		line := lines[i]
+		if line == "" {
+			// Some tools strip the single space of empty context lines
+			line = " "
+		}
+		kind := line[0]
+		switch kind {
+		case ' ':
+			oldSeen++
+			newSeen++
+		case '-':
+			oldSeen++
+		case '+':
+			newSeen++
+		case '\\':
+			hunk.markNoNewline()
+			continue
+		default:
+			return hunk, 0, fmt.Errorf("line %d: unexpected line in hunk: %q", i+1, line)
+		}
+		hunk.ops = append(hunk.ops, diffOp{kind, line[1:]})
+	}
+	if oldSeen != hunk.oldCount || newSeen != hunk.newCount {
+		return hunk, 0, fmt.Errorf("line %d: hunk is truncated", start+1)
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], "\\") {
+		hunk.markNoNewline()
+		i++
+	}
+	return hunk, i, nil
+}
+
+// markNoNewline applies a "\ No newline at end of file" marker to the side
+// of the hunk's last line.
+func (h *patchHunk) markNoNewline() {
+	if len(h.ops) == 0 {
+		return
+	}
+	switch h.ops[len(h.ops)-1].kind {
+	case '-':
+		h.oldNoNewline = true
+	case '+':
+		h.newNoNewline = true
+	default:
+		h.oldNoNewline = true
+		h.newNoNewline = true
+	}
+}
+
+// parseHunkRange parses "start,count" or "start" (count 1) from a hunk header.
+func parseHunkRange(value string) (int, int, error) {
+	startText, countText, hasCount := strings.Cut(value, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid hunk range %q", value)
+	}
+	count := 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, fmt.Errorf("invalid hunk range %q", value)
+		}
+	}
+	return start, count, nil
+}
+
+// applyHunks applies hunks in order to content. A hunk whose context is not
+// found at its stated line is looked up nearby, so patches made against a
+// slightly different revision still apply as long as the context matches.
+func applyHunks(content string, hunks []patchHunk) (string, error) {
+	lines := splitLines(content)
+	trailingNewline := content == "" || strings.HasSuffix(content, "\n")
+
+	result := make([]string, 0, len(lines))
+	pos := 0    // next unconsumed line of lines
+	offset := 0 // how far hunks have been found from their stated position
+	for n, hunk := range hunks {
+		var oldLines, newLines []string
+		for _, op := range hunk.ops {
+			if op.kind != '+' {
+				oldLines = append(oldLines, op.line)
+			}
+			if op.kind != '-' {
+				newLines = append(newLines, op.line)
+			}
+		}
+
+		// For a pure insertion oldStart is the line after which to insert
+		expected := hunk.oldStart - 1
+		if hunk.oldCount == 0 {
+			expected = hunk.oldStart
+		}
+		at := findLines(lines, oldLines, expected+offset, pos)
+		if at < 0 {
+			return "", fmt.Errorf("hunk %d (@@ -%d,%d +%d,%d @@) does not match the current file", n+1, hunk.oldStart, hunk.oldCount, hunk.newStart, hunk.newCount)
+		}
+		offset = at - expected
+
+		result = append(result, lines[pos:at]...)
+		result = append(result, newLines...)
+		pos = at + len(oldLines)
+
+		if hunk.newNoNewline {
+			trailingNewline = false
+		} else if hunk.oldNoNewline {
+			trailingNewline = true
+		}
+	}
+	result = append(result, lines[pos:]...)
+
+	if len(result) == 0 {
+		return "", nil
+	}
+	text := strings.Join(result, "\n")
+	if trailingNewline {
+		text += "\n"
+	}
+	return text, nil
+}
+
+// findLines returns the index in lines, at or after min, where want occurs,
+// preferring the occurrence closest to expected. It returns -1 if there is none.
+func findLines(lines, want []string, expected, min int) int {
+	matches := func(at int) bool {
+		if at < min || at+len(want) > len(lines) {
+			return false
+		}
+		for k, line := range want {
+			if lines[at+k] != line {
+				return false
+			}
+		}
+		return true
+	}
+	for distance := 0; expected-distance >= min || expected+distance <= len(lines); distance++ {
+		if matches(expected - distance) {
+			return expected - distance
+		}
+		if distance > 0 && matches(expected+distance) {
+			return expected + distance
+		}
+	}
+	return -1
+}
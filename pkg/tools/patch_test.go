@@ -0,0 +1,333 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyPatchReadOnlyMode(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.ReadOnlyMode = true
+	result := callTool(t, tc, "apply_patch", map[string]interface{}{
+		"project_id":     "42",
+		"branch":         "main",
+		"patch":          "--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-old\n+new\n",
+		"commit_message": "Update README",
+	})
+	if !result.IsError || !strings.Contains(resultText(t, result), "server is in read-only mode") {
+		t.Errorf("apply_patch in read-only mode = %q, want a read-only error", resultText(t, result))
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("read-only mode sent %d requests, want none", len(requests))
+	}
+}
+
+func TestApplyPatchCommitActions(t *testing.T) {
+	tc, client := newTestContext(t)
+	for path, file := range map[string]FileResponse{
+		"old.txt":   {Content: base64.StdEncoding.EncodeToString([]byte("a\nb\n")), LastCommitID: "c1"},
+		"empty.txt": {Content: base64.StdEncoding.EncodeToString([]byte("x\n")), LastCommitID: "c2"},
+	} {
+		client.Handle(http.MethodGet, "/projects/42/repository/files/"+path, http.StatusOK, file)
+	}
+	client.Handle(http.MethodPost, "/projects/42/repository/commits", http.StatusCreated, `{"id": "c3"}`)
+
+	result := callTool(t, tc, "apply_patch", map[string]interface{}{
+		"project_id":     "42",
+		"branch":         "main",
+		"patch":          "--- a/old.txt\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-a\n-b\n--- a/empty.txt\n+++ b/empty.txt\n@@ -1 +0,0 @@\n-x\n",
+		"commit_message": "Remove files",
+	})
+	if result.IsError {
+		t.Fatalf("apply_patch: %s", resultText(t, result))
+	}
+
+	requests := client.Requests()
+	commit := requests[len(requests)-1]
+	var body struct {
+		Actions []map[string]interface{} `json:"actions"`
+	}
+	if err := json.Unmarshal(commit.Body, &body); err != nil {
+		t.Fatalf("decode commit request: %v", err)
+	}
+	// Both actions are guarded by the commit the file was read at, and the
+	// emptied file is sent with its (empty) content
+	want := []map[string]interface{}{
+		{"action": "delete", "file_path": "old.txt", "last_commit_id": "c1"},
+		{"action": "update", "file_path": "empty.txt", "content": "", "last_commit_id": "c2"},
+	}
+	if !reflect.DeepEqual(body.Actions, want) {
+		t.Errorf("actions = %v, want %v", body.Actions, want)
+	}
+}
+
+func TestApplyPatchDeleteMismatch(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/42/repository/files/old.txt", http.StatusOK, FileResponse{Content: base64.StdEncoding.EncodeToString([]byte("changed\n"))})
+
+	result := callTool(t, tc, "apply_patch", map[string]interface{}{
+		"project_id":     "42",
+		"branch":         "main",
+		"patch":          "--- a/old.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-a\n",
+		"commit_message": "Remove old.txt",
+	})
+	if !result.IsError || !strings.Contains(resultText(t, result), "patch does not apply to old.txt") {
+		t.Errorf("deleting a changed file = %q, want a patch error", resultText(t, result))
+	}
+}
+
+func TestCommitActionJSON(t *testing.T) {
+	tests := []struct {
+		action CommitAction
+		want   string
+	}{
+		{CommitAction{Action: "update", FilePath: "a"}, `{"action":"update","file_path":"a","content":""}`},
+		{CommitAction{Action: "create", FilePath: "a"}, `{"action":"create","file_path":"a","content":""}`},
+		{CommitAction{Action: "update", FilePath: "a", Content: "eA==", Encoding: "base64"}, `{"action":"update","file_path":"a","content":"eA==","encoding":"base64"}`},
+		// A move without content keeps the file's content
+		{CommitAction{Action: "move", FilePath: "b", PreviousPath: "a"}, `{"action":"move","file_path":"b","previous_path":"a"}`},
+		{CommitAction{Action: "delete", FilePath: "a", LastCommitID: "c1"}, `{"action":"delete","file_path":"a","last_commit_id":"c1"}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.action)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got, want interface{}
+		json.Unmarshal(data, &got)
+		json.Unmarshal([]byte(tt.want), &want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s action = %s, want %s", tt.action.Action, data, tt.want)
+		}
+	}
+}
+
+func TestParseUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  []filePatch // paths and hunk counts only
+	}{
+		{
+			name:  "create from /dev/null",
+			patch: "--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+			want:  []filePatch{{newPath: "new.txt", hunks: make([]patchHunk, 1)}},
+		},
+		{
+			name:  "delete to /dev/null",
+			patch: "--- a/old.txt\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+			want:  []filePatch{{oldPath: "old.txt", hunks: make([]patchHunk, 1)}},
+		},
+		{
+			name:  "pure rename followed by an edit",
+			patch: "diff --git a/x.go b/y.go\nsimilarity index 100%\nrename from x.go\nrename to y.go\ndiff --git a/z.go b/z.go\n--- a/z.go\n+++ b/z.go\n@@ -1 +1 @@\n-a\n+b\n",
+			want:  []filePatch{{oldPath: "x.go", newPath: "y.go"}, {oldPath: "z.go", newPath: "z.go", hunks: make([]patchHunk, 1)}},
+		},
+		{
+			name:  "rename with changes",
+			patch: "diff --git a/x.go b/y.go\nsimilarity index 90%\nrename from x.go\nrename to y.go\n--- a/x.go\n+++ b/y.go\n@@ -1 +1 @@\n-a\n+b\n",
+			want:  []filePatch{{oldPath: "x.go", newPath: "y.go", hunks: make([]patchHunk, 1)}},
+		},
+		{
+			name:  "plain diff -u with timestamps and two hunks",
+			patch: "--- f.txt\t2024-01-01 10:00:00\n+++ f.txt\t2024-01-02 10:00:00\n@@ -1 +1 @@\n-a\n+b\n@@ -10,2 +10,2 @@\n c\n-d\n+e\n",
+			want:  []filePatch{{oldPath: "f.txt", newPath: "f.txt", hunks: make([]patchHunk, 2)}},
+		},
+		{
+			name:  "quoted path",
+			patch: "--- \"a/with space.txt\"\n+++ \"b/with space.txt\"\n@@ -1 +1 @@\n-a\n+b\n",
+			want:  []filePatch{{oldPath: "with space.txt", newPath: "with space.txt", hunks: make([]patchHunk, 1)}},
+		},
+		{
+			name:  "CRLF line endings",
+			patch: "--- a/f.txt\r\n+++ b/f.txt\r\n@@ -1,2 +1,2 @@\r\n a\r\n-b\r\n+c\r\n",
+			want:  []filePatch{{oldPath: "f.txt", newPath: "f.txt", hunks: make([]patchHunk, 1)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := parseUnifiedDiff(tt.patch)
+			if err != nil {
+				t.Fatalf("parseUnifiedDiff: %v", err)
+			}
+			if len(files) != len(tt.want) {
+				t.Fatalf("got %d files, want %d", len(files), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := files[i]
+				if got.oldPath != want.oldPath || got.newPath != want.newPath || len(got.hunks) != len(want.hunks) {
+					t.Errorf("file %d = %q -> %q with %d hunks, want %q -> %q with %d", i, got.oldPath, got.newPath, len(got.hunks), want.oldPath, want.newPath, len(want.hunks))
+				}
+			}
+		})
+	}
+}
+
+func TestParseUnifiedDiffErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		wantErr string
+	}{
+		{"binary files line", "diff --git a/logo.png b/logo.png\nBinary files a/logo.png and b/logo.png differ\n", "binary patches are not supported"},
+		{"git binary patch", "diff --git a/logo.png b/logo.png\nindex 1..2 100644\nGIT binary patch\nliteral 5\n", "binary patches are not supported"},
+		{"truncated hunk", "--- a/f.txt\n+++ b/f.txt\n@@ -1,3 +1,3 @@\n a\n-b\n", "hunk is truncated"},
+		{"unexpected line in hunk", "--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +1,2 @@\n a\n*b\n", "unexpected line in hunk"},
+		{"malformed hunk header", "--- a/f.txt\n+++ b/f.txt\n@@ -1 @@\n-a\n", "malformed hunk header"},
+		{"invalid hunk range", "--- a/f.txt\n+++ b/f.txt\n@@ -x,1 +1 @@\n-a\n+b\n", "invalid hunk range"},
+		{"hunk before file header", "@@ -1 +1 @@\n-a\n+b\n", "hunk without a ---/+++ file header"},
+		{"both sides /dev/null", "--- /dev/null\n+++ /dev/null\n", "both sides of the file header are /dev/null"},
+		{"file header without hunks", "--- a/f.txt\n+++ b/f.txt\n", "f.txt: no hunks"},
+		{"no changes", "just some text\n", "no file changes found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseUnifiedDiff(tt.patch)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseUnifiedDiff error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyHunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		patch   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "create",
+			patch: "--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+			want:  "a\nb\n",
+		},
+		{
+			name:    "delete every line",
+			content: "a\nb\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+			want:    "",
+		},
+		{
+			name:    "update in place",
+			content: "a\nb\nc\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+			want:    "a\nB\nc\n",
+		},
+		{
+			name:    "pure insertion after a line",
+			content: "a\nb\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,0 +2 @@\n+inserted\n",
+			want:    "a\ninserted\nb\n",
+		},
+		{
+			name:    "hunk shifted by lines added above",
+			content: "new1\nnew2\na\nb\nc\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+			want:    "new1\nnew2\na\nB\nc\n",
+		},
+		{
+			name:    "later hunk follows the offset of an earlier one",
+			content: "z\na\nb\nc\nd\ne\nf\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +1,2 @@\n-a\n+A\n b\n@@ -5,2 +5,2 @@\n e\n-f\n+F\n",
+			want:    "z\nA\nb\nc\nd\ne\nF\n",
+		},
+		{
+			name:    "closest of two matching positions",
+			content: "x\ny\nx\ny\nx\ny\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -5,2 +5,2 @@\n x\n-y\n+Y\n",
+			want:    "x\ny\nx\ny\nx\nY\n",
+		},
+		{
+			name:    "mismatched context",
+			content: "a\nx\nc\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+			wantErr: "hunk 1 (@@ -1,3 +1,3 @@) does not match the current file",
+		},
+		{
+			name:    "hunks out of order",
+			content: "a\nb\nc\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -3 +3 @@\n-c\n+C\n@@ -1 +1 @@\n-a\n+A\n",
+			wantErr: "hunk 2",
+		},
+		{
+			name:    "no newline at end of the new file",
+			content: "a\nb\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n\\ No newline at end of file\n",
+			want:    "a\nc",
+		},
+		{
+			name:    "no newline at end of the old file",
+			content: "a\nb",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n",
+			want:    "a\nc\n",
+		},
+		{
+			name:    "no newline at end of either file",
+			content: "a\nb",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +1,2 @@\n-a\n+x\n b\n\\ No newline at end of file\n",
+			want:    "x\nb",
+		},
+		{
+			name:    "CRLF patch",
+			content: "a\nb\n",
+			patch:   "--- a/f.txt\r\n+++ b/f.txt\r\n@@ -1,2 +1,2 @@\r\n a\r\n-b\r\n+c\r\n",
+			want:    "a\nc\n",
+		},
+		{
+			name:    "empty context line without its space",
+			content: "a\n\nb\n",
+			patch:   "--- a/f.txt\n+++ b/f.txt\n@@ -1,3 +1,3 @@\n a\n\n-b\n+B\n",
+			want:    "a\n\nB\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := parseUnifiedDiff(tt.patch)
+			if err != nil {
+				t.Fatalf("parseUnifiedDiff: %v", err)
+			}
+			got, err := applyHunks(tt.content, files[0].hunks)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("applyHunks error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyHunks: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("applyHunks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindLines(t *testing.T) {
+	lines := []string{"a", "b", "a", "b", "c"}
+	tests := []struct {
+		name          string
+		want          []string
+		expected, min int
+		at            int
+	}{
+		{"at the expected line", []string{"a", "b"}, 2, 0, 2},
+		{"closest before", []string{"a", "b"}, 1, 0, 0},
+		{"not before min", []string{"a", "b"}, 0, 1, 2},
+		{"after the expected line", []string{"c"}, 0, 0, 4},
+		{"empty want at the expected line", nil, 3, 0, 3},
+		{"missing", []string{"d"}, 0, 0, -1},
+		{"longer than the rest of the file", []string{"b", "c", "d"}, 3, 0, -1},
+	}
+	for _, tt := range tests {
+		if at := findLines(lines, tt.want, tt.expected, tt.min); at != tt.at {
+			t.Errorf("%s: findLines = %d, want %d", tt.name, at, tt.at)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// pipelineAnalysisMarker identifies the merge request note written by
+// post_pipeline_analysis_comment.
+const pipelineAnalysisMarker = "<!-- mcp:pipeline-analysis -->"
+
+const (
+	// maxAnalyzedJobs caps how many failed job traces one analysis downloads.
+	maxAnalyzedJobs = 20
+	// traceTailLines is how many trailing log lines are shown for a failed job
+	// without recognizable error lines.
+	traceTailLines = 5
+)
+
+// mergeRequestRefPattern matches the ref of a merge request pipeline.
+var mergeRequestRefPattern = regexp.MustCompile(`^refs/merge-requests/(\d+)/(head|merge|train)$`)
+
+// JobFailureAnalysis is the analysis of one failed job.
+type JobFailureAnalysis struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	Stage        string   `json:"stage"`
+	WebURL       string   `json:"web_url"`
+	AllowFailure bool     `json:"allow_failure,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+	// LogTail holds the last log lines when no error lines were recognized.
+	LogTail []string `json:"log_tail,omitempty"`
+}
+
+// PipelineAnalysisComment is the result of the post_pipeline_analysis_comment tool.
+type PipelineAnalysisComment struct {
+	PipelineID      int                  `json:"pipeline_id"`
+	PipelineStatus  string               `json:"pipeline_status"`
+	MergeRequestIID int                  `json:"merge_request_iid"`
+	Action          string               `json:"action"`
+	NoteID          int                  `json:"note_id,omitempty"`
+	Jobs            []JobFailureAnalysis `json:"jobs"`
+	Body            string               `json:"body,omitempty"`
+}
+
+// registerPostPipelineAnalysisComment registers the post_pipeline_analysis_comment tool.
+func registerPostPipelineAnalysisComment(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "post_pipeline_analysis_comment",
+			Description: "Analyze a pipeline's failed jobs (error lines extracted from each job log) and post the result as a single comment on the associated merge request. Re-running replaces the previous analysis comment instead of adding a new one; once the pipeline passes, an existing comment is updated to say so, and none is created. Use dry_run to preview the comment.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"pipeline_id": {
+						Type:        "integer",
+						Description: "The pipeline to analyze (optional, default: the merge request's latest pipeline)",
+					},
+					"merge_request_iid": {
+						Type:        "integer",
+						Description: "The merge request to comment on (optional, default: found from the pipeline's ref or commit)",
+					},
+					"max_errors_per_job": {
+						Type:        "integer",
+						Description: "Error lines shown per job (optional, default: 10)",
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(50),
+					},
+					"dry_run": {
+						Type:        "boolean",
+						Description: "Return the comment body without posting it (optional, default: false)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "post_pipeline_analysis_comment", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			pipelineID := GetInt(args, "pipeline_id", 0)
+			mrIID := GetInt(args, "merge_request_iid", 0)
+			if pipelineID == 0 && mrIID == 0 {
+				return ErrorResult("pipeline_id or merge_request_iid is required")
+			}
+			maxErrors := GetInt(args, "max_errors_per_job", 10)
+			encodedProjectID := url.PathEscape(projectID)
+
+			// Resolve the pipeline from the merge request if needed
+			if pipelineID == 0 {
+				var mr struct {
+					HeadPipeline *gitlab.Pipeline `json:"head_pipeline"`
+				}
+				if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s/merge_requests/%d", encodedProjectID, mrIID), &mr); err != nil {
+					return APIErrorResult("Failed to get merge request", err)
+				}
+				if mr.HeadPipeline == nil {
+					return ErrorResult(fmt.Sprintf("merge request !%d has no pipeline", mrIID))
+				}
+				pipelineID = mr.HeadPipeline.ID
+			}
+
+			var pipeline gitlab.Pipeline
+			if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s/pipelines/%d", encodedProjectID, pipelineID), &pipeline); err != nil {
+				return APIErrorResult("Failed to get pipeline", err)
+			}
+
+			if mrIID == 0 {
+				iid, err := findPipelineMergeRequest(ctx, c, encodedProjectID, pipeline)
+				if err != nil {
+					return APIErrorResult("Failed to find the pipeline's merge request", err)
+				}
+				if iid == 0 {
+					return ErrorResult(fmt.Sprintf("no open merge request found for pipeline %d (ref %s); pass merge_request_iid", pipeline.ID, pipeline.Ref))
+				}
+				mrIID = iid
+			}
+
+			var failed []failedJob
+			jobsEndpoint := fmt.Sprintf("/projects/%s/pipelines/%d/jobs?scope[]=failed&per_page=100", encodedProjectID, pipeline.ID)
+			if err := c.Client.Get(ctx, jobsEndpoint, &failed); err != nil {
+				return APIErrorResult("Failed to list failed jobs", err)
+			}
+
+			result := PipelineAnalysisComment{
+				PipelineID:      pipeline.ID,
+				PipelineStatus:  pipeline.Status,
+				MergeRequestIID: mrIID,
+				Jobs:            []JobFailureAnalysis{},
+			}
+			analyzed := failed
+			if len(analyzed) > maxAnalyzedJobs {
+				analyzed = analyzed[:maxAnalyzedJobs]
+			}
+			for i, job := range analyzed {
+				mcp.ReportProgress(ctx, float64(i), float64(len(analyzed)), fmt.Sprintf("Analyzing job %s", job.Name))
+				trace, err := c.Client.GetText(ctx, fmt.Sprintf("/projects/%s/jobs/%d/trace", encodedProjectID, job.ID))
+				if err != nil {
+					return APIErrorResult(fmt.Sprintf("Failed to get log of job %s", job.Name), err)
+				}
+				// The comment is visible to everyone with access to the MR
+				trace, _ = redactJobLog(ctx, c, trace, redactLogsByDefault(c))
+				result.Jobs = append(result.Jobs, analyzeJobTrace(job, trace, maxErrors))
+			}
+
+			notesEndpoint := fmt.Sprintf("/projects/%s/merge_requests/%d/notes", encodedProjectID, mrIID)
+			body := pipelineAnalysisMarkdown(pipeline, result.Jobs, len(failed))
+
+			if GetBool(args, "dry_run", false) {
+				result.Action = "dry_run"
+				result.Body = body
+				return JSONResult(result)
+			}
+
+			// A passing pipeline only replaces an earlier failure report
+			if len(failed) == 0 {
+				exists, err := hasMarkedNote(ctx, c, notesEndpoint, pipelineAnalysisMarker)
+				if err != nil {
+					return APIErrorResult("Failed to list merge request notes", err)
+				}
+				if !exists {
+					result.Action = "skipped"
+					return JSONResult(result)
+				}
+			}
+
+			note, action, err := upsertNoteByMarker(ctx, c, notesEndpoint, pipelineAnalysisMarker, body)
+			if err != nil {
+				return APIErrorResult("Failed to post pipeline analysis", err)
+			}
+			result.Action = action
+			result.NoteID = note.ID
+			return JSONResult(result)
+		},
+	)
+}
+
+// findPipelineMergeRequest returns the IID of the merge request a pipeline
+// belongs to: from a merge request pipeline ref, or else the open merge
+// request whose source branch is the pipeline's ref and contains its commit.
+// It returns 0 if there is none.
+func findPipelineMergeRequest(ctx context.Context, c *ToolContext, encodedProjectID string, pipeline gitlab.Pipeline) (int, error) {
+	if m := mergeRequestRefPattern.FindStringSubmatch(pipeline.Ref); m != nil {
+		return strconv.Atoi(m[1])
+	}
+
+	var mrs []gitlab.MergeRequest
+	endpoint := fmt.Sprintf("/projects/%s/repository/commits/%s/merge_requests", encodedProjectID, url.PathEscape(pipeline.SHA))
+	if err := c.Client.Get(ctx, endpoint, &mrs); err != nil {
+		return 0, err
+	}
+	for _, mr := range mrs {
+		if mr.State == "opened" && mr.SourceBranch == pipeline.Ref {
+			return mr.IID, nil
+		}
+	}
+	return 0, nil
+}
+
+// hasMarkedNote reports whether a note under notesEndpoint contains marker.
+func hasMarkedNote(ctx context.Context, c *ToolContext, notesEndpoint, marker string) (bool, error) {
+	notes, _, err := collectPages[gitlab.Note](ctx, c.Client, notesEndpoint, maxCollectedItems)
+	if err != nil {
+		return false, err
+	}
+	for _, note := range notes {
+		if !note.System && strings.Contains(note.Body, marker) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// analyzeJobTrace extracts up to maxErrors error lines from a job log, or its
+// last lines if none are recognized.
+func analyzeJobTrace(job failedJob, trace string, maxErrors int) JobFailureAnalysis {
+	analysis := JobFailureAnalysis{
+		ID:           job.ID,
+		Name:         job.Name,
+		Stage:        job.Stage,
+		WebURL:       job.WebURL,
+		AllowFailure: job.AllowFailure,
+	}
+	// Job logs carry ANSI colors and GitLab section markers
+	trace = ansiEscapePattern.ReplaceAllString(trace, "")
+
+	analysis.Errors = extractErrors(trace)
+	if len(analysis.Errors) > maxErrors {
+		analysis.Errors = analysis.Errors[:maxErrors]
+	}
+	if len(analysis.Errors) > 0 {
+		return analysis
+	}
+
+	lines := strings.Split(strings.TrimRight(trace, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0 && len(analysis.LogTail) < traceTailLines; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			analysis.LogTail = append([]string{line}, analysis.LogTail...)
+		}
+	}
+	return analysis
+}
+
+// ansiEscapePattern matches ANSI escape sequences and GitLab's collapsible
+// section markers in job logs.
+var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]|section_(start|end):\d+:[A-Za-z0-9_.-]+\r?`)
+
+// pipelineAnalysisMarkdown renders the analysis comment.
+func pipelineAnalysisMarkdown(pipeline gitlab.Pipeline, jobs []JobFailureAnalysis, failedCount int) string {
+	var sb strings.Builder
+	sb.WriteString(pipelineAnalysisMarker + "\n")
+
+	if failedCount == 0 {
+		fmt.Fprintf(&sb, "### ✅ Pipeline [#%d](%s) %s\n\nNo failed jobs.\n", pipeline.ID, pipeline.WebURL, pipeline.Status)
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "### ❌ Pipeline [#%d](%s) %s: %d failed job(s)\n", pipeline.ID, pipeline.WebURL, pipeline.Status, failedCount)
+	for _, job := range jobs {
+		fmt.Fprintf(&sb, "\n#### [%s](%s) (stage `%s`)", job.Name, job.WebURL, job.Stage)
+		if job.AllowFailure {
+			sb.WriteString(" - allowed to fail")
+		}
+		sb.WriteString("\n\n")
+
+		lines := job.Errors
+		if len(lines) == 0 {
+			sb.WriteString("No error lines recognized; last log lines:\n\n")
+			lines = job.LogTail
+		}
+		sb.WriteString("```\n")
+		for _, line := range lines {
+			sb.WriteString(strings.ReplaceAll(line, "```", "` ` `") + "\n")
+		}
+		sb.WriteString("```\n")
+	}
+	if failedCount > len(jobs) {
+		fmt.Fprintf(&sb, "\n_%d more failed job(s) not analyzed._\n", failedCount-len(jobs))
+	}
+	return sb.String()
+}
@@ -0,0 +1,248 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// maxBadgeBytes bounds badge downloads.
+const maxBadgeBytes = 64 << 10
+
+// svgTextPattern matches the text elements of a rendered badge.
+var svgTextPattern = regexp.MustCompile(`<text[^>]*>([^<]*)</text>`)
+
+// pipelineStatusEmoji maps pipeline and job statuses to the emoji shown in
+// status markdown.
+var pipelineStatusEmoji = map[string]string{
+	"success":              "✅",
+	"failed":               "❌",
+	"canceled":             "🚫",
+	"skipped":              "⏭️",
+	"running":              "🔄",
+	"pending":              "⏳",
+	"created":              "⏳",
+	"preparing":            "⏳",
+	"waiting_for_resource": "⏳",
+	"scheduled":            "🕒",
+	"manual":               "✋",
+}
+
+// failedJob is a job as listed by the pipeline jobs API, with the
+// allow_failure flag the shared Job type lacks.
+type failedJob struct {
+	gitlab.Job
+	AllowFailure bool `json:"allow_failure"`
+}
+
+// PipelineBadge is the result of the get_pipeline_badge tool.
+type PipelineBadge struct {
+	Project    string           `json:"project"`
+	Ref        string           `json:"ref"`
+	Kind       string           `json:"kind"`
+	Status     string           `json:"status"`
+	BadgeURL   string           `json:"badge_url"`
+	Pipeline   *gitlab.Pipeline `json:"pipeline,omitempty"`
+	FailedJobs []string         `json:"failed_jobs,omitempty"`
+	// Markdown is a compact status block to paste into comments.
+	Markdown string `json:"markdown"`
+	// Note explains why no image is attached, if none is.
+	Note string `json:"note,omitempty"`
+}
+
+// registerGetPipelineBadge registers the get_pipeline_badge tool.
+func registerGetPipelineBadge(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_pipeline_badge",
+			Description: "Get a branch's pipeline status or coverage badge: the badge URL, the rendered SVG image and a compact markdown status block (pipeline link, status emoji, failed jobs) to paste into MR comments. Pass pipeline_id to describe a specific pipeline instead of the latest one. If the badge image cannot be fetched (e.g., a private project whose badges require a browser session), the status is read from the pipeline instead.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "The branch name (optional, default: the pipeline's ref, or the project's default branch)",
+					},
+					"pipeline_id": {
+						Type:        "integer",
+						Description: "The pipeline to summarize (optional, default: the latest pipeline of ref)",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "The badge: pipeline or coverage (optional, default: pipeline)",
+						Enum:        []string{"pipeline", "coverage"},
+					},
+					"include_image": {
+						Type:        "boolean",
+						Description: "Attach the rendered badge as an image (optional, default: true)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_pipeline_badge", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			kind := GetString(args, "kind", "pipeline")
+			if kind != "pipeline" && kind != "coverage" {
+				return ErrorResult("kind must be one of: pipeline, coverage")
+			}
+			encodedProjectID := url.PathEscape(projectID)
+
+			var project gitlab.Project
+			if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s", encodedProjectID), &project); err != nil {
+				return APIErrorResult("Failed to get project", err)
+			}
+
+			// The pipeline behind the badge, with its coverage for coverage badges
+			var pipeline struct {
+				gitlab.Pipeline
+				Coverage *string `json:"coverage"`
+			}
+			ref := GetString(args, "ref", "")
+			pipelineID := GetInt(args, "pipeline_id", 0)
+			hasPipeline := true
+			if pipelineID > 0 {
+				endpoint := fmt.Sprintf("/projects/%s/pipelines/%d", encodedProjectID, pipelineID)
+				if err := c.Client.Get(ctx, endpoint, &pipeline); err != nil {
+					return APIErrorResult("Failed to get pipeline", err)
+				}
+				if ref == "" {
+					ref = pipeline.Ref
+				}
+			} else {
+				if ref == "" {
+					ref = project.DefaultBranch
+				}
+				endpoint := fmt.Sprintf("/projects/%s/pipelines/latest?ref=%s", encodedProjectID, url.QueryEscape(ref))
+				if err := c.Client.Get(ctx, endpoint, &pipeline); err != nil {
+					if !gitlab.IsNotFound(err) {
+						return APIErrorResult("Failed to get latest pipeline", err)
+					}
+					hasPipeline = false
+				}
+			}
+
+			badgePath := fmt.Sprintf("/%s/badges/%s/%s.svg", project.PathWithNamespace, escapePathSegments(ref), kind)
+			badge := PipelineBadge{
+				Project:  project.PathWithNamespace,
+				Ref:      ref,
+				Kind:     kind,
+				Status:   "unknown",
+				BadgeURL: c.Client.WebURL() + badgePath,
+			}
+
+			var failed []failedJob
+			if hasPipeline {
+				badge.Pipeline = &pipeline.Pipeline
+				badge.Status = pipeline.Status
+				if kind == "coverage" {
+					badge.Status = "unknown"
+					if pipeline.Coverage != nil {
+						badge.Status = *pipeline.Coverage + "%"
+					}
+				}
+
+				endpoint := fmt.Sprintf("/projects/%s/pipelines/%d/jobs?scope[]=failed&per_page=100", encodedProjectID, pipeline.ID)
+				if err := c.Client.Get(ctx, endpoint, &failed); err != nil {
+					return APIErrorResult("Failed to list failed jobs", err)
+				}
+				for _, job := range failed {
+					badge.FailedJobs = append(badge.FailedJobs, job.Name)
+				}
+			}
+			badge.Markdown = pipelineStatusMarkdown(badge, failed)
+
+			if !GetBool(args, "include_image", true) {
+				return JSONResult(badge)
+			}
+
+			var buf bytes.Buffer
+			info, err := c.Client.GetWebBinary(ctx, badgePath, &buf, maxBadgeBytes)
+			if err == nil && info.ContentType == "image/svg+xml" {
+				// The badge shows the status GitLab computes for the ref
+				if pipelineID == 0 {
+					if texts := svgTextPattern.FindAllStringSubmatch(buf.String(), -1); len(texts) > 0 {
+						badge.Status = strings.TrimSpace(texts[len(texts)-1][1])
+					}
+				}
+				return ImageResult(badge, buf.Bytes(), info.ContentType)
+			}
+			if err != nil {
+				badge.Note = fmt.Sprintf("the badge image could not be fetched: %v", err)
+			} else {
+				badge.Note = "the badge endpoint did not return an SVG image"
+			}
+			return JSONResult(badge)
+		},
+	)
+}
+
+// pipelineStatusMarkdown renders the compact status block of get_pipeline_badge:
+// the badge, a linked pipeline line with a status emoji, and the failed jobs.
+func pipelineStatusMarkdown(badge PipelineBadge, failed []failedJob) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "![%s status](%s)\n\n", badge.Kind, badge.BadgeURL)
+
+	pipeline := badge.Pipeline
+	if pipeline == nil {
+		fmt.Fprintf(&sb, "No pipeline for `%s`.\n", badge.Ref)
+		return sb.String()
+	}
+
+	emoji := pipelineStatusEmoji[pipeline.Status]
+	if emoji == "" {
+		emoji = "❔"
+	}
+	fmt.Fprintf(&sb, "%s **Pipeline [#%d](%s)** %s for `%s`", emoji, pipeline.ID, pipeline.WebURL, pipeline.Status, pipeline.Ref)
+	if len(pipeline.SHA) >= 8 {
+		fmt.Fprintf(&sb, " at `%s`", pipeline.SHA[:8])
+	}
+	if badge.Kind == "coverage" {
+		fmt.Fprintf(&sb, " (coverage: %s)", badge.Status)
+	}
+	sb.WriteString("\n")
+
+	if len(failed) > 0 {
+		sb.WriteString("\nFailed jobs:\n")
+		for _, job := range failed {
+			fmt.Fprintf(&sb, "- [%s](%s) (stage `%s`)", job.Name, job.WebURL, job.Stage)
+			if job.AllowFailure {
+				sb.WriteString(" - allowed to fail")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// escapePathSegments escapes each "/"-separated segment of a ref for use in
+// a URL path, keeping the separators.
+func escapePathSegments(ref string) string {
+	segments := strings.Split(ref, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -1,11 +1,14 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
 )
@@ -86,6 +89,10 @@ type JobLogResult struct {
 	Errors             []string            `json:"errors,omitempty"`
 	TestResults        []string            `json:"test_results,omitempty"`
 	MatchedLines       []string            `json:"matched_lines,omitempty"`
+	Extracted          []string            `json:"extracted,omitempty"` // custom extractor matches
+
+	// Number of secrets masked before filtering and extraction
+	Redacted int `json:"redacted,omitempty"`
 }
 
 // filterLogLines applies search/filter parameters to log content
@@ -314,6 +321,58 @@ func extractTestResults(log string) []string {
 	return results
 }
 
+// extractCustom returns the unique matches of a custom extractor pattern. When
+// the pattern has a capture group, the first group is returned.
+func extractCustom(log, pattern string) ([]string, error) {
+	re, err := regexp.Compile("(?m)" + pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var results []string
+	for _, match := range re.FindAllStringSubmatch(log, -1) {
+		value := match[0]
+		if len(match) > 1 {
+			value = match[1]
+		}
+		trimmed := strings.TrimSpace(value)
+		if trimmed != "" && !seen[trimmed] {
+			seen[trimmed] = true
+			results = append(results, trimmed)
+		}
+	}
+
+	return results, nil
+}
+
+// builtinExtractors lists the extract values handled by get_pipeline_job_output itself.
+var builtinExtractors = []string{
+	"terraform_outputs",
+	"terraform_resources",
+	"terraform_all",
+	"aws_assets",
+	"errors",
+	"test_results",
+}
+
+// customExtractors returns the config file extractors that do not shadow a
+// builtin one, sorted by name.
+func customExtractors(extractors map[string]config.Extractor) []string {
+	var names []string
+	for name := range extractors {
+		builtin := false
+		for _, b := range builtinExtractors {
+			builtin = builtin || b == name
+		}
+		if !builtin {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // formatJobLogResultAsText formats a JobLogResult as compact, LLM-friendly text
 func formatJobLogResultAsText(result *JobLogResult) string {
 	var sb strings.Builder
@@ -382,6 +441,13 @@ func formatJobLogResultAsText(result *JobLogResult) string {
 		}
 	}
 
+	if len(result.Extracted) > 0 {
+		sb.WriteString("\n=== Extracted ===\n")
+		for _, value := range result.Extracted {
+			sb.WriteString(fmt.Sprintf("%s\n", value))
+		}
+	}
+
 	if len(result.MatchedLines) > 0 {
 		sb.WriteString("\n=== Matched Lines ===\n")
 		for _, line := range result.MatchedLines {
@@ -439,16 +505,17 @@ func registerListPipelines(server *mcp.Server) {
 				},
 				Required: []string{"project_id"},
 			},
+			OutputSchema: pagedOutputSchema(pipelineOutputProperty),
 			Annotations: &mcp.ToolAnnotations{
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("list_pipelines", args)
+			c.Logger.ToolCall(ctx, "list_pipelines", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -481,17 +548,12 @@ func registerListPipelines(server *mcp.Server) {
 			}
 
 			var pipelines []gitlab.Pipeline
-			pagination, err := c.Client.GetWithPagination(endpoint, &pipelines)
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &pipelines)
 			if err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list pipelines: %v", err))
+				return APIErrorResult("Failed to list pipelines", err)
 			}
 
-			result := map[string]interface{}{
-				"pipelines":  pipelines,
-				"pagination": pagination,
-			}
-
-			return JSONResult(result)
+			return PagedJSONResult(pipelines, pagination)
 		},
 	)
 }
@@ -516,16 +578,17 @@ func registerGetPipeline(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "pipeline_id"},
 			},
+			OutputSchema: objectOutputSchema(pipelineOutputProperty, "id", "status"),
 			Annotations: &mcp.ToolAnnotations{
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_pipeline", args)
+			c.Logger.ToolCall(ctx, "get_pipeline", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -539,8 +602,8 @@ func registerGetPipeline(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/pipelines/%d", url.PathEscape(projectID), pipelineID)
 
 			var pipeline gitlab.Pipeline
-			if err := c.Client.Get(endpoint, &pipeline); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get pipeline: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &pipeline); err != nil {
+				return APIErrorResult("Failed to get pipeline", err)
 			}
 
 			return JSONResult(pipeline)
@@ -585,13 +648,14 @@ func registerCreatePipeline(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "ref"},
 			},
+			OutputSchema: objectOutputSchema(pipelineOutputProperty, "id", "status"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("create_pipeline", args)
+			c.Logger.ToolCall(ctx, "create_pipeline", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -616,8 +680,8 @@ func registerCreatePipeline(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/pipeline", url.PathEscape(projectID))
 
 			var pipeline gitlab.Pipeline
-			if err := c.Client.Post(endpoint, body, &pipeline); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create pipeline: %v", err))
+			if err := c.Client.Post(ctx, endpoint, body, &pipeline); err != nil {
+				return APIErrorResult("Failed to create pipeline", err)
 			}
 
 			return JSONResult(pipeline)
@@ -645,13 +709,14 @@ func registerRetryPipeline(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "pipeline_id"},
 			},
+			OutputSchema: objectOutputSchema(pipelineOutputProperty, "id", "status"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("retry_pipeline", args)
+			c.Logger.ToolCall(ctx, "retry_pipeline", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -665,8 +730,8 @@ func registerRetryPipeline(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/pipelines/%d/retry", url.PathEscape(projectID), pipelineID)
 
 			var pipeline gitlab.Pipeline
-			if err := c.Client.Post(endpoint, nil, &pipeline); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to retry pipeline: %v", err))
+			if err := c.Client.Post(ctx, endpoint, nil, &pipeline); err != nil {
+				return APIErrorResult("Failed to retry pipeline", err)
 			}
 
 			return JSONResult(pipeline)
@@ -694,13 +759,14 @@ func registerCancelPipeline(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "pipeline_id"},
 			},
+			OutputSchema: objectOutputSchema(pipelineOutputProperty, "id", "status"),
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("cancel_pipeline", args)
+			c.Logger.ToolCall(ctx, "cancel_pipeline", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -714,8 +780,8 @@ func registerCancelPipeline(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/pipelines/%d/cancel", url.PathEscape(projectID), pipelineID)
 
 			var pipeline gitlab.Pipeline
-			if err := c.Client.Post(endpoint, nil, &pipeline); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to cancel pipeline: %v", err))
+			if err := c.Client.Post(ctx, endpoint, nil, &pipeline); err != nil {
+				return APIErrorResult("Failed to cancel pipeline", err)
 			}
 
 			return JSONResult(pipeline)
@@ -764,16 +830,17 @@ func registerListPipelineJobs(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "pipeline_id"},
 			},
+			OutputSchema: pagedOutputSchema(jobOutputProperty),
 			Annotations: &mcp.ToolAnnotations{
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("list_pipeline_jobs", args)
+			c.Logger.ToolCall(ctx, "list_pipeline_jobs", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -801,17 +868,12 @@ func registerListPipelineJobs(server *mcp.Server) {
 			}
 
 			var jobs []gitlab.Job
-			pagination, err := c.Client.GetWithPagination(endpoint, &jobs)
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &jobs)
 			if err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list pipeline jobs: %v", err))
-			}
-
-			result := map[string]interface{}{
-				"jobs":       jobs,
-				"pagination": pagination,
+				return APIErrorResult("Failed to list pipeline jobs", err)
 			}
 
-			return JSONResult(result)
+			return PagedJSONResult(jobs, pagination)
 		},
 	)
 }
@@ -861,12 +923,12 @@ func registerListPipelineTriggerJobs(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("list_pipeline_trigger_jobs", args)
+			c.Logger.ToolCall(ctx, "list_pipeline_trigger_jobs", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -894,17 +956,12 @@ func registerListPipelineTriggerJobs(server *mcp.Server) {
 			}
 
 			var bridges []Bridge
-			pagination, err := c.Client.GetWithPagination(endpoint, &bridges)
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &bridges)
 			if err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list pipeline trigger jobs: %v", err))
-			}
-
-			result := map[string]interface{}{
-				"bridges":    bridges,
-				"pagination": pagination,
+				return APIErrorResult("Failed to list pipeline trigger jobs", err)
 			}
 
-			return JSONResult(result)
+			return PagedJSONResult(bridges, pagination)
 		},
 	)
 }
@@ -929,16 +986,17 @@ func registerGetPipelineJob(server *mcp.Server) {
 				},
 				Required: []string{"project_id", "job_id"},
 			},
+			OutputSchema: objectOutputSchema(jobOutputProperty, "id", "status"),
 			Annotations: &mcp.ToolAnnotations{
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_pipeline_job", args)
+			c.Logger.ToolCall(ctx, "get_pipeline_job", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -952,8 +1010,8 @@ func registerGetPipelineJob(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/jobs/%d", url.PathEscape(projectID), jobID)
 
 			var job gitlab.Job
-			if err := c.Client.Get(endpoint, &job); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get job: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &job); err != nil {
+				return APIErrorResult("Failed to get job", err)
 			}
 
 			return JSONResult(job)
@@ -962,13 +1020,26 @@ func registerGetPipelineJob(server *mcp.Server) {
 }
 
 // registerGetPipelineJobOutput registers the get_pipeline_job_output tool.
-func registerGetPipelineJobOutput(server *mcp.Server) {
+func registerGetPipelineJobOutput(server *mcp.Server, extractors map[string]config.Extractor) {
+	// Custom extractors from the config file are offered alongside the builtin ones
+	custom := customExtractors(extractors)
+	extractEnum := append(append([]string{}, builtinExtractors...), custom...)
+	customDescription := ""
+	if len(custom) > 0 {
+		var sb strings.Builder
+		sb.WriteString("\n\nCUSTOM EXTRACTORS (configured for this server):")
+		for _, name := range custom {
+			sb.WriteString(fmt.Sprintf("\n- %q: %s", name, extractors[name].Description))
+		}
+		customDescription = sb.String()
+	}
+
 	server.RegisterTool(
 		mcp.Tool{
 			Name: "get_pipeline_job_output",
 			Description: `Get the log (trace) output of a specific job with optional filtering and extraction.
 
-BASIC USAGE: Returns the full job log as plain text when no filters are specified.
+BASIC USAGE: Returns the full job log as plain text when no filters are specified. Secrets in the log are masked by default (see redact).
 
 SEARCH & FILTER OPTIONS:
 - search: Regex pattern to filter lines (case-insensitive). Use for custom searches like "bucket|lambda" or "deployment.*failed"
@@ -991,7 +1062,7 @@ COMMON USE CASES:
 3. Check test results: use extract="test_results"
 4. See deployment outputs: use extract="terraform_outputs"
 5. Get last 100 lines of long job: use tail=100
-6. Find specific resource: use search="aws_lambda|my-function-name"`,
+6. Find specific resource: use search="aws_lambda|my-function-name"` + customDescription,
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -1026,20 +1097,17 @@ COMMON USE CASES:
 					"extract": {
 						Type:        "string",
 						Description: "Use a predefined extractor to parse structured data from logs",
-						Enum: []string{
-							"terraform_outputs",
-							"terraform_resources",
-							"terraform_all",
-							"aws_assets",
-							"errors",
-							"test_results",
-						},
+						Enum:        extractEnum,
 					},
 					"format": {
 						Type:        "string",
 						Description: "Output format: 'json' for structured data (default), 'text' for compact LLM-friendly format with less tokens",
 						Enum:        []string{"json", "text"},
 					},
+					"redact": {
+						Type:        "boolean",
+						Description: "Mask secrets (cloud keys, access tokens, JWTs, private keys, URL passwords) as [<KIND>-REDACTED] before returning the log (default: on unless the server sets GITLAB_REDACT_LOGS=false)",
+					},
 				},
 				Required: []string{"project_id", "job_id"},
 			},
@@ -1047,12 +1115,12 @@ COMMON USE CASES:
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_pipeline_job_output", args)
+			c.Logger.ToolCall(ctx, "get_pipeline_job_output", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -1074,15 +1142,19 @@ COMMON USE CASES:
 
 			endpoint := fmt.Sprintf("/projects/%s/jobs/%d/trace", url.PathEscape(projectID), jobID)
 
-			trace, err := c.Client.GetText(endpoint)
+			mcp.ReportProgress(ctx, 0, 2, "Downloading job trace")
+			trace, err := c.Client.GetText(ctx, endpoint)
 			if err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get job output: %v", err))
+				return APIErrorResult("Failed to get job output", err)
 			}
+			mcp.ReportProgress(ctx, 1, 2, fmt.Sprintf("Processing %d bytes of job trace", len(trace)))
+			trace, redacted := redactJobLog(ctx, c, trace, GetBool(args, "redact", redactLogsByDefault(c)))
 
 			// If using an extractor, return structured data
 			if extract != "" {
 				result := JobLogResult{
 					TotalLines: len(strings.Split(trace, "\n")),
+					Redacted:   redacted,
 				}
 
 				switch extract {
@@ -1116,7 +1188,19 @@ COMMON USE CASES:
 					result.ReturnedLines = len(result.TestResults)
 
 				default:
-					return ErrorResult(fmt.Sprintf("Unknown extract type: %s. Valid options: terraform_outputs, terraform_resources, terraform_all, aws_assets, errors, test_results", extract))
+					var pattern string
+					if c.Config != nil {
+						pattern = c.Config.Extractors[extract].Pattern
+					}
+					if pattern == "" {
+						return ErrorResult(fmt.Sprintf("Unknown extract type: %s. Valid options: %s", extract, strings.Join(extractEnum, ", ")))
+					}
+					extracted, err := extractCustom(trace, pattern)
+					if err != nil {
+						return ErrorResult(fmt.Sprintf("Invalid pattern for extractor %s: %v", extract, err))
+					}
+					result.Extracted = extracted
+					result.ReturnedLines = len(result.Extracted)
 				}
 
 				// Return in requested format
@@ -1133,6 +1217,7 @@ COMMON USE CASES:
 					TotalLines:    totalLines,
 					ReturnedLines: len(lines),
 					MatchedLines:  lines,
+					Redacted:      redacted,
 				}
 				// Return in requested format
 				if format == "text" {
@@ -1185,12 +1270,12 @@ func registerPlayPipelineJob(server *mcp.Server) {
 				Required: []string{"project_id", "job_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("play_pipeline_job", args)
+			c.Logger.ToolCall(ctx, "play_pipeline_job", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -1214,8 +1299,8 @@ func registerPlayPipelineJob(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/jobs/%d/play", url.PathEscape(projectID), jobID)
 
 			var job gitlab.Job
-			if err := c.Client.Post(endpoint, body, &job); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to play job: %v", err))
+			if err := c.Client.Post(ctx, endpoint, body, &job); err != nil {
+				return APIErrorResult("Failed to play job", err)
 			}
 
 			return JSONResult(job)
@@ -1244,12 +1329,12 @@ func registerRetryPipelineJob(server *mcp.Server) {
 				Required: []string{"project_id", "job_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("retry_pipeline_job", args)
+			c.Logger.ToolCall(ctx, "retry_pipeline_job", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -1263,8 +1348,8 @@ func registerRetryPipelineJob(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/jobs/%d/retry", url.PathEscape(projectID), jobID)
 
 			var job gitlab.Job
-			if err := c.Client.Post(endpoint, nil, &job); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to retry job: %v", err))
+			if err := c.Client.Post(ctx, endpoint, nil, &job); err != nil {
+				return APIErrorResult("Failed to retry job", err)
 			}
 
 			return JSONResult(job)
@@ -1293,12 +1378,12 @@ func registerCancelPipelineJob(server *mcp.Server) {
 				Required: []string{"project_id", "job_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("cancel_pipeline_job", args)
+			c.Logger.ToolCall(ctx, "cancel_pipeline_job", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -1312,8 +1397,8 @@ func registerCancelPipelineJob(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/jobs/%d/cancel", url.PathEscape(projectID), jobID)
 
 			var job gitlab.Job
-			if err := c.Client.Post(endpoint, nil, &job); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to cancel job: %v", err))
+			if err := c.Client.Post(ctx, endpoint, nil, &job); err != nil {
+				return APIErrorResult("Failed to cancel job", err)
 			}
 
 			return JSONResult(job)
@@ -1356,12 +1441,12 @@ Combine with get_pipeline_job_output to extract specific data:
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_latest_release_pipeline", args)
+			c.Logger.ToolCall(ctx, "get_latest_release_pipeline", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -1369,16 +1454,21 @@ Combine with get_pipeline_job_output to extract specific data:
 			}
 
 			includeJobs := GetBool(args, "include_jobs", true)
+			totalSteps := 2.0
+			if includeJobs {
+				totalSteps = 3
+			}
 
 			// Step 1: Get the latest release
+			mcp.ReportProgress(ctx, 0, totalSteps, "Fetching latest release")
 			releasesEndpoint := fmt.Sprintf("/projects/%s/releases?per_page=1", url.PathEscape(projectID))
 			var releases []struct {
 				TagName   string `json:"tag_name"`
 				Name      string `json:"name"`
 				CreatedAt string `json:"created_at"`
 			}
-			if err := c.Client.Get(releasesEndpoint, &releases); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get releases: %v", err))
+			if err := c.Client.Get(ctx, releasesEndpoint, &releases); err != nil {
+				return APIErrorResult("Failed to get releases", err)
 			}
 
 			if len(releases) == 0 {
@@ -1388,13 +1478,14 @@ Combine with get_pipeline_job_output to extract specific data:
 			latestRelease := releases[0]
 
 			// Step 2: Get pipelines for the tag
+			mcp.ReportProgress(ctx, 1, totalSteps, fmt.Sprintf("Finding pipeline for tag %s", latestRelease.TagName))
 			pipelinesEndpoint := fmt.Sprintf("/projects/%s/pipelines?ref=%s&per_page=1",
 				url.PathEscape(projectID),
 				url.PathEscape(latestRelease.TagName))
 
 			var pipelines []gitlab.Pipeline
-			if err := c.Client.Get(pipelinesEndpoint, &pipelines); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get pipelines for tag %s: %v", latestRelease.TagName, err))
+			if err := c.Client.Get(ctx, pipelinesEndpoint, &pipelines); err != nil {
+				return APIErrorResult(fmt.Sprintf("Failed to get pipelines for tag %s", latestRelease.TagName), err)
 			}
 
 			if len(pipelines) == 0 {
@@ -1415,13 +1506,14 @@ Combine with get_pipeline_job_output to extract specific data:
 
 			// Step 3: Optionally get jobs
 			if includeJobs {
+				mcp.ReportProgress(ctx, 2, totalSteps, fmt.Sprintf("Listing jobs for pipeline %d", pipeline.ID))
 				jobsEndpoint := fmt.Sprintf("/projects/%s/pipelines/%d/jobs",
 					url.PathEscape(projectID),
 					pipeline.ID)
 
 				var jobs []gitlab.Job
-				if err := c.Client.Get(jobsEndpoint, &jobs); err != nil {
-					c.Logger.Warn("Failed to get jobs for pipeline %d: %v", pipeline.ID, err)
+				if err := c.Client.Get(ctx, jobsEndpoint, &jobs); err != nil {
+					c.Logger.WarnContext(ctx, "Failed to get jobs for pipeline %d: %v", pipeline.ID, err)
 				} else {
 					result["jobs"] = jobs
 				}
@@ -1433,9 +1525,9 @@ Combine with get_pipeline_job_output to extract specific data:
 }
 
 // initPipelineTools registers all pipeline-related tools with the MCP server.
-// This function is called by RegisterPipelineTools in registry.go when the
-// USE_PIPELINE feature flag is enabled.
-func initPipelineTools(server *mcp.Server) {
+// This function is called by RegisterPipelineTools and Register in
+// registry.go; extractors are the custom log extractors to offer.
+func initPipelineTools(server *mcp.Server, extractors map[string]config.Extractor) {
 	registerListPipelines(server)
 	registerGetPipeline(server)
 	registerCreatePipeline(server)
@@ -1444,9 +1536,17 @@ func initPipelineTools(server *mcp.Server) {
 	registerListPipelineJobs(server)
 	registerListPipelineTriggerJobs(server)
 	registerGetPipelineJob(server)
-	registerGetPipelineJobOutput(server)
+	registerGetPipelineJobOutput(server, extractors)
 	registerPlayPipelineJob(server)
 	registerRetryPipelineJob(server)
 	registerCancelPipelineJob(server)
 	registerGetLatestReleasePipeline(server)
+	registerGetPipelineBadge(server)
+	registerPostPipelineAnalysisComment(server)
+	registerListProjectJobs(server)
+	registerResolveCIVariables(server)
+	registerGetMergedCIConfig(server)
+	registerSimulatePipeline(server)
+	registerGetCISettings(server)
+	registerUpdateCISettings(server)
 }
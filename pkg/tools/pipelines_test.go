@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/gitlabtest"
+)
+
+func TestPipelineListEnvelope(t *testing.T) {
+	tests := []struct {
+		tool     string
+		args     map[string]interface{}
+		endpoint string
+		body     string
+	}{
+		{"list_pipelines", map[string]interface{}{}, "/projects/acme%2Fapi/pipelines", `[{"id": 10, "status": "success"}, {"id": 11, "status": "failed"}]`},
+		{"list_pipeline_jobs", map[string]interface{}{"pipeline_id": 10}, "/projects/acme%2Fapi/pipelines/10/jobs", `[{"id": 1, "name": "build"}, {"id": 2, "name": "test"}]`},
+		{"list_pipeline_trigger_jobs", map[string]interface{}{"pipeline_id": 10}, "/projects/acme%2Fapi/pipelines/10/bridges", `[{"id": 3, "name": "deploy"}, {"id": 4, "name": "docs"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			tc, client := newTestContext(t)
+			tc.Config.UsePipeline = true
+			client.AddRoute(gitlabtest.Route{
+				Method:     "GET",
+				Endpoint:   tt.endpoint,
+				Body:       json.RawMessage(tt.body),
+				Pagination: &gitlab.PaginationInfo{Page: 1, PerPage: 2, Total: 3, TotalPages: 2, NextPage: 2},
+			})
+			tt.args["project_id"] = "acme/api"
+			items, pagination := listItems(t, callTool(t, tc, tt.tool, tt.args))
+			if len(items) != 2 || pagination == nil || pagination.NextPage != 2 || pagination.Total != 3 {
+				t.Errorf("%d items, pagination %+v", len(items), pagination)
+			}
+		})
+	}
+}
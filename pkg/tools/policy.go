@@ -0,0 +1,251 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// policyTimeout bounds a single call to an HTTP policy endpoint.
+const policyTimeout = 5 * time.Second
+
+// PolicyRequest describes a tool call about to be executed.
+type PolicyRequest struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Principal string                 `json:"principal,omitempty"` // HTTP auth principal; empty in stdio mode
+	ReadOnly  bool                   `json:"read_only"`
+}
+
+// PolicyDecision is a policy's verdict on a tool call. When Allow is true and
+// Arguments is not nil, the call proceeds with Arguments instead of the
+// original arguments.
+type PolicyDecision struct {
+	Allow     bool                   `json:"allow"`
+	Reason    string                 `json:"reason,omitempty"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// Policy decides whether a tool call may run. An error denies the call.
+type Policy interface {
+	Evaluate(ctx context.Context, request PolicyRequest) (PolicyDecision, error)
+}
+
+// PolicyFunc adapts a function to the Policy interface.
+type PolicyFunc func(ctx context.Context, request PolicyRequest) (PolicyDecision, error)
+
+// Evaluate calls f.
+func (f PolicyFunc) Evaluate(ctx context.Context, request PolicyRequest) (PolicyDecision, error) {
+	return f(ctx, request)
+}
+
+// namedPolicy is a policy together with the name used in denial messages.
+type namedPolicy struct {
+	name   string
+	policy Policy
+}
+
+var (
+	policyMu sync.RWMutex
+	// registeredPolicies are the compiled-in policies, by name
+	registeredPolicies = map[string]Policy{}
+)
+
+// RegisterPolicy makes a compiled-in policy available under name, so that it
+// can be enabled with MCP_POLICIES. It is meant to be called from an init
+// function of a file added to the build; it panics if name is taken.
+func RegisterPolicy(name string, policy Policy) {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	if policy == nil {
+		panic("tools: RegisterPolicy policy is nil")
+	}
+	if _, exists := registeredPolicies[name]; exists {
+		panic("tools: RegisterPolicy called twice for " + name)
+	}
+	registeredPolicies[name] = policy
+}
+
+// RegisteredPolicies returns the names of the compiled-in policies.
+func RegisteredPolicies() []string {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	names := make([]string, 0, len(registeredPolicies))
+	for name := range registeredPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConfigurePolicies activates the policies named in c.Config.Policies,
+// followed by the HTTP policy at c.Config.PolicyURL if set, for the tool
+// calls of c. It fails, leaving the active policies unchanged, if a name is
+// not registered. It must be called before c is bound to a server.
+func (c *ToolContext) ConfigurePolicies() error {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+
+	cfg := c.Config
+	var policies []namedPolicy
+	for _, name := range cfg.Policies {
+		policy, ok := registeredPolicies[name]
+		if !ok {
+			return fmt.Errorf("unknown policy %q in MCP_POLICIES", name)
+		}
+		policies = append(policies, namedPolicy{name: name, policy: policy})
+	}
+	if cfg.PolicyURL != "" {
+		policies = append(policies, namedPolicy{name: "http", policy: NewHTTPPolicy(cfg.PolicyURL, nil)})
+	}
+	c.policies = policies
+	return nil
+}
+
+// PolicyMiddleware evaluates the policies of the call's ToolContext before
+// every tool call. The first policy that denies the call (or fails) stops it
+// with an error result; arguments modified by a policy are passed on to the
+// next policy and the tool. Modified arguments are validated against the
+// tool's input schema again and recorded in the audit log.
+func PolicyMiddleware(tool mcp.Tool, handler mcp.ToolHandler) (mcp.Tool, mcp.ToolHandler) {
+	readOnly := isReadOnlyTool(tool)
+
+	wrapped := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		var policies []namedPolicy
+		if c := FromContext(ctx); c != nil {
+			policies = c.policies
+		}
+		if len(policies) > 0 {
+			principal, _ := auth.PrincipalFromContext(ctx)
+			for _, p := range policies {
+				decision, err := p.policy.Evaluate(ctx, PolicyRequest{
+					Tool:      tool.Name,
+					Arguments: args,
+					Principal: principal,
+					ReadOnly:  readOnly,
+				})
+				if err != nil {
+					if c := FromContext(ctx); c != nil && c.Logger != nil {
+						c.Logger.ErrorContext(ctx, "Policy %s failed for %s: %v", p.name, tool.Name, err)
+					}
+					return ErrorResult(fmt.Sprintf("Denied by policy %s: policy check failed", p.name))
+				}
+				if !decision.Allow {
+					reason := decision.Reason
+					if reason == "" {
+						reason = "not allowed"
+					}
+					if c := FromContext(ctx); c != nil && c.Logger != nil {
+						c.Logger.InfoContext(ctx, "Policy %s denied %s: %s", p.name, tool.Name, reason)
+					}
+					return ErrorResult(fmt.Sprintf("Denied by policy %s: %s", p.name, reason))
+				}
+				if decision.Arguments != nil {
+					rewritten, err := decodedArguments(decision.Arguments)
+					if err != nil {
+						if c := FromContext(ctx); c != nil && c.Logger != nil {
+							c.Logger.ErrorContext(ctx, "Policy %s rewrote arguments of %s: %v", p.name, tool.Name, err)
+						}
+						return ErrorResult(fmt.Sprintf("Denied by policy %s: policy check failed", p.name))
+					}
+					if violations := mcp.ValidateArguments(tool.InputSchema, rewritten); len(violations) > 0 {
+						invalid := &mcp.InvalidArgumentsError{Tool: tool.Name, Violations: violations}
+						if c := FromContext(ctx); c != nil && c.Logger != nil {
+							c.Logger.ErrorContext(ctx, "Policy %s rewrote arguments of %s: %v", p.name, tool.Name, invalid)
+						}
+						return ErrorResult(fmt.Sprintf("Denied by policy %s: %v", p.name, invalid))
+					}
+					args = rewritten
+					recordExecutedArguments(ctx, args)
+				}
+			}
+		}
+		return handler(ctx, args)
+	}
+	return tool, wrapped
+}
+
+// decodedArguments returns arguments set by a policy as a client would have
+// sent them: Go values of a compiled-in policy (int, []string, ...) become
+// their decoded JSON form, which the schema validation and tools expect.
+func decodedArguments(args map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode arguments: %w", err)
+	}
+	return decoded, nil
+}
+
+// HTTPPolicy asks an HTTP endpoint for a decision, in the format of an Open
+// Policy Agent data API query: it POSTs {"input": <PolicyRequest>} and expects
+// {"result": <PolicyDecision>} or {"result": true|false}. A missing result
+// (an undefined OPA rule) denies the call.
+type HTTPPolicy struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPPolicy returns a policy querying url. A nil client uses one with a
+// short timeout.
+func NewHTTPPolicy(url string, client *http.Client) *HTTPPolicy {
+	if client == nil {
+		client = &http.Client{Timeout: policyTimeout}
+	}
+	return &HTTPPolicy{url: url, client: client}
+}
+
+// Evaluate queries the endpoint.
+func (p *HTTPPolicy) Evaluate(ctx context.Context, request PolicyRequest) (PolicyDecision, error) {
+	body, err := json.Marshal(map[string]interface{}{"input": request})
+	if err != nil {
+		return PolicyDecision{}, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return PolicyDecision{}, fmt.Errorf("failed to create policy request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return PolicyDecision{}, fmt.Errorf("policy request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return PolicyDecision{}, fmt.Errorf("failed to read policy response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return PolicyDecision{}, fmt.Errorf("policy endpoint returned status %d", resp.StatusCode)
+	}
+
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return PolicyDecision{}, fmt.Errorf("failed to parse policy response: %w", err)
+	}
+	var decision PolicyDecision
+	switch {
+	case len(envelope.Result) == 0 || string(envelope.Result) == "null":
+		return PolicyDecision{Reason: "no policy decision"}, nil
+	case json.Unmarshal(envelope.Result, &decision.Allow) == nil:
+		return decision, nil
+	case json.Unmarshal(envelope.Result, &decision) == nil:
+		return decision, nil
+	}
+	return PolicyDecision{}, fmt.Errorf("unexpected policy result: %s", envelope.Result)
+}
@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+func TestPolicyMiddleware(t *testing.T) {
+	tc, _ := newTestContext(t)
+	ctx := auth.WithPrincipal(WithToolContext(context.Background(), tc), "alice")
+
+	var called bool
+	var handlerArgs map[string]interface{}
+	handler := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		called, handlerArgs = true, args
+		return TextResult("ok")
+	}
+	var seen []PolicyRequest
+	addLabel := PolicyFunc(func(ctx context.Context, r PolicyRequest) (PolicyDecision, error) {
+		seen = append(seen, r)
+		args := map[string]interface{}{"labels": "audited"}
+		for k, v := range r.Arguments {
+			args[k] = v
+		}
+		return PolicyDecision{Allow: true, Arguments: args}, nil
+	})
+	record := PolicyFunc(func(ctx context.Context, r PolicyRequest) (PolicyDecision, error) {
+		seen = append(seen, r)
+		return PolicyDecision{Allow: true}, nil
+	})
+	deny := func(reason string) Policy {
+		return PolicyFunc(func(ctx context.Context, r PolicyRequest) (PolicyDecision, error) {
+			return PolicyDecision{Allow: false, Reason: reason}, nil
+		})
+	}
+	fail := PolicyFunc(func(ctx context.Context, r PolicyRequest) (PolicyDecision, error) {
+		return PolicyDecision{Allow: true}, errors.New("connection refused")
+	})
+
+	tests := []struct {
+		name     string
+		policies []namedPolicy
+		want     string // result text
+		wantCall bool
+	}{
+		{"no policies", nil, "ok", true},
+		{"modified arguments reach the next policy and the tool", []namedPolicy{{"label", addLabel}, {"record", record}}, "ok", true},
+		{"deny stops the call", []namedPolicy{{"label", addLabel}, {"protect", deny("issue 7 is protected")}, {"record", record}}, "Denied by policy protect: issue 7 is protected", false},
+		{"deny without a reason", []namedPolicy{{"protect", deny("")}}, "Denied by policy protect: not allowed", false},
+		{"an error denies", []namedPolicy{{"opa", fail}, {"record", record}}, "Denied by policy opa: policy check failed", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc.policies = tt.policies
+			called, handlerArgs, seen = false, nil, nil
+			_, wrapped := PolicyMiddleware(mcp.Tool{Name: "update_issue"}, handler)
+
+			result, err := wrapped(ctx, map[string]interface{}{"issue_iid": 7})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := resultText(t, result); got != tt.want || result.IsError == tt.wantCall {
+				t.Errorf("result = %q (error %v), want %q", got, result.IsError, tt.want)
+			}
+			if called != tt.wantCall {
+				t.Errorf("handler called = %v, want %v", called, tt.wantCall)
+			}
+		})
+	}
+
+	// What the second policy and the tool receive
+	tc.policies = []namedPolicy{{"label", addLabel}, {"record", record}}
+	seen = nil
+	_, wrapped := PolicyMiddleware(mcp.Tool{Name: "update_issue"}, handler)
+	wrapped(ctx, map[string]interface{}{"issue_iid": 7})
+	// Both see the arguments as decoded JSON, like those of the client
+	want := map[string]interface{}{"issue_iid": float64(7), "labels": "audited"}
+	if len(seen) != 2 || !reflect.DeepEqual(seen[1].Arguments, want) || !reflect.DeepEqual(handlerArgs, want) {
+		t.Errorf("next policy saw %+v and the tool %v, want the modified arguments %v", seen, handlerArgs, want)
+	}
+	if seen[0].Tool != "update_issue" || seen[0].Principal != "alice" || seen[0].ReadOnly {
+		t.Errorf("policy request = %+v, want update_issue by alice, not read-only", seen[0])
+	}
+
+	seen = nil
+	_, wrapped = PolicyMiddleware(mcp.Tool{Name: "get_issue"}, handler)
+	wrapped(ctx, nil)
+	if !seen[0].ReadOnly {
+		t.Error("policy request for get_issue is not read-only")
+	}
+}
+
+func TestPolicyMiddlewareValidatesRewrittenArguments(t *testing.T) {
+	tc, _ := newTestContext(t)
+	ctx := WithToolContext(context.Background(), tc)
+	var called bool
+	handler := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		called = true
+		return TextResult("ok")
+	}
+	rewrite := func(args map[string]interface{}) Policy {
+		return PolicyFunc(func(ctx context.Context, r PolicyRequest) (PolicyDecision, error) {
+			return PolicyDecision{Allow: true, Arguments: args}, nil
+		})
+	}
+	_, wrapped := PolicyMiddleware(mcp.Tool{
+		Name: "update_issue",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"issue_iid":   {Type: "integer"},
+				"state_event": {Type: "string", Enum: []string{"close", "reopen"}},
+			},
+			Required: []string{"issue_iid"},
+		},
+	}, handler)
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		want    string
+		wantRun bool
+	}{
+		{name: "valid", args: map[string]interface{}{"issue_iid": 7, "state_event": "close"}, want: "ok", wantRun: true},
+		{name: "wrong type", args: map[string]interface{}{"issue_iid": "seven"}, want: "Denied by policy fix: Invalid arguments for tool update_issue: issue_iid: "},
+		{name: "required argument dropped", args: map[string]interface{}{"state_event": "close"}, want: "Denied by policy fix: Invalid arguments for tool update_issue: issue_iid: "},
+		{name: "not in the enum", args: map[string]interface{}{"issue_iid": 7, "state_event": "delete"}, want: "Denied by policy fix: Invalid arguments for tool update_issue: state_event: "},
+		{name: "not JSON", args: map[string]interface{}{"issue_iid": make(chan int)}, want: "Denied by policy fix: policy check failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc.policies = []namedPolicy{{"fix", rewrite(tt.args)}}
+			called = false
+			result, err := wrapped(ctx, map[string]interface{}{"issue_iid": 7})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := resultText(t, result); !strings.HasPrefix(got, tt.want) || result.IsError == tt.wantRun || called != tt.wantRun {
+				t.Errorf("result = %q (error %v, called %v), want %q", got, result.IsError, called, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		want     PolicyDecision
+		wantErr  string
+	}{
+		{name: "OPA boolean true", status: http.StatusOK, response: `{"result": true}`, want: PolicyDecision{Allow: true}},
+		{name: "OPA boolean false", status: http.StatusOK, response: `{"result": false}`, want: PolicyDecision{}},
+		{name: "missing result", status: http.StatusOK, response: `{}`, want: PolicyDecision{Reason: "no policy decision"}},
+		{name: "null result", status: http.StatusOK, response: `{"result": null}`, want: PolicyDecision{Reason: "no policy decision"}},
+		{
+			name:     "decision object",
+			status:   http.StatusOK,
+			response: `{"result": {"allow": true, "arguments": {"confidential": true}}}`,
+			want:     PolicyDecision{Allow: true, Arguments: map[string]interface{}{"confidential": true}},
+		},
+		{name: "denial with reason", status: http.StatusOK, response: `{"result": {"allow": false, "reason": "frozen"}}`, want: PolicyDecision{Reason: "frozen"}},
+		{name: "non-200 status", status: http.StatusInternalServerError, response: `{"result": true}`, wantErr: "policy endpoint returned status 500"},
+		{name: "invalid JSON", status: http.StatusOK, response: `allow`, wantErr: "failed to parse policy response"},
+		{name: "unexpected result", status: http.StatusOK, response: `{"result": "yes"}`, wantErr: "unexpected policy result"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input struct {
+				Input PolicyRequest `json:"input"`
+			}
+			var contentType string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType = r.Header.Get("Content-Type")
+				json.NewDecoder(r.Body).Decode(&input)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			decision, err := NewHTTPPolicy(srv.URL, nil).Evaluate(context.Background(), PolicyRequest{
+				Tool:      "create_issue",
+				Arguments: map[string]interface{}{"title": "Bug"},
+				Principal: "alice",
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Evaluate error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if !reflect.DeepEqual(decision, tt.want) {
+				t.Errorf("decision = %+v, want %+v", decision, tt.want)
+			}
+			if contentType != "application/json" || input.Input.Tool != "create_issue" || input.Input.Principal != "alice" || input.Input.Arguments["title"] != "Bug" {
+				t.Errorf("policy input = %+v with content type %q", input.Input, contentType)
+			}
+		})
+	}
+}
+
+func TestHTTPPolicyUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	if _, err := NewHTTPPolicy(srv.URL, nil).Evaluate(context.Background(), PolicyRequest{Tool: "create_issue"}); err == nil || !strings.Contains(err.Error(), "policy request failed") {
+		t.Errorf("Evaluate error = %v, want a failed request", err)
+	}
+}
+
+func TestConfigurePolicies(t *testing.T) {
+	tc := NewToolContext(nil, nil, &config.Config{PolicyURL: "http://opa:8181/v1/data/mcp/allow"})
+	if err := tc.ConfigurePolicies(); err != nil {
+		t.Fatalf("ConfigurePolicies: %v", err)
+	}
+	if len(tc.policies) != 1 || tc.policies[0].name != "http" {
+		t.Errorf("active policies = %+v, want the http policy", tc.policies)
+	}
+
+	tc.Config = &config.Config{Policies: []string{"no-such-policy"}}
+	err := tc.ConfigurePolicies()
+	if err == nil || !strings.Contains(err.Error(), `unknown policy "no-such-policy"`) {
+		t.Errorf("ConfigurePolicies error = %v, want an unknown policy", err)
+	}
+	if len(tc.policies) != 1 {
+		t.Errorf("a failed ConfigurePolicies changed the active policies to %+v", tc.policies)
+	}
+
+	// Policies belong to their ToolContext, not to every server in the process
+	if other := NewToolContext(nil, nil, nil); len(other.policies) != 0 {
+		t.Errorf("a new ToolContext has policies %+v", other.policies)
+	}
+	next, err := tc.Reconfigure(&config.Config{})
+	if err != nil || len(next.policies) != 0 || len(tc.policies) != 1 {
+		t.Errorf("Reconfigure = %+v, %v; the old context has %d policies", next, err, len(tc.policies))
+	}
+	if _, err := tc.Reconfigure(&config.Config{Policies: []string{"no-such-policy"}}); err == nil {
+		t.Error("Reconfigure accepted an unknown policy")
+	}
+}
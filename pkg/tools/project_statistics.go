@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// ProjectStatistics are the storage statistics of a project, or their sum
+// over the projects of a group. Sizes are in bytes.
+type ProjectStatistics struct {
+	CommitCount           int64 `json:"commit_count"`
+	StorageSize           int64 `json:"storage_size"`
+	RepositorySize        int64 `json:"repository_size"`
+	WikiSize              int64 `json:"wiki_size"`
+	LFSObjectsSize        int64 `json:"lfs_objects_size"`
+	JobArtifactsSize      int64 `json:"job_artifacts_size"`
+	PipelineArtifactsSize int64 `json:"pipeline_artifacts_size"`
+	PackagesSize          int64 `json:"packages_size"`
+	SnippetsSize          int64 `json:"snippets_size"`
+	UploadsSize           int64 `json:"uploads_size"`
+	ContainerRegistrySize int64 `json:"container_registry_size"`
+}
+
+// add adds the statistics of another project.
+func (s *ProjectStatistics) add(o ProjectStatistics) {
+	s.CommitCount += o.CommitCount
+	s.StorageSize += o.StorageSize
+	s.RepositorySize += o.RepositorySize
+	s.WikiSize += o.WikiSize
+	s.LFSObjectsSize += o.LFSObjectsSize
+	s.JobArtifactsSize += o.JobArtifactsSize
+	s.PipelineArtifactsSize += o.PipelineArtifactsSize
+	s.PackagesSize += o.PackagesSize
+	s.SnippetsSize += o.SnippetsSize
+	s.UploadsSize += o.UploadsSize
+	s.ContainerRegistrySize += o.ContainerRegistrySize
+}
+
+// summary describes the statistics in one line, e.g. "storage 1.2 GiB
+// (repository 800.0 MiB, LFS 300.0 MiB, job artifacts 100.0 MiB, packages
+// 0 B), 1234 commits".
+func (s ProjectStatistics) summary() string {
+	return fmt.Sprintf("storage %s (repository %s, LFS %s, job artifacts %s, packages %s), %d commits",
+		formatBytes(s.StorageSize), formatBytes(s.RepositorySize), formatBytes(s.LFSObjectsSize),
+		formatBytes(s.JobArtifactsSize), formatBytes(s.PackagesSize), s.CommitCount)
+}
+
+// formatBytes renders a size in binary units, e.g. "1.5 GiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTP"[exp])
+}
+
+// projectWithStatistics is a project listed or fetched with statistics=true.
+type projectWithStatistics struct {
+	ID                int                `json:"id"`
+	PathWithNamespace string             `json:"path_with_namespace"`
+	Statistics        *ProjectStatistics `json:"statistics"`
+}
+
+// ProjectStatisticsResult is the response of the get_project_statistics tool.
+type ProjectStatisticsResult struct {
+	ProjectID  int               `json:"project_id"`
+	Project    string            `json:"project"`
+	Summary    string            `json:"summary"`
+	Statistics ProjectStatistics `json:"statistics"`
+}
+
+// ProjectStorage is the statistics of one project of a group.
+type ProjectStorage struct {
+	Project    string            `json:"project"`
+	Statistics ProjectStatistics `json:"statistics"`
+}
+
+// GroupStatistics is the response of the get_group_statistics tool.
+type GroupStatistics struct {
+	GroupID  string `json:"group_id"`
+	Projects int    `json:"projects"`
+	// WithoutStatistics counts projects whose statistics the token cannot read
+	WithoutStatistics int               `json:"without_statistics,omitempty"`
+	Complete          bool              `json:"complete"`
+	Summary           string            `json:"summary"`
+	Totals            ProjectStatistics `json:"totals"`
+	Largest           []ProjectStorage  `json:"largest"`
+}
+
+type getProjectStatisticsArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+}
+
+// registerGetProjectStatistics registers the get_project_statistics tool.
+func registerGetProjectStatistics(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_project_statistics",
+			Description: "Get the storage statistics of a project: total storage, repository, wiki, LFS, job and pipeline artifacts, packages, snippets, uploads and container registry sizes in bytes, plus the commit count and a one-line summary. Needs at least the Reporter role.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		withArgs("get_project_statistics", func(ctx context.Context, c *ToolContext, args getProjectStatisticsArgs) (*mcp.CallToolResult, error) {
+			var project projectWithStatistics
+			if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s?statistics=true", url.PathEscape(args.ProjectID)), &project); err != nil {
+				return APIErrorResult("Failed to get project", err)
+			}
+			if project.Statistics == nil {
+				return ErrorResult(fmt.Sprintf("statistics of project %s are not available; they need at least the Reporter role", args.ProjectID))
+			}
+			return JSONResult(ProjectStatisticsResult{
+				ProjectID:  project.ID,
+				Project:    project.PathWithNamespace,
+				Summary:    project.Statistics.summary(),
+				Statistics: *project.Statistics,
+			})
+		}),
+	)
+}
+
+type getGroupStatisticsArgs struct {
+	GroupID          string `json:"group_id" validate:"required"`
+	IncludeSubgroups bool   `json:"include_subgroups"`
+	Top              int    `json:"top" validate:"min=1,max=100"`
+}
+
+// registerGetGroupStatistics registers the get_group_statistics tool.
+func registerGetGroupStatistics(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_group_statistics",
+			Description: "Roll up the storage statistics of all projects of a group: summed storage, repository, LFS, artifact, package and registry sizes and commit counts, plus the largest projects by storage. Projects whose statistics the token cannot read are counted but not summed.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"group_id": {
+						Type:        "string",
+						Description: "The group ID or URL-encoded path (e.g., acme or acme/platform)",
+					},
+					"include_subgroups": {
+						Type:        "boolean",
+						Description: "Include projects of subgroups. Default: false",
+					},
+					"top": {
+						Type:        "integer",
+						Description: "Largest projects listed (max 100). Default: 10",
+						Default:     10,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"group_id"},
+			},
+		},
+		withArgs("get_group_statistics", func(ctx context.Context, c *ToolContext, args getGroupStatisticsArgs) (*mcp.CallToolResult, error) {
+			top := args.Top
+			if top == 0 {
+				top = 10
+			}
+			params := url.Values{}
+			params.Set("statistics", "true")
+			if args.IncludeSubgroups {
+				params.Set("include_subgroups", "true")
+			}
+
+			result := GroupStatistics{GroupID: args.GroupID, Largest: []ProjectStorage{}}
+			complete, err := streamPages(ctx, c.Client, fmt.Sprintf("/groups/%s/projects?%s", url.PathEscape(args.GroupID), params.Encode()), maxCollectedItems, func(project projectWithStatistics) {
+				result.Projects++
+				if project.Statistics == nil {
+					result.WithoutStatistics++
+					return
+				}
+				result.Totals.add(*project.Statistics)
+				result.Largest = append(result.Largest, ProjectStorage{Project: project.PathWithNamespace, Statistics: *project.Statistics})
+			})
+			if err != nil {
+				return APIErrorResult(fmt.Sprintf("Failed to list the projects of group %s", args.GroupID), err)
+			}
+			result.Complete = complete
+			result.Summary = fmt.Sprintf("%d projects: %s", result.Projects, result.Totals.summary())
+
+			sort.SliceStable(result.Largest, func(i, j int) bool {
+				return result.Largest[i].Statistics.StorageSize > result.Largest[j].Statistics.StorageSize
+			})
+			result.Largest = result.Largest[:min(len(result.Largest), top)]
+			return JSONResult(result)
+		}),
+	)
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGetGroupStatistics(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/groups/acme/projects", 200, `[
+		{"id": 1, "path_with_namespace": "acme/api", "statistics": {"commit_count": 10, "storage_size": 3072, "repository_size": 2048, "lfs_objects_size": 1024}},
+		{"id": 2, "path_with_namespace": "acme/web", "statistics": {"commit_count": 5, "storage_size": 5242880, "job_artifacts_size": 5242880}},
+		{"id": 3, "path_with_namespace": "acme/secret"}
+	]`)
+
+	res := callTool(t, tc, "get_group_statistics", map[string]interface{}{"group_id": "acme", "top": 1})
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, res))
+	}
+	var stats GroupStatistics
+	if err := json.Unmarshal([]byte(resultText(t, res)), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Projects != 3 || stats.WithoutStatistics != 1 || !stats.Complete {
+		t.Errorf("projects = %d, without statistics = %d, complete = %v", stats.Projects, stats.WithoutStatistics, stats.Complete)
+	}
+	if stats.Totals.CommitCount != 15 || stats.Totals.StorageSize != 5245952 || stats.Totals.JobArtifactsSize != 5242880 {
+		t.Errorf("totals = %+v", stats.Totals)
+	}
+	if len(stats.Largest) != 1 || stats.Largest[0].Project != "acme/web" {
+		t.Errorf("largest = %+v", stats.Largest)
+	}
+	if want := "3 projects: storage 5.0 MiB (repository 2.0 KiB, LFS 1.0 KiB, job artifacts 5.0 MiB, packages 0 B), 15 commits"; stats.Summary != want {
+		t.Errorf("summary = %q, want %q", stats.Summary, want)
+	}
+	query, _ := url.ParseQuery(strings.SplitN(client.Requests()[0].Endpoint, "?", 2)[1])
+	if query.Get("statistics") != "true" || query.Has("include_subgroups") {
+		t.Errorf("query = %v", query)
+	}
+}
@@ -1,13 +1,81 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
 
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
 )
 
+// projectArgs are the arguments of tools taking only a project.
+type projectArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+}
+
+type listProjectsArgs struct {
+	PageArgs
+	Namespace  string `json:"namespace"`
+	Search     string `json:"search"`
+	Visibility string `json:"visibility" validate:"oneof=public internal private"`
+	OrderBy    string `json:"order_by" validate:"oneof=id name path created_at updated_at last_activity_at"`
+	Sort       string `json:"sort" validate:"oneof=asc desc"`
+}
+
+type searchRepositoriesArgs struct {
+	PageArgs
+	Query     string `json:"query" validate:"required"`
+	Namespace string `json:"namespace"`
+}
+
+type createRepositoryArgs struct {
+	Name                 string `json:"name" validate:"required"`
+	NamespaceID          string `json:"namespace_id"`
+	Description          string `json:"description"`
+	Visibility           string `json:"visibility" validate:"oneof=public internal private"`
+	InitializeWithReadme bool   `json:"initialize_with_readme"`
+}
+
+type forkRepositoryArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	Namespace string `json:"namespace"`
+}
+
+type listProjectForksArgs struct {
+	PageArgs
+	ProjectID string `json:"project_id" validate:"required"`
+	Owned     *bool  `json:"owned"`
+	Archived  *bool  `json:"archived"`
+	OrderBy   string `json:"order_by" validate:"oneof=id name path created_at updated_at last_activity_at"`
+	Sort      string `json:"sort" validate:"oneof=asc desc"`
+}
+
+type listGroupProjectsArgs struct {
+	PageArgs
+	GroupID          string `json:"group_id"`
+	Archived         *bool  `json:"archived"`
+	IncludeSubgroups *bool  `json:"include_subgroups"`
+	WithShared       *bool  `json:"with_shared"`
+	MinAccessLevel   int    `json:"min_access_level" validate:"min=5,max=50"`
+	OrderBy          string `json:"order_by" validate:"oneof=id name path created_at updated_at similarity last_activity_at star_count"`
+	Sort             string `json:"sort" validate:"oneof=asc desc"`
+}
+
+type repositoryTreeArgs struct {
+	PageArgs
+	ProjectID string `json:"project_id" validate:"required"`
+	Path      string `json:"path"`
+	Ref       string `json:"ref"`
+	Recursive bool   `json:"recursive"`
+	MaxDepth  int    `json:"max_depth" validate:"min=1"`
+	Glob      string `json:"glob"`
+	// ResolveSymlinks reads the target of each symlink on the page
+	ResolveSymlinks bool `json:"resolve_symlinks"`
+}
+
 // registerGetProject registers the get_project tool
 func registerGetProject(server *mcp.Server) {
 	server.RegisterTool(
@@ -28,27 +96,16 @@ func registerGetProject(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
-			if c == nil {
-				return ErrorResult("tool context not initialized")
-			}
-			c.Logger.ToolCall("get_project", args)
-
-			projectID := GetString(args, "project_id", "")
-			if projectID == "" {
-				return ErrorResult("project_id is required")
-			}
-
-			endpoint := fmt.Sprintf("/projects/%s", url.PathEscape(projectID))
+		withArgs("get_project", func(ctx context.Context, c *ToolContext, args projectArgs) (*mcp.CallToolResult, error) {
+			endpoint := fmt.Sprintf("/projects/%s", url.PathEscape(args.ProjectID))
 
 			var project gitlab.Project
-			if err := c.Client.Get(endpoint, &project); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get project: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &project); err != nil {
+				return APIErrorResult("Failed to get project", err)
 			}
 
 			return JSONResult(project)
-		},
+		}),
 	)
 }
 
@@ -57,7 +114,7 @@ func registerListProjects(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "list_projects",
-			Description: "List all projects visible to the authenticated user. Returns an array of project objects with basic metadata. Use this for broad project discovery. For targeted searches by name/description, prefer search_repositories instead. If GITLAB_DEFAULT_NAMESPACE is configured, lists projects within that namespace by default.",
+			Description: "List all projects visible to the authenticated user. Returns an array of project objects with basic metadata. Use this for broad project discovery. For targeted searches by name/description, prefer search_repositories instead. If GITLAB_DEFAULT_NAMESPACE is configured, lists projects within that namespace and its subgroups by default; with several namespaces, each is listed in order.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -103,67 +160,105 @@ func registerListProjects(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
-			if c == nil {
-				return ErrorResult("tool context not initialized")
-			}
-			c.Logger.ToolCall("list_projects", args)
-
-			// Determine namespace: explicit arg > config default > none
-			namespace := GetString(args, "namespace", "")
-			if namespace == "" && c.Config.DefaultNamespace != "" {
-				namespace = c.Config.DefaultNamespace
-			}
-
+		withArgs("list_projects", func(ctx context.Context, c *ToolContext, args listProjectsArgs) (*mcp.CallToolResult, error) {
 			params := url.Values{}
 
-			if page := GetInt(args, "page", 0); page > 0 {
-				params.Set("page", fmt.Sprintf("%d", page))
-			}
-			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
-				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			args.setParams(params)
+			if args.Search != "" {
+				params.Set("search", args.Search)
 			}
-			if search := GetString(args, "search", ""); search != "" {
-				params.Set("search", search)
+			if args.Visibility != "" {
+				params.Set("visibility", args.Visibility)
 			}
-			if visibility := GetString(args, "visibility", ""); visibility != "" {
-				params.Set("visibility", visibility)
+			if args.OrderBy != "" {
+				params.Set("order_by", args.OrderBy)
 			}
-			if orderBy := GetString(args, "order_by", ""); orderBy != "" {
-				params.Set("order_by", orderBy)
-			}
-			if sort := GetString(args, "sort", ""); sort != "" {
-				params.Set("sort", sort)
+			if args.Sort != "" {
+				params.Set("sort", args.Sort)
 			}
 
-			// Use group endpoint if namespace is set, otherwise list all projects
-			var endpoint string
-			if namespace != "" {
-				endpoint = fmt.Sprintf("/groups/%s/projects", url.PathEscape(namespace))
-			} else {
-				endpoint = "/projects"
+			// Use the group endpoints if a namespace applies, otherwise list all projects
+			if namespaces := projectNamespaces(c, args.Namespace); len(namespaces) > 0 {
+				params.Set("include_subgroups", "true")
+				projects, pagination, err := listNamespaceProjects(ctx, c.Client, namespaces, params)
+				if err != nil {
+					return APIErrorResult("Failed to list projects", err)
+				}
+				return PagedJSONResult(projects, pagination)
 			}
+			endpoint := "/projects"
 			if len(params) > 0 {
 				endpoint = fmt.Sprintf("%s?%s", endpoint, params.Encode())
 			}
 
 			var projects []gitlab.Project
-			if err := c.Client.Get(endpoint, &projects); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list projects: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &projects)
+			if err != nil {
+				return APIErrorResult("Failed to list projects", err)
 			}
 
-			return JSONResult(projects)
-		},
+			return PagedJSONResult(projects, pagination)
+		}),
 	)
 }
 
+// projectNamespaces returns the namespaces a project listing covers: the
+// given one, else the configured defaults in search order.
+func projectNamespaces(c *ToolContext, namespace string) []string {
+	if namespace != "" {
+		return []string{namespace}
+	}
+	if c.Config == nil {
+		return nil
+	}
+	return c.Config.DefaultNamespaces
+}
+
+// listNamespaceProjects lists one page of the projects of each namespace in
+// order, dropping projects already listed for an earlier namespace. With
+// several namespaces the pagination is combined: the totals are summed and
+// there is a next page while any namespace has one.
+func listNamespaceProjects(ctx context.Context, client gitlab.API, namespaces []string, params url.Values) ([]gitlab.Project, *gitlab.PaginationInfo, error) {
+	projects := []gitlab.Project{}
+	var combined *gitlab.PaginationInfo
+	seen := map[int]bool{}
+	for _, namespace := range namespaces {
+		endpoint := fmt.Sprintf("/groups/%s/projects", url.PathEscape(namespace))
+		if len(params) > 0 {
+			endpoint = fmt.Sprintf("%s?%s", endpoint, params.Encode())
+		}
+		var page []gitlab.Project
+		pagination, err := client.GetWithPagination(ctx, endpoint, &page)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, project := range page {
+			if !seen[project.ID] {
+				seen[project.ID] = true
+				projects = append(projects, project)
+			}
+		}
+		switch {
+		case pagination == nil:
+			continue
+		case combined == nil:
+			first := *pagination
+			combined = &first
+		default:
+			combined.Total += pagination.Total
+			combined.TotalPages = max(combined.TotalPages, pagination.TotalPages)
+			combined.NextPage = max(combined.NextPage, pagination.NextPage)
+		}
+	}
+	return projects, combined, nil
+}
+
 // registerSearchRepositories registers the search_repositories tool
 func registerSearchRepositories(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "search_repositories",
-			Description: "Search for GitLab repositories by name or description using a query string. Returns matching projects sorted by relevance. Use this for targeted searches when you know keywords. For broad listing without specific search terms, use list_projects instead. If GITLAB_DEFAULT_NAMESPACE is configured, searches within that namespace by default.",
+			Description: "Search for GitLab repositories by name or description using a query string. Returns matching projects sorted by relevance. Use this for targeted searches when you know keywords. For broad listing without specific search terms, use list_projects instead. If GITLAB_DEFAULT_NAMESPACE is configured, searches within that namespace and its subgroups by default; with several namespaces, each is searched in order.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -195,49 +290,27 @@ func registerSearchRepositories(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
-			if c == nil {
-				return ErrorResult("tool context not initialized")
-			}
-			c.Logger.ToolCall("search_repositories", args)
-
-			query := GetString(args, "query", "")
-			if query == "" {
-				return ErrorResult("query is required")
-			}
-
-			// Determine namespace: explicit arg > config default > none
-			namespace := GetString(args, "namespace", "")
-			if namespace == "" && c.Config.DefaultNamespace != "" {
-				namespace = c.Config.DefaultNamespace
-			}
-
+		withArgs("search_repositories", func(ctx context.Context, c *ToolContext, args searchRepositoriesArgs) (*mcp.CallToolResult, error) {
 			params := url.Values{}
-			params.Set("search", query)
-
-			if page := GetInt(args, "page", 0); page > 0 {
-				params.Set("page", fmt.Sprintf("%d", page))
-			}
-			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
-				params.Set("per_page", fmt.Sprintf("%d", perPage))
-			}
+			params.Set("search", args.Query)
+			args.setParams(params)
 
-			// Use group endpoint if namespace is set, otherwise search all projects
-			var endpoint string
-			if namespace != "" {
-				endpoint = fmt.Sprintf("/groups/%s/projects?%s", url.PathEscape(namespace), params.Encode())
+			// Use the group endpoints if a namespace applies, otherwise search all projects
+			var projects []gitlab.Project
+			var pagination *gitlab.PaginationInfo
+			var err error
+			if namespaces := projectNamespaces(c, args.Namespace); len(namespaces) > 0 {
+				params.Set("include_subgroups", "true")
+				projects, pagination, err = listNamespaceProjects(ctx, c.Client, namespaces, params)
 			} else {
-				endpoint = fmt.Sprintf("/projects?%s", params.Encode())
+				pagination, err = c.Client.GetWithPagination(ctx, fmt.Sprintf("/projects?%s", params.Encode()), &projects)
 			}
-
-			var projects []gitlab.Project
-			if err := c.Client.Get(endpoint, &projects); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to search repositories: %v", err))
+			if err != nil {
+				return APIErrorResult("Failed to search repositories", err)
 			}
 
-			return JSONResult(projects)
-		},
+			return PagedJSONResult(projects, pagination)
+		}),
 	)
 }
 
@@ -275,48 +348,37 @@ func registerCreateRepository(server *mcp.Server) {
 				Required: []string{"name"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
-			if c == nil {
-				return ErrorResult("tool context not initialized")
-			}
-			c.Logger.ToolCall("create_repository", args)
-
-			name := GetString(args, "name", "")
-			if name == "" {
-				return ErrorResult("name is required")
-			}
-
+		withArgs("create_repository", func(ctx context.Context, c *ToolContext, args createRepositoryArgs) (*mcp.CallToolResult, error) {
 			// Determine namespace: explicit arg > config default > user's personal namespace
-			namespace := GetString(args, "namespace_id", "")
+			namespace := args.NamespaceID
 			if namespace == "" && c.Config.DefaultNamespace != "" {
 				namespace = c.Config.DefaultNamespace
 			}
 
 			body := map[string]interface{}{
-				"name": name,
+				"name": args.Name,
 			}
 
 			if namespace != "" {
 				body["namespace_id"] = namespace
 			}
-			if description := GetString(args, "description", ""); description != "" {
-				body["description"] = description
+			if args.Description != "" {
+				body["description"] = args.Description
 			}
-			if visibility := GetString(args, "visibility", ""); visibility != "" {
-				body["visibility"] = visibility
+			if args.Visibility != "" {
+				body["visibility"] = args.Visibility
 			}
-			if initWithReadme := GetBool(args, "initialize_with_readme", false); initWithReadme {
+			if args.InitializeWithReadme {
 				body["initialize_with_readme"] = true
 			}
 
 			var project gitlab.Project
-			if err := c.Client.Post("/projects", body, &project); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create repository: %v", err))
+			if err := c.Client.Post(ctx, "/projects", body, &project); err != nil {
+				return APIErrorResult("Failed to create repository", err)
 			}
 
 			return JSONResult(project)
-		},
+		}),
 	)
 }
 
@@ -341,25 +403,14 @@ func registerForkRepository(server *mcp.Server) {
 				Required: []string{"project_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
-			if c == nil {
-				return ErrorResult("tool context not initialized")
-			}
-			c.Logger.ToolCall("fork_repository", args)
-
-			projectID := GetString(args, "project_id", "")
-			if projectID == "" {
-				return ErrorResult("project_id is required")
-			}
-
+		withArgs("fork_repository", func(ctx context.Context, c *ToolContext, args forkRepositoryArgs) (*mcp.CallToolResult, error) {
 			// Determine namespace: explicit arg > config default > user's personal namespace
-			namespace := GetString(args, "namespace", "")
+			namespace := args.Namespace
 			if namespace == "" && c.Config.DefaultNamespace != "" {
 				namespace = c.Config.DefaultNamespace
 			}
 
-			endpoint := fmt.Sprintf("/projects/%s/fork", url.PathEscape(projectID))
+			endpoint := fmt.Sprintf("/projects/%s/fork", url.PathEscape(args.ProjectID))
 
 			var body map[string]interface{}
 			if namespace != "" {
@@ -369,27 +420,58 @@ func registerForkRepository(server *mcp.Server) {
 			}
 
 			var project gitlab.Project
-			if err := c.Client.Post(endpoint, body, &project); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to fork repository: %v", err))
+			if err := c.Client.Post(ctx, endpoint, body, &project); err != nil {
+				return APIErrorResult("Failed to fork repository", err)
 			}
 
 			return JSONResult(project)
-		},
+		}),
 	)
 }
 
-// registerListGroupProjects registers the list_group_projects tool
-func registerListGroupProjects(server *mcp.Server) {
+// ForkRelationship describes where a project was forked from.
+type ForkRelationship struct {
+	ProjectID         int             `json:"project_id"`
+	PathWithNamespace string          `json:"path_with_namespace"`
+	IsFork            bool            `json:"is_fork"`
+	ForkedFromProject *gitlab.Project `json:"forked_from_project,omitempty"`
+	// CommitsBehind and CommitsAhead compare the fork's default branch with
+	// the upstream default branch. They are omitted if the comparison failed.
+	CommitsBehind *int   `json:"commits_behind,omitempty"`
+	CommitsAhead  *int   `json:"commits_ahead,omitempty"`
+	CompareError  string `json:"compare_error,omitempty"`
+}
+
+// registerListProjectForks registers the list_project_forks tool
+func registerListProjectForks(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
-			Name:        "list_group_projects",
-			Description: "List all projects within a GitLab group. Returns an array of project objects. Use this when you specifically want to list projects in a known group. For general project discovery, use list_projects instead. Uses GITLAB_DEFAULT_NAMESPACE if group_id is not provided.",
+			Name:        "list_project_forks",
+			Description: "List the forks of a project that are visible to the authenticated user.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
-					"group_id": {
+					"project_id": {
 						Type:        "string",
-						Description: "The ID or URL-encoded path of the group. Falls back to GITLAB_DEFAULT_NAMESPACE if not set.",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"owned": {
+						Type:        "boolean",
+						Description: "Only forks owned by the authenticated user",
+					},
+					"archived": {
+						Type:        "boolean",
+						Description: "Filter by archived status (true = only archived, false = only active, omit = all)",
+					},
+					"order_by": {
+						Type:        "string",
+						Description: "Order forks by id, name, path, created_at, updated_at or last_activity_at (default: created_at)",
+						Enum:        []string{"id", "name", "path", "created_at", "updated_at", "last_activity_at"},
+					},
+					"sort": {
+						Type:        "string",
+						Description: "Sort order: asc or desc (default: desc)",
+						Enum:        []string{"asc", "desc"},
 					},
 					"page": {
 						Type:        "integer",
@@ -404,67 +486,51 @@ func registerListGroupProjects(server *mcp.Server) {
 						Minimum:     mcp.IntPtr(1),
 						Maximum:     mcp.IntPtr(100),
 					},
-					"archived": {
-						Type:        "boolean",
-						Description: "Filter by archived status (true = only archived, false = only active, omit = all)",
-					},
 				},
+				Required: []string{"project_id"},
 			},
 			Annotations: &mcp.ToolAnnotations{
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
-			if c == nil {
-				return ErrorResult("tool context not initialized")
-			}
-			c.Logger.ToolCall("list_group_projects", args)
-
-			// Determine group: explicit arg > config default
-			groupID := GetString(args, "group_id", "")
-			if groupID == "" && c.Config.DefaultNamespace != "" {
-				groupID = c.Config.DefaultNamespace
-			}
-			if groupID == "" {
-				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
-			}
-
+		withArgs("list_project_forks", func(ctx context.Context, c *ToolContext, args listProjectForksArgs) (*mcp.CallToolResult, error) {
 			params := url.Values{}
-
-			if page := GetInt(args, "page", 0); page > 0 {
-				params.Set("page", fmt.Sprintf("%d", page))
+			if args.Owned != nil {
+				params.Set("owned", fmt.Sprintf("%t", *args.Owned))
 			}
-			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
-				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			if args.Archived != nil {
+				params.Set("archived", fmt.Sprintf("%t", *args.Archived))
 			}
-			// Handle archived parameter - only add if explicitly set
-			if _, exists := args["archived"]; exists {
-				archived := GetBool(args, "archived", false)
-				params.Set("archived", fmt.Sprintf("%t", archived))
+			if args.OrderBy != "" {
+				params.Set("order_by", args.OrderBy)
 			}
+			if args.Sort != "" {
+				params.Set("sort", args.Sort)
+			}
+			args.setParams(params)
 
-			endpoint := fmt.Sprintf("/groups/%s/projects", url.PathEscape(groupID))
+			endpoint := fmt.Sprintf("/projects/%s/forks", url.PathEscape(args.ProjectID))
 			if len(params) > 0 {
 				endpoint = fmt.Sprintf("%s?%s", endpoint, params.Encode())
 			}
 
-			var projects []gitlab.Project
-			if err := c.Client.Get(endpoint, &projects); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list group projects: %v", err))
+			var forks []gitlab.Project
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &forks)
+			if err != nil {
+				return APIErrorResult("Failed to list project forks", err)
 			}
 
-			return JSONResult(projects)
-		},
+			return PagedJSONResult(forks, pagination)
+		}),
 	)
 }
 
-// registerGetRepositoryTree registers the get_repository_tree tool
-func registerGetRepositoryTree(server *mcp.Server) {
+// registerGetForkRelationship registers the get_fork_relationship tool
+func registerGetForkRelationship(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
-			Name:        "get_repository_tree",
-			Description: "Get the repository file tree for a GitLab project. Returns an array of tree nodes (files and directories) with name, path, type, and mode. Use this to explore directory structure before fetching specific files with get_file_contents.",
+			Name:        "get_fork_relationship",
+			Description: "Show where a project was forked from, and how many commits its default branch is behind and ahead of the upstream default branch.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -472,82 +538,175 @@ func registerGetRepositoryTree(server *mcp.Server) {
 						Type:        "string",
 						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
 					},
-					"path": {
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_fork_relationship", func(ctx context.Context, c *ToolContext, args projectArgs) (*mcp.CallToolResult, error) {
+			var project gitlab.Project
+			if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s", url.PathEscape(args.ProjectID)), &project); err != nil {
+				return APIErrorResult("Failed to get project", err)
+			}
+
+			relationship := ForkRelationship{
+				ProjectID:         project.ID,
+				PathWithNamespace: project.PathWithNamespace,
+				IsFork:            project.ForkedFromProject != nil,
+				ForkedFromProject: project.ForkedFromProject,
+			}
+			upstream := project.ForkedFromProject
+			if upstream == nil || upstream.DefaultBranch == "" || project.DefaultBranch == "" {
+				return JSONResult(relationship)
+			}
+
+			// Commits ahead are compared inside the fork against the upstream
+			// branch; commits behind are compared inside the upstream.
+			ahead, err := countCompareCommits(ctx, c, project.ID, upstream.ID, upstream.DefaultBranch, project.DefaultBranch)
+			if err == nil {
+				relationship.CommitsAhead = &ahead
+				var behind int
+				if behind, err = countCompareCommits(ctx, c, upstream.ID, project.ID, project.DefaultBranch, upstream.DefaultBranch); err == nil {
+					relationship.CommitsBehind = &behind
+				}
+			}
+			if err != nil {
+				relationship.CompareError = err.Error()
+			}
+
+			return JSONResult(relationship)
+		}),
+	)
+}
+
+// countCompareCommits returns the number of commits on to (in projectID) that
+// are not on from (in fromProjectID).
+func countCompareCommits(ctx context.Context, c *ToolContext, projectID, fromProjectID int, from, to string) (int, error) {
+	params := url.Values{}
+	params.Set("from", from)
+	params.Set("to", to)
+	params.Set("from_project_id", fmt.Sprintf("%d", fromProjectID))
+	var result struct {
+		Commits []gitlab.Commit `json:"commits"`
+	}
+	endpoint := fmt.Sprintf("/projects/%d/repository/compare?%s", projectID, params.Encode())
+	if err := c.Client.Get(ctx, endpoint, &result); err != nil {
+		return 0, err
+	}
+	return len(result.Commits), nil
+}
+
+// registerListGroupProjects registers the list_group_projects tool
+func registerListGroupProjects(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_group_projects",
+			Description: "List all projects within a GitLab group. Returns an array of project objects. Use this when you specifically want to list projects in a known group. For general project discovery, use list_projects instead. Set include_subgroups to cover a deep group hierarchy, and with_shared=false to leave out projects shared from other groups. Uses GITLAB_DEFAULT_NAMESPACE, subgroups included, if group_id is not provided.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"group_id": {
 						Type:        "string",
-						Description: "Path inside repository to list (default: root)",
+						Description: "The ID or URL-encoded path of the group. Falls back to GITLAB_DEFAULT_NAMESPACE if not set.",
 					},
-					"ref": {
-						Type:        "string",
-						Description: "Branch name, tag, or commit SHA (default: default branch)",
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
 					},
-					"recursive": {
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+					"archived": {
 						Type:        "boolean",
-						Description: "Get tree recursively",
+						Description: "Filter by archived status (true = only archived, false = only active, omit = all)",
+					},
+					"include_subgroups": {
+						Type:        "boolean",
+						Description: "Include projects in subgroups at any depth. Default: false with group_id, true for the GITLAB_DEFAULT_NAMESPACE groups",
+					},
+					"with_shared": {
+						Type:        "boolean",
+						Description: "Include projects shared with the group. Default: true",
+					},
+					"min_access_level": {
+						Type:        "integer",
+						Description: "Only projects where the authenticated user has at least this access level: 5 (minimal), 10 (guest), 15 (planner), 20 (reporter), 30 (developer), 40 (maintainer) or 50 (owner)",
+						Minimum:     mcp.IntPtr(5),
+						Maximum:     mcp.IntPtr(50),
+					},
+					"order_by": {
+						Type:        "string",
+						Description: "Order by: id, name, path, created_at, updated_at, similarity (with a search), last_activity_at or star_count. Default: created_at",
+						Enum:        []string{"id", "name", "path", "created_at", "updated_at", "similarity", "last_activity_at", "star_count"},
+					},
+					"sort": {
+						Type:        "string",
+						Description: "Sort direction: asc or desc. Default: desc",
+						Enum:        []string{"asc", "desc"},
 					},
 				},
-				Required: []string{"project_id"},
 			},
 			Annotations: &mcp.ToolAnnotations{
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
-			if c == nil {
-				return ErrorResult("tool context not initialized")
-			}
-			c.Logger.ToolCall("get_repository_tree", args)
-
-			projectID := GetString(args, "project_id", "")
-			if projectID == "" {
-				return ErrorResult("project_id is required")
+		withArgs("list_group_projects", func(ctx context.Context, c *ToolContext, args listGroupProjectsArgs) (*mcp.CallToolResult, error) {
+			// Determine groups: explicit arg > config defaults, searched in order
+			groups := projectNamespaces(c, args.GroupID)
+			if len(groups) == 0 {
+				return ErrorResult("group_id is required (or set GITLAB_DEFAULT_NAMESPACE)")
 			}
 
 			params := url.Values{}
 
-			if path := GetString(args, "path", ""); path != "" {
-				params.Set("path", path)
+			args.setParams(params)
+			// Handle archived parameter - only add if explicitly set
+			if args.Archived != nil {
+				params.Set("archived", fmt.Sprintf("%t", *args.Archived))
 			}
-			if ref := GetString(args, "ref", ""); ref != "" {
-				params.Set("ref", ref)
+			switch {
+			case args.IncludeSubgroups != nil:
+				params.Set("include_subgroups", fmt.Sprintf("%t", *args.IncludeSubgroups))
+			case args.GroupID == "":
+				params.Set("include_subgroups", "true")
 			}
-			if recursive := GetBool(args, "recursive", false); recursive {
-				params.Set("recursive", "true")
+			if args.WithShared != nil {
+				params.Set("with_shared", fmt.Sprintf("%t", *args.WithShared))
 			}
-
-			endpoint := fmt.Sprintf("/projects/%s/repository/tree", url.PathEscape(projectID))
-			if len(params) > 0 {
-				endpoint = fmt.Sprintf("%s?%s", endpoint, params.Encode())
+			if args.MinAccessLevel != 0 {
+				params.Set("min_access_level", fmt.Sprintf("%d", args.MinAccessLevel))
+			}
+			if args.OrderBy != "" {
+				params.Set("order_by", args.OrderBy)
+			}
+			if args.Sort != "" {
+				params.Set("sort", args.Sort)
 			}
 
-			var treeNodes []gitlab.TreeNode
-			if err := c.Client.Get(endpoint, &treeNodes); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get repository tree: %v", err))
+			projects, pagination, err := listNamespaceProjects(ctx, c.Client, groups, params)
+			if err != nil {
+				return APIErrorResult("Failed to list group projects", err)
 			}
 
-			return JSONResult(treeNodes)
-		},
+			return PagedJSONResult(projects, pagination)
+		}),
 	)
 }
 
-// Member represents a project or group member with access level information
-type Member struct {
-	ID          int    `json:"id"`
-	Username    string `json:"username"`
-	Name        string `json:"name"`
-	State       string `json:"state"`
-	AvatarURL   string `json:"avatar_url"`
-	WebURL      string `json:"web_url"`
-	AccessLevel int    `json:"access_level"`
-	ExpiresAt   string `json:"expires_at,omitempty"`
-}
-
-// registerListProjectMembers registers the list_project_members tool
-func registerListProjectMembers(server *mcp.Server) {
+// registerGetRepositoryTree registers the get_repository_tree tool
+func registerGetRepositoryTree(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
-			Name:        "list_project_members",
-			Description: "List all members of a GitLab project. Returns an array of member objects with username, name, access level (10=Guest, 20=Reporter, 30=Developer, 40=Maintainer, 50=Owner), and membership details.",
+			Name:        "get_repository_tree",
+			Description: "Get the repository file tree for a GitLab project. Returns one page of tree nodes (files and directories) with name, path, type, and mode, in the standard items/pagination envelope. Symlinks (mode 120000) and executables (mode 100755) are flagged; set resolve_symlinks to also get each symlink's target path. Use this to explore directory structure before fetching specific files with get_file_contents. On large repositories, narrow a recursive listing with max_depth and glob; they filter each page before it is returned, so keep following pagination.next_page until it is empty.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -555,6 +714,31 @@ func registerListProjectMembers(server *mcp.Server) {
 						Type:        "string",
 						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
 					},
+					"path": {
+						Type:        "string",
+						Description: "Path inside repository to list (default: root)",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Branch name, tag, or commit SHA (default: default branch)",
+					},
+					"recursive": {
+						Type:        "boolean",
+						Description: "Get tree recursively",
+					},
+					"max_depth": {
+						Type:        "integer",
+						Description: "Only return entries at most this many levels below path (1 = direct children). Implies recursive",
+						Minimum:     mcp.IntPtr(1),
+					},
+					"glob": {
+						Type:        "string",
+						Description: "Only return entries whose path matches this glob: * and ? stay within a directory, ** spans directories (e.g., **/*.tf or services/*/Dockerfile). A glob without a slash matches the file name in any directory",
+					},
+					"resolve_symlinks": {
+						Type:        "boolean",
+						Description: "Read the target path of each symlink returned (one extra request per symlink)",
+					},
 					"page": {
 						Type:        "integer",
 						Description: "Page number for pagination",
@@ -575,38 +759,136 @@ func registerListProjectMembers(server *mcp.Server) {
 				ReadOnlyHint: true,
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
-			if c == nil {
-				return ErrorResult("tool context not initialized")
-			}
-			c.Logger.ToolCall("list_project_members", args)
-
-			projectID := GetString(args, "project_id", "")
-			if projectID == "" {
-				return ErrorResult("project_id is required")
+		withArgs("get_repository_tree", func(ctx context.Context, c *ToolContext, args repositoryTreeArgs) (*mcp.CallToolResult, error) {
+			var glob *regexp.Regexp
+			if args.Glob != "" {
+				var err error
+				if glob, err = globRegexp(args.Glob); err != nil {
+					return ErrorResult(fmt.Sprintf("invalid glob %q: %v", args.Glob, err))
+				}
 			}
 
 			params := url.Values{}
 
-			if page := GetInt(args, "page", 0); page > 0 {
-				params.Set("page", fmt.Sprintf("%d", page))
+			args.setParams(params)
+			if args.Path != "" {
+				params.Set("path", args.Path)
 			}
-			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
-				params.Set("per_page", fmt.Sprintf("%d", perPage))
+			if args.Ref != "" {
+				params.Set("ref", args.Ref)
+			}
+			if args.Recursive || args.MaxDepth > 0 {
+				params.Set("recursive", "true")
 			}
 
-			endpoint := fmt.Sprintf("/projects/%s/members", url.PathEscape(projectID))
+			endpoint := fmt.Sprintf("/projects/%s/repository/tree", url.PathEscape(args.ProjectID))
 			if len(params) > 0 {
 				endpoint = fmt.Sprintf("%s?%s", endpoint, params.Encode())
 			}
 
-			var members []Member
-			if err := c.Client.Get(endpoint, &members); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list project members: %v", err))
+			var treeNodes []gitlab.TreeNode
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &treeNodes)
+			if err != nil {
+				return APIErrorResult("Failed to get repository tree", err)
 			}
 
-			return JSONResult(members)
-		},
+			base := strings.Trim(args.Path, "/")
+			filtered := make([]TreeEntry, 0, len(treeNodes))
+			for _, node := range treeNodes {
+				if args.MaxDepth > 0 && treeDepth(base, node.Path) > args.MaxDepth {
+					continue
+				}
+				if glob != nil && !glob.MatchString(node.Path) {
+					continue
+				}
+				entry := newTreeEntry(node)
+				if entry.Symlink && args.ResolveSymlinks {
+					target, err := c.Client.GetText(ctx, fmt.Sprintf("/projects/%s/repository/blobs/%s/raw", url.PathEscape(args.ProjectID), node.ID))
+					if err != nil {
+						return APIErrorResult(fmt.Sprintf("Failed to read symlink %s", node.Path), err)
+					}
+					entry.SymlinkTarget = target
+				}
+				filtered = append(filtered, entry)
+			}
+
+			return PagedJSONResult(filtered, pagination)
+		}),
 	)
 }
+
+// Git file modes of tree entries that are not plain files or directories.
+const (
+	fileModeSymlink    = "120000"
+	fileModeExecutable = "100755"
+)
+
+// TreeEntry is a repository tree node with its mode spelled out, so a
+// symlink is not mistaken for a regular file holding a path.
+type TreeEntry struct {
+	gitlab.TreeNode
+	Symlink       bool   `json:"symlink,omitempty"`
+	Executable    bool   `json:"executable,omitempty"`
+	SymlinkTarget string `json:"symlink_target,omitempty"`
+}
+
+// newTreeEntry flags the mode of a tree node.
+func newTreeEntry(node gitlab.TreeNode) TreeEntry {
+	return TreeEntry{
+		TreeNode:   node,
+		Symlink:    node.Mode == fileModeSymlink,
+		Executable: node.Mode == fileModeExecutable,
+	}
+}
+
+// treeDepth returns how many levels below the directory base a tree entry
+// is, 1 for its direct children.
+func treeDepth(base, entryPath string) int {
+	rel := entryPath
+	if base != "" {
+		rel = strings.TrimPrefix(entryPath, base+"/")
+	}
+	return strings.Count(rel, "/") + 1
+}
+
+// globRegexp compiles a path glob: * and ? match within a path segment and
+// ** spans segments. A glob without a slash matches the last segment, so
+// *.tf finds Terraform files in any directory.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimPrefix(pattern, "/")
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !strings.Contains(p, "/") {
+		sb.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case p[i] == '*':
+			sb.WriteString("[^/]*")
+		case p[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// Member represents a project or group member with access level information
+type Member struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	AvatarURL   string `json:"avatar_url"`
+	WebURL      string `json:"web_url"`
+	AccessLevel int    `json:"access_level"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+}
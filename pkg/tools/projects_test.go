@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+func TestGetRepositoryTreeFilters(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/42/repository/tree?page=2&path=infra&per_page=50&recursive=true", 200, `[
+		{"id": "1", "name": "main.tf", "type": "blob", "path": "infra/main.tf", "mode": "100644"},
+		{"id": "2", "name": "prod", "type": "tree", "path": "infra/prod", "mode": "040000"},
+		{"id": "3", "name": "vars.tf", "type": "blob", "path": "infra/prod/vars.tf", "mode": "100644"},
+		{"id": "4", "name": "db.tf", "type": "blob", "path": "infra/prod/modules/db.tf", "mode": "100644"},
+		{"id": "5", "name": "README.md", "type": "blob", "path": "infra/prod/README.md", "mode": "100644"}
+	]`)
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{"no filter", map[string]interface{}{"recursive": true}, []string{"infra/main.tf", "infra/prod", "infra/prod/vars.tf", "infra/prod/modules/db.tf", "infra/prod/README.md"}},
+		{"max depth", map[string]interface{}{"max_depth": 2}, []string{"infra/main.tf", "infra/prod", "infra/prod/vars.tf", "infra/prod/README.md"}},
+		{"file name glob", map[string]interface{}{"recursive": true, "glob": "*.tf"}, []string{"infra/main.tf", "infra/prod/vars.tf", "infra/prod/modules/db.tf"}},
+		{"path glob", map[string]interface{}{"recursive": true, "glob": "infra/*/*.tf"}, []string{"infra/prod/vars.tf"}},
+		{"double star", map[string]interface{}{"recursive": true, "glob": "infra/prod/**/*.tf", "max_depth": 2}, []string{"infra/prod/vars.tf"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]interface{}{"project_id": "42", "path": "infra", "page": 2, "per_page": 50}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			result := callTool(t, tc, "get_repository_tree", args)
+			if result.IsError {
+				t.Fatalf("unexpected error: %s", resultText(t, result))
+			}
+			var page struct {
+				Items []gitlab.TreeNode `json:"items"`
+			}
+			if err := json.Unmarshal([]byte(resultText(t, result)), &page); err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, node := range page.Items {
+				paths = append(paths, node.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("paths = %v, want %v", paths, tt.want)
+			}
+		})
+	}
+	if unmatched := client.Unmatched(); len(unmatched) > 0 {
+		t.Errorf("unmatched requests: %v", unmatched)
+	}
+}
+
+func TestSymlinkModes(t *testing.T) {
+	tc, client := newTestContext(t)
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	docs := `[
+		{"id": "aaa", "name": "README.md", "type": "blob", "path": "docs/README.md", "mode": "120000"},
+		{"id": "bbb", "name": "build.sh", "type": "blob", "path": "docs/build.sh", "mode": "100755"},
+		{"id": "ccc", "name": "outside", "type": "blob", "path": "docs/outside", "mode": "120000"}
+	]`
+	client.Handle("GET", "/projects/42/repository/tree?path=docs&ref=main", 200, docs)
+	client.Handle("GET", "/projects/42/repository/tree?path=docs&ref=main&page=1&per_page=100", 200, docs)
+	client.Handle("GET", "/projects/42/repository/tree?ref=main&page=1&per_page=100", 200, `[
+		{"id": "ddd", "name": "README.md", "type": "blob", "path": "README.md", "mode": "100644"}
+	]`)
+	client.Handle("GET", "/projects/42/repository/blobs/aaa/raw", 200, "../README.md")
+	client.Handle("GET", "/projects/42/repository/files/docs%2FREADME.md?ref=main", 200,
+		`{"file_name": "README.md", "file_path": "docs/README.md", "ref": "main", "content": "`+encode("../README.md")+`"}`)
+	client.Handle("GET", "/projects/42/repository/files/README.md?ref=main", 200,
+		`{"file_name": "README.md", "file_path": "README.md", "ref": "main", "blob_id": "ddd", "content": "`+encode("# Project")+`"}`)
+	client.Handle("GET", "/projects/42/repository/files/docs%2Foutside?ref=main", 200,
+		`{"file_name": "outside", "file_path": "docs/outside", "ref": "main", "content": "`+encode("/etc/passwd")+`"}`)
+
+	result := callTool(t, tc, "get_repository_tree", map[string]interface{}{"project_id": "42", "path": "docs", "ref": "main", "glob": "*.*", "resolve_symlinks": true})
+	var page struct {
+		Items []TreeEntry `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, result)), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || !page.Items[0].Symlink || page.Items[0].SymlinkTarget != "../README.md" || !page.Items[1].Executable || page.Items[1].Symlink {
+		t.Errorf("tree = %+v", page.Items)
+	}
+
+	var file map[string]interface{}
+	result = callTool(t, tc, "get_file_contents", map[string]interface{}{"project_id": "42", "file_path": "docs/README.md", "ref": "main", "follow_symlinks": true})
+	if err := json.Unmarshal([]byte(resultText(t, result)), &file); err != nil {
+		t.Fatal(err)
+	}
+	if file["content"] != "# Project" || file["mode"] != "120000" || file["symlink_target"] != "../README.md" || file["resolved_path"] != "README.md" || file["blob_id"] != "ddd" {
+		t.Errorf("followed file = %v", file)
+	}
+
+	file = nil
+	result = callTool(t, tc, "get_file_contents", map[string]interface{}{"project_id": "42", "file_path": "docs/outside", "ref": "main", "follow_symlinks": true})
+	if err := json.Unmarshal([]byte(resultText(t, result)), &file); err != nil {
+		t.Fatal(err)
+	}
+	if file["content"] != "/etc/passwd" || !strings.Contains(fmt.Sprint(file["note"]), "outside the repository") {
+		t.Errorf("outside file = %v", file)
+	}
+	if unmatched := client.Unmatched(); len(unmatched) > 0 {
+		t.Errorf("unmatched requests: %v", unmatched)
+	}
+}
+
+func TestListProjectsDefaultNamespaces(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.DefaultNamespaces = []string{"team-a", "team-b"}
+	client.Handle("GET", "/groups/team-a/projects", 200, `[{"id": 1, "path_with_namespace": "team-a/api"}, {"id": 3, "path_with_namespace": "team-a/shared"}]`)
+	client.Handle("GET", "/groups/team-b/projects", 200, `[{"id": 2, "path_with_namespace": "team-b/web"}, {"id": 3, "path_with_namespace": "team-a/shared"}]`)
+
+	result := callTool(t, tc, "list_projects", map[string]interface{}{"search": "a"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	var list struct {
+		Items []gitlab.Project `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, result)), &list); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, project := range list.Items {
+		got = append(got, project.PathWithNamespace)
+	}
+	if want := []string{"team-a/api", "team-a/shared", "team-b/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("projects = %v, want %v", got, want)
+	}
+	for _, req := range client.Requests() {
+		query, _ := url.ParseQuery(strings.SplitN(req.Endpoint, "?", 2)[1])
+		if query.Get("include_subgroups") != "true" || query.Get("search") != "a" {
+			t.Errorf("%s: query = %v", req.Endpoint, query)
+		}
+	}
+
+	result = callTool(t, tc, "list_projects", map[string]interface{}{"namespace": "team-b"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if requests := client.Requests(); !strings.HasPrefix(requests[len(requests)-1].Endpoint, "/groups/team-b/projects?") || len(requests) != 3 {
+		t.Errorf("requests = %+v, want one more to team-b", requests)
+	}
+}
+
+func TestListGroupProjectsFilters(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/groups/acme/projects", 200, `[{"id": 1, "path_with_namespace": "acme/platform/api"}]`)
+
+	result := callTool(t, tc, "list_group_projects", map[string]interface{}{
+		"group_id": "acme", "include_subgroups": true, "with_shared": false, "min_access_level": 30, "order_by": "name", "sort": "asc",
+	})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	query, _ := url.ParseQuery(strings.SplitN(client.Requests()[0].Endpoint, "?", 2)[1])
+	want := url.Values{
+		"include_subgroups": {"true"}, "with_shared": {"false"}, "min_access_level": {"30"},
+		"order_by": {"name"}, "sort": {"asc"},
+	}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("query = %v, want %v", query, want)
+	}
+
+	result = callTool(t, tc, "list_group_projects", map[string]interface{}{"group_id": "acme", "min_access_level": 60})
+	if !result.IsError || !strings.Contains(resultText(t, result), "min_access_level must be at most 50") {
+		t.Errorf("result with a bad access level = %s", resultText(t, result))
+	}
+}
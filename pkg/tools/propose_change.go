@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// ProposedChange is the response of the propose_change tool.
+type ProposedChange struct {
+	Branch       *gitlab.Branch       `json:"branch"`
+	Commit       *CommitResponse      `json:"commit"`
+	MergeRequest *gitlab.MergeRequest `json:"merge_request"`
+}
+
+// registerProposeChange registers the propose_change tool.
+func registerProposeChange(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "propose_change",
+			Description: "Propose a change in one call: create a branch, commit file actions to it and open a merge request (optionally as draft). Returns the branch, commit and merge request. If the commit fails the new branch is deleted again; if only the merge request fails the branch and commit are kept and reported.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: withMergeRequestAttributes(map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"branch": {
+						Type:        "string",
+						Description: "The name of the new branch to create",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "The branch name or commit SHA to create the branch from (optional, default: the project's default branch)",
+					},
+					"commit_message": {
+						Type:        "string",
+						Description: "The commit message",
+					},
+					"actions": commitActionsProperty,
+					"author_email": {
+						Type:        "string",
+						Description: "The commit author's email address (optional)",
+					},
+					"author_name": {
+						Type:        "string",
+						Description: "The commit author's name (optional)",
+					},
+					"title": {
+						Type:        "string",
+						Description: "The title of the merge request",
+					},
+					"description": {
+						Type:        "string",
+						Description: "The description of the merge request (optional)",
+					},
+					"target_branch": {
+						Type:        "string",
+						Description: "The target branch of the merge request (optional, default: ref, or the project's default branch)",
+					},
+					"draft": {
+						Type:        "boolean",
+						Description: "Open the merge request as a draft (optional, default: false)",
+					},
+					"remove_source_branch": {
+						Type:        "boolean",
+						Description: "Flag indicating if the source branch should be removed when the MR is merged (optional)",
+					},
+				}),
+				Required: []string{"project_id", "branch", "commit_message", "actions", "title"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "propose_change", args)
+
+			// Check read-only mode
+			if c.Config != nil && c.Config.ReadOnlyMode {
+				return ErrorResult("cannot propose change: server is in read-only mode")
+			}
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			branch := GetString(args, "branch", "")
+			if branch == "" {
+				return ErrorResult("branch is required")
+			}
+			commitMessage := GetString(args, "commit_message", "")
+			if commitMessage == "" {
+				return ErrorResult("commit_message is required")
+			}
+			actionsRaw, ok := args["actions"]
+			if !ok {
+				return ErrorResult("actions is required")
+			}
+			title := GetString(args, "title", "")
+			if title == "" {
+				return ErrorResult("title is required")
+			}
+
+			actions, err := parseCommitActions(actionsRaw)
+			if err != nil {
+				return APIErrorResult("Invalid actions parameter", err)
+			}
+
+			attributes := map[string]interface{}{}
+			setMergeRequestAttributes(args, attributes)
+			change, message, err := proposeChange(ctx, c, changeProposal{
+				ProjectID:          projectID,
+				Branch:             branch,
+				Ref:                GetString(args, "ref", ""),
+				TargetBranch:       GetString(args, "target_branch", ""),
+				CommitMessage:      commitMessage,
+				Actions:            actions,
+				AuthorEmail:        GetString(args, "author_email", ""),
+				AuthorName:         GetString(args, "author_name", ""),
+				Title:              title,
+				Description:        GetString(args, "description", ""),
+				Draft:              GetBool(args, "draft", false),
+				RemoveSourceBranch: GetBool(args, "remove_source_branch", false),
+				Attributes:         attributes,
+			})
+			if err != nil {
+				return APIErrorResult(message, err)
+			}
+			return JSONResult(change)
+		},
+	)
+}
+
+// changeProposal is a change for proposeChange to commit to a new branch and
+// open a merge request for.
+type changeProposal struct {
+	ProjectID string
+	Branch    string
+	// Ref is the branch or commit the new branch is created from, the
+	// project's default branch if empty
+	Ref string
+	// TargetBranch is the target of the merge request, Ref if empty
+	TargetBranch       string
+	CommitMessage      string
+	Actions            []CommitAction
+	AuthorEmail        string
+	AuthorName         string
+	Title              string
+	Description        string
+	Draft              bool
+	RemoveSourceBranch bool
+	// Attributes are further merge request attributes (assignees, labels, ...)
+	Attributes map[string]interface{}
+}
+
+// proposeChange creates the branch of p, commits its actions and opens the
+// merge request, reporting progress along the way. If the commit fails the new
+// branch is deleted again. On failure it returns the message to report the
+// error with.
+func proposeChange(ctx context.Context, c *ToolContext, p changeProposal) (*ProposedChange, string, error) {
+	encodeCommitActions(p.Actions)
+	encodedProjectID := url.PathEscape(p.ProjectID)
+	const totalSteps = 3
+
+	ref, targetBranch := p.Ref, p.TargetBranch
+	if targetBranch == "" {
+		targetBranch = ref
+	}
+	if ref == "" || targetBranch == "" {
+		var project gitlab.Project
+		if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s", encodedProjectID), &project); err != nil {
+			return nil, "Failed to get project default branch", err
+		}
+		if ref == "" {
+			ref = project.DefaultBranch
+		}
+		if targetBranch == "" {
+			targetBranch = project.DefaultBranch
+		}
+	}
+
+	mcp.ReportProgress(ctx, 0, totalSteps, fmt.Sprintf("Creating branch %s from %s", p.Branch, ref))
+	var createdBranch gitlab.Branch
+	branchBody := map[string]string{
+		"branch": p.Branch,
+		"ref":    ref,
+	}
+	if err := c.Client.Post(ctx, fmt.Sprintf("/projects/%s/repository/branches", encodedProjectID), branchBody, &createdBranch); err != nil {
+		return nil, "Failed to create branch", err
+	}
+
+	mcp.ReportProgress(ctx, 1, totalSteps, fmt.Sprintf("Committing %d file action(s)", len(p.Actions)))
+	commitRequest := CommitRequest{
+		Branch:        p.Branch,
+		CommitMessage: p.CommitMessage,
+		Actions:       p.Actions,
+		AuthorEmail:   p.AuthorEmail,
+		AuthorName:    p.AuthorName,
+	}
+	var commit CommitResponse
+	if err := c.Client.Post(ctx, fmt.Sprintf("/projects/%s/repository/commits", encodedProjectID), commitRequest, &commit); err != nil {
+		// Remove the empty branch so the call can be retried as is
+		branchEndpoint := fmt.Sprintf("/projects/%s/repository/branches/%s", encodedProjectID, url.PathEscape(p.Branch))
+		if deleteErr := c.Client.Delete(ctx, branchEndpoint); deleteErr != nil {
+			return nil, fmt.Sprintf("Failed to commit files (branch %s was created and could not be deleted: %v)", p.Branch, deleteErr), err
+		}
+		return nil, "Failed to commit files", err
+	}
+
+	mcp.ReportProgress(ctx, 2, totalSteps, fmt.Sprintf("Opening merge request into %s", targetBranch))
+	title := p.Title
+	if p.Draft && !isDraftTitle(title) {
+		title = "Draft: " + title
+	}
+	mrBody := map[string]interface{}{}
+	for key, value := range p.Attributes {
+		mrBody[key] = value
+	}
+	mrBody["source_branch"] = p.Branch
+	mrBody["target_branch"] = targetBranch
+	mrBody["title"] = title
+	if p.Description != "" {
+		mrBody["description"] = p.Description
+	}
+	if p.RemoveSourceBranch {
+		mrBody["remove_source_branch"] = true
+	}
+
+	var mr gitlab.MergeRequest
+	if err := c.Client.Post(ctx, fmt.Sprintf("/projects/%s/merge_requests", encodedProjectID), mrBody, &mr); err != nil {
+		return nil, fmt.Sprintf("Failed to create merge request (branch %s and commit %s were created)", p.Branch, commit.ShortID), err
+	}
+	mcp.ReportProgress(ctx, totalSteps, totalSteps, "Change proposed")
+
+	return &ProposedChange{
+		Branch:       &createdBranch,
+		Commit:       &commit,
+		MergeRequest: &mr,
+	}, "", nil
+}
+
+// isDraftTitle reports whether a merge request title already marks it as a draft.
+func isDraftTitle(title string) bool {
+	lower := strings.ToLower(title)
+	for _, prefix := range []string{"draft:", "[draft]", "(draft)", "wip:", "[wip]"} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
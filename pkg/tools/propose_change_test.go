@@ -0,0 +1,24 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProposeChangeReadOnlyMode(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.ReadOnlyMode = true
+	result := callTool(t, tc, "propose_change", map[string]interface{}{
+		"project_id":     "42",
+		"branch":         "fix-typo",
+		"commit_message": "Fix typo",
+		"title":          "Fix typo",
+		"actions":        []interface{}{map[string]interface{}{"action": "update", "file_path": "README.md", "content": "fixed"}},
+	})
+	if !result.IsError || !strings.Contains(resultText(t, result), "server is in read-only mode") {
+		t.Errorf("propose_change in read-only mode = %q, want a read-only error", resultText(t, result))
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("read-only mode sent %d requests, want none", len(requests))
+	}
+}
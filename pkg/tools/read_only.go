@@ -0,0 +1,31 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// readOnlyModeChecked are tools that only change GitLab for some arguments
+// and check read-only mode themselves (e.g. suggest_reviewers with assign).
+var readOnlyModeChecked = map[string]bool{
+	"suggest_reviewers": true,
+}
+
+// ReadOnlyModeMiddleware rejects calls to tools that are not read-only (see
+// isReadOnlyTool) while GITLAB_READ_ONLY_MODE is set. The mode is read on
+// every call, so a configuration reload applies at once. Some tools also check the
+// mode themselves, for servers built without this middleware.
+func ReadOnlyModeMiddleware(tool mcp.Tool, handler mcp.ToolHandler) (mcp.Tool, mcp.ToolHandler) {
+	if isReadOnlyTool(tool) || readOnlyModeChecked[tool.Name] {
+		return tool, handler
+	}
+	wrapped := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		if c := FromContext(ctx); c != nil && c.Config != nil && c.Config.ReadOnlyMode {
+			return ErrorResult(fmt.Sprintf("cannot call %s: server is in read-only mode", tool.Name))
+		}
+		return handler(ctx, args)
+	}
+	return tool, wrapped
+}
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+func TestReadOnlyModeMiddleware(t *testing.T) {
+	tc, _ := newTestContext(t)
+	ctx := WithToolContext(context.Background(), tc)
+	ok := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return TextResult("ok")
+	}
+
+	tests := []struct {
+		tool     mcp.Tool
+		rejected bool
+	}{
+		{mcp.Tool{Name: "create_issue"}, true},
+		{mcp.Tool{Name: "delete_note"}, true},
+		{mcp.Tool{Name: "get_issue"}, false},
+		{mcp.Tool{Name: "mr_discussions"}, false},
+		{mcp.Tool{Name: "download_release_asset", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}}, false},
+		// Checks the mode itself, as only assign=true writes
+		{mcp.Tool{Name: "suggest_reviewers"}, false},
+	}
+	for _, tt := range tests {
+		_, handler := ReadOnlyModeMiddleware(tt.tool, ok)
+
+		tc.Config.ReadOnlyMode = false
+		if result, _ := handler(ctx, nil); result.IsError {
+			t.Errorf("%s outside read-only mode failed: %s", tt.tool.Name, resultText(t, result))
+		}
+
+		// The mode is read per call, so a reload applies to registered tools
+		tc.Config.ReadOnlyMode = true
+		result, _ := handler(ctx, nil)
+		text := resultText(t, result)
+		if rejected := result.IsError && strings.Contains(text, "read-only mode"); rejected != tt.rejected {
+			t.Errorf("%s in read-only mode = %q, want rejected %v", tt.tool.Name, text, tt.rejected)
+		}
+	}
+}
+
+func TestGeneratedWriteToolsReadOnlyMode(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.ReadOnlyMode = true
+	for _, call := range []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"create_broadcast_message", map[string]interface{}{"message": "Maintenance at 22:00"}},
+		{"update_broadcast_message", map[string]interface{}{"broadcast_message_id": 1, "message": "Moved to 23:00"}},
+		{"delete_broadcast_message", map[string]interface{}{"broadcast_message_id": 1}},
+		{"delete_fork_relationship", map[string]interface{}{"project_id": "42"}},
+	} {
+		result := callTool(t, tc, call.name, call.args)
+		if !result.IsError || !strings.Contains(resultText(t, result), "server is in read-only mode") {
+			t.Errorf("%s in read-only mode = %q, want a read-only error", call.name, resultText(t, result))
+		}
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("read-only mode sent %d requests, want none", len(requests))
+	}
+
+	// Generated read tools are unaffected
+	client.Handle(http.MethodGet, "/broadcast_messages/1", http.StatusOK, map[string]interface{}{"id": 1})
+	if result := callTool(t, tc, "get_broadcast_message", map[string]interface{}{"broadcast_message_id": 1}); strings.Contains(resultText(t, result), "read-only mode") {
+		t.Errorf("get_broadcast_message in read-only mode = %q", resultText(t, result))
+	}
+}
+
+func TestSuggestReviewersAssignReadOnlyMode(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.ReadOnlyMode = true
+	result := callTool(t, tc, "suggest_reviewers", map[string]interface{}{
+		"project_id":        "42",
+		"merge_request_iid": 5,
+		"assign":            true,
+	})
+	if !result.IsError || !strings.Contains(resultText(t, result), "read-only mode") {
+		t.Errorf("suggest_reviewers with assign in read-only mode = %q, want a read-only error", resultText(t, result))
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("read-only mode sent %d requests, want none", len(requests))
+	}
+}
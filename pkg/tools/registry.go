@@ -1,7 +1,8 @@
 package tools
 
 import (
-	"sync"
+	"context"
+	"fmt"
 
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
@@ -9,53 +10,90 @@ import (
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
 )
 
-// Context holds shared dependencies for tool handlers.
+// ToolContext holds shared dependencies for tool handlers.
 // It provides access to the GitLab client, logger, and configuration
-// that all tool handlers need.
-type Context struct {
-	Client *gitlab.Client
+// that all tool handlers need. Handlers read it from their request context
+// (see FromContext), so several servers with different GitLab clients can
+// run in one process.
+type ToolContext struct {
+	Client gitlab.API
 	Logger *logging.Logger
 	Config *config.Config
+
+	// policies are applied to every tool call, in order (see ConfigurePolicies)
+	policies []namedPolicy
+	// sessions holds the defaults set with set_default_project
+	sessions *sessionProjects
 }
 
-var (
-	// ctx is the global context for tool handlers
-	ctx  *Context
-	ctxMu sync.RWMutex
-)
+// NewToolContext returns a ToolContext for client. A nil logger discards
+// log output; a nil cfg is an empty configuration (all optional features off).
+// No policies are active until ConfigurePolicies is called.
+func NewToolContext(client gitlab.API, logger *logging.Logger, cfg *config.Config) *ToolContext {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	return &ToolContext{Client: client, Logger: logger, Config: cfg, sessions: newSessionProjects()}
+}
 
-// SetContext initializes the global tool context with the provided dependencies.
-// This should be called once during server initialization before any tools are invoked.
-func SetContext(client *gitlab.Client, logger *logging.Logger, cfg *config.Config) {
-	ctxMu.Lock()
-	defer ctxMu.Unlock()
-	ctx = &Context{
-		Client: client,
-		Logger: logger,
-		Config: cfg,
+// Reconfigure returns a ToolContext for cfg with the client and logger of c,
+// its policies configured. Session defaults are shared with c, so they
+// survive a configuration reload.
+func (c *ToolContext) Reconfigure(cfg *config.Config) (*ToolContext, error) {
+	next := NewToolContext(c.Client, c.Logger, cfg)
+	next.sessions = c.sessions
+	if err := next.ConfigurePolicies(); err != nil {
+		return nil, err
 	}
+	return next, nil
+}
+
+// toolContextKey is the context key of the ToolContext.
+type toolContextKey struct{}
+
+// WithToolContext returns a context whose tool calls use tc.
+func WithToolContext(ctx context.Context, tc *ToolContext) context.Context {
+	return context.WithValue(ctx, toolContextKey{}, tc)
 }
 
-// GetContext returns the global tool context.
-// Returns nil if SetContext has not been called.
-func GetContext() *Context {
-	ctxMu.RLock()
-	defer ctxMu.RUnlock()
-	return ctx
+// FromContext returns the ToolContext of a tool call, or nil if the server
+// was not set up with Register.
+func FromContext(ctx context.Context) *ToolContext {
+	tc, _ := ctx.Value(toolContextKey{}).(*ToolContext)
+	return tc
+}
+
+// Bind makes every request handled by server use tc, replacing any earlier
+// binding. Register calls it; servers that re-register tools with
+// mcp.Server.ReplaceTools call it before RegisterGroups when tc changes.
+func Bind(server *mcp.Server, tc *ToolContext) {
+	server.SetContextFunc(func(ctx context.Context) context.Context {
+		return WithToolContext(ctx, tc)
+	})
 }
 
 // RegisterProjectTools registers all project-related tools with the MCP server.
 // Includes: get_project, list_projects, search_repositories, create_repository,
-// fork_repository, list_group_projects, get_repository_tree, list_project_members
+// fork_repository, list_project_forks, get_fork_relationship, delete_fork_relationship,
+// list_group_projects, get_repository_tree, list_project_members, get_project_avatar,
+// set_default_project, resolve_project, get_project_statistics, get_group_statistics
 func RegisterProjectTools(server *mcp.Server) {
 	registerGetProject(server)
 	registerListProjects(server)
 	registerSearchRepositories(server)
 	registerCreateRepository(server)
 	registerForkRepository(server)
+	registerListProjectForks(server)
+	registerGetForkRelationship(server)
+	registerDeleteForkRelationship(server)
 	registerListGroupProjects(server)
 	registerGetRepositoryTree(server)
 	registerListProjectMembers(server)
+	registerGetProjectAvatar(server)
+	registerSetDefaultProject(server)
+	registerResolveProject(server)
+	registerGetProjectStatistics(server)
+	registerGetGroupStatistics(server)
 }
 
 // Note: RegisterFileTools is implemented in files.go with signature:
@@ -65,7 +103,8 @@ func RegisterProjectTools(server *mcp.Server) {
 // RegisterIssueTools(server *mcp.Server, ctx *ToolContext)
 
 // RegisterMergeRequestTools registers all merge request-related tools with the MCP server.
-// Includes: list_merge_requests, get_merge_request, create_merge_request, update_merge_request, etc.
+// Includes: list_merge_requests, list_group_merge_requests, my_merge_requests, get_merge_request,
+// create_merge_request, update_merge_request, etc.
 func RegisterMergeRequestTools(server *mcp.Server) {
 	initMergeRequestTools(server)
 }
@@ -80,88 +119,248 @@ func RegisterLabelTools(server *mcp.Server) {
 }
 
 // RegisterNamespaceTools registers all namespace-related tools with the MCP server.
-// Includes: list_namespaces, get_namespace, verify_namespace
+// Includes: list_namespaces, get_namespace, get_namespace_usage, verify_namespace
 func RegisterNamespaceTools(server *mcp.Server) {
 	initNamespaceTools(server)
 }
 
 // RegisterUserTools registers all user-related tools with the MCP server.
-// Includes: get_users
+// Includes: get_users, get_user_by_username, list_users, list_ssh_keys,
+// add_ssh_key, delete_ssh_key, list_gpg_keys, add_gpg_key, delete_gpg_key
 func RegisterUserTools(server *mcp.Server) {
 	initUserTools(server)
 }
 
 // RegisterEventTools registers all event-related tools with the MCP server.
-// Includes: list_events, get_project_events
+// Includes: list_events, get_project_events, get_user_contribution_events
 func RegisterEventTools(server *mcp.Server) {
 	initEventTools(server)
 }
 
 // RegisterReleaseTools registers all release-related tools with the MCP server.
 // Includes: get_release, create_release, update_release, delete_release,
-// create_release_evidence, download_release_asset
+// create_release_evidence, download_release_asset, list_freeze_periods, create_freeze_period,
+// delete_freeze_period, get_deploy_freeze_status, upload_generic_package
 // Note: list_releases is registered via RegisterBranchTools
 func RegisterReleaseTools(server *mcp.Server) {
 	initReleaseTools(server)
 }
 
+// RegisterDiagnosticTools registers server diagnostic tools with the MCP server.
+// Includes: get_rate_limit_status, gitlab_connectivity_check, get_server_stats
+func RegisterDiagnosticTools(server *mcp.Server) {
+	initDiagnosticTools(server)
+}
+
+// RegisterAdminTools registers the instance administration tools with the MCP
+// server. Apart from get_gitlab_version they need an administrator token.
+// Includes: get_gitlab_version, get_instance_settings, list_broadcast_messages,
+// get_broadcast_message, create_broadcast_message, update_broadcast_message,
+// delete_broadcast_message
+func RegisterAdminTools(server *mcp.Server) {
+	initAdminTools(server)
+}
+
 // RegisterPipelineTools registers all pipeline-related tools with the MCP server.
-// This is a feature-flagged tool set, only registered when USE_PIPELINE is enabled.
+// This is a feature-flagged tool set: Register includes it by default only when
+// USE_PIPELINE is enabled. Custom log extractors from the config file are
+// offered only when it is registered through Register.
 // Includes: list_pipelines, get_pipeline, create_pipeline, retry_pipeline, cancel_pipeline,
 // list_pipeline_jobs, list_pipeline_trigger_jobs, get_pipeline_job, get_pipeline_job_output,
-// play_pipeline_job, retry_pipeline_job, cancel_pipeline_job
+// play_pipeline_job, retry_pipeline_job, cancel_pipeline_job, get_latest_release_pipeline,
+// get_pipeline_badge, post_pipeline_analysis_comment, list_project_jobs, resolve_ci_variables,
+// get_merged_ci_config, simulate_pipeline, get_ci_settings, update_ci_settings
 func RegisterPipelineTools(server *mcp.Server) {
-	// Check if pipeline feature is enabled
-	c := GetContext()
-	if c == nil || c.Config == nil || !c.Config.UsePipeline {
-		return
-	}
-	initPipelineTools(server)
+	initPipelineTools(server, nil)
 }
 
 // RegisterMilestoneTools registers all milestone-related tools with the MCP server.
-// This is a feature-flagged tool set, only registered when USE_MILESTONE is enabled.
+// This is a feature-flagged tool set: Register includes it by default only when
+// USE_MILESTONE is enabled.
 // Includes: list_milestones, get_milestone, create_milestone, edit_milestone, delete_milestone,
-// get_milestone_issues, get_milestone_merge_requests, promote_milestone, get_milestone_burndown_events
+// get_milestone_issues, get_milestone_merge_requests, promote_milestone, get_milestone_burndown_events,
+// get_milestone_burndown
 func RegisterMilestoneTools(server *mcp.Server) {
-	// Check if milestone feature is enabled
-	c := GetContext()
-	if c == nil || c.Config == nil || !c.Config.UseMilestone {
-		return
-	}
 	initMilestoneTools(server)
 }
 
 // RegisterWikiTools registers all wiki-related tools with the MCP server.
-// This is a feature-flagged tool set, only registered when USE_GITLAB_WIKI is enabled.
+// This is a feature-flagged tool set: Register includes it by default only when
+// USE_GITLAB_WIKI is enabled.
 // Includes: list_wiki_pages, get_wiki_page, create_wiki_page, update_wiki_page, delete_wiki_page,
-// upload_wiki_attachment
+// upload_wiki_attachment, get_wiki_page_versions, diff_wiki_page_versions, and the matching group wiki tools (list_group_wiki_pages, get_group_wiki_page,
+// create_group_wiki_page, update_group_wiki_page, delete_group_wiki_page, upload_group_wiki_attachment)
 func RegisterWikiTools(server *mcp.Server) {
-	// Check if wiki feature is enabled
-	c := GetContext()
-	if c == nil || c.Config == nil || !c.Config.UseWiki {
-		return
-	}
 	initWikiTools(server)
 }
 
-// RegisterAllTools is a convenience function that registers all available tools.
-// It respects feature flags for optional tool sets.
-func RegisterAllTools(server *mcp.Server) {
+// RegisterImportExportTools registers project import and export tools with the MCP server.
+// Includes: schedule_project_export, get_project_export_status, download_project_export,
+// import_project_from_file, import_project_from_url, get_project_import_status
+func RegisterImportExportTools(server *mcp.Server) {
+	initImportExportTools(server)
+}
+
+// RegisterMirrorTools registers repository mirror tools with the MCP server.
+// Includes: list_push_mirrors, create_push_mirror, update_push_mirror, sync_push_mirror,
+// get_pull_mirror_status
+func RegisterMirrorTools(server *mcp.Server) {
+	initMirrorTools(server)
+}
+
+// RegisterKubernetesTools registers GitLab agent for Kubernetes tools with the MCP server.
+// Includes: list_cluster_agents, get_cluster_agent_config, get_agent_tokens,
+// create_agent_token, revoke_agent_token
+func RegisterKubernetesTools(server *mcp.Server) {
+	initKubernetesTools(server)
+}
+
+// RegisterTemplateTools registers project template tools with the MCP server.
+// Includes: list_project_templates, get_project_template, create_issue_from_template
+func RegisterTemplateTools(server *mcp.Server) {
+	initTemplateTools(server)
+}
+
+// RegisterAnalyticsTools registers delivery analytics tools with the MCP server.
+// Includes: get_dora_metrics, get_value_stream_summary, mr_cycle_time_report
+func RegisterAnalyticsTools(server *mcp.Server) {
+	initAnalyticsTools(server)
+}
+
+// RegisterReportTools registers computed report tools with the MCP server.
+// Includes: project_hygiene_report, commit_activity_by_author, generate_activity_summary,
+// multi_project_query, release_dashboard, ci_storage_report
+func RegisterReportTools(server *mcp.Server) {
+	initReportTools(server)
+}
+
+// ToolGroup lists the tools registered by one tool set.
+type ToolGroup struct {
+	Name  string
+	Tools []string
+}
+
+// toolGroup is a tool set that can be registered by name.
+type toolGroup struct {
+	name     string
+	register func(*mcp.Server, *ToolContext)
+	// enabled reports whether the group is part of the default tool set
+	// (nil means always)
+	enabled func(*config.Config) bool
+}
+
+// toolGroups lists the tool sets in registration order.
+var toolGroups = []toolGroup{
 	// Core tools (always registered)
-	RegisterProjectTools(server)
-	RegisterFileTools(server)
-	RegisterIssueTools(server)
-	RegisterMergeRequestTools(server)
-	RegisterBranchTools(server)
-	RegisterLabelTools(server)
-	RegisterNamespaceTools(server)
-	RegisterUserTools(server)
-	RegisterEventTools(server)
-	RegisterReleaseTools(server)
+	{"projects", withoutContext(RegisterProjectTools), nil},
+	{"files", withoutContext(RegisterFileTools), nil},
+	{"issues", withoutContext(RegisterIssueTools), nil},
+	{"merge_requests", withoutContext(RegisterMergeRequestTools), nil},
+	{"branches", withoutContext(RegisterBranchTools), nil},
+	{"labels", withoutContext(RegisterLabelTools), nil},
+	{"namespaces", withoutContext(RegisterNamespaceTools), nil},
+	{"users", withoutContext(RegisterUserTools), nil},
+	{"events", withoutContext(RegisterEventTools), nil},
+	{"releases", withoutContext(RegisterReleaseTools), nil},
+	{"templates", withoutContext(RegisterTemplateTools), nil},
+	{"import_export", withoutContext(RegisterImportExportTools), nil},
+	{"mirrors", withoutContext(RegisterMirrorTools), nil},
+	{"kubernetes", withoutContext(RegisterKubernetesTools), nil},
+	{"diagnostics", withoutContext(RegisterDiagnosticTools), nil},
+	{"admin", withoutContext(RegisterAdminTools), nil},
+	{"reports", withoutContext(RegisterReportTools), nil},
+	{"analytics", withoutContext(RegisterAnalyticsTools), nil},
 
 	// Feature-flagged tools (conditionally registered)
-	RegisterPipelineTools(server)
-	RegisterMilestoneTools(server)
-	RegisterWikiTools(server)
+	{"pipelines", func(server *mcp.Server, tc *ToolContext) { initPipelineTools(server, tc.Config.Extractors) },
+		func(cfg *config.Config) bool { return cfg.UsePipeline }},
+	{"milestones", withoutContext(RegisterMilestoneTools), func(cfg *config.Config) bool { return cfg.UseMilestone }},
+	{"wiki", withoutContext(RegisterWikiTools), func(cfg *config.Config) bool { return cfg.UseWiki }},
+}
+
+// withoutContext adapts a Register*Tools function to toolGroup.register.
+func withoutContext(register func(*mcp.Server)) func(*mcp.Server, *ToolContext) {
+	return func(server *mcp.Server, _ *ToolContext) { register(server) }
+}
+
+// ToolGroupNames returns the names accepted by Register, in registration order.
+func ToolGroupNames() []string {
+	names := make([]string, len(toolGroups))
+	for i, group := range toolGroups {
+		names[i] = group.name
+	}
+	return names
+}
+
+// Register binds tc to server and registers the named tool groups (see
+// ToolGroupNames) on it. Without names, it registers the default tool set:
+// the core groups plus the feature-flagged groups enabled in tc.Config. It
+// returns the non-empty groups registered, and fails without registering
+// anything if a name is unknown.
+//
+// Other Go programs embed the tools with it:
+//
+//	server := mcp.NewServer("my-server", "1.0.0")
+//	client := gitlab.NewClient("https://gitlab.example.com/api/v4", token)
+//	tc := tools.NewToolContext(client, nil, nil)
+//	if _, err := tools.Register(server, tc, "projects", "issues"); err != nil { ... }
+func Register(server *mcp.Server, tc *ToolContext, names ...string) ([]ToolGroup, error) {
+	groups, err := selectToolGroups(tc, names)
+	if err != nil {
+		return nil, err
+	}
+	Bind(server, tc)
+	return registerToolGroups(server, tc, groups), nil
+}
+
+// RegisterGroups is Register without binding tc to server, for re-registering
+// tools with mcp.Server.ReplaceTools (see Bind).
+func RegisterGroups(server *mcp.Server, tc *ToolContext, names ...string) ([]ToolGroup, error) {
+	groups, err := selectToolGroups(tc, names)
+	if err != nil {
+		return nil, err
+	}
+	return registerToolGroups(server, tc, groups), nil
+}
+
+// selectToolGroups returns the named groups, or the default tool set.
+func selectToolGroups(tc *ToolContext, names []string) ([]toolGroup, error) {
+	if tc == nil {
+		return nil, fmt.Errorf("tool context is nil")
+	}
+	var groups []toolGroup
+	if len(names) == 0 {
+		for _, group := range toolGroups {
+			if group.enabled == nil || group.enabled(tc.Config) {
+				groups = append(groups, group)
+			}
+		}
+		return groups, nil
+	}
+	for _, name := range names {
+		found := false
+		for _, group := range toolGroups {
+			if group.name == name {
+				groups = append(groups, group)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown tool group %q", name)
+		}
+	}
+	return groups, nil
+}
+
+// registerToolGroups registers groups and reports the tools each one added.
+func registerToolGroups(server *mcp.Server, tc *ToolContext, groups []toolGroup) []ToolGroup {
+	var registered []ToolGroup
+	for _, group := range groups {
+		before := len(server.ToolNames())
+		group.register(server, tc)
+		if added := server.ToolNames()[before:]; len(added) > 0 {
+			registered = append(registered, ToolGroup{Name: group.name, Tools: added})
+		}
+	}
+	return registered
 }
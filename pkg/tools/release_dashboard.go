@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// defaultDashboardEnvironments is how many environments per project the
+// release dashboard reports by default.
+const defaultDashboardEnvironments = 5
+
+// DashboardPipeline is the latest pipeline for a release tag.
+type DashboardPipeline struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	WebURL string `json:"web_url"`
+}
+
+// DashboardEnvironment is the latest deployment to one environment.
+type DashboardEnvironment struct {
+	Name       string     `json:"name"`
+	Tier       string     `json:"tier,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	Ref        string     `json:"ref,omitempty"`
+	DeployedAt *time.Time `json:"deployed_at,omitempty"`
+	// OnRelease is true when the deployed ref is the latest release tag
+	OnRelease bool `json:"on_release"`
+}
+
+// ReleaseDashboardRow is the release state of one project. Tag, ReleasedAt
+// and PipelineStatus are empty when the project has no release.
+type ReleaseDashboardRow struct {
+	Project        string                 `json:"project"`
+	Tag            string                 `json:"tag"`
+	ReleasedAt     *time.Time             `json:"released_at"`
+	PipelineStatus string                 `json:"pipeline_status"`
+	Deployments    string                 `json:"deployments"`
+	Pipeline       *DashboardPipeline     `json:"pipeline,omitempty"`
+	Environments   []DashboardEnvironment `json:"environments,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+}
+
+// ReleaseDashboard is the response of the release_dashboard tool.
+type ReleaseDashboard struct {
+	GroupID     string    `json:"group_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// ProjectsTruncated is true when the group has more than max_projects
+	// projects
+	ProjectsTruncated bool                  `json:"projects_truncated,omitempty"`
+	Projects          []ReleaseDashboardRow `json:"projects"`
+}
+
+// dashboardDeployment is the part of a GitLab deployment the dashboard reads.
+type dashboardDeployment struct {
+	Ref        string     `json:"ref"`
+	Status     string     `json:"status"`
+	CreatedAt  *time.Time `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// dashboardEnvironment is the part of a GitLab environment the dashboard reads.
+type dashboardEnvironment struct {
+	Name string `json:"name"`
+	Tier string `json:"tier"`
+}
+
+type releaseDashboardArgs struct {
+	GroupID          string `json:"group_id" validate:"required"`
+	IncludeSubgroups bool   `json:"include_subgroups"`
+	Environment      string `json:"environment"`
+	MaxEnvironments  int    `json:"max_environments" validate:"min=1,max=20"`
+	MaxProjects      int    `json:"max_projects" validate:"min=1,max=100"`
+	Concurrency      int    `json:"concurrency" validate:"min=1,max=10"`
+}
+
+// registerReleaseDashboard registers the release_dashboard tool.
+func registerReleaseDashboard(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "release_dashboard",
+			Description: "Release status of every project of a group in one call: the latest release tag and date, the status of the latest pipeline on that tag, and the latest deployment to each environment, flagged when it is not the released tag. Projects are queried in parallel. Use format=markdown-table for a weekly overview table.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"group_id": {
+						Type:        "string",
+						Description: "The group ID or URL-encoded path (e.g., acme or acme/platform)",
+					},
+					"include_subgroups": {
+						Type:        "boolean",
+						Description: "Include projects of subgroups. Default: false",
+					},
+					"environment": {
+						Type:        "string",
+						Description: "Only report this environment (e.g., production)",
+					},
+					"max_environments": {
+						Type:        "integer",
+						Description: "Environments reported per project (max 20). Default: 5",
+						Default:     defaultDashboardEnvironments,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(20),
+					},
+					"max_projects": {
+						Type:        "integer",
+						Description: "The most projects reported (max 100). Default: 50",
+						Default:     50,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(maxFanOutProjects),
+					},
+					"concurrency": {
+						Type:        "integer",
+						Description: "Projects queried at once (max 10). Default: 5",
+						Default:     defaultFanOutWorkers,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(maxFanOutWorkers),
+					},
+				},
+				Required: []string{"group_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("release_dashboard", func(ctx context.Context, c *ToolContext, args releaseDashboardArgs) (*mcp.CallToolResult, error) {
+			maxProjects := args.MaxProjects
+			if maxProjects == 0 {
+				maxProjects = 50
+			}
+			maxEnvironments := args.MaxEnvironments
+			if maxEnvironments == 0 {
+				maxEnvironments = defaultDashboardEnvironments
+			}
+
+			projects, truncated, err := groupProjectPaths(ctx, c.Client, args.GroupID, args.IncludeSubgroups, maxProjects)
+			if err != nil {
+				return APIErrorResult(fmt.Sprintf("Failed to list the projects of group %s", args.GroupID), err)
+			}
+
+			results := fanOut(ctx, projects, args.Concurrency, func(ctx context.Context, projectID string) (ReleaseDashboardRow, error) {
+				return releaseDashboardRow(ctx, c.Client, projectID, args.Environment, maxEnvironments)
+			})
+			dashboard := ReleaseDashboard{
+				GroupID:           args.GroupID,
+				GeneratedAt:       time.Now().UTC(),
+				ProjectsTruncated: truncated,
+				Projects:          make([]ReleaseDashboardRow, 0, len(results)),
+			}
+			for _, r := range results {
+				row := r.Value
+				row.Project = r.ProjectID
+				if r.Err != nil {
+					row.Error = r.Err.Error()
+				}
+				dashboard.Projects = append(dashboard.Projects, row)
+			}
+			return JSONResult(dashboard)
+		}),
+	)
+}
+
+// releaseDashboardRow gathers the release state of one project. A failing
+// request returns the row gathered so far with the error.
+func releaseDashboardRow(ctx context.Context, client gitlab.API, projectID, environment string, maxEnvironments int) (ReleaseDashboardRow, error) {
+	var row ReleaseDashboardRow
+	project := url.PathEscape(projectID)
+
+	var releases []gitlab.Release
+	if err := client.Get(ctx, fmt.Sprintf("/projects/%s/releases?order_by=released_at&sort=desc&per_page=1", project), &releases); err != nil {
+		return row, fmt.Errorf("releases: %w", err)
+	}
+	if len(releases) > 0 {
+		row.Tag = releases[0].TagName
+		row.ReleasedAt = releases[0].ReleasedAt
+
+		var pipelines []gitlab.Pipeline
+		if err := client.Get(ctx, fmt.Sprintf("/projects/%s/pipelines?ref=%s&per_page=1", project, url.QueryEscape(row.Tag)), &pipelines); err != nil {
+			return row, fmt.Errorf("pipelines: %w", err)
+		}
+		if len(pipelines) > 0 {
+			row.Pipeline = &DashboardPipeline{ID: pipelines[0].ID, Status: pipelines[0].Status, WebURL: pipelines[0].WebURL}
+			row.PipelineStatus = pipelines[0].Status
+		}
+	}
+
+	params := url.Values{}
+	params.Set("states", "available")
+	params.Set("per_page", fmt.Sprintf("%d", maxEnvironments))
+	if environment != "" {
+		params.Set("name", environment)
+	}
+	var environments []dashboardEnvironment
+	if err := client.Get(ctx, fmt.Sprintf("/projects/%s/environments?%s", project, params.Encode()), &environments); err != nil {
+		return row, fmt.Errorf("environments: %w", err)
+	}
+	summaries := make([]string, 0, len(environments))
+	for _, env := range environments {
+		entry := DashboardEnvironment{Name: env.Name, Tier: env.Tier}
+		var deployments []dashboardDeployment
+		if err := client.Get(ctx, fmt.Sprintf("/projects/%s/deployments?environment=%s&order_by=id&sort=desc&per_page=1", project, url.QueryEscape(env.Name)), &deployments); err != nil {
+			return row, fmt.Errorf("deployments to %s: %w", env.Name, err)
+		}
+		summary := env.Name + ": none"
+		if len(deployments) > 0 {
+			d := deployments[0]
+			entry.Status, entry.Ref = d.Status, d.Ref
+			entry.DeployedAt = d.FinishedAt
+			if entry.DeployedAt == nil {
+				entry.DeployedAt = d.CreatedAt
+			}
+			entry.OnRelease = row.Tag != "" && d.Ref == row.Tag
+			summary = fmt.Sprintf("%s: %s %s", env.Name, d.Status, d.Ref)
+			if row.Tag != "" && !entry.OnRelease {
+				summary += " (not the release tag)"
+			}
+		}
+		row.Environments = append(row.Environments, entry)
+		summaries = append(summaries, summary)
+	}
+	row.Deployments = strings.Join(summaries, "; ")
+	return row, nil
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestReleaseDashboard(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/groups/acme/projects", http.StatusOK, []map[string]interface{}{
+		{"id": 1, "path_with_namespace": "acme/api"},
+		{"id": 2, "path_with_namespace": "acme/docs"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/releases", http.StatusOK, []map[string]interface{}{
+		{"tag_name": "v1.4.0", "released_at": "2026-10-01T12:00:00Z"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/pipelines?ref=v1.4.0&per_page=1", http.StatusOK, []map[string]interface{}{
+		{"id": 900, "status": "success", "web_url": "https://gitlab.example.com/acme/api/-/pipelines/900"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/environments", http.StatusOK, []map[string]interface{}{
+		{"name": "production", "tier": "production"},
+		{"name": "staging", "tier": "staging"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/deployments?environment=production&order_by=id&sort=desc&per_page=1", http.StatusOK, []map[string]interface{}{
+		{"ref": "v1.4.0", "status": "success", "finished_at": "2026-10-02T08:00:00Z"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/deployments?environment=staging&order_by=id&sort=desc&per_page=1", http.StatusOK, []map[string]interface{}{
+		{"ref": "main", "status": "running", "created_at": "2026-10-15T08:00:00Z"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fdocs/releases", http.StatusOK, []interface{}{})
+	client.Handle(http.MethodGet, "/projects/acme%2Fdocs/environments", http.StatusOK, []interface{}{})
+
+	result := callTool(t, tc, "release_dashboard", map[string]interface{}{"group_id": "acme"})
+	if result.IsError {
+		t.Fatalf("release_dashboard failed: %s", resultText(t, result))
+	}
+	if unmatched := client.Unmatched(); len(unmatched) > 0 {
+		t.Errorf("requests without fixtures: %v", unmatched)
+	}
+	var got ReleaseDashboard
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if len(got.Projects) != 2 {
+		t.Fatalf("projects = %+v, want 2", got.Projects)
+	}
+
+	api := got.Projects[0]
+	if api.Project != "acme/api" || api.Tag != "v1.4.0" || api.PipelineStatus != "success" || api.Error != "" {
+		t.Errorf("acme/api = %+v", api)
+	}
+	if len(api.Environments) != 2 || !api.Environments[0].OnRelease || api.Environments[1].OnRelease {
+		t.Errorf("acme/api environments = %+v", api.Environments)
+	}
+	if want := "production: success v1.4.0; staging: running main (not the release tag)"; api.Deployments != want {
+		t.Errorf("acme/api deployments = %q, want %q", api.Deployments, want)
+	}
+	if docs := got.Projects[1]; docs.Project != "acme/docs" || docs.Tag != "" || docs.Pipeline != nil || docs.Error != "" {
+		t.Errorf("acme/docs = %+v", docs)
+	}
+}
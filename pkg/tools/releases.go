@@ -2,8 +2,10 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"path"
 	"strings"
 
 	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
@@ -77,12 +79,12 @@ func registerGetRelease(server *mcp.Server) {
 				Required: []string{"project_id", "tag_name"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("get_release", args)
+			c.Logger.ToolCall(ctx, "get_release", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -100,8 +102,8 @@ func registerGetRelease(server *mcp.Server) {
 			)
 
 			var release ReleaseDetailed
-			if err := c.Client.Get(endpoint, &release); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get release: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &release); err != nil {
+				return APIErrorResult("Failed to get release", err)
 			}
 
 			return JSONResult(release)
@@ -151,12 +153,12 @@ func registerCreateRelease(server *mcp.Server) {
 				Required: []string{"project_id", "tag_name"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("create_release", args)
+			c.Logger.ToolCall(ctx, "create_release", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -196,8 +198,8 @@ func registerCreateRelease(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/releases", url.PathEscape(projectID))
 
 			var release ReleaseDetailed
-			if err := c.Client.Post(endpoint, body, &release); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create release: %v", err))
+			if err := c.Client.Post(ctx, endpoint, body, &release); err != nil {
+				return APIErrorResult("Failed to create release", err)
 			}
 
 			return JSONResult(release)
@@ -243,12 +245,12 @@ func registerUpdateRelease(server *mcp.Server) {
 				Required: []string{"project_id", "tag_name"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("update_release", args)
+			c.Logger.ToolCall(ctx, "update_release", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -285,8 +287,8 @@ func registerUpdateRelease(server *mcp.Server) {
 			)
 
 			var release ReleaseDetailed
-			if err := c.Client.Put(endpoint, body, &release); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to update release: %v", err))
+			if err := c.Client.Put(ctx, endpoint, body, &release); err != nil {
+				return APIErrorResult("Failed to update release", err)
 			}
 
 			return JSONResult(release)
@@ -315,12 +317,12 @@ func registerDeleteRelease(server *mcp.Server) {
 				Required: []string{"project_id", "tag_name"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("delete_release", args)
+			c.Logger.ToolCall(ctx, "delete_release", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -337,8 +339,8 @@ func registerDeleteRelease(server *mcp.Server) {
 				url.PathEscape(tagName),
 			)
 
-			if err := c.Client.Delete(endpoint); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to delete release: %v", err))
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("Failed to delete release", err)
 			}
 
 			return TextResult(fmt.Sprintf("Release '%s' deleted successfully", tagName))
@@ -367,12 +369,12 @@ func registerCreateReleaseEvidence(server *mcp.Server) {
 				Required: []string{"project_id", "tag_name"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("create_release_evidence", args)
+			c.Logger.ToolCall(ctx, "create_release_evidence", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -391,8 +393,8 @@ func registerCreateReleaseEvidence(server *mcp.Server) {
 
 			// POST with empty body
 			var result interface{}
-			if err := c.Client.Post(endpoint, nil, &result); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create release evidence: %v", err))
+			if err := c.Client.Post(ctx, endpoint, nil, &result); err != nil {
+				return APIErrorResult("Failed to create release evidence", err)
 			}
 
 			// The API returns 201 Created with empty body on success
@@ -410,10 +412,10 @@ func registerDownloadReleaseAsset(server *mcp.Server) {
 	server.RegisterTool(
 		mcp.Tool{
 			Name:        "download_release_asset",
-			Description: "Download a release asset from a GitLab project. Use the direct_asset_url from the release's assets.links array.",
+			Description: "Download a release asset from a GitLab project. Use the direct_asset_url from the release's assets.links array. Text is returned as is and binaries as base64, with the detected content type; large assets can be written to a temporary file instead.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
-				Properties: map[string]mcp.Property{
+				Properties: withDownloadOutputProperties(map[string]mcp.Property{
 					"project_id": {
 						Type:        "string",
 						Description: "The ID or URL-encoded path of the project",
@@ -426,16 +428,19 @@ func registerDownloadReleaseAsset(server *mcp.Server) {
 						Type:        "string",
 						Description: "The direct_asset_url from the release's assets.links array. This is the full URL to the asset.",
 					},
-				},
+				}),
 				Required: []string{"project_id", "tag_name", "asset_link_url"},
 			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			c := GetContext()
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
 			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			c.Logger.ToolCall("download_release_asset", args)
+			c.Logger.ToolCall(ctx, "download_release_asset", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -493,7 +498,7 @@ func registerDownloadReleaseAsset(server *mcp.Server) {
 				// but extract just the path component
 				parsedURL, err := url.Parse(assetLinkURL)
 				if err != nil {
-					return ErrorResult(fmt.Sprintf("invalid asset_link_url: %v", err))
+					return APIErrorResult("invalid asset_link_url", err)
 				}
 				endpoint = parsedURL.Path
 
@@ -507,15 +512,11 @@ func registerDownloadReleaseAsset(server *mcp.Server) {
 			}
 
 			// Log the computed endpoint for debugging
-			c.Logger.Debug("downloading release asset: baseURL=%s endpoint=%s", baseURL, endpoint)
+			c.Logger.DebugContext(ctx, "downloading release asset: baseURL=%s endpoint=%s", baseURL, endpoint)
 
 			// Download the asset content
-			var content string
-			if err := c.Client.Get(endpoint, &content); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to download release asset: %v", err))
-			}
-
-			return TextResult(content)
+			mcp.ReportProgress(ctx, 0, 1, "Downloading release asset")
+			return downloadResult(ctx, c, endpoint, path.Base(endpoint), args)
 		},
 	)
 }
@@ -529,4 +530,6 @@ func initReleaseTools(server *mcp.Server) {
 	registerDeleteRelease(server)
 	registerCreateReleaseEvidence(server)
 	registerDownloadReleaseAsset(server)
+	initFreezePeriodTools(server)
+	initPackageTools(server)
 }
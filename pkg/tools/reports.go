@@ -0,0 +1,463 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// defaultStaleDays is the inactivity threshold used by the hygiene report.
+const defaultStaleDays = 30
+
+// StaleIssue is an open issue without recent activity.
+type StaleIssue struct {
+	IID          int        `json:"iid"`
+	Title        string     `json:"title"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+	DaysInactive int        `json:"days_inactive"`
+	Assignees    []string   `json:"assignees,omitempty"`
+	Labels       []string   `json:"labels,omitempty"`
+	WebURL       string     `json:"web_url"`
+}
+
+// UnreviewedMergeRequest is an open merge request with no reviewers assigned.
+type UnreviewedMergeRequest struct {
+	IID          int        `json:"iid"`
+	Title        string     `json:"title"`
+	Author       string     `json:"author,omitempty"`
+	SourceBranch string     `json:"source_branch"`
+	Draft        bool       `json:"draft"`
+	CreatedAt    *time.Time `json:"created_at"`
+	DaysOpen     int        `json:"days_open"`
+	WebURL       string     `json:"web_url"`
+}
+
+// StaleBranch is a branch with no open merge request and no recent commits.
+type StaleBranch struct {
+	Name         string     `json:"name"`
+	Merged       bool       `json:"merged"`
+	LastCommitAt *time.Time `json:"last_commit_at"`
+	DaysOld      int        `json:"days_old"`
+	LastAuthor   string     `json:"last_author,omitempty"`
+}
+
+// HygieneReport is the response of the project_hygiene_report tool. The
+// *Complete flags are false when a collection exceeded maxCollectedItems.
+type HygieneReport struct {
+	ProjectID                     string                   `json:"project_id"`
+	GeneratedAt                   time.Time                `json:"generated_at"`
+	StaleDays                     int                      `json:"stale_days"`
+	BranchAgeDays                 int                      `json:"branch_age_days"`
+	Summary                       map[string]int           `json:"summary"`
+	StaleIssues                   []StaleIssue             `json:"stale_issues"`
+	StaleIssuesComplete           bool                     `json:"stale_issues_complete"`
+	MergeRequestsWithoutReviewers []UnreviewedMergeRequest `json:"merge_requests_without_reviewers"`
+	MergeRequestsComplete         bool                     `json:"merge_requests_complete"`
+	StaleBranches                 []StaleBranch            `json:"stale_branches"`
+	BranchesComplete              bool                     `json:"branches_complete"`
+}
+
+// AuthorActivity is the commit activity of one author in a date range.
+type AuthorActivity struct {
+	AuthorName    string     `json:"author_name"`
+	AuthorEmail   string     `json:"author_email"`
+	Commits       int        `json:"commits"`
+	Additions     int        `json:"additions,omitempty"`
+	Deletions     int        `json:"deletions,omitempty"`
+	FirstCommitAt *time.Time `json:"first_commit_at"`
+	LastCommitAt  *time.Time `json:"last_commit_at"`
+}
+
+// ActivityPeriod is the commit activity of one day or week, starting at Start
+// (a date in the report's timezone).
+type ActivityPeriod struct {
+	Start   string `json:"start"`
+	Commits int    `json:"commits"`
+	Authors int    `json:"authors"`
+}
+
+// CommitActivityReport is the response of the commit_activity_by_author tool.
+// Periods lists the days or weeks with commits when an interval is requested.
+type CommitActivityReport struct {
+	ProjectID    string           `json:"project_id"`
+	RefName      string           `json:"ref_name,omitempty"`
+	Since        string           `json:"since,omitempty"`
+	Until        string           `json:"until,omitempty"`
+	Interval     string           `json:"interval,omitempty"`
+	Timezone     string           `json:"timezone,omitempty"`
+	TotalCommits int              `json:"total_commits"`
+	Complete     bool             `json:"complete"`
+	Authors      []AuthorActivity `json:"authors"`
+	Periods      []ActivityPeriod `json:"periods,omitempty"`
+}
+
+// commitWithStats is a commit listed with with_stats=true.
+type commitWithStats struct {
+	gitlab.Commit
+	Stats *struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats,omitempty"`
+}
+
+// periodStart returns the first day of the day or week (starting on Monday)
+// containing t in loc, as YYYY-MM-DD.
+func periodStart(t time.Time, interval string, loc *time.Location) string {
+	t = t.In(loc)
+	if interval == "week" {
+		t = t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+	}
+	return t.Format("2006-01-02")
+}
+
+// daysSince returns the whole days between t and now (0 for nil).
+func daysSince(t *time.Time, now time.Time) int {
+	if t == nil {
+		return 0
+	}
+	return int(now.Sub(*t).Hours() / 24)
+}
+
+// registerProjectHygieneReport registers the project_hygiene_report tool.
+func registerProjectHygieneReport(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "project_hygiene_report",
+			Description: "Compute a cleanup report for a project: open issues with no activity for stale_days, open MRs without reviewers, and branches with no open MR whose last commit is older than branch_age_days (default and protected branches excluded). Returns counts plus the matching items, oldest first.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"stale_days": {
+						Type:        "integer",
+						Description: "Days without activity after which an open issue is stale. Default: 30",
+						Default:     defaultStaleDays,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"branch_age_days": {
+						Type:        "integer",
+						Description: "Days since the last commit after which a branch without an open MR is stale. Default: stale_days",
+						Minimum:     mcp.IntPtr(1),
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "project_hygiene_report", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+			staleDays := GetInt(args, "stale_days", defaultStaleDays)
+			if staleDays < 1 {
+				return ErrorResult("stale_days must be at least 1")
+			}
+			branchAgeDays := GetInt(args, "branch_age_days", staleDays)
+			if branchAgeDays < 1 {
+				return ErrorResult("branch_age_days must be at least 1")
+			}
+
+			now := time.Now().UTC()
+			report := HygieneReport{
+				ProjectID:     projectID,
+				GeneratedAt:   now,
+				StaleDays:     staleDays,
+				BranchAgeDays: branchAgeDays,
+			}
+			base := fmt.Sprintf("/projects/%s", url.PathEscape(projectID))
+
+			mcp.ReportProgress(ctx, 0, 3, "Listing stale issues")
+			params := url.Values{}
+			params.Set("state", "opened")
+			params.Set("updated_before", now.AddDate(0, 0, -staleDays).Format(time.RFC3339))
+			params.Set("order_by", "updated_at")
+			params.Set("sort", "asc")
+			issues, complete, err := collectPages[gitlab.Issue](ctx, c.Client, base+"/issues?"+params.Encode(), maxCollectedItems)
+			if err != nil {
+				return APIErrorResult("failed to list issues", err)
+			}
+			report.StaleIssuesComplete = complete
+			report.StaleIssues = make([]StaleIssue, 0, len(issues))
+			for _, issue := range issues {
+				stale := StaleIssue{
+					IID:          issue.IID,
+					Title:        issue.Title,
+					UpdatedAt:    issue.UpdatedAt,
+					DaysInactive: daysSince(issue.UpdatedAt, now),
+					Labels:       issue.Labels,
+					WebURL:       issue.WebURL,
+				}
+				for _, assignee := range issue.Assignees {
+					stale.Assignees = append(stale.Assignees, assignee.Username)
+				}
+				report.StaleIssues = append(report.StaleIssues, stale)
+			}
+
+			mcp.ReportProgress(ctx, 1, 3, "Listing open merge requests")
+			mergeRequests, complete, err := collectPages[gitlab.MergeRequest](ctx, c.Client, base+"/merge_requests?state=opened&order_by=created_at&sort=asc", maxCollectedItems)
+			if err != nil {
+				return APIErrorResult("failed to list merge requests", err)
+			}
+			report.MergeRequestsComplete = complete
+			report.MergeRequestsWithoutReviewers = []UnreviewedMergeRequest{}
+			openBranches := make(map[string]bool, len(mergeRequests))
+			for _, mr := range mergeRequests {
+				openBranches[mr.SourceBranch] = true
+				if len(mr.Reviewers) > 0 {
+					continue
+				}
+				unreviewed := UnreviewedMergeRequest{
+					IID:          mr.IID,
+					Title:        mr.Title,
+					SourceBranch: mr.SourceBranch,
+					Draft:        mr.Draft,
+					CreatedAt:    mr.CreatedAt,
+					DaysOpen:     daysSince(mr.CreatedAt, now),
+					WebURL:       mr.WebURL,
+				}
+				if mr.Author != nil {
+					unreviewed.Author = mr.Author.Username
+				}
+				report.MergeRequestsWithoutReviewers = append(report.MergeRequestsWithoutReviewers, unreviewed)
+			}
+
+			mcp.ReportProgress(ctx, 2, 3, "Listing branches")
+			branches, complete, err := collectPages[gitlab.Branch](ctx, c.Client, base+"/repository/branches", maxCollectedItems)
+			if err != nil {
+				return APIErrorResult("failed to list branches", err)
+			}
+			report.BranchesComplete = complete
+			report.StaleBranches = []StaleBranch{}
+			for _, branch := range branches {
+				if branch.Default || branch.Protected || openBranches[branch.Name] || branch.Commit == nil {
+					continue
+				}
+				lastCommit := branch.Commit.CommittedDate
+				if age := daysSince(lastCommit, now); lastCommit != nil && age >= branchAgeDays {
+					report.StaleBranches = append(report.StaleBranches, StaleBranch{
+						Name:         branch.Name,
+						Merged:       branch.Merged,
+						LastCommitAt: lastCommit,
+						DaysOld:      age,
+						LastAuthor:   branch.Commit.AuthorName,
+					})
+				}
+			}
+			sort.Slice(report.StaleBranches, func(i, j int) bool {
+				return report.StaleBranches[i].DaysOld > report.StaleBranches[j].DaysOld
+			})
+
+			report.Summary = map[string]int{
+				"stale_issues":                     len(report.StaleIssues),
+				"merge_requests_without_reviewers": len(report.MergeRequestsWithoutReviewers),
+				"stale_branches":                   len(report.StaleBranches),
+			}
+			mcp.ReportProgress(ctx, 3, 3, "Report complete")
+
+			return JSONResult(report)
+		},
+	)
+}
+
+// registerCommitActivityByAuthor registers the commit_activity_by_author tool.
+func registerCommitActivityByAuthor(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "commit_activity_by_author",
+			Description: "Summarize commit activity per author over a date range: commit count, optional line additions/deletions, and first/last commit time, plus commits per day or week with interval. Aggregates the commit list server-side so standup or retro summaries don't need the raw commits.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"since": {
+						Type:        "string",
+						Description: "Only commits after or on this date (ISO 8601 format, e.g., 2024-01-01T00:00:00Z)",
+					},
+					"until": {
+						Type:        "string",
+						Description: "Only commits before or on this date (ISO 8601 format)",
+					},
+					"ref_name": {
+						Type:        "string",
+						Description: "Branch or tag to read history from. Default: the default branch",
+					},
+					"all": {
+						Type:        "boolean",
+						Description: "Count commits from every branch instead of ref_name (default: false)",
+					},
+					"with_stats": {
+						Type:        "boolean",
+						Description: "Include line additions and deletions per author (slower on large histories, default: false)",
+					},
+					"interval": {
+						Type:        "string",
+						Description: "Also count commits per day or per week (weeks start on Monday) by authored date (optional)",
+						Enum:        []string{"day", "week"},
+					},
+					"timezone": {
+						Type:        "string",
+						Description: "IANA timezone the days and weeks of interval are counted in (e.g., Europe/Berlin, default: UTC)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "commit_activity_by_author", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			report := CommitActivityReport{
+				ProjectID: projectID,
+				RefName:   GetString(args, "ref_name", ""),
+				Since:     GetString(args, "since", ""),
+				Until:     GetString(args, "until", ""),
+				Interval:  GetString(args, "interval", ""),
+			}
+			if report.Interval != "" && report.Interval != "day" && report.Interval != "week" {
+				return ErrorResult("interval must be day or week")
+			}
+			loc := time.UTC
+			if timezone := GetString(args, "timezone", ""); timezone != "" {
+				loaded, err := time.LoadLocation(timezone)
+				if err != nil {
+					return ErrorResult(fmt.Sprintf("unknown timezone %q", timezone))
+				}
+				loc = loaded
+			}
+			if report.Interval != "" {
+				report.Timezone = loc.String()
+			}
+
+			params := url.Values{}
+			if report.RefName != "" {
+				params.Set("ref_name", report.RefName)
+			}
+			if report.Since != "" {
+				params.Set("since", report.Since)
+			}
+			if report.Until != "" {
+				params.Set("until", report.Until)
+			}
+			if GetBool(args, "all", false) {
+				params.Set("all", "true")
+			}
+			if GetBool(args, "with_stats", false) {
+				params.Set("with_stats", "true")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/repository/commits", url.PathEscape(projectID))
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			// Commits are aggregated as they are decoded rather than collected
+			byAuthor := make(map[string]*AuthorActivity)
+			byPeriod := make(map[string]*ActivityPeriod)
+			periodAuthors := make(map[string]map[string]bool)
+			complete, err := streamPages(ctx, c.Client, endpoint, maxCollectedItems, func(commit commitWithStats) {
+				report.TotalCommits++
+				key := strings.ToLower(commit.AuthorEmail)
+				if key == "" {
+					key = commit.AuthorName
+				}
+				activity, ok := byAuthor[key]
+				if !ok {
+					activity = &AuthorActivity{AuthorName: commit.AuthorName, AuthorEmail: commit.AuthorEmail}
+					byAuthor[key] = activity
+				}
+				activity.Commits++
+				if commit.Stats != nil {
+					activity.Additions += commit.Stats.Additions
+					activity.Deletions += commit.Stats.Deletions
+				}
+				if at := commit.AuthoredDate; at != nil {
+					if activity.FirstCommitAt == nil || at.Before(*activity.FirstCommitAt) {
+						activity.FirstCommitAt = at
+					}
+					if activity.LastCommitAt == nil || at.After(*activity.LastCommitAt) {
+						activity.LastCommitAt = at
+					}
+					if report.Interval != "" {
+						start := periodStart(*at, report.Interval, loc)
+						period, ok := byPeriod[start]
+						if !ok {
+							period = &ActivityPeriod{Start: start}
+							byPeriod[start] = period
+							periodAuthors[start] = make(map[string]bool)
+						}
+						period.Commits++
+						if !periodAuthors[start][key] {
+							periodAuthors[start][key] = true
+							period.Authors++
+						}
+					}
+				}
+			})
+			if err != nil {
+				return APIErrorResult("failed to list commits", err)
+			}
+			report.Complete = complete
+
+			report.Authors = make([]AuthorActivity, 0, len(byAuthor))
+			for _, activity := range byAuthor {
+				report.Authors = append(report.Authors, *activity)
+			}
+			sort.Slice(report.Authors, func(i, j int) bool {
+				if report.Authors[i].Commits != report.Authors[j].Commits {
+					return report.Authors[i].Commits > report.Authors[j].Commits
+				}
+				return report.Authors[i].AuthorName < report.Authors[j].AuthorName
+			})
+			for _, period := range byPeriod {
+				report.Periods = append(report.Periods, *period)
+			}
+			sort.Slice(report.Periods, func(i, j int) bool {
+				return report.Periods[i].Start < report.Periods[j].Start
+			})
+
+			return JSONResult(report)
+		},
+	)
+}
+
+// initReportTools registers the computed report tools.
+func initReportTools(server *mcp.Server) {
+	registerProjectHygieneReport(server)
+	registerCommitActivityByAuthor(server)
+	registerGenerateActivitySummary(server)
+	registerMultiProjectQuery(server)
+	registerReleaseDashboard(server)
+	registerCIStorageReport(server)
+}
@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+)
+
+// daysAgo returns a time days (plus an hour, to stay clear of the day
+// boundary) before now.
+func daysAgo(days int) *time.Time {
+	t := time.Now().UTC().AddDate(0, 0, -days).Add(-time.Hour)
+	return &t
+}
+
+func TestProjectHygieneReportBranches(t *testing.T) {
+	branch := func(name string, days int) gitlab.Branch {
+		return gitlab.Branch{Name: name, Commit: &gitlab.Commit{CommittedDate: daysAgo(days), AuthorName: "Alice"}}
+	}
+	branches := []gitlab.Branch{
+		branch("feature/old", 90),
+		branch("feature/cutoff", 30),
+		branch("feature/fresh", 29),
+		branch("feature/with-mr", 90),
+		{Name: "main", Default: true, Commit: &gitlab.Commit{CommittedDate: daysAgo(400)}},
+		{Name: "release/1.0", Protected: true, Commit: &gitlab.Commit{CommittedDate: daysAgo(400)}},
+		// Branches listed without their commit, or a commit without a date,
+		// cannot be aged and are left out
+		{Name: "feature/no-commit"},
+		{Name: "feature/no-date", Commit: &gitlab.Commit{}},
+	}
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{name: "default cut-off of 30 days", want: []string{"feature/old", "feature/cutoff"}},
+		{name: "branch_age_days follows stale_days", args: map[string]interface{}{"stale_days": 60}, want: []string{"feature/old"}},
+		{name: "branch_age_days", args: map[string]interface{}{"stale_days": 60, "branch_age_days": 29}, want: []string{"feature/old", "feature/cutoff", "feature/fresh"}},
+		{name: "nothing old enough", args: map[string]interface{}{"branch_age_days": 365}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, client := newTestContext(t)
+			client.Handle(http.MethodGet, "/projects/7/issues", http.StatusOK, `[]`)
+			client.Handle(http.MethodGet, "/projects/7/merge_requests", http.StatusOK, []gitlab.MergeRequest{{IID: 1, SourceBranch: "feature/with-mr", Reviewers: []gitlab.User{{Username: "bob"}}}})
+			client.Handle(http.MethodGet, "/projects/7/repository/branches", http.StatusOK, branches)
+
+			args := map[string]interface{}{"project_id": "7"}
+			for name, value := range tt.args {
+				args[name] = value
+			}
+			var report HygieneReport
+			if err := json.Unmarshal([]byte(resultText(t, callTool(t, tc, "project_hygiene_report", args))), &report); err != nil {
+				t.Fatalf("decode report: %v", err)
+			}
+			got := []string{}
+			for _, branch := range report.StaleBranches {
+				got = append(got, branch.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stale branches = %v, want %v (oldest first)", got, tt.want)
+			}
+			if report.Summary["stale_branches"] != len(tt.want) || !report.BranchesComplete {
+				t.Errorf("summary = %v, complete %v", report.Summary, report.BranchesComplete)
+			}
+		})
+	}
+}
+
+func TestProjectHygieneReport(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/issues", http.StatusOK, []gitlab.Issue{
+		{IID: 3, Title: "Flaky test", UpdatedAt: daysAgo(45), Labels: []string{"ci"}, Assignees: []gitlab.User{{Username: "alice"}}},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/merge_requests", http.StatusOK, []gitlab.MergeRequest{
+		{IID: 10, Title: "Reviewed", SourceBranch: "a", CreatedAt: daysAgo(3), Reviewers: []gitlab.User{{Username: "bob"}}},
+		{IID: 11, Title: "Unreviewed", SourceBranch: "b", CreatedAt: daysAgo(12), Draft: true, Author: &gitlab.User{Username: "carol"}},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/repository/branches", http.StatusOK, `[]`)
+
+	result := callTool(t, tc, "project_hygiene_report", map[string]interface{}{"project_id": "acme/api", "stale_days": 40})
+	var report HygieneReport
+	if err := json.Unmarshal([]byte(resultText(t, result)), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	wantSummary := map[string]int{"stale_issues": 1, "merge_requests_without_reviewers": 1, "stale_branches": 0}
+	if !reflect.DeepEqual(report.Summary, wantSummary) || report.StaleDays != 40 || report.BranchAgeDays != 40 {
+		t.Errorf("report = %+v", report)
+	}
+	if issue := report.StaleIssues[0]; issue.IID != 3 || issue.DaysInactive != 45 || !reflect.DeepEqual(issue.Assignees, []string{"alice"}) {
+		t.Errorf("stale issue = %+v", issue)
+	}
+	if mr := report.MergeRequestsWithoutReviewers[0]; mr.IID != 11 || mr.Author != "carol" || !mr.Draft || mr.DaysOpen != 12 {
+		t.Errorf("merge request without reviewers = %+v", mr)
+	}
+
+	// GitLab filters the issues by the stale_days cut-off
+	for _, request := range client.Requests() {
+		if !strings.Contains(request.Endpoint, "/issues?") {
+			continue
+		}
+		query, _ := url.ParseQuery(request.Endpoint[strings.Index(request.Endpoint, "?")+1:])
+		before, err := time.Parse(time.RFC3339, query.Get("updated_before"))
+		if err != nil || query.Get("state") != "opened" || time.Since(before) < 40*24*time.Hour || time.Since(before) > 40*24*time.Hour+time.Minute {
+			t.Errorf("issues query = %v, want open issues updated 40 days ago or earlier", query)
+		}
+	}
+}
+
+func TestProjectHygieneReportErrors(t *testing.T) {
+	tc, _ := newTestContext(t)
+	for _, args := range []map[string]interface{}{
+		{},
+		{"project_id": "7", "stale_days": 0},
+		{"project_id": "7", "branch_age_days": -1},
+	} {
+		if result := callTool(t, tc, "project_hygiene_report", args); !result.IsError {
+			t.Errorf("project_hygiene_report(%v) succeeded", args)
+		}
+	}
+}
+
+func TestCommitActivityByAuthor(t *testing.T) {
+	// Two of the commits fall on another day, and one in another week, in
+	// Berlin (UTC+1) than in UTC
+	commits := `[
+		{"id": "c1", "author_name": "Alice", "author_email": "alice@example.com", "authored_date": "2024-03-04T08:00:00Z", "stats": {"additions": 10, "deletions": 1}},
+		{"id": "c2", "author_name": "Alice", "author_email": "ALICE@example.com", "authored_date": "2024-03-04T23:30:00Z", "stats": {"additions": 5, "deletions": 0}},
+		{"id": "c3", "author_name": "Bob", "author_email": "bob@example.com", "authored_date": "2024-03-05T12:00:00Z", "stats": {"additions": 1, "deletions": 2}},
+		{"id": "c4", "author_name": "Alice", "author_email": "alice@example.com", "authored_date": "2024-03-10T23:30:00Z", "stats": {"additions": 0, "deletions": 3}}
+	]`
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		timezone string
+		want     []ActivityPeriod
+	}{
+		{name: "authors only"},
+		{
+			name:     "days in UTC",
+			args:     map[string]interface{}{"interval": "day"},
+			timezone: "UTC",
+			want:     []ActivityPeriod{{"2024-03-04", 2, 1}, {"2024-03-05", 1, 1}, {"2024-03-10", 1, 1}},
+		},
+		{
+			name:     "days in Berlin",
+			args:     map[string]interface{}{"interval": "day", "timezone": "Europe/Berlin"},
+			timezone: "Europe/Berlin",
+			want:     []ActivityPeriod{{"2024-03-04", 1, 1}, {"2024-03-05", 2, 2}, {"2024-03-11", 1, 1}},
+		},
+		{
+			name:     "weeks in UTC",
+			args:     map[string]interface{}{"interval": "week"},
+			timezone: "UTC",
+			want:     []ActivityPeriod{{"2024-03-04", 4, 2}},
+		},
+		{
+			name:     "weeks in Berlin",
+			args:     map[string]interface{}{"interval": "week", "timezone": "Europe/Berlin"},
+			timezone: "Europe/Berlin",
+			want:     []ActivityPeriod{{"2024-03-04", 3, 2}, {"2024-03-11", 1, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, client := newTestContext(t)
+			client.Handle(http.MethodGet, "/projects/7/repository/commits", http.StatusOK, commits)
+
+			args := map[string]interface{}{"project_id": "7", "with_stats": true, "since": "2024-03-01T00:00:00Z"}
+			for name, value := range tt.args {
+				args[name] = value
+			}
+			var report CommitActivityReport
+			if err := json.Unmarshal([]byte(resultText(t, callTool(t, tc, "commit_activity_by_author", args))), &report); err != nil {
+				t.Fatalf("decode report: %v", err)
+			}
+			if !reflect.DeepEqual(report.Periods, tt.want) || report.Timezone != tt.timezone {
+				t.Errorf("periods in %q = %+v, want %+v in %q", report.Timezone, report.Periods, tt.want, tt.timezone)
+			}
+
+			// Authors are matched by email regardless of case
+			if report.TotalCommits != 4 || !report.Complete || len(report.Authors) != 2 {
+				t.Fatalf("report = %+v", report)
+			}
+			alice, bob := report.Authors[0], report.Authors[1]
+			if alice.AuthorName != "Alice" || alice.Commits != 3 || alice.Additions != 15 || alice.Deletions != 4 ||
+				!alice.FirstCommitAt.Equal(mustParseTime(t, "2024-03-04T08:00:00Z")) || !alice.LastCommitAt.Equal(mustParseTime(t, "2024-03-10T23:30:00Z")) {
+				t.Errorf("first author = %+v, want Alice with 3 commits", alice)
+			}
+			if bob.AuthorName != "Bob" || bob.Commits != 1 || bob.Additions != 1 || bob.Deletions != 2 {
+				t.Errorf("second author = %+v, want Bob with 1 commit", bob)
+			}
+
+			endpoint := client.Requests()[0].Endpoint
+			if !strings.Contains(endpoint, "with_stats=true") || !strings.Contains(endpoint, "since=2024-03-01T00%3A00%3A00Z") {
+				t.Errorf("commits requested with %s", endpoint)
+			}
+		})
+	}
+}
+
+func TestCommitActivityByAuthorErrors(t *testing.T) {
+	tc, _ := newTestContext(t)
+	for _, args := range []map[string]interface{}{
+		{"project_id": "7", "interval": "month"},
+		{"project_id": "7", "interval": "day", "timezone": "Mars/Olympus_Mons"},
+	} {
+		if result := callTool(t, tc, "commit_activity_by_author", args); !result.IsError {
+			t.Errorf("commit_activity_by_author(%v) succeeded", args)
+		}
+	}
+}
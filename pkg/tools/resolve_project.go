@@ -0,0 +1,366 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// maxResolveCandidates is the number of search matches returned by
+// resolve_project when the input is a fuzzy name.
+const maxResolveCandidates = 5
+
+// scpRemotePattern matches scp-like SSH remotes, e.g. git@gitlab.com:group/project.git.
+var scpRemotePattern = regexp.MustCompile(`^[\w.\-]+@([\w.\-]+):(.+)$`)
+
+// urlResourcePattern matches the resource part of a GitLab web URL, e.g.
+// /-/merge_requests/12 or /-/blob/main/README.md.
+var urlResourcePattern = regexp.MustCompile(`^(merge_requests|issues|pipelines|jobs|commit|tree|blob)/([^/]+)(?:/(.*))?$`)
+
+// ResolvedProject is a project found by resolve_project.
+type ResolvedProject struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	NameWithNamespace string `json:"name_with_namespace"`
+	DefaultBranch     string `json:"default_branch,omitempty"`
+	WebURL            string `json:"web_url"`
+	Score             int    `json:"score,omitempty"`
+}
+
+// URLResource is the merge request, issue, pipeline, job, commit or file a
+// pasted web URL points to, besides the project.
+type URLResource struct {
+	Type string `json:"type"`
+	IID  int    `json:"iid,omitempty"` // merge request and issue IIDs
+	ID   int    `json:"id,omitempty"`  // pipeline and job IDs
+	Ref  string `json:"ref,omitempty"` // commit SHA, or branch of a tree or blob URL
+	Path string `json:"path,omitempty"`
+}
+
+// ProjectResolution is the result of the resolve_project tool. Method is how
+// the input was interpreted: id, path, url, ssh or search.
+type ProjectResolution struct {
+	Input      string            `json:"input"`
+	Method     string            `json:"method"`
+	Project    *ResolvedProject  `json:"project,omitempty"`
+	Resource   *URLResource      `json:"resource,omitempty"`
+	Ambiguous  bool              `json:"ambiguous,omitempty"`
+	Candidates []ResolvedProject `json:"candidates,omitempty"`
+	Note       string            `json:"note,omitempty"`
+}
+
+// registerResolveProject registers the resolve_project tool.
+func registerResolveProject(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "resolve_project",
+			Description: "Turn whatever the user gave for a project into its canonical ID and path: a numeric ID, a path, a web URL (also deep links such as .../-/merge_requests/12, whose MR, issue, pipeline, job, commit or file is returned as resource), an SSH or HTTPS git remote, or a fuzzy name such as \"payments api\". Exact matches win; for names the best search matches are ranked, and ambiguous is set when the top ones score the same, in which case ask the user to pick from candidates.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"query": {
+						Type:        "string",
+						Description: "Project ID, path, web URL, git remote or name",
+					},
+				},
+				Required: []string{"query"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "resolve_project", args)
+
+			query := strings.TrimSpace(GetString(args, "query", ""))
+			if query == "" {
+				return ErrorResult("query is required")
+			}
+			result := ProjectResolution{Input: query}
+
+			if _, err := strconv.Atoi(query); err == nil {
+				result.Method = "id"
+				project, err := lookupProject(ctx, c, query)
+				if err != nil {
+					return APIErrorResult("Failed to get project", err)
+				}
+				result.Project = project
+				return JSONResult(result)
+			}
+
+			path, host := "", ""
+			switch {
+			case strings.Contains(query, "://"):
+				parsed, err := url.Parse(query)
+				if err != nil {
+					return ErrorResult(fmt.Sprintf("query is not a valid URL: %v", err))
+				}
+				result.Method = "url"
+				if parsed.Scheme == "ssh" || parsed.Scheme == "git+ssh" {
+					result.Method = "ssh"
+				}
+				host = parsed.Hostname()
+				urlPath := parsed.Path
+				if strings.EqualFold(host, gitLabHost(c)) {
+					urlPath = strings.TrimPrefix(urlPath, gitLabPathPrefix(c))
+				}
+				path, result.Resource = splitProjectURLPath(urlPath)
+			case scpRemotePattern.MatchString(query):
+				m := scpRemotePattern.FindStringSubmatch(query)
+				result.Method = "ssh"
+				host = m[1]
+				path, _ = splitProjectURLPath(m[2])
+			case strings.Contains(query, "/"):
+				result.Method = "path"
+				path, result.Resource = splitProjectURLPath(query)
+			}
+
+			if path != "" {
+				if host != "" && !strings.EqualFold(host, gitLabHost(c)) {
+					result.Note = fmt.Sprintf("%s is not the configured GitLab host (%s); looked up the path there anyway", host, gitLabHost(c))
+				}
+				project, err := lookupProjectPath(ctx, c, path)
+				if err == nil {
+					result.Project = project
+					return JSONResult(result)
+				}
+				if !gitlab.IsNotFound(err) {
+					return APIErrorResult("Failed to get project", err)
+				}
+				// Fall back to searching by the last path segment
+				result.Method = "search"
+				result.Resource = nil
+				result.Note = strings.TrimSpace(fmt.Sprintf("No project at %s. %s", path, result.Note))
+			} else {
+				result.Method = "search"
+			}
+
+			candidates, err := searchProjectCandidates(ctx, c, query, path)
+			if err != nil {
+				return APIErrorResult("Failed to search projects", err)
+			}
+			if len(candidates) == 0 {
+				return ErrorResult(fmt.Sprintf("no project matches %q", query))
+			}
+			result.Project = &candidates[0]
+			result.Ambiguous = len(candidates) > 1 && candidates[1].Score == candidates[0].Score
+			result.Candidates = candidates
+			return JSONResult(result)
+		},
+	)
+}
+
+// lookupProject gets a project by ID or path.
+func lookupProject(ctx context.Context, c *ToolContext, projectID string) (*ResolvedProject, error) {
+	var project gitlab.Project
+	if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s", url.PathEscape(projectID)), &project); err != nil {
+		return nil, err
+	}
+	return &ResolvedProject{
+		ID:                project.ID,
+		PathWithNamespace: project.PathWithNamespace,
+		NameWithNamespace: project.NameWithNamespace,
+		DefaultBranch:     project.DefaultBranch,
+		WebURL:            project.WebURL,
+	}, nil
+}
+
+// lookupProjectPath gets the project at path, retrying with shorter paths
+// for old-style deep links without "/-/" (e.g. group/project/issues/5).
+func lookupProjectPath(ctx context.Context, c *ToolContext, path string) (*ResolvedProject, error) {
+	segments := strings.Split(path, "/")
+	var firstErr error
+	for n := len(segments); n >= 2 && n >= len(segments)-2; n-- {
+		project, err := lookupProject(ctx, c, strings.Join(segments[:n], "/"))
+		if err == nil {
+			return project, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if !gitlab.IsNotFound(err) {
+			break
+		}
+	}
+	return nil, firstErr
+}
+
+// splitProjectURLPath extracts the project path from the path of a web URL or
+// git remote, and the resource after "/-/" if any.
+func splitProjectURLPath(path string) (string, *URLResource) {
+	path = strings.Trim(path, "/")
+	var resource *URLResource
+	if i := strings.Index(path, "/-/"); i >= 0 {
+		resource = parseURLResource(path[i+3:])
+		path = path[:i]
+	} else if strings.HasSuffix(path, "/-") {
+		path = strings.TrimSuffix(path, "/-")
+	}
+	path = strings.TrimSuffix(path, ".git")
+	if decoded, err := url.PathUnescape(path); err == nil {
+		path = decoded
+	}
+	return path, resource
+}
+
+// parseURLResource describes the part of a web URL after "/-/".
+func parseURLResource(rest string) *URLResource {
+	m := urlResourcePattern.FindStringSubmatch(strings.SplitN(rest, "#", 2)[0])
+	if m == nil {
+		return nil
+	}
+	resource := &URLResource{Type: strings.TrimSuffix(m[1], "s")}
+	number, _ := strconv.Atoi(m[2])
+	switch m[1] {
+	case "merge_requests", "issues":
+		resource.IID = number
+	case "pipelines", "jobs":
+		resource.ID = number
+	case "commit":
+		resource.Ref = m[2]
+	case "tree", "blob":
+		if m[1] == "blob" {
+			resource.Type = "file"
+		}
+		resource.Ref = m[2]
+		resource.Path = m[3]
+	}
+	if resource.IID == 0 && resource.ID == 0 && resource.Ref == "" {
+		return nil
+	}
+	return resource
+}
+
+// searchProjectCandidates searches projects by the last word of the query
+// and ranks the matches against the whole query.
+func searchProjectCandidates(ctx context.Context, c *ToolContext, query, path string) ([]ResolvedProject, error) {
+	term := query
+	if path != "" {
+		term = path[strings.LastIndex(path, "/")+1:]
+	}
+	normalized := normalizeProjectName(term)
+	fields := strings.Fields(normalized)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	// GitLab searches names and paths for the longest word; the ranking
+	// below checks the others
+	search := fields[0]
+	for _, field := range fields {
+		if len(field) > len(search) {
+			search = field
+		}
+	}
+
+	params := url.Values{}
+	params.Set("search", search)
+	params.Set("search_namespaces", "true")
+	params.Set("simple", "true")
+	params.Set("order_by", "last_activity_at")
+	params.Set("per_page", "50")
+	var projects []gitlab.Project
+	if err := c.Client.Get(ctx, "/projects?"+params.Encode(), &projects); err != nil {
+		return nil, err
+	}
+
+	wanted := normalizeProjectName(query)
+	if path != "" {
+		wanted = normalizeProjectName(path)
+	}
+	var candidates []ResolvedProject
+	for _, project := range projects {
+		score := scoreProjectMatch(wanted, fields, project)
+		if score == 0 {
+			continue
+		}
+		candidates = append(candidates, ResolvedProject{
+			ID:                project.ID,
+			PathWithNamespace: project.PathWithNamespace,
+			NameWithNamespace: project.NameWithNamespace,
+			DefaultBranch:     project.DefaultBranch,
+			WebURL:            project.WebURL,
+			Score:             score,
+		})
+	}
+	// Stable, so equal scores keep GitLab's most-recently-active order
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	if len(candidates) > maxResolveCandidates {
+		candidates = candidates[:maxResolveCandidates]
+	}
+	return candidates, nil
+}
+
+// scoreProjectMatch rates how well a project matches a normalized query, from
+// 100 (its full path) down to 20 (all query words appear somewhere); 0 means
+// no match.
+func scoreProjectMatch(wanted string, words []string, project gitlab.Project) int {
+	fullPath := normalizeProjectName(project.PathWithNamespace)
+	fullName := normalizeProjectName(project.NameWithNamespace)
+	path := normalizeProjectName(project.Path)
+	name := normalizeProjectName(project.Name)
+	switch {
+	case wanted == fullPath || wanted == fullName:
+		return 100
+	case wanted == path || wanted == name:
+		return 80
+	case strings.HasSuffix(fullPath, " "+wanted) || strings.HasSuffix(fullName, " "+wanted):
+		return 70
+	case strings.HasPrefix(path, wanted) || strings.HasPrefix(name, wanted) || strings.HasPrefix(fullPath, wanted):
+		return 50
+	case strings.Contains(path, wanted) || strings.Contains(name, wanted):
+		return 40
+	}
+	haystack := fullPath + " " + fullName
+	for _, word := range words {
+		if !strings.Contains(haystack, word) {
+			return 0
+		}
+	}
+	return 20
+}
+
+// normalizeProjectName lowercases a name or path and turns separators into
+// single spaces, so "Payments API", "payments-api" and "payments_api" compare
+// equal.
+func normalizeProjectName(s string) string {
+	s = strings.ToLower(s)
+	s = strings.NewReplacer("/", " ", "-", " ", "_", " ", ".", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// gitLabHost returns the host name of the configured GitLab API URL.
+func gitLabHost(c *ToolContext) string {
+	if c.Config == nil {
+		return ""
+	}
+	parsed, err := url.Parse(c.Config.GitLabAPIURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// gitLabPathPrefix returns the relative URL root of a GitLab installed under
+// a subpath (e.g. /gitlab for https://example.com/gitlab/api/v4).
+func gitLabPathPrefix(c *ToolContext) string {
+	if c.Config == nil {
+		return ""
+	}
+	parsed, err := url.Parse(c.Config.GitLabAPIURL)
+	if err != nil {
+		return ""
+	}
+	prefix, _, _ := strings.Cut(parsed.Path, "/api/")
+	return strings.TrimSuffix(prefix, "/")
+}
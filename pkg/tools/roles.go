@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// readToolPrefixes name tools that only read from GitLab. Tools are also
+// read-only when annotated with ReadOnlyHint.
+var readToolPrefixes = []string{"get_", "list_", "search_"}
+
+// readToolsWithoutPrefix are read-only tools whose names do not say so.
+var readToolsWithoutPrefix = map[string]bool{
+	"my_issues":        true,
+	"mr_discussions":   true,
+	"verify_namespace": true,
+}
+
+// maintainerToolPrefixes name tools that delete data or bypass review.
+var maintainerToolPrefixes = []string{"delete_", "merge_", "import_", "promote_"}
+
+// maintainerTools are further tools that change project settings, publish
+// releases or packages, or move data in or out of GitLab.
+var maintainerTools = map[string]bool{
+	"create_freeze_period":     true,
+	"create_push_mirror":       true,
+	"update_push_mirror":       true,
+	"sync_push_mirror":         true,
+	"schedule_project_export":  true,
+	"download_project_export":  true,
+	"create_release":           true,
+	"update_release":           true,
+	"create_release_evidence":  true,
+	"upload_generic_package":   true,
+	"create_repository":        true,
+	"update_ci_settings":       true,
+	"create_broadcast_message": true,
+	"update_broadcast_message": true,
+	"create_agent_token":       true,
+	"revoke_agent_token":       true,
+}
+
+// isReadOnlyTool reports whether a tool only reads from GitLab.
+func isReadOnlyTool(tool mcp.Tool) bool {
+	if tool.Annotations != nil && tool.Annotations.ReadOnlyHint {
+		return true
+	}
+	for _, prefix := range readToolPrefixes {
+		if strings.HasPrefix(tool.Name, prefix) {
+			return true
+		}
+	}
+	return readToolsWithoutPrefix[tool.Name]
+}
+
+// ReadOnlyTool reports whether a tool only reads from GitLab, for
+// mcp.Server.SetReadOnlyTools.
+func ReadOnlyTool(tool mcp.Tool) bool {
+	return isReadOnlyTool(tool)
+}
+
+// requiredRole returns the lowest role that may call a tool.
+func requiredRole(tool mcp.Tool) auth.Role {
+	if isReadOnlyTool(tool) {
+		return auth.RoleViewer
+	}
+	if maintainerTools[tool.Name] {
+		return auth.RoleMaintainer
+	}
+	for _, prefix := range maintainerToolPrefixes {
+		if strings.HasPrefix(tool.Name, prefix) {
+			return auth.RoleMaintainer
+		}
+	}
+	return auth.RoleContributor
+}
+
+// ToolAccess restricts the tools of authenticated HTTP callers to their role
+// (MCP_ROLES, MCP_DEFAULT_ROLE). Callers without a principal (stdio mode or
+// HTTP without authentication) and all callers when no roles are configured
+// may use every tool.
+func ToolAccess(ctx context.Context, tool mcp.Tool) bool {
+	return roleIncludes(ctx, requiredRole(tool))
+}
+
+// roleIncludes reports whether the caller of ctx has at least role. Callers
+// are unrestricted without MCP_ROLES or an authenticated principal.
+func roleIncludes(ctx context.Context, role auth.Role) bool {
+	c := FromContext(ctx)
+	if c == nil || c.Config == nil || len(c.Config.Roles) == 0 {
+		return true
+	}
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return true
+	}
+	granted, ok := c.Config.Roles[principal]
+	if !ok {
+		granted = c.Config.DefaultRole
+	}
+	return granted.Includes(role)
+}
@@ -0,0 +1,133 @@
+package tools
+
+import "github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+
+// Output schemas for tools that return JSON, advertised as outputSchema so
+// clients can validate structuredContent. Only fields that are always present
+// with a fixed type are typed; nullable fields (timestamps, users, milestones)
+// are described without a type so that null values still validate.
+
+// userOutputProperty describes an embedded GitLab user, which may be null.
+var userOutputProperty = mcp.Property{
+	Description: "User (id, username, name, state, web_url) or null",
+}
+
+// paginationOutputProperty describes the pagination object returned by list tools.
+var paginationOutputProperty = mcp.Property{
+	Description: "Pagination info (page, per_page, total, total_pages, next_page, prev_page) or null",
+}
+
+// issueOutputProperty describes a GitLab issue.
+var issueOutputProperty = mcp.Property{
+	Type: "object",
+	Properties: map[string]mcp.Property{
+		"id":           {Type: "integer", Description: "Global issue ID"},
+		"iid":          {Type: "integer", Description: "Project-scoped issue IID"},
+		"project_id":   {Type: "integer"},
+		"title":        {Type: "string"},
+		"description":  {Type: "string"},
+		"state":        {Type: "string", Description: "opened or closed"},
+		"web_url":      {Type: "string"},
+		"confidential": {Type: "boolean"},
+		"labels":       {Description: "Label names"},
+		"author":       userOutputProperty,
+		"assignees":    {Description: "Assigned users"},
+		"milestone":    {Description: "Milestone or null"},
+		"created_at":   {Description: "ISO 8601 timestamp"},
+		"updated_at":   {Description: "ISO 8601 timestamp"},
+		"closed_at":    {Description: "ISO 8601 timestamp or null"},
+	},
+}
+
+// mergeRequestOutputProperty describes a GitLab merge request.
+var mergeRequestOutputProperty = mcp.Property{
+	Type: "object",
+	Properties: map[string]mcp.Property{
+		"id":                {Type: "integer", Description: "Global merge request ID"},
+		"iid":               {Type: "integer", Description: "Project-scoped merge request IID"},
+		"project_id":        {Type: "integer"},
+		"title":             {Type: "string"},
+		"description":       {Type: "string"},
+		"state":             {Type: "string", Description: "opened, closed, merged or locked"},
+		"source_branch":     {Type: "string"},
+		"target_branch":     {Type: "string"},
+		"source_project_id": {Type: "integer"},
+		"target_project_id": {Type: "integer"},
+		"merge_status":      {Type: "string"},
+		"sha":               {Type: "string", Description: "Head commit SHA"},
+		"draft":             {Type: "boolean"},
+		"web_url":           {Type: "string"},
+		"labels":            {Description: "Label names"},
+		"author":            userOutputProperty,
+		"assignees":         {Description: "Assigned users"},
+		"reviewers":         {Description: "Reviewer users"},
+		"squash":            {Type: "boolean"},
+		"merged_by":         userOutputProperty,
+		"diff_refs":         {Description: "base_sha, head_sha and start_sha, or null"},
+		"created_at":        {Description: "ISO 8601 timestamp"},
+		"updated_at":        {Description: "ISO 8601 timestamp"},
+		"merged_at":         {Description: "ISO 8601 timestamp or null"},
+	},
+}
+
+// pipelineOutputProperty describes a GitLab pipeline.
+var pipelineOutputProperty = mcp.Property{
+	Type: "object",
+	Properties: map[string]mcp.Property{
+		"id":          {Type: "integer", Description: "Pipeline ID"},
+		"iid":         {Type: "integer", Description: "Project-scoped pipeline IID"},
+		"project_id":  {Type: "integer"},
+		"sha":         {Type: "string"},
+		"ref":         {Type: "string"},
+		"status":      {Type: "string", Description: "created, pending, running, success, failed, canceled, skipped, manual or scheduled"},
+		"source":      {Type: "string"},
+		"web_url":     {Type: "string"},
+		"user":        userOutputProperty,
+		"created_at":  {Description: "ISO 8601 timestamp"},
+		"updated_at":  {Description: "ISO 8601 timestamp"},
+		"started_at":  {Description: "ISO 8601 timestamp or null"},
+		"finished_at": {Description: "ISO 8601 timestamp or null"},
+	},
+}
+
+// jobOutputProperty describes a GitLab CI/CD job.
+var jobOutputProperty = mcp.Property{
+	Type: "object",
+	Properties: map[string]mcp.Property{
+		"id":          {Type: "integer", Description: "Job ID"},
+		"name":        {Type: "string"},
+		"stage":       {Type: "string"},
+		"status":      {Type: "string"},
+		"ref":         {Type: "string"},
+		"tag":         {Type: "boolean"},
+		"web_url":     {Type: "string"},
+		"duration":    {Description: "Seconds, omitted if not started"},
+		"pipeline":    {Description: "Parent pipeline summary"},
+		"user":        userOutputProperty,
+		"created_at":  {Description: "ISO 8601 timestamp"},
+		"started_at":  {Description: "ISO 8601 timestamp or null"},
+		"finished_at": {Description: "ISO 8601 timestamp or null"},
+	},
+}
+
+// objectOutputSchema builds an outputSchema for a tool returning a single object.
+func objectOutputSchema(item mcp.Property, required ...string) *mcp.JSONSchema {
+	return &mcp.JSONSchema{
+		Type:       "object",
+		Properties: item.Properties,
+		Required:   required,
+	}
+}
+
+// pagedOutputSchema builds an outputSchema for list tools returning the
+// {"items": [...], "pagination": {...}} envelope (see PagedJSONResult).
+func pagedOutputSchema(item mcp.Property) *mcp.JSONSchema {
+	return &mcp.JSONSchema{
+		Type: "object",
+		Properties: map[string]mcp.Property{
+			"items":      {Type: "array", Items: &item},
+			"pagination": paginationOutputProperty,
+		},
+		Required: []string{"items"},
+	}
+}
@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// Submodule is a submodule declared in .gitmodules and the commit the
+// superproject pins it to. Project and ProjectURL are set when the submodule
+// lives on the same GitLab instance; CommitURL links the pinned commit.
+type Submodule struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	URL        string `json:"url"`
+	Branch     string `json:"branch,omitempty"`
+	CommitSHA  string `json:"commit_sha,omitempty"`
+	Project    string `json:"project,omitempty"`
+	ProjectURL string `json:"project_url,omitempty"`
+	CommitURL  string `json:"commit_url,omitempty"`
+	Note       string `json:"note,omitempty"`
+}
+
+// SubmodulesResult is the response of the get_submodules tool.
+type SubmodulesResult struct {
+	Project    string      `json:"project"`
+	Ref        string      `json:"ref"`
+	Submodules []Submodule `json:"submodules"`
+	Note       string      `json:"note,omitempty"`
+}
+
+// parseGitmodules parses the submodule sections of a .gitmodules file, in
+// file order. Sections without a path are dropped.
+func parseGitmodules(content string) []Submodule {
+	var submodules []Submodule
+	var current *Submodule
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			current = nil
+			header := strings.TrimSpace(strings.Trim(line, "[]"))
+			if name, ok := strings.CutPrefix(header, "submodule"); ok {
+				submodules = append(submodules, Submodule{Name: strings.Trim(strings.TrimSpace(name), `"`)})
+				current = &submodules[len(submodules)-1]
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "path":
+			current.Path = strings.Trim(value, "/")
+		case "url":
+			current.URL = value
+		case "branch":
+			current.Branch = value
+		}
+	}
+
+	kept := submodules[:0]
+	for _, submodule := range submodules {
+		if submodule.Path != "" {
+			kept = append(kept, submodule)
+		}
+	}
+	return kept
+}
+
+// submoduleProject returns the project path of a submodule URL on the GitLab
+// instance at webBase (e.g. https://gitlab.com), or "" when the submodule is
+// hosted elsewhere. Relative URLs such as ../lib.git are resolved against the
+// superproject's path, as git does.
+func submoduleProject(rawURL, superproject, webBase string) string {
+	if strings.HasPrefix(rawURL, "./") || strings.HasPrefix(rawURL, "../") {
+		resolved := path.Clean(path.Join(superproject, rawURL))
+		if strings.HasPrefix(resolved, "..") {
+			return ""
+		}
+		project, _ := splitProjectURLPath(resolved)
+		return project
+	}
+
+	base, err := url.Parse(webBase)
+	if err != nil {
+		return ""
+	}
+	host, urlPath := "", ""
+	if m := scpRemotePattern.FindStringSubmatch(rawURL); m != nil && !strings.Contains(rawURL, "://") {
+		host, urlPath = m[1], m[2]
+	} else if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		host, urlPath = parsed.Hostname(), parsed.Path
+		if parsed.Scheme == "http" || parsed.Scheme == "https" {
+			urlPath = strings.TrimPrefix(urlPath, strings.TrimSuffix(base.Path, "/"))
+		}
+	}
+	if host == "" || !strings.EqualFold(host, base.Hostname()) {
+		return ""
+	}
+	project, _ := splitProjectURLPath(urlPath)
+	return project
+}
+
+type getSubmodulesArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	Ref       string `json:"ref"`
+}
+
+// registerGetSubmodules registers the get_submodules tool.
+func registerGetSubmodules(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_submodules",
+			Description: "List the git submodules of a project at a ref: the name, path, URL and branch from .gitmodules, and the commit SHA the superproject pins each one to, read from the repository tree. Submodules on this GitLab instance (including relative URLs like ../lib.git) are resolved to their project path, project URL and a link to the pinned commit, to trace cross-repository builds.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Branch name, tag, or commit SHA (default: default branch)",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_submodules", func(ctx context.Context, c *ToolContext, args getSubmodulesArgs) (*mcp.CallToolResult, error) {
+			encodedProjectID := url.PathEscape(args.ProjectID)
+			var project gitlab.Project
+			if err := c.Client.Get(ctx, "/projects/"+encodedProjectID, &project); err != nil {
+				return APIErrorResult("Failed to get project", err)
+			}
+			ref := args.Ref
+			if ref == "" {
+				ref = project.DefaultBranch
+			}
+			result := SubmodulesResult{Project: project.PathWithNamespace, Ref: ref, Submodules: []Submodule{}}
+
+			content, err := c.Client.GetText(ctx, fmt.Sprintf("/projects/%s/repository/files/.gitmodules/raw?ref=%s", encodedProjectID, url.QueryEscape(ref)))
+			if gitlab.IsNotFound(err) {
+				result.Note = fmt.Sprintf("no .gitmodules at %s", ref)
+				return JSONResult(result)
+			}
+			if err != nil {
+				return APIErrorResult("Failed to get .gitmodules", err)
+			}
+			result.Submodules = append(result.Submodules, parseGitmodules(content)...)
+
+			// Submodules are tree entries of type commit; list each parent
+			// directory once
+			var dirs []string
+			for _, submodule := range result.Submodules {
+				if dir := path.Dir(submodule.Path); !containsString(dirs, dir) {
+					dirs = append(dirs, dir)
+				}
+			}
+			sort.Strings(dirs)
+			pinned := map[string]string{}
+			for _, dir := range dirs {
+				params := url.Values{}
+				params.Set("ref", ref)
+				if dir != "." {
+					params.Set("path", dir)
+				}
+				nodes, _, err := collectPages[gitlab.TreeNode](ctx, c.Client, fmt.Sprintf("/projects/%s/repository/tree?%s", encodedProjectID, params.Encode()), maxCollectedItems)
+				if err != nil && !gitlab.IsNotFound(err) {
+					return APIErrorResult(fmt.Sprintf("Failed to get the repository tree of %s", dir), err)
+				}
+				for _, node := range nodes {
+					if node.Type == "commit" {
+						pinned[node.Path] = node.ID
+					}
+				}
+			}
+
+			webBase := strings.TrimSuffix(strings.TrimSuffix(project.WebURL, project.PathWithNamespace), "/")
+			for i := range result.Submodules {
+				submodule := &result.Submodules[i]
+				submodule.CommitSHA = pinned[submodule.Path]
+				if submodule.CommitSHA == "" {
+					submodule.Note = fmt.Sprintf("no submodule entry at %s in the tree; it was removed or never committed", submodule.Path)
+				}
+				submodule.Project = submoduleProject(submodule.URL, project.PathWithNamespace, webBase)
+				if submodule.Project == "" {
+					if submodule.Note == "" {
+						submodule.Note = "hosted outside this GitLab instance"
+					}
+					continue
+				}
+				submodule.ProjectURL = webBase + "/" + submodule.Project
+				if submodule.CommitSHA != "" {
+					submodule.CommitURL = submodule.ProjectURL + "/-/commit/" + submodule.CommitSHA
+				}
+			}
+			return JSONResult(result)
+		}),
+	)
+}
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetSubmodules(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/acme%2Fapp", 200, `{"id": 7, "path_with_namespace": "acme/app", "default_branch": "main", "web_url": "https://gitlab.example.com/acme/app"}`)
+	client.Handle("GET", "/projects/acme%2Fapp/repository/files/.gitmodules/raw?ref=v1.2", 200, `# vendored code
+[submodule "lib"]
+	path = vendor/lib
+	url = ../shared/lib.git
+	branch = stable
+[submodule "proto"]
+	path = proto
+	url = git@gitlab.example.com:acme/proto.git
+[submodule "ext"]
+	path = vendor/ext
+	url = https://github.com/example/ext.git
+`)
+	client.Handle("GET", "/projects/acme%2Fapp/repository/tree?path=vendor&ref=v1.2&page=1&per_page=100", 200, `[
+		{"id": "aaa111", "name": "lib", "type": "commit", "path": "vendor/lib", "mode": "160000"},
+		{"id": "bbb222", "name": "ext", "type": "commit", "path": "vendor/ext", "mode": "160000"},
+		{"id": "ccc333", "name": "README.md", "type": "blob", "path": "vendor/README.md", "mode": "100644"}
+	]`)
+	client.Handle("GET", "/projects/acme%2Fapp/repository/tree?ref=v1.2&page=1&per_page=100", 200, `[
+		{"id": "ddd444", "name": "proto", "type": "commit", "path": "proto", "mode": "160000"}
+	]`)
+
+	res := callTool(t, tc, "get_submodules", map[string]interface{}{"project_id": "acme/app", "ref": "v1.2"})
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, res))
+	}
+	var result SubmodulesResult
+	if err := json.Unmarshal([]byte(resultText(t, res)), &result); err != nil {
+		t.Fatal(err)
+	}
+	want := []Submodule{
+		{Name: "lib", Path: "vendor/lib", URL: "../shared/lib.git", Branch: "stable", CommitSHA: "aaa111", Project: "acme/shared/lib",
+			ProjectURL: "https://gitlab.example.com/acme/shared/lib", CommitURL: "https://gitlab.example.com/acme/shared/lib/-/commit/aaa111"},
+		{Name: "proto", Path: "proto", URL: "git@gitlab.example.com:acme/proto.git", CommitSHA: "ddd444", Project: "acme/proto",
+			ProjectURL: "https://gitlab.example.com/acme/proto", CommitURL: "https://gitlab.example.com/acme/proto/-/commit/ddd444"},
+		{Name: "ext", Path: "vendor/ext", URL: "https://github.com/example/ext.git", CommitSHA: "bbb222", Note: "hosted outside this GitLab instance"},
+	}
+	if !reflect.DeepEqual(result.Submodules, want) {
+		t.Errorf("submodules = %+v\nwant %+v", result.Submodules, want)
+	}
+	if unmatched := client.Unmatched(); len(unmatched) > 0 {
+		t.Errorf("unmatched requests: %v", unmatched)
+	}
+
+	client.Handle("GET", "/projects/acme%2Fapp/repository/files/.gitmodules/raw?ref=main", 404, `{"message": "404 File Not Found"}`)
+	res = callTool(t, tc, "get_submodules", map[string]interface{}{"project_id": "acme/app"})
+	if res.IsError || !strings.Contains(resultText(t, res), "no .gitmodules at main") {
+		t.Errorf("result = %s", resultText(t, res))
+	}
+}
+
+func TestParseGitmodules(t *testing.T) {
+	got := parseGitmodules(`; comment
+[core]
+	path = not-a-submodule
+[submodule "docs"]
+	path = "/docs/site/"
+	URL = https://gitlab.example.com/acme/site.git
+[submodule "broken"]
+	url = ../broken.git
+[submodule   "spaced name"  ]
+	path=tools
+	url=../tools.git
+	junk line
+`)
+	want := []Submodule{
+		{Name: "docs", Path: "docs/site", URL: "https://gitlab.example.com/acme/site.git"},
+		{Name: "spaced name", Path: "tools", URL: "../tools.git"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGitmodules = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestSubmoduleProject(t *testing.T) {
+	tests := []struct {
+		url     string
+		webBase string
+		want    string
+	}{
+		{"../lib.git", "https://gitlab.example.com", "acme/lib"},
+		{"./sub/lib.git", "https://gitlab.example.com", "acme/app/sub/lib"},
+		{"../../../outside.git", "https://gitlab.example.com", ""},
+		{"git@GITLAB.example.com:acme/proto.git", "https://gitlab.example.com", "acme/proto"},
+		{"ssh://git@gitlab.example.com:2222/acme/proto.git", "https://gitlab.example.com", "acme/proto"},
+		{"https://gitlab.example.com/gitlab/acme/proto.git", "https://gitlab.example.com/gitlab", "acme/proto"},
+		{"https://github.com/acme/proto.git", "https://gitlab.example.com", ""},
+		{"not a url", "https://gitlab.example.com", ""},
+	}
+	for _, tt := range tests {
+		if got := submoduleProject(tt.url, "acme/app", tt.webBase); got != tt.want {
+			t.Errorf("submoduleProject(%q, %q) = %q, want %q", tt.url, tt.webBase, got, tt.want)
+		}
+	}
+}
+
+func TestGetSubmodulesUncommitted(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("GET", "/projects/acme%2Fapp", 200, `{"id": 7, "path_with_namespace": "acme/app", "default_branch": "main", "web_url": "https://gitlab.example.com/acme/app"}`)
+	client.Handle("GET", "/projects/acme%2Fapp/repository/files/.gitmodules/raw?ref=main", 200, "[submodule \"lib\"]\n\tpath = vendor/lib\n\turl = ../lib.git\n")
+	client.Handle("GET", "/projects/acme%2Fapp/repository/tree?path=vendor&ref=main&page=1&per_page=100", 404, `{"message": "404 Tree Not Found"}`)
+
+	res := callTool(t, tc, "get_submodules", map[string]interface{}{"project_id": "acme/app"})
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", res.Content[0].Text)
+	}
+	var result SubmodulesResult
+	if err := json.Unmarshal([]byte(resultText(t, res)), &result); err != nil {
+		t.Fatal(err)
+	}
+	// Without a pinned commit there is nothing to link to
+	if s := result.Submodules[0]; s.CommitSHA != "" || s.CommitURL != "" || s.Project != "acme/lib" || !strings.Contains(s.Note, "removed or never committed") {
+		t.Errorf("submodule = %+v", s)
+	}
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// sudoProperty is the argument SudoMiddleware adds to write tools.
+var sudoProperty = mcp.Property{
+	Type:        "string",
+	Description: "Username or ID of the user to act as, so the change is attributed to them. Requires an administrator token with the sudo scope; every use is logged and audited",
+}
+
+// SudoMiddleware adds a sudo argument to every tool that is not read-only
+// (see isReadOnlyTool). A call with sudo makes its GitLab requests as that
+// user through the Sudo header. It is only installed when GITLAB_ALLOW_SUDO is
+// set; with MCP_ROLES, only maintainers may use it. The audit log records the
+// user in its sudo field.
+func SudoMiddleware(tool mcp.Tool, handler mcp.ToolHandler) (mcp.Tool, mcp.ToolHandler) {
+	if isReadOnlyTool(tool) {
+		return tool, handler
+	}
+	if _, exists := tool.InputSchema.Properties["sudo"]; exists {
+		return tool, handler
+	}
+
+	properties := make(map[string]mcp.Property, len(tool.InputSchema.Properties)+1)
+	for name, p := range tool.InputSchema.Properties {
+		properties[name] = p
+	}
+	properties["sudo"] = sudoProperty
+	tool.InputSchema.Properties = properties
+
+	wrapped := func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		user := GetString(args, "sudo", "")
+		if user == "" {
+			return handler(ctx, args)
+		}
+		if !roleIncludes(ctx, auth.RoleMaintainer) {
+			return ErrorResult("sudo requires the maintainer role")
+		}
+		if c := FromContext(ctx); c != nil && c.Logger != nil {
+			c.Logger.WarnContext(ctx, "%s called with sudo as %s", tool.Name, user)
+		}
+		return handler(gitlab.WithSudo(ctx, user), args)
+	}
+	return tool, wrapped
+}
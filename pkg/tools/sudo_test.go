@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/auth"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+func TestSudoMiddleware(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("POST", "/projects/42/issues/7/notes", 201, `{"id": 1, "body": "Done"}`)
+	client.Handle("GET", "/users?username=alice", 200, `[{"id": 5, "username": "alice", "state": "active", "bot": false}]`)
+
+	registered := map[string]mcp.Tool{}
+	handlers := map[string]mcp.ToolHandler{}
+	server := mcp.NewServer("test", "0.0.0")
+	server.UseToolMiddleware(func(tool mcp.Tool, h mcp.ToolHandler) (mcp.Tool, mcp.ToolHandler) {
+		registered[tool.Name], handlers[tool.Name] = tool, h
+		return tool, h
+	})
+	server.UseToolMiddleware(SudoMiddleware)
+	if _, err := Register(server, tc); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, ok := registered["create_note"].InputSchema.Properties["sudo"]; !ok {
+		t.Error("create_note has no sudo argument")
+	}
+	if _, ok := registered["get_user_by_username"].InputSchema.Properties["sudo"]; ok {
+		t.Error("read-only get_user_by_username has a sudo argument")
+	}
+
+	ctx := WithToolContext(context.Background(), tc)
+	result, err := handlers["get_user_by_username"](ctx, map[string]interface{}{"username": "@alice"})
+	if err != nil || result.IsError {
+		t.Fatalf("get_user_by_username: %v %s", err, resultText(t, result))
+	}
+	var user UserDetails
+	if err := json.Unmarshal([]byte(resultText(t, result)), &user); err != nil || user.ID != 5 {
+		t.Fatalf("user = %+v, %v", user, err)
+	}
+
+	args := map[string]interface{}{"project_id": "42", "noteable_type": "issue", "noteable_iid": 7, "body": "Done", "sudo": "alice"}
+	result, err = handlers["create_note"](ctx, args)
+	if err != nil || result.IsError {
+		t.Fatalf("create_note: %v %s", err, resultText(t, result))
+	}
+	requests := client.Requests()
+	if last := requests[len(requests)-1]; last.Method != "POST" || last.Sudo != "alice" {
+		t.Errorf("request = %+v, want a POST as alice", last)
+	}
+
+	tc.Config.Roles = map[string]auth.Role{"bot": auth.RoleContributor}
+	result, _ = handlers["create_note"](auth.WithPrincipal(ctx, "bot"), args)
+	if !result.IsError || !strings.Contains(resultText(t, result), "maintainer") {
+		t.Errorf("sudo as a contributor = %s", resultText(t, result))
+	}
+	if got := len(client.Requests()); got != len(requests) {
+		t.Errorf("%d requests after a denied sudo call", got-len(requests))
+	}
+}
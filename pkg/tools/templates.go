@@ -0,0 +1,432 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// templateTypes are the template types served by the project templates API.
+// issues and merge_requests come from the repository's .gitlab/issue_templates
+// and .gitlab/merge_request_templates directories.
+var templateTypes = []string{"issues", "merge_requests", "licenses", "gitignores", "gitlab_ci_ymls", "dockerfiles"}
+
+// TemplateSummary is an entry of a template listing.
+type TemplateSummary struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Template is a single template with its content. License templates carry
+// the additional metadata fields.
+type Template struct {
+	Key         string   `json:"key,omitempty"`
+	Name        string   `json:"name"`
+	Content     string   `json:"content"`
+	Nickname    string   `json:"nickname,omitempty"`
+	Description string   `json:"description,omitempty"`
+	HTMLURL     string   `json:"html_url,omitempty"`
+	SourceURL   string   `json:"source_url,omitempty"`
+	Conditions  []string `json:"conditions,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Limitations []string `json:"limitations,omitempty"`
+}
+
+// templateTypeProperty is the shared type argument of the template tools.
+var templateTypeProperty = mcp.Property{
+	Type:        "string",
+	Description: "Template type: issues and merge_requests are the project's description templates; licenses, gitignores, gitlab_ci_ymls and dockerfiles include instance-wide templates",
+	Enum:        templateTypes,
+}
+
+// registerListProjectTemplates registers the list_project_templates tool.
+func registerListProjectTemplates(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_project_templates",
+			Description: "List the templates available to a project: issue and merge request description templates from the repository, or license, .gitignore, GitLab CI and Dockerfile templates. Fetch one with get_project_template.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"type": templateTypeProperty,
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+				Required: []string{"project_id", "type"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "list_project_templates", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			templateType := GetString(args, "type", "")
+			if templateType == "" {
+				return ErrorResult("type is required")
+			}
+
+			params := url.Values{}
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", strconv.Itoa(page))
+			}
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", strconv.Itoa(perPage))
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/templates/%s",
+				url.PathEscape(projectID),
+				url.PathEscape(templateType),
+			)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var templates []TemplateSummary
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &templates)
+			if err != nil {
+				return APIErrorResult("failed to list templates", err)
+			}
+
+			return PagedJSONResult(templates, pagination)
+		},
+	)
+}
+
+// registerGetProjectTemplate registers the get_project_template tool.
+func registerGetProjectTemplate(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_project_template",
+			Description: "Get the content of a template by type and key. Use issue and merge request templates as the description of create_issue and create_merge_request so new items follow the project's conventions.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"type": templateTypeProperty,
+					"name": {
+						Type:        "string",
+						Description: "The template key as returned by list_project_templates (e.g., Bug, mit, Go)",
+					},
+					"fullname": {
+						Type:        "string",
+						Description: "Copyright holder substituted into license templates",
+					},
+					"source_template_project_id": {
+						Type:        "integer",
+						Description: "Project ID holding the template when the instance uses custom file templates",
+					},
+				},
+				Required: []string{"project_id", "type", "name"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_project_template", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			templateType := GetString(args, "type", "")
+			if templateType == "" {
+				return ErrorResult("type is required")
+			}
+
+			name := GetString(args, "name", "")
+			if name == "" {
+				return ErrorResult("name is required")
+			}
+
+			params := url.Values{}
+			if fullname := GetString(args, "fullname", ""); fullname != "" {
+				params.Set("fullname", fullname)
+			}
+			if sourceProjectID := GetInt(args, "source_template_project_id", 0); sourceProjectID > 0 {
+				params.Set("source_template_project_id", strconv.Itoa(sourceProjectID))
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/templates/%s/%s",
+				url.PathEscape(projectID),
+				url.PathEscape(templateType),
+				url.PathEscape(name),
+			)
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var template Template
+			if err := c.Client.Get(ctx, endpoint, &template); err != nil {
+				return APIErrorResult("failed to get template", err)
+			}
+
+			return JSONResult(template)
+		},
+	)
+}
+
+// templateVariable matches a {{name}} placeholder in an issue template.
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// templateFrontMatter is the YAML front matter an issue template may start
+// with, between "---" lines.
+type templateFrontMatter struct {
+	Title        string   `yaml:"title"`
+	Labels       yamlList `yaml:"labels"`
+	Confidential bool     `yaml:"confidential"`
+}
+
+// yamlList is a YAML list that may also be written as a comma-separated string.
+type yamlList []string
+
+func (l *yamlList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		for _, item := range strings.Split(node.Value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*l = append(*l, item)
+			}
+		}
+		return nil
+	}
+	var items []string
+	if err := node.Decode(&items); err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
+
+// splitFrontMatter separates the YAML front matter of a template from its
+// body. A template without front matter is returned as the body.
+func splitFrontMatter(content string) (templateFrontMatter, string, error) {
+	var front templateFrontMatter
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return front, content, nil
+	}
+	header, body, found := strings.Cut(normalized[len("---\n"):], "\n---\n")
+	if !found {
+		if header, found = strings.CutSuffix(normalized[len("---\n"):], "\n---"); !found {
+			return front, content, nil
+		}
+		body = ""
+	}
+	if err := yaml.Unmarshal([]byte(header), &front); err != nil {
+		return front, "", fmt.Errorf("invalid front matter: %w", err)
+	}
+	return front, strings.TrimLeft(body, "\n"), nil
+}
+
+// fillTemplate replaces the {{name}} placeholders of text with variables and
+// adds the names of placeholders without a value to missing.
+func fillTemplate(text string, variables map[string]interface{}, missing map[string]bool) string {
+	return templateVariable.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := templateVariable.FindStringSubmatch(placeholder)[1]
+		switch value := variables[name].(type) {
+		case nil:
+		case string:
+			return value
+		case float64:
+			return formatNumber(value)
+		default:
+			return fmt.Sprint(value)
+		}
+		missing[name] = true
+		return placeholder
+	})
+}
+
+// TemplatedIssue is the response of the create_issue_from_template tool.
+type TemplatedIssue struct {
+	Template string        `json:"template"`
+	Issue    *gitlab.Issue `json:"issue,omitempty"`
+	// Title, Description and Labels are the filled-in template, returned
+	// without creating an issue for dry_run
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	// UnresolvedVariables are placeholders left in the issue because no
+	// variable was given for them
+	UnresolvedVariables []string `json:"unresolved_variables,omitempty"`
+}
+
+type createIssueFromTemplateArgs struct {
+	ProjectID    string                 `json:"project_id" validate:"required"`
+	TemplateName string                 `json:"template_name" validate:"required"`
+	Variables    map[string]interface{} `json:"variables"`
+	Title        string                 `json:"title"`
+	Labels       string                 `json:"labels"`
+	AssigneeIDs  []int                  `json:"assignee_ids"`
+	MilestoneID  int                    `json:"milestone_id"`
+	Strict       bool                   `json:"strict"`
+	DryRun       bool                   `json:"dry_run"`
+}
+
+// registerCreateIssueFromTemplate registers the create_issue_from_template tool.
+func registerCreateIssueFromTemplate(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "create_issue_from_template",
+			Description: "Create an issue from one of the project's issue templates (.gitlab/issue_templates). {{name}} placeholders in the template are replaced with variables. The template may start with YAML front matter between --- lines setting title, labels and confidential; labels are merged with the labels argument. Use list_project_templates with type=issues to find template names.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"template_name": {
+						Type:        "string",
+						Description: "The issue template key as returned by list_project_templates (e.g., Bug)",
+					},
+					"variables": {
+						Type:        "object",
+						Description: "Values for the template's {{name}} placeholders, e.g. {\"summary\": \"Checkout fails\", \"version\": \"1.4.2\"}",
+					},
+					"title": {
+						Type:        "string",
+						Description: "The issue title (optional if the template's front matter has one; placeholders are filled in)",
+					},
+					"labels": {
+						Type:        "string",
+						Description: "Comma-separated labels added to the template's labels",
+					},
+					"assignee_ids": {
+						Type:        "array",
+						Description: "Array of user IDs to assign the issue to",
+						Items:       &mcp.Property{Type: "integer"},
+					},
+					"milestone_id": {
+						Type:        "integer",
+						Description: "The ID of a milestone to assign the issue to",
+					},
+					"strict": {
+						Type:        "boolean",
+						Description: "Fail instead of creating the issue when placeholders have no variable (optional, default: false)",
+					},
+					"dry_run": {
+						Type:        "boolean",
+						Description: "Return the filled-in title, description and labels without creating the issue (optional, default: false)",
+					},
+				},
+				Required: []string{"project_id", "template_name"},
+			},
+		},
+		withArgs("create_issue_from_template", func(ctx context.Context, c *ToolContext, args createIssueFromTemplateArgs) (*mcp.CallToolResult, error) {
+			encodedProjectID := url.PathEscape(args.ProjectID)
+			var template Template
+			if err := c.Client.Get(ctx, fmt.Sprintf("/projects/%s/templates/issues/%s", encodedProjectID, url.PathEscape(args.TemplateName)), &template); err != nil {
+				return APIErrorResult("failed to get issue template "+args.TemplateName, err)
+			}
+			front, body, err := splitFrontMatter(template.Content)
+			if err != nil {
+				return ErrorResult(fmt.Sprintf("issue template %s: %v", args.TemplateName, err))
+			}
+
+			missing := map[string]bool{}
+			title := args.Title
+			if title == "" {
+				title = front.Title
+			}
+			if title == "" {
+				return ErrorResult(fmt.Sprintf("title is required: issue template %s has no title in its front matter", args.TemplateName))
+			}
+			result := TemplatedIssue{
+				Template:    args.TemplateName,
+				Title:       fillTemplate(title, args.Variables, missing),
+				Description: fillTemplate(body, args.Variables, missing),
+			}
+			seen := map[string]bool{}
+			for _, label := range append(front.Labels, strings.Split(args.Labels, ",")...) {
+				label = strings.TrimSpace(fillTemplate(label, args.Variables, missing))
+				if label != "" && !seen[strings.ToLower(label)] {
+					seen[strings.ToLower(label)] = true
+					result.Labels = append(result.Labels, label)
+				}
+			}
+			for name := range missing {
+				result.UnresolvedVariables = append(result.UnresolvedVariables, name)
+			}
+			sort.Strings(result.UnresolvedVariables)
+			if args.Strict && len(missing) > 0 {
+				return ErrorResult(fmt.Sprintf("no value for template variables: %s", strings.Join(result.UnresolvedVariables, ", ")))
+			}
+			if args.DryRun {
+				return JSONResult(result)
+			}
+
+			issueBody := map[string]interface{}{
+				"title":       result.Title,
+				"description": result.Description,
+			}
+			if len(result.Labels) > 0 {
+				issueBody["labels"] = strings.Join(result.Labels, ",")
+			}
+			if front.Confidential {
+				issueBody["confidential"] = true
+			}
+			if args.MilestoneID > 0 {
+				issueBody["milestone_id"] = args.MilestoneID
+			}
+			if len(args.AssigneeIDs) > 0 {
+				issueBody["assignee_ids"] = args.AssigneeIDs
+			}
+
+			var issue gitlab.Issue
+			if err := c.Client.Post(ctx, fmt.Sprintf("/projects/%s/issues", encodedProjectID), issueBody, &issue); err != nil {
+				return APIErrorResult("failed to create issue", err)
+			}
+			return JSONResult(TemplatedIssue{Template: args.TemplateName, Issue: &issue, UnresolvedVariables: result.UnresolvedVariables})
+		}),
+	)
+}
+
+// initTemplateTools registers the project template tools.
+func initTemplateTools(server *mcp.Server) {
+	registerListProjectTemplates(server)
+	registerGetProjectTemplate(server)
+	registerCreateIssueFromTemplate(server)
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCreateIssueFromTemplate(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/42/templates/issues/Bug", http.StatusOK, map[string]interface{}{
+		"name":    "Bug",
+		"content": "---\ntitle: \"Bug: {{ summary }}\"\nlabels: [bug, \"needs-triage\"]\nconfidential: true\n---\n\n## Summary\n\n{{summary}} in version {{version}}.\n\n## Logs\n\n{{logs}}\n",
+	})
+	client.Handle(http.MethodPost, "/projects/42/issues", http.StatusCreated, map[string]interface{}{"id": 100, "iid": 12, "title": "Bug: Checkout fails"})
+
+	args := map[string]interface{}{
+		"project_id":    "42",
+		"template_name": "Bug",
+		"variables":     map[string]interface{}{"summary": "Checkout fails", "version": 1.4},
+		"labels":        "Bug, payments",
+	}
+	result := callTool(t, tc, "create_issue_from_template", args)
+	if result.IsError {
+		t.Fatalf("create_issue_from_template failed: %s", resultText(t, result))
+	}
+	var got TemplatedIssue
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if got.Issue == nil || got.Issue.IID != 12 || strings.Join(got.UnresolvedVariables, ",") != "logs" {
+		t.Errorf("result = %+v", got)
+	}
+
+	requests := client.Requests()
+	var body map[string]interface{}
+	if err := json.Unmarshal(requests[len(requests)-1].Body, &body); err != nil {
+		t.Fatalf("issue body: %v", err)
+	}
+	if body["title"] != "Bug: Checkout fails" || body["labels"] != "bug,needs-triage,payments" || body["confidential"] != true {
+		t.Errorf("issue body = %v", body)
+	}
+	if want := "## Summary\n\nCheckout fails in version 1.4.\n\n## Logs\n\n{{logs}}\n"; body["description"] != want {
+		t.Errorf("description = %q, want %q", body["description"], want)
+	}
+
+	args["strict"] = true
+	if result := callTool(t, tc, "create_issue_from_template", args); !result.IsError || !strings.Contains(resultText(t, result), "logs") {
+		t.Errorf("strict with a missing variable = %s", resultText(t, result))
+	}
+}
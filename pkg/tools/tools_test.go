@@ -0,0 +1,313 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/gitlabtest"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/tools/internal/toolgen"
+)
+
+// newTestContext returns a tool context with a fake GitLab client serving
+// the fixtures in testdata/fixtures.
+func newTestContext(t *testing.T) (*ToolContext, *gitlabtest.Client) {
+	t.Helper()
+	client := gitlabtest.NewClient()
+	client.MustLoadFixtures(t, filepath.Join("testdata", "fixtures"))
+
+	logger, err := logging.NewLogger(logging.Config{LogDir: t.TempDir(), Level: logging.LevelError})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	t.Cleanup(func() { logger.Close() })
+	return NewToolContext(client, logger, &config.Config{GitLabAPIURL: client.BaseURL()}), client
+}
+
+// callTool runs the handler of a registered tool with tc.
+func callTool(t *testing.T, tc *ToolContext, name string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	var handler mcp.ToolHandler
+	server := mcp.NewServer("test", "0.0.0")
+	server.UseToolMiddleware(func(tool mcp.Tool, h mcp.ToolHandler) (mcp.Tool, mcp.ToolHandler) {
+		if tool.Name == name {
+			handler = h
+		}
+		return tool, h
+	})
+	if _, err := Register(server, tc); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if handler == nil {
+		t.Fatalf("tool %s is not registered", name)
+	}
+
+	result, err := handler(WithToolContext(context.Background(), tc), args)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return result
+}
+
+// resultText returns the text of a single-item result.
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if len(result.Content) != 1 {
+		t.Fatalf("result has %d content items, want 1", len(result.Content))
+	}
+	return result.Content[0].Text
+}
+
+// listItems decodes the {"items": [...], "pagination": {...}} envelope of a
+// list tool result.
+func listItems(t *testing.T, result *mcp.CallToolResult) ([]json.RawMessage, *gitlab.PaginationInfo) {
+	t.Helper()
+	var envelope struct {
+		Items      []json.RawMessage      `json:"items"`
+		Pagination *gitlab.PaginationInfo `json:"pagination"`
+	}
+	decoder := json.NewDecoder(strings.NewReader(resultText(t, result)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&envelope); err != nil || envelope.Items == nil {
+		t.Fatalf("result is not a list envelope (%v): %s", err, resultText(t, result))
+	}
+	return envelope.Items, envelope.Pagination
+}
+
+func TestTools_Golden(t *testing.T) {
+	tests := []struct {
+		name string
+		tool string
+		args map[string]interface{}
+	}{
+		{"get_project", "get_project", map[string]interface{}{"project_id": "acme/payments-api"}},
+		{"resolve_project_url", "resolve_project", map[string]interface{}{"query": "https://gitlab.example.com/acme/payments-api/-/merge_requests/12"}},
+		{"resolve_project_fuzzy", "resolve_project", map[string]interface{}{"query": "Payments API"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, client := newTestContext(t)
+			result := callTool(t, tc, tt.tool, tt.args)
+			if result.IsError {
+				t.Fatalf("%s failed: %s", tt.tool, resultText(t, result))
+			}
+			if unmatched := client.Unmatched(); len(unmatched) > 0 {
+				t.Errorf("requests without fixtures: %v", unmatched)
+			}
+			gitlabtest.AssertGolden(t, filepath.Join("testdata", "golden", tt.name+".golden"), []byte(resultText(t, result)))
+		})
+	}
+}
+
+func TestTools_APIError(t *testing.T) {
+	tc, _ := newTestContext(t)
+	result := callTool(t, tc, "get_project", map[string]interface{}{"project_id": "404"})
+	if !result.IsError {
+		t.Fatalf("get_project succeeded for a missing project: %s", resultText(t, result))
+	}
+}
+
+func TestCreateIssue_RequestBody(t *testing.T) {
+	tc, client := newTestContext(t)
+	result := callTool(t, tc, "create_issue", map[string]interface{}{
+		"project_id": "42",
+		"title":      "Checkout fails for EUR",
+		"labels":     "bug",
+	})
+	if result.IsError {
+		t.Fatalf("create_issue failed: %s", resultText(t, result))
+	}
+
+	requests := client.Requests()
+	if len(requests) != 1 || requests[0].Method != http.MethodPost || requests[0].Endpoint != "/projects/42/issues" {
+		t.Fatalf("requests = %+v, want one POST /projects/42/issues", requests)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(requests[0].Body, &body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if body["title"] != "Checkout fails for EUR" || body["labels"] != "bug" {
+		t.Errorf("request body = %v", body)
+	}
+}
+
+func TestRegister_Groups(t *testing.T) {
+	tc, _ := newTestContext(t)
+	server := mcp.NewServer("test", "0.0.0")
+	groups, err := Register(server, tc, "projects", "pipelines")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	// Named feature-flagged groups are registered even when the flag is off
+	if len(groups) != 2 || groups[0].Name != "projects" || groups[1].Name != "pipelines" {
+		t.Errorf("groups = %+v", groups)
+	}
+
+	if _, err := Register(mcp.NewServer("test", "0.0.0"), tc, "nope"); err == nil {
+		t.Error("Register accepted an unknown group")
+	}
+
+	defaults, err := Register(mcp.NewServer("test", "0.0.0"), tc)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	for _, group := range defaults {
+		if group.Name == "pipelines" {
+			t.Error("default tool set includes pipelines although USE_PIPELINE is off")
+		}
+	}
+}
+
+func TestRegister_SeparateContexts(t *testing.T) {
+	first, firstClient := newTestContext(t)
+	second := NewToolContext(gitlabtest.NewClient(), nil, nil)
+
+	if result := callTool(t, first, "get_project", map[string]interface{}{"project_id": "acme/payments-api"}); result.IsError {
+		t.Fatalf("get_project failed: %s", resultText(t, result))
+	}
+	// The second context's client has no routes, so the call fails there
+	if result := callTool(t, second, "get_project", map[string]interface{}{"project_id": "acme/payments-api"}); !result.IsError {
+		t.Errorf("get_project used the wrong client: %s", resultText(t, result))
+	}
+	if got := len(firstClient.Requests()); got != 1 {
+		t.Errorf("first client received %d requests, want 1", got)
+	}
+}
+
+func TestDecodeArgs(t *testing.T) {
+	type args struct {
+		PageArgs
+		ProjectID string   `json:"project_id" validate:"required"`
+		State     string   `json:"state" validate:"oneof=opened closed"`
+		Archived  *bool    `json:"archived"`
+		Labels    []string `json:"labels"`
+	}
+
+	got, err := DecodeArgs[args](map[string]interface{}{
+		"project_id": float64(42),
+		"state":      "opened",
+		"archived":   false,
+		"labels":     []interface{}{"bug", "ui"},
+		"per_page":   "50",
+		"unknown":    true,
+	})
+	if err != nil {
+		t.Fatalf("DecodeArgs: %v", err)
+	}
+	if got.ProjectID != "42" || got.State != "opened" || got.Archived == nil || *got.Archived ||
+		len(got.Labels) != 2 || got.PerPage != 50 || got.Page != 0 {
+		t.Errorf("decoded %+v", got)
+	}
+
+	_, err = DecodeArgs[args](map[string]interface{}{
+		"state":    "merged",
+		"per_page": float64(500),
+		"labels":   "bug",
+	})
+	want := "per_page must be at most 100; state must be one of: opened, closed; labels must be an array; project_id is required"
+	if err == nil {
+		t.Fatal("DecodeArgs accepted invalid arguments")
+	}
+	for _, problem := range strings.Split(want, "; ") {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("error %q does not mention %q", err, problem)
+		}
+	}
+}
+
+// TestArgTypes_MatchSchemas checks that the argument structs of typed tools
+// agree with their input schemas, so that a handler cannot silently ignore a
+// documented argument or read an undocumented one.
+func TestArgTypes_MatchSchemas(t *testing.T) {
+	tc, _ := newTestContext(t)
+	tools := map[string]mcp.Tool{}
+	server := mcp.NewServer("test", "0.0.0")
+	server.UseToolMiddleware(func(tool mcp.Tool, h mcp.ToolHandler) (mcp.Tool, mcp.ToolHandler) {
+		tools[tool.Name] = tool
+		return tool, h
+	})
+	if _, err := Register(server, tc, ToolGroupNames()...); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	argTypesMu.Lock()
+	defer argTypesMu.Unlock()
+	if len(argTypes) == 0 {
+		t.Fatal("no tool uses typed arguments")
+	}
+	for name, argType := range argTypes {
+		tool, ok := tools[name]
+		if !ok {
+			t.Errorf("%s: not registered", name)
+			continue
+		}
+		schema := tool.InputSchema
+		required := map[string]bool{}
+		for _, key := range schema.Required {
+			required[key] = true
+		}
+
+		fields := map[string]bool{}
+		for _, field := range argFields(argType) {
+			fields[field.name] = true
+			prop, ok := schema.Properties[field.name]
+			if !ok {
+				t.Errorf("%s: argument %s is not in the schema", name, field.name)
+				continue
+			}
+			if field.required != required[field.name] {
+				t.Errorf("%s: %s is required in the struct: %t, in the schema: %t", name, field.name, field.required, required[field.name])
+			}
+			if len(prop.Enum) > 0 && strings.Join(prop.Enum, " ") != strings.Join(field.oneOf, " ") {
+				t.Errorf("%s: %s allows %v in the struct but %v in the schema", name, field.name, field.oneOf, prop.Enum)
+			}
+		}
+		for key := range schema.Properties {
+			if !fields[key] {
+				t.Errorf("%s: schema property %s has no struct field", name, key)
+			}
+		}
+	}
+}
+
+func TestManifest_Generated(t *testing.T) {
+	data, err := os.ReadFile("manifest.yaml")
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	manifest, err := toolgen.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want, err := toolgen.Generate(manifest, "manifest.yaml")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got, err := os.ReadFile("manifest_gen.go")
+	if err != nil {
+		t.Fatalf("read generated code: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("manifest_gen.go is out of date; run go generate ./pkg/tools")
+	}
+}
+
+func TestManifest_DeleteFreezePeriod(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodDelete, "/projects/acme%2Fpayments-api/freeze_periods/3", http.StatusNoContent, nil)
+
+	result := callTool(t, tc, "delete_freeze_period", map[string]interface{}{"project_id": "acme/payments-api", "freeze_period_id": float64(3)})
+	if text := resultText(t, result); result.IsError || text != "Freeze period 3 deleted" {
+		t.Errorf("delete_freeze_period = %q", text)
+	}
+}
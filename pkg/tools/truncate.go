@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// truncationSuggestion is appended to truncated responses so the model knows
+// how to fetch the rest instead of assuming the result is complete.
+const truncationSuggestion = "Response exceeded the size limit of %d bytes. Request fewer results (smaller per_page, next page) or narrower filters to see the remaining data."
+
+// maxResponseBytes returns the configured response budget (0 = unlimited).
+func maxResponseBytes(ctx context.Context) int {
+	c := FromContext(ctx)
+	if c == nil || c.Config == nil {
+		return 0
+	}
+	return c.Config.MaxResponseBytes
+}
+
+// truncateJSON shrinks a JSON response that exceeds limit bytes. A top-level
+// array becomes {"items": [...]} with as many items as fit; for an object, the
+// largest array (or string) field is cut. Truncation metadata is added:
+// "truncated": true, "truncated_field", "returned_count", "remaining_count"
+// and a "suggestion" for a follow-up query. It returns nil if the response
+// cannot be shrunk below the limit this way.
+func truncateJSON(data []byte, limit int) []byte {
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil
+	}
+
+	var obj map[string]interface{}
+	var field string
+	switch v := generic.(type) {
+	case []interface{}:
+		obj = map[string]interface{}{"items": v}
+		field = "items"
+	case map[string]interface{}:
+		obj = v
+		field = largestField(v)
+	}
+	if field == "" {
+		return nil
+	}
+
+	obj["truncated"] = true
+	obj["truncated_field"] = field
+	obj["suggestion"] = fmt.Sprintf(truncationSuggestion, limit)
+
+	switch value := obj[field].(type) {
+	case []interface{}:
+		total := len(value)
+		fits := func(n int) ([]byte, bool) {
+			obj[field] = value[:n]
+			obj["returned_count"] = n
+			obj["remaining_count"] = total - n
+			out, err := json.MarshalIndent(obj, "", "  ")
+			return out, err == nil && len(out) <= limit
+		}
+		n := sort.Search(total+1, func(n int) bool { _, ok := fits(n); return !ok }) - 1
+		if n < 0 {
+			return nil
+		}
+		out, _ := fits(n)
+		return out
+
+	case string:
+		total := len(value)
+		fits := func(n int) ([]byte, bool) {
+			cut := value[:n]
+			for len(cut) > 0 && !utf8.ValidString(cut) {
+				cut = cut[:len(cut)-1]
+			}
+			obj[field] = cut
+			obj["returned_count"] = len(cut)
+			obj["remaining_count"] = total - len(cut)
+			out, err := json.MarshalIndent(obj, "", "  ")
+			return out, err == nil && len(out) <= limit
+		}
+		n := sort.Search(total+1, func(n int) bool { _, ok := fits(n); return !ok }) - 1
+		if n < 0 {
+			return nil
+		}
+		out, _ := fits(n)
+		return out
+	}
+	return nil
+}
+
+// largestField returns the key of the largest array or string field of obj,
+// measured by encoded size, or "" if there is none.
+func largestField(obj map[string]interface{}) string {
+	best, bestSize := "", 0
+	for key, value := range obj {
+		switch value.(type) {
+		case []interface{}, string:
+		default:
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		if len(encoded) > bestSize || (len(encoded) == bestSize && key < best) {
+			best, bestSize = key, len(encoded)
+		}
+	}
+	return best
+}
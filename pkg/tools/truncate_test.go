@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateJSON(t *testing.T) {
+	var items []map[string]interface{}
+	for i := 1; i <= 50; i++ {
+		items = append(items, map[string]interface{}{"iid": i, "title": fmt.Sprintf("Issue number %d", i)})
+	}
+	array, _ := json.Marshal(items)
+	object, _ := json.Marshal(map[string]interface{}{
+		"name":   "api",
+		"labels": []string{"bug", "feature"},
+		"issues": items,
+	})
+	// 2-byte runes, so most cuts fall inside one
+	text, _ := json.Marshal(map[string]interface{}{
+		"id":          7,
+		"description": strings.Repeat("ü", 500),
+	})
+
+	tests := []struct {
+		name      string
+		data      []byte
+		limit     int
+		wantField string
+	}{
+		{"top-level array", array, 1000, "items"},
+		{"largest field of an object", object, 1000, "issues"},
+		{"string field with multibyte runes", text, 501, "description"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := truncateJSON(tt.data, tt.limit)
+			if out == nil {
+				t.Fatal("truncateJSON returned nil")
+			}
+			if len(out) > tt.limit {
+				t.Errorf("truncated to %d bytes, over the limit of %d", len(out), tt.limit)
+			}
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatalf("truncated response is not JSON: %v", err)
+			}
+			if got["truncated"] != true || got["truncated_field"] != tt.wantField || got["suggestion"] == "" {
+				t.Errorf("truncation metadata = %v", got)
+			}
+
+			returned := int(got["returned_count"].(float64))
+			remaining := int(got["remaining_count"].(float64))
+			switch value := got[tt.wantField].(type) {
+			case []interface{}:
+				if returned != len(value) || returned+remaining != len(items) || returned == 0 {
+					t.Errorf("returned %d of %d items, with %d remaining", len(value), len(items), remaining)
+				}
+			case string:
+				if !utf8.ValidString(value) || strings.ContainsRune(value, utf8.RuneError) {
+					t.Errorf("string cut inside a rune: %q", value)
+				}
+				if returned != len(value) || returned+remaining != len(strings.Repeat("ü", 500)) || returned == 0 {
+					t.Errorf("returned %d bytes, with %d remaining", len(value), remaining)
+				}
+			default:
+				t.Errorf("%s = %v", tt.wantField, got[tt.wantField])
+			}
+			// Other fields are kept
+			if tt.wantField == "issues" && (got["name"] != "api" || len(got["labels"].([]interface{})) != 2) {
+				t.Errorf("other fields changed: %v", got)
+			}
+		})
+	}
+}
+
+func TestTruncateJSONCannotFit(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"metadata alone exceeds the limit", `[{"iid": 1}, {"iid": 2}]`},
+		{"no array or string field", `{"id": 1, "count": 2, "closed": true}`},
+		{"not JSON", `not json`},
+		{"scalar", `42`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if out := truncateJSON([]byte(tt.data), 20); out != nil {
+				t.Errorf("truncateJSON = %s, want nil", out)
+			}
+		})
+	}
+}
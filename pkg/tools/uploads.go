@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// uploadFileProperties are the file arguments shared by the upload tools: the
+// content either inline as base64 or as a local file.
+var uploadFileProperties = map[string]mcp.Property{
+	"file": {
+		Type:        "string",
+		Description: "The file content encoded as base64 (or use file_path)",
+	},
+	"file_path": {
+		Type:        "string",
+		Description: "Path of a local file to upload instead of file, streamed without loading it into memory (stdio mode only)",
+	},
+	"filename": {
+		Type:        "string",
+		Description: "The name of the file to upload (required with file; defaults to the base name of file_path)",
+	},
+}
+
+// withUploadFileProperties adds uploadFileProperties to a tool's input properties.
+func withUploadFileProperties(properties map[string]mcp.Property) map[string]mcp.Property {
+	for name, property := range uploadFileProperties {
+		properties[name] = property
+	}
+	return properties
+}
+
+// openUpload returns the multipart file described by the uploadFileProperties
+// arguments, with the given form field. The returned closer must be called
+// once the upload is done.
+func openUpload(c *ToolContext, args map[string]interface{}, field string) (gitlab.MultipartFile, io.Closer, error) {
+	filename := GetString(args, "filename", "")
+
+	if GetString(args, "file_path", "") != "" {
+		path, err := localPath(c, args, "file_path")
+		if err != nil {
+			return gitlab.MultipartFile{}, nil, err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return gitlab.MultipartFile{}, nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		if filename == "" {
+			filename = filepath.Base(path)
+		}
+		return gitlab.MultipartFile{Field: field, Name: filename, Reader: file}, file, nil
+	}
+
+	content := GetString(args, "file", "")
+	if content == "" {
+		return gitlab.MultipartFile{}, nil, fmt.Errorf("file or file_path is required")
+	}
+	if filename == "" {
+		return gitlab.MultipartFile{}, nil, fmt.Errorf("filename is required")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return gitlab.MultipartFile{}, nil, fmt.Errorf("invalid base64 file content: %w", err)
+	}
+	return gitlab.MultipartFile{Field: field, Name: filename, Reader: bytes.NewReader(decoded)}, io.NopCloser(nil), nil
+}
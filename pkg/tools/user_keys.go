@@ -0,0 +1,20 @@
+package tools
+
+import "time"
+
+// SSHKey is an SSH key of a user.
+type SSHKey struct {
+	ID        int        `json:"id"`
+	Title     string     `json:"title"`
+	Key       string     `json:"key"`
+	UsageType string     `json:"usage_type,omitempty"`
+	CreatedAt *time.Time `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// GPGKey is a GPG key of a user.
+type GPGKey struct {
+	ID        int        `json:"id"`
+	Key       string     `json:"key"`
+	CreatedAt *time.Time `json:"created_at"`
+}
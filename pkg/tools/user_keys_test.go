@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUserKeyWritesReadOnlyMode(t *testing.T) {
+	tc, client := newTestContext(t)
+	tc.Config.ReadOnlyMode = true
+	for _, call := range []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"add_ssh_key", map[string]interface{}{"title": "laptop", "key": "ssh-ed25519 AAAAC3Nza laptop"}},
+		{"delete_ssh_key", map[string]interface{}{"key_id": 7}},
+		{"add_gpg_key", map[string]interface{}{"key": "-----BEGIN PGP PUBLIC KEY BLOCK-----"}},
+		{"delete_gpg_key", map[string]interface{}{"key_id": 8}},
+	} {
+		result := callTool(t, tc, call.name, call.args)
+		if !result.IsError || !strings.Contains(resultText(t, result), "server is in read-only mode") {
+			t.Errorf("%s in read-only mode = %q, want a read-only error", call.name, resultText(t, result))
+		}
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("read-only mode sent %d requests, want none", len(requests))
+	}
+}
+
+func TestUserKeyTools(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle("POST", "/user/keys", 201, `{"id": 4, "title": "laptop", "key": "ssh-ed25519 AAAA dev@laptop", "usage_type": "auth"}`)
+	client.Handle("DELETE", "/user/gpg_keys/9", 204, ``)
+
+	result := callTool(t, tc, "add_ssh_key", map[string]interface{}{"title": "laptop", "key": "ssh-ed25519 AAAA dev@laptop", "usage_type": "auth"})
+	if result.IsError {
+		t.Fatalf("add_ssh_key failed: %s", resultText(t, result))
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(client.Requests()[0].Body, &body); err != nil {
+		t.Fatalf("request body: %v", err)
+	}
+	if want := map[string]interface{}{"title": "laptop", "key": "ssh-ed25519 AAAA dev@laptop", "usage_type": "auth"}; !reflect.DeepEqual(body, want) {
+		t.Errorf("request body = %v, want %v", body, want)
+	}
+	var key SSHKey
+	if err := json.Unmarshal([]byte(resultText(t, result)), &key); err != nil || key.ID != 4 {
+		t.Errorf("key = %+v, err = %v", key, err)
+	}
+
+	result = callTool(t, tc, "delete_gpg_key", map[string]interface{}{"key_id": 9})
+	if text := resultText(t, result); result.IsError || !strings.Contains(text, "GPG key 9 deleted") {
+		t.Errorf("delete_gpg_key = %s", text)
+	}
+}
@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// UserDetails is a user as returned by get_user_by_username and list_users.
+// IsAdmin and LastSignInAt are only visible to administrators.
+type UserDetails struct {
+	gitlab.User
+	Bot            bool   `json:"bot"`
+	External       bool   `json:"external,omitempty"`
+	Locked         bool   `json:"locked,omitempty"`
+	PublicEmail    string `json:"public_email,omitempty"`
+	JobTitle       string `json:"job_title,omitempty"`
+	Organization   string `json:"organization,omitempty"`
+	CreatedAt      string `json:"created_at,omitempty"`
+	LastActivityOn string `json:"last_activity_on,omitempty"`
+	LastSignInAt   string `json:"last_sign_in_at,omitempty"`
+	IsAdmin        *bool  `json:"is_admin,omitempty"`
+}
+
+type getUserByUsernameArgs struct {
+	Username string `json:"username" validate:"required"`
+}
+
+type listUsersArgs struct {
+	PageArgs
+	Search   string `json:"search"`
+	Active   bool   `json:"active"`
+	Blocked  bool   `json:"blocked"`
+	External bool   `json:"external"`
+	Humans   bool   `json:"humans"`
+	OrderBy  string `json:"order_by" validate:"oneof=id name username created_at updated_at"`
+	Sort     string `json:"sort" validate:"oneof=asc desc"`
+}
+
+// registerGetUserByUsername registers the get_user_by_username tool.
+func registerGetUserByUsername(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_user_by_username",
+			Description: "Look up one GitLab user by exact username and return their ID, name, state, whether they are a bot, and their public profile. Use the ID for assignee, reviewer and member arguments, or the username as sudo on write tools when the server allows it.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"username": {
+						Type:        "string",
+						Description: "The exact username, with or without a leading @",
+					},
+				},
+				Required: []string{"username"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("get_user_by_username", func(ctx context.Context, c *ToolContext, args getUserByUsernameArgs) (*mcp.CallToolResult, error) {
+			username := strings.TrimPrefix(args.Username, "@")
+			var users []UserDetails
+			if err := c.Client.Get(ctx, "/users?username="+url.QueryEscape(username), &users); err != nil {
+				return APIErrorResult("Failed to get user", err)
+			}
+			if len(users) == 0 {
+				return ErrorResult(fmt.Sprintf("user %q not found", username))
+			}
+			return JSONResult(users[0])
+		}),
+	)
+}
+
+// registerListUsers registers the list_users tool.
+func registerListUsers(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "list_users",
+			Description: "List or search the users of the GitLab instance by name, username or email (public email only, unless the token is an administrator's), filtered by state. Set humans to leave out bots and service accounts.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"search": {
+						Type:        "string",
+						Description: "Match against name, username and email",
+					},
+					"active": {
+						Type:        "boolean",
+						Description: "Only active users",
+					},
+					"blocked": {
+						Type:        "boolean",
+						Description: "Only blocked users",
+					},
+					"external": {
+						Type:        "boolean",
+						Description: "Only external users (administrators only)",
+					},
+					"humans": {
+						Type:        "boolean",
+						Description: "Leave out bots and service accounts",
+					},
+					"order_by": {
+						Type:        "string",
+						Description: "Order by: id, name, username, created_at or updated_at (administrators only). Default: id",
+						Enum:        []string{"id", "name", "username", "created_at", "updated_at"},
+					},
+					"sort": {
+						Type:        "string",
+						Description: "Sort direction: asc or desc. Default: desc",
+						Enum:        []string{"asc", "desc"},
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination",
+						Default:     1,
+						Minimum:     mcp.IntPtr(1),
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page",
+						Default:     20,
+						Minimum:     mcp.IntPtr(1),
+						Maximum:     mcp.IntPtr(100),
+					},
+				},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		withArgs("list_users", func(ctx context.Context, c *ToolContext, args listUsersArgs) (*mcp.CallToolResult, error) {
+			params := url.Values{}
+			args.setParams(params)
+			if args.Search != "" {
+				params.Set("search", args.Search)
+			}
+			for name, set := range map[string]bool{"active": args.Active, "blocked": args.Blocked, "external": args.External, "humans": args.Humans} {
+				if set {
+					params.Set(name, "true")
+				}
+			}
+			if args.OrderBy != "" {
+				params.Set("order_by", args.OrderBy)
+			}
+			if args.Sort != "" {
+				params.Set("sort", args.Sort)
+			}
+
+			var users []UserDetails
+			pagination, err := c.Client.GetWithPagination(ctx, "/users?"+params.Encode(), &users)
+			if err != nil {
+				return APIErrorResult("Failed to list users", err)
+			}
+			return PagedJSONResult(users, pagination)
+		}),
+	)
+}
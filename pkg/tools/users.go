@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -74,12 +75,12 @@ func registerGetUsers(server *mcp.Server) {
 				Required: []string{"usernames"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_users", args)
+			c.Logger.ToolCall(ctx, "get_users", args)
 
 			usernames := GetStringArray(args, "usernames")
 			if len(usernames) == 0 {
@@ -95,8 +96,8 @@ func registerGetUsers(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/users?%s", params.Encode())
 
 			var users []gitlab.User
-			if err := ctx.Client.Get(endpoint, &users); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to get users: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &users); err != nil {
+				return APIErrorResult("failed to get users", err)
 			}
 
 			return JSONResult(users)
@@ -142,12 +143,12 @@ func registerListEvents(server *mcp.Server) {
 				},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("list_events", args)
+			c.Logger.ToolCall(ctx, "list_events", args)
 
 			// Build query parameters
 			params := url.Values{}
@@ -182,11 +183,12 @@ func registerListEvents(server *mcp.Server) {
 			}
 
 			var events []Event
-			if err := ctx.Client.Get(endpoint, &events); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to list events: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &events)
+			if err != nil {
+				return APIErrorResult("failed to list events", err)
 			}
 
-			return JSONResult(events)
+			return PagedJSONResult(events, pagination)
 		},
 	)
 }
@@ -234,12 +236,12 @@ func registerGetProjectEvents(server *mcp.Server) {
 				Required: []string{"project_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_project_events", args)
+			c.Logger.ToolCall(ctx, "get_project_events", args)
 
 			projectID := GetString(args, "project_id", "")
 			if projectID == "" {
@@ -279,24 +281,194 @@ func registerGetProjectEvents(server *mcp.Server) {
 			}
 
 			var events []Event
-			if err := ctx.Client.Get(endpoint, &events); err != nil {
-				return ErrorResult(fmt.Sprintf("failed to get project events: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &events)
+			if err != nil {
+				return APIErrorResult("failed to get project events", err)
 			}
 
-			return JSONResult(events)
+			return PagedJSONResult(events, pagination)
 		},
 	)
 }
 
+// ContributionSummary aggregates a user's events over a date range.
+type ContributionSummary struct {
+	UserID        string         `json:"user_id"`
+	After         string         `json:"after,omitempty"`
+	Before        string         `json:"before,omitempty"`
+	TotalEvents   int            `json:"total_events"`
+	Complete      bool           `json:"complete"`
+	PushedCommits int            `json:"pushed_commits"`
+	ByAction      map[string]int `json:"by_action"`
+	ByTargetType  map[string]int `json:"by_target_type"`
+	ByProject     map[string]int `json:"by_project"`
+}
+
+// registerGetUserContributionEvents registers the get_user_contribution_events tool.
+func registerGetUserContributionEvents(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_user_contribution_events",
+			Description: "List contribution events (pushes, comments, issue and merge request activity) of a specific user. Set summarize=true to get counts by action, target type and project over the date range instead of the raw events.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"user_id": {
+						Type:        "string",
+						Description: "The ID or username of the user",
+					},
+					"action": {
+						Type:        "string",
+						Description: "Filter events by action type: created, updated, closed, reopened, pushed, commented, merged, joined, left, destroyed, expired",
+						Enum:        []string{"created", "updated", "closed", "reopened", "pushed", "commented", "merged", "joined", "left", "destroyed", "expired"},
+					},
+					"target_type": {
+						Type:        "string",
+						Description: "Filter events by target type: issue, milestone, merge_request, note, project, snippet, user",
+						Enum:        []string{"issue", "milestone", "merge_request", "note", "project", "snippet", "user"},
+					},
+					"before": {
+						Type:        "string",
+						Description: "Filter events before this date (format: YYYY-MM-DD)",
+					},
+					"after": {
+						Type:        "string",
+						Description: "Filter events after this date (format: YYYY-MM-DD)",
+					},
+					"summarize": {
+						Type:        "boolean",
+						Description: "Return aggregated counts for all matching events instead of a page of events (default: false)",
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination (default: 1). Ignored when summarize is true",
+					},
+					"per_page": {
+						Type:        "integer",
+						Description: "Number of items per page (default: 20, max: 100). Ignored when summarize is true",
+					},
+				},
+				Required: []string{"user_id"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_user_contribution_events", args)
+
+			userID := GetString(args, "user_id", "")
+			if userID == "" {
+				return ErrorResult("user_id is required")
+			}
+
+			// Build query parameters
+			params := url.Values{}
+
+			if action := GetString(args, "action", ""); action != "" {
+				params.Set("action", action)
+			}
+
+			if targetType := GetString(args, "target_type", ""); targetType != "" {
+				params.Set("target_type", targetType)
+			}
+
+			before := GetString(args, "before", "")
+			if before != "" {
+				params.Set("before", before)
+			}
+
+			after := GetString(args, "after", "")
+			if after != "" {
+				params.Set("after", after)
+			}
+
+			endpoint := fmt.Sprintf("/users/%s/events", url.PathEscape(userID))
+
+			if GetBool(args, "summarize", false) {
+				if len(params) > 0 {
+					endpoint += "?" + params.Encode()
+				}
+				events, complete, err := collectPages[Event](ctx, c.Client, endpoint, maxCollectedItems)
+				if err != nil {
+					return APIErrorResult("failed to get user contribution events", err)
+				}
+				return JSONResult(summarizeEvents(userID, after, before, events, complete))
+			}
+
+			if page := GetInt(args, "page", 0); page > 0 {
+				params.Set("page", strconv.Itoa(page))
+			}
+
+			if perPage := GetInt(args, "per_page", 0); perPage > 0 {
+				params.Set("per_page", strconv.Itoa(perPage))
+			}
+
+			if len(params) > 0 {
+				endpoint += "?" + params.Encode()
+			}
+
+			var events []Event
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &events)
+			if err != nil {
+				return APIErrorResult("failed to get user contribution events", err)
+			}
+
+			return PagedJSONResult(events, pagination)
+		},
+	)
+}
+
+// summarizeEvents counts events by action, target type and project.
+func summarizeEvents(userID, after, before string, events []Event, complete bool) ContributionSummary {
+	summary := ContributionSummary{
+		UserID:       userID,
+		After:        after,
+		Before:       before,
+		TotalEvents:  len(events),
+		Complete:     complete,
+		ByAction:     map[string]int{},
+		ByTargetType: map[string]int{},
+		ByProject:    map[string]int{},
+	}
+	for _, event := range events {
+		summary.ByAction[event.ActionName]++
+		if event.TargetType != "" {
+			summary.ByTargetType[event.TargetType]++
+		}
+		if event.ProjectID != 0 {
+			summary.ByProject[strconv.Itoa(event.ProjectID)]++
+		}
+		if event.PushData != nil {
+			summary.PushedCommits += event.PushData.CommitCount
+		}
+	}
+	return summary
+}
+
 // initUserTools registers all user-related tools with the MCP server.
-// Includes: get_users
+// Includes: get_users, get_user_by_username, list_users, list_ssh_keys,
+// add_ssh_key, delete_ssh_key, list_gpg_keys, add_gpg_key, delete_gpg_key
 func initUserTools(server *mcp.Server) {
 	registerGetUsers(server)
+	registerGetUserByUsername(server)
+	registerListUsers(server)
+	registerListSshKeys(server)
+	registerAddSshKey(server)
+	registerDeleteSshKey(server)
+	registerListGpgKeys(server)
+	registerAddGpgKey(server)
+	registerDeleteGpgKey(server)
 }
 
 // initEventTools registers all event-related tools with the MCP server.
-// Includes: list_events, get_project_events
+// Includes: list_events, get_project_events, get_user_contribution_events
 func initEventTools(server *mcp.Server) {
 	registerListEvents(server)
 	registerGetProjectEvents(server)
+	registerGetUserContributionEvents(server)
 }
@@ -0,0 +1,385 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// defaultWikiHistoryDays is how far back get_wiki_page_versions looks by default.
+const defaultWikiHistoryDays = 90
+
+// maxDiffCells bounds the line-diff table (old lines x new lines) built by
+// lineDiff after the common prefix and suffix are trimmed.
+const maxDiffCells = 4_000_000
+
+// WikiPageVersion is one recorded change of a wiki page.
+type WikiPageVersion struct {
+	Action         string     `json:"action"`
+	Title          string     `json:"title"`
+	AuthorUsername string     `json:"author_username"`
+	AuthorName     string     `json:"author_name,omitempty"`
+	CreatedAt      *time.Time `json:"created_at"`
+}
+
+// WikiPageHistory is the response of the get_wiki_page_versions tool.
+type WikiPageHistory struct {
+	Slug     string            `json:"slug"`
+	After    string            `json:"after"`
+	Versions []WikiPageVersion `json:"versions"`
+	Complete bool              `json:"complete"`
+}
+
+// WikiPageDiff is the response of the diff_wiki_page_versions tool.
+type WikiPageDiff struct {
+	Slug        string `json:"slug"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+	FromTitle   string `json:"from_title"`
+	ToTitle     string `json:"to_title"`
+	Additions   int    `json:"additions"`
+	Deletions   int    `json:"deletions"`
+	Diff        string `json:"diff"`
+}
+
+// registerGetWikiPageVersions registers the get_wiki_page_versions tool.
+func registerGetWikiPageVersions(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "get_wiki_page_versions",
+			Description: "List the recorded changes of a wiki page (who created or updated it and when), newest first, built from the project's activity events. GitLab's REST API does not expose wiki commit SHAs; take them from the page history in the GitLab UI for diff_wiki_page_versions.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The ID or URL-encoded path of the project",
+					},
+					"slug": {
+						Type:        "string",
+						Description: "The slug of the wiki page (e.g., 'home' or 'getting-started')",
+					},
+					"after": {
+						Type:        "string",
+						Description: "Only changes after this date (format: YYYY-MM-DD, default: 90 days ago). Events older than the instance's event retention are unavailable",
+					},
+				},
+				Required: []string{"project_id", "slug"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "get_wiki_page_versions", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			slug := GetString(args, "slug", "")
+			if slug == "" {
+				return ErrorResult("slug is required")
+			}
+
+			after := GetString(args, "after", "")
+			if after == "" {
+				after = time.Now().UTC().AddDate(0, 0, -defaultWikiHistoryDays).Format("2006-01-02")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/events?after=%s", url.PathEscape(projectID), url.QueryEscape(after))
+			events, complete, err := collectPages[Event](ctx, c.Client, endpoint, maxCollectedItems)
+			if err != nil {
+				return APIErrorResult("Failed to get wiki page versions", err)
+			}
+
+			history := WikiPageHistory{
+				Slug:     slug,
+				After:    after,
+				Versions: []WikiPageVersion{},
+				Complete: complete,
+			}
+			for _, event := range events {
+				if event.WikiPage == nil || event.WikiPage.Slug != slug {
+					continue
+				}
+				version := WikiPageVersion{
+					Action:         event.ActionName,
+					Title:          event.WikiPage.Title,
+					AuthorUsername: event.AuthorUsername,
+					CreatedAt:      event.CreatedAt,
+				}
+				if event.Author != nil {
+					version.AuthorName = event.Author.Name
+				}
+				history.Versions = append(history.Versions, version)
+			}
+
+			return JSONResult(history)
+		},
+	)
+}
+
+// registerDiffWikiPageVersions registers the diff_wiki_page_versions tool.
+func registerDiffWikiPageVersions(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "diff_wiki_page_versions",
+			Description: "Show a unified diff of a wiki page between two versions, identified by wiki commit SHA. to_version defaults to the current page.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The ID or URL-encoded path of the project",
+					},
+					"slug": {
+						Type:        "string",
+						Description: "The slug of the wiki page (e.g., 'home' or 'getting-started')",
+					},
+					"from_version": {
+						Type:        "string",
+						Description: "Wiki commit SHA of the older version",
+					},
+					"to_version": {
+						Type:        "string",
+						Description: "Wiki commit SHA of the newer version (optional, default: current version)",
+					},
+					"context_lines": {
+						Type:        "integer",
+						Description: "Unchanged lines shown around each change (optional, default: 3)",
+						Minimum:     mcp.IntPtr(0),
+					},
+				},
+				Required: []string{"project_id", "slug", "from_version"},
+			},
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
+				return ErrorResult("tool context not initialized")
+			}
+			c.Logger.ToolCall(ctx, "diff_wiki_page_versions", args)
+
+			projectID := GetString(args, "project_id", "")
+			if projectID == "" {
+				return ErrorResult("project_id is required")
+			}
+
+			slug := GetString(args, "slug", "")
+			if slug == "" {
+				return ErrorResult("slug is required")
+			}
+
+			fromVersion := GetString(args, "from_version", "")
+			if fromVersion == "" {
+				return ErrorResult("from_version is required")
+			}
+			toVersion := GetString(args, "to_version", "")
+			contextLines := GetInt(args, "context_lines", 3)
+			if contextLines < 0 {
+				return ErrorResult("context_lines must not be negative")
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/wikis/%s", url.PathEscape(projectID), url.PathEscape(slug))
+
+			var from, to WikiPage
+			if err := c.Client.Get(ctx, endpoint+"?version="+url.QueryEscape(fromVersion), &from); err != nil {
+				return APIErrorResult("Failed to get wiki page version "+fromVersion, err)
+			}
+			toEndpoint := endpoint
+			if toVersion != "" {
+				toEndpoint += "?version=" + url.QueryEscape(toVersion)
+			}
+			if err := c.Client.Get(ctx, toEndpoint, &to); err != nil {
+				return APIErrorResult("Failed to get wiki page", err)
+			}
+
+			ops, ok := lineDiff(splitLines(from.Content), splitLines(to.Content))
+			if !ok {
+				return ErrorResult("wiki page versions are too large to diff; compare them with get_wiki_page instead")
+			}
+
+			result := WikiPageDiff{
+				Slug:        slug,
+				FromVersion: fromVersion,
+				ToVersion:   toVersion,
+				FromTitle:   from.Title,
+				ToTitle:     to.Title,
+			}
+			if result.ToVersion == "" {
+				result.ToVersion = "current"
+			}
+			for _, op := range ops {
+				switch op.kind {
+				case '+':
+					result.Additions++
+				case '-':
+					result.Deletions++
+				}
+			}
+			result.Diff = unifiedDiff(ops, contextLines)
+
+			return JSONResult(result)
+		},
+	)
+}
+
+// diffOp is one line of a line diff: ' ' (unchanged), '-' (removed) or '+' (added).
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// splitLines splits text into lines without their trailing newlines.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// lineDiff computes a minimal line diff of a and b using a longest common
+// subsequence table. It returns false if the changed region exceeds maxDiffCells.
+func lineDiff(a, b []string) ([]diffOp, bool) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		return nil, false
+	}
+
+	// lcs[i][j] is the LCS length of midA[i:] and midB[j:].
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			ops = append(ops, diffOp{' ', midA[i]})
+			i++
+			j++
+		case j < len(midB) && (i == len(midA) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, diffOp{'+', midB[j]})
+			j++
+		default:
+			ops = append(ops, diffOp{'-', midA[i]})
+			i++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops, true
+}
+
+// unifiedDiff renders ops as unified diff hunks with the given number of
+// context lines. It returns an empty string when nothing changed.
+func unifiedDiff(ops []diffOp, context int) string {
+	var sb strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change.
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context lines of each other.
+		last := first
+		for k := first; k < len(ops); k++ {
+			if ops[k].kind != ' ' {
+				last = k
+			} else if k-last > 2*context {
+				break
+			}
+		}
+		from := first - context
+		if from < start {
+			from = start
+		}
+		to := last + context + 1
+		if to > len(ops) {
+			to = len(ops)
+		}
+
+		// Line numbers of the hunk start in the old and new text.
+		oldLine, newLine := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				oldLine++
+			}
+			if op.kind != '-' {
+				newLine++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		// An empty side is numbered by the line before it, as in diff -u.
+		if oldCount == 0 {
+			oldLine--
+		}
+		if newCount == 0 {
+			newLine--
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, op := range ops[from:to] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+		start = to
+	}
+	return sb.String()
+}
+
+// initWikiVersionTools registers the wiki history tools.
+func initWikiVersionTools(server *mcp.Server) {
+	registerGetWikiPageVersions(server)
+	registerDiffWikiPageVersions(server)
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// wikiVersionsContext returns a test context with two versions of the
+// "runbook" wiki page; the second is also the current one.
+func wikiVersionsContext(t *testing.T) *ToolContext {
+	t.Helper()
+	tc, client := newTestContext(t)
+	tc.Config.UseWiki = true
+	const endpoint = "/projects/acme%2Fapi/wikis/runbook"
+	v1 := WikiPage{Slug: "runbook", Title: "Runbook", Content: "# Runbook\n\nRestart the service.\nCheck the logs.\n\nEscalate to on-call.\n"}
+	v2 := WikiPage{Slug: "runbook", Title: "On-call runbook", Content: "# Runbook\n\nRestart the service with systemctl.\nCheck the logs.\n\nEscalate to on-call.\nPage the SRE lead.\n"}
+	client.Handle(http.MethodGet, endpoint+"?version=1111111", http.StatusOK, v1)
+	client.Handle(http.MethodGet, endpoint+"?version=2222222", http.StatusOK, v2)
+	client.Handle(http.MethodGet, endpoint+"?version=deadbee", http.StatusNotFound, `{"message": "404 Wiki Page Not Found"}`)
+	client.Handle(http.MethodGet, endpoint, http.StatusOK, v2)
+	return tc
+}
+
+func TestDiffWikiPageVersions(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want WikiPageDiff
+	}{
+		{
+			name: "two versions",
+			args: map[string]interface{}{"from_version": "1111111", "to_version": "2222222"},
+			want: WikiPageDiff{
+				ToVersion: "2222222",
+				Additions: 2,
+				Deletions: 1,
+				Diff:      "@@ -1,6 +1,7 @@\n # Runbook\n \n-Restart the service.\n+Restart the service with systemctl.\n Check the logs.\n \n Escalate to on-call.\n+Page the SRE lead.\n",
+			},
+		},
+		{
+			name: "against the current version without context",
+			args: map[string]interface{}{"from_version": "1111111", "context_lines": 0},
+			want: WikiPageDiff{
+				ToVersion: "current",
+				Additions: 2,
+				Deletions: 1,
+				Diff:      "@@ -3,1 +3,1 @@\n-Restart the service.\n+Restart the service with systemctl.\n@@ -6,0 +7,1 @@\n+Page the SRE lead.\n",
+			},
+		},
+		{
+			name: "same version",
+			args: map[string]interface{}{"from_version": "2222222", "to_version": "2222222"},
+			want: WikiPageDiff{ToVersion: "2222222"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := wikiVersionsContext(t)
+			args := map[string]interface{}{"project_id": "acme/api", "slug": "runbook"}
+			for name, value := range tt.args {
+				args[name] = value
+			}
+			var got WikiPageDiff
+			if err := json.Unmarshal([]byte(resultText(t, callTool(t, tc, "diff_wiki_page_versions", args))), &got); err != nil {
+				t.Fatalf("decode diff: %v", err)
+			}
+			want := tt.want
+			want.Slug, want.FromVersion = "runbook", tt.args["from_version"].(string)
+			want.FromTitle, want.ToTitle = "Runbook", "On-call runbook"
+			if tt.args["from_version"] == "2222222" {
+				want.FromTitle = "On-call runbook"
+			}
+			if got != want {
+				t.Errorf("diff =\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDiffWikiPageVersionsMissingVersion(t *testing.T) {
+	tc := wikiVersionsContext(t)
+	for _, args := range []map[string]interface{}{
+		{"from_version": "deadbee"},
+		{"from_version": "1111111", "to_version": "deadbee"},
+	} {
+		args["project_id"], args["slug"] = "acme/api", "runbook"
+		result := callTool(t, tc, "diff_wiki_page_versions", args)
+		if !result.IsError || !strings.Contains(result.Content[0].Text, "404 Wiki Page Not Found") {
+			t.Errorf("diff with an unknown version (%v) = %+v, want the 404", args, result.Content)
+		}
+	}
+	if result := callTool(t, tc, "diff_wiki_page_versions", map[string]interface{}{"project_id": "acme/api", "slug": "runbook"}); !result.IsError {
+		t.Error("diff without from_version succeeded")
+	}
+}
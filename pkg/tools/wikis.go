@@ -2,7 +2,7 @@
 package tools
 
 import (
-	"encoding/base64"
+	"context"
 	"fmt"
 	"net/url"
 
@@ -58,12 +58,12 @@ func registerListWikiPages(server *mcp.Server) {
 				Required: []string{"project_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("list_wiki_pages", args)
+			c.Logger.ToolCall(ctx, "list_wiki_pages", args)
 
 			// Extract required parameters
 			projectID := GetString(args, "project_id", "")
@@ -98,11 +98,12 @@ func registerListWikiPages(server *mcp.Server) {
 
 			// Make API request
 			var wikiPages []WikiPage
-			if err := ctx.Client.Get(endpoint, &wikiPages); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to list wiki pages: %v", err))
+			pagination, err := c.Client.GetWithPagination(ctx, endpoint, &wikiPages)
+			if err != nil {
+				return APIErrorResult("Failed to list wiki pages", err)
 			}
 
-			return JSONResult(wikiPages)
+			return PagedJSONResult(wikiPages, pagination)
 		},
 	)
 }
@@ -124,16 +125,20 @@ func registerGetWikiPage(server *mcp.Server) {
 						Type:        "string",
 						Description: "The URL-encoded slug of the wiki page (e.g., 'home' or 'getting-started')",
 					},
+					"version": {
+						Type:        "string",
+						Description: "Wiki commit SHA of an earlier version of the page (optional, default: current version)",
+					},
 				},
 				Required: []string{"project_id", "slug"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("get_wiki_page", args)
+			c.Logger.ToolCall(ctx, "get_wiki_page", args)
 
 			// Extract required parameters
 			projectID := GetString(args, "project_id", "")
@@ -150,11 +155,14 @@ func registerGetWikiPage(server *mcp.Server) {
 			encodedProjectID := url.PathEscape(projectID)
 			encodedSlug := url.PathEscape(slug)
 			endpoint := fmt.Sprintf("/projects/%s/wikis/%s", encodedProjectID, encodedSlug)
+			if version := GetString(args, "version", ""); version != "" {
+				endpoint += "?version=" + url.QueryEscape(version)
+			}
 
 			// Make API request
 			var wikiPage WikiPage
-			if err := ctx.Client.Get(endpoint, &wikiPage); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to get wiki page: %v", err))
+			if err := c.Client.Get(ctx, endpoint, &wikiPage); err != nil {
+				return APIErrorResult("Failed to get wiki page", err)
 			}
 
 			return JSONResult(wikiPage)
@@ -191,15 +199,15 @@ func registerCreateWikiPage(server *mcp.Server) {
 				Required: []string{"project_id", "title", "content"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("create_wiki_page", args)
+			c.Logger.ToolCall(ctx, "create_wiki_page", args)
 
 			// Check read-only mode
-			if ctx.Config != nil && ctx.Config.ReadOnlyMode {
+			if c.Config != nil && c.Config.ReadOnlyMode {
 				return ErrorResult("cannot create wiki page: server is in read-only mode")
 			}
 
@@ -243,8 +251,8 @@ func registerCreateWikiPage(server *mcp.Server) {
 
 			// Make API request
 			var wikiPage WikiPage
-			if err := ctx.Client.Post(endpoint, requestBody, &wikiPage); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to create wiki page: %v", err))
+			if err := c.Client.Post(ctx, endpoint, requestBody, &wikiPage); err != nil {
+				return APIErrorResult("Failed to create wiki page", err)
 			}
 
 			return JSONResult(wikiPage)
@@ -285,15 +293,15 @@ func registerUpdateWikiPage(server *mcp.Server) {
 				Required: []string{"project_id", "slug"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("update_wiki_page", args)
+			c.Logger.ToolCall(ctx, "update_wiki_page", args)
 
 			// Check read-only mode
-			if ctx.Config != nil && ctx.Config.ReadOnlyMode {
+			if c.Config != nil && c.Config.ReadOnlyMode {
 				return ErrorResult("cannot update wiki page: server is in read-only mode")
 			}
 
@@ -343,8 +351,8 @@ func registerUpdateWikiPage(server *mcp.Server) {
 
 			// Make API request
 			var wikiPage WikiPage
-			if err := ctx.Client.Put(endpoint, requestBody, &wikiPage); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to update wiki page: %v", err))
+			if err := c.Client.Put(ctx, endpoint, requestBody, &wikiPage); err != nil {
+				return APIErrorResult("Failed to update wiki page", err)
 			}
 
 			return JSONResult(wikiPage)
@@ -373,15 +381,15 @@ func registerDeleteWikiPage(server *mcp.Server) {
 				Required: []string{"project_id", "slug"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("delete_wiki_page", args)
+			c.Logger.ToolCall(ctx, "delete_wiki_page", args)
 
 			// Check read-only mode
-			if ctx.Config != nil && ctx.Config.ReadOnlyMode {
+			if c.Config != nil && c.Config.ReadOnlyMode {
 				return ErrorResult("cannot delete wiki page: server is in read-only mode")
 			}
 
@@ -402,8 +410,8 @@ func registerDeleteWikiPage(server *mcp.Server) {
 			endpoint := fmt.Sprintf("/projects/%s/wikis/%s", encodedProjectID, encodedSlug)
 
 			// Make API request (DELETE returns no content on success)
-			if err := ctx.Client.Delete(endpoint); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to delete wiki page: %v", err))
+			if err := c.Client.Delete(ctx, endpoint); err != nil {
+				return APIErrorResult("Failed to delete wiki page", err)
 			}
 
 			result := map[string]interface{}{
@@ -424,36 +432,28 @@ func registerUploadWikiAttachment(server *mcp.Server) {
 			Description: "Upload an attachment to a GitLab project wiki and get a markdown link",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
-				Properties: map[string]mcp.Property{
+				Properties: withUploadFileProperties(map[string]mcp.Property{
 					"project_id": {
 						Type:        "string",
 						Description: "The ID or URL-encoded path of the project",
 					},
-					"file": {
-						Type:        "string",
-						Description: "The file content encoded as base64",
-					},
-					"filename": {
-						Type:        "string",
-						Description: "The name of the file to upload",
-					},
 					"branch": {
 						Type:        "string",
 						Description: "The branch to upload to (optional, defaults to wiki default branch)",
 					},
-				},
-				Required: []string{"project_id", "file", "filename"},
+				}),
+				Required: []string{"project_id"},
 			},
 		},
-		func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := GetContext()
-			if ctx == nil {
+		func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			c := FromContext(ctx)
+			if c == nil {
 				return ErrorResult("tool context not initialized")
 			}
-			ctx.Logger.ToolCall("upload_wiki_attachment", args)
+			c.Logger.ToolCall(ctx, "upload_wiki_attachment", args)
 
 			// Check read-only mode
-			if ctx.Config != nil && ctx.Config.ReadOnlyMode {
+			if c.Config != nil && c.Config.ReadOnlyMode {
 				return ErrorResult("cannot upload wiki attachment: server is in read-only mode")
 			}
 
@@ -463,45 +463,25 @@ func registerUploadWikiAttachment(server *mcp.Server) {
 				return ErrorResult("project_id is required")
 			}
 
-			fileContent := GetString(args, "file", "")
-			if fileContent == "" {
-				return ErrorResult("file is required")
-			}
-
-			filename := GetString(args, "filename", "")
-			if filename == "" {
-				return ErrorResult("filename is required")
+			file, closer, err := openUpload(c, args, "file")
+			if err != nil {
+				return ErrorResult(err.Error())
 			}
+			defer closer.Close()
 
-			// Extract optional parameters
-			branch := GetString(args, "branch", "")
-
-			// Validate base64 content
-			_, err := base64.StdEncoding.DecodeString(fileContent)
-			if err != nil {
-				return ErrorResult(fmt.Sprintf("Invalid base64 file content: %v", err))
+			fields := map[string]string{}
+			if branch := GetString(args, "branch", ""); branch != "" {
+				fields["branch"] = branch
 			}
 
 			// Build the endpoint with URL-encoded project_id
 			encodedProjectID := url.PathEscape(projectID)
 			endpoint := fmt.Sprintf("/projects/%s/wikis/attachments", encodedProjectID)
 
-			// Prepare request body
-			requestBody := map[string]interface{}{
-				"file": map[string]interface{}{
-					"content":  fileContent,
-					"filename": filename,
-				},
-			}
-
-			if branch != "" {
-				requestBody["branch"] = branch
-			}
-
 			// Make API request
 			var response WikiAttachmentResponse
-			if err := ctx.Client.Post(endpoint, requestBody, &response); err != nil {
-				return ErrorResult(fmt.Sprintf("Failed to upload wiki attachment: %v", err))
+			if err := c.Client.PostMultipart(ctx, endpoint, fields, file, &response); err != nil {
+				return APIErrorResult("Failed to upload wiki attachment", err)
 			}
 
 			// Build response
@@ -526,4 +506,6 @@ func initWikiTools(server *mcp.Server) {
 	registerUpdateWikiPage(server)
 	registerDeleteWikiPage(server)
 	registerUploadWikiAttachment(server)
+	initGroupWikiTools(server)
+	initWikiVersionTools(server)
 }
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+)
+
+// IssueTransition is the response of the transition_issue tool.
+type IssueTransition struct {
+	IssueIID int    `json:"issue_iid"`
+	Scope    string `json:"scope"`
+	// From lists the labels of the scope that were removed, usually one
+	From    []string `json:"from"`
+	To      string   `json:"to"`
+	Changed bool     `json:"changed"`
+	Labels  []string `json:"labels"`
+	WebURL  string   `json:"web_url"`
+}
+
+// labelScope returns the scope of a scoped label, e.g. "workflow" for
+// "workflow::review" and "team::api" for "team::api::owner". It returns ""
+// for unscoped labels.
+func labelScope(name string) string {
+	i := strings.LastIndex(name, "::")
+	if i <= 0 {
+		return ""
+	}
+	return name[:i]
+}
+
+type transitionIssueArgs struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	IssueIID  int    `json:"issue_iid" validate:"required"`
+	To        string `json:"to" validate:"required"`
+	From      string `json:"from"`
+}
+
+// registerTransitionIssue registers the transition_issue tool.
+func registerTransitionIssue(server *mcp.Server) {
+	server.RegisterTool(
+		mcp.Tool{
+			Name:        "transition_issue",
+			Description: "Move an issue to another state of a scoped-label workflow (e.g., workflow::in-progress -> workflow::review). The target label must exist in the project (or its groups); every other label of the same scope is removed and the target added in a single update. Set from to only transition an issue that currently has that label.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "The project identifier - either a numeric ID (e.g., 42) or URL-encoded path (e.g., my-group/my-project)",
+					},
+					"issue_iid": {
+						Type:        "integer",
+						Description: "The internal ID of the issue within the project",
+					},
+					"to": {
+						Type:        "string",
+						Description: "The scoped label to move to (e.g., workflow::review)",
+					},
+					"from": {
+						Type:        "string",
+						Description: "The scoped label the issue is expected to have (e.g., workflow::in-progress); the transition fails if it does not",
+					},
+				},
+				Required: []string{"project_id", "issue_iid", "to"},
+			},
+		},
+		withArgs("transition_issue", func(ctx context.Context, c *ToolContext, args transitionIssueArgs) (*mcp.CallToolResult, error) {
+			scope := labelScope(args.To)
+			if scope == "" {
+				return ErrorResult(fmt.Sprintf("%q is not a scoped label (e.g., workflow::review)", args.To))
+			}
+			if args.From != "" && labelScope(args.From) != scope {
+				return ErrorResult(fmt.Sprintf("from %q is not a label of scope %s", args.From, scope))
+			}
+
+			project := url.PathEscape(args.ProjectID)
+			params := url.Values{}
+			params.Set("include_ancestor_groups", "true")
+			params.Set("search", scope+"::")
+			var states []string
+			if _, err := streamPages(ctx, c.Client, fmt.Sprintf("/projects/%s/labels?%s", project, params.Encode()), maxCollectedItems, func(label Label) {
+				if labelScope(label.Name) == scope {
+					states = append(states, label.Name)
+				}
+			}); err != nil {
+				return APIErrorResult("Failed to list labels", err)
+			}
+			sort.Strings(states)
+			if !containsString(states, args.To) {
+				if len(states) == 0 {
+					return ErrorResult(fmt.Sprintf("the project has no labels of scope %s", scope))
+				}
+				return ErrorResult(fmt.Sprintf("label %s does not exist; labels of scope %s: %s", args.To, scope, strings.Join(states, ", ")))
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/issues/%d", project, args.IssueIID)
+			var issue gitlab.Issue
+			if err := c.Client.Get(ctx, endpoint, &issue); err != nil {
+				return APIErrorResult("Failed to get issue", err)
+			}
+
+			transition := IssueTransition{IssueIID: issue.IID, Scope: scope, From: []string{}, To: args.To, WebURL: issue.WebURL}
+			current := []string{}
+			for _, label := range issue.Labels {
+				if labelScope(label) == scope {
+					current = append(current, label)
+				}
+			}
+			if args.From != "" && !containsString(current, args.From) {
+				if len(current) == 0 {
+					return ErrorResult(fmt.Sprintf("issue #%d has no %s label, not %s", issue.IID, scope, args.From))
+				}
+				return ErrorResult(fmt.Sprintf("issue #%d is in %s, not %s", issue.IID, strings.Join(current, ", "), args.From))
+			}
+			for _, label := range current {
+				if label != args.To {
+					transition.From = append(transition.From, label)
+				}
+			}
+			if len(transition.From) == 0 && containsString(current, args.To) {
+				transition.Labels = issue.Labels
+				return JSONResult(transition)
+			}
+
+			// One update adds and removes, so the issue is never without or
+			// with two labels of the scope
+			body := map[string]interface{}{"add_labels": args.To}
+			if len(transition.From) > 0 {
+				body["remove_labels"] = strings.Join(transition.From, ",")
+			}
+			var updated gitlab.Issue
+			if err := c.Client.Put(ctx, endpoint, body, &updated); err != nil {
+				return APIErrorResult("Failed to update issue labels", err)
+			}
+			transition.Changed = true
+			transition.Labels = updated.Labels
+			return JSONResult(transition)
+		}),
+	)
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTransitionIssue(t *testing.T) {
+	tc, client := newTestContext(t)
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/labels", http.StatusOK, []map[string]interface{}{
+		{"name": "workflow::in-progress"},
+		{"name": "workflow::review"},
+		{"name": "workflow::done"},
+		{"name": "workflow-legacy"},
+	})
+	client.Handle(http.MethodGet, "/projects/acme%2Fapi/issues/7", http.StatusOK, map[string]interface{}{
+		"iid": 7, "labels": []string{"bug", "workflow::in-progress"},
+	})
+	client.Handle(http.MethodPut, "/projects/acme%2Fapi/issues/7", http.StatusOK, map[string]interface{}{
+		"iid": 7, "labels": []string{"bug", "workflow::review"},
+	})
+
+	result := callTool(t, tc, "transition_issue", map[string]interface{}{
+		"project_id": "acme/api",
+		"issue_iid":  7,
+		"from":       "workflow::in-progress",
+		"to":         "workflow::review",
+	})
+	if result.IsError {
+		t.Fatalf("transition_issue failed: %s", resultText(t, result))
+	}
+	var got IssueTransition
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if !got.Changed || got.Scope != "workflow" || len(got.From) != 1 || got.From[0] != "workflow::in-progress" {
+		t.Errorf("transition = %+v", got)
+	}
+	var puts int
+	for _, req := range client.Requests() {
+		if req.Method != http.MethodPut {
+			continue
+		}
+		puts++
+		var body map[string]interface{}
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			t.Fatalf("PUT body: %v", err)
+		}
+		if body["add_labels"] != "workflow::review" || body["remove_labels"] != "workflow::in-progress" || body["labels"] != nil {
+			t.Errorf("PUT body = %v", body)
+		}
+	}
+	if puts != 1 {
+		t.Errorf("got %d label updates, want 1", puts)
+	}
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"unscoped", map[string]interface{}{"to": "review"}, "not a scoped label"},
+		{"unknown label", map[string]interface{}{"to": "workflow::qa"}, "workflow::done, workflow::in-progress, workflow::review"},
+		{"wrong from", map[string]interface{}{"to": "workflow::done", "from": "workflow::review"}, "is in workflow::in-progress"},
+		{"other scope", map[string]interface{}{"to": "workflow::done", "from": "team::api"}, "not a label of scope workflow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["project_id"] = "acme/api"
+			tt.args["issue_iid"] = 7
+			result := callTool(t, tc, "transition_issue", tt.args)
+			if text := resultText(t, result); !result.IsError || !strings.Contains(text, tt.want) {
+				t.Errorf("result = %q, want an error containing %q", text, tt.want)
+			}
+		})
+	}
+}
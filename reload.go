@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/tools"
+)
+
+// watchReload reloads the configuration whenever the process receives SIGHUP.
+// current is the tool context the server was started with.
+func watchReload(logger *logging.Logger, client *gitlab.Client, server *mcp.Server, current *tools.ToolContext) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			logger.Info("SIGHUP received, reloading configuration")
+			next, err := reloadConfig(logger, client, server, current)
+			if err != nil {
+				logger.Error("Configuration reload failed, keeping current configuration: %v", err)
+				continue
+			}
+			current = next
+		}
+	}()
+}
+
+// reloadConfig re-reads ~/.mcp_env, the environment, the config file and token
+// sources, then applies the token, log level, feature flags, project and tool
+// allowlists, tool timeouts, slow call threshold, custom extractors, redaction and policies without dropping the client
+// connection. Tools are re-registered, which notifies the client via
+// notifications/tools/list_changed if the tool set changed.
+// Settings bound at startup (API URL, HTTP listener, log directory, audit log,
+// sudo support, rate limit, response size limit, request compression, usage log interval)
+// require a restart. It returns the tool context now in use.
+func reloadConfig(logger *logging.Logger, client *gitlab.Client, server *mcp.Server, current *tools.ToolContext) (*tools.ToolContext, error) {
+	if _, err := logging.ReloadEnvFile(); err != nil {
+		return nil, err
+	}
+	cfg, err := config.Reload()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if current == nil || current.Config == nil {
+		return nil, fmt.Errorf("tool context not initialized")
+	}
+	toolContext, err := current.Reconfigure(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.GitLabAPIURL != current.Config.GitLabAPIURL {
+		logger.Warn("GITLAB_API_URL changed to %s; restart the server to apply it", cfg.GitLabAPIURL)
+		cfg.GitLabAPIURL = current.Config.GitLabAPIURL
+	}
+	if cfg.AuditLog != current.Config.AuditLog {
+		logger.Warn("MCP_AUDIT_LOG changed to %q; restart the server to apply it", cfg.AuditLog)
+		cfg.AuditLog = current.Config.AuditLog
+	}
+	if cfg.AllowSudo != current.Config.AllowSudo {
+		logger.Warn("GITLAB_ALLOW_SUDO changed to %t; restart the server to apply it", cfg.AllowSudo)
+		cfg.AllowSudo = current.Config.AllowSudo
+	}
+
+	logger.SetLevel(logging.ParseLogLevel(cfg.LogLevel))
+	client.SetToken(cfg.GitLabToken)
+	tools.Bind(server, toolContext)
+	server.SetToolFilter(cfg.IsToolEnabled)
+	server.SetToolTimeouts(cfg.ToolTimeout, cfg.ToolTimeouts)
+	server.SetRedactor(resultRedactor(cfg))
+	server.ToolStats().SetSlowThreshold(cfg.SlowToolCall)
+	var toolGroups []tools.ToolGroup
+	server.ReplaceTools(func(staging *mcp.Server) {
+		// Without group names RegisterGroups cannot fail
+		toolGroups, _ = tools.RegisterGroups(staging, toolContext)
+	})
+	server.SetInstructions(buildInstructions(cfg, toolGroups))
+
+	logger.Info("Configuration reloaded: token=%s (%s) log_level=%s features=%v tools=%d",
+		logging.MaskToken(cfg.GitLabToken), cfg.TokenSource, cfg.LogLevel, cfg.GetEnabledFeatures(), len(server.ToolNames()))
+	return toolContext, nil
+}
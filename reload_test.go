@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/gitlabtest"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/mcp"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/tools"
+)
+
+// parseFlags lets config.Reload run: it reuses the flags of the first load,
+// and flags can only be defined once per process.
+var parseFlags sync.Once
+
+// reloadable is a stdio server as main runs it, whose configuration comes
+// from the environment and the config file at configPath.
+type reloadable struct {
+	t          *testing.T
+	gitlab     *gitlabtest.Server
+	logger     *logging.Logger
+	client     *gitlab.Client
+	server     *mcp.Server
+	current    *tools.ToolContext
+	configPath string
+	stdin      io.WriteCloser
+	messages   chan string
+	nextID     int
+}
+
+// newReloadable starts a server for the given config file content with an
+// isolated environment. token is the token the server starts with.
+func newReloadable(t *testing.T, token, content string) *reloadable {
+	t.Helper()
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, prefix := range []string{"GITLAB_", "GL_", "MCP_", "USE_"} {
+			if strings.HasPrefix(name, prefix) {
+				t.Setenv(name, "")
+			}
+		}
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("NETRC", filepath.Join(home, "netrc"))
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	parseFlags.Do(func() { config.LoadConfig() })
+
+	r := &reloadable{t: t, gitlab: gitlabtest.NewServer(t), configPath: filepath.Join(home, "config.yaml")}
+	r.gitlab.MustLoadFixtures(t, filepath.Join("pkg", "tools", "testdata", "fixtures"))
+	t.Setenv("GITLAB_API_URL", r.gitlab.URL())
+	t.Setenv("GITLAB_PERSONAL_ACCESS_TOKEN", token)
+	t.Setenv("MCP_CONFIG_FILE", r.configPath)
+	r.writeConfig(content)
+
+	cfg, err := config.Reload()
+	if err != nil {
+		t.Fatalf("load configuration: %v", err)
+	}
+	r.logger, err = logging.NewLogger(logging.Config{LogDir: t.TempDir(), Level: logging.LevelError})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	t.Cleanup(func() { r.logger.Close() })
+	r.client = gitlab.NewClient(cfg.GitLabAPIURL, cfg.GitLabToken)
+	r.server, r.current, err = newServer(cfg, r.client, r.logger, nil)
+	if err != nil {
+		t.Fatalf("newServer: %v", err)
+	}
+
+	stdin, stdinWriter := io.Pipe()
+	stdoutReader, stdout := io.Pipe()
+	r.stdin = stdinWriter
+	r.messages = make(chan string, 16)
+	go func() {
+		scanner := bufio.NewScanner(stdoutReader)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			r.messages <- scanner.Text()
+		}
+	}()
+	done := make(chan struct{})
+	go func() {
+		r.server.RunIO(stdin, stdout)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		stdinWriter.Close()
+		<-done
+		stdout.Close()
+	})
+
+	// Run enables notifications before it reads the first message
+	r.call("ping", nil)
+	return r
+}
+
+// writeConfig replaces the config file.
+func (r *reloadable) writeConfig(content string) {
+	r.t.Helper()
+	if err := os.WriteFile(r.configPath, []byte(content), 0600); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// reload runs reloadConfig and keeps the new tool context, like watchReload.
+func (r *reloadable) reload() error {
+	r.t.Helper()
+	next, err := reloadConfig(r.logger, r.client, r.server, r.current)
+	if err != nil {
+		if next != nil {
+			r.t.Errorf("reloadConfig returned a tool context with error %v", err)
+		}
+		return err
+	}
+	r.current = next
+	return nil
+}
+
+// next returns the next message the server wrote.
+func (r *reloadable) next() string {
+	r.t.Helper()
+	select {
+	case message := <-r.messages:
+		return message
+	case <-time.After(5 * time.Second):
+		r.t.Fatal("no message from the server")
+		return ""
+	}
+}
+
+// call sends a request and returns its response, failing on notifications
+// written before it.
+func (r *reloadable) call(method string, params interface{}) mcp.JSONRPCResponse {
+	r.t.Helper()
+	r.nextID++
+	data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": r.nextID, "method": method, "params": params})
+	if _, err := r.stdin.Write(append(data, '\n')); err != nil {
+		r.t.Fatalf("%s: %v", method, err)
+	}
+	message := r.next()
+	var response mcp.JSONRPCResponse
+	if err := json.Unmarshal([]byte(message), &response); err != nil || response.ID == nil {
+		r.t.Fatalf("%s: got %s, want its response", method, message)
+	}
+	return response
+}
+
+// callTool calls a tool and returns its result.
+func (r *reloadable) callTool(name string, args map[string]interface{}) mcp.CallToolResult {
+	r.t.Helper()
+	response := r.call("tools/call", map[string]interface{}{"name": name, "arguments": args})
+	if response.Error != nil {
+		r.t.Fatalf("%s: JSON-RPC error %d: %s", name, response.Error.Code, response.Error.Message)
+	}
+	data, _ := json.Marshal(response.Result)
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		r.t.Fatalf("%s: decode result: %v", name, err)
+	}
+	return result
+}
+
+// hasTool reports whether the server lists the named tool.
+func (r *reloadable) hasTool(name string) bool {
+	for _, tool := range r.server.ToolNames() {
+		if tool == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReloadConfigRotatesToken(t *testing.T) {
+	r := newReloadable(t, "glpat-revoked", "")
+	project := map[string]interface{}{"project_id": "acme/payments-api"}
+	if result := r.callTool("get_project", project); !result.IsError || !strings.Contains(result.Content[0].Text, "401") {
+		t.Fatalf("get_project with the revoked token = %+v, want a 401", result)
+	}
+
+	t.Setenv("GITLAB_PERSONAL_ACCESS_TOKEN", r.gitlab.Token())
+	if err := r.reload(); err != nil {
+		t.Fatalf("reloadConfig: %v", err)
+	}
+	if result := r.callTool("get_project", project); result.IsError {
+		t.Errorf("get_project after the reload = %+v, want the new token used", result)
+	}
+	if r.current.Config.GitLabToken != r.gitlab.Token() {
+		t.Error("the new tool context does not carry the new token")
+	}
+}
+
+func TestReloadConfigReregistersTools(t *testing.T) {
+	r := newReloadable(t, gitlabtest.DefaultToken, "features:\n  pipeline: false\n")
+	if r.hasTool("list_pipelines") {
+		t.Fatal("pipeline tools are registered with features.pipeline off")
+	}
+
+	r.writeConfig("features:\n  pipeline: true\n")
+	if err := r.reload(); err != nil {
+		t.Fatalf("reloadConfig: %v", err)
+	}
+	if !r.hasTool("list_pipelines") || !r.current.Config.UsePipeline {
+		t.Fatal("pipeline tools were not registered by the reload")
+	}
+	if message := r.next(); !strings.Contains(message, `"method":"notifications/tools/list_changed"`) {
+		t.Errorf("message after the reload = %s, want notifications/tools/list_changed", message)
+	}
+	// The re-registered tools run with the new tool context
+	result := r.callTool("list_pipelines", map[string]interface{}{"project_id": "acme/payments-api"})
+	if strings.Contains(result.Content[0].Text, "not enabled") {
+		t.Errorf("list_pipelines after the reload = %s", result.Content[0].Text)
+	}
+
+	// Reloading without changes leaves the tool list alone
+	if err := r.reload(); err != nil {
+		t.Fatalf("reloadConfig: %v", err)
+	}
+	if response := r.call("ping", nil); response.Error != nil {
+		t.Errorf("ping: %+v", response.Error)
+	}
+}
+
+func TestReloadConfigInvalidFileKeepsContext(t *testing.T) {
+	r := newReloadable(t, gitlabtest.DefaultToken, "features:\n  pipeline: true\n")
+	previous := r.current
+
+	r.writeConfig("features:\n  pipeline: false\nunknown_setting: true\n")
+	t.Setenv("GITLAB_PERSONAL_ACCESS_TOKEN", "glpat-revoked")
+	if err := r.reload(); err == nil {
+		t.Fatal("reloadConfig accepted an invalid config file")
+	}
+	if r.current != previous || !r.hasTool("list_pipelines") {
+		t.Error("a failed reload replaced the tool context or the tools")
+	}
+	// Neither the token nor the tool list changed, so calls keep working
+	// and no list_changed notification was sent
+	if result := r.callTool("get_project", map[string]interface{}{"project_id": "acme/payments-api"}); result.IsError {
+		t.Errorf("get_project after a failed reload = %+v", result)
+	}
+}
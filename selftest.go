@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/demo"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/logging"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/tools"
+)
+
+// Exit codes for -check/-selftest. When several checks fail, the first
+// failure in this order determines the code.
+const (
+	exitCheckOK           = 0
+	exitCheckConfig       = 2 // configuration is invalid
+	exitCheckGitLab       = 3 // GitLab unreachable or token rejected
+	exitCheckLogDir       = 4 // log directory not writable
+	selfTestGitLabTimeout = 15 * time.Second
+)
+
+// runSelfTest validates the configuration, verifies the token against GitLab
+// and checks that the log directory is writable, printing a summary to out.
+// It does not start the server and returns the process exit code.
+func runSelfTest(cfg *config.Config, out io.Writer) int {
+	code := exitCheckOK
+	fail := func(c int) {
+		if code == exitCheckOK {
+			code = c
+		}
+	}
+	report := func(ok bool, name, format string, args ...interface{}) {
+		status := "PASS"
+		if !ok {
+			status = "FAIL"
+		}
+		fmt.Fprintf(out, "[%s] %-13s %s\n", status, name, fmt.Sprintf(format, args...))
+	}
+
+	fmt.Fprintf(out, "go-mcp-gitlab %s self-test\n\n", Version)
+
+	// Configuration
+	if err := cfg.Validate(); err != nil {
+		report(false, "configuration", "%v", err)
+		fail(exitCheckConfig)
+	} else {
+		report(true, "configuration", "api_url=%s token=%s (%s)", cfg.GitLabAPIURL, logging.MaskToken(cfg.GitLabToken), cfg.TokenSource)
+	}
+	if features := cfg.GetEnabledFeatures(); len(features) > 0 {
+		fmt.Fprintf(out, "       features      %v\n", features)
+	}
+
+	// GitLab token and connectivity
+	if (cfg.GitLabToken != "" || cfg.Demo) && cfg.GitLabAPIURL != "" {
+		var opts []gitlab.ClientOption
+		if cfg.Demo {
+			if transport, err := demo.NewTransport(cfg.GitLabAPIURL); err == nil {
+				opts = append(opts, gitlab.WithHTTPClient(&http.Client{Transport: transport}))
+			}
+		}
+		client := gitlab.NewClient(cfg.GitLabAPIURL, cfg.GitLabToken, opts...)
+		ctx, cancel := context.WithTimeout(context.Background(), selfTestGitLabTimeout)
+		result := tools.CheckConnectivity(ctx, client)
+		cancel()
+
+		switch {
+		case result.TokenValid:
+			report(true, "gitlab", "authenticated as %s (GitLab %s, %dms)", result.User.Username, result.GitLabVersion, result.LatencyMS)
+		case result.Reachable:
+			report(false, "gitlab", "%s reachable but token rejected: %s", result.BaseURL, result.Checks[0].Error)
+			fail(exitCheckGitLab)
+		default:
+			report(false, "gitlab", "%s unreachable: %s", result.BaseURL, result.Checks[0].Error)
+			fail(exitCheckGitLab)
+		}
+	} else {
+		report(false, "gitlab", "skipped: no token or API URL configured")
+		fail(exitCheckGitLab)
+	}
+
+	// Log directory
+	logDir := logging.ResolveLogDir(logging.Config{
+		LogDir:          cfg.LogDir,
+		AppName:         AppName,
+		AddAppSubfolder: cfg.AddAppSubfolder,
+	})
+	if err := checkWritableDir(logDir); err != nil {
+		report(false, "log_dir", "%s: %v", logDir, err)
+		fail(exitCheckLogDir)
+	} else {
+		report(true, "log_dir", "%s (level %s)", logDir, cfg.LogLevel)
+	}
+
+	fmt.Fprintln(out)
+	if code == exitCheckOK {
+		fmt.Fprintln(out, "All checks passed")
+	} else {
+		fmt.Fprintf(out, "Self-test failed (exit code %d)\n", code)
+	}
+	return code
+}
+
+// checkWritableDir creates dir if needed and verifies a file can be written to it.
+func checkWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	probe, err := os.CreateTemp(dir, ".selftest-*")
+	if err != nil {
+		return err
+	}
+	name := probe.Name()
+	probe.Close()
+	return os.Remove(name)
+}
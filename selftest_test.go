@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/config"
+	"github.com/go-mcp-gitlab/go-mcp-gitlab/pkg/gitlab/gitlabtest"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	gl := gitlabtest.NewServer(t)
+	gl.Handle(http.MethodGet, "/user", http.StatusOK, `{"id": 1, "username": "alice"}`)
+	gl.Handle(http.MethodGet, "/version", http.StatusOK, `{"version": "17.0.0"}`)
+	// A file where the log directory should be cannot become one
+	notADir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notADir, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.Config
+		wantCode int
+		want     []string
+	}{
+		{
+			name:     "all checks pass",
+			cfg:      config.Config{GitLabToken: gl.Token()},
+			wantCode: exitCheckOK,
+			want:     []string{"[PASS] configuration", "[PASS] gitlab        authenticated as alice (GitLab 17.0.0", "[PASS] log_dir", "All checks passed"},
+		},
+		{
+			name:     "token rejected",
+			cfg:      config.Config{GitLabToken: "glpat-revoked"},
+			wantCode: exitCheckGitLab,
+			want:     []string{"[PASS] configuration", "[FAIL] gitlab        " + gl.URL() + " reachable but token rejected", "[PASS] log_dir", "Self-test failed (exit code 3)"},
+		},
+		{
+			name:     "GitLab unreachable",
+			cfg:      config.Config{GitLabToken: gl.Token(), GitLabAPIURL: "http://127.0.0.1:1/api/v4"},
+			wantCode: exitCheckGitLab,
+			want:     []string{"[FAIL] gitlab        http://127.0.0.1:1/api/v4 unreachable", "Self-test failed (exit code 3)"},
+		},
+		{
+			name:     "log directory not writable",
+			cfg:      config.Config{GitLabToken: gl.Token(), LogDir: notADir},
+			wantCode: exitCheckLogDir,
+			want:     []string{"[PASS] gitlab", "[FAIL] log_dir       " + notADir, "Self-test failed (exit code 4)"},
+		},
+		{
+			// The configuration failure comes first and decides the code
+			name:     "no token",
+			cfg:      config.Config{LogDir: notADir},
+			wantCode: exitCheckConfig,
+			want:     []string{"[FAIL] configuration configuration validation failed", "GitLab token not found", "[FAIL] gitlab        skipped", "[FAIL] log_dir", "Self-test failed (exit code 2)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if cfg.GitLabAPIURL == "" {
+				cfg.GitLabAPIURL = gl.URL()
+			}
+			if cfg.LogDir == "" {
+				cfg.LogDir = t.TempDir()
+			}
+			var out bytes.Buffer
+			if code := runSelfTest(&cfg, &out); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("report lacks %q:\n%s", want, out.String())
+				}
+			}
+			if strings.Contains(out.String(), gl.Token()) {
+				t.Errorf("report shows the token:\n%s", out.String())
+			}
+		})
+	}
+}